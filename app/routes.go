@@ -12,10 +12,15 @@ import (
 	masterservicev1 "gin-starter/modules/master/v1/service"
 	notificationhandlerv1 "gin-starter/modules/notification/v1/handler"
 	notificationservicev1 "gin-starter/modules/notification/v1/service"
+	privacyhandlerv1 "gin-starter/modules/privacy/v1/handler"
+	privacyservicev1 "gin-starter/modules/privacy/v1/service"
+	scimhandlerv1 "gin-starter/modules/scim/v1/handler"
+	scimservicev1 "gin-starter/modules/scim/v1/service"
 	userhandlerv1 "gin-starter/modules/user/v1/handler"
 	userservicev1 "gin-starter/modules/user/v1/service"
 	"gin-starter/response"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -42,11 +47,16 @@ func AuthHTTPHandler(cfg config.Config, router *gin.Engine, auc authservicev1.Au
 		v1.POST("/user/login", hnd.Login)
 		v1.POST("/cms/login", hnd.LoginCMS)
 	}
+
+	v1.Use(middleware.Auth(cfg))
+	{
+		v1.POST("/user/logout", hnd.Logout)
+	}
 }
 
 // NotificationFinderHTTPHandler is a handler for notification APIs
-func NotificationFinderHTTPHandler(cfg config.Config, router *gin.Engine, cf notificationservicev1.NotificationFinderUseCase, nu notificationservicev1.NotificationUpdaterUseCase) {
-	hnd := notificationhandlerv1.NewNotificationFinderHandler(cf, nu)
+func NotificationFinderHTTPHandler(cfg config.Config, router *gin.Engine, cf notificationservicev1.NotificationFinderUseCase) {
+	hnd := notificationhandlerv1.NewNotificationFinderHandler(cf)
 	v1 := router.Group("/v1")
 
 	v1.Use(middleware.Auth(cfg))
@@ -57,11 +67,31 @@ func NotificationFinderHTTPHandler(cfg config.Config, router *gin.Engine, cf not
 }
 
 // NotificationCreatorHTTPHandler is a handler for notification APIs
-func NotificationCreatorHTTPHandler(cfg config.Config, router *gin.Engine, cf notificationservicev1.NotificationCreatorUseCase) {
+func NotificationCreatorHTTPHandler(cfg config.Config, router *gin.Engine, pc middleware.PermissionChecker, cf notificationservicev1.NotificationCreatorUseCase) {
 	hnd := notificationhandlerv1.NewNotificationCreatorHandler(cf)
 	v1 := router.Group("/v1")
+
+	v1.Use(middleware.Auth(cfg))
+	v1.Use(middleware.Admin(cfg))
+	{
+		v1.POST("/cms/notification", middleware.Permission(pc, "notification.create"), hnd.CreateNotification)
+	}
+}
+
+// NotificationSchedulerHTTPHandler is a handler for the notifications scheduled in the CMS
+func NotificationSchedulerHTTPHandler(cfg config.Config, router *gin.Engine, pc middleware.PermissionChecker, ns notificationservicev1.NotificationSchedulerUseCase) {
+	hnd := notificationhandlerv1.NewNotificationSchedulerHandler(ns)
+	v1 := router.Group("/v1")
+
+	v1.Use(middleware.Auth(cfg))
+	v1.Use(middleware.Admin(cfg))
 	{
-		v1.POST("/cms/notification", hnd.CreateNotification)
+		v1.GET("/cms/notification/schedules", middleware.Permission(pc, "notification.schedule.view"), hnd.GetScheduledNotifications)
+		v1.GET("/cms/notification/schedules/:id", middleware.Permission(pc, "notification.schedule.view"), hnd.GetScheduledNotificationByID)
+		v1.GET("/cms/notification/schedules/:id/runs", middleware.Permission(pc, "notification.schedule.view"), hnd.GetScheduledNotificationRuns)
+		v1.POST("/cms/notification/schedules", middleware.Permission(pc, "notification.schedule.manage"), hnd.ScheduleNotification)
+		v1.PUT("/cms/notification/schedules/:id/reschedule", middleware.Permission(pc, "notification.schedule.manage"), hnd.RescheduleNotification)
+		v1.POST("/cms/notification/schedules/:id/cancel", middleware.Permission(pc, "notification.schedule.manage"), hnd.CancelScheduledNotification)
 	}
 }
 
@@ -72,8 +102,60 @@ func NotificationUpdaterHTTPHandler(cfg config.Config, router *gin.Engine, cf no
 
 	v1.Use(middleware.Auth(cfg))
 	{
-		v1.PUT("/user/notification/set", hnd.RegisterUnregisterPlayerID)
+		// replaced by the device registry of /user/devices
+		v1.PUT("/user/notification/set", DeprecatedAPI)
 		v1.PUT("/user/notification/read", hnd.UpdateReadNotification)
+		v1.PUT("/user/notifications/read", hnd.MarkRead)
+		v1.PUT("/user/notifications/read-all", hnd.UpdateReadNotification)
+		v1.PUT("/user/notifications/archive", hnd.Archive)
+		v1.DELETE("/user/notifications", hnd.Delete)
+	}
+}
+
+// UserDeviceManagerHTTPHandler is a handler for the devices push notifications are delivered to
+func UserDeviceManagerHTTPHandler(cfg config.Config, router *gin.Engine, pc middleware.PermissionChecker, dm notificationservicev1.UserDeviceManagerUseCase) {
+	hnd := notificationhandlerv1.NewUserDeviceManagerHandler(dm)
+	v1 := router.Group("/v1")
+
+	v1.Use(middleware.Auth(cfg))
+	{
+		v1.POST("/user/devices", hnd.RegisterDevice)
+		v1.DELETE("/user/devices", hnd.UnregisterDevice)
+	}
+
+	v1.Use(middleware.Admin(cfg))
+	{
+		v1.GET("/cms/user/devices/:id", middleware.Permission(pc, "notification.device.view"), hnd.GetUserDevices)
+	}
+}
+
+// NotificationTemplateManagerHTTPHandler is a handler for the notification templates managed in the CMS
+func NotificationTemplateManagerHTTPHandler(cfg config.Config, router *gin.Engine, pc middleware.PermissionChecker, tm notificationservicev1.NotificationTemplateManagerUseCase) {
+	hnd := notificationhandlerv1.NewNotificationTemplateManagerHandler(tm)
+	v1 := router.Group("/v1")
+
+	v1.Use(middleware.Auth(cfg))
+	v1.Use(middleware.Admin(cfg))
+	{
+		v1.GET("/cms/notification/templates", middleware.Permission(pc, "notification.template.view"), hnd.GetTemplates)
+		v1.GET("/cms/notification/templates/:id", middleware.Permission(pc, "notification.template.view"), hnd.GetTemplateByID)
+		v1.POST("/cms/notification/templates", middleware.Permission(pc, "notification.template.manage"), hnd.CreateTemplate)
+		v1.PUT("/cms/notification/templates/:id", middleware.Permission(pc, "notification.template.manage"), hnd.UpdateTemplate)
+		v1.DELETE("/cms/notification/templates/:id", middleware.Permission(pc, "notification.template.manage"), hnd.DeleteTemplate)
+		v1.POST("/cms/notification/templates/:id/publish", middleware.Permission(pc, "notification.template.manage"), hnd.PublishTemplate)
+		v1.POST("/cms/notification/templates/:id/unpublish", middleware.Permission(pc, "notification.template.manage"), hnd.UnpublishTemplate)
+		v1.POST("/cms/notification/templates/:id/preview", middleware.Permission(pc, "notification.template.view"), hnd.PreviewTemplate)
+	}
+}
+
+// NotificationStreamHTTPHandler is a handler for the notifications streamed to the signed in users
+func NotificationStreamHTTPHandler(cfg config.Config, router *gin.Engine, ns notificationservicev1.NotificationStreamerUseCase, heartbeat time.Duration) {
+	hnd := notificationhandlerv1.NewNotificationStreamHandler(ns, heartbeat)
+	v1 := router.Group("/v1")
+
+	v1.Use(middleware.Auth(cfg))
+	{
+		v1.GET("/user/notifications/stream", hnd.Stream)
 	}
 }
 
@@ -99,7 +181,7 @@ func MasterCreatorHTTPHandler(cfg config.Config, router *gin.Engine, mc masterse
 }
 
 // UserFinderHTTPHandler is a handler for user APIs
-func UserFinderHTTPHandler(cfg config.Config, router *gin.Engine, cf userservicev1.UserFinderUseCase) {
+func UserFinderHTTPHandler(cfg config.Config, router *gin.Engine, pc middleware.PermissionChecker, cf userservicev1.UserFinderUseCase) {
 	hnd := userhandlerv1.NewUserFinderHandler(cf)
 	v1 := router.Group("/v1")
 	{
@@ -114,34 +196,51 @@ func UserFinderHTTPHandler(cfg config.Config, router *gin.Engine, cf userservice
 	v1.Use(middleware.Admin(cfg))
 	{
 		v1.GET("/cms/profile", hnd.GetAdminProfile)
-		v1.GET("/cms/admin/list", hnd.GetAdminUsers)
-		v1.GET("/cms/admin/detail/:id", hnd.GetAdminUserByID)
-		v1.GET("/cms/user/list", hnd.GetUsers)
-		v1.GET("/cms/user/detail/:id", hnd.GetUserByID)
-		v1.GET("/cms/roles", hnd.GetRoles)
-		v1.GET("/cms/permission", hnd.GetPermissions)
+		v1.GET("/cms/admin/list", middleware.Permission(pc, "admin.view"), hnd.GetAdminUsers)
+		v1.GET("/cms/admin/detail/:id", middleware.Permission(pc, "admin.view"), hnd.GetAdminUserByID)
+		v1.GET("/cms/user/list", middleware.Permission(pc, "user.view"), hnd.GetUsers)
+		v1.GET("/cms/user/detail/:id", middleware.Permission(pc, "user.view"), hnd.GetUserByID)
+		v1.GET("/cms/roles", middleware.Permission(pc, "role.view"), hnd.GetRoles)
+		v1.GET("/cms/permission", middleware.Permission(pc, "permission.view"), hnd.GetPermissions)
 		v1.GET("/cms/user/permission", hnd.GetUserPermissions)
 	}
 }
 
 // UserCreatorHTTPHandler is a handler for user APIs
-func UserCreatorHTTPHandler(cfg config.Config, router *gin.Engine, uc userservicev1.UserCreatorUseCase, uf userservicev1.UserFinderUseCase, cloudStorage interfaces.CloudStorageUseCase) {
-	hnd := userhandlerv1.NewUserCreatorHandler(uc, cloudStorage)
+func UserCreatorHTTPHandler(cfg config.Config, router *gin.Engine, pc middleware.PermissionChecker, uc userservicev1.UserCreatorUseCase, uf userservicev1.UserFinderUseCase, photoStorage interfaces.PhotoStorageUseCase) {
+	hnd := userhandlerv1.NewUserCreatorHandler(uc, photoStorage)
 	v1 := router.Group("/v1")
 
 	v1.Use(middleware.Auth(cfg))
 	v1.Use(middleware.Admin(cfg))
 	{
-		v1.POST("/cms/user", hnd.CreateUser)
-		v1.POST("/cms/admin/user", hnd.CreateAdmin)
-		v1.POST("/cms/permission", hnd.CreatePermission)
-		v1.POST("/cms/role", hnd.CreateRole)
+		v1.POST("/cms/user", middleware.Permission(pc, "user.create"), hnd.CreateUser)
+		v1.POST("/cms/permission", middleware.Permission(pc, "permission.create"), hnd.CreatePermission)
+		v1.POST("/cms/role", middleware.Permission(pc, "role.create"), hnd.CreateRole)
+	}
+}
+
+// AdminInviterHTTPHandler is a handler for admin invitation APIs
+func AdminInviterHTTPHandler(cfg config.Config, router *gin.Engine, pc middleware.PermissionChecker, ai userservicev1.AdminInviterUseCase) {
+	hnd := userhandlerv1.NewAdminInviterHandler(ai)
+	v1 := router.Group("/v1")
+	{
+		v1.PUT("/cms/admin/invitation/accept", hnd.AcceptAdminInvitation)
+	}
+
+	v1.Use(middleware.Auth(cfg))
+	v1.Use(middleware.Admin(cfg))
+	{
+		v1.POST("/cms/admin/invitation", middleware.Permission(pc, "admin.invite"), hnd.InviteAdmin)
+		v1.GET("/cms/admin/invitation", middleware.Permission(pc, "admin.invite"), hnd.GetAdminInvitations)
+		v1.POST("/cms/admin/invitation/:id/resend", middleware.Permission(pc, "admin.invite"), hnd.ResendAdminInvitation)
+		v1.DELETE("/cms/admin/invitation/:id", middleware.Permission(pc, "admin.invite"), hnd.RevokeAdminInvitation)
 	}
 }
 
 // UserUpdaterHTTPHandler is a handler for user APIs
-func UserUpdaterHTTPHandler(cfg config.Config, router *gin.Engine, uu userservicev1.UserUpdaterUseCase, uf userservicev1.UserFinderUseCase, cloudStorage interfaces.CloudStorageUseCase) {
-	hnd := userhandlerv1.NewUserUpdaterHandler(uu, uf, cloudStorage)
+func UserUpdaterHTTPHandler(cfg config.Config, router *gin.Engine, pc middleware.PermissionChecker, uu userservicev1.UserUpdaterUseCase, uf userservicev1.UserFinderUseCase, photoStorage interfaces.PhotoStorageUseCase) {
+	hnd := userhandlerv1.NewUserUpdaterHandler(uu, uf, photoStorage)
 	v1 := router.Group("/v1")
 	{
 		v1.PUT("/user/forgot-password/request", hnd.ForgotPasswordRequest)
@@ -151,6 +250,7 @@ func UserUpdaterHTTPHandler(cfg config.Config, router *gin.Engine, uu userservic
 	v1.Use(middleware.Auth(cfg))
 	{
 		v1.PUT("/user/profile", hnd.UpdateUser)
+		v1.PATCH("/user/profile", hnd.PatchUser)
 		v1.PUT("/user/password", hnd.ChangePassword)
 		v1.PUT("/verify/otp", hnd.VerifyOTP)
 		v1.PUT("/resend/otp", hnd.ResendOTP)
@@ -158,23 +258,112 @@ func UserUpdaterHTTPHandler(cfg config.Config, router *gin.Engine, uu userservic
 
 	v1.Use(middleware.Admin(cfg))
 	{
-		v1.PUT("/cms/admin/:id", hnd.UpdateAdmin)
-		v1.PUT("/cms/user/activate/:id", hnd.ActivateDeactivateUser)
-		v1.PUT("/cms/role/:id", hnd.UpdateRole)
-		v1.PUT("/cms/permission/:id", hnd.UpdatePermission)
+		v1.PUT("/cms/admin/:id", middleware.Permission(pc, "admin.update"), hnd.UpdateAdmin)
+		v1.PATCH("/cms/admin/:id", middleware.Permission(pc, "admin.update"), hnd.PatchAdmin)
+		v1.PUT("/cms/user/activate/:id", middleware.Permission(pc, "user.update"), hnd.ActivateDeactivateUser)
+		v1.PUT("/cms/role/:id", middleware.Permission(pc, "role.update"), hnd.UpdateRole)
+		v1.PATCH("/cms/role/:id", middleware.Permission(pc, "role.update"), hnd.PatchRole)
+		v1.PUT("/cms/permission/:id", middleware.Permission(pc, "permission.update"), hnd.UpdatePermission)
+		v1.PATCH("/cms/permission/:id", middleware.Permission(pc, "permission.update"), hnd.PatchPermission)
+	}
+}
+
+// UserEmailChangerHTTPHandler is a handler for email change APIs
+func UserEmailChangerHTTPHandler(cfg config.Config, router *gin.Engine, ec userservicev1.UserEmailChangerUseCase) {
+	hnd := userhandlerv1.NewUserEmailChangerHandler(ec)
+	v1 := router.Group("/v1")
+	{
+		v1.PUT("/user/email/confirm", hnd.ConfirmEmailChange)
+		v1.PUT("/user/email/cancel", hnd.CancelEmailChange)
+	}
+
+	v1.Use(middleware.Auth(cfg))
+	{
+		v1.PUT("/user/email", hnd.RequestEmailChange)
+	}
+}
+
+// UserPhoneVerifierHTTPHandler is a handler for phone verification APIs
+func UserPhoneVerifierHTTPHandler(cfg config.Config, router *gin.Engine, pv userservicev1.UserPhoneVerifierUseCase) {
+	hnd := userhandlerv1.NewUserPhoneVerifierHandler(pv)
+	v1 := router.Group("/v1")
+
+	v1.Use(middleware.Auth(cfg))
+	{
+		v1.PUT("/user/phone/verification", hnd.SendPhoneVerification)
+		v1.PUT("/user/phone/verify", hnd.VerifyPhone)
+		v1.PUT("/user/otp-channel", hnd.SetOTPChannel)
+	}
+}
+
+// UserPreferenceManagerHTTPHandler is a handler for user preference APIs
+func UserPreferenceManagerHTTPHandler(cfg config.Config, router *gin.Engine, pc middleware.PermissionChecker, pm userservicev1.UserPreferenceManagerUseCase) {
+	hnd := userhandlerv1.NewUserPreferenceManagerHandler(pm)
+	v1 := router.Group("/v1")
+
+	v1.Use(middleware.Auth(cfg))
+	{
+		v1.GET("/user/preferences", hnd.GetPreferences)
+		v1.PATCH("/user/preferences", hnd.UpdatePreferences)
+	}
+
+	v1.Use(middleware.Admin(cfg))
+	{
+		v1.GET("/cms/user/preferences/:id", middleware.Permission(pc, "user.view"), hnd.GetUserPreferences)
+		v1.PATCH("/cms/user/preferences/:id", middleware.Permission(pc, "user.update"), hnd.UpdateUserPreferences)
 	}
 }
 
 // UserDeleterHTTPHandler is a handler for user APIs
-func UserDeleterHTTPHandler(cfg config.Config, router *gin.Engine, ud userservicev1.UserDeleterUseCase, cloudStorage interfaces.CloudStorageUseCase) {
+func UserDeleterHTTPHandler(cfg config.Config, router *gin.Engine, pc middleware.PermissionChecker, ud userservicev1.UserDeleterUseCase, cloudStorage interfaces.CloudStorageUseCase) {
 	hnd := userhandlerv1.NewUserDeleterHandler(ud, cloudStorage)
 	v1 := router.Group("/v1")
 
 	v1.Use(middleware.Auth(cfg))
 	v1.Use(middleware.Admin(cfg))
 	{
-		v1.DELETE("/cms/admin/:id", hnd.DeleteAdmin)
-		v1.DELETE("/cms/role/:id", hnd.DeleteRole)
+		v1.DELETE("/cms/admin/:id", middleware.Permission(pc, "admin.delete"), hnd.DeleteAdmin)
+		v1.DELETE("/cms/role/:id", middleware.Permission(pc, "role.delete"), hnd.DeleteRole)
+	}
+}
+
+// UserImporterHTTPHandler is a handler for user import APIs
+func UserImporterHTTPHandler(cfg config.Config, router *gin.Engine, pc middleware.PermissionChecker, ui userservicev1.UserImporterUseCase) {
+	hnd := userhandlerv1.NewUserImporterHandler(ui)
+	v1 := router.Group("/v1")
+
+	v1.Use(middleware.Auth(cfg))
+	v1.Use(middleware.Admin(cfg))
+	{
+		v1.POST("/cms/user/import", middleware.Permission(pc, "user.import"), hnd.ImportUsers)
+		v1.GET("/cms/user/import/:id", middleware.Permission(pc, "user.import"), hnd.GetUserImport)
+		v1.GET("/cms/user/import/:id/report", middleware.Permission(pc, "user.import"), hnd.GetUserImportReport)
+	}
+}
+
+// UserExporterHTTPHandler is a handler for user export APIs
+func UserExporterHTTPHandler(cfg config.Config, router *gin.Engine, pc middleware.PermissionChecker, ue userservicev1.UserExporterUseCase) {
+	hnd := userhandlerv1.NewUserExporterHandler(ue)
+	v1 := router.Group("/v1")
+
+	v1.Use(middleware.Auth(cfg))
+	v1.Use(middleware.Admin(cfg))
+	{
+		v1.GET("/cms/user/export", middleware.Permission(pc, "user.export"), hnd.ExportUsers)
+		v1.GET("/cms/admin/export", middleware.Permission(pc, "admin.export"), hnd.ExportAdminUsers)
+	}
+}
+
+// UserTrashHTTPHandler is a handler for deleted users and roles APIs
+func UserTrashHTTPHandler(cfg config.Config, router *gin.Engine, pc middleware.PermissionChecker, ut userservicev1.UserTrashUseCase) {
+	hnd := userhandlerv1.NewUserTrashHandler(ut)
+	v1 := router.Group("/v1")
+
+	v1.Use(middleware.Auth(cfg))
+	v1.Use(middleware.Admin(cfg))
+	{
+		v1.GET("/cms/trash", middleware.Permission(pc, "trash.view"), hnd.GetTrash)
+		v1.POST("/cms/trash/:type/:id/restore", middleware.Permission(pc, "trash.restore"), hnd.Restore)
 	}
 }
 
@@ -190,37 +379,81 @@ func ActivitiesFinderHTTPHandler(cfg config.Config, router *gin.Engine, af activ
 }
 
 // ActivitiesCreatorHTTPHandler is a handler for activities APIs
-func ActivitiesCreatorHTTPHandler(cfg config.Config, router *gin.Engine, ac activitiesservicev1.ActivitiesCreatorUseCase) {
+func ActivitiesCreatorHTTPHandler(cfg config.Config, router *gin.Engine, pc middleware.PermissionChecker, ac activitiesservicev1.ActivitiesCreatorUseCase) {
 	hnd := activitieshandlerv1.NewActivitiesCreatorHandler(ac)
 	v1 := router.Group("/v1")
 
 	v1.Use(middleware.Auth(cfg))
 	v1.Use(middleware.Admin(cfg))
 	{
-		v1.POST("/activities", hnd.CreateActivities)
+		v1.POST("/activities", middleware.Permission(pc, "activity.create"), hnd.CreateActivities)
 	}
 }
 
 // ActivitiesUpdaterHTTPHandler is a handler for activities APIs
-func ActivitiesUpdaterHTTPHandler(cfg config.Config, router *gin.Engine, au activitiesservicev1.ActivitiesUpdaterUseCase) {
-	hnd := activitieshandlerv1.NewActivitiesUpdaterHandler(au)
+func ActivitiesUpdaterHTTPHandler(cfg config.Config, router *gin.Engine, pc middleware.PermissionChecker, au activitiesservicev1.ActivitiesUpdaterUseCase, af activitiesservicev1.ActivitiesFinderUseCase) {
+	hnd := activitieshandlerv1.NewActivitiesUpdaterHandler(au, af)
 	v1 := router.Group("/v1")
 
 	v1.Use(middleware.Auth(cfg))
 	v1.Use(middleware.Admin(cfg))
 	{
-		v1.PUT("/activities/:id", hnd.UpdateActivities)
+		v1.PUT("/activities/:id", middleware.Permission(pc, "activity.update"), hnd.UpdateActivities)
+		v1.PATCH("/activities/:id", middleware.Permission(pc, "activity.update"), hnd.PatchActivities)
 	}
 }
 
 // ActivitiesDeleterHTTPHandler is a handler for activities APIs
-func ActivitiesDeleterHTTPHandler(cfg config.Config, router *gin.Engine, ad activitiesservicev1.ActivitiesDeleterUseCase) {
+func ActivitiesDeleterHTTPHandler(cfg config.Config, router *gin.Engine, pc middleware.PermissionChecker, ad activitiesservicev1.ActivitiesDeleterUseCase) {
 	hnd := activitieshandlerv1.NewActivitiesDeleterHandler(ad)
 	v1 := router.Group("/v1")
 
 	v1.Use(middleware.Auth(cfg))
 	v1.Use(middleware.Admin(cfg))
 	{
-		v1.DELETE("/activities/:id", hnd.DeleteActivities)
+		v1.DELETE("/activities/:id", middleware.Permission(pc, "activity.delete"), hnd.DeleteActivities)
+	}
+}
+
+// SCIMHTTPHandler is a handler for SCIM provisioning APIs
+func SCIMHTTPHandler(cfg config.Config, router *gin.Engine, su scimservicev1.SCIMUserUseCase, sg scimservicev1.SCIMGroupUseCase) {
+	hnd := scimhandlerv1.NewSCIMHandler(cfg)
+	uhnd := scimhandlerv1.NewSCIMUserHandler(cfg, su)
+	ghnd := scimhandlerv1.NewSCIMGroupHandler(cfg, sg)
+	v2 := router.Group("/scim/v2")
+
+	v2.Use(middleware.SCIM(cfg))
+	{
+		v2.GET("/ServiceProviderConfig", hnd.GetServiceProviderConfig)
+
+		v2.GET("/Users", uhnd.GetUsers)
+		v2.GET("/Users/:id", uhnd.GetUser)
+		v2.POST("/Users", uhnd.CreateUser)
+		v2.PUT("/Users/:id", uhnd.ReplaceUser)
+		v2.PATCH("/Users/:id", uhnd.PatchUser)
+		v2.DELETE("/Users/:id", uhnd.DeleteUser)
+
+		v2.GET("/Groups", ghnd.GetGroups)
+		v2.GET("/Groups/:id", ghnd.GetGroup)
+		v2.POST("/Groups", ghnd.CreateGroup)
+		v2.PUT("/Groups/:id", ghnd.ReplaceGroup)
+		v2.PATCH("/Groups/:id", ghnd.PatchGroup)
+		v2.DELETE("/Groups/:id", ghnd.DeleteGroup)
+	}
+}
+
+// PrivacyHTTPHandler is a handler for personal data export and erasure APIs
+func PrivacyHTTPHandler(cfg config.Config, router *gin.Engine, de privacyservicev1.DataExporterUseCase, dr privacyservicev1.DataEraserUseCase) {
+	hnd := privacyhandlerv1.NewDataRequestHandler(de, dr)
+	v1 := router.Group("/v1")
+
+	v1.Use(middleware.Auth(cfg))
+	{
+		v1.POST("/user/data-export", hnd.RequestExport)
+		v1.GET("/user/data-export/:id", hnd.GetExport)
+		v1.GET("/user/data-export/:id/download", hnd.DownloadExport)
+		v1.POST("/user/erasure", hnd.RequestErasure)
+		v1.GET("/user/erasure", hnd.GetErasure)
+		v1.DELETE("/user/erasure", hnd.CancelErasure)
 	}
 }
@@ -19,4 +19,14 @@ const (
 	RolePermissionFindByRoleIDAndPermissionID = prefix + ":role-permission:find-by-role-id-and-permission-id:%v:%v"
 	// RoleFindByID is a redis key for find cms role by id.
 	RoleFindByID = prefix + ":role:find-by-id:%v"
+	// SMSRateByPhoneNumber is a redis key for the messages recently sent to a phone number.
+	SMSRateByPhoneNumber = prefix + ":sms-rate:find-by-phone-number:%v"
+	// SMSResendByPhoneNumber is a redis key set while a phone number waits before receiving another message.
+	SMSResendByPhoneNumber = prefix + ":sms-resend:find-by-phone-number:%v"
+	// UserPreferencesByUserID is a redis key for find user preferences by user id.
+	UserPreferencesByUserID = prefix + ":user-preferences:find-by-user-id:%v"
+	// NotificationEventStream is a redis stream keeping the recent notification events for the resuming clients.
+	NotificationEventStream = prefix + ":notification-events"
+	// NotificationEventChannel is a redis channel the notification events are published on to every instance.
+	NotificationEventChannel = prefix + ":notification-events:channel"
 )
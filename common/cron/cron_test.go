@@ -0,0 +1,88 @@
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"gin-starter/common/cron"
+)
+
+type CronTestSuite struct {
+	suite.Suite
+	jakarta *time.Location
+}
+
+func TestCronTestSuite(t *testing.T) {
+	suite.Run(t, new(CronTestSuite))
+}
+
+func (suite *CronTestSuite) SetupTest() {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	suite.Require().NoError(err)
+	suite.jakarta = loc
+}
+
+func (suite *CronTestSuite) next(expr string, after time.Time, loc *time.Location) time.Time {
+	s, err := cron.Parse(expr)
+	suite.Require().NoError(err)
+	return s.Next(after, loc)
+}
+
+func (suite *CronTestSuite) TestParse() {
+	suite.Run("successfully parse expressions and descriptors", func() {
+		for _, expr := range []string{"* * * * *", "*/15 9-17 * * 1-5", "0 0 1,15 * *", "5/10 * * * *", "0 9 * * 7", "@daily", "@Weekly"} {
+			_, err := cron.Parse(expr)
+			suite.NoError(err, expr)
+		}
+	})
+
+	suite.Run("fail to parse malformed expressions", func() {
+		for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+			_, err := cron.Parse(expr)
+			suite.Error(err, expr)
+		}
+	})
+}
+
+func (suite *CronTestSuite) TestNext() {
+	after := time.Date(2026, 10, 19, 9, 30, 0, 0, suite.jakarta) // Monday
+
+	suite.Run("successfully find the next minute", func() {
+		suite.Equal(after.Add(time.Minute), suite.next("* * * * *", after, suite.jakarta))
+	})
+
+	suite.Run("successfully evaluate the schedule in its time zone", func() {
+		got := suite.next("0 9 * * *", after, suite.jakarta)
+		suite.True(time.Date(2026, 10, 20, 9, 0, 0, 0, suite.jakarta).Equal(got))
+		suite.True(time.Date(2026, 10, 20, 2, 0, 0, 0, time.UTC).Equal(got))
+	})
+
+	suite.Run("successfully skip to the matching weekday", func() {
+		got := suite.next("0 8 * * 6", after, suite.jakarta)
+		suite.True(time.Date(2026, 10, 24, 8, 0, 0, 0, suite.jakarta).Equal(got))
+	})
+
+	suite.Run("successfully match either day field when both are restricted", func() {
+		got := suite.next("0 0 1 * 3", after, suite.jakarta)
+		suite.True(time.Date(2026, 10, 21, 0, 0, 0, 0, suite.jakarta).Equal(got))
+	})
+
+	suite.Run("successfully roll over to the next year", func() {
+		got := suite.next("@yearly", after, time.UTC)
+		suite.True(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC).Equal(got))
+	})
+
+	suite.Run("successfully skip a local time missing on daylight saving", func() {
+		ny, err := time.LoadLocation("America/New_York")
+		suite.Require().NoError(err)
+
+		got := suite.next("30 2 * * *", time.Date(2027, 3, 13, 3, 0, 0, 0, ny), ny)
+		suite.True(time.Date(2027, 3, 15, 2, 30, 0, 0, ny).Equal(got))
+	})
+
+	suite.Run("return the zero time when the schedule never runs", func() {
+		suite.True(suite.next("0 0 30 2 *", after, time.UTC).IsZero())
+	})
+}
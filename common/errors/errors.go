@@ -23,6 +23,130 @@ var (
 	ErrWrongPasswordConfirmation = NewError(http.StatusBadRequest, "Konfirmasi password kamu tidak sesuai.")
 	// ErrOTPMismatch represents error when otp is mismatched.
 	ErrOTPMismatch = NewError(http.StatusBadRequest, "Kode OTP Salah")
+	// ErrNoCMSAccess represents error when a directory user is not mapped to any role.
+	ErrNoCMSAccess = NewError(http.StatusForbidden, "akun anda tidak memiliki akses ke cms")
+	// ErrUserDeactivated represents error when a deactivated user tries to sign in.
+	ErrUserDeactivated = NewError(http.StatusForbidden, "akun anda tidak aktif")
+	// ErrInvalidImportFile represents error when an import file has no header or data rows.
+	ErrInvalidImportFile = NewError(http.StatusBadRequest, "file import tidak valid")
+	// ErrUnsupportedImportFile represents error when an import file is neither csv nor xlsx.
+	ErrUnsupportedImportFile = NewError(http.StatusBadRequest, "format file import harus csv atau xlsx")
+	// ErrTooManyImportRows represents error when an import file exceeds the row limit.
+	ErrTooManyImportRows = NewError(http.StatusRequestEntityTooLarge, "jumlah baris file import melebihi batas")
+	// ErrUnsupportedExportFormat represents error when an export format is neither csv nor xlsx.
+	ErrUnsupportedExportFormat = NewError(http.StatusBadRequest, "format export harus csv atau xlsx")
+	// ErrInvalidExportColumn represents error when an export column is unknown or not exportable.
+	ErrInvalidExportColumn = NewError(http.StatusBadRequest, "kolom export tidak valid")
+	// ErrRoleHasUsers represents error when a role to be deleted is still assigned to users.
+	ErrRoleHasUsers = NewError(http.StatusConflict, "role masih digunakan oleh user, pindahkan user ke role lain terlebih dahulu")
+	// ErrEmailReserved represents error when an email still belongs to a deleted user.
+	ErrEmailReserved = NewFieldError(http.StatusConflict, "email masih digunakan oleh akun yang dihapus", "email")
+	// ErrRoleInTrash represents error when restoring a user whose role is deleted.
+	ErrRoleInTrash = NewError(http.StatusConflict, "role user ada di trash, pulihkan role terlebih dahulu")
+	// ErrInvalidTrashType represents error when the trash type is neither user nor role.
+	ErrInvalidTrashType = NewError(http.StatusBadRequest, "tipe trash harus user atau role")
+	// ErrDataExportNotReady represents error when downloading a data export that is still being assembled.
+	ErrDataExportNotReady = NewError(http.StatusConflict, "export data belum selesai diproses")
+	// ErrDataExportExpired represents error when downloading a data export whose link has expired.
+	ErrDataExportExpired = NewError(http.StatusGone, "link export data sudah kedaluwarsa")
+	// ErrErasureInProgress represents error when cancelling an erasure that has already started.
+	ErrErasureInProgress = NewError(http.StatusConflict, "penghapusan data sedang diproses dan tidak dapat dibatalkan")
+	// ErrInvalidFilter represents error when a list filter is malformed or targets a field that cannot be filtered.
+	ErrInvalidFilter = NewError(http.StatusBadRequest, "filter tidak valid")
+	// ErrInvalidSort represents error when a list sort is malformed or targets a field that cannot be sorted.
+	ErrInvalidSort = NewError(http.StatusBadRequest, "sort tidak valid")
+	// ErrInvalidCursor represents error when a list cursor is malformed or the list does not support cursor pagination.
+	ErrInvalidCursor = NewError(http.StatusBadRequest, "cursor tidak valid")
+	// ErrInvalidImage represents error when an uploaded photo cannot be decoded as an image.
+	ErrInvalidImage = NewError(http.StatusBadRequest, "file bukan gambar yang valid")
+	// ErrImageTooLarge represents error when the dimensions of an uploaded photo exceed the limit.
+	ErrImageTooLarge = NewError(http.StatusRequestEntityTooLarge, "dimensi gambar melebihi batas")
+	// ErrUploadTooLarge represents error when an uploaded file exceeds the size limit of its folder.
+	ErrUploadTooLarge = NewError(http.StatusRequestEntityTooLarge, "ukuran file melebihi batas")
+	// ErrUploadTypeNotAllowed represents error when the sniffed type of an uploaded file is not allowed in its folder.
+	ErrUploadTypeNotAllowed = NewError(http.StatusUnsupportedMediaType, "tipe file tidak diizinkan")
+	// ErrUploadFolderNotAllowed represents error when uploading to a folder without an upload policy.
+	ErrUploadFolderNotAllowed = NewError(http.StatusForbidden, "folder tidak menerima upload")
+	// ErrUploadInfected represents error when the content scanner detects malware in an uploaded file.
+	ErrUploadInfected = NewError(http.StatusUnprocessableEntity, "file terdeteksi mengandung malware")
+	// ErrScannerUnavailable represents error when the content scanner cannot scan an uploaded file.
+	ErrScannerUnavailable = NewError(http.StatusServiceUnavailable, "pemindai file sedang tidak tersedia")
+	// ErrEmailAlreadyUsed represents error when an email, ignoring case, already belongs to another active user.
+	ErrEmailAlreadyUsed = NewFieldError(http.StatusConflict, "email sudah digunakan", "email")
+	// ErrInvalidInvitation represents error when an invite link is malformed, tampered with or superseded by a resend.
+	ErrInvalidInvitation = NewError(http.StatusBadRequest, "link undangan tidak valid")
+	// ErrInvitationExpired represents error when accepting an invitation past its expiry.
+	ErrInvitationExpired = NewError(http.StatusGone, "undangan sudah kedaluwarsa")
+	// ErrInvitationNotPending represents error when acting on an invitation already accepted or revoked.
+	ErrInvitationNotPending = NewError(http.StatusConflict, "undangan sudah diterima atau dibatalkan")
+	// ErrEmailUnchanged represents error when requesting to change the email to the current one.
+	ErrEmailUnchanged = NewError(http.StatusBadRequest, "email baru sama dengan email saat ini")
+	// ErrInvalidEmailChange represents error when an email change link is unknown.
+	ErrInvalidEmailChange = NewError(http.StatusBadRequest, "link perubahan email tidak valid")
+	// ErrEmailChangeExpired represents error when confirming an email change past its expiry.
+	ErrEmailChangeExpired = NewError(http.StatusGone, "link perubahan email sudah kedaluwarsa")
+	// ErrEmailChangeNotPending represents error when acting on an email change already confirmed or cancelled.
+	ErrEmailChangeNotPending = NewError(http.StatusConflict, "perubahan email sudah dikonfirmasi atau dibatalkan")
+	// ErrInvalidPhoneNumber represents error when a phone number cannot be normalised to E.164.
+	ErrInvalidPhoneNumber = NewError(http.StatusBadRequest, "nomor telepon tidak valid")
+	// ErrSMSRateLimited represents error when a phone number received too many messages recently.
+	ErrSMSRateLimited = NewError(http.StatusTooManyRequests, "terlalu banyak sms dikirim ke nomor ini, coba lagi nanti")
+	// ErrPhoneAlreadyVerified represents error when requesting a code for a phone number already verified.
+	ErrPhoneAlreadyVerified = NewError(http.StatusConflict, "nomor telepon sudah terverifikasi")
+	// ErrPhoneNotVerified represents error when choosing sms as otp channel without a verified phone number.
+	ErrPhoneNotVerified = NewError(http.StatusConflict, "nomor telepon belum terverifikasi")
+	// ErrInvalidVerificationCode represents error when a phone verification code is wrong, expired or exhausted.
+	ErrInvalidVerificationCode = NewError(http.StatusBadRequest, "kode verifikasi salah atau sudah kedaluwarsa")
+	// ErrInvalidOTPChannel represents error when the otp channel is neither email nor sms.
+	ErrInvalidOTPChannel = NewError(http.StatusBadRequest, "channel otp harus email atau sms")
+	// ErrUnknownPreference represents error when a preference key is missing from the preference schema.
+	ErrUnknownPreference = NewError(http.StatusBadRequest, "preferensi tidak dikenal")
+	// ErrInvalidPreference represents error when a preference value does not match its type or options.
+	ErrInvalidPreference = NewError(http.StatusBadRequest, "nilai preferensi tidak valid")
+	// ErrPreconditionRequired represents error when an update is sent without the If-Match header.
+	ErrPreconditionRequired = NewError(http.StatusPreconditionRequired, "header If-Match wajib diisi")
+	// ErrVersionMismatch represents error when an update is based on a version which has been changed since.
+	ErrVersionMismatch = NewError(http.StatusPreconditionFailed, "data telah diubah oleh pengguna lain, muat ulang data terbaru")
+	// ErrUnsupportedPatchType represents error when a patch is sent with a content type other than json merge patch.
+	ErrUnsupportedPatchType = NewError(http.StatusUnsupportedMediaType, "content type harus application/merge-patch+json")
+	// ErrInvalidPatch represents error when a patch document is not a json object.
+	ErrInvalidPatch = NewError(http.StatusBadRequest, "dokumen patch harus berupa objek json")
+	// ErrUnknownPatchField represents error when a patch document sets a field which cannot be patched.
+	ErrUnknownPatchField = NewError(http.StatusBadRequest, "field tidak dikenal atau tidak dapat diubah")
+	// ErrInvalidPatchValue represents error when a patched field has a value of the wrong type or failing its validation.
+	ErrInvalidPatchValue = NewError(http.StatusBadRequest, "nilai field tidak valid")
+	// ErrSystemRoleProtected represents error when deleting or renaming a system role.
+	ErrSystemRoleProtected = NewError(http.StatusForbidden, "role sistem tidak dapat dihapus atau diubah namanya")
+	// ErrDeclaredPermissionProtected represents error when renaming a permission declared by a module.
+	ErrDeclaredPermissionProtected = NewError(http.StatusForbidden, "permission yang dideklarasikan modul tidak dapat diubah namanya")
+	// ErrCorePermissionRemoved represents error when removing a core permission from a system role.
+	ErrCorePermissionRemoved = NewError(http.StatusForbidden, "permission inti dari role sistem tidak dapat dihapus")
+	// ErrLastSuperAdmin represents error when removing, deactivating or moving the last active super admin.
+	ErrLastSuperAdmin = NewError(http.StatusConflict, "super admin aktif terakhir tidak dapat dihapus, dinonaktifkan, atau dipindahkan rolenya")
+	// ErrPermissionEscalation represents error when granting permissions the admin does not hold.
+	ErrPermissionEscalation = NewError(http.StatusForbidden, "tidak dapat memberikan permission yang tidak anda miliki")
+	// ErrNotificationTemplateKeyUsed represents error when a notification template key already belongs to another template.
+	ErrNotificationTemplateKeyUsed = NewFieldError(http.StatusConflict, "key template sudah digunakan", "key")
+	// ErrInvalidNotificationTemplateKey represents error when a notification template key is not lower case words joined by dots, dashes or underscores.
+	ErrInvalidNotificationTemplateKey = NewFieldError(http.StatusBadRequest, "key template hanya boleh berisi huruf kecil, angka, titik, strip, dan garis bawah", "key")
+	// ErrInvalidNotificationTemplateLocale represents error when a notification template is translated to a language users cannot choose.
+	ErrInvalidNotificationTemplateLocale = NewFieldError(http.StatusBadRequest, "bahasa template tidak didukung", "translations")
+	// ErrNotificationTemplateFallbackMissing represents error when a notification template lacks the translation of the default language.
+	ErrNotificationTemplateFallbackMissing = NewFieldError(http.StatusBadRequest, "template harus memiliki terjemahan bahasa default", "translations")
+	// ErrNotificationTemplateNotPublished represents error when sending a notification template which is still a draft.
+	ErrNotificationTemplateNotPublished = NewError(http.StatusConflict, "template notifikasi belum dipublikasikan")
+	// ErrNotificationTemplateDataMissing represents error when sending a notification template without the data of its variables.
+	ErrNotificationTemplateDataMissing = NewFieldError(http.StatusBadRequest, "data variabel template tidak lengkap", "data")
+	// ErrInvalidNotificationSchedule represents error when a scheduled notification has neither or both a send time and a cron expression, or a cron expression which never runs.
+	ErrInvalidNotificationSchedule = NewFieldError(http.StatusBadRequest, "jadwal notifikasi harus berupa waktu kirim atau ekspresi cron yang valid", "cron")
+	// ErrNotificationSendAtPassed represents error when a notification is scheduled at a time which has passed.
+	ErrNotificationSendAtPassed = NewFieldError(http.StatusBadRequest, "waktu kirim notifikasi harus di masa depan", "send_at")
+	// ErrInvalidNotificationTimezone represents error when a notification is scheduled in an unknown time zone.
+	ErrInvalidNotificationTimezone = NewFieldError(http.StatusBadRequest, "zona waktu tidak valid", "timezone")
+	// ErrNotificationContentMissing represents error when a scheduled notification has neither a template nor a title and message.
+	ErrNotificationContentMissing = NewFieldError(http.StatusBadRequest, "notifikasi harus memiliki template atau judul dan pesan", "title")
+	// ErrScheduledNotificationNotScheduled represents error when cancelling a scheduled notification which already completed or was cancelled.
+	ErrScheduledNotificationNotScheduled = NewError(http.StatusConflict, "notifikasi terjadwal sudah selesai atau dibatalkan")
 )
 
 // Error represents a data structure for error.
@@ -33,6 +157,8 @@ type Error struct {
 	// Message represents error message.
 	// This is the message that exposed to the user.
 	Message string `json:"message"`
+	// Field names the field of the request the error is about, empty when the error is not about a field.
+	Field string `json:"field,omitempty"`
 }
 
 // NewError creates an instance of Error.
@@ -43,8 +169,21 @@ func NewError(code int, message string) *Error {
 	}
 }
 
+// NewFieldError creates an instance of Error about a field of the request.
+func NewFieldError(code int, message, field string) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
+		Field:   field,
+	}
+}
+
 // Error returns internal message in one string.
 func (err *Error) Error() error {
+	if err.Field != "" {
+		return fmt.Errorf("%d:%s:%s", err.Code, err.Message, err.Field)
+	}
+
 	return fmt.Errorf("%d:%s", err.Code, err.Message)
 }
 
@@ -64,5 +203,9 @@ func ParseError(err error) *Error {
 		return ErrInternalServerError
 	}
 
+	if len(split) > 2 {
+		return NewFieldError(int(code), split[1], split[2])
+	}
+
 	return NewError(int(code), split[1])
 }
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"gin-starter/entity"
+)
+
+// Authenticator define interface for verifying CMS credentials
+type Authenticator interface {
+	// Authenticate verifies the credentials and returns the authenticated identity
+	Authenticate(ctx context.Context, email, password string) (*entity.AuthIdentity, error)
+}
@@ -19,3 +19,11 @@ type Cacheable interface {
 	// Scan all cache key with certain pattern
 	Scan(pattern string) ([]string, error)
 }
+
+// Counter is an interface for the counters and flags a cache changes atomically, shared by every instance.
+type Counter interface {
+	// Increment increments the counter of the key and returns its value, a new counter expires after ttl
+	Increment(key string, ttl time.Duration) (int64, error)
+	// SetNX sets the key for ttl unless it exists, returning false when it exists
+	SetNX(key string, ttl time.Duration) (bool, error)
+}
@@ -1,6 +1,7 @@
 package interfaces
 
 import (
+	"io"
 	"mime/multipart"
 )
 
@@ -8,5 +9,8 @@ import (
 type CloudStorageUseCase interface {
 	Upload(f *multipart.FileHeader, folder string) (string, error)
 	UploadSavedFile(filepath, folder string) (string, error)
+	// UploadObject uploads the content of r to the given path, replacing any existing object
+	UploadObject(r io.Reader, path, contentType string) error
 	Delete(path string) error
+	Download(path string) (io.ReadCloser, error)
 }
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"io"
+)
+
+// ContentScanner define interface for scanning uploaded files for malware
+type ContentScanner interface {
+	// Scan scans the content read from r and returns the name of the detected signature, empty when the content is clean
+	Scan(ctx context.Context, r io.Reader) (string, error)
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// DeviceDetacher define interface for detaching the push devices of a user from other modules
+type DeviceDetacher interface {
+	// DetachDevices stops delivering push notifications to the device holding the token,
+	// or to every device of the user when the token is empty
+	DetachDevices(ctx context.Context, userID uuid.UUID, token string) error
+}
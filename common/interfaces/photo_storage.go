@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"mime/multipart"
+
+	"gin-starter/entity"
+)
+
+// PhotoStorageUseCase define interface for storing processed profile photos
+type PhotoStorageUseCase interface {
+	// Upload checks and processes the uploaded photo and stores its variants under the content hash in folder
+	Upload(ctx context.Context, f *multipart.FileHeader, folder string) (*entity.UserPhoto, error)
+	// Delete deletes the stored variants of the photo of the user
+	Delete(user *entity.User) error
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"gin-starter/entity"
+)
+
+// PreferenceReader define interface for reading the preferences of a user from other modules
+type PreferenceReader interface {
+	// GetPreferences returns the preferences of the user, keys the user never set hold their default
+	GetPreferences(ctx context.Context, userID uuid.UUID) (entity.Preferences, error)
+}
@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+)
+
+// PushMessage is a push notification shown on the devices of a user
+type PushMessage struct {
+	Title string
+	Body  string
+	// Data is delivered to the application along with the notification
+	Data map[string]string
+}
+
+// PushProvider define interface for delivering push notifications to devices
+type PushProvider interface {
+	// Name returns the name of the provider, stored along with the tokens it issued
+	Name() string
+	// Send delivers the message to the device tokens, or player ids, and returns the tokens
+	// the provider reports as no longer valid. It fails only when no device could be reached.
+	Send(ctx context.Context, tokens []string, message *PushMessage) ([]string, error)
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import "context"
+
+// SMSSender define interface for sending text messages
+type SMSSender interface {
+	// Send sends the message to the phone number, given in E.164
+	Send(ctx context.Context, phoneNumber, message string) error
+}
@@ -0,0 +1,232 @@
+// Package patch applies RFC 7396 JSON Merge Patch documents to resources.
+// Each resource declares the fields a client may patch, only the fields present in a document are validated
+// and a null value clears the field instead of being mistaken for an omitted one.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"gin-starter/common/errors"
+)
+
+const (
+	// ContentType is the media type of JSON Merge Patch documents
+	ContentType = "application/merge-patch+json"
+	// DateLayout is the layout of the date fields, the same layout the PUT endpoints accept
+	DateLayout = "2006-01-02"
+)
+
+// Decoder converts the JSON value of a field to the value stored in its column
+type Decoder func(raw json.RawMessage) (interface{}, error)
+
+// Field declares a field of a resource a client may patch
+type Field struct {
+	// Column is the column the field is stored in, empty for fields the service applies itself
+	Column string
+	// Nullable lets null clear the field, null is rejected for the other fields
+	Nullable bool
+	// ClearOnly accepts null only, for fields set through another endpoint such as uploaded files
+	ClearOnly bool
+	// Cleared is the value stored when the field is cleared, nil stores NULL
+	Cleared interface{}
+	// Rules are the validation tags the decoded value must pass, as used in the binding tags of requests
+	Rules string
+	// Decode decodes the value of the field, String when nil
+	Decode Decoder
+}
+
+// Schema declares the fields of a resource a client may patch.
+// Client supplied names are only ever looked up in Fields, so they never reach the SQL.
+type Schema struct {
+	// Fields maps the field names of the API to their declaration
+	Fields map[string]*Field
+}
+
+// Patch is a validated merge patch document
+type Patch struct {
+	schema *Schema
+	// values holds the decoded value of every field of the document, nil for the cleared ones
+	values map[string]interface{}
+}
+
+// Bind reads the merge patch document of a request, application/json is accepted along with the merge patch media type
+func Bind(c *gin.Context, schema *Schema) (*Patch, error) {
+	mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if err != nil || (mediaType != ContentType && mediaType != binding.MIMEJSON) {
+		return nil, errors.ErrUnsupportedPatchType.Error()
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, errors.ErrInvalidPatch.Error()
+	}
+
+	return Parse(data, schema)
+}
+
+// Parse parses a merge patch document against the schema of the resource.
+// The document must be a JSON object, unknown fields are rejected and the provided fields are decoded and validated.
+func Parse(data []byte, schema *Schema) (*Patch, error) {
+	var document map[string]json.RawMessage
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := decoder.Decode(&document); err != nil || document == nil || decoder.More() {
+		return nil, errors.ErrInvalidPatch.Error()
+	}
+
+	p := &Patch{schema: schema, values: make(map[string]interface{}, len(document))}
+
+	for name, raw := range document {
+		field, ok := schema.Fields[name]
+		if !ok {
+			return nil, errors.ErrUnknownPatchField.Error()
+		}
+
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			if !field.Nullable && !field.ClearOnly {
+				return nil, errors.ErrInvalidPatchValue.Error()
+			}
+
+			p.values[name] = nil
+			continue
+		}
+
+		if field.ClearOnly {
+			return nil, errors.ErrInvalidPatchValue.Error()
+		}
+
+		decode := field.Decode
+		if decode == nil {
+			decode = String
+		}
+
+		value, err := decode(raw)
+		if err != nil {
+			return nil, errors.ErrInvalidPatchValue.Error()
+		}
+
+		if field.Rules != "" && validate(value, field.Rules) != nil {
+			return nil, errors.ErrInvalidPatchValue.Error()
+		}
+
+		p.values[name] = value
+	}
+
+	return p, nil
+}
+
+// Empty tells whether the document changes no field
+func (p *Patch) Empty() bool {
+	return len(p.values) == 0
+}
+
+// Has tells whether the document provides the field, cleared fields included
+func (p *Patch) Has(name string) bool {
+	_, ok := p.values[name]
+	return ok
+}
+
+// Value returns the decoded value of a provided field, nil when the field is cleared or missing
+func (p *Patch) Value(name string) interface{} {
+	return p.values[name]
+}
+
+// String returns the value of a provided string field, empty when the field is cleared or missing
+func (p *Patch) String(name string) string {
+	s, _ := p.values[name].(string)
+	return s
+}
+
+// Strings returns the value of a provided string list field, nil when the field is cleared or missing
+func (p *Patch) Strings(name string) []string {
+	s, _ := p.values[name].([]string)
+	return s
+}
+
+// Fields returns the sorted names of the provided fields
+func (p *Patch) Fields() []string {
+	names := make([]string, 0, len(p.values))
+	for name := range p.values {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Columns returns the column updates of the provided fields, cleared fields are set to their Cleared value.
+// Fields without a column are left to the service.
+func (p *Patch) Columns() map[string]interface{} {
+	columns := make(map[string]interface{}, len(p.values))
+
+	for name, value := range p.values {
+		field := p.schema.Fields[name]
+		if field.Column == "" {
+			continue
+		}
+
+		if value == nil {
+			columns[field.Column] = field.Cleared
+			continue
+		}
+
+		columns[field.Column] = value
+	}
+
+	return columns
+}
+
+// String decodes a string field
+func String(raw json.RawMessage) (interface{}, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Strings decodes a string list field
+func Strings(raw json.RawMessage) (interface{}, error) {
+	var s []string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		s = make([]string, 0)
+	}
+
+	return s, nil
+}
+
+// Date decodes a date field in DateLayout
+func Date(raw json.RawMessage) (interface{}, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+
+	return time.Parse(DateLayout, s)
+}
+
+// validate checks a value against validation tags with the validator gin binds requests with
+func validate(value interface{}, rules string) error {
+	engine, ok := binding.Validator.Engine().(interface {
+		Var(field interface{}, tag string) error
+	})
+	if !ok {
+		return nil
+	}
+
+	return engine.Var(value, rules)
+}
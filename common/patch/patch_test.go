@@ -0,0 +1,103 @@
+package patch_test
+
+import (
+	"testing"
+	"time"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/patch"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PatchTestSuite struct {
+	suite.Suite
+	schema *patch.Schema
+}
+
+func TestPatchTestSuite(t *testing.T) {
+	suite.Run(t, new(PatchTestSuite))
+}
+
+func (suite *PatchTestSuite) SetupTest() {
+	suite.schema = &patch.Schema{
+		Fields: map[string]*patch.Field{
+			"name":     {Column: "name", Rules: "required"},
+			"dob":      {Column: "dob", Nullable: true, Decode: patch.Date},
+			"phone":    {Column: "phone_number", Nullable: true, Cleared: ""},
+			"photo":    {Column: "photo", ClearOnly: true, Cleared: ""},
+			"role_id":  {Rules: "required,uuid"},
+			"tag_list": {Column: "tags", Rules: "dive,required", Decode: patch.Strings},
+		},
+	}
+}
+
+func (suite *PatchTestSuite) TestParse() {
+	suite.Run("only the provided fields are set, omitted fields stay untouched", func() {
+		p, err := patch.Parse([]byte(`{"name":"John","dob":"1990-02-03"}`), suite.schema)
+		suite.Require().NoError(err)
+
+		suite.Equal([]string{"dob", "name"}, p.Fields())
+		suite.False(p.Has("phone"))
+		suite.Equal(map[string]interface{}{
+			"name": "John",
+			"dob":  time.Date(1990, 2, 3, 0, 0, 0, 0, time.UTC),
+		}, p.Columns())
+	})
+
+	suite.Run("null clears a nullable field to its cleared value", func() {
+		p, err := patch.Parse([]byte(`{"dob":null,"phone":null,"photo":null}`), suite.schema)
+		suite.Require().NoError(err)
+
+		suite.True(p.Has("phone"))
+		suite.Equal(map[string]interface{}{
+			"dob":          nil,
+			"phone_number": "",
+			"photo":        "",
+		}, p.Columns())
+	})
+
+	suite.Run("fields without a column are left to the service", func() {
+		p, err := patch.Parse([]byte(`{"role_id":"8f0e3f39-5a1c-4f0c-9d8e-3a5b7c9d1e2f"}`), suite.schema)
+		suite.Require().NoError(err)
+
+		suite.Equal("8f0e3f39-5a1c-4f0c-9d8e-3a5b7c9d1e2f", p.String("role_id"))
+		suite.Empty(p.Columns())
+	})
+
+	suite.Run("an empty document changes nothing", func() {
+		p, err := patch.Parse([]byte(`{}`), suite.schema)
+		suite.Require().NoError(err)
+
+		suite.True(p.Empty())
+	})
+
+	suite.Run("fail on a document which is not an object", func() {
+		for _, document := range []string{`[]`, `null`, `"name"`, `{"name":"John"} {}`, `{`} {
+			_, err := patch.Parse([]byte(document), suite.schema)
+			suite.Equal(errors.ErrInvalidPatch.Error(), err, document)
+		}
+	})
+
+	suite.Run("fail on a field missing from the schema", func() {
+		_, err := patch.Parse([]byte(`{"email":"john@example.com"}`), suite.schema)
+		suite.Equal(errors.ErrUnknownPatchField.Error(), err)
+	})
+
+	suite.Run("fail on null for a field which cannot be cleared", func() {
+		_, err := patch.Parse([]byte(`{"name":null}`), suite.schema)
+		suite.Equal(errors.ErrInvalidPatchValue.Error(), err)
+	})
+
+	suite.Run("fail on a value for a field which can only be cleared", func() {
+		_, err := patch.Parse([]byte(`{"photo":"/users/user/profile/new"}`), suite.schema)
+		suite.Equal(errors.ErrInvalidPatchValue.Error(), err)
+	})
+
+	suite.Run("fail on a value of the wrong type or failing its rules", func() {
+		for _, document := range []string{`{"name":1}`, `{"name":""}`, `{"dob":"03-02-1990"}`, `{"role_id":"admin"}`, `{"tag_list":["a",""]}`} {
+			_, err := patch.Parse([]byte(document), suite.schema)
+			suite.Equal(errors.ErrInvalidPatchValue.Error(), err, document)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package patch
+
+import (
+	goErrors "errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrVersionConflict is returned when a patch is based on a version of the row which has been changed since
+var ErrVersionConflict = goErrors.New("version conflict")
+
+// Update applies column updates to the row of model with the id when the row is still at version,
+// moves the row to the next version and reloads it into model.
+// It fails with ErrVersionConflict when the version is outdated and gorm.ErrRecordNotFound when the row is missing.
+func Update(db *gorm.DB, model interface{}, id interface{}, version int64, columns map[string]interface{}) error {
+	updates := make(map[string]interface{}, len(columns)+2)
+	for column, value := range columns {
+		updates[column] = value
+	}
+
+	updates["version"] = gorm.Expr("version + 1")
+	updates["updated_at"] = time.Now()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(model).
+			Where("id = ? AND version = ?", id, version).
+			UpdateColumns(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			if err := tx.Where("id = ?", id).First(model).Error; err != nil {
+				return err
+			}
+
+			return ErrVersionConflict
+		}
+
+		return tx.Where("id = ?", id).First(model).Error
+	})
+}
@@ -0,0 +1,219 @@
+// Package permission holds the catalogue of the permissions the modules declare in code.
+// Module builders declare the permissions their routes use and the default roles granting them,
+// the catalogue is then synchronised into the database when the application boots.
+package permission
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// SuperAdminRole is the system role granted every permission the modules declare,
+// its last active holder cannot be removed so that an installation always keeps an administrator
+const SuperAdminRole = "Super Admin"
+
+// Permission is a permission declared by a module
+type Permission struct {
+	// Name is the unique name the CMS routes check through middleware.Permission, such as "user.create"
+	Name string
+	// Label is the human readable name shown in the CMS
+	Label string
+	// Group is the module declaring the permission
+	Group string
+}
+
+// Group is a module with the permissions it declares
+type Group struct {
+	Name        string
+	Permissions []*Permission
+}
+
+// Role is a default role declared with the names of the permissions it grants
+type Role struct {
+	Name        string
+	Permissions []string
+	// System roles cannot be deleted or renamed and their declared permissions cannot be removed
+	System bool
+}
+
+// Registry is a catalogue of declared permissions and default roles
+type Registry struct {
+	mu          sync.RWMutex
+	permissions map[string]*Permission
+	roles       map[string]*Role
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		permissions: make(map[string]*Permission),
+		roles:       make(map[string]*Role),
+	}
+}
+
+// Default is the registry the module builders declare their permissions in
+var Default = NewRegistry()
+
+// Declare declares the permissions of a module in the default registry
+func Declare(group string, permissions ...Permission) {
+	Default.Declare(group, permissions...)
+}
+
+// DeclareRole declares a default role in the default registry
+func DeclareRole(name string, permissions ...string) {
+	Default.DeclareRole(name, permissions...)
+}
+
+// DeclareSystemRole declares a system role in the default registry
+func DeclareSystemRole(name string, permissions ...string) {
+	Default.DeclareSystemRole(name, permissions...)
+}
+
+// Declare declares the permissions of a module.
+// Declaring the same permission again is a no-op, it panics when another module already declares the name.
+func (r *Registry) Declare(group string, permissions ...Permission) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range permissions {
+		if p.Name == "" {
+			panic(fmt.Sprintf("permission: module %s declares a permission without a name", group))
+		}
+
+		if declared, ok := r.permissions[p.Name]; ok && declared.Group != group {
+			panic(fmt.Sprintf("permission: %s is declared by both %s and %s", p.Name, declared.Group, group))
+		}
+
+		r.permissions[p.Name] = &Permission{Name: p.Name, Label: p.Label, Group: group}
+	}
+}
+
+// DeclareRole declares a default role granting the permissions.
+// Roles declared by several modules grant the permissions of every declaration.
+func (r *Registry) DeclareRole(name string, permissions ...string) {
+	r.declareRole(name, false, permissions)
+}
+
+// DeclareSystemRole declares a system role granting the permissions as its core permissions.
+// A role is a system role as soon as one of its declarations is.
+func (r *Registry) DeclareSystemRole(name string, permissions ...string) {
+	r.declareRole(name, true, permissions)
+}
+
+func (r *Registry) declareRole(name string, system bool, permissions []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	role, ok := r.roles[name]
+	if !ok {
+		role = &Role{Name: name}
+		r.roles[name] = role
+	}
+
+	role.System = role.System || system
+
+	for _, p := range permissions {
+		if !contains(role.Permissions, p) {
+			role.Permissions = append(role.Permissions, p)
+		}
+	}
+}
+
+// Has tells whether the permission is declared
+func (r *Registry) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.permissions[name]
+	return ok
+}
+
+// Permissions returns the declared permissions sorted by group and name
+func (r *Registry) Permissions() []*Permission {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	permissions := make([]*Permission, 0, len(r.permissions))
+	for _, p := range r.permissions {
+		permissions = append(permissions, p)
+	}
+
+	sort.Slice(permissions, func(i, j int) bool {
+		if permissions[i].Group != permissions[j].Group {
+			return permissions[i].Group < permissions[j].Group
+		}
+
+		return permissions[i].Name < permissions[j].Name
+	})
+
+	return permissions
+}
+
+// Groups returns the declared permissions grouped by module, sorted by group and name
+func (r *Registry) Groups() []*Group {
+	groups := make([]*Group, 0)
+
+	for _, p := range r.Permissions() {
+		if len(groups) == 0 || groups[len(groups)-1].Name != p.Group {
+			groups = append(groups, &Group{Name: p.Group})
+		}
+
+		last := groups[len(groups)-1]
+		last.Permissions = append(last.Permissions, p)
+	}
+
+	return groups
+}
+
+// Roles returns the declared default roles sorted by name, their permissions sorted by name
+func (r *Registry) Roles() []*Role {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	roles := make([]*Role, 0, len(r.roles))
+	for _, role := range r.roles {
+		permissions := append([]string(nil), role.Permissions...)
+		sort.Strings(permissions)
+		roles = append(roles, &Role{Name: role.Name, Permissions: permissions, System: role.System})
+	}
+
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Name < roles[j].Name
+	})
+
+	return roles
+}
+
+// Validate checks that the default roles only grant declared permissions
+func (r *Registry) Validate() error {
+	for _, role := range r.Roles() {
+		for _, p := range role.Permissions {
+			if !r.Has(p) {
+				return fmt.Errorf("permission: role %s grants %s which no module declares", role.Name, p)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Names returns the names of the permissions
+func Names(permissions ...Permission) []string {
+	names := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		names = append(names, p.Name)
+	}
+
+	return names
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,88 @@
+package permission_test
+
+import (
+	"testing"
+
+	"gin-starter/common/permission"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RegistryTestSuite struct {
+	suite.Suite
+	registry *permission.Registry
+}
+
+func TestRegistryTestSuite(t *testing.T) {
+	suite.Run(t, new(RegistryTestSuite))
+}
+
+func (suite *RegistryTestSuite) SetupTest() {
+	suite.registry = permission.NewRegistry()
+	suite.registry.Declare("user",
+		permission.Permission{Name: "user.view", Label: "View users"},
+		permission.Permission{Name: "role.view", Label: "View roles"},
+	)
+	suite.registry.Declare("notification",
+		permission.Permission{Name: "notification.create", Label: "Send notifications"},
+	)
+}
+
+func (suite *RegistryTestSuite) TestGroups() {
+	suite.Run("permissions are grouped by module in a stable order", func() {
+		groups := suite.registry.Groups()
+
+		suite.Require().Len(groups, 2)
+		suite.Equal("notification", groups[0].Name)
+		suite.Equal("user", groups[1].Name)
+		suite.Equal("role.view", groups[1].Permissions[0].Name)
+		suite.Equal("user", groups[1].Permissions[0].Group)
+		suite.Equal("user.view", groups[1].Permissions[1].Name)
+	})
+}
+
+func (suite *RegistryTestSuite) TestDeclare() {
+	suite.Run("declaring a permission again keeps a single entry", func() {
+		suite.registry.Declare("user", permission.Permission{Name: "user.view", Label: "See users"})
+
+		suite.Len(suite.registry.Permissions(), 3)
+		suite.Equal("See users", suite.registry.Permissions()[2].Label)
+	})
+
+	suite.Run("panic when another module declares the same name", func() {
+		suite.Panics(func() {
+			suite.registry.Declare("master", permission.Permission{Name: "user.view"})
+		})
+	})
+}
+
+func (suite *RegistryTestSuite) TestRoles() {
+	suite.Run("declarations of a role are merged", func() {
+		suite.registry.DeclareRole("Super Admin", "user.view", "role.view")
+		suite.registry.DeclareRole("Super Admin", "notification.create", "user.view")
+
+		roles := suite.registry.Roles()
+
+		suite.Require().Len(roles, 1)
+		suite.Equal([]string{"notification.create", "role.view", "user.view"}, roles[0].Permissions)
+		suite.NoError(suite.registry.Validate())
+	})
+
+	suite.Run("a role declared as a system role once stays a system role", func() {
+		suite.registry.DeclareSystemRole("Auditor", "user.view")
+		suite.registry.DeclareRole("Auditor", "role.view")
+
+		roles := suite.registry.Roles()
+
+		suite.Require().Len(roles, 2)
+		suite.True(roles[0].System)
+		suite.Equal([]string{"role.view", "user.view"}, roles[0].Permissions)
+		suite.False(roles[1].System)
+	})
+
+	suite.Run("fail when a role grants an undeclared permission", func() {
+		suite.registry.DeclareRole("Editor", "article.publish")
+
+		suite.Error(suite.registry.Validate())
+	})
+}
@@ -0,0 +1,204 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gin-starter/common/errors"
+)
+
+const (
+	// TotalExact counts the matching rows, the default of offset pagination
+	TotalExact = "exact"
+	// TotalEstimated reads the row estimate of the table from pg_class, ignoring the filters
+	TotalEstimated = "estimated"
+	// TotalNone skips the count, the default of cursor pagination
+	TotalNone = "none"
+)
+
+// Keyset declares the columns cursor pagination orders on.
+// Together they must be unique so that every row has a stable position.
+type Keyset struct {
+	CreatedAt string
+	ID        string
+}
+
+// Cursor is the position of a row in a keyset ordered list.
+// It is handed to clients base64 encoded so they treat it as opaque.
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"i"`
+	// Backward is set on cursors pointing to the previous page
+	Backward bool `json:"b,omitempty"`
+}
+
+// Page is the pagination of a list result
+type Page struct {
+	// Total is the number of matching rows, nil when the count was skipped
+	Total *int64
+	// Estimated is set when Total is an estimate of the table size
+	Estimated bool
+	// Keyset is set when the list was paginated by cursor
+	Keyset     bool
+	Limit      int
+	Offset     int
+	NextCursor string
+	PrevCursor string
+}
+
+// EncodeCursor encodes a cursor into an opaque string
+func EncodeCursor(c *Cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor decodes a cursor encoded by EncodeCursor
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.ErrInvalidCursor.Error()
+	}
+
+	c := new(Cursor)
+	if err := json.Unmarshal(b, c); err != nil || c.ID == "" || c.CreatedAt.IsZero() {
+		return nil, errors.ErrInvalidCursor.Error()
+	}
+
+	return c, nil
+}
+
+// keysetDesc reports whether the keyset is walked from the newest row, only created_at may set the direction
+func (q *Query) keysetDesc() bool {
+	if len(q.Sorts) == 1 {
+		return q.Sorts[0].Desc
+	}
+
+	return true
+}
+
+// keysetOrder orders by the keyset columns, reversed when walking backward from a cursor
+func (q *Query) keysetOrder(db *gorm.DB, keyset *Keyset) *gorm.DB {
+	desc := q.keysetDesc()
+	if q.Cursor != nil && q.Cursor.Backward {
+		desc = !desc
+	}
+
+	dir := " asc"
+	if desc {
+		dir = " desc"
+	}
+
+	return db.Order(keyset.CreatedAt + dir).Order(keyset.ID + dir)
+}
+
+// keysetPaginate seeks past the cursor and fetches one row more than the limit to tell whether another page follows
+func (q *Query) keysetPaginate(db *gorm.DB, keyset *Keyset) *gorm.DB {
+	if q.Cursor != nil {
+		op := "<"
+		if q.keysetDesc() == q.Cursor.Backward {
+			op = ">"
+		}
+
+		db = db.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", keyset.CreatedAt, keyset.ID, op), q.Cursor.CreatedAt, q.Cursor.ID)
+	}
+
+	return db.Limit(q.Limit + 1)
+}
+
+// Count counts the rows matching db according to the total mode of the query,
+// reporting whether the total is an estimate of the table size.
+func (q *Query) Count(db *gorm.DB, schema *Schema) (*int64, bool, error) {
+	mode := TotalExact
+	if q != nil && q.Total != "" {
+		mode = q.Total
+	} else if q != nil && q.Keyset {
+		mode = TotalNone
+	}
+
+	var total int64
+
+	switch mode {
+	case TotalNone:
+		return nil, false, nil
+	case TotalEstimated:
+		if schema.Table != "" {
+			if err := db.
+				Session(&gorm.Session{NewDB: true}).
+				Raw("SELECT COALESCE(reltuples, -1)::bigint FROM pg_class WHERE oid = to_regclass(?)", schema.Table).
+				Scan(&total).
+				Error; err != nil {
+				return nil, false, err
+			}
+
+			// tables never analysed report -1, count them instead
+			if total >= 0 {
+				return &total, true, nil
+			}
+		}
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, false, err
+	}
+
+	return &total, false, nil
+}
+
+// Page trims the row fetched past the limit, restores the order of a backward page and builds the cursors.
+// items must point to the fetched slice, which is resliced in place,
+// and key returns the keyset values of its i-th element.
+func (q *Query) Page(items interface{}, total *int64, estimated bool, key func(i int) (time.Time, string)) *Page {
+	page := &Page{Total: total, Estimated: estimated}
+	if q == nil {
+		return page
+	}
+
+	page.Limit = q.Limit
+	page.Offset = q.Offset
+
+	if !q.Keyset {
+		return page
+	}
+
+	page.Keyset = true
+	page.Offset = 0
+
+	slice := reflect.ValueOf(items).Elem()
+	n := slice.Len()
+
+	hasMore := n > q.Limit
+	if hasMore {
+		n = q.Limit
+		slice.Set(slice.Slice(0, n))
+	}
+
+	backward := q.Cursor != nil && q.Cursor.Backward
+	if backward {
+		swap := reflect.Swapper(slice.Interface())
+		for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+
+	if n == 0 {
+		return page
+	}
+
+	first, firstID := key(0)
+	last, lastID := key(n - 1)
+
+	if hasMore || backward {
+		page.NextCursor = EncodeCursor(&Cursor{CreatedAt: last, ID: lastID})
+	}
+
+	if (hasMore && backward) || (!backward && q.Cursor != nil) {
+		page.PrevCursor = EncodeCursor(&Cursor{CreatedAt: first, ID: firstID, Backward: true})
+	}
+
+	return page
+}
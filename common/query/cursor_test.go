@@ -0,0 +1,119 @@
+package query_test
+
+import (
+	"net/url"
+	"time"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/query"
+)
+
+type row struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+func rows(ids ...string) []*row {
+	base := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	res := make([]*row, 0, len(ids))
+
+	for i, id := range ids {
+		res = append(res, &row{CreatedAt: base.Add(-time.Duration(i) * time.Minute), ID: id})
+	}
+
+	return res
+}
+
+func (suite *QueryTestSuite) TestParseCursor() {
+	cursor := &query.Cursor{CreatedAt: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC), ID: "b"}
+
+	suite.Run("successfully parse the first page of a cursor list", func() {
+		q, err := query.Parse(url.Values{"cursor": {""}, "offset": {"20"}})
+
+		suite.Nil(err)
+		suite.True(q.Keyset)
+		suite.Nil(q.Cursor)
+		suite.Zero(q.Offset)
+	})
+
+	suite.Run("successfully parse an encoded cursor", func() {
+		q, err := query.Parse(url.Values{"cursor": {query.EncodeCursor(cursor)}})
+
+		suite.Nil(err)
+		suite.Equal(cursor.ID, q.Cursor.ID)
+		suite.True(cursor.CreatedAt.Equal(q.Cursor.CreatedAt))
+	})
+
+	suite.Run("fail to parse a tampered cursor", func() {
+		_, err := query.Parse(url.Values{"cursor": {"not-a-cursor"}})
+		suite.Equal(errors.ErrInvalidCursor.Error(), err)
+	})
+
+	suite.Run("fail to sort a cursor list by another field", func() {
+		q := &query.Query{Keyset: true, Sorts: []query.Sort{{Field: "name"}}}
+		suite.Equal(errors.ErrInvalidSort.Error(), q.Validate(suite.schema))
+	})
+}
+
+func (suite *QueryTestSuite) TestKeysetSQL() {
+	cursor := &query.Cursor{CreatedAt: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC), ID: "b"}
+
+	suite.Run("seek past the cursor of the next page", func() {
+		sql := suite.toSQL(&query.Query{Keyset: true, Cursor: cursor, Limit: 2})
+		suite.Equal(`SELECT * FROM "items" WHERE items.owner_id = $1 AND (items.created_at, items.id) < ($2, $3) ORDER BY items.created_at desc,items.id desc LIMIT 3`, sql)
+	})
+
+	suite.Run("seek before the cursor of the previous page", func() {
+		backward := *cursor
+		backward.Backward = true
+
+		sql := suite.toSQL(&query.Query{Keyset: true, Cursor: &backward, Limit: 2})
+		suite.Equal(`SELECT * FROM "items" WHERE items.owner_id = $1 AND (items.created_at, items.id) > ($2, $3) ORDER BY items.created_at asc,items.id asc LIMIT 3`, sql)
+	})
+}
+
+func (suite *QueryTestSuite) TestPage() {
+	key := func(items *[]*row) func(int) (time.Time, string) {
+		return func(i int) (time.Time, string) {
+			return (*items)[i].CreatedAt, (*items)[i].ID
+		}
+	}
+
+	suite.Run("trim the extra row of the first page and point to the next one", func() {
+		items := rows("a", "b", "c")
+		page := (&query.Query{Keyset: true, Limit: 2}).Page(&items, nil, false, key(&items))
+
+		suite.Len(items, 2)
+		suite.Empty(page.PrevCursor)
+
+		next, err := query.DecodeCursor(page.NextCursor)
+		suite.Nil(err)
+		suite.Equal("b", next.ID)
+		suite.False(next.Backward)
+	})
+
+	suite.Run("restore the order of a previous page", func() {
+		// a previous page is fetched in ascending order, nearest to the cursor first
+		newest := rows("a", "b", "c")
+		items := []*row{newest[2], newest[1], newest[0]}
+		q := &query.Query{Keyset: true, Limit: 2, Cursor: &query.Cursor{ID: "d", Backward: true}}
+		page := q.Page(&items, nil, false, key(&items))
+
+		suite.Equal([]string{"b", "c"}, []string{items[0].ID, items[1].ID})
+
+		prev, err := query.DecodeCursor(page.PrevCursor)
+		suite.Nil(err)
+		suite.Equal("b", prev.ID)
+		suite.True(prev.Backward)
+		suite.NotEmpty(page.NextCursor)
+	})
+
+	suite.Run("stop at the last page", func() {
+		items := rows("a")
+		q := &query.Query{Keyset: true, Limit: 2, Cursor: &query.Cursor{ID: "z"}}
+		page := q.Page(&items, nil, false, key(&items))
+
+		suite.Empty(page.NextCursor)
+		suite.NotEmpty(page.PrevCursor)
+	})
+}
@@ -0,0 +1,361 @@
+// Package query parses the filter, sort, search and pagination parameters of list endpoints
+// and applies them to GORM against a whitelist of columns declared per resource.
+package query
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"gin-starter/common/constant"
+	"gin-starter/common/errors"
+)
+
+const (
+	// OpEqual matches values equal to the filter value
+	OpEqual = "eq"
+	// OpNotEqual matches values not equal to the filter value
+	OpNotEqual = "ne"
+	// OpGreaterThan matches values greater than the filter value
+	OpGreaterThan = "gt"
+	// OpGreaterThanOrEqual matches values greater than or equal to the filter value
+	OpGreaterThanOrEqual = "gte"
+	// OpLessThan matches values less than the filter value
+	OpLessThan = "lt"
+	// OpLessThanOrEqual matches values less than or equal to the filter value
+	OpLessThanOrEqual = "lte"
+	// OpLike matches values containing the filter value, ignoring case
+	OpLike = "like"
+	// OpIn matches values equal to one of the comma separated filter values
+	OpIn = "in"
+	// OpNull matches null values when the filter value is true and non-null values when it is false
+	OpNull = "null"
+
+	// DefaultLimit is the page size used when the limit parameter is missing
+	DefaultLimit = 10
+	// MaxLimit is the largest page size a client may request
+	MaxLimit = constant.Hundred
+)
+
+var (
+	filterKey = regexp.MustCompile(`^filter\[([a-z0-9_]+)\](?:\[([a-z]+)\])?$`)
+
+	comparisons = map[string]string{
+		OpEqual:              "=",
+		OpNotEqual:           "<>",
+		OpGreaterThan:        ">",
+		OpGreaterThanOrEqual: ">=",
+		OpLessThan:           "<",
+		OpLessThanOrEqual:    "<=",
+	}
+)
+
+// Schema declares the fields of a list resource a client may filter, sort and search on.
+// Client supplied names are only ever looked up in Fields, so they never reach the SQL.
+type Schema struct {
+	// Fields maps the field names of the API to their column
+	Fields map[string]string
+	// Search lists the columns matched by the search term
+	Search []string
+	// DefaultSort is the sort applied when the client asks for none, e.g. "-created_at"
+	DefaultSort string
+	// Keyset enables cursor pagination, lists without it only support offset pagination
+	Keyset *Keyset
+	// Table is the table whose row estimate is read for estimated totals
+	Table string
+}
+
+// Filter is a condition on a field
+type Filter struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// Sort is an ordering on a field
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Query holds the parsed parameters of a list request
+type Query struct {
+	Search  string
+	Filters []Filter
+	Sorts   []Sort
+	Limit   int
+	Offset  int
+	// Keyset is set when the client paginates by cursor, Cursor is nil on the first page
+	Keyset bool
+	Cursor *Cursor
+	// Total is the total mode, empty for the default of the pagination mode
+	Total string
+}
+
+// Parse parses the list parameters of a request:
+//
+//	query=john                          search term
+//	filter[status][eq]=ACTIVATED        filter, the operator defaults to eq
+//	sort=-created_at,name               sort fields, descending when prefixed with -
+//	order=desc                          direction of the sort fields without a prefix
+//	limit=10&offset=0                   offset pagination
+//	cursor=&limit=10                    cursor pagination, the cursor is empty on the first page
+//	total=estimated                     total mode, one of exact, estimated or none
+func Parse(values url.Values) (*Query, error) {
+	q := &Query{
+		Search: strings.TrimSpace(values.Get("query")),
+		Limit:  DefaultLimit,
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if strings.HasPrefix(key, "filter") {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		vals := values[key]
+
+		match := filterKey.FindStringSubmatch(key)
+		if match == nil {
+			return nil, errors.ErrInvalidFilter.Error()
+		}
+
+		op := match[2]
+		if op == "" {
+			op = OpEqual
+		}
+
+		if _, ok := comparisons[op]; !ok && op != OpLike && op != OpIn && op != OpNull {
+			return nil, errors.ErrInvalidFilter.Error()
+		}
+
+		for _, v := range vals {
+			if op == OpNull && v != "true" && v != "false" {
+				return nil, errors.ErrInvalidFilter.Error()
+			}
+
+			q.Filters = append(q.Filters, Filter{Field: match[1], Op: op, Value: v})
+		}
+	}
+
+	order := strings.ToLower(values.Get("order"))
+	if order != "" && order != constant.Ascending && order != constant.Descending {
+		return nil, errors.ErrInvalidSort.Error()
+	}
+
+	if sorts := values.Get("sort"); sorts != "" {
+		for _, field := range strings.Split(sorts, ",") {
+			field = strings.TrimSpace(field)
+
+			s := Sort{Field: strings.TrimPrefix(field, "-"), Desc: order == constant.Descending}
+			if strings.HasPrefix(field, "-") {
+				s.Desc = true
+			}
+
+			if s.Field == "" {
+				return nil, errors.ErrInvalidSort.Error()
+			}
+
+			q.Sorts = append(q.Sorts, s)
+		}
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l <= 0 {
+			return nil, errors.ErrInvalidArgument.Error()
+		}
+
+		if l > MaxLimit {
+			l = MaxLimit
+		}
+
+		q.Limit = l
+	}
+
+	if offset := values.Get("offset"); offset != "" {
+		o, err := strconv.Atoi(offset)
+		if err != nil || o < 0 {
+			return nil, errors.ErrInvalidArgument.Error()
+		}
+
+		q.Offset = o
+	}
+
+	if _, ok := values["cursor"]; ok {
+		q.Keyset = true
+		q.Offset = 0
+
+		if cursor := values.Get("cursor"); cursor != "" {
+			c, err := DecodeCursor(cursor)
+			if err != nil {
+				return nil, err
+			}
+
+			q.Cursor = c
+		}
+	}
+
+	if total := values.Get("total"); total != "" {
+		if total != TotalExact && total != TotalEstimated && total != TotalNone {
+			return nil, errors.ErrInvalidArgument.Error()
+		}
+
+		q.Total = total
+	}
+
+	return q, nil
+}
+
+// Validate checks that every filtered and sorted field is declared in the schema
+func (q *Query) Validate(schema *Schema) error {
+	if q == nil {
+		return nil
+	}
+
+	for _, f := range q.Filters {
+		if _, ok := schema.Fields[f.Field]; !ok {
+			return errors.ErrInvalidFilter.Error()
+		}
+	}
+
+	for _, s := range q.Sorts {
+		if _, ok := schema.Fields[s.Field]; !ok {
+			return errors.ErrInvalidSort.Error()
+		}
+	}
+
+	if q.Keyset {
+		if schema.Keyset == nil {
+			return errors.ErrInvalidCursor.Error()
+		}
+
+		// a cursor only holds the keyset, so the list can only be sorted by it
+		if len(q.Sorts) > 1 || (len(q.Sorts) == 1 && q.Sorts[0].Field != "created_at") {
+			return errors.ErrInvalidSort.Error()
+		}
+	}
+
+	return nil
+}
+
+// Filter returns a scope applying the search term and the filters, each as its own grouped condition
+// so that they never escape the conditions of the base query.
+// Fields missing from the schema are skipped, Validate reports them to the client.
+func (q *Query) Filter(schema *Schema) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if q == nil {
+			return db
+		}
+
+		if q.Search != "" && len(schema.Search) > 0 {
+			conditions := make([]clause.Expression, 0, len(schema.Search))
+
+			for _, column := range schema.Search {
+				conditions = append(conditions, clause.Expr{SQL: column + " ILIKE ?", Vars: []interface{}{"%" + escapeLike(q.Search) + "%"}})
+			}
+
+			db = db.Where(clause.Or(conditions...))
+		}
+
+		for _, f := range q.Filters {
+			column, ok := schema.Fields[f.Field]
+			if !ok {
+				continue
+			}
+
+			switch f.Op {
+			case OpLike:
+				db = db.Where(column+" ILIKE ?", "%"+escapeLike(f.Value)+"%")
+			case OpIn:
+				db = db.Where(column+" IN ?", strings.Split(f.Value, ","))
+			case OpNull:
+				if f.Value == "true" {
+					db = db.Where(column + " IS NULL")
+				} else {
+					db = db.Where(column + " IS NOT NULL")
+				}
+			default:
+				if op, ok := comparisons[f.Op]; ok {
+					db = db.Where(column+" "+op+" ?", f.Value)
+				}
+			}
+		}
+
+		return db
+	}
+}
+
+// Order returns a scope applying the sorts, or the default sort of the schema when there are none.
+// Cursor pagination orders by the keyset of the schema instead.
+func (q *Query) Order(schema *Schema) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if q != nil && q.Keyset && schema.Keyset != nil {
+			return q.keysetOrder(db, schema.Keyset)
+		}
+
+		sorts := make([]Sort, 0)
+		if q != nil {
+			sorts = q.Sorts
+		}
+
+		if len(sorts) == 0 && schema.DefaultSort != "" {
+			sorts = []Sort{{
+				Field: strings.TrimPrefix(schema.DefaultSort, "-"),
+				Desc:  strings.HasPrefix(schema.DefaultSort, "-"),
+			}}
+		}
+
+		for _, s := range sorts {
+			column, ok := schema.Fields[s.Field]
+			if !ok {
+				continue
+			}
+
+			if s.Desc {
+				db = db.Order(column + " desc")
+			} else {
+				db = db.Order(column + " asc")
+			}
+		}
+
+		return db
+	}
+}
+
+// Paginate returns a scope applying the limit and offset, or seeking past the cursor, a nil query fetches every row
+func (q *Query) Paginate(schema *Schema) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if q == nil {
+			return db
+		}
+
+		if q.Keyset && schema.Keyset != nil {
+			return q.keysetPaginate(db, schema.Keyset)
+		}
+
+		if q.Limit > 0 {
+			db = db.Limit(q.Limit)
+		}
+
+		if q.Offset > 0 {
+			db = db.Offset(q.Offset)
+		}
+
+		return db
+	}
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
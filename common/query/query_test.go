@@ -0,0 +1,136 @@
+package query_test
+
+import (
+	"net/url"
+	"testing"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/query"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type item struct {
+	ID   int
+	Name string
+}
+
+type QueryTestSuite struct {
+	suite.Suite
+	db     *gorm.DB
+	schema *query.Schema
+}
+
+func TestQueryTestSuite(t *testing.T) {
+	suite.Run(t, new(QueryTestSuite))
+}
+
+func (suite *QueryTestSuite) SetupTest() {
+	conn, _, err := sqlmock.New()
+	suite.Require().NoError(err)
+
+	suite.db, err = gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{DryRun: true})
+	suite.Require().NoError(err)
+
+	suite.schema = &query.Schema{
+		Fields: map[string]string{
+			"name":       "items.name",
+			"status":     "items.status",
+			"created_at": "items.created_at",
+		},
+		Search:      []string{"items.name", "items.email"},
+		DefaultSort: "-created_at",
+		Keyset:      &query.Keyset{CreatedAt: "items.created_at", ID: "items.id"},
+	}
+}
+
+func (suite *QueryTestSuite) toSQL(q *query.Query) string {
+	stmt := suite.db.
+		Model(&item{}).
+		Where("items.owner_id = ?", 1).
+		Scopes(q.Filter(suite.schema), q.Order(suite.schema), q.Paginate(suite.schema)).
+		Find(&[]item{}).
+		Statement
+
+	return stmt.SQL.String()
+}
+
+func (suite *QueryTestSuite) TestParse() {
+	suite.Run("successfully parse filters, sorts, search and pagination", func() {
+		q, err := query.Parse(url.Values{
+			"query":                  {"john"},
+			"filter[status]":         {"ACTIVATED"},
+			"filter[name][like]":     {"jo"},
+			"filter[created_at][in]": {"a,b"},
+			"sort":                   {"-created_at,name"},
+			"limit":                  {"500"},
+			"offset":                 {"20"},
+		})
+
+		suite.Nil(err)
+		suite.Equal("john", q.Search)
+		suite.Equal([]query.Filter{
+			{Field: "created_at", Op: query.OpIn, Value: "a,b"},
+			{Field: "name", Op: query.OpLike, Value: "jo"},
+			{Field: "status", Op: query.OpEqual, Value: "ACTIVATED"},
+		}, q.Filters)
+		suite.Equal([]query.Sort{{Field: "created_at", Desc: true}, {Field: "name"}}, q.Sorts)
+		suite.Equal(query.MaxLimit, q.Limit)
+		suite.Equal(20, q.Offset)
+	})
+
+	suite.Run("successfully parse the legacy sort and order parameters", func() {
+		q, err := query.Parse(url.Values{"sort": {"name"}, "order": {"desc"}})
+
+		suite.Nil(err)
+		suite.Equal([]query.Sort{{Field: "name", Desc: true}}, q.Sorts)
+		suite.Equal(query.DefaultLimit, q.Limit)
+	})
+
+	suite.Run("fail to parse an unknown operator", func() {
+		_, err := query.Parse(url.Values{"filter[name][regex]": {"x"}})
+		suite.Equal(errors.ErrInvalidFilter.Error(), err)
+	})
+
+	suite.Run("fail to parse an invalid order", func() {
+		_, err := query.Parse(url.Values{"sort": {"name"}, "order": {"; drop table users"}})
+		suite.Equal(errors.ErrInvalidSort.Error(), err)
+	})
+}
+
+func (suite *QueryTestSuite) TestValidate() {
+	suite.Nil((&query.Query{Sorts: []query.Sort{{Field: "name"}}}).Validate(suite.schema))
+	suite.Equal(errors.ErrInvalidSort.Error(), (&query.Query{Sorts: []query.Sort{{Field: "name; drop table users"}}}).Validate(suite.schema))
+	suite.Equal(errors.ErrInvalidFilter.Error(), (&query.Query{Filters: []query.Filter{{Field: "password", Op: query.OpEqual}}}).Validate(suite.schema))
+}
+
+func (suite *QueryTestSuite) TestApply() {
+	suite.Run("group the search so it cannot escape the base condition", func() {
+		sql := suite.toSQL(&query.Query{
+			Search:  "john",
+			Filters: []query.Filter{{Field: "status", Op: query.OpEqual, Value: "ACTIVATED"}},
+			Sorts:   []query.Sort{{Field: "name"}},
+			Limit:   10,
+			Offset:  10,
+		})
+
+		suite.Equal(`SELECT * FROM "items" WHERE items.owner_id = $1 AND (items.name ILIKE $2 OR items.email ILIKE $3) AND items.status = $4 ORDER BY items.name asc LIMIT 10 OFFSET 10`, sql)
+	})
+
+	suite.Run("skip fields missing from the schema", func() {
+		sql := suite.toSQL(&query.Query{
+			Filters: []query.Filter{{Field: "1=1) OR (1", Op: query.OpEqual, Value: "x"}},
+			Sorts:   []query.Sort{{Field: "name; drop table users"}},
+		})
+
+		suite.Equal(`SELECT * FROM "items" WHERE items.owner_id = $1`, sql)
+	})
+
+	suite.Run("apply the default sort without pagination for a nil query", func() {
+		var q *query.Query
+		suite.Equal(`SELECT * FROM "items" WHERE items.owner_id = $1 ORDER BY items.created_at desc`, suite.toSQL(q))
+	})
+}
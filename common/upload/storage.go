@@ -0,0 +1,65 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime/multipart"
+	"path"
+
+	"gin-starter/common/interfaces"
+)
+
+// Storage enforces the upload policies in front of a cloud storage.
+// Only Upload receives client files, the other methods store content generated by the server and are passed through.
+type Storage struct {
+	guard *Guard
+	next  interfaces.CloudStorageUseCase
+}
+
+// NewStorage is a constructor for Storage
+func NewStorage(guard *Guard, next interfaces.CloudStorageUseCase) *Storage {
+	return &Storage{
+		guard: guard,
+		next:  next,
+	}
+}
+
+// Upload checks the file against the policy of the folder and stores it as <folder>/<sha256>.<sniffed extension>
+func (s *Storage) Upload(f *multipart.FileHeader, folder string) (string, error) {
+	file, err := s.guard.Check(context.Background(), f, folder)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(file.Data)
+	fileStored := path.Join("/", folder, hex.EncodeToString(sum[:])+"."+file.Extension)
+
+	if err := s.next.UploadObject(bytes.NewReader(file.Data), fileStored, file.ContentType); err != nil {
+		return "", err
+	}
+
+	return fileStored, nil
+}
+
+// UploadSavedFile uploads a file generated by the server
+func (s *Storage) UploadSavedFile(filepath, folder string) (string, error) {
+	return s.next.UploadSavedFile(filepath, folder)
+}
+
+// UploadObject uploads content generated by the server
+func (s *Storage) UploadObject(r io.Reader, path, contentType string) error {
+	return s.next.UploadObject(r, path, contentType)
+}
+
+// Delete deletes a file
+func (s *Storage) Delete(path string) error {
+	return s.next.Delete(path)
+}
+
+// Download opens a file
+func (s *Storage) Download(path string) (io.ReadCloser, error) {
+	return s.next.Download(path)
+}
@@ -0,0 +1,203 @@
+// Package upload enforces the upload policy of a folder on client uploads before they reach the cloud storage:
+// the type is sniffed from the content, the size and the image dimensions are limited
+// and the content is handed to the configured scanner.
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	// registers the decoders read for the dimensions of the allowed image types
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	// registers the webp decoder
+	_ "golang.org/x/image/webp"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/interfaces"
+	"gin-starter/config"
+	"gin-starter/sdk/clamav"
+)
+
+const (
+	// ScannerNone accepts uploads without scanning them
+	ScannerNone = "none"
+	// ScannerClamAV scans uploads with a ClamAV daemon
+	ScannerClamAV = "clamav"
+)
+
+// ImageTypes are the image types the photo pipeline decodes
+var ImageTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// extensions maps the sniffed types to the extension the files are stored with
+var extensions = map[string]string{
+	"image/jpeg":      "jpg",
+	"image/png":       "png",
+	"image/gif":       "gif",
+	"image/webp":      "webp",
+	"application/pdf": "pdf",
+	"application/zip": "zip",
+	"text/plain":      "txt",
+}
+
+// Policy restricts the files uploaded to a folder
+type Policy struct {
+	// Types lists the allowed types, sniffed from the content rather than taken from the file name
+	Types []string
+	// MaxSize is the largest size in bytes
+	MaxSize int64
+	// MaxWidth and MaxHeight limit the dimensions of images, zero disables the limit
+	MaxWidth  int
+	MaxHeight int
+}
+
+// Policies returns the policy of every folder accepting client uploads, uploads to other folders are rejected
+func Policies(cfg config.Config) map[string]*Policy {
+	photo := &Policy{
+		Types:     ImageTypes,
+		MaxSize:   cfg.Upload.MaxImageSize,
+		MaxWidth:  cfg.Upload.MaxImageWidth,
+		MaxHeight: cfg.Upload.MaxImageHeight,
+	}
+
+	return map[string]*Policy{
+		"users/user/profile":  photo,
+		"users/admin/profile": photo,
+	}
+}
+
+// NewScanner returns the content scanner of the configuration, nil when uploads are not scanned
+func NewScanner(cfg config.Config) (interfaces.ContentScanner, error) {
+	switch cfg.Upload.Scanner {
+	case "", ScannerNone:
+		return nil, nil
+	case ScannerClamAV:
+		client, err := clamav.NewClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return client, nil
+	default:
+		return nil, fmt.Errorf("[Upload-NewScanner] unknown scanner %q", cfg.Upload.Scanner)
+	}
+}
+
+// File is an upload accepted by the guard
+type File struct {
+	Data        []byte
+	ContentType string
+	// Extension is derived from the sniffed type, never from the client file name
+	Extension string
+}
+
+// Guard enforces the upload policies and scans the accepted files
+type Guard struct {
+	policies map[string]*Policy
+	scanner  interfaces.ContentScanner
+}
+
+// NewGuard is a constructor for Guard, a nil scanner skips scanning
+func NewGuard(policies map[string]*Policy, scanner interfaces.ContentScanner) *Guard {
+	return &Guard{
+		policies: policies,
+		scanner:  scanner,
+	}
+}
+
+// Check reads the uploaded file and checks it against the policy of the folder
+func (g *Guard) Check(ctx context.Context, f *multipart.FileHeader, folder string) (*File, error) {
+	policy, ok := g.policies[folder]
+	if !ok {
+		return nil, errors.ErrUploadFolderNotAllowed.Error()
+	}
+
+	// the header size is reported by the client, the read below enforces the limit on the content itself
+	if policy.MaxSize > 0 && f.Size > policy.MaxSize {
+		return nil, errors.ErrUploadTooLarge.Error()
+	}
+
+	src, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := src.Close(); err != nil {
+			log.Println("[UploadGuard-Check]", err)
+		}
+	}()
+
+	return g.check(ctx, src, policy)
+}
+
+func (g *Guard) check(ctx context.Context, r io.Reader, policy *Policy) (*File, error) {
+	if policy.MaxSize > 0 {
+		r = io.LimitReader(r, policy.MaxSize+1)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy.MaxSize > 0 && int64(len(data)) > policy.MaxSize {
+		return nil, errors.ErrUploadTooLarge.Error()
+	}
+
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil || !allowed(policy.Types, contentType) {
+		return nil, errors.ErrUploadTypeNotAllowed.Error()
+	}
+
+	if strings.HasPrefix(contentType, "image/") && (policy.MaxWidth > 0 || policy.MaxHeight > 0) {
+		conf, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, errors.ErrInvalidImage.Error()
+		}
+
+		if (policy.MaxWidth > 0 && conf.Width > policy.MaxWidth) || (policy.MaxHeight > 0 && conf.Height > policy.MaxHeight) {
+			return nil, errors.ErrImageTooLarge.Error()
+		}
+	}
+
+	if g.scanner != nil {
+		signature, err := g.scanner.Scan(ctx, bytes.NewReader(data))
+		if err != nil {
+			log.Println("[UploadGuard-Check]", err)
+			return nil, errors.ErrScannerUnavailable.Error()
+		}
+
+		if signature != "" {
+			log.Println("[UploadGuard-Check] rejected upload infected with", signature)
+			return nil, errors.ErrUploadInfected.Error()
+		}
+	}
+
+	extension, ok := extensions[contentType]
+	if !ok {
+		extension = "bin"
+	}
+
+	return &File{Data: data, ContentType: contentType, Extension: extension}, nil
+}
+
+func allowed(types []string, contentType string) bool {
+	for _, t := range types {
+		if t == contentType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,140 @@
+package upload_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"testing"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/upload"
+	mockInterfaces "gin-starter/test/mock/common/interfaces"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+)
+
+const folder = "users/user/profile"
+
+type UploadTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	scanner      *mockInterfaces.MockContentScanner
+	cloudStorage *mockInterfaces.MockCloudStorageUseCase
+	guard        *upload.Guard
+	storage      *upload.Storage
+}
+
+func TestUploadTestSuite(t *testing.T) {
+	suite.Run(t, new(UploadTestSuite))
+}
+
+func (suite *UploadTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.scanner = mockInterfaces.NewMockContentScanner(suite.mockCtrl)
+	suite.cloudStorage = mockInterfaces.NewMockCloudStorageUseCase(suite.mockCtrl)
+
+	policies := map[string]*upload.Policy{
+		folder: {Types: upload.ImageTypes, MaxSize: 1024, MaxWidth: 20, MaxHeight: 20},
+	}
+
+	suite.guard = upload.NewGuard(policies, suite.scanner)
+	suite.storage = upload.NewStorage(suite.guard, suite.cloudStorage)
+}
+
+func (suite *UploadTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+// newFileHeader builds the header of a multipart file the way gin binds it from a request
+func (suite *UploadTestSuite) newFileHeader(filename string, data []byte) *multipart.FileHeader {
+	var body bytes.Buffer
+
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("photo", filename)
+	suite.Require().NoError(err)
+	_, err = part.Write(data)
+	suite.Require().NoError(err)
+	suite.Require().NoError(w.Close())
+
+	form, err := multipart.NewReader(&body, w.Boundary()).ReadForm(1 << 20)
+	suite.Require().NoError(err)
+
+	return form.File["photo"][0]
+}
+
+func (suite *UploadTestSuite) newPNG(w, h int) []byte {
+	var buf bytes.Buffer
+	suite.Require().NoError(png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h))))
+
+	return buf.Bytes()
+}
+
+func (suite *UploadTestSuite) TestGuard_Check() {
+	ctx := context.Background()
+
+	suite.Run("successfully accept an image whatever its file name", func() {
+		suite.scanner.EXPECT().Scan(ctx, gomock.Any()).Return("", nil)
+
+		file, err := suite.guard.Check(ctx, suite.newFileHeader("photo.exe", suite.newPNG(10, 10)), folder)
+
+		suite.Nil(err)
+		suite.Equal("image/png", file.ContentType)
+		suite.Equal("png", file.Extension)
+	})
+
+	suite.Run("fail to accept a file sniffed as another type than its extension", func() {
+		_, err := suite.guard.Check(ctx, suite.newFileHeader("photo.jpg", []byte("<html><script>alert(1)</script></html>")), folder)
+		suite.Equal(errors.ErrUploadTypeNotAllowed.Error(), err)
+	})
+
+	suite.Run("fail to accept a file over the size limit", func() {
+		_, err := suite.guard.Check(ctx, suite.newFileHeader("photo.png", bytes.Repeat([]byte{0}, 2048)), folder)
+		suite.Equal(errors.ErrUploadTooLarge.Error(), err)
+	})
+
+	suite.Run("fail to accept an image over the dimension limit", func() {
+		_, err := suite.guard.Check(ctx, suite.newFileHeader("photo.png", suite.newPNG(30, 10)), folder)
+		suite.Equal(errors.ErrImageTooLarge.Error(), err)
+	})
+
+	suite.Run("fail to accept an infected file", func() {
+		suite.scanner.EXPECT().Scan(ctx, gomock.Any()).Return("Eicar-Test-Signature", nil)
+
+		_, err := suite.guard.Check(ctx, suite.newFileHeader("photo.png", suite.newPNG(10, 10)), folder)
+		suite.Equal(errors.ErrUploadInfected.Error(), err)
+	})
+
+	suite.Run("fail closed when the scanner is unavailable", func() {
+		suite.scanner.EXPECT().Scan(ctx, gomock.Any()).Return("", fmt.Errorf("connection refused"))
+
+		_, err := suite.guard.Check(ctx, suite.newFileHeader("photo.png", suite.newPNG(10, 10)), folder)
+		suite.Equal(errors.ErrScannerUnavailable.Error(), err)
+	})
+
+	suite.Run("fail to accept a file uploaded to a folder without a policy", func() {
+		_, err := suite.guard.Check(ctx, suite.newFileHeader("photo.png", suite.newPNG(10, 10)), "anything")
+		suite.Equal(errors.ErrUploadFolderNotAllowed.Error(), err)
+	})
+}
+
+func (suite *UploadTestSuite) TestStorage_Upload() {
+	suite.Run("successfully store the file with its sniffed extension", func() {
+		suite.scanner.EXPECT().Scan(gomock.Any(), gomock.Any()).Return("", nil)
+		suite.cloudStorage.EXPECT().UploadObject(gomock.Any(), gomock.Any(), "image/png").Return(nil)
+
+		stored, err := suite.storage.Upload(suite.newFileHeader("photo.php", suite.newPNG(10, 10)), folder)
+
+		suite.Nil(err)
+		suite.Regexp(`^/users/user/profile/[0-9a-f]{64}\.png$`, stored)
+	})
+
+	suite.Run("fail to store a rejected file", func() {
+		_, err := suite.storage.Upload(suite.newFileHeader("photo.png", []byte("plain text")), folder)
+		suite.Equal(errors.ErrUploadTypeNotAllowed.Error(), err)
+	})
+}
@@ -8,22 +8,36 @@ import (
 
 // Config holds configuration for the project.
 type Config struct {
-	Env       string `env:"APP_ENV,default=development"`
-	AppName   string `env:"APP_NAME,default=starter-api"`
-	Port      Port
-	HashID    HashID
-	Google    Google
-	AWS       AWS
-	Postgres  Postgres
-	Redis     Redis
-	SMTP      SMTP
-	JWTConfig JWTConfig
-	Image     Image
-	OneSignal OneSignal
-	Jaeger    Jaeger
-	URL       URL
-	MailGun   MailGun
-	Sendgrid  Sendgrid
+	Env         string `env:"APP_ENV,default=development"`
+	AppName     string `env:"APP_NAME,default=starter-api"`
+	Port        Port
+	HashID      HashID
+	Google      Google
+	AWS         AWS
+	Postgres    Postgres
+	Redis       Redis
+	SMTP        SMTP
+	JWTConfig   JWTConfig
+	Image       Image
+	OneSignal   OneSignal
+	Jaeger      Jaeger
+	URL         URL
+	MailGun     MailGun
+	Sendgrid    Sendgrid
+	SCIM        SCIM
+	CMSAuth     CMSAuth
+	LDAP        LDAP
+	Trash       Trash
+	UserImport  UserImport
+	Privacy     Privacy
+	Upload      Upload
+	ClamAV      ClamAV
+	Invitation  Invitation
+	EmailChange EmailChange
+	SMS         SMS
+	Push        Push
+	Stream      NotificationStream
+	Schedule    NotificationSchedule
 }
 
 // Port holds configuration for project's port.
@@ -50,11 +64,23 @@ type AWS struct {
 // Image holds configuration for the Image.
 type Image struct {
 	Host string `env:"IMAGE_HOST"`
+	// VariantSizes are the longest edges, in pixels, of the variants generated from an uploaded photo
+	VariantSizes []int `env:"IMAGE_VARIANT_SIZES,default=64;256;1024"`
+	// VariantFormat is the encoding of the variants, jpeg, png or webp
+	VariantFormat string `env:"IMAGE_VARIANT_FORMAT,default=jpeg"`
+	JPEGQuality   int    `env:"IMAGE_JPEG_QUALITY,default=85"`
+	// MaxPixels rejects uploads whose decoded size would exceed it
+	MaxPixels int `env:"IMAGE_MAX_PIXELS,default=40000000"`
 }
 
 // URL holds configuration for the URL.
 type URL struct {
-	ForgotPasswordURL string `env:"FORGOT_PASSWORD_URL"`
+	ForgotPasswordURL     string `env:"FORGOT_PASSWORD_URL"`
+	DataExportURL         string `env:"DATA_EXPORT_URL"`
+	DataErasureURL        string `env:"DATA_ERASURE_URL"`
+	AdminInvitationURL    string `env:"ADMIN_INVITATION_URL"`
+	EmailChangeConfirmURL string `env:"EMAIL_CHANGE_CONFIRM_URL"`
+	EmailChangeCancelURL  string `env:"EMAIL_CHANGE_CANCEL_URL"`
 }
 
 // HashID holds configuration for HashID.
@@ -117,6 +143,12 @@ type Sendgrid struct {
 	APIKey    string `env:"SENDGRID_API_KEY"`
 }
 
+// SCIM holds configuration for the SCIM provisioning endpoints.
+type SCIM struct {
+	BearerToken string `env:"SCIM_BEARER_TOKEN"`
+	BaseURL     string `env:"SCIM_BASE_URL"`
+}
+
 // Jaeger holds configuration for the Jaeger.
 type Jaeger struct {
 	Address string `env:"JAEGER_ADDRESS"`
@@ -135,3 +167,115 @@ func LoadConfig(env string) (*Config, error) {
 
 	return &config, nil
 }
+
+// CMSAuth holds configuration for the CMS authentication backend.
+type CMSAuth struct {
+	Authenticator string `env:"CMS_AUTHENTICATOR,default=bcrypt"`
+}
+
+// LDAP holds configuration for the LDAP authenticator.
+type LDAP struct {
+	URL                string `env:"LDAP_URL"`
+	StartTLS           bool   `env:"LDAP_START_TLS,default=false"`
+	InsecureSkipVerify bool   `env:"LDAP_INSECURE_SKIP_VERIFY,default=false"`
+	CACertFile         string `env:"LDAP_CA_CERT_FILE"`
+	BindDN             string `env:"LDAP_BIND_DN"`
+	BindPassword       string `env:"LDAP_BIND_PASSWORD"`
+	BaseDN             string `env:"LDAP_BASE_DN"`
+	UserFilter         string `env:"LDAP_USER_FILTER,default=(|(mail={email})(userPrincipalName={email}))"`
+	EmailAttribute     string `env:"LDAP_EMAIL_ATTRIBUTE,default=mail"`
+	NameAttribute      string `env:"LDAP_NAME_ATTRIBUTE,default=displayName"`
+	GroupAttribute     string `env:"LDAP_GROUP_ATTRIBUTE,default=memberOf"`
+	GroupRoleMapping   string `env:"LDAP_GROUP_ROLE_MAPPING"`
+	PoolSize           int    `env:"LDAP_POOL_SIZE,default=5"`
+	Timeout            string `env:"LDAP_TIMEOUT,default=10s"`
+}
+
+// Trash holds configuration for the soft-deleted users and roles.
+type Trash struct {
+	RetentionDays int    `env:"TRASH_RETENTION_DAYS,default=30"`
+	PurgeInterval string `env:"TRASH_PURGE_INTERVAL,default=1h"`
+}
+
+// UserImport holds configuration for the users imported from CSV or XLSX files.
+type UserImport struct {
+	// WorkerInterval is how often the worker looks for pending imports, the delay an import may start with
+	WorkerInterval string `env:"USER_IMPORT_WORKER_INTERVAL,default=10s"`
+}
+
+// Privacy holds configuration for the personal data export and erasure requests.
+type Privacy struct {
+	ExportExpiryHours     int    `env:"PRIVACY_EXPORT_EXPIRY_HOURS,default=72"`
+	ErasureCoolingOffDays int    `env:"PRIVACY_ERASURE_COOLING_OFF_DAYS,default=14"`
+	WorkerInterval        string `env:"PRIVACY_WORKER_INTERVAL,default=1m"`
+}
+
+// Upload holds configuration for the policies enforced on uploaded files.
+type Upload struct {
+	MaxImageSize   int64 `env:"UPLOAD_MAX_IMAGE_SIZE,default=5242880"`
+	MaxImageWidth  int   `env:"UPLOAD_MAX_IMAGE_WIDTH,default=6000"`
+	MaxImageHeight int   `env:"UPLOAD_MAX_IMAGE_HEIGHT,default=6000"`
+	// Scanner scans the accepted uploads for malware, none or clamav
+	Scanner string `env:"UPLOAD_SCANNER,default=none"`
+}
+
+// ClamAV holds configuration for the ClamAV daemon scanning uploads.
+type ClamAV struct {
+	// Address is either tcp://host:port or unix:///path/to/clamd.sock
+	Address string `env:"CLAMAV_ADDRESS,default=tcp://localhost:3310"`
+	Timeout string `env:"CLAMAV_TIMEOUT,default=30s"`
+}
+
+// Invitation holds configuration for the admin invitations.
+type Invitation struct {
+	// Secret signs the invite links
+	Secret      string `env:"INVITATION_SECRET,required"`
+	ExpiryHours int    `env:"INVITATION_EXPIRY_HOURS,default=72"`
+}
+
+// EmailChange holds configuration for the email change requests.
+type EmailChange struct {
+	ExpiryHours int `env:"EMAIL_CHANGE_EXPIRY_HOURS,default=24"`
+}
+
+// SMS holds configuration for the text messages sent to phone numbers.
+type SMS struct {
+	// Provider sends the messages, http or fake
+	Provider string `env:"SMS_PROVIDER,default=fake"`
+	HTTPURL  string `env:"SMS_HTTP_URL"`
+	APIKey   string `env:"SMS_API_KEY"`
+	SenderID string `env:"SMS_SENDER_ID"`
+	Timeout  string `env:"SMS_TIMEOUT,default=10s"`
+	// RateLimit is the number of messages a phone number receives per RateWindow
+	RateLimit  int    `env:"SMS_RATE_LIMIT,default=5"`
+	RateWindow string `env:"SMS_RATE_WINDOW,default=1h"`
+	// ResendInterval is the shortest delay between two messages to a phone number
+	ResendInterval string `env:"SMS_RESEND_INTERVAL,default=60s"`
+	CodeExpiry     string `env:"SMS_CODE_EXPIRY,default=10m"`
+}
+
+// Push holds configuration for the push notifications delivered to the devices of the users.
+type Push struct {
+	// Provider delivers the push notifications, onesignal, fcm or fake
+	Provider string `env:"PUSH_PROVIDER,default=fake"`
+	Timeout  string `env:"PUSH_TIMEOUT,default=10s"`
+	// FCMProjectID is the firebase project the messages are sent from
+	FCMProjectID string `env:"PUSH_FCM_PROJECT_ID"`
+	// FCMCredentialsFile is the service account key file, the application default credentials apply when empty
+	FCMCredentialsFile string `env:"PUSH_FCM_CREDENTIALS_FILE"`
+	FCMURL             string `env:"PUSH_FCM_URL,default=https://fcm.googleapis.com"`
+}
+
+// NotificationStream holds configuration for the notifications streamed to the signed in users.
+type NotificationStream struct {
+	// Heartbeat is the interval of the comments keeping idle streams open through proxies
+	Heartbeat string `env:"NOTIFICATION_STREAM_HEARTBEAT,default=15s"`
+	// Length is the number of recent events kept for the clients resuming with Last-Event-ID
+	Length int `env:"NOTIFICATION_STREAM_LENGTH,default=10000"`
+}
+
+// NotificationSchedule holds configuration for the notifications sent at a time or on a recurrence.
+type NotificationSchedule struct {
+	// WorkerInterval is how often the worker looks for due notifications, the delay a notification may be sent with
+	WorkerInterval string `env:"NOTIFICATION_SCHEDULE_WORKER_INTERVAL,default=30s"`
+}
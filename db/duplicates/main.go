@@ -0,0 +1,65 @@
+// Command duplicates reports the users which share an email once emails are normalised.
+// Run it before the users_email_unique migration, which fails while any of the reported duplicates remain.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gin-gonic/gin"
+
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/utils"
+)
+
+// duplicate is a normalised email shared by more than one user which is not deleted
+type duplicate struct {
+	Email string
+	Total int64
+	IDs   string
+}
+
+func main() {
+	cfg, err := config.LoadConfig(".env")
+	checkError(err)
+
+	db, err := utils.NewPostgresGormDB(&cfg.Postgres)
+	checkError(err)
+
+	if cfg.Env == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	duplicates := make([]*duplicate, 0)
+
+	if err := db.WithContext(context.Background()).
+		Model(&entity.User{}).
+		Select("LOWER(TRIM(email)) AS email, COUNT(*) AS total, STRING_AGG(id::text, ', ' ORDER BY created_at) AS ids").
+		Group("LOWER(TRIM(email))").
+		Having("COUNT(*) > 1").
+		Order("email").
+		Scan(&duplicates).
+		Error; err != nil {
+		panic(err)
+	}
+
+	if len(duplicates) == 0 {
+		fmt.Println("no duplicate emails found")
+		return
+	}
+
+	for _, d := range duplicates {
+		fmt.Printf("%s is used by %d users: %s\n", d.Email, d.Total, d.IDs)
+	}
+
+	fmt.Printf("%d duplicate emails found, resolve them before applying the %s index\n", len(duplicates), entity.UserEmailUniqueIndex)
+	os.Exit(1)
+}
+
+func checkError(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
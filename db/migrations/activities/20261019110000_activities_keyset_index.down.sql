@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS activities.activities_created_at_id_idx;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS activities_created_at_id_idx
+    ON activities.activities (created_at, id);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE activities.activities DROP COLUMN IF EXISTS version;
+
+COMMIT;
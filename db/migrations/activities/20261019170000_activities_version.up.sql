@@ -0,0 +1,6 @@
+BEGIN;
+
+-- incremented on every update, exposed as the ETag of the activity and checked against If-Match
+ALTER TABLE activities.activities ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+COMMIT;
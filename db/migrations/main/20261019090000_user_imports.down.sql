@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS main.user_import_rows;
+DROP TABLE IF EXISTS main.user_imports;
+
+COMMIT;
@@ -0,0 +1,43 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS main.user_imports
+(
+    id           UUID         NOT NULL,
+    file_name    VARCHAR(255) NOT NULL,
+    status       VARCHAR(50)  NOT NULL,
+    create_roles BOOLEAN      NOT NULL DEFAULT FALSE,
+    total_rows   INTEGER      NOT NULL DEFAULT 0,
+    created_rows INTEGER      NOT NULL DEFAULT 0,
+    failed_rows  INTEGER      NOT NULL DEFAULT 0,
+    finished_at  TIMESTAMPTZ,
+    created_by   VARCHAR(128) NOT NULL,
+    updated_by   VARCHAR(128) NOT NULL,
+    deleted_by   VARCHAR(128),
+    created_at   TIMESTAMPTZ  NOT NULL,
+    updated_at   TIMESTAMPTZ  NOT NULL,
+    deleted_at   TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS main.user_import_rows
+(
+    id             UUID         NOT NULL,
+    user_import_id UUID         NOT NULL REFERENCES main.user_imports (id) ON DELETE CASCADE,
+    row_number     INTEGER      NOT NULL,
+    name           VARCHAR(128) NOT NULL,
+    email          VARCHAR(128) NOT NULL,
+    phone_number   VARCHAR(50)  NOT NULL,
+    dob            VARCHAR(50)  NOT NULL,
+    role           VARCHAR(128) NOT NULL,
+    status         VARCHAR(50)  NOT NULL,
+    message        TEXT         NOT NULL,
+    user_id        UUID,
+    created_at     TIMESTAMPTZ  NOT NULL,
+    updated_at     TIMESTAMPTZ  NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS user_import_rows_user_import_id_idx
+    ON main.user_import_rows (user_import_id, row_number);
+
+COMMIT;
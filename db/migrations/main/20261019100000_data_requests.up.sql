@@ -0,0 +1,28 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS main.data_requests
+(
+    id           UUID         NOT NULL,
+    user_id      UUID         NOT NULL,
+    type         VARCHAR(50)  NOT NULL,
+    status       VARCHAR(50)  NOT NULL,
+    file_path    TEXT         NOT NULL DEFAULT '',
+    scheduled_at TIMESTAMPTZ  NOT NULL,
+    expires_at   TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    created_by   VARCHAR(128) NOT NULL,
+    updated_by   VARCHAR(128) NOT NULL,
+    deleted_by   VARCHAR(128),
+    created_at   TIMESTAMPTZ  NOT NULL,
+    updated_at   TIMESTAMPTZ  NOT NULL,
+    deleted_at   TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS data_requests_user_id_idx
+    ON main.data_requests (user_id, type);
+
+CREATE INDEX IF NOT EXISTS data_requests_status_scheduled_at_idx
+    ON main.data_requests (status, scheduled_at);
+
+COMMIT;
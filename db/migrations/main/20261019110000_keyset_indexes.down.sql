@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS main.notifications_created_at_id_idx;
+DROP INDEX IF EXISTS main.users_created_at_id_idx;
+
+COMMIT;
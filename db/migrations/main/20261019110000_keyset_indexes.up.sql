@@ -0,0 +1,9 @@
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS notifications_created_at_id_idx
+    ON main.notifications (created_at, id);
+
+CREATE INDEX IF NOT EXISTS users_created_at_id_idx
+    ON main.users (created_at, id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS main.users_photo_idx;
+ALTER TABLE main.users DROP COLUMN IF EXISTS photo_variants;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- comma separated file names of the variants stored under users.photo, empty for photos uploaded before variants
+ALTER TABLE main.users ADD COLUMN IF NOT EXISTS photo_variants TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS users_photo_idx ON main.users (photo);
+
+COMMIT;
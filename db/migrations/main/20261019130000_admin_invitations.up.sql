@@ -0,0 +1,32 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS main.admin_invitations
+(
+    id           UUID         NOT NULL,
+    user_id      UUID         REFERENCES main.users (id) ON DELETE SET NULL,
+    name         VARCHAR(255) NOT NULL,
+    email        VARCHAR(255) NOT NULL,
+    role_id      UUID         NOT NULL,
+    status       VARCHAR(50)  NOT NULL,
+    nonce        VARCHAR(64)  NOT NULL,
+    expires_at   TIMESTAMPTZ  NOT NULL,
+    sent_count   INT          NOT NULL DEFAULT 1,
+    last_sent_at TIMESTAMPTZ  NOT NULL,
+    accepted_at  TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ,
+    created_by   VARCHAR(128) NOT NULL,
+    updated_by   VARCHAR(128) NOT NULL,
+    deleted_by   VARCHAR(128),
+    created_at   TIMESTAMPTZ  NOT NULL,
+    updated_at   TIMESTAMPTZ  NOT NULL,
+    deleted_at   TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS admin_invitations_status_created_at_idx
+    ON main.admin_invitations (status, created_at);
+
+CREATE INDEX IF NOT EXISTS admin_invitations_user_id_idx
+    ON main.admin_invitations (user_id);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS main.email_changes;
+
+COMMIT;
@@ -0,0 +1,33 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS main.email_changes
+(
+    id                 UUID         NOT NULL,
+    user_id            UUID         NOT NULL REFERENCES main.users (id) ON DELETE CASCADE,
+    old_email          VARCHAR(255) NOT NULL,
+    new_email          VARCHAR(255) NOT NULL,
+    status             VARCHAR(50)  NOT NULL,
+    confirm_token_hash VARCHAR(64)  NOT NULL,
+    cancel_token_hash  VARCHAR(64)  NOT NULL,
+    expires_at         TIMESTAMPTZ  NOT NULL,
+    confirmed_at       TIMESTAMPTZ,
+    cancelled_at       TIMESTAMPTZ,
+    created_by         VARCHAR(128) NOT NULL,
+    updated_by         VARCHAR(128) NOT NULL,
+    deleted_by         VARCHAR(128),
+    created_at         TIMESTAMPTZ  NOT NULL,
+    updated_at         TIMESTAMPTZ  NOT NULL,
+    deleted_at         TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS email_changes_confirm_token_hash_idx
+    ON main.email_changes (confirm_token_hash);
+
+CREATE UNIQUE INDEX IF NOT EXISTS email_changes_cancel_token_hash_idx
+    ON main.email_changes (cancel_token_hash);
+
+CREATE INDEX IF NOT EXISTS email_changes_user_id_status_idx
+    ON main.email_changes (user_id, status);
+
+COMMIT;
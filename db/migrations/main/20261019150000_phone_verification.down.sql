@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS main.phone_verifications;
+ALTER TABLE main.users DROP COLUMN IF EXISTS otp_channel;
+ALTER TABLE main.users DROP COLUMN IF EXISTS phone_verified_at;
+
+COMMIT;
@@ -0,0 +1,28 @@
+BEGIN;
+
+ALTER TABLE main.users ADD COLUMN IF NOT EXISTS phone_verified_at TIMESTAMPTZ;
+-- channel of the login code, sms is only honoured while the phone number is verified
+ALTER TABLE main.users ADD COLUMN IF NOT EXISTS otp_channel VARCHAR(10) NOT NULL DEFAULT 'email';
+
+CREATE TABLE IF NOT EXISTS main.phone_verifications
+(
+    id           UUID         NOT NULL,
+    user_id      UUID         NOT NULL REFERENCES main.users (id) ON DELETE CASCADE,
+    phone_number VARCHAR(20)  NOT NULL,
+    code_hash    VARCHAR(64)  NOT NULL,
+    attempts     INTEGER      NOT NULL DEFAULT 0,
+    expires_at   TIMESTAMPTZ  NOT NULL,
+    verified_at  TIMESTAMPTZ,
+    created_by   VARCHAR(128) NOT NULL,
+    updated_by   VARCHAR(128) NOT NULL,
+    deleted_by   VARCHAR(128),
+    created_at   TIMESTAMPTZ  NOT NULL,
+    updated_at   TIMESTAMPTZ  NOT NULL,
+    deleted_at   TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS phone_verifications_user_id_created_at_idx
+    ON main.phone_verifications (user_id, created_at);
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+-- only the preferences set away from their default are stored, values are JSON encoded
+CREATE TABLE IF NOT EXISTS main.user_preferences
+(
+    id         UUID         NOT NULL,
+    user_id    UUID         NOT NULL REFERENCES main.users (id) ON DELETE CASCADE,
+    key        VARCHAR(64)  NOT NULL,
+    value      TEXT         NOT NULL,
+    created_by VARCHAR(128) NOT NULL,
+    updated_by VARCHAR(128) NOT NULL,
+    deleted_by VARCHAR(128),
+    created_at TIMESTAMPTZ  NOT NULL,
+    updated_at TIMESTAMPTZ  NOT NULL,
+    deleted_at TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_preferences_user_id_key_idx
+    ON main.user_preferences (user_id, key);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE main.users DROP COLUMN IF EXISTS version;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- incremented on every update, exposed as the ETag of the user and checked against If-Match
+ALTER TABLE main.users ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+COMMIT;
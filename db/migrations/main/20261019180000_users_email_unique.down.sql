@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS main.users_email_unique;
+
+COMMIT;
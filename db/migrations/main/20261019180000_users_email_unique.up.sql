@@ -0,0 +1,9 @@
+BEGIN;
+
+-- emails are stored trimmed and lower cased, run `make email-duplicates` first as the index fails on duplicates
+UPDATE main.users SET email = LOWER(TRIM(email)) WHERE email <> LOWER(TRIM(email));
+
+-- deleted users keep their email reserved in the application until they are purged, only active users are unique here
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_unique ON main.users (LOWER(email)) WHERE deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS main.permissions_name;
+
+ALTER TABLE main.permissions DROP COLUMN IF EXISTS orphaned_at;
+ALTER TABLE main.permissions DROP COLUMN IF EXISTS group_name;
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE main.permissions ADD COLUMN IF NOT EXISTS label VARCHAR(255) NOT NULL DEFAULT '';
+-- the module declaring the permission in code, empty for the permissions created through the CMS
+ALTER TABLE main.permissions ADD COLUMN IF NOT EXISTS group_name VARCHAR(128) NOT NULL DEFAULT '';
+-- set at startup on the permissions no module declares anymore
+ALTER TABLE main.permissions ADD COLUMN IF NOT EXISTS orphaned_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS permissions_name ON main.permissions (name) WHERE deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE main.role_permissions DROP COLUMN IF EXISTS core;
+ALTER TABLE main.roles DROP COLUMN IF EXISTS system;
+
+COMMIT;
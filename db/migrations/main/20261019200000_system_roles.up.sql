@@ -0,0 +1,8 @@
+BEGIN;
+
+-- system roles are declared in code, they cannot be deleted or renamed
+ALTER TABLE main.roles ADD COLUMN IF NOT EXISTS system BOOLEAN NOT NULL DEFAULT FALSE;
+-- core permissions are granted to a system role in code and cannot be removed from it
+ALTER TABLE main.role_permissions ADD COLUMN IF NOT EXISTS core BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
@@ -0,0 +1,28 @@
+BEGIN;
+
+-- devices push notifications are delivered to, a token belongs to the last user who registered it
+CREATE TABLE IF NOT EXISTS main.user_devices
+(
+    id           UUID         NOT NULL,
+    user_id      UUID         NOT NULL REFERENCES main.users (id) ON DELETE CASCADE,
+    provider     VARCHAR(32)  NOT NULL,
+    token        TEXT         NOT NULL,
+    platform     VARCHAR(16)  NOT NULL,
+    app_version  VARCHAR(64),
+    last_seen_at TIMESTAMPTZ  NOT NULL,
+    created_by   VARCHAR(128) NOT NULL,
+    updated_by   VARCHAR(128) NOT NULL,
+    deleted_by   VARCHAR(128),
+    created_at   TIMESTAMPTZ  NOT NULL,
+    updated_at   TIMESTAMPTZ  NOT NULL,
+    deleted_at   TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_devices_provider_token_idx
+    ON main.user_devices (provider, token);
+
+CREATE INDEX IF NOT EXISTS user_devices_user_id_idx
+    ON main.user_devices (user_id);
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+ALTER TABLE main.notifications ADD COLUMN IF NOT EXISTS is_read BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE main.notifications n
+SET is_read = true
+FROM main.notification_receipts r
+WHERE r.notification_id = n.id
+  AND r.user_id = n.user_id
+  AND r.read_at IS NOT NULL;
+
+ALTER TABLE main.notifications ALTER COLUMN is_read DROP DEFAULT;
+
+DROP TABLE IF EXISTS main.notification_receipts;
+
+COMMIT;
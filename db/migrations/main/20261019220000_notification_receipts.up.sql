@@ -0,0 +1,30 @@
+BEGIN;
+
+-- read state of a notification for one of its recipients, broadcasts have a receipt per user.
+-- notifications without a receipt are unread, neither archived nor deleted.
+CREATE TABLE IF NOT EXISTS main.notification_receipts
+(
+    notification_id UUID        NOT NULL REFERENCES main.notifications (id) ON DELETE CASCADE,
+    user_id         UUID        NOT NULL REFERENCES main.users (id) ON DELETE CASCADE,
+    read_at         TIMESTAMPTZ,
+    archived_at     TIMESTAMPTZ,
+    deleted_at      TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL,
+    updated_at      TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (notification_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS notification_receipts_user_id_idx
+    ON main.notification_receipts (user_id);
+
+-- the read flag of a broadcast was shared by everyone, only the read state of personal notifications is kept
+INSERT INTO main.notification_receipts (notification_id, user_id, read_at, created_at, updated_at)
+SELECT n.id, n.user_id, n.updated_at, n.updated_at, n.updated_at
+FROM main.notifications n
+         JOIN main.users u ON u.id = n.user_id
+WHERE n.is_read = true
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE main.notifications DROP COLUMN IF EXISTS is_read;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE main.notifications DROP COLUMN IF EXISTS deep_link;
+
+DROP TABLE IF EXISTS main.notification_template_translations;
+
+DROP TABLE IF EXISTS main.notification_templates;
+
+COMMIT;
@@ -0,0 +1,38 @@
+BEGIN;
+
+-- notifications sent by key, rendered in the language of the recipient
+CREATE TABLE IF NOT EXISTS main.notification_templates
+(
+    id         UUID         NOT NULL,
+    key        VARCHAR(100) NOT NULL,
+    type       VARCHAR(50)  NOT NULL,
+    deep_link  TEXT         NOT NULL DEFAULT '',
+    status     VARCHAR(16)  NOT NULL,
+    created_by VARCHAR(128) NOT NULL,
+    updated_by VARCHAR(128) NOT NULL,
+    deleted_by VARCHAR(128),
+    created_at TIMESTAMPTZ  NOT NULL,
+    updated_at TIMESTAMPTZ  NOT NULL,
+    deleted_at TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS notification_templates_key_idx
+    ON main.notification_templates (key)
+    WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS notification_templates_created_at_id_idx
+    ON main.notification_templates (created_at, id);
+
+CREATE TABLE IF NOT EXISTS main.notification_template_translations
+(
+    template_id UUID       NOT NULL REFERENCES main.notification_templates (id) ON DELETE CASCADE,
+    locale      VARCHAR(8) NOT NULL,
+    title       TEXT       NOT NULL,
+    body        TEXT       NOT NULL,
+    PRIMARY KEY (template_id, locale)
+);
+
+ALTER TABLE main.notifications ADD COLUMN IF NOT EXISTS deep_link TEXT;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS main.scheduled_notification_runs;
+
+DROP TABLE IF EXISTS main.scheduled_notifications;
+
+COMMIT;
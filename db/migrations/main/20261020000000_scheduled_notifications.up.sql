@@ -0,0 +1,54 @@
+BEGIN;
+
+-- notifications sent at a time or on a cron recurrence by the schedule worker
+CREATE TABLE IF NOT EXISTS main.scheduled_notifications
+(
+    id           UUID         NOT NULL,
+    user_id      UUID,
+    template_key VARCHAR(100),
+    data         TEXT         NOT NULL DEFAULT '{}',
+    title        TEXT         NOT NULL DEFAULT '',
+    message      TEXT         NOT NULL DEFAULT '',
+    type         VARCHAR(50)  NOT NULL DEFAULT '',
+    extra        TEXT         NOT NULL DEFAULT '',
+    platforms    VARCHAR(64)  NOT NULL DEFAULT '',
+    send_at      TIMESTAMPTZ,
+    cron         VARCHAR(100),
+    timezone     VARCHAR(64)  NOT NULL,
+    status       VARCHAR(16)  NOT NULL,
+    next_run_at  TIMESTAMPTZ,
+    last_run_at  TIMESTAMPTZ,
+    run_count    INTEGER      NOT NULL DEFAULT 0,
+    created_by   VARCHAR(128) NOT NULL,
+    updated_by   VARCHAR(128) NOT NULL,
+    deleted_by   VARCHAR(128),
+    created_at   TIMESTAMPTZ  NOT NULL,
+    updated_at   TIMESTAMPTZ  NOT NULL,
+    deleted_at   TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS scheduled_notifications_due_idx
+    ON main.scheduled_notifications (next_run_at)
+    WHERE status = 'scheduled' AND deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS scheduled_notifications_created_at_id_idx
+    ON main.scheduled_notifications (created_at, id);
+
+-- one row per occurrence, the unique index keeps an occurrence from being delivered twice
+CREATE TABLE IF NOT EXISTS main.scheduled_notification_runs
+(
+    id                        UUID        NOT NULL,
+    scheduled_notification_id UUID        NOT NULL REFERENCES main.scheduled_notifications (id) ON DELETE CASCADE,
+    scheduled_for             TIMESTAMPTZ NOT NULL,
+    status                    VARCHAR(16) NOT NULL,
+    error                     TEXT        NOT NULL DEFAULT '',
+    started_at                TIMESTAMPTZ NOT NULL,
+    finished_at               TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS scheduled_notification_runs_occurrence_idx
+    ON main.scheduled_notification_runs (scheduled_notification_id, scheduled_for);
+
+COMMIT;
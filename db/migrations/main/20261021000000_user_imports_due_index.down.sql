@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS main.user_imports_due_idx;
+
+COMMIT;
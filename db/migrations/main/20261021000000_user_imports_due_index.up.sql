@@ -0,0 +1,8 @@
+BEGIN;
+
+-- the import worker looks for the imports which did not finish
+CREATE INDEX IF NOT EXISTS user_imports_due_idx
+    ON main.user_imports (created_at)
+    WHERE status IN ('PENDING', 'PROCESSING') AND deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE main.user_import_rows
+    ALTER COLUMN name TYPE VARCHAR(128) USING LEFT(name, 128),
+    ALTER COLUMN email TYPE VARCHAR(128) USING LEFT(email, 128),
+    ALTER COLUMN phone_number TYPE VARCHAR(50) USING LEFT(phone_number, 50),
+    ALTER COLUMN dob TYPE VARCHAR(50) USING LEFT(dob, 50),
+    ALTER COLUMN role TYPE VARCHAR(128) USING LEFT(role, 128);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+-- rows are stored with the cells of the file as they are, including the invalid ones reported back
+ALTER TABLE main.user_import_rows
+    ALTER COLUMN name TYPE TEXT,
+    ALTER COLUMN email TYPE TEXT,
+    ALTER COLUMN phone_number TYPE TEXT,
+    ALTER COLUMN dob TYPE TEXT,
+    ALTER COLUMN role TYPE TEXT;
+
+COMMIT;
@@ -16,6 +16,8 @@ type Activities struct {
 	Title          string    `json:"title"`
 	Description    string    `json:"description"`
 	ActivitiesType string    `json:"activities_type"`
+	// Version is incremented on every update, updates of an older version are rejected
+	Version int64 `json:"version"`
 	Auditable
 }
 
@@ -38,6 +40,7 @@ func NewActivities(
 		Title:          title,
 		Description:    description,
 		ActivitiesType: activities_type,
+		Version:        1,
 		Auditable:      NewAuditable(createdBy),
 	}
 }
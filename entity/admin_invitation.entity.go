@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	adminInvitationTableName = "main.admin_invitations"
+
+	// AdminInvitationStatusPending is the status of an invitation waiting for the invitee
+	AdminInvitationStatusPending = "PENDING"
+	// AdminInvitationStatusAccepted is the status of an invitation whose invitee activated the account
+	AdminInvitationStatusAccepted = "ACCEPTED"
+	// AdminInvitationStatusRevoked is the status of an invitation withdrawn by an operator
+	AdminInvitationStatusRevoked = "REVOKED"
+	// AdminInvitationStatusExpired is reported for a pending invitation past its expiry, it is never stored
+	AdminInvitationStatusExpired = "EXPIRED"
+)
+
+// AdminInvitation defines table admin_invitations, the invitation of a pending admin to set a password
+type AdminInvitation struct {
+	ID     uuid.UUID     `json:"id"`
+	UserID uuid.NullUUID `json:"user_id"`
+	Name   string        `json:"name"`
+	Email  string        `json:"email"`
+	RoleID uuid.UUID     `json:"role_id"`
+	Status string        `json:"status"`
+	// Nonce is signed into the invite link, rotating it invalidates the links sent before
+	Nonce      string     `json:"-"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	SentCount  int        `json:"sent_count"`
+	LastSentAt time.Time  `json:"last_sent_at"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	Role       *Role      `gorm:"foreignKey:RoleID"`
+	Auditable
+}
+
+// TableName specifies table name
+func (model *AdminInvitation) TableName() string {
+	return adminInvitationTableName
+}
+
+// NewAdminInvitation creates new admin invitation entity for the pending admin
+func NewAdminInvitation(
+	id uuid.UUID,
+	userID uuid.UUID,
+	name string,
+	email string,
+	roleID uuid.UUID,
+	nonce string,
+	expiresAt time.Time,
+	createdBy string,
+) *AdminInvitation {
+	return &AdminInvitation{
+		ID:         id,
+		UserID:     uuid.NullUUID{UUID: userID, Valid: true},
+		Name:       name,
+		Email:      email,
+		RoleID:     roleID,
+		Status:     AdminInvitationStatusPending,
+		Nonce:      nonce,
+		ExpiresAt:  expiresAt,
+		SentCount:  1,
+		LastSentAt: time.Now(),
+		Auditable:  NewAuditable(createdBy),
+	}
+}
+
+// CurrentStatus returns the status of the invitation at the given time, reporting expired pending invitations
+func (model *AdminInvitation) CurrentStatus(now time.Time) string {
+	if model.Status == AdminInvitationStatusPending && !now.Before(model.ExpiresAt) {
+		return AdminInvitationStatusExpired
+	}
+
+	return model.Status
+}
@@ -3,3 +3,13 @@ package entity
 type Token struct {
 	Token string `json:"token"`
 }
+
+// AuthIdentity is the identity verified by a CMS authenticator
+type AuthIdentity struct {
+	Email string
+	Name  string
+	// User is the local user when the credentials were verified against the database
+	User *User
+	// Roles are the role names granted by an external directory, highest priority first
+	Roles []string
+}
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	dataRequestTableName = "main.data_requests"
+
+	// DataRequestTypeExport is the type of a personal data export request
+	DataRequestTypeExport = "EXPORT"
+	// DataRequestTypeErasure is the type of a personal data erasure request
+	DataRequestTypeErasure = "ERASURE"
+
+	// DataRequestStatusPending is the status of a request waiting for the worker
+	DataRequestStatusPending = "PENDING"
+	// DataRequestStatusProcessing is the status of a request being processed
+	DataRequestStatusProcessing = "PROCESSING"
+	// DataRequestStatusCompleted is the status of a processed request
+	DataRequestStatusCompleted = "COMPLETED"
+	// DataRequestStatusFailed is the status of a request aborted by an unexpected error
+	DataRequestStatusFailed = "FAILED"
+	// DataRequestStatusCancelled is the status of an erasure request cancelled during the cooling-off period
+	DataRequestStatusCancelled = "CANCELLED"
+	// DataRequestStatusExpired is the status of an export whose file has been removed
+	DataRequestStatusExpired = "EXPIRED"
+)
+
+// DataRequest defines table data_requests, a data-subject request of a user
+type DataRequest struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	FilePath    string     `json:"file_path"`
+	ScheduledAt time.Time  `json:"scheduled_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	Auditable
+}
+
+// TableName specifies table name
+func (model *DataRequest) TableName() string {
+	return dataRequestTableName
+}
+
+// NewDataRequest creates new data request entity processed from scheduledAt onwards
+func NewDataRequest(
+	id uuid.UUID,
+	userID uuid.UUID,
+	requestType string,
+	scheduledAt time.Time,
+	createdBy string,
+) *DataRequest {
+	return &DataRequest{
+		ID:          id,
+		UserID:      userID,
+		Type:        requestType,
+		Status:      DataRequestStatusPending,
+		ScheduledAt: scheduledAt,
+		Auditable:   NewAuditable(createdBy),
+	}
+}
@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	emailChangeTableName = "main.email_changes"
+
+	// EmailChangeStatusPending is the status of a change waiting for the confirmation of the new address
+	EmailChangeStatusPending = "PENDING"
+	// EmailChangeStatusConfirmed is the status of a change whose new address replaced the email of the user
+	EmailChangeStatusConfirmed = "CONFIRMED"
+	// EmailChangeStatusCancelled is the status of a change cancelled from the old address or superseded by a newer request
+	EmailChangeStatusCancelled = "CANCELLED"
+)
+
+// EmailChange defines table email_changes, a request of a user to change its email
+type EmailChange struct {
+	ID       uuid.UUID `json:"id"`
+	UserID   uuid.UUID `json:"user_id"`
+	OldEmail string    `json:"old_email"`
+	NewEmail string    `json:"new_email"`
+	Status   string    `json:"status"`
+	// ConfirmTokenHash and CancelTokenHash are the sha256 of the tokens mailed to the new and the old address
+	ConfirmTokenHash string     `json:"-"`
+	CancelTokenHash  string     `json:"-"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	ConfirmedAt      *time.Time `json:"confirmed_at"`
+	CancelledAt      *time.Time `json:"cancelled_at"`
+	Auditable
+}
+
+// TableName specifies table name
+func (model *EmailChange) TableName() string {
+	return emailChangeTableName
+}
+
+// NewEmailChange creates new email change entity
+func NewEmailChange(
+	id uuid.UUID,
+	userID uuid.UUID,
+	oldEmail string,
+	newEmail string,
+	confirmTokenHash string,
+	cancelTokenHash string,
+	expiresAt time.Time,
+	createdBy string,
+) *EmailChange {
+	return &EmailChange{
+		ID:               id,
+		UserID:           userID,
+		OldEmail:         oldEmail,
+		NewEmail:         newEmail,
+		Status:           EmailChangeStatusPending,
+		ConfirmTokenHash: confirmTokenHash,
+		CancelTokenHash:  cancelTokenHash,
+		ExpiresAt:        expiresAt,
+		Auditable:        NewAuditable(createdBy),
+	}
+}
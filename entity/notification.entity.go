@@ -20,7 +20,11 @@ type Notification struct {
 	Description string         `json:"description"`
 	Type        string         `json:"type"`
 	Extra       string         `json:"extra"`
-	IsRead      bool           `json:"is_read"`
+	// DeepLink is the link opened from the notification
+	DeepLink sql.NullString `json:"deep_link"`
+	// IsRead and IsArchived are the state for the user the notification is read for, kept in its receipt
+	IsRead     bool `gorm:"->" json:"is_read"`
+	IsArchived bool `gorm:"->" json:"is_archived"`
 	Auditable
 }
 
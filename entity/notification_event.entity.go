@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// NotificationEventCreated is sent when a notification is created
+	NotificationEventCreated = "notification"
+	// NotificationEventRead is sent when notifications are marked as read
+	NotificationEventRead = "read"
+	// NotificationEventArchived is sent when notifications are archived
+	NotificationEventArchived = "archived"
+	// NotificationEventDeleted is sent when notifications are deleted
+	NotificationEventDeleted = "deleted"
+)
+
+// NotificationEvent is a change of the notifications of a user streamed to the signed in clients
+type NotificationEvent struct {
+	// ID is assigned by the event log when the event is published, it increases with every event
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// UserID is the recipient, uuid.Nil when the notification is broadcast to everyone
+	UserID       uuid.UUID     `json:"user_id"`
+	Notification *Notification `json:"notification,omitempty"`
+	// NotificationIDs are the notifications read, archived or deleted, every notification of the user when empty
+	NotificationIDs []uuid.UUID `json:"notification_ids,omitempty"`
+}
+
+// NewNotificationEvent creates new notification event
+func NewNotificationEvent(eventType string, userID uuid.UUID, notification *Notification) *NotificationEvent {
+	return &NotificationEvent{
+		Type:         eventType,
+		UserID:       userID,
+		Notification: notification,
+	}
+}
+
+// IsFor tells whether the event is addressed to the user
+func (e *NotificationEvent) IsFor(userID uuid.UUID) bool {
+	return e.UserID == uuid.Nil || e.UserID == userID
+}
+
+// After tells whether the event was published after the event of the id
+func (e *NotificationEvent) After(id string) bool {
+	ms, seq, ok := parseNotificationEventID(e.ID)
+	lastMs, lastSeq, lastOk := parseNotificationEventID(id)
+
+	if !ok || !lastOk {
+		return true
+	}
+
+	return ms > lastMs || (ms == lastMs && seq > lastSeq)
+}
+
+// IsNotificationEventID tells whether the id has the <milliseconds>-<sequence> format of the event ids
+func IsNotificationEventID(id string) bool {
+	_, _, ok := parseNotificationEventID(id)
+	return ok
+}
+
+func parseNotificationEventID(id string) (uint64, uint64, bool) {
+	parts := strings.SplitN(id, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+
+	ms, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	seq, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return ms, seq, true
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	notificationReceiptTableName = "main.notification_receipts"
+
+	// NotificationReceiptRead marks the notification as read
+	NotificationReceiptRead = "read_at"
+	// NotificationReceiptArchived moves the notification out of the inbox
+	NotificationReceiptArchived = "archived_at"
+	// NotificationReceiptDeleted hides the notification from its recipient
+	NotificationReceiptDeleted = "deleted_at"
+)
+
+// NotificationReceipt is the state of a notification for one of its recipients.
+// Broadcasts are shared by everyone, so their read, archived and deleted state is tracked per user here.
+type NotificationReceipt struct {
+	NotificationID uuid.UUID    `json:"notification_id"`
+	UserID         uuid.UUID    `json:"user_id"`
+	ReadAt         sql.NullTime `json:"read_at"`
+	ArchivedAt     sql.NullTime `json:"archived_at"`
+	// DeletedAt hides the notification from the user only, it is not a soft delete of the receipt
+	DeletedAt sql.NullTime `json:"deleted_at"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// TableName specifies table name
+func (model *NotificationReceipt) TableName() string {
+	return notificationReceiptTableName
+}
+
+// NewNotificationReceipt creates new notification receipt
+func NewNotificationReceipt(notificationID uuid.UUID, userID uuid.UUID) *NotificationReceipt {
+	return &NotificationReceipt{
+		NotificationID: notificationID,
+		UserID:         userID,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+}
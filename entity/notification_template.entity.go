@@ -0,0 +1,201 @@
+package entity
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	notificationTemplateTableName            = "main.notification_templates"
+	notificationTemplateTranslationTableName = "main.notification_template_translations"
+
+	// NotificationTemplateDraft is a template editors are still working on, it cannot be sent
+	NotificationTemplateDraft = "draft"
+	// NotificationTemplatePublished is a template the services can send
+	NotificationTemplatePublished = "published"
+)
+
+// notificationTemplateVariable matches the {{variables}} of a template, spaces inside the braces are allowed
+var notificationTemplateVariable = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.]+)\s*\}\}`)
+
+// NotificationTemplateKey is the format of the keys services send the templates by
+var NotificationTemplateKey = regexp.MustCompile(`^[a-z0-9]+([._-][a-z0-9]+)*$`)
+
+// NotificationTemplate is a notification the services send by key, rendered in the language of the recipient
+type NotificationTemplate struct {
+	ID  uuid.UUID `json:"id"`
+	Key string    `json:"key"`
+	// Type is the type of the notifications sent from the template
+	Type string `json:"type"`
+	// DeepLink is the default link opened from the notification, it can hold variables
+	DeepLink     string                             `json:"deep_link"`
+	Status       string                             `json:"status"`
+	Translations []*NotificationTemplateTranslation `gorm:"foreignKey:TemplateID" json:"translations"`
+	Auditable
+}
+
+// TableName specifies table name
+func (model *NotificationTemplate) TableName() string {
+	return notificationTemplateTableName
+}
+
+// NotificationTemplateTranslation is the title and body of a template in a language
+type NotificationTemplateTranslation struct {
+	TemplateID uuid.UUID `gorm:"primaryKey" json:"template_id"`
+	Locale     string    `gorm:"primaryKey" json:"locale"`
+	Title      string    `json:"title"`
+	Body       string    `json:"body"`
+}
+
+// TableName specifies table name
+func (model *NotificationTemplateTranslation) TableName() string {
+	return notificationTemplateTranslationTableName
+}
+
+// RenderedNotification is a template rendered in a language with data
+type RenderedNotification struct {
+	Locale   string
+	Type     string
+	Title    string
+	Body     string
+	DeepLink string
+	// Missing are the variables the data lacks, they are left as they are in the texts
+	Missing []string
+}
+
+// NewNotificationTemplate creates new notification template, as a draft
+func NewNotificationTemplate(
+	id uuid.UUID,
+	key string,
+	notifType string,
+	deepLink string,
+	translations []*NotificationTemplateTranslation,
+	createdBy string,
+) *NotificationTemplate {
+	for _, translation := range translations {
+		translation.TemplateID = id
+	}
+
+	return &NotificationTemplate{
+		ID:           id,
+		Key:          key,
+		Type:         notifType,
+		DeepLink:     deepLink,
+		Status:       NotificationTemplateDraft,
+		Translations: translations,
+		Auditable:    NewAuditable(createdBy),
+	}
+}
+
+// NotificationTemplateFallbackLocale is the language templates fall back to, the default language of the users
+func NotificationTemplateFallbackLocale() string {
+	definition := FindPreferenceDefinition(PreferenceLanguage)
+	locale, _ := definition.Default.(string)
+	return locale
+}
+
+// NotificationTemplateLocales are the languages a template can be translated to, the languages users can choose
+func NotificationTemplateLocales() []string {
+	return FindPreferenceDefinition(PreferenceLanguage).Options
+}
+
+// Translation returns the translation of the locale, falling back to the fallback locale then to the first locale.
+// It returns nil when the template has no translation.
+func (model *NotificationTemplate) Translation(locale string) *NotificationTemplateTranslation {
+	if len(model.Translations) == 0 {
+		return nil
+	}
+
+	byLocale := make(map[string]*NotificationTemplateTranslation, len(model.Translations))
+	for _, translation := range model.Translations {
+		byLocale[translation.Locale] = translation
+	}
+
+	for _, l := range []string{locale, NotificationTemplateFallbackLocale()} {
+		if translation, ok := byLocale[l]; ok {
+			return translation
+		}
+	}
+
+	locales := make([]string, 0, len(byLocale))
+	for l := range byLocale {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+
+	return byLocale[locales[0]]
+}
+
+// Render renders the template in the locale, or its fallback, with the data
+func (model *NotificationTemplate) Render(locale string, data map[string]string) *RenderedNotification {
+	translation := model.Translation(locale)
+	if translation == nil {
+		return nil
+	}
+
+	missing := make(map[string]bool)
+	rendered := &RenderedNotification{
+		Locale:   translation.Locale,
+		Type:     model.Type,
+		Title:    renderNotificationTemplate(translation.Title, data, missing),
+		Body:     renderNotificationTemplate(translation.Body, data, missing),
+		DeepLink: renderNotificationTemplate(model.DeepLink, data, missing),
+		Missing:  make([]string, 0, len(missing)),
+	}
+
+	for name := range missing {
+		rendered.Missing = append(rendered.Missing, name)
+	}
+	sort.Strings(rendered.Missing)
+
+	return rendered
+}
+
+// Variables returns the variables used by the texts and the deep link of the template, sorted by name
+func (model *NotificationTemplate) Variables() []string {
+	texts := []string{model.DeepLink}
+	for _, translation := range model.Translations {
+		texts = append(texts, translation.Title, translation.Body)
+	}
+
+	seen := make(map[string]bool)
+	variables := make([]string, 0)
+	for _, match := range notificationTemplateVariable.FindAllStringSubmatch(strings.Join(texts, "\n"), -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			variables = append(variables, match[1])
+		}
+	}
+	sort.Strings(variables)
+
+	return variables
+}
+
+// MapUpdateFrom mapping from model
+func (model *NotificationTemplate) MapUpdateFrom(from *NotificationTemplate) *map[string]interface{} {
+	return &map[string]interface{}{
+		"key":        from.Key,
+		"type":       from.Type,
+		"deep_link":  from.DeepLink,
+		"status":     from.Status,
+		"updated_by": from.UpdatedBy,
+		"updated_at": from.UpdatedAt,
+	}
+}
+
+// renderNotificationTemplate replaces the variables of the text with the data, recording the missing ones
+func renderNotificationTemplate(text string, data map[string]string, missing map[string]bool) string {
+	return notificationTemplateVariable.ReplaceAllStringFunc(text, func(variable string) string {
+		name := notificationTemplateVariable.FindStringSubmatch(variable)[1]
+		value, ok := data[name]
+		if !ok {
+			missing[name] = true
+			return variable
+		}
+
+		return value
+	})
+}
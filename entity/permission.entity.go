@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,6 +16,10 @@ type Permission struct {
 	ID    uuid.UUID `json:"id"`
 	Name  string    `json:"name"`
 	Label string    `json:"label"`
+	// Group is the module declaring the permission in code, empty for the permissions created through the CMS
+	Group string `json:"group" gorm:"column:group_name"`
+	// OrphanedAt is set when the permission is no longer declared by any module
+	OrphanedAt sql.NullTime `json:"orphaned_at"`
 	Auditable
 }
 
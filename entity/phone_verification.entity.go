@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	phoneVerificationTableName = "main.phone_verifications"
+	// PhoneVerificationMaxAttempts is the number of wrong codes after which a verification is discarded
+	PhoneVerificationMaxAttempts = 5
+)
+
+// PhoneVerification defines table phone_verifications, a code sent by SMS to verify the phone number of a user
+type PhoneVerification struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"user_id"`
+	PhoneNumber string    `json:"phone_number"`
+	// CodeHash is the sha256 of the code sent to the phone number
+	CodeHash   string     `json:"-"`
+	Attempts   int        `json:"attempts"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	VerifiedAt *time.Time `json:"verified_at"`
+	Auditable
+}
+
+// TableName specifies table name
+func (model *PhoneVerification) TableName() string {
+	return phoneVerificationTableName
+}
+
+// NewPhoneVerification creates new phone verification entity
+func NewPhoneVerification(
+	id uuid.UUID,
+	userID uuid.UUID,
+	phoneNumber string,
+	codeHash string,
+	expiresAt time.Time,
+	createdBy string,
+) *PhoneVerification {
+	return &PhoneVerification{
+		ID:          id,
+		UserID:      userID,
+		PhoneNumber: phoneNumber,
+		CodeHash:    codeHash,
+		ExpiresAt:   expiresAt,
+		Auditable:   NewAuditable(createdBy),
+	}
+}
+
+// Usable tells whether the code can still be entered at the given time
+func (model *PhoneVerification) Usable(now time.Time) bool {
+	return model.VerifiedAt == nil && model.Attempts < PhoneVerificationMaxAttempts && now.Before(model.ExpiresAt)
+}
@@ -12,8 +12,10 @@ const (
 
 // Role defines table role
 type Role struct {
-	ID              uuid.UUID         `json:"id"`
-	Name            string            `json:"name"`
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	// System roles are declared in code, they cannot be deleted or renamed and keep their core permissions
+	System          bool              `json:"system"`
 	RolePermissions []*RolePermission `foreignKey:"ID" associationForeignKey:"RoleID"`
 	Auditable
 }
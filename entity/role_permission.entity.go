@@ -10,10 +10,12 @@ const (
 
 // RolePermission define for table role_permissions
 type RolePermission struct {
-	ID           uuid.UUID   `json:"id"`
-	RoleID       uuid.UUID   `json:"role_id"`
-	PermissionID uuid.UUID   `json:"permission_id"`
-	Permission   *Permission `gorm:"ForeignKey:PermissionID;AssociationForeignKey:ID` //nolint
+	ID           uuid.UUID `json:"id"`
+	RoleID       uuid.UUID `json:"role_id"`
+	PermissionID uuid.UUID `json:"permission_id"`
+	// Core permissions are granted to a system role in code and cannot be removed from it
+	Core       bool        `json:"core"`
+	Permission *Permission `gorm:"ForeignKey:PermissionID;AssociationForeignKey:ID"`
 	Auditable
 }
 
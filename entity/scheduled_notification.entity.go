@@ -0,0 +1,202 @@
+package entity
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gin-starter/common/cron"
+	"gin-starter/utils"
+)
+
+const (
+	scheduledNotificationTableName    = "main.scheduled_notifications"
+	scheduledNotificationRunTableName = "main.scheduled_notification_runs"
+
+	// ScheduledNotificationScheduled is a notification waiting for its next run
+	ScheduledNotificationScheduled = "scheduled"
+	// ScheduledNotificationCompleted is a one-off notification which ran
+	ScheduledNotificationCompleted = "completed"
+	// ScheduledNotificationCancelled is a notification an admin cancelled before it completed
+	ScheduledNotificationCancelled = "cancelled"
+
+	// ScheduledNotificationRunRunning is a run the worker claimed and is delivering
+	ScheduledNotificationRunRunning = "running"
+	// ScheduledNotificationRunSent is a run delivered to its recipients
+	ScheduledNotificationRunSent = "sent"
+	// ScheduledNotificationRunFailed is a run which could not be delivered
+	ScheduledNotificationRunFailed = "failed"
+)
+
+// ScheduledNotification is a notification sent once at SendAt, or on every occurrence of Cron,
+// to a user or to everyone when UserID is null. It sends the template of TemplateKey rendered with Data
+// when set, the title and message otherwise.
+type ScheduledNotification struct {
+	ID          uuid.UUID      `json:"id"`
+	UserID      uuid.NullUUID  `json:"user_id"`
+	TemplateKey sql.NullString `json:"template_key"`
+	// Data is the JSON object of the variables the template is rendered with
+	Data    string `json:"data"`
+	Title   string `json:"title"`
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	Extra   string `json:"extra"`
+	// Platforms is the comma separated platforms the push is limited to, every platform when empty
+	Platforms string         `json:"platforms"`
+	SendAt    sql.NullTime   `json:"send_at"`
+	Cron      sql.NullString `json:"cron"`
+	// Timezone is the zone the cron expression is evaluated in
+	Timezone  string       `json:"timezone"`
+	Status    string       `json:"status"`
+	NextRunAt sql.NullTime `json:"next_run_at"`
+	LastRunAt sql.NullTime `json:"last_run_at"`
+	RunCount  int          `json:"run_count"`
+	Auditable
+}
+
+// TableName specifies table name
+func (model *ScheduledNotification) TableName() string {
+	return scheduledNotificationTableName
+}
+
+// ScheduledNotificationRun is the delivery of an occurrence of a scheduled notification
+type ScheduledNotificationRun struct {
+	ID                      uuid.UUID    `json:"id"`
+	ScheduledNotificationID uuid.UUID    `json:"scheduled_notification_id"`
+	ScheduledFor            time.Time    `json:"scheduled_for"`
+	Status                  string       `json:"status"`
+	Error                   string       `json:"error"`
+	StartedAt               time.Time    `json:"started_at"`
+	FinishedAt              sql.NullTime `json:"finished_at"`
+}
+
+// TableName specifies table name
+func (model *ScheduledNotificationRun) TableName() string {
+	return scheduledNotificationRunTableName
+}
+
+// NewScheduledNotification creates new scheduled notification, its schedule is set with Reschedule
+func NewScheduledNotification(
+	id uuid.UUID,
+	userID uuid.UUID,
+	templateKey string,
+	data map[string]string,
+	title string,
+	message string,
+	notifType string,
+	extra string,
+	platforms []string,
+	createdBy string,
+) *ScheduledNotification {
+	encoded, _ := json.Marshal(data)
+	if data == nil {
+		encoded = []byte("{}")
+	}
+
+	return &ScheduledNotification{
+		ID:          id,
+		UserID:      uuid.NullUUID{UUID: userID, Valid: userID != uuid.Nil},
+		TemplateKey: utils.StringToNullString(templateKey),
+		Data:        string(encoded),
+		Title:       title,
+		Message:     message,
+		Type:        notifType,
+		Extra:       extra,
+		Platforms:   strings.Join(platforms, ","),
+		Status:      ScheduledNotificationScheduled,
+		Auditable:   NewAuditable(createdBy),
+	}
+}
+
+// NewScheduledNotificationRun creates new run of the occurrence of a scheduled notification, as running
+func NewScheduledNotificationRun(id uuid.UUID, scheduledNotificationID uuid.UUID, scheduledFor time.Time) *ScheduledNotificationRun {
+	return &ScheduledNotificationRun{
+		ID:                      id,
+		ScheduledNotificationID: scheduledNotificationID,
+		ScheduledFor:            scheduledFor,
+		Status:                  ScheduledNotificationRunRunning,
+		StartedAt:               time.Now(),
+	}
+}
+
+// Reschedule sets the schedule, sending once at sendAt when cronExpr is empty, and schedules the next run after now.
+// The cron expression is evaluated in the timezone.
+func (model *ScheduledNotification) Reschedule(sendAt time.Time, cronExpr string, timezone string, now time.Time) error {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return err
+	}
+
+	if cronExpr != "" {
+		if _, err := cron.Parse(cronExpr); err != nil {
+			return err
+		}
+
+		sendAt = time.Time{}
+	}
+
+	model.SendAt = sql.NullTime{Time: sendAt, Valid: !sendAt.IsZero()}
+	model.Cron = utils.StringToNullString(cronExpr)
+	model.Timezone = timezone
+	model.Status = ScheduledNotificationScheduled
+
+	next := model.NextRun(now)
+	model.NextRunAt = sql.NullTime{Time: next, Valid: !next.IsZero()}
+
+	return nil
+}
+
+// NextRun returns the first run of the schedule after the time, the zero time when it has none.
+// A one-off notification runs at its send time, whether or not the time passed, the worker completes it after its run.
+func (model *ScheduledNotification) NextRun(after time.Time) time.Time {
+	if !model.Cron.Valid {
+		if !model.SendAt.Valid {
+			return time.Time{}
+		}
+
+		return model.SendAt.Time
+	}
+
+	schedule, err := cron.Parse(model.Cron.String)
+	if err != nil {
+		return time.Time{}
+	}
+
+	loc, err := time.LoadLocation(model.Timezone)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return schedule.Next(after, loc)
+}
+
+// DataMap decodes the template data
+func (model *ScheduledNotification) DataMap() map[string]string {
+	data := make(map[string]string)
+	_ = json.Unmarshal([]byte(model.Data), &data)
+	return data
+}
+
+// PlatformList splits the platforms the push is limited to
+func (model *ScheduledNotification) PlatformList() []string {
+	if model.Platforms == "" {
+		return nil
+	}
+
+	return strings.Split(model.Platforms, ",")
+}
+
+// MapUpdateFrom mapping from model
+func (model *ScheduledNotification) MapUpdateFrom(from *ScheduledNotification) *map[string]interface{} {
+	return &map[string]interface{}{
+		"send_at":     from.SendAt,
+		"cron":        from.Cron,
+		"timezone":    from.Timezone,
+		"status":      from.Status,
+		"next_run_at": from.NextRunAt,
+		"updated_by":  from.UpdatedBy,
+		"updated_at":  from.UpdatedAt,
+	}
+}
@@ -2,6 +2,8 @@ package entity
 
 import (
 	"database/sql"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,20 +14,43 @@ import (
 
 const (
 	userTableName = "main.users"
+	// UserStatusActivated is the status of an active user
+	UserStatusActivated = "ACTIVATED"
+	// UserStatusDeactivated is the status of a deactivated user
+	UserStatusDeactivated = "DEACTIVATED"
+	// UserStatusPending is the status of an invited admin who has not set a password yet
+	UserStatusPending = "PENDING"
+	// OTPChannelEmail sends the login code of the user by email
+	OTPChannelEmail = "email"
+	// OTPChannelSMS sends the login code of the user by SMS to the verified phone number
+	OTPChannelSMS = "sms"
+	// UserEmailUniqueIndex is the unique index on the lower cased emails of the users which are not deleted
+	UserEmailUniqueIndex = "users_email_unique"
 )
 
+// UserPhoto is a processed profile photo, its variants are stored as files under Path
+type UserPhoto struct {
+	Path     string
+	Variants []string
+}
+
 type User struct {
 	ID                  uuid.UUID      `json:"id"`
 	Name                string         `json:"name"`
 	Email               string         `json:"email"`
 	Password            string         `json:"password"`
 	PhoneNumber         string         `json:"phone_number"`
+	PhoneVerifiedAt     sql.NullTime   `json:"phone_verified_at"`
+	OTPChannel          string         `json:"otp_channel"`
 	Photo               string         `json:"photo"`
+	PhotoVariants       string         `json:"photo_variants"`
 	DOB                 sql.NullTime   `json:"dob"`
 	OTP                 sql.NullString `json:"otp"`
 	Status              string         `json:"status"`
 	ForgotPasswordToken sql.NullString `json:"forgot_password_token"`
-	UserRole            *UserRole      `foreignKey:"ID" associationForeignKey:"UserID"`
+	// Version is incremented on every update, updates of an older version are rejected
+	Version  int64     `json:"version"`
+	UserRole *UserRole `foreignKey:"ID" associationForeignKey:"UserID"`
 	Auditable
 }
 
@@ -49,13 +74,15 @@ func NewUser(
 	return &User{
 		ID:          id,
 		Name:        name,
-		Email:       email,
+		Email:       utils.NormalizeEmail(email),
 		Password:    string(passwordHash),
 		PhoneNumber: phoneNumber,
 		Photo:       photo,
 		DOB:         dob,
 		OTP:         sql.NullString{},
-		Status:      "ACTIVATED",
+		Status:      UserStatusActivated,
+		OTPChannel:  OTPChannelEmail,
+		Version:     1,
 		Auditable:   NewAuditable(createdBy),
 	}
 }
@@ -64,13 +91,14 @@ func NewUser(
 func (model *User) MapUpdateFrom(from *User) *map[string]interface{} {
 	if from == nil {
 		return &map[string]interface{}{
-			"name":         model.Name,
-			"email":        model.Email,
-			"phone_number": model.PhoneNumber,
-			"photo":        model.Photo,
-			"otp":          model.OTP,
-			"status":       model.Status,
-			"updated_at":   model.UpdatedAt,
+			"name":           model.Name,
+			"email":          model.Email,
+			"phone_number":   model.PhoneNumber,
+			"photo":          model.Photo,
+			"photo_variants": model.PhotoVariants,
+			"otp":            model.OTP,
+			"status":         model.Status,
+			"updated_at":     model.UpdatedAt,
 		}
 	}
 
@@ -86,6 +114,9 @@ func (model *User) MapUpdateFrom(from *User) *map[string]interface{} {
 
 	if model.PhoneNumber != from.PhoneNumber {
 		mapped["phone_number"] = from.PhoneNumber
+		// a new number has to be verified again before it receives login codes
+		mapped["phone_verified_at"] = nil
+		mapped["otp_channel"] = OTPChannelEmail
 	}
 
 	if model.DOB != from.DOB {
@@ -94,6 +125,7 @@ func (model *User) MapUpdateFrom(from *User) *map[string]interface{} {
 
 	if (model.Photo != from.Photo) && from.Photo != "" {
 		mapped["photo"] = from.Photo
+		mapped["photo_variants"] = from.PhotoVariants
 	}
 
 	if model.OTP != from.OTP {
@@ -111,3 +143,38 @@ func (model *User) MapUpdateFrom(from *User) *map[string]interface{} {
 	mapped["updated_at"] = time.Now()
 	return &mapped
 }
+
+// SetPhoto sets the processed photo of the user, a nil photo leaves it unchanged
+func (model *User) SetPhoto(photo *UserPhoto) {
+	if photo == nil {
+		return
+	}
+
+	model.Photo = photo.Path
+	model.PhotoVariants = strings.Join(photo.Variants, ",")
+}
+
+// PhotoFiles returns the stored files of the photo keyed by variant size,
+// photos uploaded before variants were generated are returned as the original
+func (model *User) PhotoFiles() map[string]string {
+	files := make(map[string]string)
+	if model.Photo == "" {
+		return files
+	}
+
+	if model.PhotoVariants == "" {
+		files["original"] = model.Photo
+		return files
+	}
+
+	for _, variant := range strings.Split(model.PhotoVariants, ",") {
+		files[strings.TrimSuffix(variant, path.Ext(variant))] = path.Join(model.Photo, variant)
+	}
+
+	return files
+}
+
+// SendsOTPBySMS tells whether the login code of the user goes to its verified phone number
+func (model *User) SendsOTPBySMS() bool {
+	return model.OTPChannel == OTPChannelSMS && model.PhoneVerifiedAt.Valid && model.PhoneNumber != ""
+}
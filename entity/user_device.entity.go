@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gin-starter/utils"
+)
+
+const (
+	userDeviceTableName = "main.user_devices"
+
+	// DevicePlatformAndroid is an android device
+	DevicePlatformAndroid = "android"
+	// DevicePlatformIOS is an iOS device
+	DevicePlatformIOS = "ios"
+	// DevicePlatformWeb is a web browser
+	DevicePlatformWeb = "web"
+)
+
+// DevicePlatforms are the platforms a device can be registered for
+var DevicePlatforms = []string{DevicePlatformAndroid, DevicePlatformIOS, DevicePlatformWeb}
+
+// UserDevice is a device push notifications are delivered to.
+// A token belongs to a single user, the last one to register it.
+type UserDevice struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	// Provider is the push provider which issued the token
+	Provider string `json:"provider"`
+	// Token is the registration token, or the player id on OneSignal
+	Token      string         `json:"token"`
+	Platform   string         `json:"platform"`
+	AppVersion sql.NullString `json:"app_version"`
+	LastSeenAt time.Time      `json:"last_seen_at"`
+	Auditable
+}
+
+// TableName specifies table name
+func (model *UserDevice) TableName() string {
+	return userDeviceTableName
+}
+
+// NewUserDevice creates new user device entity
+func NewUserDevice(
+	id uuid.UUID,
+	userID uuid.UUID,
+	provider string,
+	token string,
+	platform string,
+	appVersion string,
+	createdBy string,
+) *UserDevice {
+	return &UserDevice{
+		ID:         id,
+		UserID:     userID,
+		Provider:   provider,
+		Token:      token,
+		Platform:   platform,
+		AppVersion: utils.StringToNullString(appVersion),
+		LastSeenAt: time.Now(),
+		Auditable:  NewAuditable(createdBy),
+	}
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// UserExport is the read-only projection of a user used for exports.
+// It deliberately has no password, otp or forgot password token field.
+type UserExport struct {
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	PhoneNumber string    `json:"phone_number"`
+	Status      string    `json:"status"`
+	Role        string    `json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,99 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	userImportTableName    = "main.user_imports"
+	userImportRowTableName = "main.user_import_rows"
+
+	// UserImportStatusPending is the status of an import waiting for the background job
+	UserImportStatusPending = "PENDING"
+	// UserImportStatusProcessing is the status of an import being processed
+	UserImportStatusProcessing = "PROCESSING"
+	// UserImportStatusCompleted is the status of a processed import
+	UserImportStatusCompleted = "COMPLETED"
+	// UserImportStatusFailed is the status of an import aborted by an unexpected error
+	UserImportStatusFailed = "FAILED"
+
+	// UserImportRowStatusValid is the status of a row which passed validation
+	UserImportRowStatusValid = "VALID"
+	// UserImportRowStatusInvalid is the status of a row which failed validation
+	UserImportRowStatusInvalid = "INVALID"
+	// UserImportRowStatusCreated is the status of a row whose user was created
+	UserImportRowStatusCreated = "CREATED"
+	// UserImportRowStatusFailed is the status of a row whose user could not be created
+	UserImportRowStatusFailed = "FAILED"
+)
+
+// UserImport defines table user_imports
+type UserImport struct {
+	ID          uuid.UUID        `json:"id"`
+	FileName    string           `json:"file_name"`
+	Status      string           `json:"status"`
+	CreateRoles bool             `json:"create_roles"`
+	TotalRows   int              `json:"total_rows"`
+	CreatedRows int              `json:"created_rows"`
+	FailedRows  int              `json:"failed_rows"`
+	FinishedAt  *time.Time       `json:"finished_at"`
+	Rows        []*UserImportRow `gorm:"foreignKey:UserImportID"`
+	Auditable
+}
+
+// TableName specifies table name
+func (model *UserImport) TableName() string {
+	return userImportTableName
+}
+
+// NewUserImport creates new user import entity
+func NewUserImport(
+	id uuid.UUID,
+	fileName string,
+	createRoles bool,
+	rows []*UserImportRow,
+	createdBy string,
+) *UserImport {
+	failed := 0
+	for _, row := range rows {
+		row.UserImportID = id
+		if row.Status == UserImportRowStatusInvalid {
+			failed++
+		}
+	}
+
+	return &UserImport{
+		ID:          id,
+		FileName:    fileName,
+		Status:      UserImportStatusPending,
+		CreateRoles: createRoles,
+		TotalRows:   len(rows),
+		FailedRows:  failed,
+		Rows:        rows,
+		Auditable:   NewAuditable(createdBy),
+	}
+}
+
+// UserImportRow defines table user_import_rows, one per data row of the imported file
+type UserImportRow struct {
+	ID           uuid.UUID  `json:"id"`
+	UserImportID uuid.UUID  `json:"user_import_id"`
+	RowNumber    int        `json:"row_number"`
+	Name         string     `json:"name"`
+	Email        string     `json:"email"`
+	PhoneNumber  string     `json:"phone_number"`
+	DOB          string     `json:"dob"`
+	Role         string     `json:"role"`
+	Status       string     `json:"status"`
+	Message      string     `json:"message"`
+	UserID       *uuid.UUID `json:"user_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TableName specifies table name
+func (model *UserImportRow) TableName() string {
+	return userImportRowTableName
+}
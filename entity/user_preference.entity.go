@@ -0,0 +1,191 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	userPreferenceTableName = "main.user_preferences"
+
+	// PreferenceLanguage is the language of the messages sent to the user
+	PreferenceLanguage = "language"
+	// PreferenceTimezone is the IANA timezone the dates sent to the user are shown in
+	PreferenceTimezone = "timezone"
+	// PreferenceEmailNotifications opts the user in to notifications by email
+	PreferenceEmailNotifications = "email_notifications"
+	// PreferencePushNotifications opts the user in to push notifications
+	PreferencePushNotifications = "push_notifications"
+	// PreferenceSMSNotifications opts the user in to notifications by SMS
+	PreferenceSMSNotifications = "sms_notifications"
+	// PreferenceMarketingConsent records the consent of the user to receive marketing messages
+	PreferenceMarketingConsent = "marketing_consent"
+
+	// PreferenceTypeBoolean is the type of the preferences holding true or false
+	PreferenceTypeBoolean = "boolean"
+	// PreferenceTypeString is the type of the preferences holding a string
+	PreferenceTypeString = "string"
+)
+
+// PreferenceDefinition describes a known preference key, its type and its default
+type PreferenceDefinition struct {
+	Key     string      `json:"key"`
+	Type    string      `json:"type"`
+	Default interface{} `json:"default"`
+	// Options lists the accepted values of a string preference, empty accepts any value passing validate
+	Options  []string `json:"options,omitempty"`
+	validate func(value string) error
+}
+
+// PreferenceSchema lists the preferences a user can set, keys missing from the schema are rejected
+var PreferenceSchema = []*PreferenceDefinition{
+	{Key: PreferenceLanguage, Type: PreferenceTypeString, Default: "id", Options: []string{"id", "en"}},
+	{Key: PreferenceTimezone, Type: PreferenceTypeString, Default: "Asia/Jakarta", validate: validateTimezone},
+	{Key: PreferenceEmailNotifications, Type: PreferenceTypeBoolean, Default: true},
+	{Key: PreferencePushNotifications, Type: PreferenceTypeBoolean, Default: true},
+	{Key: PreferenceSMSNotifications, Type: PreferenceTypeBoolean, Default: false},
+	{Key: PreferenceMarketingConsent, Type: PreferenceTypeBoolean, Default: false},
+}
+
+// FindPreferenceDefinition finds the definition of a preference key, nil when the key is unknown
+func FindPreferenceDefinition(key string) *PreferenceDefinition {
+	for _, definition := range PreferenceSchema {
+		if definition.Key == key {
+			return definition
+		}
+	}
+
+	return nil
+}
+
+// Parse validates a value of the preference and returns it typed
+func (d *PreferenceDefinition) Parse(value interface{}) (interface{}, error) {
+	switch d.Type {
+	case PreferenceTypeBoolean:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+
+		return nil, fmt.Errorf("%s must be a boolean", d.Key)
+	case PreferenceTypeString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", d.Key)
+		}
+
+		if len(d.Options) > 0 && !containsString(d.Options, s) {
+			return nil, fmt.Errorf("%s must be one of %v", d.Key, d.Options)
+		}
+
+		if d.validate != nil {
+			if err := d.validate(s); err != nil {
+				return nil, err
+			}
+		}
+
+		return s, nil
+	default:
+		return nil, fmt.Errorf("%s has unknown type %s", d.Key, d.Type)
+	}
+}
+
+// UserPreference defines table user_preferences, a preference a user set away from its default
+type UserPreference struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	Key    string    `json:"key"`
+	// Value is the JSON encoding of the typed value
+	Value string `json:"value"`
+	Auditable
+}
+
+// TableName specifies table name
+func (model *UserPreference) TableName() string {
+	return userPreferenceTableName
+}
+
+// NewUserPreference creates new user preference entity, the value must already be parsed by its definition
+func NewUserPreference(
+	id uuid.UUID,
+	userID uuid.UUID,
+	key string,
+	value interface{},
+	createdBy string,
+) (*UserPreference, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserPreference{
+		ID:        id,
+		UserID:    userID,
+		Key:       key,
+		Value:     string(encoded),
+		Auditable: NewAuditable(createdBy),
+	}, nil
+}
+
+// Preferences are the typed preferences of a user keyed by preference key, keys the user never set hold their default
+type Preferences map[string]interface{}
+
+// NewPreferences merges the stored preferences of a user over the defaults of the schema.
+// Stored values of keys removed from the schema, or no longer valid, are ignored.
+func NewPreferences(stored []*UserPreference) Preferences {
+	preferences := make(Preferences, len(PreferenceSchema))
+
+	for _, definition := range PreferenceSchema {
+		preferences[definition.Key] = definition.Default
+	}
+
+	for _, preference := range stored {
+		definition := FindPreferenceDefinition(preference.Key)
+		if definition == nil {
+			continue
+		}
+
+		var value interface{}
+		if err := json.Unmarshal([]byte(preference.Value), &value); err != nil {
+			continue
+		}
+
+		if parsed, err := definition.Parse(value); err == nil {
+			preferences[definition.Key] = parsed
+		}
+	}
+
+	return preferences
+}
+
+// Bool returns a boolean preference, false when the key is not a boolean preference
+func (p Preferences) Bool(key string) bool {
+	b, _ := p[key].(bool)
+	return b
+}
+
+// String returns a string preference, empty when the key is not a string preference
+func (p Preferences) String(key string) string {
+	s, _ := p[key].(string)
+	return s
+}
+
+func validateTimezone(value string) error {
+	if _, err := time.LoadLocation(value); err != nil || value == "" || value == "Local" {
+		return fmt.Errorf("timezone %q is not a valid IANA timezone", value)
+	}
+
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -9,6 +9,8 @@ import (
 	authBuilder "gin-starter/modules/auth/v1/builder"
 	masterBuilder "gin-starter/modules/master/v1/builder"
 	notificationBuilder "gin-starter/modules/notification/v1/builder"
+	privacyBuilder "gin-starter/modules/privacy/v1/builder"
+	scimBuilder "gin-starter/modules/scim/v1/builder"
 	userBuilder "gin-starter/modules/user/v1/builder"
 	pubsubSDK "gin-starter/sdk/pubsub"
 	"gin-starter/utils"
@@ -124,6 +126,10 @@ func BuildHandler(cfg config.Config, router *gin.Engine, db *gorm.DB, redisPool
 	notificationBuilder.BuildNotificationHandler(cfg, router, db, redisPool, awsSession)
 	masterBuilder.BuildMasterHandler(cfg, router, db, redisPool, awsSession)
 	activitiesBuilder.BuildActivitiesHandler(cfg, router, db, redisPool, awsSession)
+	scimBuilder.BuildSCIMHandler(cfg, router, db, redisPool, awsSession)
+	privacyBuilder.BuildPrivacyHandler(cfg, router, db, redisPool, awsSession)
+
+	checkError(userBuilder.SyncPermissions(cfg, db, redisPool))
 }
 
 func checkError(err error) {
@@ -137,7 +143,8 @@ func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, If-Match, Last-Event-ID")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, POST, PUT, DELETE, OPTIONS, PATCH")
 
 		if c.Request.Method == "OPTIONS" {
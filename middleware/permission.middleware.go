@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/permission"
+	"gin-starter/response"
+)
+
+// PermissionChecker tells whether a user holds a permission
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, userID uuid.UUID, name string) (bool, error)
+}
+
+// Permission lets through the admins whose role grants the permission, it runs after Admin.
+// It panics when no module declares the permission, so a route cannot check a name the roles never grant.
+func Permission(checker PermissionChecker, name string) gin.HandlerFunc {
+	if !permission.Default.Has(name) {
+		panic(fmt.Sprintf("permission: a route checks %s which no module declares", name))
+	}
+
+	return func(c *gin.Context) {
+		allowed, err := checker.HasPermission(c, UserID, name)
+		if err != nil {
+			log.Println("[Middleware-Permission]", err)
+			c.JSON(errors.ErrInternalServerError.Code, response.ErrorAPIResponse(errors.ErrInternalServerError.Code, errors.ErrInternalServerError.Message))
+			c.Abort()
+			return
+		}
+
+		if !allowed {
+			c.JSON(http.StatusForbidden, response.ErrorAPIResponse(http.StatusForbidden, "forbidden"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
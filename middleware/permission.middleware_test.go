@@ -0,0 +1,46 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"gin-starter/common/permission"
+	"gin-starter/middleware"
+)
+
+type permissionChecker map[string]bool
+
+func (pc permissionChecker) HasPermission(_ context.Context, _ uuid.UUID, name string) (bool, error) {
+	return pc[name], nil
+}
+
+func TestPermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	permission.Declare("middleware_test", permission.Permission{Name: "test.view"})
+
+	router := gin.New()
+	router.GET("/test", middleware.Permission(permissionChecker{"test.view": true}, "test.view"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.GET("/denied", middleware.Permission(permissionChecker{}, "test.view"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/test", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/denied", nil))
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+
+	assert.Panics(t, func() {
+		middleware.Permission(permissionChecker{}, "test.undeclared")
+	})
+}
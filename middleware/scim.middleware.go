@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"gin-starter/config"
+	"gin-starter/resource"
+)
+
+// SCIM authenticates SCIM provisioning clients using the dedicated bearer credential.
+// The endpoints are disabled when no credential is configured.
+func SCIM(cfg config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := strings.Split(c.Request.Header.Get("Authorization"), "Bearer ")
+
+		if cfg.SCIM.BearerToken == "" || len(tokenString) < 2 ||
+			subtle.ConstantTimeCompare([]byte(tokenString[1]), []byte(cfg.SCIM.BearerToken)) != 1 {
+			c.Header("Content-Type", resource.SCIMContentType)
+			c.JSON(http.StatusUnauthorized, resource.NewSCIMError(http.StatusUnauthorized, "", "unauthorized"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -2,9 +2,13 @@ package builder
 
 import (
 	"gin-starter/app"
+	"gin-starter/common/permission"
 	"gin-starter/config"
 	"gin-starter/modules/activities/v1/repository"
 	"gin-starter/modules/activities/v1/service"
+	userRepo "gin-starter/modules/user/v1/repository"
+	userService "gin-starter/modules/user/v1/service"
+	"gin-starter/utils"
 
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/gin-gonic/gin"
@@ -12,21 +16,38 @@ import (
 	"gorm.io/gorm"
 )
 
+// permissions are the permissions of the activities module
+var permissions = []permission.Permission{
+	{Name: "activity.create", Label: "Create activities"},
+	{Name: "activity.update", Label: "Update activities"},
+	{Name: "activity.delete", Label: "Delete activities"},
+}
+
 // BuildActivitiesHandler builds activities handler
 // starting from handler down to repository or tool.
 func BuildActivitiesHandler(cfg config.Config, router *gin.Engine, db *gorm.DB, redisPool *redis.Pool, awsSession *session.Session) {
+	// Permissions
+	permission.Declare("activities", permissions...)
+	permission.DeclareSystemRole(permission.SuperAdminRole, permission.Names(permissions...)...)
+
+	// Cache
+	cache := utils.NewClient(redisPool)
+
 	// Repository
 	ar := repository.NewActivitiesRepository(db)
+	urr := userRepo.NewUserRoleRepository(db, cache)
+	rr := userRepo.NewRoleRepository(db, cache)
 
 	// Service
 	ac := service.NewActivitiesCreator(cfg, ar)
 	af := service.NewActivitiesFinder(cfg, ar)
 	ad := service.NewActivitiesDeleter(cfg, ar)
 	au := service.NewActivitiesUpdater(cfg, ar)
+	pc := userService.NewPermissionChecker(urr, rr)
 
 	// Handler
 	app.ActivitiesFinderHTTPHandler(cfg, router, af)
-	app.ActivitiesCreatorHTTPHandler(cfg, router, ac)
-	app.ActivitiesDeleterHTTPHandler(cfg, router, ad)
-	app.ActivitiesUpdaterHTTPHandler(cfg, router, au)
+	app.ActivitiesCreatorHTTPHandler(cfg, router, pc, ac)
+	app.ActivitiesDeleterHTTPHandler(cfg, router, pc, ad)
+	app.ActivitiesUpdaterHTTPHandler(cfg, router, pc, au, af)
 }
@@ -2,9 +2,11 @@ package handler
 
 import (
 	"gin-starter/common/errors"
+	"gin-starter/common/query"
 	"gin-starter/modules/activities/v1/service"
 	"gin-starter/resource"
 	"gin-starter/response"
+	"gin-starter/utils"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -25,15 +27,15 @@ func NewActivitiesFinderHandler(
 
 // GetActivities is a handler for getting activities
 func (a *ActivitiesFinderHandler) GetActivities(c *gin.Context) {
-	var request resource.GetActivitiesRequest
-
-	if err := c.ShouldBind(&request); err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+	q, err := query.Parse(c.Request.URL.Query())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
 		c.Abort()
 		return
 	}
 
-	activities, total, err := a.activitiesFinder.GetActivities(c, request.Query, request.Sort, request.Order, request.Limit, request.Offset)
+	activities, page, err := a.activitiesFinder.GetActivities(c, q)
 
 	if err != nil {
 		parseError := errors.ParseError(err)
@@ -50,7 +52,8 @@ func (a *ActivitiesFinderHandler) GetActivities(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", &resource.GetActivitiesResponse{
 		List:  res,
-		Total: total,
+		Total: page.Total,
+		Meta:  resource.NewListMeta(page),
 	}))
 }
 
@@ -73,6 +76,13 @@ func (a *ActivitiesFinderHandler) GetActivityByID(c *gin.Context) {
 		return
 	}
 
+	if activity == nil {
+		c.JSON(errors.ErrRecordNotFound.Code, response.ErrorAPIResponse(errors.ErrRecordNotFound.Code, errors.ErrRecordNotFound.Message))
+		c.Abort()
+		return
+	}
+
+	c.Header("ETag", utils.ETag(activity.Version))
 	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewActivities(activity)))
 }
 
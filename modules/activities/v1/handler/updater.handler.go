@@ -2,25 +2,32 @@ package handler
 
 import (
 	"gin-starter/common/errors"
+	"gin-starter/common/patch"
 	"gin-starter/entity"
 	"gin-starter/modules/activities/v1/service"
 	"gin-starter/resource"
 	"gin-starter/response"
+	"gin-starter/utils"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // ActivitiesUpdaterHandler is handler for activities updater
 type ActivitiesUpdaterHandler struct {
 	activitiesUpdater service.ActivitiesUpdaterUseCase
+	activitiesFinder  service.ActivitiesFinderUseCase
 }
 
+// NewActivitiesUpdaterHandler is a constructor for ActivitiesUpdaterHandler
 func NewActivitiesUpdaterHandler(
 	activitiesUpdater service.ActivitiesUpdaterUseCase,
+	activitiesFinder service.ActivitiesFinderUseCase,
 ) *ActivitiesUpdaterHandler {
 	return &ActivitiesUpdaterHandler{
 		activitiesUpdater: activitiesUpdater,
+		activitiesFinder:  activitiesFinder,
 	}
 }
 
@@ -34,6 +41,13 @@ func (a *ActivitiesUpdaterHandler) UpdateActivities(c *gin.Context) {
 		return
 	}
 
+	version, err := utils.ParseIfMatch(c.GetHeader("If-Match"))
+
+	if err != nil {
+		a.abortUpdate(c, err, request.ID)
+		return
+	}
+
 	activities := entity.NewActivities(
 		request.ID,
 		request.UserID,
@@ -42,11 +56,67 @@ func (a *ActivitiesUpdaterHandler) UpdateActivities(c *gin.Context) {
 		request.ActivitiesType,
 		"system",
 	)
+	activities.Version = version
 
 	if err := a.activitiesUpdater.UpdateActivity(c, activities); err != nil {
+		a.abortUpdate(c, err, request.ID)
+		return
+	}
+
+	c.Header("ETag", utils.ETag(activities.Version))
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
+}
+
+// PatchActivities is a handler for partially updating activities with a JSON merge patch
+func (a *ActivitiesUpdaterHandler) PatchActivities(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	version, err := utils.ParseIfMatch(c.GetHeader("If-Match"))
+
+	if err != nil {
+		a.abortUpdate(c, err, id)
+		return
+	}
+
+	p, err := patch.Bind(c, resource.ActivitiesPatchSchema)
+
+	if err != nil {
 		parseError := errors.ParseError(err)
 		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
 		c.Abort()
 		return
 	}
+
+	activities, err := a.activitiesUpdater.PatchActivity(c, id, version, p)
+
+	if err != nil {
+		a.abortUpdate(c, err, id)
+		return
+	}
+
+	c.Header("ETag", utils.ETag(activities.Version))
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewActivities(activities)))
+}
+
+// abortUpdate writes the error of a rejected update, a version mismatch also carries the current activity and its ETag
+func (a *ActivitiesUpdaterHandler) abortUpdate(c *gin.Context, err error, id uuid.UUID) {
+	parseError := errors.ParseError(err)
+
+	if utils.IsVersionMismatch(err) {
+		if current, findErr := a.activitiesFinder.GetActivitiesByID(c, id); findErr == nil && current != nil {
+			c.Header("ETag", utils.ETag(current.Version))
+			c.JSON(parseError.Code, response.ErrorAPIResponseWithData(parseError.Code, parseError.Message, resource.NewActivities(current)))
+			c.Abort()
+			return
+		}
+	}
+
+	c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+	c.Abort()
 }
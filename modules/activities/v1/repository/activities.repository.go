@@ -2,10 +2,9 @@ package repository
 
 import (
 	"context"
-	"fmt"
-	"gin-starter/common/constant"
+	"gin-starter/common/patch"
+	"gin-starter/common/query"
 	"gin-starter/entity"
-	"log"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,6 +13,23 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// ActivitiesListSchema declares the fields GetActivities can be filtered, sorted and searched on
+var ActivitiesListSchema = &query.Schema{
+	Fields: map[string]string{
+		"user_id":         "user_id",
+		"title":           "title",
+		"activities_type": "activities_type",
+		"created_at":      "created_at",
+	},
+	Search:      []string{"title", "description"},
+	DefaultSort: "-created_at",
+	Keyset:      &query.Keyset{CreatedAt: "created_at", ID: "id"},
+	Table:       "activities.activities",
+}
+
+// ErrVersionConflict is returned when an update is based on an outdated version of the Activities
+var ErrVersionConflict = patch.ErrVersionConflict
+
 // ActivitiesRepository is a repository for Activities
 type ActivitiesRepository struct {
 	db *gorm.DB
@@ -23,12 +39,14 @@ type ActivitiesRepository struct {
 type ActivitiesRepositoryUseCase interface {
 	// GetActivitiesByID is a function to get Activities by id
 	GetActivitiesByID(ctx context.Context, id uuid.UUID) (*entity.Activities, error)
-	// Update is a function to update Activities
+	// Update is a function to update Activities, it fails with ErrVersionConflict when the version is outdated
 	Update(ctx context.Context, Activities *entity.Activities) error
+	// Patch is a function to update columns of Activities, it fails with ErrVersionConflict when the version is outdated
+	Patch(ctx context.Context, id uuid.UUID, version int64, columns map[string]interface{}) error
 	// Create is a function to create Activities
 	Create(ctx context.Context, Activities *entity.Activities) error
 	// GetActivitiess is a function to get Activitiess
-	GetActivities(ctx context.Context, query, sort, order string, limit, offset int) ([]*entity.Activities, int64, error)
+	GetActivities(ctx context.Context, q *query.Query) ([]*entity.Activities, *query.Page, error)
 	// Delete is a function to delete admin Activities
 	Delete(ctx context.Context, id uuid.UUID) error
 	// GetActivitiesByID is a function to get Activities by id
@@ -58,28 +76,45 @@ func (ur *ActivitiesRepository) GetActivitiesByID(ctx context.Context, id uuid.U
 	return result, nil
 }
 
-// Update is a function to update Activities
+// Update is a function to update Activities, it fails with ErrVersionConflict when the stored version differs from Activities.Version
 func (ur *ActivitiesRepository) Update(ctx context.Context, Activities *entity.Activities) error {
 	oldTime := Activities.UpdatedAt
 	Activities.UpdatedAt = time.Now()
+
 	if err := ur.db.
 		WithContext(ctx).
 		Transaction(func(tx *gorm.DB) error {
 			sourceModel := new(entity.Activities)
-			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Find(&sourceModel, Activities.ID).Error; err != nil {
-				log.Println("[ActivitiesRepository - Update]", err)
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", Activities.ID).First(sourceModel).Error; err != nil {
 				return err
 			}
-			if err := tx.Model(&entity.Activities{}).
-				Where(`id`, Activities.ID).
-				UpdateColumns(sourceModel.MapUpdateFrom(Activities)).Error; err != nil {
-				log.Println("[ActivitiesRepository - Update]", err)
-				return err
+
+			if sourceModel.Version != Activities.Version {
+				return ErrVersionConflict
 			}
-			return nil
+
+			updates := sourceModel.MapUpdateFrom(Activities)
+			(*updates)["version"] = sourceModel.Version + 1
+
+			return tx.Model(&entity.Activities{}).
+				Where("id = ?", Activities.ID).
+				UpdateColumns(updates).Error
 		}); err != nil {
 		Activities.UpdatedAt = oldTime
+		return errors.Wrap(err, "[ActivitiesRepository-Update] error when updating Activities data")
 	}
+
+	Activities.Version++
+
+	return nil
+}
+
+// Patch is a function to update columns of Activities, it fails with ErrVersionConflict when the version is outdated
+func (ur *ActivitiesRepository) Patch(ctx context.Context, id uuid.UUID, version int64, columns map[string]interface{}) error {
+	if err := patch.Update(ur.db.WithContext(ctx), new(entity.Activities), id, version, columns); err != nil {
+		return errors.Wrap(err, "[ActivitiesRepository-Patch] error when patching Activities data")
+	}
+
 	return nil
 }
 
@@ -96,42 +131,31 @@ func (ur *ActivitiesRepository) Create(ctx context.Context, Activities *entity.A
 	return nil
 }
 
-// GetActivitiess is a function to get all Activitiess
-func (ur *ActivitiesRepository) GetActivities(ctx context.Context, query, sort, order string, limit, offset int) ([]*entity.Activities, int64, error) {
+// GetActivities is a function to get all Activities
+func (ur *ActivitiesRepository) GetActivities(ctx context.Context, q *query.Query) ([]*entity.Activities, *query.Page, error) {
 	var Activities []*entity.Activities
-	var total int64
 	var gormDB = ur.db.
 		WithContext(ctx).
-		Model(&entity.Activities{})
-
-	gormDB.Count(&total)
-
-	gormDB = gormDB.Limit(limit).
-		Offset(offset)
-
-	if query != "" {
-		gormDB = gormDB.
-			Where("name ILIKE ?", "%"+query+"%")
-	}
+		Model(&entity.Activities{}).
+		Scopes(q.Filter(ActivitiesListSchema))
 
-	if order != constant.Ascending && order != constant.Descending {
-		order = constant.Descending
+	total, estimated, err := q.Count(gormDB, ActivitiesListSchema)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "[ActivitiesRepository-GetActivities] error when counting Activities")
 	}
 
-	if sort == "" {
-		sort = "created_at"
+	if err := gormDB.
+		Scopes(q.Order(ActivitiesListSchema), q.Paginate(ActivitiesListSchema)).
+		Find(&Activities).
+		Error; err != nil {
+		return nil, nil, errors.Wrap(err, "[ActivitiesRepository-GetActivities] error when looking up all Activities")
 	}
 
-	gormDB = gormDB.Order(fmt.Sprintf("%s %s", sort, order))
-
-	if err := gormDB.Find(&Activities).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, 0, nil
-		}
-		return nil, 0, errors.Wrap(err, "[ActivitiesRepository-GetActivitiess] error when looking up all Activities")
-	}
+	page := q.Page(&Activities, total, estimated, func(i int) (time.Time, string) {
+		return Activities[i].CreatedAt, Activities[i].ID.String()
+	})
 
-	return Activities, total, nil
+	return Activities, page, nil
 }
 
 // Delete is a function to delete Activities
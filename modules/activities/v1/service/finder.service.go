@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"gin-starter/common/errors"
+	"gin-starter/common/query"
 	"gin-starter/config"
 	"gin-starter/entity"
 	"gin-starter/modules/activities/v1/repository"
@@ -19,7 +20,7 @@ type ActivitiesFinder struct {
 // ActivitiesFinderUseCase is a use case for the Activities finder
 type ActivitiesFinderUseCase interface {
 	// GetActivities gets all activities
-	GetActivities(ctx context.Context, query, order, sort string, limit, offset int) ([]*entity.Activities, int64, error)
+	GetActivities(ctx context.Context, q *query.Query) ([]*entity.Activities, *query.Page, error)
 	// GetActivitiesByID gets a activities by ID
 	GetActivitiesByID(ctx context.Context, id uuid.UUID) (*entity.Activities, error)
 	// GetActivitiesByUserID gets a activities by user ID
@@ -38,13 +39,17 @@ func NewActivitiesFinder(
 }
 
 // GetActivities gets all activities
-func (a *ActivitiesFinder) GetActivities(ctx context.Context, query, order, sort string, limit, offset int) ([]*entity.Activities, int64, error) {
-	activities, total, err := a.activitiesRepo.GetActivities(ctx, query, order, sort, limit, offset)
+func (a *ActivitiesFinder) GetActivities(ctx context.Context, q *query.Query) ([]*entity.Activities, *query.Page, error) {
+	if err := q.Validate(repository.ActivitiesListSchema); err != nil {
+		return nil, nil, err
+	}
+
+	activities, page, err := a.activitiesRepo.GetActivities(ctx, q)
 	if err != nil {
-		return nil, 0, errors.ErrInternalServerError.Error()
+		return nil, nil, errors.ErrInternalServerError.Error()
 	}
 
-	return activities, total, nil
+	return activities, page, nil
 }
 
 // GetActivitiesByID gets a activities by ID
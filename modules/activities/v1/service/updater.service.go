@@ -2,10 +2,16 @@ package service
 
 import (
 	"context"
+	goErrors "errors"
 	"gin-starter/common/errors"
+	"gin-starter/common/patch"
 	"gin-starter/config"
 	"gin-starter/entity"
 	"gin-starter/modules/activities/v1/repository"
+	"log"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // ActivitiesUpdater is a service for updating activities
@@ -16,8 +22,10 @@ type ActivitiesUpdater struct {
 
 // ActivitiesUpdaterUseCase is a use case for the Activities updater
 type ActivitiesUpdaterUseCase interface {
-	// UpdateActivity updates an activity
+	// UpdateActivity updates an activity, activities.Version must be the version the update is based on
 	UpdateActivity(ctx context.Context, activities *entity.Activities) error
+	// PatchActivity applies a merge patch to an activity, version is the version of the activity the client read
+	PatchActivity(ctx context.Context, id uuid.UUID, version int64, p *patch.Patch) (*entity.Activities, error)
 }
 
 // NewActivitiesUpdater is a constructor for the Activities updater
@@ -34,8 +42,45 @@ func NewActivitiesUpdater(
 // UpdateActivity updates an activity
 func (a *ActivitiesUpdater) UpdateActivity(ctx context.Context, activities *entity.Activities) error {
 	if err := a.activitiesRepo.Update(ctx, activities); err != nil {
-		return errors.ErrInternalServerError.Error()
+		return activitiesUpdateError(err)
 	}
 
 	return nil
 }
+
+// PatchActivity applies a merge patch to an activity, version is the version of the activity the client read
+func (a *ActivitiesUpdater) PatchActivity(ctx context.Context, id uuid.UUID, version int64, p *patch.Patch) (*entity.Activities, error) {
+	if !p.Empty() {
+		if err := a.activitiesRepo.Patch(ctx, id, version, p.Columns()); err != nil {
+			return nil, activitiesUpdateError(err)
+		}
+	}
+
+	activities, err := a.activitiesRepo.GetActivitiesByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if activities == nil {
+		return nil, errors.ErrRecordNotFound.Error()
+	}
+
+	if p.Empty() && activities.Version != version {
+		return nil, errors.ErrVersionMismatch.Error()
+	}
+
+	return activities, nil
+}
+
+// activitiesUpdateError maps a failed update of an activity to the error returned to the client
+func activitiesUpdateError(err error) error {
+	switch {
+	case goErrors.Is(err, repository.ErrVersionConflict):
+		return errors.ErrVersionMismatch.Error()
+	case goErrors.Is(err, gorm.ErrRecordNotFound):
+		return errors.ErrRecordNotFound.Error()
+	}
+
+	log.Println("[ActivitiesUpdater]", err)
+	return errors.ErrInternalServerError.Error()
+}
@@ -2,9 +2,17 @@ package builder
 
 import (
 	"gin-starter/app"
+	"gin-starter/common/interfaces"
 	"gin-starter/config"
 	authRepo "gin-starter/modules/auth/v1/repository"
 	auth "gin-starter/modules/auth/v1/service"
+	notificationRepo "gin-starter/modules/notification/v1/repository"
+	notification "gin-starter/modules/notification/v1/service"
+	"gin-starter/sdk/ldap"
+	"gin-starter/sdk/push"
+	"gin-starter/sdk/sms"
+	"gin-starter/utils"
+	"log"
 
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/gin-gonic/gin"
@@ -15,10 +23,43 @@ import (
 // BuildAuthHandler build auth handlers
 // starting from handler down to repository or tool.
 func BuildAuthHandler(cfg config.Config, router *gin.Engine, db *gorm.DB, redisPool *redis.Pool, awsSession *session.Session) {
+	// Cache
+	cache := utils.NewClient(redisPool)
+
 	// Repository
-	ar := authRepo.NewAuthRepository(db)
+	ar := authRepo.NewAuthRepository(db, cache)
+
+	// CMS Authenticator
+	var authenticator interfaces.Authenticator = auth.NewBcryptAuthenticator(ar)
+
+	if cfg.CMSAuth.Authenticator == "ldap" {
+		ldapAuthenticator, err := ldap.NewAuthenticator(cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		authenticator = ldapAuthenticator
+	}
+
+	// SMS
+	smsSender, err := sms.NewSender(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rateLimitedSender, err := sms.NewRateLimitedSender(cfg, cache, smsSender)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Push devices detached on logout
+	pushProvider, err := push.NewProvider(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dm := notification.NewUserDeviceManager(cfg, notificationRepo.NewUserDeviceRepository(db), pushProvider)
 
-	uc := auth.NewAuthService(cfg, ar)
+	uc := auth.NewAuthService(cfg, ar, authenticator, rateLimitedSender, dm)
 
 	app.AuthHTTPHandler(cfg, router, uc)
 }
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"gin-starter/common/errors"
+	"gin-starter/middleware"
 	"gin-starter/modules/auth/v1/service"
 	"gin-starter/resource"
 	"gin-starter/response"
@@ -91,3 +92,23 @@ func (ah *AuthHandler) LoginCMS(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewLoginResponse(token.Token, false)))
 }
+
+// Logout is a handler for logout, the device of the request stops receiving push notifications
+func (ah *AuthHandler) Logout(c *gin.Context) {
+	var request resource.LogoutRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	if err := ah.authUseCase.Logout(c, middleware.UserID, request.DeviceToken); err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
+}
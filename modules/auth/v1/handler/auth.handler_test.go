@@ -6,7 +6,9 @@ import (
 	"gin-starter/entity"
 	"gin-starter/modules/auth/v1/handler"
 	"gin-starter/modules/auth/v1/service"
+	"gin-starter/sdk/sms"
 	"gin-starter/test/helpers"
+	mockInterfaces "gin-starter/test/mock/common/interfaces"
 	mockRepo "gin-starter/test/mock/modules/auth/repository"
 	"gin-starter/utils"
 	"log"
@@ -119,7 +121,7 @@ func (suite *AuthHandlerTestSuite) BeforeTest(string, string) {
 		},
 	}
 	suite.authRepository = mockRepo.NewMockAuthRepositoryUseCase(suite.mockCtrl)
-	suite.service = service.NewAuthService(suite.cfg, suite.authRepository)
+	suite.service = service.NewAuthService(suite.cfg, suite.authRepository, service.NewBcryptAuthenticator(suite.authRepository), sms.NewFakeSender(), mockInterfaces.NewMockDeviceDetacher(suite.mockCtrl))
 	suite.authHandler = handler.NewAuthHandler(suite.service)
 
 	if err := os.MkdirAll("template/email", os.ModePerm); err != nil {
@@ -2,18 +2,30 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	commonCache "gin-starter/common/cache"
+	"gin-starter/common/interfaces"
 	"gin-starter/entity"
+	userRepo "gin-starter/modules/user/v1/repository"
 	"gin-starter/utils"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+// ErrEmailConflict is returned when a created user has the email of another user which is not deleted
+var ErrEmailConflict = errors.New("email conflict")
+
+// ErrLastSuperAdmin is returned when a role assignment would move the last active super admin to another role
+var ErrLastSuperAdmin = userRepo.ErrLastSuperAdmin
+
 // AuthRepository is a repository for auth
 type AuthRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	cache interfaces.Cacheable
 }
 
 // AuthRepositoryUseCase is a repository for auth
@@ -24,11 +36,20 @@ type AuthRepositoryUseCase interface {
 	GetAdminByEmail(ctx context.Context, email string) (*entity.User, error)
 	// UpdateOTP updates OTP
 	UpdateOTP(ctx context.Context, user *entity.User, otp string) error
+	// GetRoleByName finds a role by name
+	GetRoleByName(ctx context.Context, name string) (*entity.Role, error)
+	// CreateAdmin creates an admin with the given role
+	CreateAdmin(ctx context.Context, user *entity.User, roleID uuid.UUID) error
+	// AssignRole makes the given role the only role of the user, rejected with ErrLastSuperAdmin when it moves the last
+	// active super admin
+	AssignRole(ctx context.Context, userID, roleID uuid.UUID, updatedBy string) error
+	// IsEmailReserved checks whether a deleted user still owns the email
+	IsEmailReserved(ctx context.Context, email string) (bool, error)
 }
 
 // NewAuthRepository returns a auth repository
-func NewAuthRepository(db *gorm.DB) *AuthRepository {
-	return &AuthRepository{db}
+func NewAuthRepository(db *gorm.DB, cache interfaces.Cacheable) *AuthRepository {
+	return &AuthRepository{db, cache}
 }
 
 // GetUserByEmail finds a user by email
@@ -37,8 +58,8 @@ func (ar *AuthRepository) GetUserByEmail(ctx context.Context, email string) (*en
 
 	if err := ar.db.
 		WithContext(ctx).
-		Where("email = ?", email).
-		Find(result).
+		Where("LOWER(email) = ?", utils.NormalizeEmail(email)).
+		First(result).
 		Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -89,3 +110,114 @@ func (ar *AuthRepository) GetAdminByEmail(ctx context.Context, email string) (*e
 
 	return result, nil
 }
+
+// GetRoleByName finds a role by name
+func (ar *AuthRepository) GetRoleByName(ctx context.Context, name string) (*entity.Role, error) {
+	result := new(entity.Role)
+
+	if err := ar.db.
+		WithContext(ctx).
+		Where("name = ?", name).
+		First(result).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "[AuthRepository-GetRoleByName] error while getting role")
+	}
+
+	return result, nil
+}
+
+// CreateAdmin creates an admin with the given role
+func (ar *AuthRepository) CreateAdmin(ctx context.Context, user *entity.User, roleID uuid.UUID) error {
+	if err := ar.db.
+		WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&entity.User{}).Create(user).Error; err != nil {
+				if index, ok := utils.UniqueViolation(err); ok && index == entity.UserEmailUniqueIndex {
+					err = ErrEmailConflict
+				}
+
+				return errors.Wrap(err, "[AuthRepository-CreateAdmin] error while creating user")
+			}
+
+			userRole := entity.NewUserRole(uuid.New(), user.ID, roleID, user.CreatedBy.String)
+			if err := tx.Model(&entity.UserRole{}).Create(userRole).Error; err != nil {
+				return errors.Wrap(err, "[AuthRepository-CreateAdmin] error while creating user role")
+			}
+
+			return nil
+		}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AssignRole makes the given role the only role of the user, rejected with ErrLastSuperAdmin when it moves the last
+// active super admin
+func (ar *AuthRepository) AssignRole(ctx context.Context, userID, roleID uuid.UUID, updatedBy string) error {
+	userRole := new(entity.UserRole)
+
+	find := ar.db.
+		WithContext(ctx).
+		Where("user_id = ?", userID).
+		Limit(1).
+		Find(userRole)
+
+	if err := find.Error; err != nil {
+		return errors.Wrap(err, "[AuthRepository-AssignRole] error while getting user role")
+	}
+
+	if find.RowsAffected > 0 && userRole.RoleID == roleID {
+		return nil
+	}
+
+	if find.RowsAffected > 0 {
+		if err := ar.db.
+			WithContext(ctx).
+			Transaction(func(tx *gorm.DB) error {
+				if err := userRepo.KeepSuperAdmin(tx, userID, roleID); err != nil {
+					return err
+				}
+
+				return tx.Model(&entity.UserRole{}).
+					Where("user_id = ?", userID).
+					Updates(map[string]interface{}{
+						"role_id":    roleID,
+						"updated_by": updatedBy,
+						"updated_at": time.Now(),
+					}).Error
+			}); err != nil {
+			return errors.Wrap(err, "[AuthRepository-AssignRole] error while updating user role")
+		}
+	} else {
+		if err := ar.db.
+			WithContext(ctx).
+			Model(&entity.UserRole{}).
+			Create(entity.NewUserRole(uuid.New(), userID, roleID, updatedBy)).Error; err != nil {
+			return errors.Wrap(err, "[AuthRepository-AssignRole] error while creating user role")
+		}
+	}
+
+	return ar.cache.BulkRemove(fmt.Sprintf(commonCache.UserRoleByUserID, "*"))
+}
+
+// IsEmailReserved checks whether a deleted user still owns the email
+func (ar *AuthRepository) IsEmailReserved(ctx context.Context, email string) (bool, error) {
+	var total int64
+
+	if err := ar.db.
+		WithContext(ctx).
+		Unscoped().
+		Model(&entity.User{}).
+		Where("LOWER(email) = LOWER(?)", email).
+		Where("deleted_at IS NOT NULL").
+		Count(&total).
+		Error; err != nil {
+		return false, errors.Wrap(err, "[AuthRepository-IsEmailReserved] error when counting deleted users")
+	}
+
+	return total > 0, nil
+}
@@ -2,7 +2,9 @@ package repository_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"gin-starter/entity"
 	"gin-starter/modules/auth/v1/repository"
 	"gin-starter/test/helpers"
 	"gin-starter/utils"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -51,7 +54,7 @@ func (s *AuthServiceTestSuite) BeforeTest(string, string) {
 	}
 
 	s.mock = mock
-	s.repo = repository.NewAuthRepository(s.db)
+	s.repo = repository.NewAuthRepository(s.db, nil)
 }
 
 func (s *AuthServiceTestSuite) AfterTest(string, string) {
@@ -65,7 +68,7 @@ func (s *AuthServiceTestSuite) TestGetUserByEmail() {
 		defer s.subReporter.Add(s.T())()
 
 		s.mock.
-			ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "main"."users" WHERE email = $1 AND "users"."deleted_at" IS NULL`)).
+			ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "main"."users" WHERE LOWER(email) = $1 AND "users"."deleted_at" IS NULL ORDER BY "users"."id" LIMIT 1`)).
 			WithArgs(email).
 			WillReturnError(errors.New("error"))
 
@@ -79,7 +82,7 @@ func (s *AuthServiceTestSuite) TestGetUserByEmail() {
 		defer s.subReporter.Add(s.T())()
 
 		s.mock.
-			ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "main"."users" WHERE email = $1 AND "users"."deleted_at" IS NULL`)).
+			ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "main"."users" WHERE LOWER(email) = $1 AND "users"."deleted_at" IS NULL ORDER BY "users"."id" LIMIT 1`)).
 			WithArgs(email).
 			WillReturnError(gorm.ErrRecordNotFound)
 
@@ -93,7 +96,7 @@ func (s *AuthServiceTestSuite) TestGetUserByEmail() {
 		defer s.subReporter.Add(s.T())()
 		dob, _ := utils.DateStringToTime("2006-01-02 00:00:00")
 		s.mock.
-			ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "main"."users" WHERE email = $1 AND "users"."deleted_at" IS NULL`)).
+			ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "main"."users" WHERE LOWER(email) = $1 AND "users"."deleted_at" IS NULL ORDER BY "users"."id" LIMIT 1`)).
 			WithArgs(email).
 			WillReturnRows(
 				sqlmock.
@@ -129,3 +132,21 @@ func (s *AuthServiceTestSuite) TestGetUserByEmail() {
 		s.Nil(err)
 	})
 }
+
+func (s *AuthServiceTestSuite) TestCreateAdmin() {
+	s.Run("fail to create an admin with the email of another user", func() {
+		defer s.subReporter.Add(s.T())()
+
+		s.mock.ExpectBegin()
+		s.mock.
+			ExpectExec(regexp.QuoteMeta(`INSERT INTO "main"."users"`)).
+			WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: entity.UserEmailUniqueIndex})
+		s.mock.ExpectRollback()
+
+		user := entity.NewUser(uuid.New(), "Test", "Test@Mail.com", "ThePassword", sql.NullTime{}, "", "", "directory")
+		err := s.repo.CreateAdmin(context.Background(), user, uuid.New())
+
+		s.True(errors.Is(err, repository.ErrEmailConflict))
+		s.Equal("test@mail.com", user.Email)
+	})
+}
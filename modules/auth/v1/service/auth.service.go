@@ -3,9 +3,12 @@ package service
 import (
 	"bytes"
 	"context"
+	"database/sql"
+	goErrors "errors"
 	"fmt"
 	"gin-starter/common/constant"
 	"gin-starter/common/errors"
+	"gin-starter/common/interfaces"
 	"gin-starter/config"
 	"gin-starter/entity"
 	"gin-starter/modules/auth/v1/repository"
@@ -13,17 +16,23 @@ import (
 	"html/template"
 	"log"
 
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
 const (
 	four = 4
+	// directoryActor is recorded as creator of users provisioned from an external directory
+	directoryActor = "directory"
 )
 
 // AuthService is a service for auth
 type AuthService struct {
-	cfg      config.Config
-	authRepo repository.AuthRepositoryUseCase
+	cfg           config.Config
+	authRepo      repository.AuthRepositoryUseCase
+	authenticator interfaces.Authenticator
+	smsSender     interfaces.SMSSender
+	devices       interfaces.DeviceDetacher
 }
 
 // AuthUseCase is a usecase for auth
@@ -36,16 +45,24 @@ type AuthUseCase interface {
 	GenerateAccessToken(ctx context.Context, user *entity.User) (*entity.Token, error)
 	// GenerateAccessTokenCMS is a function that generates an access token
 	GenerateAccessTokenCMS(ctx context.Context, user *entity.User) (*entity.Token, error)
+	// Logout detaches the device the user signs out from, or every device of the user when no token is given
+	Logout(ctx context.Context, userID uuid.UUID, deviceToken string) error
 }
 
 // NewAuthService is a constructor for AuthService
 func NewAuthService(
 	cfg config.Config,
 	authRepo repository.AuthRepositoryUseCase,
+	authenticator interfaces.Authenticator,
+	smsSender interfaces.SMSSender,
+	devices interfaces.DeviceDetacher,
 ) *AuthService {
 	return &AuthService{
-		cfg:      cfg,
-		authRepo: authRepo,
+		cfg:           cfg,
+		authRepo:      authRepo,
+		authenticator: authenticator,
+		smsSender:     smsSender,
+		devices:       devices,
 	}
 }
 
@@ -77,6 +94,17 @@ func (as *AuthService) AuthValidate(ctx context.Context, email, password string)
 		return nil, err
 	}
 
+	if user.SendsOTPBySMS() {
+		message := fmt.Sprintf("%s is your %s login code.", otp, as.cfg.AppName)
+		err := as.smsSender.Send(ctx, user.PhoneNumber, message)
+		if err == nil {
+			return user, nil
+		}
+
+		// a rate limited or failing provider must not lock the user out, the code is mailed instead
+		log.Println("[AuthService-AuthValidate] falling back to email:", err)
+	}
+
 	t, err := template.ParseFiles("./template/email/send_otp.html")
 	if err != nil {
 		log.Println(fmt.Errorf("failed to load email template: %w", err))
@@ -113,20 +141,93 @@ func (as *AuthService) AuthValidate(ctx context.Context, email, password string)
 
 // AuthValidateCMS is a function that validates the user
 func (as *AuthService) AuthValidateCMS(ctx context.Context, email, password string) (*entity.User, error) {
-	user, err := as.authRepo.GetAdminByEmail(ctx, email)
+	identity, err := as.authenticator.Authenticate(ctx, email, password)
 
 	if err != nil {
-		return user, err
+		return nil, err
 	}
 
-	if user == nil {
-		return nil, entity.ErrPasswordMismatch.Error
+	if identity.User != nil {
+		return identity.User, nil
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	return as.syncDirectoryUser(ctx, identity)
+}
 
+// syncDirectoryUser provisions the admin of an identity verified by an external directory
+// and keeps its role in line with the directory group membership
+func (as *AuthService) syncDirectoryUser(ctx context.Context, identity *entity.AuthIdentity) (*entity.User, error) {
+	var role *entity.Role
+
+	for _, name := range identity.Roles {
+		r, err := as.authRepo.GetRoleByName(ctx, name)
+		if err != nil {
+			return nil, errors.ErrInternalServerError.Error()
+		}
+
+		if r != nil {
+			role = r
+			break
+		}
+
+		log.Println("[AuthService-AuthValidateCMS] mapped role does not exist:", name)
+	}
+
+	if role == nil {
+		return nil, errors.ErrNoCMSAccess.Error()
+	}
+
+	user, err := as.authRepo.GetUserByEmail(ctx, identity.Email)
 	if err != nil {
-		return nil, errors.ErrWrongLoginCredentials.Error()
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if user == nil || user.ID == uuid.Nil {
+		// a deleted account keeps its email until it is purged from the trash
+		reserved, err := as.authRepo.IsEmailReserved(ctx, identity.Email)
+		if err != nil {
+			return nil, errors.ErrInternalServerError.Error()
+		}
+
+		if reserved {
+			return nil, errors.ErrUserDeactivated.Error()
+		}
+
+		// directory users never sign in with a local password
+		user = entity.NewUser(
+			uuid.New(),
+			identity.Name,
+			identity.Email,
+			uuid.NewString(),
+			sql.NullTime{},
+			"",
+			"",
+			directoryActor,
+		)
+
+		if err := as.authRepo.CreateAdmin(ctx, user, role.ID); err != nil {
+			// a concurrent sign in of the same identity created the user first
+			if goErrors.Is(err, repository.ErrEmailConflict) {
+				return nil, errors.ErrEmailAlreadyUsed.Error()
+			}
+
+			return nil, errors.ErrInternalServerError.Error()
+		}
+
+		return user, nil
+	}
+
+	if user.Status != entity.UserStatusActivated {
+		return nil, errors.ErrUserDeactivated.Error()
+	}
+
+	if err := as.authRepo.AssignRole(ctx, user.ID, role.ID, directoryActor); err != nil {
+		// the last active super admin keeps its role until another super admin is active
+		if !goErrors.Is(err, repository.ErrLastSuperAdmin) {
+			return nil, errors.ErrInternalServerError.Error()
+		}
+
+		log.Printf("[AuthService-AuthValidateCMS] role of %s kept, it is the last active super admin", user.ID)
 	}
 
 	return user, nil
@@ -157,3 +258,9 @@ func (as *AuthService) GenerateAccessTokenCMS(ctx context.Context, user *entity.
 		Token: token,
 	}, nil
 }
+
+// Logout detaches the device the user signs out from, or every device of the user when no token is given.
+// The access tokens are stateless, they stay valid until they expire.
+func (as *AuthService) Logout(ctx context.Context, userID uuid.UUID, deviceToken string) error {
+	return as.devices.DetachDevices(ctx, userID, deviceToken)
+}
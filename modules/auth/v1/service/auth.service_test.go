@@ -3,17 +3,22 @@ package service_test
 import (
 	"context"
 	"database/sql"
+	"gin-starter/common/errors"
 	"gin-starter/config"
 	"gin-starter/entity"
+	"gin-starter/modules/auth/v1/repository"
 	"gin-starter/modules/auth/v1/service"
+	mockInterfaces "gin-starter/test/mock/common/interfaces"
 	mockRepo "gin-starter/test/mock/modules/auth/repository"
 	"gin-starter/utils"
 	"log"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	pkgErrors "github.com/pkg/errors"
 	"github.com/stretchr/testify/suite"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -24,6 +29,8 @@ type AuthServiceTestSuite struct {
 
 	cfg            config.Config
 	authRepository *mockRepo.MockAuthRepositoryUseCase
+	smsSender      *mockInterfaces.MockSMSSender
+	deviceDetacher *mockInterfaces.MockDeviceDetacher
 	authService    *service.AuthService
 }
 
@@ -36,10 +43,15 @@ func (suite *AuthServiceTestSuite) BeforeTest(string, string) {
 
 	suite.cfg = config.Config{}
 	suite.authRepository = mockRepo.NewMockAuthRepositoryUseCase(suite.mockCtrl)
+	suite.smsSender = mockInterfaces.NewMockSMSSender(suite.mockCtrl)
+	suite.deviceDetacher = mockInterfaces.NewMockDeviceDetacher(suite.mockCtrl)
 
 	suite.authService = service.NewAuthService(
 		suite.cfg,
 		suite.authRepository,
+		service.NewBcryptAuthenticator(suite.authRepository),
+		suite.smsSender,
+		suite.deviceDetacher,
 	)
 }
 
@@ -111,3 +123,151 @@ func (suite *AuthServiceTestSuite) TestAuthService_AuthValidate() {
 		suite.Nil(err)
 	})
 }
+
+func (suite *AuthServiceTestSuite) TestAuthService_AuthValidate_SMS() {
+	email := "test@mail.com"
+	password := "test123"
+	passwordHash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+
+	newUser := func() *entity.User {
+		return &entity.User{
+			ID:              uuid.New(),
+			Email:           email,
+			Password:        string(passwordHash),
+			PhoneNumber:     "+6281234567890",
+			PhoneVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			OTPChannel:      entity.OTPChannelSMS,
+		}
+	}
+
+	suite.Run("successfully send the login code by sms", func() {
+		user := newUser()
+		suite.authRepository.EXPECT().GetUserByEmail(context.Background(), email).Return(user, nil)
+		suite.authRepository.EXPECT().UpdateOTP(context.Background(), user, gomock.Any()).Return(nil)
+		suite.smsSender.EXPECT().Send(context.Background(), user.PhoneNumber, gomock.Any()).Return(nil)
+
+		validate, err := suite.authService.AuthValidate(context.Background(), email, password)
+
+		suite.Nil(err)
+		suite.Equal(user, validate)
+	})
+
+	suite.Run("fall back to email when the sms is rate limited", func() {
+		if err := os.MkdirAll("template/email", os.ModePerm); err != nil {
+			panic(err)
+		}
+
+		if err := os.WriteFile("template/email/send_otp.html", []byte("{{.OTP}}"), os.ModePerm); err != nil {
+			panic(err)
+		}
+
+		defer func() {
+			if err := os.RemoveAll("template"); err != nil {
+				panic(err)
+			}
+		}()
+
+		user := newUser()
+		suite.authRepository.EXPECT().GetUserByEmail(context.Background(), email).Return(user, nil)
+		suite.authRepository.EXPECT().UpdateOTP(context.Background(), user, gomock.Any()).Return(nil)
+		suite.smsSender.EXPECT().Send(context.Background(), user.PhoneNumber, gomock.Any()).Return(errors.ErrSMSRateLimited.Error())
+
+		validate, err := suite.authService.AuthValidate(context.Background(), email, password)
+
+		suite.Nil(err)
+		suite.Equal(user, validate)
+	})
+}
+
+func (suite *AuthServiceTestSuite) TestAuthService_AuthValidateCMS_Directory() {
+	identity := &entity.AuthIdentity{
+		Email: "admin@corp.example",
+		Name:  "Directory Admin",
+		Roles: []string{"Missing Role", "Super Admin"},
+	}
+	role := &entity.Role{ID: uuid.New(), Name: "Super Admin"}
+
+	newService := func() (*service.AuthService, *mockInterfaces.MockAuthenticator) {
+		authenticator := mockInterfaces.NewMockAuthenticator(suite.mockCtrl)
+		return service.NewAuthService(suite.cfg, suite.authRepository, authenticator, suite.smsSender, suite.deviceDetacher), authenticator
+	}
+
+	suite.Run("provisions a new admin with the first existing mapped role", func() {
+		authService, authenticator := newService()
+
+		authenticator.EXPECT().Authenticate(context.Background(), identity.Email, "secret").Return(identity, nil)
+		suite.authRepository.EXPECT().GetRoleByName(context.Background(), "Missing Role").Return(nil, nil)
+		suite.authRepository.EXPECT().GetRoleByName(context.Background(), "Super Admin").Return(role, nil)
+		suite.authRepository.EXPECT().GetUserByEmail(context.Background(), identity.Email).Return(&entity.User{}, nil)
+		suite.authRepository.EXPECT().IsEmailReserved(context.Background(), identity.Email).Return(false, nil)
+		suite.authRepository.EXPECT().CreateAdmin(context.Background(), gomock.Any(), role.ID).Return(nil)
+
+		user, err := authService.AuthValidateCMS(context.Background(), identity.Email, "secret")
+
+		suite.Nil(err)
+		suite.Equal(identity.Email, user.Email)
+		suite.Equal(identity.Name, user.Name)
+	})
+
+	suite.Run("syncs the role of an existing admin", func() {
+		authService, authenticator := newService()
+		existing := &entity.User{ID: uuid.New(), Email: identity.Email, Status: entity.UserStatusActivated}
+
+		authenticator.EXPECT().Authenticate(context.Background(), identity.Email, "secret").Return(identity, nil)
+		suite.authRepository.EXPECT().GetRoleByName(context.Background(), "Missing Role").Return(nil, nil)
+		suite.authRepository.EXPECT().GetRoleByName(context.Background(), "Super Admin").Return(role, nil)
+		suite.authRepository.EXPECT().GetUserByEmail(context.Background(), identity.Email).Return(existing, nil)
+		suite.authRepository.EXPECT().AssignRole(context.Background(), existing.ID, role.ID, gomock.Any()).Return(nil)
+
+		user, err := authService.AuthValidateCMS(context.Background(), identity.Email, "secret")
+
+		suite.Nil(err)
+		suite.Equal(existing, user)
+	})
+
+	suite.Run("keeps the role of the last active super admin", func() {
+		authService, authenticator := newService()
+		existing := &entity.User{ID: uuid.New(), Email: identity.Email, Status: entity.UserStatusActivated}
+
+		authenticator.EXPECT().Authenticate(context.Background(), identity.Email, "secret").Return(identity, nil)
+		suite.authRepository.EXPECT().GetRoleByName(context.Background(), "Missing Role").Return(nil, nil)
+		suite.authRepository.EXPECT().GetRoleByName(context.Background(), "Super Admin").Return(role, nil)
+		suite.authRepository.EXPECT().GetUserByEmail(context.Background(), identity.Email).Return(existing, nil)
+		suite.authRepository.EXPECT().
+			AssignRole(context.Background(), existing.ID, role.ID, gomock.Any()).
+			Return(pkgErrors.Wrap(repository.ErrLastSuperAdmin, "assign"))
+
+		user, err := authService.AuthValidateCMS(context.Background(), identity.Email, "secret")
+
+		suite.Nil(err)
+		suite.Equal(existing, user)
+	})
+
+	suite.Run("rejects a directory user without mapped role", func() {
+		authService, authenticator := newService()
+
+		authenticator.EXPECT().Authenticate(context.Background(), identity.Email, "secret").
+			Return(&entity.AuthIdentity{Email: identity.Email}, nil)
+
+		user, err := authService.AuthValidateCMS(context.Background(), identity.Email, "secret")
+
+		suite.Nil(user)
+		suite.Equal(errors.ErrNoCMSAccess.Error(), err)
+	})
+}
+
+func (suite *AuthServiceTestSuite) TestAuthService_Logout() {
+	userID := uuid.New()
+
+	suite.Run("detaches the device the user signs out from", func() {
+		suite.deviceDetacher.EXPECT().DetachDevices(context.Background(), userID, "device-token").Return(nil)
+
+		suite.Nil(suite.authService.Logout(context.Background(), userID, "device-token"))
+	})
+
+	suite.Run("fails when the devices cannot be detached", func() {
+		suite.deviceDetacher.EXPECT().DetachDevices(context.Background(), userID, "").Return(errors.ErrInternalServerError.Error())
+
+		suite.Equal(errors.ErrInternalServerError.Error(), suite.authService.Logout(context.Background(), userID, ""))
+	})
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"gin-starter/common/errors"
+	"gin-starter/entity"
+	"gin-starter/modules/auth/v1/repository"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptAuthenticator verifies CMS credentials against the password hashes stored in the database
+type BcryptAuthenticator struct {
+	authRepo repository.AuthRepositoryUseCase
+}
+
+// NewBcryptAuthenticator is a constructor for BcryptAuthenticator
+func NewBcryptAuthenticator(authRepo repository.AuthRepositoryUseCase) *BcryptAuthenticator {
+	return &BcryptAuthenticator{
+		authRepo: authRepo,
+	}
+}
+
+// Authenticate verifies the credentials against the admin password hash
+func (ba *BcryptAuthenticator) Authenticate(ctx context.Context, email, password string) (*entity.AuthIdentity, error) {
+	user, err := ba.authRepo.GetAdminByEmail(ctx, email)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, entity.ErrPasswordMismatch.Error
+	}
+
+	// an invited admin only signs in once it has accepted the invitation
+	if user.Status == entity.UserStatusPending {
+		return nil, errors.ErrUserDeactivated.Error()
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+
+	if err != nil {
+		return nil, errors.ErrWrongLoginCredentials.Error()
+	}
+
+	return &entity.AuthIdentity{
+		Email: user.Email,
+		Name:  user.Name,
+		User:  user,
+	}, nil
+}
@@ -2,10 +2,12 @@ package builder
 
 import (
 	"gin-starter/app"
+	"gin-starter/common/upload"
 	"gin-starter/config"
 	"gin-starter/modules/master/v1/repository"
 	"gin-starter/modules/master/v1/service"
 	"gin-starter/sdk/gcs"
+	"log"
 
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/gin-gonic/gin"
@@ -21,8 +23,17 @@ func BuildMasterHandler(cfg config.Config, router *gin.Engine, db *gorm.DB, redi
 	dr := repository.NewDistrictRepository(db)
 	vr := repository.NewVillageRepository(db)
 	rr := repository.NewRegencyRepository(db)
-	cloudStorage := gcs.NewGoogleCloudStorage(cfg)
-	// cloudStorage := aws.NewS3Bucket(cfg, awsSession)
+
+	// Upload Policy
+	scanner, err := upload.NewScanner(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	guard := upload.NewGuard(upload.Policies(cfg), scanner)
+
+	// Cloud Storage
+	cloudStorage := upload.NewStorage(guard, gcs.NewGoogleCloudStorage(cfg))
+	// cloudStorage := upload.NewStorage(guard, aws.NewS3Bucket(cfg, awsSession))
 
 	// Service
 	mc := service.NewMasterCreator(cfg, cloudStorage)
@@ -1,23 +1,69 @@
 package builder
 
 import (
+	"context"
+	"log"
+	"time"
+
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/gin-gonic/gin"
 	"github.com/gomodule/redigo/redis"
 	"gorm.io/gorm"
 
 	"gin-starter/app"
+	"gin-starter/common/permission"
 	"gin-starter/config"
 	"gin-starter/modules/notification/v1/pubsub/handler"
 	"gin-starter/modules/notification/v1/repository"
 	"gin-starter/modules/notification/v1/service"
+	userRepo "gin-starter/modules/user/v1/repository"
+	userService "gin-starter/modules/user/v1/service"
+	"gin-starter/sdk/push"
+	"gin-starter/utils"
 )
 
+// permissions are the permissions of the notification module
+var permissions = []permission.Permission{
+	{Name: "notification.create", Label: "Send notifications"},
+	{Name: "notification.device.view", Label: "View user devices"},
+	{Name: "notification.template.view", Label: "View notification templates"},
+	{Name: "notification.template.manage", Label: "Manage notification templates"},
+	{Name: "notification.schedule.view", Label: "View scheduled notifications"},
+	{Name: "notification.schedule.manage", Label: "Schedule notifications"},
+}
+
 // BuildNotificationHandler build user handlers
 // starting from handler down to repository or tool.
 func BuildNotificationHandler(cfg config.Config, router *gin.Engine, db *gorm.DB, redisPool *redis.Pool, awsSession *session.Session) {
+	// Permissions
+	permission.Declare("notification", permissions...)
+	permission.DeclareSystemRole(permission.SuperAdminRole, permission.Names(permissions...)...)
+
+	// Cache
+	cache := utils.NewClient(redisPool)
+
 	// Repository
 	notificationRp := repository.NewNotificationRepository(db)
+	templateRp := repository.NewNotificationTemplateRepository(db)
+	scheduledRp := repository.NewScheduledNotificationRepository(db)
+	userDeviceRp := repository.NewUserDeviceRepository(db)
+	eventRp := repository.NewNotificationEventRepository(redisPool, cfg.Stream.Length)
+	ur := userRepo.NewUserRepository(db)
+	upr := userRepo.NewUserPreferenceRepository(db, cache)
+	urr := userRepo.NewUserRoleRepository(db, cache)
+	rr := userRepo.NewRoleRepository(db, cache)
+
+	// Preferences of the notified users
+	pm := userService.NewUserPreferenceManager(cfg, ur, upr)
+
+	// Permissions of the admins
+	pc := userService.NewPermissionChecker(urr, rr)
+
+	// Push
+	pushProvider, err := push.NewProvider(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	nf := service.NewNotificationFinder(
 		cfg,
@@ -26,15 +72,58 @@ func BuildNotificationHandler(cfg config.Config, router *gin.Engine, db *gorm.DB
 	nu := service.NewNotificationUpdater(
 		cfg,
 		notificationRp,
+		eventRp,
 	)
 	nc := service.NewNotificationCreator(
 		cfg,
 		notificationRp,
+		templateRp,
+		userDeviceRp,
+		eventRp,
+		pm,
+		pushProvider,
 	)
+	tm := service.NewNotificationTemplateManager(
+		cfg,
+		templateRp,
+	)
+	sc := service.NewNotificationScheduler(
+		cfg,
+		scheduledRp,
+		templateRp,
+	)
+	sw := service.NewScheduledNotificationWorker(
+		cfg,
+		scheduledRp,
+		nc,
+	)
+	ns := service.NewNotificationStreamer(
+		cfg,
+		eventRp,
+		notificationRp,
+	)
+	dm := service.NewUserDeviceManager(
+		cfg,
+		userDeviceRp,
+		pushProvider,
+	)
+
+	heartbeat, err := time.ParseDuration(cfg.Stream.Heartbeat)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Background job
+	go ns.RunSubscriber(context.Background())
+	go sw.Run(context.Background())
 
-	app.NotificationFinderHTTPHandler(cfg, router, nf, nu)
-	app.NotificationCreatorHTTPHandler(cfg, router, nc)
+	app.NotificationFinderHTTPHandler(cfg, router, nf)
+	app.NotificationCreatorHTTPHandler(cfg, router, pc, nc)
 	app.NotificationUpdaterHTTPHandler(cfg, router, nu)
+	app.UserDeviceManagerHTTPHandler(cfg, router, pc, dm)
+	app.NotificationStreamHTTPHandler(cfg, router, ns, heartbeat)
+	app.NotificationTemplateManagerHTTPHandler(cfg, router, pc, tm)
+	app.NotificationSchedulerHTTPHandler(cfg, router, pc, sc)
 }
 
 // BuildSendEmailPubsubHandler is used to build the pubsub handler.
@@ -4,7 +4,9 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
+	"gin-starter/common/errors"
 	"gin-starter/modules/notification/v1/service"
 	"gin-starter/resource"
 	"gin-starter/response"
@@ -31,6 +33,11 @@ func (cf *NotificationCreatorHandler) CreateNotification(c *gin.Context) {
 		return
 	}
 
+	if request.TemplateKey != "" {
+		cf.sendTemplate(c, &request)
+		return
+	}
+
 	if err := cf.notificationCreator.InsertNotification(
 		c,
 		request.UserID,
@@ -39,6 +46,7 @@ func (cf *NotificationCreatorHandler) CreateNotification(c *gin.Context) {
 		request.Type,
 		request.Extra,
 		false,
+		request.Platforms...,
 	); err != nil {
 		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
 		c.Abort()
@@ -47,3 +55,27 @@ func (cf *NotificationCreatorHandler) CreateNotification(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
 }
+
+// sendTemplate sends the template of the request to its user, or to everyone when the request has no user
+func (cf *NotificationCreatorHandler) sendTemplate(c *gin.Context, request *resource.CreateNotificationRequest) {
+	userID := uuid.Nil
+	if request.UserID != "" {
+		id, err := uuid.Parse(request.UserID)
+		if err != nil {
+			c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+			c.Abort()
+			return
+		}
+
+		userID = id
+	}
+
+	if err := cf.notificationCreator.SendTemplate(c, userID, request.TemplateKey, request.Data, request.Platforms...); err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"gin-starter/common/errors"
+	"gin-starter/middleware"
+	"gin-starter/modules/notification/v1/service"
+	"gin-starter/resource"
+	"gin-starter/response"
+)
+
+// UserDeviceManagerHandler is a handler for the devices push notifications are delivered to
+type UserDeviceManagerHandler struct {
+	deviceManager service.UserDeviceManagerUseCase
+}
+
+// NewUserDeviceManagerHandler is a constructor for UserDeviceManagerHandler
+func NewUserDeviceManagerHandler(
+	deviceManager service.UserDeviceManagerUseCase,
+) *UserDeviceManagerHandler {
+	return &UserDeviceManagerHandler{
+		deviceManager: deviceManager,
+	}
+}
+
+// RegisterDevice is a handler for registering a device of the signed in user
+func (dm *UserDeviceManagerHandler) RegisterDevice(c *gin.Context) {
+	var request resource.RegisterDeviceRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	device, err := dm.deviceManager.RegisterDevice(c, middleware.UserID, request.Token, request.Platform, request.AppVersion)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewUserDeviceResponse(device)))
+}
+
+// UnregisterDevice is a handler for unregistering a device of the signed in user
+func (dm *UserDeviceManagerHandler) UnregisterDevice(c *gin.Context) {
+	var request resource.UnregisterDeviceRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	if err := dm.deviceManager.UnregisterDevice(c, middleware.UserID, request.Token); err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
+}
+
+// GetUserDevices is a handler for getting the devices of a user in the CMS
+func (dm *UserDeviceManagerHandler) GetUserDevices(c *gin.Context) {
+	var request resource.GetUserByIDRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	userID, err := uuid.Parse(request.ID)
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	devices, err := dm.deviceManager.GetUserDevices(c, userID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewGetUserDevicesResponse(devices)))
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"gin-starter/common/errors"
+	"gin-starter/common/query"
 	"gin-starter/entity"
 	"gin-starter/middleware"
 	service2 "gin-starter/modules/notification/v1/service"
@@ -13,41 +15,30 @@ import (
 )
 
 type NotificationFinderHandler struct {
-	notificationFinder  service2.NotificationFinderUseCase
-	notificationUpdater service2.NotificationUpdaterUseCase
+	notificationFinder service2.NotificationFinderUseCase
 }
 
 func NewNotificationFinderHandler(
 	notificationFinder service2.NotificationFinderUseCase,
-	notificationUpdater service2.NotificationUpdaterUseCase,
 ) *NotificationFinderHandler {
 	return &NotificationFinderHandler{
-		notificationFinder:  notificationFinder,
-		notificationUpdater: notificationUpdater,
+		notificationFinder: notificationFinder,
 	}
 }
 
 func (cf *NotificationFinderHandler) GetNotification(c *gin.Context) {
-	var request resource.GetNotificationsRequest
-
-	if err := c.ShouldBind(&request); err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+	q, err := query.Parse(c.Request.URL.Query())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
 		c.Abort()
 		return
 	}
 
-	notifications, total, err := cf.notificationFinder.GetNotification(
-		c,
-		middleware.UserID,
-		request.Sort,
-		request.Order,
-		request.Limit,
-		request.Offset,
-	)
-
+	notifications, page, err := cf.notificationFinder.GetNotification(c, middleware.UserID, q)
 	if err != nil {
-		c.JSON(http.StatusBadRequest,
-			response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
 		c.Abort()
 		return
 	}
@@ -65,16 +56,10 @@ func (cf *NotificationFinderHandler) GetNotification(c *gin.Context) {
 		res = append(res, resource.NewNotificationResponse(n, extraData))
 	}
 
-	if err := cf.notificationUpdater.UpdateReadNotification(c, middleware.UserID); err != nil {
-		c.JSON(http.StatusBadRequest,
-			response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
-		c.Abort()
-		return
-	}
-
 	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", &resource.GetNotificationsResponse{
 		List:  res,
-		Total: total,
+		Total: page.Total,
+		Meta:  resource.NewListMeta(page),
 	}))
 }
 
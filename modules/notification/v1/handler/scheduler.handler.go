@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/query"
+	"gin-starter/entity"
+	"gin-starter/middleware"
+	"gin-starter/modules/notification/v1/service"
+	"gin-starter/resource"
+	"gin-starter/response"
+)
+
+// NotificationSchedulerHandler is a handler for the notifications scheduled in the CMS
+type NotificationSchedulerHandler struct {
+	notificationScheduler service.NotificationSchedulerUseCase
+}
+
+// NewNotificationSchedulerHandler is a constructor for NotificationSchedulerHandler
+func NewNotificationSchedulerHandler(
+	notificationScheduler service.NotificationSchedulerUseCase,
+) *NotificationSchedulerHandler {
+	return &NotificationSchedulerHandler{
+		notificationScheduler: notificationScheduler,
+	}
+}
+
+// GetScheduledNotifications is a handler for listing the scheduled notifications
+func (ns *NotificationSchedulerHandler) GetScheduledNotifications(c *gin.Context) {
+	q, err := query.Parse(c.Request.URL.Query())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	scheduled, page, err := ns.notificationScheduler.GetScheduledNotifications(c, q)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	res := make([]*resource.ScheduledNotification, 0, len(scheduled))
+	for _, s := range scheduled {
+		res = append(res, resource.NewScheduledNotificationResponse(s))
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", &resource.GetScheduledNotificationsResponse{
+		List:  res,
+		Total: page.Total,
+		Meta:  resource.NewListMeta(page),
+	}))
+}
+
+// GetScheduledNotificationByID is a handler for getting a scheduled notification
+func (ns *NotificationSchedulerHandler) GetScheduledNotificationByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	scheduled, err := ns.notificationScheduler.GetScheduledNotificationByID(c, id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewScheduledNotificationResponse(scheduled)))
+}
+
+// GetScheduledNotificationRuns is a handler for listing the runs of a scheduled notification with their delivery results
+func (ns *NotificationSchedulerHandler) GetScheduledNotificationRuns(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	q, err := query.Parse(c.Request.URL.Query())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	runs, page, err := ns.notificationScheduler.GetScheduledNotificationRuns(c, id, q)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	res := make([]*resource.ScheduledNotificationRun, 0, len(runs))
+	for _, run := range runs {
+		res = append(res, resource.NewScheduledNotificationRunResponse(run))
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", &resource.GetScheduledNotificationRunsResponse{
+		List:  res,
+		Total: page.Total,
+		Meta:  resource.NewListMeta(page),
+	}))
+}
+
+// ScheduleNotification is a handler for scheduling a notification at a time or on a recurrence
+func (ns *NotificationSchedulerHandler) ScheduleNotification(c *gin.Context) {
+	actorID := middleware.UserID
+
+	var request resource.ScheduleNotificationRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	userID := uuid.Nil
+	if request.UserID != "" {
+		id, err := uuid.Parse(request.UserID)
+		if err != nil {
+			c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+			c.Abort()
+			return
+		}
+
+		userID = id
+	}
+
+	scheduled := entity.NewScheduledNotification(
+		uuid.New(),
+		userID,
+		request.TemplateKey,
+		request.Data,
+		request.Title,
+		request.Message,
+		request.Type,
+		request.Extra,
+		request.Platforms,
+		actorID.String(),
+	)
+
+	scheduled, err := ns.notificationScheduler.ScheduleNotification(c, scheduled, request.SendAt, request.Cron, request.Timezone)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewScheduledNotificationResponse(scheduled)))
+}
+
+// RescheduleNotification is a handler for replacing the schedule of a notification
+func (ns *NotificationSchedulerHandler) RescheduleNotification(c *gin.Context) {
+	actorID := middleware.UserID
+
+	var request resource.RescheduleNotificationRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	scheduled, err := ns.notificationScheduler.RescheduleNotification(c, id, request.SendAt, request.Cron, request.Timezone, actorID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewScheduledNotificationResponse(scheduled)))
+}
+
+// CancelScheduledNotification is a handler for cancelling the next runs of a scheduled notification
+func (ns *NotificationSchedulerHandler) CancelScheduledNotification(c *gin.Context) {
+	actorID := middleware.UserID
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	scheduled, err := ns.notificationScheduler.CancelScheduledNotification(c, id, actorID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewScheduledNotificationResponse(scheduled)))
+}
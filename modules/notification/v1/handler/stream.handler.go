@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"gin-starter/common/errors"
+	"gin-starter/entity"
+	"gin-starter/middleware"
+	"gin-starter/modules/notification/v1/service"
+	"gin-starter/resource"
+	"gin-starter/response"
+)
+
+const (
+	// notificationStreamRetry is the delay in milliseconds the clients wait before reconnecting
+	notificationStreamRetry = 3000
+	// notificationEventUnreadCount is the event carrying the unread count of the user
+	notificationEventUnreadCount = "unread_count"
+)
+
+// NotificationStreamHandler is a handler streaming the notifications through server-sent events
+type NotificationStreamHandler struct {
+	notificationStreamer service.NotificationStreamerUseCase
+	heartbeat            time.Duration
+}
+
+// NewNotificationStreamHandler is a constructor for NotificationStreamHandler
+func NewNotificationStreamHandler(
+	notificationStreamer service.NotificationStreamerUseCase,
+	heartbeat time.Duration,
+) *NotificationStreamHandler {
+	return &NotificationStreamHandler{
+		notificationStreamer: notificationStreamer,
+		heartbeat:            heartbeat,
+	}
+}
+
+// Stream streams the new notifications and the unread count of the user as server-sent events.
+// The unread count is sent first and after every event, idle streams receive a heartbeat comment.
+// Clients resume after the Last-Event-ID header, or the last_event_id query for clients unable to set headers.
+func (sh *NotificationStreamHandler) Stream(c *gin.Context) {
+	userID := middleware.UserID
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	ctx := c.Request.Context()
+
+	events, err := sh.notificationStreamer.Stream(ctx, userID, lastEventID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	total, err := sh.notificationStreamer.CountUnread(ctx, userID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", notificationStreamRetry)
+	writeNotificationEvent(c.Writer, "", notificationEventUnreadCount, &resource.CountUnreadNotificationsResponse{Total: total})
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(sh.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+			c.Writer.Flush()
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			switch event.Type {
+			case entity.NotificationEventCreated:
+				writeNotificationEvent(c.Writer, event.ID, event.Type, resource.NewNotificationResponse(event.Notification, nil))
+			case entity.NotificationEventRead, entity.NotificationEventArchived, entity.NotificationEventDeleted:
+				writeNotificationEvent(c.Writer, event.ID, event.Type, resource.NewNotificationStateEvent(event))
+			}
+
+			if total, err := sh.notificationStreamer.CountUnread(ctx, userID); err == nil {
+				writeNotificationEvent(c.Writer, "", notificationEventUnreadCount, &resource.CountUnreadNotificationsResponse{Total: total})
+			}
+
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeNotificationEvent writes a server-sent event with its data encoded as JSON, without id when empty
+func writeNotificationEvent(w io.Writer, id, event string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+}
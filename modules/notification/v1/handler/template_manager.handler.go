@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/query"
+	"gin-starter/entity"
+	"gin-starter/middleware"
+	"gin-starter/modules/notification/v1/service"
+	"gin-starter/resource"
+	"gin-starter/response"
+)
+
+// NotificationTemplateManagerHandler is a handler for the notification templates managed in the CMS
+type NotificationTemplateManagerHandler struct {
+	templateManager service.NotificationTemplateManagerUseCase
+}
+
+// NewNotificationTemplateManagerHandler is a constructor for NotificationTemplateManagerHandler
+func NewNotificationTemplateManagerHandler(
+	templateManager service.NotificationTemplateManagerUseCase,
+) *NotificationTemplateManagerHandler {
+	return &NotificationTemplateManagerHandler{
+		templateManager: templateManager,
+	}
+}
+
+// GetTemplates is a handler for listing the notification templates
+func (tm *NotificationTemplateManagerHandler) GetTemplates(c *gin.Context) {
+	q, err := query.Parse(c.Request.URL.Query())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	templates, page, err := tm.templateManager.GetTemplates(c, q)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	res := make([]*resource.NotificationTemplate, 0, len(templates))
+	for _, template := range templates {
+		res = append(res, resource.NewNotificationTemplateResponse(template))
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", &resource.GetNotificationTemplatesResponse{
+		List:  res,
+		Total: page.Total,
+		Meta:  resource.NewListMeta(page),
+	}))
+}
+
+// GetTemplateByID is a handler for getting a notification template
+func (tm *NotificationTemplateManagerHandler) GetTemplateByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	template, err := tm.templateManager.GetTemplateByID(c, id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewNotificationTemplateResponse(template)))
+}
+
+// CreateTemplate is a handler for creating a notification template as a draft
+func (tm *NotificationTemplateManagerHandler) CreateTemplate(c *gin.Context) {
+	actorID := middleware.UserID
+
+	var request resource.SaveNotificationTemplateRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	template, err := tm.templateManager.CreateTemplate(c, request.Key, request.Type, request.DeepLink, request.ToEntities(), actorID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewNotificationTemplateResponse(template)))
+}
+
+// UpdateTemplate is a handler for updating a notification template
+func (tm *NotificationTemplateManagerHandler) UpdateTemplate(c *gin.Context) {
+	actorID := middleware.UserID
+
+	var request resource.SaveNotificationTemplateRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	template, err := tm.templateManager.UpdateTemplate(c, id, request.Key, request.Type, request.DeepLink, request.ToEntities(), actorID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewNotificationTemplateResponse(template)))
+}
+
+// DeleteTemplate is a handler for deleting a notification template
+func (tm *NotificationTemplateManagerHandler) DeleteTemplate(c *gin.Context) {
+	actorID := middleware.UserID
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	if err := tm.templateManager.DeleteTemplate(c, id, actorID); err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
+}
+
+// PublishTemplate is a handler for publishing a notification template so that the services can send it
+func (tm *NotificationTemplateManagerHandler) PublishTemplate(c *gin.Context) {
+	tm.setStatus(c, entity.NotificationTemplatePublished)
+}
+
+// UnpublishTemplate is a handler for turning a notification template back into a draft
+func (tm *NotificationTemplateManagerHandler) UnpublishTemplate(c *gin.Context) {
+	tm.setStatus(c, entity.NotificationTemplateDraft)
+}
+
+// PreviewTemplate is a handler for rendering a notification template with sample data
+func (tm *NotificationTemplateManagerHandler) PreviewTemplate(c *gin.Context) {
+	var request resource.PreviewNotificationTemplateRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	rendered, err := tm.templateManager.PreviewTemplate(c, id, request.Locale, request.Data)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewPreviewNotificationTemplateResponse(rendered)))
+}
+
+func (tm *NotificationTemplateManagerHandler) setStatus(c *gin.Context, status string) {
+	actorID := middleware.UserID
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	var template *entity.NotificationTemplate
+	if status == entity.NotificationTemplatePublished {
+		template, err = tm.templateManager.PublishTemplate(c, id, actorID)
+	} else {
+		template, err = tm.templateManager.UnpublishTemplate(c, id, actorID)
+	}
+
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewNotificationTemplateResponse(template)))
+}
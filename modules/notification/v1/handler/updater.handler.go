@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"gin-starter/common/errors"
 	"gin-starter/middleware"
 	"gin-starter/modules/notification/v1/service"
 	"gin-starter/resource"
@@ -24,44 +26,51 @@ func NewNotificationUpdaterHandler(
 	}
 }
 
-func (cf *NotificationUpdaterHandler) RegisterUnregisterPlayerID(c *gin.Context) {
-	var request resource.NotificationRegistrationRequest
-
-	if err := c.ShouldBind(&request); err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+// UpdateReadNotification is a handler for marking every notification of the signed in user as read
+func (cf *NotificationUpdaterHandler) UpdateReadNotification(c *gin.Context) {
+	if err := cf.notificationUpdater.UpdateReadNotification(
+		c,
+		middleware.UserID,
+	); err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
 		c.Abort()
 		return
 	}
 
-	playerID, err := uuid.Parse(request.PlayerID)
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
+}
 
-	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
-		c.Abort()
-		return
-	}
+// MarkRead is a handler for marking notifications of the signed in user as read
+func (cf *NotificationUpdaterHandler) MarkRead(c *gin.Context) {
+	cf.updateNotifications(c, cf.notificationUpdater.MarkRead)
+}
 
-	if err := cf.notificationUpdater.RegisterUnregisterPlayerID(
-		c,
-		middleware.UserID,
-		playerID,
-		request.Type,
-	); err != nil {
+// Archive is a handler for archiving notifications of the signed in user
+func (cf *NotificationUpdaterHandler) Archive(c *gin.Context) {
+	cf.updateNotifications(c, cf.notificationUpdater.Archive)
+}
+
+// Delete is a handler for deleting notifications of the signed in user
+func (cf *NotificationUpdaterHandler) Delete(c *gin.Context) {
+	cf.updateNotifications(c, cf.notificationUpdater.Delete)
+}
+
+// updateNotifications applies the update to the notifications of the request
+func (cf *NotificationUpdaterHandler) updateNotifications(c *gin.Context, update func(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error) {
+	userID := middleware.UserID
+
+	var request resource.NotificationIDsRequest
+
+	if err := c.ShouldBind(&request); err != nil {
 		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
 		c.Abort()
 		return
 	}
 
-	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
-}
-
-func (cf *NotificationUpdaterHandler) UpdateReadNotification(c *gin.Context) {
-	if err := cf.notificationUpdater.UpdateReadNotification(
-		c,
-		middleware.UserID,
-	); err != nil {
-		c.JSON(http.StatusBadRequest,
-			response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+	if err := update(c, userID, request.NotificationIDs); err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
 		c.Abort()
 		return
 	}
@@ -2,103 +2,194 @@ package repository
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
 
+	"gin-starter/common/query"
 	"gin-starter/entity"
 )
 
+// NotificationListSchema declares the fields GetNotification can be filtered, sorted and searched on.
+// The read and archived state come from the receipt of the user joined as receipts.
+var NotificationListSchema = &query.Schema{
+	Fields: map[string]string{
+		"title":       "main.notifications.title",
+		"type":        "main.notifications.type",
+		"is_read":     "(receipts.read_at IS NOT NULL)",
+		"is_archived": "(receipts.archived_at IS NOT NULL)",
+		"created_at":  "main.notifications.created_at",
+	},
+	Search:      []string{"main.notifications.title", "main.notifications.description"},
+	DefaultSort: "-created_at",
+	Keyset:      &query.Keyset{CreatedAt: "main.notifications.created_at", ID: "main.notifications.id"},
+	Table:       "main.notifications",
+}
+
+// notificationReceiptStates are the receipt columns UpdateReceipts can set
+var notificationReceiptStates = map[string]bool{
+	entity.NotificationReceiptRead:     true,
+	entity.NotificationReceiptArchived: true,
+	entity.NotificationReceiptDeleted:  true,
+}
+
 type NotificationRepository struct {
 	db *gorm.DB
 }
 
 type NotificationRepositoryUseCase interface {
-	GetNotification(ctx context.Context, id uuid.UUID, sort, order string, limit, offset int) ([]*entity.Notification, int64, error)
+	// GetNotification gets the notifications of the user the user did not delete, archived ones only when filtered on
+	GetNotification(ctx context.Context, id uuid.UUID, q *query.Query) ([]*entity.Notification, *query.Page, error)
+	// Create creates the notification, with a read receipt for its recipient when it is created read
 	Create(ctx context.Context, notification *entity.Notification) error
+	// CountUnreadNotification counts the notifications of the user in the inbox the user did not read
 	CountUnreadNotification(ctx context.Context, id uuid.UUID) (int64, error)
-	UpdateReadNotification(ctx context.Context, id uuid.UUID) error
+	// UpdateReceipts sets the state of the notifications of the user, or of all of them when ids is empty,
+	// and returns the number of notifications found
+	UpdateReceipts(ctx context.Context, userID uuid.UUID, state string, ids []uuid.UUID) (int64, error)
 }
 
 func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
 	return &NotificationRepository{db}
 }
 
-func (nr *NotificationRepository) GetNotification(ctx context.Context, id uuid.UUID, sort, order string, limit, offset int) ([]*entity.Notification, int64, error) {
+// withReceipts scopes the notifications to the ones addressed to the user or broadcast to everyone,
+// joined with the receipts of the user
+func withReceipts(userID uuid.UUID) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Joins("LEFT JOIN main.notification_receipts receipts ON receipts.notification_id = main.notifications.id AND receipts.user_id = ?", userID).
+			Where("main.notifications.user_id = ? OR main.notifications.user_id IS NULL", userID).
+			Where("receipts.deleted_at IS NULL")
+	}
+}
+
+// GetNotification gets the notifications addressed to the user or broadcast to everyone the user did not delete.
+// Archived notifications are left out unless the query filters on is_archived.
+func (nr *NotificationRepository) GetNotification(ctx context.Context, id uuid.UUID, q *query.Query) ([]*entity.Notification, *query.Page, error) {
 	notifications := make([]*entity.Notification, 0)
-	var total int64
+	scopes := []func(*gorm.DB) *gorm.DB{withReceipts(id)}
+	if !filtersOn(q, "is_archived") {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("receipts.archived_at IS NULL")
+		})
+	}
+
 	var gormDB = nr.db.
 		WithContext(ctx).
 		Model(&entity.Notification{}).
-		Where("user_id = ?", id).
-		Or("user_id is null")
+		Scopes(append(scopes, q.Filter(NotificationListSchema))...)
 
-	if order != "asc" && order != "desc" {
-		order = "desc"
+	total, estimated, err := q.Count(gormDB, NotificationListSchema)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "[NotificationRepository-GetNotification] error while counting notifications")
 	}
 
-	if sort == "" {
-		sort = "created_at"
+	if err := gormDB.
+		Select("main.notifications.*, receipts.read_at IS NOT NULL AS is_read, receipts.archived_at IS NOT NULL AS is_archived").
+		Scopes(q.Order(NotificationListSchema), q.Paginate(NotificationListSchema)).
+		Find(&notifications).
+		Error; err != nil {
+		return nil, nil, errors.Wrap(err, "[NotificationRepository-GetNotification] error while retrieving notifications data")
 	}
 
-	gormDB = gormDB.Order(fmt.Sprintf("%s %s", sort, order))
+	page := q.Page(&notifications, total, estimated, func(i int) (time.Time, string) {
+		return notifications[i].CreatedAt, notifications[i].ID.String()
+	})
 
-	gormDB.Count(&total)
+	return notifications, page, nil
+}
 
-	gormDB = gormDB.Limit(limit).
-		Offset(offset)
+// Create creates the notification, with a read receipt for its recipient when it is created read
+func (nr *NotificationRepository) Create(ctx context.Context, notification *entity.Notification) error {
+	return nr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Model(&entity.Notification{}).
+			Create(notification).
+			Error; err != nil {
+			return errors.Wrap(err, "[NotificationRepository-Create] error while creating notification")
+		}
 
-	if err := gormDB.Find(&notifications).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, 0, nil
+		recipientID, err := uuid.Parse(notification.UserID.String)
+		if !notification.IsRead || err != nil {
+			return nil
 		}
-		return nil, 0, errors.Wrap(err, "[NotificationRepository-GetNotification] error while retrieving notifications data")
-	}
 
-	return notifications, total, nil
+		receipt := entity.NewNotificationReceipt(notification.ID, recipientID)
+		receipt.ReadAt = sql.NullTime{Time: receipt.CreatedAt, Valid: true}
+
+		if err := tx.Create(receipt).Error; err != nil {
+			return errors.Wrap(err, "[NotificationRepository-Create] error while creating notification receipt")
+		}
+
+		return nil
+	})
 }
 
-func (nr *NotificationRepository) Create(ctx context.Context, notification *entity.Notification) error {
+// CountUnreadNotification counts the notifications of the user in the inbox the user did not read
+func (nr *NotificationRepository) CountUnreadNotification(ctx context.Context, id uuid.UUID) (int64, error) {
+	var total int64
+
 	if err := nr.db.
 		WithContext(ctx).
 		Model(&entity.Notification{}).
-		Create(notification).
+		Scopes(withReceipts(id)).
+		Where("receipts.read_at IS NULL AND receipts.archived_at IS NULL").
+		Count(&total).
 		Error; err != nil {
-		return errors.Wrap(err, "[NewsCategoryRepository-CreateNews] error while creating user")
+		return 0, errors.Wrap(err, "[NotificationRepository-CountUnreadNotification] error while counting notifications")
 	}
 
-	return nil
+	return total, nil
 }
 
-func (nr *NotificationRepository) CountUnreadNotification(ctx context.Context, id uuid.UUID) (int64, error) {
-	var total int64
+// UpdateReceipts sets the state of the notifications of the user, or of all of them when ids is empty,
+// and returns the number of notifications found. A state already set keeps its first timestamp,
+// notifications the user deleted are not found.
+func (nr *NotificationRepository) UpdateReceipts(ctx context.Context, userID uuid.UUID, state string, ids []uuid.UUID) (int64, error) {
+	if !notificationReceiptStates[state] {
+		return 0, errors.Errorf("[NotificationRepository-UpdateReceipts] unknown receipt state %s", state)
+	}
 
-	nr.db.
-		WithContext(ctx).
-		Model(&entity.Notification{}).
-		Where("user_id = ?", id).
-		Or("user_id is NULL").
-		Where("is_read = false").
-		Count(&total)
+	now := time.Now()
+	vars := []interface{}{userID, now, now, now, userID, userID}
 
-	return total, nil
+	stmt := `INSERT INTO main.notification_receipts (notification_id, user_id, ` + state + `, created_at, updated_at)
+		SELECT n.id, ?, ?, ?, ?
+		FROM main.notifications n
+		LEFT JOIN main.notification_receipts r ON r.notification_id = n.id AND r.user_id = ?
+		WHERE (n.user_id = ? OR n.user_id IS NULL) AND n.deleted_at IS NULL AND r.deleted_at IS NULL`
+
+	if len(ids) > 0 {
+		stmt += ` AND n.id IN ?`
+		vars = append(vars, ids)
+	}
+
+	stmt += ` ON CONFLICT (notification_id, user_id) DO UPDATE
+		SET ` + state + ` = COALESCE(main.notification_receipts.` + state + `, EXCLUDED.` + state + `), updated_at = EXCLUDED.updated_at`
+
+	res := nr.db.WithContext(ctx).Exec(stmt, vars...)
+	if res.Error != nil {
+		return 0, errors.Wrap(res.Error, "[NotificationRepository-UpdateReceipts] error while updating notification receipts")
+	}
+
+	return res.RowsAffected, nil
 }
 
-func (nr *NotificationRepository) UpdateReadNotification(ctx context.Context, id uuid.UUID) error {
-	if err := nr.db.WithContext(ctx).
-		Model(&entity.Notification{}).
-		Where(`user_id = ?`, id).
-		Or("user_id is NULL").
-		Updates(
-			map[string]interface{}{
-				"is_read":    true,
-				"updated_at": time.Now(),
-			}).Error; err != nil {
-		return errors.Wrap(err, "[UserRepository-DeactivateUser] error when updating user data")
+// filtersOn tells whether the query filters on the field
+func filtersOn(q *query.Query, field string) bool {
+	if q == nil {
+		return false
+	}
+
+	for _, f := range q.Filters {
+		if f.Field == field {
+			return true
+		}
 	}
 
-	return nil
+	return false
 }
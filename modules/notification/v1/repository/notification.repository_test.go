@@ -0,0 +1,156 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"gin-starter/common/query"
+	"gin-starter/entity"
+	"gin-starter/modules/notification/v1/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type NotificationRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	mock sqlmock.Sqlmock
+	repo *repository.NotificationRepository
+}
+
+func TestNotificationRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationRepositoryTestSuite))
+}
+
+func (s *NotificationRepositoryTestSuite) BeforeTest(string, string) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		s.FailNow("error opening a stub db connection: ", err)
+	}
+
+	s.db, err = gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		s.FailNow("error initializing gorm connection: ", err)
+	}
+
+	s.mock = mock
+	s.repo = repository.NewNotificationRepository(s.db)
+}
+
+func (s *NotificationRepositoryTestSuite) AfterTest(string, string) {
+	if err := s.mock.ExpectationsWereMet(); err != nil {
+		s.FailNow("there were unfulfilled expectations: ", err)
+	}
+}
+
+func (s *NotificationRepositoryTestSuite) TestGetNotification() {
+	userID := uuid.New()
+
+	s.Run("read the state of the user from the receipts and leave out archived notifications", func() {
+		s.mock.
+			ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "main"."notifications" LEFT JOIN main.notification_receipts receipts ON receipts.notification_id = main.notifications.id AND receipts.user_id = $1 WHERE (main.notifications.user_id = $2 OR main.notifications.user_id IS NULL) AND receipts.deleted_at IS NULL AND receipts.archived_at IS NULL`)).
+			WithArgs(userID, userID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		s.mock.
+			ExpectQuery(regexp.QuoteMeta(`SELECT main.notifications.*, receipts.read_at IS NOT NULL AS is_read, receipts.archived_at IS NOT NULL AS is_archived FROM "main"."notifications" LEFT JOIN`)).
+			WithArgs(userID, userID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "is_read", "is_archived"}).AddRow(uuid.New(), "Title", true, false))
+
+		res, page, err := s.repo.GetNotification(context.Background(), userID, &query.Query{})
+
+		s.NoError(err)
+		s.Require().Len(res, 1)
+		s.True(res[0].IsRead)
+		s.False(res[0].IsArchived)
+		s.Equal(int64(1), *page.Total)
+	})
+
+	s.Run("list archived notifications when filtered on", func() {
+		q := &query.Query{Filters: []query.Filter{{Field: "is_archived", Op: query.OpEqual, Value: "true"}}}
+
+		s.mock.
+			ExpectQuery(regexp.QuoteMeta(`receipts.deleted_at IS NULL AND (receipts.archived_at IS NOT NULL) = $3`)).
+			WithArgs(userID, userID, "true").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		s.mock.
+			ExpectQuery(regexp.QuoteMeta(`SELECT main.notifications.*`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		res, _, err := s.repo.GetNotification(context.Background(), userID, q)
+
+		s.NoError(err)
+		s.Empty(res)
+	})
+}
+
+func (s *NotificationRepositoryTestSuite) TestCountUnreadNotification() {
+	userID := uuid.New()
+
+	s.Run("count the unread notifications of the inbox of the user", func() {
+		s.mock.
+			ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "main"."notifications" LEFT JOIN main.notification_receipts receipts ON receipts.notification_id = main.notifications.id AND receipts.user_id = $1 WHERE (receipts.read_at IS NULL AND receipts.archived_at IS NULL) AND (main.notifications.user_id = $2 OR main.notifications.user_id IS NULL) AND receipts.deleted_at IS NULL AND "notifications"."deleted_at" IS NULL`)).
+			WithArgs(userID, userID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		total, err := s.repo.CountUnreadNotification(context.Background(), userID)
+
+		s.NoError(err)
+		s.Equal(int64(2), total)
+	})
+
+	s.Run("fail when the notifications cannot be counted", func() {
+		s.mock.
+			ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "main"."notifications"`)).
+			WillReturnError(errors.New("error"))
+
+		_, err := s.repo.CountUnreadNotification(context.Background(), userID)
+
+		s.Error(err)
+	})
+}
+
+func (s *NotificationRepositoryTestSuite) TestUpdateReceipts() {
+	userID := uuid.New()
+	notificationID := uuid.New()
+
+	s.Run("upsert the receipts of the notifications", func() {
+		s.mock.
+			ExpectExec(regexp.QuoteMeta(`INSERT INTO main.notification_receipts (notification_id, user_id, read_at, created_at, updated_at)`)+`(?s).*`+regexp.QuoteMeta(`AND n.id IN ($7) ON CONFLICT (notification_id, user_id) DO UPDATE`)).
+			WithArgs(userID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), userID, userID, notificationID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		total, err := s.repo.UpdateReceipts(context.Background(), userID, entity.NotificationReceiptRead, []uuid.UUID{notificationID})
+
+		s.NoError(err)
+		s.Equal(int64(1), total)
+	})
+
+	s.Run("upsert the receipts of every notification when no id is given", func() {
+		s.mock.
+			ExpectExec(regexp.QuoteMeta(`INSERT INTO main.notification_receipts (notification_id, user_id, archived_at, created_at, updated_at)`)).
+			WithArgs(userID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), userID, userID).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+
+		total, err := s.repo.UpdateReceipts(context.Background(), userID, entity.NotificationReceiptArchived, nil)
+
+		s.NoError(err)
+		s.Equal(int64(3), total)
+	})
+
+	s.Run("reject an unknown state", func() {
+		_, err := s.repo.UpdateReceipts(context.Background(), userID, "title", nil)
+
+		s.Error(err)
+	})
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	commonCache "gin-starter/common/cache"
+	"gin-starter/entity"
+)
+
+// notificationEventPing is the interval the subscription is checked at, so that a dropped connection is noticed
+const notificationEventPing = time.Minute
+
+// NotificationEventRepository is a repository for the notification events streamed to the signed in users.
+// The events are appended to a capped redis stream, whose ids order them and let the clients resume,
+// then published on a redis channel every instance of the API subscribes to.
+type NotificationEventRepository struct {
+	pool   *redis.Pool
+	length int
+}
+
+// NotificationEventRepositoryUseCase is a use case for the notification events
+type NotificationEventRepositoryUseCase interface {
+	// Publish appends the event to the event log, sets its id and publishes it to every instance
+	Publish(ctx context.Context, event *entity.NotificationEvent) error
+	// FindAfter returns the logged events addressed to the user published after the event of the id
+	FindAfter(ctx context.Context, userID uuid.UUID, id string) ([]*entity.NotificationEvent, error)
+	// Subscribe passes the published events to handle until the context is done or the connection fails
+	Subscribe(ctx context.Context, handle func(event *entity.NotificationEvent)) error
+}
+
+// NewNotificationEventRepository is a constructor for NotificationEventRepository, the log keeps about length events
+func NewNotificationEventRepository(pool *redis.Pool, length int) *NotificationEventRepository {
+	return &NotificationEventRepository{pool, length}
+}
+
+// Publish appends the event to the event log, sets its id and publishes it to every instance
+func (er *NotificationEventRepository) Publish(ctx context.Context, event *entity.NotificationEvent) error {
+	conn, err := er.pool.GetContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "[NotificationEventRepository-Publish] error while getting connection")
+	}
+	defer conn.Close()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	id, err := redis.String(conn.Do(
+		"XADD", commonCache.NotificationEventStream,
+		"MAXLEN", "~", er.length,
+		"*",
+		"user_id", event.UserID.String(),
+		"event", payload,
+	))
+	if err != nil {
+		return errors.Wrap(err, "[NotificationEventRepository-Publish] error while appending event")
+	}
+
+	event.ID = id
+
+	message, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if _, err := conn.Do("PUBLISH", commonCache.NotificationEventChannel, message); err != nil {
+		return errors.Wrap(err, "[NotificationEventRepository-Publish] error while publishing event")
+	}
+
+	return nil
+}
+
+// FindAfter returns the logged events addressed to the user published after the event of the id.
+// Events trimmed from the log are not returned.
+func (er *NotificationEventRepository) FindAfter(ctx context.Context, userID uuid.UUID, id string) ([]*entity.NotificationEvent, error) {
+	conn, err := er.pool.GetContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "[NotificationEventRepository-FindAfter] error while getting connection")
+	}
+	defer conn.Close()
+
+	entries, err := redis.Values(conn.Do("XRANGE", commonCache.NotificationEventStream, id, "+", "COUNT", er.length))
+	if err != nil {
+		return nil, errors.Wrap(err, "[NotificationEventRepository-FindAfter] error while reading events")
+	}
+
+	events := make([]*entity.NotificationEvent, 0)
+	for _, entry := range entries {
+		values, err := redis.Values(entry, nil)
+		if err != nil || len(values) != 2 {
+			continue
+		}
+
+		entryID, _ := redis.String(values[0], nil)
+		fields, _ := redis.StringMap(values[1], nil)
+
+		event := &entity.NotificationEvent{}
+		if err := json.Unmarshal([]byte(fields["event"]), event); err != nil {
+			continue
+		}
+
+		event.ID = entryID
+		if event.IsFor(userID) && event.After(id) {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// Subscribe passes the published events to handle until the context is done or the connection fails.
+// It returns nil once the context is done.
+func (er *NotificationEventRepository) Subscribe(ctx context.Context, handle func(event *entity.NotificationEvent)) error {
+	conn, err := er.pool.GetContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "[NotificationEventRepository-Subscribe] error while getting connection")
+	}
+
+	psc := redis.PubSubConn{Conn: conn}
+	defer psc.Close()
+
+	if err := psc.Subscribe(commonCache.NotificationEventChannel); err != nil {
+		return errors.Wrap(err, "[NotificationEventRepository-Subscribe] error while subscribing")
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(notificationEventPing)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				_ = psc.Unsubscribe()
+				return
+			case <-ticker.C:
+				_ = psc.Ping("")
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for {
+		switch v := psc.Receive().(type) {
+		case redis.Message:
+			event := &entity.NotificationEvent{}
+			if err := json.Unmarshal(v.Data, event); err != nil {
+				continue
+			}
+
+			handle(event)
+		case redis.Subscription:
+			if v.Count == 0 {
+				return nil
+			}
+		case error:
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return errors.Wrap(v, "[NotificationEventRepository-Subscribe] error while receiving events")
+		}
+	}
+}
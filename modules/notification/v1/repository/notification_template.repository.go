@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gin-starter/common/query"
+	"gin-starter/entity"
+	"gin-starter/utils"
+)
+
+// notificationTemplateKeyIndex is the unique index on the keys of the templates which are not deleted
+const notificationTemplateKeyIndex = "notification_templates_key_idx"
+
+// ErrNotificationTemplateKeyConflict is returned when a write would give a template the key of another template
+var ErrNotificationTemplateKeyConflict = errors.New("notification template key conflict")
+
+// NotificationTemplateListSchema declares the fields FindAll can be filtered, sorted and searched on
+var NotificationTemplateListSchema = &query.Schema{
+	Fields: map[string]string{
+		"key":        "key",
+		"type":       "type",
+		"status":     "status",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	},
+	Search:      []string{"key"},
+	DefaultSort: "key",
+	Keyset:      &query.Keyset{CreatedAt: "created_at", ID: "id"},
+	Table:       "main.notification_templates",
+}
+
+// NotificationTemplateRepository is a repository for the notification templates
+type NotificationTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NotificationTemplateRepositoryUseCase is a use case for the notification templates
+type NotificationTemplateRepositoryUseCase interface {
+	// FindAll finds the templates with their translations
+	FindAll(ctx context.Context, q *query.Query) ([]*entity.NotificationTemplate, *query.Page, error)
+	// FindByID finds a template with its translations, nil when it does not exist
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.NotificationTemplate, error)
+	// FindByKey finds a template with its translations by key, nil when it does not exist
+	FindByKey(ctx context.Context, key string) (*entity.NotificationTemplate, error)
+	// Create creates a template with its translations
+	Create(ctx context.Context, template *entity.NotificationTemplate) error
+	// Update updates a template and replaces its translations
+	Update(ctx context.Context, template *entity.NotificationTemplate) error
+	// Delete deletes a template
+	Delete(ctx context.Context, id uuid.UUID, deletedBy string) error
+}
+
+// NewNotificationTemplateRepository is a constructor for NotificationTemplateRepository
+func NewNotificationTemplateRepository(db *gorm.DB) *NotificationTemplateRepository {
+	return &NotificationTemplateRepository{db}
+}
+
+// FindAll finds the templates with their translations
+func (tr *NotificationTemplateRepository) FindAll(ctx context.Context, q *query.Query) ([]*entity.NotificationTemplate, *query.Page, error) {
+	templates := make([]*entity.NotificationTemplate, 0)
+	var gormDB = tr.db.
+		WithContext(ctx).
+		Model(&entity.NotificationTemplate{}).
+		Scopes(q.Filter(NotificationTemplateListSchema))
+
+	total, estimated, err := q.Count(gormDB, NotificationTemplateListSchema)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "[NotificationTemplateRepository-FindAll] error while counting templates")
+	}
+
+	if err := gormDB.
+		Preload("Translations").
+		Scopes(q.Order(NotificationTemplateListSchema), q.Paginate(NotificationTemplateListSchema)).
+		Find(&templates).
+		Error; err != nil {
+		return nil, nil, errors.Wrap(err, "[NotificationTemplateRepository-FindAll] error while getting templates")
+	}
+
+	page := q.Page(&templates, total, estimated, func(i int) (time.Time, string) {
+		return templates[i].CreatedAt, templates[i].ID.String()
+	})
+
+	return templates, page, nil
+}
+
+// FindByID finds a template with its translations, nil when it does not exist
+func (tr *NotificationTemplateRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.NotificationTemplate, error) {
+	return tr.findOne(ctx, "[NotificationTemplateRepository-FindByID]", "id = ?", id)
+}
+
+// FindByKey finds a template with its translations by key, nil when it does not exist
+func (tr *NotificationTemplateRepository) FindByKey(ctx context.Context, key string) (*entity.NotificationTemplate, error) {
+	return tr.findOne(ctx, "[NotificationTemplateRepository-FindByKey]", "key = ?", key)
+}
+
+func (tr *NotificationTemplateRepository) findOne(ctx context.Context, tag string, condition string, value interface{}) (*entity.NotificationTemplate, error) {
+	template := &entity.NotificationTemplate{}
+
+	if err := tr.db.
+		WithContext(ctx).
+		Model(&entity.NotificationTemplate{}).
+		Preload("Translations").
+		Where(condition, value).
+		First(template).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, tag+" error while getting template")
+	}
+
+	return template, nil
+}
+
+// Create creates a template with its translations
+func (tr *NotificationTemplateRepository) Create(ctx context.Context, template *entity.NotificationTemplate) error {
+	if err := tr.db.
+		WithContext(ctx).
+		Create(template).
+		Error; err != nil {
+		return errors.Wrap(templateKeyConflict(err), "[NotificationTemplateRepository-Create] error while creating template")
+	}
+
+	return nil
+}
+
+// Update updates a template and replaces its translations
+func (tr *NotificationTemplateRepository) Update(ctx context.Context, template *entity.NotificationTemplate) error {
+	oldTime := template.UpdatedAt
+	template.UpdatedAt = time.Now()
+
+	if err := tr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Model(&entity.NotificationTemplate{}).
+			Where("id = ?", template.ID).
+			UpdateColumns(template.MapUpdateFrom(template)).
+			Error; err != nil {
+			return errors.Wrap(templateKeyConflict(err), "[NotificationTemplateRepository-Update] error while updating template")
+		}
+
+		if err := tx.
+			Where("template_id = ?", template.ID).
+			Delete(&entity.NotificationTemplateTranslation{}).
+			Error; err != nil {
+			return errors.Wrap(err, "[NotificationTemplateRepository-Update] error while deleting translations")
+		}
+
+		for _, translation := range template.Translations {
+			translation.TemplateID = template.ID
+		}
+
+		if len(template.Translations) > 0 {
+			if err := tx.Create(template.Translations).Error; err != nil {
+				return errors.Wrap(err, "[NotificationTemplateRepository-Update] error while creating translations")
+			}
+		}
+
+		return nil
+	}); err != nil {
+		template.UpdatedAt = oldTime
+		return err
+	}
+
+	return nil
+}
+
+// Delete deletes a template, its key can then be given to another template
+func (tr *NotificationTemplateRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy string) error {
+	if err := tr.db.
+		WithContext(ctx).
+		Model(&entity.NotificationTemplate{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"deleted_by": utils.StringToNullString(deletedBy),
+			"deleted_at": time.Now(),
+		}).
+		Error; err != nil {
+		return errors.Wrap(err, "[NotificationTemplateRepository-Delete] error while deleting template")
+	}
+
+	return nil
+}
+
+// templateKeyConflict translates a violation of the unique key index to ErrNotificationTemplateKeyConflict
+func templateKeyConflict(err error) error {
+	if index, ok := utils.UniqueViolation(err); ok && index == notificationTemplateKeyIndex {
+		return ErrNotificationTemplateKeyConflict
+	}
+
+	return err
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"gin-starter/common/query"
+	"gin-starter/entity"
+	"gin-starter/utils"
+)
+
+// ScheduledNotificationListSchema declares the fields FindAll can be filtered, sorted and searched on
+var ScheduledNotificationListSchema = &query.Schema{
+	Fields: map[string]string{
+		"status":       "status",
+		"template_key": "template_key",
+		"type":         "type",
+		"next_run_at":  "next_run_at",
+		"last_run_at":  "last_run_at",
+		"created_at":   "created_at",
+	},
+	Search:      []string{"title", "template_key"},
+	DefaultSort: "-created_at",
+	Keyset:      &query.Keyset{CreatedAt: "created_at", ID: "id"},
+	Table:       "main.scheduled_notifications",
+}
+
+// ScheduledNotificationRunListSchema declares the fields FindRuns can be filtered and sorted on
+var ScheduledNotificationRunListSchema = &query.Schema{
+	Fields: map[string]string{
+		"status":        "status",
+		"scheduled_for": "scheduled_for",
+	},
+	DefaultSort: "-scheduled_for",
+	Keyset:      &query.Keyset{CreatedAt: "scheduled_for", ID: "id"},
+}
+
+// ScheduledNotificationRepository is a repository for the scheduled notifications and their runs
+type ScheduledNotificationRepository struct {
+	db *gorm.DB
+}
+
+// ScheduledNotificationRepositoryUseCase is a use case for the scheduled notifications
+type ScheduledNotificationRepositoryUseCase interface {
+	// FindAll finds the scheduled notifications
+	FindAll(ctx context.Context, q *query.Query) ([]*entity.ScheduledNotification, *query.Page, error)
+	// FindByID finds a scheduled notification, nil when it does not exist
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.ScheduledNotification, error)
+	// FindRuns finds the runs of a scheduled notification
+	FindRuns(ctx context.Context, id uuid.UUID, q *query.Query) ([]*entity.ScheduledNotificationRun, *query.Page, error)
+	// Create creates a scheduled notification
+	Create(ctx context.Context, scheduled *entity.ScheduledNotification) error
+	// Update updates the schedule and status of a scheduled notification
+	Update(ctx context.Context, scheduled *entity.ScheduledNotification) error
+	// Cancel cancels a scheduled notification, false when it is no longer scheduled
+	Cancel(ctx context.Context, id uuid.UUID, cancelledBy string) (bool, error)
+	// FindDue finds the scheduled notifications whose next run is due
+	FindDue(ctx context.Context, now time.Time, limit int) ([]*entity.ScheduledNotification, error)
+	// ClaimRun moves a due notification to its next run and records the run of the due occurrence, false when another worker claimed it
+	ClaimRun(ctx context.Context, scheduled *entity.ScheduledNotification, dueAt time.Time, run *entity.ScheduledNotificationRun) (bool, error)
+	// FinishRun records the result of a run
+	FinishRun(ctx context.Context, run *entity.ScheduledNotificationRun) error
+}
+
+// NewScheduledNotificationRepository is a constructor for ScheduledNotificationRepository
+func NewScheduledNotificationRepository(db *gorm.DB) *ScheduledNotificationRepository {
+	return &ScheduledNotificationRepository{db}
+}
+
+// FindAll finds the scheduled notifications
+func (sr *ScheduledNotificationRepository) FindAll(ctx context.Context, q *query.Query) ([]*entity.ScheduledNotification, *query.Page, error) {
+	scheduled := make([]*entity.ScheduledNotification, 0)
+	var gormDB = sr.db.
+		WithContext(ctx).
+		Model(&entity.ScheduledNotification{}).
+		Scopes(q.Filter(ScheduledNotificationListSchema))
+
+	total, estimated, err := q.Count(gormDB, ScheduledNotificationListSchema)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "[ScheduledNotificationRepository-FindAll] error while counting scheduled notifications")
+	}
+
+	if err := gormDB.
+		Scopes(q.Order(ScheduledNotificationListSchema), q.Paginate(ScheduledNotificationListSchema)).
+		Find(&scheduled).
+		Error; err != nil {
+		return nil, nil, errors.Wrap(err, "[ScheduledNotificationRepository-FindAll] error while getting scheduled notifications")
+	}
+
+	page := q.Page(&scheduled, total, estimated, func(i int) (time.Time, string) {
+		return scheduled[i].CreatedAt, scheduled[i].ID.String()
+	})
+
+	return scheduled, page, nil
+}
+
+// FindByID finds a scheduled notification, nil when it does not exist
+func (sr *ScheduledNotificationRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.ScheduledNotification, error) {
+	scheduled := &entity.ScheduledNotification{}
+
+	if err := sr.db.
+		WithContext(ctx).
+		Model(&entity.ScheduledNotification{}).
+		Where("id = ?", id).
+		First(scheduled).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "[ScheduledNotificationRepository-FindByID] error while getting scheduled notification")
+	}
+
+	return scheduled, nil
+}
+
+// FindRuns finds the runs of a scheduled notification, the latest first by default
+func (sr *ScheduledNotificationRepository) FindRuns(ctx context.Context, id uuid.UUID, q *query.Query) ([]*entity.ScheduledNotificationRun, *query.Page, error) {
+	runs := make([]*entity.ScheduledNotificationRun, 0)
+	var gormDB = sr.db.
+		WithContext(ctx).
+		Model(&entity.ScheduledNotificationRun{}).
+		Where("scheduled_notification_id = ?", id).
+		Scopes(q.Filter(ScheduledNotificationRunListSchema))
+
+	total, estimated, err := q.Count(gormDB, ScheduledNotificationRunListSchema)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "[ScheduledNotificationRepository-FindRuns] error while counting runs")
+	}
+
+	if err := gormDB.
+		Scopes(q.Order(ScheduledNotificationRunListSchema), q.Paginate(ScheduledNotificationRunListSchema)).
+		Find(&runs).
+		Error; err != nil {
+		return nil, nil, errors.Wrap(err, "[ScheduledNotificationRepository-FindRuns] error while getting runs")
+	}
+
+	page := q.Page(&runs, total, estimated, func(i int) (time.Time, string) {
+		return runs[i].ScheduledFor, runs[i].ID.String()
+	})
+
+	return runs, page, nil
+}
+
+// Create creates a scheduled notification
+func (sr *ScheduledNotificationRepository) Create(ctx context.Context, scheduled *entity.ScheduledNotification) error {
+	if err := sr.db.
+		WithContext(ctx).
+		Create(scheduled).
+		Error; err != nil {
+		return errors.Wrap(err, "[ScheduledNotificationRepository-Create] error while creating scheduled notification")
+	}
+
+	return nil
+}
+
+// Update updates the schedule and status of a scheduled notification
+func (sr *ScheduledNotificationRepository) Update(ctx context.Context, scheduled *entity.ScheduledNotification) error {
+	oldTime := scheduled.UpdatedAt
+	scheduled.UpdatedAt = time.Now()
+
+	if err := sr.db.
+		WithContext(ctx).
+		Model(&entity.ScheduledNotification{}).
+		Where("id = ?", scheduled.ID).
+		UpdateColumns(scheduled.MapUpdateFrom(scheduled)).
+		Error; err != nil {
+		scheduled.UpdatedAt = oldTime
+		return errors.Wrap(err, "[ScheduledNotificationRepository-Update] error while updating scheduled notification")
+	}
+
+	return nil
+}
+
+// Cancel cancels a scheduled notification, false when it already completed or was cancelled.
+// A run the worker claimed before still delivers.
+func (sr *ScheduledNotificationRepository) Cancel(ctx context.Context, id uuid.UUID, cancelledBy string) (bool, error) {
+	result := sr.db.
+		WithContext(ctx).
+		Model(&entity.ScheduledNotification{}).
+		Where("id = ? AND status = ?", id, entity.ScheduledNotificationScheduled).
+		UpdateColumns(map[string]interface{}{
+			"status":      entity.ScheduledNotificationCancelled,
+			"next_run_at": nil,
+			"updated_by":  utils.StringToNullString(cancelledBy),
+			"updated_at":  time.Now(),
+		})
+	if result.Error != nil {
+		return false, errors.Wrap(result.Error, "[ScheduledNotificationRepository-Cancel] error while cancelling scheduled notification")
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// FindDue finds the scheduled notifications whose next run is due, the most overdue first
+func (sr *ScheduledNotificationRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*entity.ScheduledNotification, error) {
+	scheduled := make([]*entity.ScheduledNotification, 0)
+
+	if err := sr.db.
+		WithContext(ctx).
+		Model(&entity.ScheduledNotification{}).
+		Where("status = ? AND next_run_at <= ?", entity.ScheduledNotificationScheduled, now).
+		Order("next_run_at").
+		Limit(limit).
+		Find(&scheduled).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "[ScheduledNotificationRepository-FindDue] error while getting due scheduled notifications")
+	}
+
+	return scheduled, nil
+}
+
+// ClaimRun moves a notification due at dueAt to the next run, status and last run of scheduled and records the run.
+// The move only applies while the notification is still scheduled at dueAt, so of the workers reading the same due
+// notification a single one claims it, and a cancel or reschedule racing the worker either wins or waits for the next run.
+// The unique occurrence index keeps a rescheduled notification from delivering an occurrence which already ran.
+func (sr *ScheduledNotificationRepository) ClaimRun(ctx context.Context, scheduled *entity.ScheduledNotification, dueAt time.Time, run *entity.ScheduledNotificationRun) (bool, error) {
+	claimed := false
+
+	if err := sr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&entity.ScheduledNotification{}).
+			Where("id = ? AND status = ? AND next_run_at = ?", scheduled.ID, entity.ScheduledNotificationScheduled, dueAt).
+			UpdateColumns(map[string]interface{}{
+				"status":      scheduled.Status,
+				"next_run_at": scheduled.NextRunAt,
+				"last_run_at": scheduled.LastRunAt,
+				"run_count":   gorm.Expr("run_count + 1"),
+				"updated_at":  time.Now(),
+			})
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "[ScheduledNotificationRepository-ClaimRun] error while claiming scheduled notification")
+		}
+
+		if result.RowsAffected != 1 {
+			return nil
+		}
+
+		result = tx.
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(run)
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "[ScheduledNotificationRepository-ClaimRun] error while creating run")
+		}
+
+		claimed = result.RowsAffected == 1
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	return claimed, nil
+}
+
+// FinishRun records the result of a run
+func (sr *ScheduledNotificationRepository) FinishRun(ctx context.Context, run *entity.ScheduledNotificationRun) error {
+	if err := sr.db.
+		WithContext(ctx).
+		Model(&entity.ScheduledNotificationRun{}).
+		Where("id = ?", run.ID).
+		UpdateColumns(map[string]interface{}{
+			"status":      run.Status,
+			"error":       run.Error,
+			"finished_at": run.FinishedAt,
+		}).
+		Error; err != nil {
+		return errors.Wrap(err, "[ScheduledNotificationRepository-FinishRun] error while finishing run")
+	}
+
+	return nil
+}
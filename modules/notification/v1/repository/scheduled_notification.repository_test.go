@@ -0,0 +1,156 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"gin-starter/entity"
+	"gin-starter/modules/notification/v1/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type ScheduledNotificationRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	mock sqlmock.Sqlmock
+	repo *repository.ScheduledNotificationRepository
+}
+
+func TestScheduledNotificationRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ScheduledNotificationRepositoryTestSuite))
+}
+
+func (s *ScheduledNotificationRepositoryTestSuite) BeforeTest(string, string) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		s.FailNow("error opening a stub db connection: ", err)
+	}
+
+	s.db, err = gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		s.FailNow("error initializing gorm connection: ", err)
+	}
+
+	s.mock = mock
+	s.repo = repository.NewScheduledNotificationRepository(s.db)
+}
+
+func (s *ScheduledNotificationRepositoryTestSuite) AfterTest(string, string) {
+	if err := s.mock.ExpectationsWereMet(); err != nil {
+		s.FailNow("there were unfulfilled expectations: ", err)
+	}
+}
+
+func (s *ScheduledNotificationRepositoryTestSuite) TestClaimRun() {
+	dueAt := time.Date(2026, 10, 20, 2, 0, 0, 0, time.UTC)
+	claim := regexp.QuoteMeta(`UPDATE "main"."scheduled_notifications" SET "last_run_at"=$1,"next_run_at"=$2,"run_count"=run_count + 1,"status"=$3,"updated_at"=$4 WHERE (id = $5 AND status = $6 AND next_run_at = $7)`)
+	insertRun := regexp.QuoteMeta(`INSERT INTO "main"."scheduled_notification_runs"`)
+
+	newScheduled := func() (*entity.ScheduledNotification, *entity.ScheduledNotificationRun) {
+		scheduled := &entity.ScheduledNotification{
+			ID:        uuid.New(),
+			Status:    entity.ScheduledNotificationScheduled,
+			NextRunAt: sql.NullTime{Time: dueAt.Add(24 * time.Hour), Valid: true},
+			LastRunAt: sql.NullTime{Time: dueAt, Valid: true},
+		}
+
+		return scheduled, entity.NewScheduledNotificationRun(uuid.New(), scheduled.ID, dueAt)
+	}
+
+	s.Run("claim the due run and record it", func() {
+		scheduled, run := newScheduled()
+
+		s.mock.ExpectBegin()
+		s.mock.
+			ExpectExec(claim).
+			WithArgs(scheduled.LastRunAt, scheduled.NextRunAt, entity.ScheduledNotificationScheduled, sqlmock.AnyArg(), scheduled.ID, entity.ScheduledNotificationScheduled, dueAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.
+			ExpectExec(insertRun).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+
+		claimed, err := s.repo.ClaimRun(context.Background(), scheduled, dueAt, run)
+
+		s.Nil(err)
+		s.True(claimed)
+	})
+
+	s.Run("leave the run to the worker which claimed it first", func() {
+		scheduled, run := newScheduled()
+
+		s.mock.ExpectBegin()
+		s.mock.
+			ExpectExec(claim).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		s.mock.ExpectCommit()
+
+		claimed, err := s.repo.ClaimRun(context.Background(), scheduled, dueAt, run)
+
+		s.Nil(err)
+		s.False(claimed)
+	})
+
+	s.Run("move on without delivering an occurrence which already ran", func() {
+		scheduled, run := newScheduled()
+
+		s.mock.ExpectBegin()
+		s.mock.
+			ExpectExec(claim).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.
+			ExpectExec(insertRun).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		s.mock.ExpectCommit()
+
+		claimed, err := s.repo.ClaimRun(context.Background(), scheduled, dueAt, run)
+
+		s.Nil(err)
+		s.False(claimed)
+	})
+}
+
+func (s *ScheduledNotificationRepositoryTestSuite) TestCancel() {
+	id := uuid.New()
+	cancel := regexp.QuoteMeta(`UPDATE "main"."scheduled_notifications" SET "next_run_at"=$1,"status"=$2,"updated_at"=$3,"updated_by"=$4 WHERE (id = $5 AND status = $6)`)
+
+	s.Run("cancel a scheduled notification", func() {
+		s.mock.ExpectBegin()
+		s.mock.
+			ExpectExec(cancel).
+			WithArgs(nil, entity.ScheduledNotificationCancelled, sqlmock.AnyArg(), sqlmock.AnyArg(), id, entity.ScheduledNotificationScheduled).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+
+		cancelled, err := s.repo.Cancel(context.Background(), id, uuid.NewString())
+
+		s.Nil(err)
+		s.True(cancelled)
+	})
+
+	s.Run("report a notification which is no longer scheduled", func() {
+		s.mock.ExpectBegin()
+		s.mock.
+			ExpectExec(cancel).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		s.mock.ExpectCommit()
+
+		cancelled, err := s.repo.Cancel(context.Background(), id, uuid.NewString())
+
+		s.Nil(err)
+		s.False(cancelled)
+	})
+}
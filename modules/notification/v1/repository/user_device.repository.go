@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"gin-starter/entity"
+)
+
+// UserDeviceRepository is a repository for the devices push notifications are delivered to
+type UserDeviceRepository struct {
+	db *gorm.DB
+}
+
+// UserDeviceRepositoryUseCase is a use case for the devices push notifications are delivered to
+type UserDeviceRepositoryUseCase interface {
+	// Save registers the device, a token registered before is moved to the user of the device and marked as seen
+	Save(ctx context.Context, device *entity.UserDevice) error
+	// FindByUserID finds the devices of the user, only the ones of the provider and platforms when given
+	FindByUserID(ctx context.Context, userID uuid.UUID, provider string, platforms []string) ([]*entity.UserDevice, error)
+	// FindAfter finds up to limit devices of the provider and platforms when given, ordered by id after the id
+	FindAfter(ctx context.Context, provider string, platforms []string, afterID uuid.UUID, limit int) ([]*entity.UserDevice, error)
+	// DeleteByToken detaches the token from the user
+	DeleteByToken(ctx context.Context, userID uuid.UUID, token string) error
+	// DeleteByUserID detaches every device of the user
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+	// DeleteByTokens deletes the tokens of the provider whoever they belong to
+	DeleteByTokens(ctx context.Context, provider string, tokens []string) error
+}
+
+// NewUserDeviceRepository is a constructor for UserDeviceRepository
+func NewUserDeviceRepository(db *gorm.DB) *UserDeviceRepository {
+	return &UserDeviceRepository{db}
+}
+
+// Save registers the device, a token registered before is moved to the user of the device and marked as seen
+func (dr *UserDeviceRepository) Save(ctx context.Context, device *entity.UserDevice) error {
+	if err := dr.db.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "provider"}, {Name: "token"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"user_id":      gorm.Expr("EXCLUDED.user_id"),
+				"platform":     gorm.Expr("EXCLUDED.platform"),
+				"app_version":  gorm.Expr("EXCLUDED.app_version"),
+				"last_seen_at": gorm.Expr("EXCLUDED.last_seen_at"),
+				"updated_by":   gorm.Expr("EXCLUDED.updated_by"),
+				"updated_at":   time.Now(),
+			}),
+		}).
+		Create(device).
+		Error; err != nil {
+		return errors.Wrap(err, "[UserDeviceRepository-Save] error while saving device")
+	}
+
+	return nil
+}
+
+// FindByUserID finds the devices of the user, only the ones of the provider and platforms when given
+func (dr *UserDeviceRepository) FindByUserID(ctx context.Context, userID uuid.UUID, provider string, platforms []string) ([]*entity.UserDevice, error) {
+	devices := make([]*entity.UserDevice, 0)
+
+	q := dr.db.
+		WithContext(ctx).
+		Where("user_id = ?", userID)
+
+	if provider != "" {
+		q = q.Where("provider = ?", provider)
+	}
+
+	if len(platforms) > 0 {
+		q = q.Where("platform IN ?", platforms)
+	}
+
+	if err := q.
+		Order("last_seen_at DESC").
+		Find(&devices).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "[UserDeviceRepository-FindByUserID] error while getting devices")
+	}
+
+	return devices, nil
+}
+
+// FindAfter finds up to limit devices of the provider and platforms when given, ordered by id after the id.
+// Every device is walked by passing the id of the last device found, uuid.Nil to start.
+func (dr *UserDeviceRepository) FindAfter(ctx context.Context, provider string, platforms []string, afterID uuid.UUID, limit int) ([]*entity.UserDevice, error) {
+	devices := make([]*entity.UserDevice, 0)
+
+	q := dr.db.
+		WithContext(ctx).
+		Where("id > ?", afterID)
+
+	if provider != "" {
+		q = q.Where("provider = ?", provider)
+	}
+
+	if len(platforms) > 0 {
+		q = q.Where("platform IN ?", platforms)
+	}
+
+	if err := q.
+		Order("id").
+		Limit(limit).
+		Find(&devices).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "[UserDeviceRepository-FindAfter] error while getting devices")
+	}
+
+	return devices, nil
+}
+
+// DeleteByToken detaches the token from the user
+func (dr *UserDeviceRepository) DeleteByToken(ctx context.Context, userID uuid.UUID, token string) error {
+	if err := dr.db.
+		WithContext(ctx).
+		Unscoped().
+		Where("user_id = ? AND token = ?", userID, token).
+		Delete(&entity.UserDevice{}).
+		Error; err != nil {
+		return errors.Wrap(err, "[UserDeviceRepository-DeleteByToken] error while deleting device")
+	}
+
+	return nil
+}
+
+// DeleteByUserID detaches every device of the user
+func (dr *UserDeviceRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	if err := dr.db.
+		WithContext(ctx).
+		Unscoped().
+		Where("user_id = ?", userID).
+		Delete(&entity.UserDevice{}).
+		Error; err != nil {
+		return errors.Wrap(err, "[UserDeviceRepository-DeleteByUserID] error while deleting devices")
+	}
+
+	return nil
+}
+
+// DeleteByTokens deletes the tokens of the provider whoever they belong to
+func (dr *UserDeviceRepository) DeleteByTokens(ctx context.Context, provider string, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	if err := dr.db.
+		WithContext(ctx).
+		Unscoped().
+		Where("provider = ? AND token IN ?", provider, tokens).
+		Delete(&entity.UserDevice{}).
+		Error; err != nil {
+		return errors.Wrap(err, "[UserDeviceRepository-DeleteByTokens] error while deleting devices")
+	}
+
+	return nil
+}
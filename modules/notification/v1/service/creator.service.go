@@ -2,62 +2,60 @@ package service
 
 import (
 	"context"
+	"log"
 
 	"github.com/google/uuid"
-	"github.com/rifqiakrm/onesignal-go-lib"
 
+	"gin-starter/common/constant"
+	"gin-starter/common/errors"
+	"gin-starter/common/interfaces"
 	"gin-starter/config"
 	"gin-starter/entity"
 	"gin-starter/modules/notification/v1/repository"
+	"gin-starter/utils"
 )
 
 type NotificationCreator struct {
 	cfg              config.Config
 	notificationRepo repository.NotificationRepositoryUseCase
+	templateRepo     repository.NotificationTemplateRepositoryUseCase
+	userDeviceRepo   repository.UserDeviceRepositoryUseCase
+	eventRepo        repository.NotificationEventRepositoryUseCase
+	preferences      interfaces.PreferenceReader
+	pushProvider     interfaces.PushProvider
 }
 
 type NotificationCreatorUseCase interface {
-	InsertNotification(ctx context.Context, userID string, title, message, notifType, extra string, isRead bool) error
+	InsertNotification(ctx context.Context, userID string, title, message, notifType, extra string, isRead bool, platforms ...string) error
+	// SendTemplate sends the published template of the key rendered with the data in the language of the user,
+	// to everyone when userID is uuid.Nil
+	SendTemplate(ctx context.Context, userID uuid.UUID, key string, data map[string]string, platforms ...string) error
 }
 
 func NewNotificationCreator(
 	cfg config.Config,
 	notificationRepo repository.NotificationRepositoryUseCase,
+	templateRepo repository.NotificationTemplateRepositoryUseCase,
+	userDeviceRepo repository.UserDeviceRepositoryUseCase,
+	eventRepo repository.NotificationEventRepositoryUseCase,
+	preferences interfaces.PreferenceReader,
+	pushProvider interfaces.PushProvider,
 ) *NotificationCreator {
 	return &NotificationCreator{
 		cfg:              cfg,
 		notificationRepo: notificationRepo,
+		templateRepo:     templateRepo,
+		userDeviceRepo:   userDeviceRepo,
+		eventRepo:        eventRepo,
+		preferences:      preferences,
+		pushProvider:     pushProvider,
 	}
 }
 
-func (nc *NotificationCreator) InsertNotification(ctx context.Context, userID string, title, message, notifType, extra string, isRead bool) error {
-	client := onesignal.NewClient(nil)
-	client.AppKey = nc.cfg.OneSignal.AppKey
-
-	notificationReq := &onesignal.NotificationRequest{
-		AppID: nc.cfg.OneSignal.AppID,
-		Contents: map[string]string{
-			"en": message,
-		},
-		Headings: map[string]string{
-			"en": title,
-		},
-		Tags: []map[string]string{
-			{
-				"key":      "logged_in",
-				"relation": "=",
-				"value":    "true",
-			},
-		},
-		Data: nil,
-	}
-
-	_, _, err := client.Notifications.Create(notificationReq)
-
-	if err != nil {
-		return err
-	}
-
+// InsertNotification stores the notification, streams it to the signed in clients of its recipient, then pushes it
+// to the devices of its recipient in the background, only to the devices of the platforms when given.
+// Notifications without a recipient are streamed to everyone and pushed to the devices of everyone who opted in.
+func (nc *NotificationCreator) InsertNotification(ctx context.Context, userID string, title, message, notifType, extra string, isRead bool, platforms ...string) error {
 	notification := entity.NewNotification(
 		uuid.New(),
 		userID,
@@ -69,9 +67,192 @@ func (nc *NotificationCreator) InsertNotification(ctx context.Context, userID st
 		"system",
 	)
 
+	return nc.send(ctx, notification, nil, platforms)
+}
+
+// SendTemplate sends the published template of the key rendered with the data in the language of the user,
+// falling back to the default language when the template is not translated to it. Broadcasts are rendered
+// in the default language. Every variable of the template needs data.
+func (nc *NotificationCreator) SendTemplate(ctx context.Context, userID uuid.UUID, key string, data map[string]string, platforms ...string) error {
+	template, err := nc.templateRepo.FindByKey(ctx, key)
+	if err != nil {
+		log.Println("[NotificationCreator-SendTemplate]", err)
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if template == nil {
+		return errors.ErrRecordNotFound.Error()
+	}
+
+	if template.Status != entity.NotificationTemplatePublished {
+		return errors.ErrNotificationTemplateNotPublished.Error()
+	}
+
+	var preferences entity.Preferences
+	recipient := ""
+	if userID != uuid.Nil {
+		preferences = nc.preferencesOf(ctx, userID)
+		recipient = userID.String()
+	}
+
+	rendered := template.Render(preferences.String(entity.PreferenceLanguage), data)
+	if rendered == nil {
+		log.Printf("[NotificationCreator-SendTemplate] template %s has no translation\n", key)
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if len(rendered.Missing) > 0 {
+		return errors.ErrNotificationTemplateDataMissing.Error()
+	}
+
+	notification := entity.NewNotification(
+		uuid.New(),
+		recipient,
+		rendered.Title,
+		rendered.Body,
+		rendered.Type,
+		"",
+		false,
+		"system",
+	)
+	notification.DeepLink = utils.StringToNullString(rendered.DeepLink)
+
+	return nc.send(ctx, notification, preferences, platforms)
+}
+
+// send stores the notification, streams it and pushes it when its recipient opted in.
+// The preferences of the recipient are read when not given.
+func (nc *NotificationCreator) send(ctx context.Context, notification *entity.Notification, preferences entity.Preferences, platforms []string) error {
 	if err := nc.notificationRepo.Create(ctx, notification); err != nil {
 		return err
 	}
 
+	recipientID, err := uuid.Parse(notification.UserID.String)
+	if err != nil {
+		recipientID = uuid.Nil
+	}
+
+	if err := nc.eventRepo.Publish(ctx, entity.NewNotificationEvent(entity.NotificationEventCreated, recipientID, notification)); err != nil {
+		log.Println("[NotificationCreator-send]", err)
+	}
+
+	if recipientID == uuid.Nil {
+		go nc.broadcast(notification, platforms)
+		return nil
+	}
+
+	if preferences == nil {
+		preferences = nc.preferencesOf(ctx, recipientID)
+	}
+
+	// the in-app notification is stored either way
+	if preferences.Bool(entity.PreferencePushNotifications) {
+		go nc.push(recipientID, notification, platforms)
+	}
+
 	return nil
 }
+
+// preferencesOf reads the preferences of the user, the defaults apply when the preferences cannot be read
+func (nc *NotificationCreator) preferencesOf(ctx context.Context, userID uuid.UUID) entity.Preferences {
+	preferences, err := nc.preferences.GetPreferences(ctx, userID)
+	if err != nil {
+		log.Println("[NotificationCreator-preferencesOf]", err)
+		return entity.NewPreferences(nil)
+	}
+
+	return preferences
+}
+
+// push delivers the stored notification detached from the request, so that a push outage never fails the notification.
+// Failures are logged, the provider bounds the delivery with its own timeout.
+func (nc *NotificationCreator) push(userID uuid.UUID, notification *entity.Notification, platforms []string) {
+	ctx := context.Background()
+
+	devices, err := nc.userDeviceRepo.FindByUserID(ctx, userID, nc.pushProvider.Name(), platforms)
+	if err != nil {
+		log.Println("[NotificationCreator-push]", err)
+		return
+	}
+
+	tokens := make([]string, 0, len(devices))
+	for _, device := range devices {
+		tokens = append(tokens, device.Token)
+	}
+
+	nc.deliver(ctx, tokens, newPushMessage(notification))
+}
+
+// broadcast delivers the stored notification without recipient to the devices of everyone who opted in, a page of
+// devices at a time, detached from the request like push.
+func (nc *NotificationCreator) broadcast(notification *entity.Notification, platforms []string) {
+	ctx := context.Background()
+	message := newPushMessage(notification)
+
+	// a user with several devices is read once
+	optedIn := make(map[uuid.UUID]bool)
+
+	afterID := uuid.Nil
+	for {
+		devices, err := nc.userDeviceRepo.FindAfter(ctx, nc.pushProvider.Name(), platforms, afterID, constant.Thousand)
+		if err != nil {
+			log.Println("[NotificationCreator-broadcast]", err)
+			return
+		}
+
+		tokens := make([]string, 0, len(devices))
+		for _, device := range devices {
+			pushes, ok := optedIn[device.UserID]
+			if !ok {
+				pushes = nc.preferencesOf(ctx, device.UserID).Bool(entity.PreferencePushNotifications)
+				optedIn[device.UserID] = pushes
+			}
+
+			if pushes {
+				tokens = append(tokens, device.Token)
+			}
+		}
+
+		nc.deliver(ctx, tokens, message)
+
+		if len(devices) < constant.Thousand {
+			return
+		}
+
+		afterID = devices[len(devices)-1].ID
+	}
+}
+
+// deliver pushes the message to the tokens, the tokens the provider reports as no longer valid are pruned
+func (nc *NotificationCreator) deliver(ctx context.Context, tokens []string, message *interfaces.PushMessage) {
+	if len(tokens) == 0 {
+		return
+	}
+
+	invalid, err := nc.pushProvider.Send(ctx, tokens, message)
+	if err != nil {
+		log.Printf("[NotificationCreator-deliver] %s: %v\n", nc.pushProvider.Name(), err)
+	}
+
+	if err := nc.userDeviceRepo.DeleteByTokens(ctx, nc.pushProvider.Name(), invalid); err != nil {
+		log.Println("[NotificationCreator-deliver]", err)
+	}
+}
+
+// newPushMessage creates the push message of the stored notification
+func newPushMessage(notification *entity.Notification) *interfaces.PushMessage {
+	message := &interfaces.PushMessage{
+		Title: notification.Title,
+		Body:  notification.Description,
+		Data: map[string]string{
+			"notification_id": notification.ID.String(),
+			"type":            notification.Type,
+		},
+	}
+
+	if notification.DeepLink.Valid {
+		message.Data["deep_link"] = notification.DeepLink.String
+	}
+
+	return message
+}
@@ -0,0 +1,346 @@
+package service_test
+
+import (
+	"context"
+	goErrors "errors"
+	"testing"
+	"time"
+
+	"gin-starter/common/constant"
+	"gin-starter/common/errors"
+	"gin-starter/common/interfaces"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/notification/v1/service"
+	mockInterfaces "gin-starter/test/mock/common/interfaces"
+	mockRepo "gin-starter/test/mock/modules/notification/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotificationCreatorTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	notificationRepository *mockRepo.MockNotificationRepositoryUseCase
+	templateRepository     *mockRepo.MockNotificationTemplateRepositoryUseCase
+	userDeviceRepository   *mockRepo.MockUserDeviceRepositoryUseCase
+	eventRepository        *mockRepo.MockNotificationEventRepositoryUseCase
+	preferenceReader       *mockInterfaces.MockPreferenceReader
+	pushProvider           *mockInterfaces.MockPushProvider
+	notificationCreator    *service.NotificationCreator
+}
+
+func TestNotificationCreatorTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationCreatorTestSuite))
+}
+
+func (suite *NotificationCreatorTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.notificationRepository = mockRepo.NewMockNotificationRepositoryUseCase(suite.mockCtrl)
+	suite.templateRepository = mockRepo.NewMockNotificationTemplateRepositoryUseCase(suite.mockCtrl)
+	suite.userDeviceRepository = mockRepo.NewMockUserDeviceRepositoryUseCase(suite.mockCtrl)
+	suite.eventRepository = mockRepo.NewMockNotificationEventRepositoryUseCase(suite.mockCtrl)
+	suite.preferenceReader = mockInterfaces.NewMockPreferenceReader(suite.mockCtrl)
+	suite.pushProvider = mockInterfaces.NewMockPushProvider(suite.mockCtrl)
+	suite.pushProvider.EXPECT().Name().Return("fake").AnyTimes()
+
+	suite.notificationCreator = service.NewNotificationCreator(
+		config.Config{},
+		suite.notificationRepository,
+		suite.templateRepository,
+		suite.userDeviceRepository,
+		suite.eventRepository,
+		suite.preferenceReader,
+		suite.pushProvider,
+	)
+}
+
+func (suite *NotificationCreatorTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+func (suite *NotificationCreatorTestSuite) TestNotificationCreator_InsertNotification() {
+	ctx := context.Background()
+	userID := uuid.New()
+	optedIn := entity.Preferences{entity.PreferencePushNotifications: true}
+
+	devices := []*entity.UserDevice{
+		entity.NewUserDevice(uuid.New(), userID, "fake", "android-token", entity.DevicePlatformAndroid, "1.0.0", userID.String()),
+		entity.NewUserDevice(uuid.New(), userID, "fake", "ios-token", entity.DevicePlatformIOS, "1.0.0", userID.String()),
+	}
+
+	suite.Run("push the stored notification to the devices of the recipient", func() {
+		pruned := make(chan []string, 1)
+
+		var stored *entity.Notification
+		suite.notificationRepository.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n *entity.Notification) error {
+			stored = n
+			return nil
+		})
+		suite.eventRepository.EXPECT().Publish(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.NotificationEvent) error {
+			suite.Equal(entity.NotificationEventCreated, e.Type)
+			suite.Equal(userID, e.UserID)
+			suite.Equal(stored, e.Notification)
+			return nil
+		})
+		suite.preferenceReader.EXPECT().GetPreferences(ctx, userID).Return(optedIn, nil)
+		suite.userDeviceRepository.EXPECT().FindByUserID(gomock.Any(), userID, "fake", []string(nil)).Return(devices, nil)
+		suite.pushProvider.EXPECT().Send(gomock.Any(), []string{"android-token", "ios-token"}, gomock.Any()).DoAndReturn(func(_ context.Context, _ []string, m *interfaces.PushMessage) ([]string, error) {
+			suite.Equal("Title", m.Title)
+			suite.Equal("Message", m.Body)
+			suite.Equal(stored.ID.String(), m.Data["notification_id"])
+			return []string{"ios-token"}, nil
+		})
+		suite.userDeviceRepository.EXPECT().DeleteByTokens(gomock.Any(), "fake", []string{"ios-token"}).DoAndReturn(func(_ context.Context, _ string, tokens []string) error {
+			pruned <- tokens
+			return nil
+		})
+
+		err := suite.notificationCreator.InsertNotification(ctx, userID.String(), "Title", "Message", "info", "", false)
+
+		suite.Nil(err)
+		suite.Equal([]string{"ios-token"}, suite.wait(pruned))
+	})
+
+	suite.Run("push only to the devices of the platforms", func() {
+		pruned := make(chan []string, 1)
+
+		suite.notificationRepository.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		suite.eventRepository.EXPECT().Publish(ctx, gomock.Any()).Return(nil)
+		suite.preferenceReader.EXPECT().GetPreferences(ctx, userID).Return(optedIn, nil)
+		suite.userDeviceRepository.EXPECT().FindByUserID(gomock.Any(), userID, "fake", []string{entity.DevicePlatformAndroid}).Return(devices[:1], nil)
+		suite.pushProvider.EXPECT().Send(gomock.Any(), []string{"android-token"}, gomock.Any()).Return([]string{}, nil)
+		suite.userDeviceRepository.EXPECT().DeleteByTokens(gomock.Any(), "fake", []string{}).DoAndReturn(func(_ context.Context, _ string, tokens []string) error {
+			pruned <- tokens
+			return nil
+		})
+
+		err := suite.notificationCreator.InsertNotification(ctx, userID.String(), "Title", "Message", "info", "", false, entity.DevicePlatformAndroid)
+
+		suite.Nil(err)
+		suite.Empty(suite.wait(pruned))
+	})
+
+	suite.Run("store the notification when the push fails", func() {
+		pruned := make(chan []string, 1)
+
+		suite.notificationRepository.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		suite.eventRepository.EXPECT().Publish(ctx, gomock.Any()).Return(nil)
+		suite.preferenceReader.EXPECT().GetPreferences(ctx, userID).Return(optedIn, nil)
+		suite.userDeviceRepository.EXPECT().FindByUserID(gomock.Any(), userID, "fake", []string(nil)).Return(devices, nil)
+		suite.pushProvider.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, goErrors.New("provider unavailable"))
+		suite.userDeviceRepository.EXPECT().DeleteByTokens(gomock.Any(), "fake", gomock.Nil()).DoAndReturn(func(_ context.Context, _ string, tokens []string) error {
+			pruned <- tokens
+			return nil
+		})
+
+		err := suite.notificationCreator.InsertNotification(ctx, userID.String(), "Title", "Message", "info", "", false)
+
+		suite.Nil(err)
+		suite.Empty(suite.wait(pruned))
+	})
+
+	suite.Run("do not push when the user opted out", func() {
+		suite.notificationRepository.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		suite.eventRepository.EXPECT().Publish(ctx, gomock.Any()).Return(nil)
+		suite.preferenceReader.EXPECT().GetPreferences(ctx, userID).Return(entity.Preferences{entity.PreferencePushNotifications: false}, nil)
+
+		err := suite.notificationCreator.InsertNotification(ctx, userID.String(), "Title", "Message", "info", "", false)
+
+		suite.Nil(err)
+	})
+
+	suite.Run("stream a notification without recipient to everyone and push it to the users who opted in", func() {
+		pruned := make(chan []string, 1)
+		optedOutID := uuid.New()
+
+		suite.notificationRepository.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		suite.eventRepository.EXPECT().Publish(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.NotificationEvent) error {
+			suite.Equal(uuid.Nil, e.UserID)
+			return nil
+		})
+		suite.userDeviceRepository.EXPECT().FindAfter(gomock.Any(), "fake", []string(nil), uuid.Nil, gomock.Any()).Return(append(devices,
+			entity.NewUserDevice(uuid.New(), optedOutID, "fake", "opted-out-token", entity.DevicePlatformWeb, "", optedOutID.String()),
+		), nil)
+		suite.preferenceReader.EXPECT().GetPreferences(gomock.Any(), userID).Return(optedIn, nil)
+		suite.preferenceReader.EXPECT().GetPreferences(gomock.Any(), optedOutID).Return(entity.Preferences{entity.PreferencePushNotifications: false}, nil)
+		suite.pushProvider.EXPECT().Send(gomock.Any(), []string{"android-token", "ios-token"}, gomock.Any()).Return([]string{"ios-token"}, nil)
+		suite.userDeviceRepository.EXPECT().DeleteByTokens(gomock.Any(), "fake", []string{"ios-token"}).DoAndReturn(func(_ context.Context, _ string, tokens []string) error {
+			pruned <- tokens
+			return nil
+		})
+
+		err := suite.notificationCreator.InsertNotification(ctx, "", "Title", "Message", "info", "", false)
+
+		suite.Nil(err)
+		suite.Equal([]string{"ios-token"}, suite.wait(pruned))
+	})
+
+	suite.Run("push a notification without recipient to every page of devices", func() {
+		pruned := make(chan []string, 1)
+
+		page := make([]*entity.UserDevice, 0, constant.Thousand)
+		for i := 0; i < constant.Thousand; i++ {
+			page = append(page, entity.NewUserDevice(uuid.New(), userID, "fake", uuid.NewString(), entity.DevicePlatformAndroid, "", userID.String()))
+		}
+
+		suite.notificationRepository.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		suite.eventRepository.EXPECT().Publish(ctx, gomock.Any()).Return(nil)
+		gomock.InOrder(
+			suite.userDeviceRepository.EXPECT().FindAfter(gomock.Any(), "fake", []string{entity.DevicePlatformAndroid}, uuid.Nil, constant.Thousand).Return(page, nil),
+			suite.userDeviceRepository.EXPECT().FindAfter(gomock.Any(), "fake", []string{entity.DevicePlatformAndroid}, page[len(page)-1].ID, constant.Thousand).Return(devices[:1], nil),
+		)
+		suite.preferenceReader.EXPECT().GetPreferences(gomock.Any(), userID).Return(optedIn, nil)
+		suite.pushProvider.EXPECT().Send(gomock.Any(), gomock.Len(constant.Thousand), gomock.Any()).Return(nil, nil)
+		suite.pushProvider.EXPECT().Send(gomock.Any(), []string{"android-token"}, gomock.Any()).Return(nil, nil)
+		suite.userDeviceRepository.EXPECT().DeleteByTokens(gomock.Any(), "fake", gomock.Nil()).Return(nil)
+		suite.userDeviceRepository.EXPECT().DeleteByTokens(gomock.Any(), "fake", gomock.Nil()).DoAndReturn(func(_ context.Context, _ string, tokens []string) error {
+			pruned <- tokens
+			return nil
+		})
+
+		err := suite.notificationCreator.InsertNotification(ctx, "", "Title", "Message", "info", "", false, entity.DevicePlatformAndroid)
+
+		suite.Nil(err)
+		suite.wait(pruned)
+	})
+
+	suite.Run("store the notification when it cannot be streamed", func() {
+		suite.notificationRepository.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		suite.eventRepository.EXPECT().Publish(ctx, gomock.Any()).Return(errors.ErrInternalServerError.Error())
+		suite.preferenceReader.EXPECT().GetPreferences(ctx, userID).Return(entity.Preferences{entity.PreferencePushNotifications: false}, nil)
+
+		err := suite.notificationCreator.InsertNotification(ctx, userID.String(), "Title", "Message", "info", "", false)
+
+		suite.Nil(err)
+	})
+
+	suite.Run("fail without pushing when the notification cannot be stored", func() {
+		suite.notificationRepository.EXPECT().Create(ctx, gomock.Any()).Return(errors.ErrInternalServerError.Error())
+
+		err := suite.notificationCreator.InsertNotification(ctx, userID.String(), "Title", "Message", "info", "", false)
+
+		suite.NotNil(err)
+	})
+}
+
+func (suite *NotificationCreatorTestSuite) TestNotificationCreator_SendTemplate() {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	template := entity.NewNotificationTemplate(uuid.New(), "order.shipped", "order", "app://orders/{{order_id}}", []*entity.NotificationTemplateTranslation{
+		{Locale: "id", Title: "Pesanan {{ order_id }} dikirim", Body: "Halo {{name}}, pesananmu sedang dikirim"},
+		{Locale: "en", Title: "Order {{ order_id }} shipped", Body: "Hi {{name}}, your order is on its way"},
+	}, "system")
+	template.Status = entity.NotificationTemplatePublished
+	data := map[string]string{"order_id": "A-1", "name": "Budi"}
+
+	suite.Run("render the template in the language of the user", func() {
+		pruned := make(chan []string, 1)
+
+		suite.templateRepository.EXPECT().FindByKey(ctx, "order.shipped").Return(template, nil)
+		suite.preferenceReader.EXPECT().GetPreferences(ctx, userID).Return(entity.Preferences{entity.PreferenceLanguage: "en", entity.PreferencePushNotifications: true}, nil)
+		suite.notificationRepository.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n *entity.Notification) error {
+			suite.Equal(userID.String(), n.UserID.String)
+			suite.Equal("Order A-1 shipped", n.Title)
+			suite.Equal("Hi Budi, your order is on its way", n.Description)
+			suite.Equal("order", n.Type)
+			suite.Equal("app://orders/A-1", n.DeepLink.String)
+			return nil
+		})
+		suite.eventRepository.EXPECT().Publish(ctx, gomock.Any()).Return(nil)
+		suite.userDeviceRepository.EXPECT().FindByUserID(gomock.Any(), userID, "fake", []string(nil)).Return([]*entity.UserDevice{
+			entity.NewUserDevice(uuid.New(), userID, "fake", "web-token", entity.DevicePlatformWeb, "", userID.String()),
+		}, nil)
+		suite.pushProvider.EXPECT().Send(gomock.Any(), []string{"web-token"}, gomock.Any()).DoAndReturn(func(_ context.Context, _ []string, m *interfaces.PushMessage) ([]string, error) {
+			suite.Equal("app://orders/A-1", m.Data["deep_link"])
+			return nil, nil
+		})
+		suite.userDeviceRepository.EXPECT().DeleteByTokens(gomock.Any(), "fake", gomock.Nil()).DoAndReturn(func(_ context.Context, _ string, tokens []string) error {
+			pruned <- tokens
+			return nil
+		})
+
+		err := suite.notificationCreator.SendTemplate(ctx, userID, "order.shipped", data)
+
+		suite.NoError(err)
+		suite.wait(pruned)
+	})
+
+	suite.Run("fall back to the default language", func() {
+		suite.templateRepository.EXPECT().FindByKey(ctx, "order.shipped").Return(template, nil)
+		suite.preferenceReader.EXPECT().GetPreferences(ctx, userID).Return(entity.Preferences{entity.PreferenceLanguage: "fr", entity.PreferencePushNotifications: false}, nil)
+		suite.notificationRepository.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n *entity.Notification) error {
+			suite.Equal("Pesanan A-1 dikirim", n.Title)
+			return nil
+		})
+		suite.eventRepository.EXPECT().Publish(ctx, gomock.Any()).Return(nil)
+
+		err := suite.notificationCreator.SendTemplate(ctx, userID, "order.shipped", data)
+
+		suite.NoError(err)
+	})
+
+	suite.Run("broadcast in the default language", func() {
+		pushed := make(chan []string, 1)
+
+		suite.templateRepository.EXPECT().FindByKey(ctx, "order.shipped").Return(template, nil)
+		suite.notificationRepository.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n *entity.Notification) error {
+			suite.False(n.UserID.Valid)
+			suite.Equal("Pesanan A-1 dikirim", n.Title)
+			return nil
+		})
+		suite.eventRepository.EXPECT().Publish(ctx, gomock.Any()).Return(nil)
+		suite.userDeviceRepository.EXPECT().FindAfter(gomock.Any(), "fake", []string(nil), uuid.Nil, gomock.Any()).DoAndReturn(
+			func(context.Context, string, []string, uuid.UUID, int) ([]*entity.UserDevice, error) {
+				pushed <- nil
+				return nil, nil
+			})
+
+		err := suite.notificationCreator.SendTemplate(ctx, uuid.Nil, "order.shipped", data)
+
+		suite.NoError(err)
+		suite.wait(pushed)
+	})
+
+	suite.Run("fail when the data lacks a variable", func() {
+		suite.templateRepository.EXPECT().FindByKey(ctx, "order.shipped").Return(template, nil)
+		suite.preferenceReader.EXPECT().GetPreferences(ctx, userID).Return(entity.Preferences{entity.PreferenceLanguage: "id"}, nil)
+
+		err := suite.notificationCreator.SendTemplate(ctx, userID, "order.shipped", map[string]string{"order_id": "A-1"})
+
+		suite.Equal(errors.ErrNotificationTemplateDataMissing.Error(), err)
+	})
+
+	suite.Run("fail when the template is a draft", func() {
+		draft := *template
+		draft.Status = entity.NotificationTemplateDraft
+		suite.templateRepository.EXPECT().FindByKey(ctx, "order.shipped").Return(&draft, nil)
+
+		err := suite.notificationCreator.SendTemplate(ctx, userID, "order.shipped", data)
+
+		suite.Equal(errors.ErrNotificationTemplateNotPublished.Error(), err)
+	})
+
+	suite.Run("fail when the template does not exist", func() {
+		suite.templateRepository.EXPECT().FindByKey(ctx, "order.lost").Return(nil, nil)
+
+		err := suite.notificationCreator.SendTemplate(ctx, userID, "order.lost", data)
+
+		suite.Equal(errors.ErrRecordNotFound.Error(), err)
+	})
+}
+
+// wait waits for the push running in the background to prune the invalid tokens
+func (suite *NotificationCreatorTestSuite) wait(pruned chan []string) []string {
+	select {
+	case tokens := <-pruned:
+		return tokens
+	case <-time.After(time.Second):
+		suite.FailNow("the notification was not pushed")
+		return nil
+	}
+}
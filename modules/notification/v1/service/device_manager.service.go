@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/interfaces"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/notification/v1/repository"
+)
+
+// UserDeviceManager is a service for the devices push notifications are delivered to
+type UserDeviceManager struct {
+	cfg            config.Config
+	userDeviceRepo repository.UserDeviceRepositoryUseCase
+	pushProvider   interfaces.PushProvider
+}
+
+// UserDeviceManagerUseCase is a use case for the devices push notifications are delivered to
+type UserDeviceManagerUseCase interface {
+	// RegisterDevice registers the token issued by the configured push provider for the user
+	RegisterDevice(ctx context.Context, userID uuid.UUID, token, platform, appVersion string) (*entity.UserDevice, error)
+	// UnregisterDevice detaches the token from the user
+	UnregisterDevice(ctx context.Context, userID uuid.UUID, token string) error
+	// DetachDevices detaches the token, or every device of the user when the token is empty
+	DetachDevices(ctx context.Context, userID uuid.UUID, token string) error
+	// GetUserDevices returns the devices of the user, the most recently seen first
+	GetUserDevices(ctx context.Context, userID uuid.UUID) ([]*entity.UserDevice, error)
+}
+
+// NewUserDeviceManager is a constructor for UserDeviceManager
+func NewUserDeviceManager(
+	cfg config.Config,
+	userDeviceRepo repository.UserDeviceRepositoryUseCase,
+	pushProvider interfaces.PushProvider,
+) *UserDeviceManager {
+	return &UserDeviceManager{
+		cfg:            cfg,
+		userDeviceRepo: userDeviceRepo,
+		pushProvider:   pushProvider,
+	}
+}
+
+// RegisterDevice registers the token issued by the configured push provider for the user.
+// Registering a known token again moves it to the user and marks it as seen.
+func (dm *UserDeviceManager) RegisterDevice(ctx context.Context, userID uuid.UUID, token, platform, appVersion string) (*entity.UserDevice, error) {
+	device := entity.NewUserDevice(
+		uuid.New(),
+		userID,
+		dm.pushProvider.Name(),
+		token,
+		platform,
+		appVersion,
+		userID.String(),
+	)
+
+	if err := dm.userDeviceRepo.Save(ctx, device); err != nil {
+		log.Println("[UserDeviceManager-RegisterDevice]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	return device, nil
+}
+
+// UnregisterDevice detaches the token from the user, unknown tokens are ignored
+func (dm *UserDeviceManager) UnregisterDevice(ctx context.Context, userID uuid.UUID, token string) error {
+	if err := dm.userDeviceRepo.DeleteByToken(ctx, userID, token); err != nil {
+		log.Println("[UserDeviceManager-UnregisterDevice]", err)
+		return errors.ErrInternalServerError.Error()
+	}
+
+	return nil
+}
+
+// DetachDevices detaches the token, or every device of the user when the token is empty
+func (dm *UserDeviceManager) DetachDevices(ctx context.Context, userID uuid.UUID, token string) error {
+	if token != "" {
+		return dm.UnregisterDevice(ctx, userID, token)
+	}
+
+	if err := dm.userDeviceRepo.DeleteByUserID(ctx, userID); err != nil {
+		log.Println("[UserDeviceManager-DetachDevices]", err)
+		return errors.ErrInternalServerError.Error()
+	}
+
+	return nil
+}
+
+// GetUserDevices returns the devices of the user, the most recently seen first
+func (dm *UserDeviceManager) GetUserDevices(ctx context.Context, userID uuid.UUID) ([]*entity.UserDevice, error) {
+	devices, err := dm.userDeviceRepo.FindByUserID(ctx, userID, "", nil)
+	if err != nil {
+		log.Println("[UserDeviceManager-GetUserDevices]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	return devices, nil
+}
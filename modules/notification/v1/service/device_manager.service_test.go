@@ -0,0 +1,96 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/notification/v1/service"
+	mockInterfaces "gin-starter/test/mock/common/interfaces"
+	mockRepo "gin-starter/test/mock/modules/notification/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserDeviceManagerTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	userDeviceRepository *mockRepo.MockUserDeviceRepositoryUseCase
+	pushProvider         *mockInterfaces.MockPushProvider
+	deviceManager        *service.UserDeviceManager
+}
+
+func TestUserDeviceManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(UserDeviceManagerTestSuite))
+}
+
+func (suite *UserDeviceManagerTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.userDeviceRepository = mockRepo.NewMockUserDeviceRepositoryUseCase(suite.mockCtrl)
+	suite.pushProvider = mockInterfaces.NewMockPushProvider(suite.mockCtrl)
+	suite.pushProvider.EXPECT().Name().Return("fcm").AnyTimes()
+
+	suite.deviceManager = service.NewUserDeviceManager(config.Config{}, suite.userDeviceRepository, suite.pushProvider)
+}
+
+func (suite *UserDeviceManagerTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+func (suite *UserDeviceManagerTestSuite) TestUserDeviceManager_RegisterDevice() {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	suite.Run("successfully register the token of the configured provider", func() {
+		suite.userDeviceRepository.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, device *entity.UserDevice) error {
+			suite.Equal(userID, device.UserID)
+			suite.Equal("fcm", device.Provider)
+			suite.Equal("device-token", device.Token)
+			suite.Equal(entity.DevicePlatformAndroid, device.Platform)
+			suite.Equal("2.1.0", device.AppVersion.String)
+			return nil
+		})
+
+		device, err := suite.deviceManager.RegisterDevice(ctx, userID, "device-token", entity.DevicePlatformAndroid, "2.1.0")
+
+		suite.Nil(err)
+		suite.NotNil(device)
+	})
+
+	suite.Run("fail when the device cannot be saved", func() {
+		suite.userDeviceRepository.EXPECT().Save(ctx, gomock.Any()).Return(errors.ErrInternalServerError.Error())
+
+		device, err := suite.deviceManager.RegisterDevice(ctx, userID, "device-token", entity.DevicePlatformIOS, "")
+
+		suite.Nil(device)
+		suite.Equal(errors.ErrInternalServerError.Error(), err)
+	})
+}
+
+func (suite *UserDeviceManagerTestSuite) TestUserDeviceManager_DetachDevices() {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	suite.Run("detach the device of the token", func() {
+		suite.userDeviceRepository.EXPECT().DeleteByToken(ctx, userID, "device-token").Return(nil)
+
+		suite.Nil(suite.deviceManager.DetachDevices(ctx, userID, "device-token"))
+	})
+
+	suite.Run("detach every device of the user without token", func() {
+		suite.userDeviceRepository.EXPECT().DeleteByUserID(ctx, userID).Return(nil)
+
+		suite.Nil(suite.deviceManager.DetachDevices(ctx, userID, ""))
+	})
+
+	suite.Run("fail when the devices cannot be deleted", func() {
+		suite.userDeviceRepository.EXPECT().DeleteByUserID(ctx, userID).Return(errors.ErrInternalServerError.Error())
+
+		suite.Equal(errors.ErrInternalServerError.Error(), suite.deviceManager.DetachDevices(ctx, userID, ""))
+	})
+}
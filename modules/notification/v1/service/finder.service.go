@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"gin-starter/common/query"
 	"gin-starter/config"
 	"gin-starter/entity"
 	"gin-starter/modules/notification/v1/repository"
@@ -16,7 +17,7 @@ type NotificationFinder struct {
 }
 
 type NotificationFinderUseCase interface {
-	GetNotification(ctx context.Context, id uuid.UUID, sort, order string, limit, offset int) ([]*entity.Notification, int64, error)
+	GetNotification(ctx context.Context, id uuid.UUID, q *query.Query) ([]*entity.Notification, *query.Page, error)
 	CountUnreadNotifications(ctx context.Context, id uuid.UUID) (int64, error)
 }
 
@@ -30,21 +31,18 @@ func NewNotificationFinder(
 	}
 }
 
-func (nf *NotificationFinder) GetNotification(ctx context.Context, id uuid.UUID, sort, order string, limit, offset int) ([]*entity.Notification, int64, error) {
-	notification, total, err := nf.notificationRepo.GetNotification(
-		ctx,
-		id,
-		sort,
-		order,
-		limit,
-		offset,
-	)
+func (nf *NotificationFinder) GetNotification(ctx context.Context, id uuid.UUID, q *query.Query) ([]*entity.Notification, *query.Page, error) {
+	if err := q.Validate(repository.NotificationListSchema); err != nil {
+		return nil, nil, err
+	}
+
+	notification, page, err := nf.notificationRepo.GetNotification(ctx, id, q)
 
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
 
-	return notification, total, nil
+	return notification, page, nil
 }
 
 func (nf *NotificationFinder) CountUnreadNotifications(ctx context.Context, id uuid.UUID) (int64, error) {
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gin-starter/common/constant"
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/notification/v1/repository"
+)
+
+// ScheduledNotificationWorker sends the due scheduled notifications.
+// Every instance runs a worker, each run is claimed by a single one of them.
+type ScheduledNotificationWorker struct {
+	cfg                 config.Config
+	scheduledRepo       repository.ScheduledNotificationRepositoryUseCase
+	notificationCreator NotificationCreatorUseCase
+}
+
+// NewScheduledNotificationWorker creates a new ScheduledNotificationWorker
+func NewScheduledNotificationWorker(
+	cfg config.Config,
+	scheduledRepo repository.ScheduledNotificationRepositoryUseCase,
+	notificationCreator NotificationCreatorUseCase,
+) *ScheduledNotificationWorker {
+	return &ScheduledNotificationWorker{
+		cfg:                 cfg,
+		scheduledRepo:       scheduledRepo,
+		notificationCreator: notificationCreator,
+	}
+}
+
+// ProcessDue claims and sends the due notifications, recording the result of every run.
+// A recurring notification overdue by several occurrences, while no worker ran, sends once and moves to its next
+// occurrence after now. A run is claimed before it is sent, so a worker stopping mid-send leaves it running
+// rather than sending it twice.
+func (sw *ScheduledNotificationWorker) ProcessDue(ctx context.Context) error {
+	now := time.Now()
+
+	due, err := sw.scheduledRepo.FindDue(ctx, now, constant.Hundred)
+	if err != nil {
+		return err
+	}
+
+	for _, scheduled := range due {
+		dueAt := scheduled.NextRunAt.Time
+
+		next := time.Time{}
+		if scheduled.Cron.Valid {
+			next = scheduled.NextRun(now)
+		}
+
+		scheduled.NextRunAt = sql.NullTime{Time: next, Valid: !next.IsZero()}
+		scheduled.LastRunAt = sql.NullTime{Time: now, Valid: true}
+		if next.IsZero() {
+			scheduled.Status = entity.ScheduledNotificationCompleted
+		}
+
+		run := entity.NewScheduledNotificationRun(uuid.New(), scheduled.ID, dueAt)
+
+		claimed, err := sw.scheduledRepo.ClaimRun(ctx, scheduled, dueAt, run)
+		if err != nil {
+			return err
+		}
+
+		if !claimed {
+			continue
+		}
+
+		run.Status = entity.ScheduledNotificationRunSent
+		if err := sw.send(ctx, scheduled); err != nil {
+			log.Printf("[ScheduledNotificationWorker-ProcessDue] scheduled notification %s: %v", scheduled.ID, err)
+			run.Status = entity.ScheduledNotificationRunFailed
+			// the cause of an internal error is only logged, the CMS shows the message of the error
+			run.Error = errors.ParseError(err).Message
+		}
+
+		run.FinishedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		if err := sw.scheduledRepo.FinishRun(ctx, run); err != nil {
+			log.Println("[ScheduledNotificationWorker-ProcessDue]", err)
+		}
+	}
+
+	return nil
+}
+
+// send sends the template or the title and message of the notification to its user, or to everyone
+func (sw *ScheduledNotificationWorker) send(ctx context.Context, scheduled *entity.ScheduledNotification) error {
+	if scheduled.TemplateKey.Valid {
+		return sw.notificationCreator.SendTemplate(
+			ctx,
+			scheduled.UserID.UUID,
+			scheduled.TemplateKey.String,
+			scheduled.DataMap(),
+			scheduled.PlatformList()...,
+		)
+	}
+
+	userID := ""
+	if scheduled.UserID.Valid {
+		userID = scheduled.UserID.UUID.String()
+	}
+
+	return sw.notificationCreator.InsertNotification(
+		ctx,
+		userID,
+		scheduled.Title,
+		scheduled.Message,
+		scheduled.Type,
+		scheduled.Extra,
+		false,
+		scheduled.PlatformList()...,
+	)
+}
+
+// Run sends the due notifications periodically until the context is done
+func (sw *ScheduledNotificationWorker) Run(ctx context.Context) {
+	interval, err := time.ParseDuration(sw.cfg.Schedule.WorkerInterval)
+	if err != nil || interval <= 0 {
+		log.Println("[ScheduledNotificationWorker-Run] invalid worker interval, scheduled notifications are never sent:", sw.cfg.Schedule.WorkerInterval)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := sw.ProcessDue(ctx); err != nil {
+			log.Println("[ScheduledNotificationWorker-Run]", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/notification/v1/service"
+	mockRepo "gin-starter/test/mock/modules/notification/repository"
+	mockService "gin-starter/test/mock/modules/notification/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type ScheduledNotificationWorkerTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	scheduledRepository *mockRepo.MockScheduledNotificationRepositoryUseCase
+	notificationCreator *mockService.MockNotificationCreatorUseCase
+	worker              *service.ScheduledNotificationWorker
+}
+
+func TestScheduledNotificationWorkerTestSuite(t *testing.T) {
+	suite.Run(t, new(ScheduledNotificationWorkerTestSuite))
+}
+
+func (suite *ScheduledNotificationWorkerTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.scheduledRepository = mockRepo.NewMockScheduledNotificationRepositoryUseCase(suite.mockCtrl)
+	suite.notificationCreator = mockService.NewMockNotificationCreatorUseCase(suite.mockCtrl)
+
+	suite.worker = service.NewScheduledNotificationWorker(config.Config{}, suite.scheduledRepository, suite.notificationCreator)
+}
+
+func (suite *ScheduledNotificationWorkerTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+func (suite *ScheduledNotificationWorkerTestSuite) TestScheduledNotificationWorker_ProcessDue() {
+	ctx := context.Background()
+	dueAt := time.Now().Add(-time.Minute).Truncate(time.Microsecond)
+
+	suite.Run("successfully send a one-off notification and complete it", func() {
+		userID := uuid.New()
+		scheduled := entity.NewScheduledNotification(uuid.New(), userID, "", nil, "Promo", "Diskon hari ini", "promo", "", []string{"ios"}, "admin")
+		scheduled.SendAt = sql.NullTime{Time: dueAt, Valid: true}
+		scheduled.NextRunAt = sql.NullTime{Time: dueAt, Valid: true}
+
+		suite.scheduledRepository.EXPECT().FindDue(ctx, gomock.Any(), gomock.Any()).Return([]*entity.ScheduledNotification{scheduled}, nil)
+		suite.scheduledRepository.EXPECT().
+			ClaimRun(ctx, scheduled, dueAt, gomock.Any()).
+			DoAndReturn(func(_ context.Context, s *entity.ScheduledNotification, _ time.Time, run *entity.ScheduledNotificationRun) (bool, error) {
+				suite.Equal(entity.ScheduledNotificationCompleted, s.Status)
+				suite.False(s.NextRunAt.Valid)
+				suite.True(dueAt.Equal(run.ScheduledFor))
+				return true, nil
+			})
+		suite.notificationCreator.EXPECT().InsertNotification(ctx, userID.String(), "Promo", "Diskon hari ini", "promo", "", false, "ios").Return(nil)
+		suite.scheduledRepository.EXPECT().
+			FinishRun(ctx, gomock.Any()).
+			Do(func(_ context.Context, run *entity.ScheduledNotificationRun) {
+				suite.Equal(entity.ScheduledNotificationRunSent, run.Status)
+				suite.True(run.FinishedAt.Valid)
+			})
+
+		suite.Nil(suite.worker.ProcessDue(ctx))
+	})
+
+	suite.Run("record the failure of a recurring template and move it to its next run", func() {
+		scheduled := entity.NewScheduledNotification(uuid.New(), uuid.Nil, "weekly.digest", map[string]string{"week": "42"}, "", "", "", "", nil, "admin")
+		suite.Require().NoError(scheduled.Reschedule(time.Time{}, "0 9 * * 1", "Asia/Jakarta", time.Now()))
+		scheduled.NextRunAt = sql.NullTime{Time: dueAt, Valid: true}
+
+		suite.scheduledRepository.EXPECT().FindDue(ctx, gomock.Any(), gomock.Any()).Return([]*entity.ScheduledNotification{scheduled}, nil)
+		suite.scheduledRepository.EXPECT().
+			ClaimRun(ctx, scheduled, dueAt, gomock.Any()).
+			DoAndReturn(func(_ context.Context, s *entity.ScheduledNotification, _ time.Time, _ *entity.ScheduledNotificationRun) (bool, error) {
+				suite.Equal(entity.ScheduledNotificationScheduled, s.Status)
+				suite.True(s.NextRunAt.Time.After(time.Now()))
+				return true, nil
+			})
+		suite.notificationCreator.EXPECT().SendTemplate(ctx, uuid.Nil, "weekly.digest", map[string]string{"week": "42"}).Return(errors.ErrNotificationTemplateNotPublished.Error())
+		suite.scheduledRepository.EXPECT().
+			FinishRun(ctx, gomock.Any()).
+			Do(func(_ context.Context, run *entity.ScheduledNotificationRun) {
+				suite.Equal(entity.ScheduledNotificationRunFailed, run.Status)
+				suite.Equal(errors.ErrNotificationTemplateNotPublished.Message, run.Error)
+			})
+
+		suite.Nil(suite.worker.ProcessDue(ctx))
+	})
+
+	suite.Run("skip a notification another worker claimed", func() {
+		scheduled := entity.NewScheduledNotification(uuid.New(), uuid.Nil, "", nil, "Promo", "Diskon hari ini", "promo", "", nil, "admin")
+		scheduled.NextRunAt = sql.NullTime{Time: dueAt, Valid: true}
+
+		suite.scheduledRepository.EXPECT().FindDue(ctx, gomock.Any(), gomock.Any()).Return([]*entity.ScheduledNotification{scheduled}, nil)
+		suite.scheduledRepository.EXPECT().ClaimRun(ctx, scheduled, dueAt, gomock.Any()).Return(false, nil)
+
+		suite.Nil(suite.worker.ProcessDue(ctx))
+	})
+}
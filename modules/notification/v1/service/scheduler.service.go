@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/query"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/notification/v1/repository"
+	"gin-starter/utils"
+)
+
+// NotificationScheduler is a service for the notifications sent at a time or on a recurrence
+type NotificationScheduler struct {
+	cfg           config.Config
+	scheduledRepo repository.ScheduledNotificationRepositoryUseCase
+	templateRepo  repository.NotificationTemplateRepositoryUseCase
+}
+
+// NotificationSchedulerUseCase is a use case for scheduling notifications
+type NotificationSchedulerUseCase interface {
+	// GetScheduledNotifications gets the scheduled notifications
+	GetScheduledNotifications(ctx context.Context, q *query.Query) ([]*entity.ScheduledNotification, *query.Page, error)
+	// GetScheduledNotificationByID gets a scheduled notification
+	GetScheduledNotificationByID(ctx context.Context, id uuid.UUID) (*entity.ScheduledNotification, error)
+	// GetScheduledNotificationRuns gets the runs of a scheduled notification with their delivery results
+	GetScheduledNotificationRuns(ctx context.Context, id uuid.UUID, q *query.Query) ([]*entity.ScheduledNotificationRun, *query.Page, error)
+	// ScheduleNotification schedules the notification once at sendAt, or on the cron expression evaluated in the timezone
+	ScheduleNotification(ctx context.Context, scheduled *entity.ScheduledNotification, sendAt time.Time, cronExpr, timezone string) (*entity.ScheduledNotification, error)
+	// RescheduleNotification replaces the schedule of a notification, scheduling it again when it completed or was cancelled
+	RescheduleNotification(ctx context.Context, id uuid.UUID, sendAt time.Time, cronExpr, timezone string, actorID uuid.UUID) (*entity.ScheduledNotification, error)
+	// CancelScheduledNotification cancels the next runs of a notification
+	CancelScheduledNotification(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (*entity.ScheduledNotification, error)
+}
+
+// NewNotificationScheduler is a constructor for NotificationScheduler
+func NewNotificationScheduler(
+	cfg config.Config,
+	scheduledRepo repository.ScheduledNotificationRepositoryUseCase,
+	templateRepo repository.NotificationTemplateRepositoryUseCase,
+) *NotificationScheduler {
+	return &NotificationScheduler{
+		cfg:           cfg,
+		scheduledRepo: scheduledRepo,
+		templateRepo:  templateRepo,
+	}
+}
+
+// GetScheduledNotifications gets the scheduled notifications
+func (ns *NotificationScheduler) GetScheduledNotifications(ctx context.Context, q *query.Query) ([]*entity.ScheduledNotification, *query.Page, error) {
+	if err := q.Validate(repository.ScheduledNotificationListSchema); err != nil {
+		return nil, nil, err
+	}
+
+	scheduled, page, err := ns.scheduledRepo.FindAll(ctx, q)
+	if err != nil {
+		log.Println("[NotificationScheduler-GetScheduledNotifications]", err)
+		return nil, nil, errors.ErrInternalServerError.Error()
+	}
+
+	return scheduled, page, nil
+}
+
+// GetScheduledNotificationByID gets a scheduled notification
+func (ns *NotificationScheduler) GetScheduledNotificationByID(ctx context.Context, id uuid.UUID) (*entity.ScheduledNotification, error) {
+	scheduled, err := ns.scheduledRepo.FindByID(ctx, id)
+	if err != nil {
+		log.Println("[NotificationScheduler-GetScheduledNotificationByID]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if scheduled == nil {
+		return nil, errors.ErrRecordNotFound.Error()
+	}
+
+	return scheduled, nil
+}
+
+// GetScheduledNotificationRuns gets the runs of a scheduled notification with their delivery results, the latest first
+func (ns *NotificationScheduler) GetScheduledNotificationRuns(ctx context.Context, id uuid.UUID, q *query.Query) ([]*entity.ScheduledNotificationRun, *query.Page, error) {
+	if err := q.Validate(repository.ScheduledNotificationRunListSchema); err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := ns.GetScheduledNotificationByID(ctx, id); err != nil {
+		return nil, nil, err
+	}
+
+	runs, page, err := ns.scheduledRepo.FindRuns(ctx, id, q)
+	if err != nil {
+		log.Println("[NotificationScheduler-GetScheduledNotificationRuns]", err)
+		return nil, nil, errors.ErrInternalServerError.Error()
+	}
+
+	return runs, page, nil
+}
+
+// ScheduleNotification schedules the notification once at sendAt, or on the cron expression evaluated in the timezone,
+// the default timezone of the users when empty. The notification sends a template or a title and message.
+// A template is checked to exist, it only has to be published by the time it runs.
+func (ns *NotificationScheduler) ScheduleNotification(
+	ctx context.Context,
+	scheduled *entity.ScheduledNotification,
+	sendAt time.Time,
+	cronExpr, timezone string,
+) (*entity.ScheduledNotification, error) {
+	if !scheduled.TemplateKey.Valid && (scheduled.Title == "" || scheduled.Message == "") {
+		return nil, errors.ErrNotificationContentMissing.Error()
+	}
+
+	if err := reschedule(scheduled, sendAt, cronExpr, timezone); err != nil {
+		return nil, err
+	}
+
+	if scheduled.TemplateKey.Valid {
+		template, err := ns.templateRepo.FindByKey(ctx, scheduled.TemplateKey.String)
+		if err != nil {
+			log.Println("[NotificationScheduler-ScheduleNotification]", err)
+			return nil, errors.ErrInternalServerError.Error()
+		}
+
+		if template == nil {
+			return nil, errors.ErrRecordNotFound.Error()
+		}
+	}
+
+	if err := ns.scheduledRepo.Create(ctx, scheduled); err != nil {
+		log.Println("[NotificationScheduler-ScheduleNotification]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	return scheduled, nil
+}
+
+// RescheduleNotification replaces the schedule of a notification, scheduling it again when it completed or was cancelled.
+// A run the worker already claimed still delivers.
+func (ns *NotificationScheduler) RescheduleNotification(
+	ctx context.Context,
+	id uuid.UUID,
+	sendAt time.Time,
+	cronExpr, timezone string,
+	actorID uuid.UUID,
+) (*entity.ScheduledNotification, error) {
+	scheduled, err := ns.GetScheduledNotificationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := reschedule(scheduled, sendAt, cronExpr, timezone); err != nil {
+		return nil, err
+	}
+
+	scheduled.UpdatedBy = utils.StringToNullString(actorID.String())
+
+	if err := ns.scheduledRepo.Update(ctx, scheduled); err != nil {
+		log.Println("[NotificationScheduler-RescheduleNotification]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	return scheduled, nil
+}
+
+// CancelScheduledNotification cancels the next runs of a notification, it can be scheduled again with RescheduleNotification
+func (ns *NotificationScheduler) CancelScheduledNotification(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (*entity.ScheduledNotification, error) {
+	if _, err := ns.GetScheduledNotificationByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	cancelled, err := ns.scheduledRepo.Cancel(ctx, id, actorID.String())
+	if err != nil {
+		log.Println("[NotificationScheduler-CancelScheduledNotification]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if !cancelled {
+		return nil, errors.ErrScheduledNotificationNotScheduled.Error()
+	}
+
+	return ns.GetScheduledNotificationByID(ctx, id)
+}
+
+// reschedule validates and sets the schedule, exactly one of a future send time and a cron expression with a next run
+func reschedule(scheduled *entity.ScheduledNotification, sendAt time.Time, cronExpr, timezone string) error {
+	if timezone == "" {
+		timezone, _ = entity.FindPreferenceDefinition(entity.PreferenceTimezone).Default.(string)
+	}
+
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return errors.ErrInvalidNotificationTimezone.Error()
+	}
+
+	if sendAt.IsZero() == (cronExpr == "") {
+		return errors.ErrInvalidNotificationSchedule.Error()
+	}
+
+	now := time.Now()
+	if cronExpr == "" && !sendAt.After(now) {
+		return errors.ErrNotificationSendAtPassed.Error()
+	}
+
+	if err := scheduled.Reschedule(sendAt, cronExpr, timezone, now); err != nil || !scheduled.NextRunAt.Valid {
+		return errors.ErrInvalidNotificationSchedule.Error()
+	}
+
+	return nil
+}
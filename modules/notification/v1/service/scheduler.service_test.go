@@ -0,0 +1,171 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/notification/v1/service"
+	mockRepo "gin-starter/test/mock/modules/notification/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotificationSchedulerTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	scheduledRepository *mockRepo.MockScheduledNotificationRepositoryUseCase
+	templateRepository  *mockRepo.MockNotificationTemplateRepositoryUseCase
+	scheduler           *service.NotificationScheduler
+}
+
+func TestNotificationSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationSchedulerTestSuite))
+}
+
+func (suite *NotificationSchedulerTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.scheduledRepository = mockRepo.NewMockScheduledNotificationRepositoryUseCase(suite.mockCtrl)
+	suite.templateRepository = mockRepo.NewMockNotificationTemplateRepositoryUseCase(suite.mockCtrl)
+
+	suite.scheduler = service.NewNotificationScheduler(config.Config{}, suite.scheduledRepository, suite.templateRepository)
+}
+
+func (suite *NotificationSchedulerTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+func newScheduledNotification(templateKey string) *entity.ScheduledNotification {
+	return entity.NewScheduledNotification(uuid.New(), uuid.Nil, templateKey, nil, "Promo", "Diskon hari ini", "promo", "", nil, uuid.NewString())
+}
+
+func (suite *NotificationSchedulerTestSuite) TestNotificationScheduler_ScheduleNotification() {
+	ctx := context.Background()
+
+	suite.Run("successfully schedule a one-off notification", func() {
+		sendAt := time.Now().Add(time.Hour)
+		suite.scheduledRepository.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		res, err := suite.scheduler.ScheduleNotification(ctx, newScheduledNotification(""), sendAt, "", "")
+
+		suite.Nil(err)
+		suite.Equal(entity.ScheduledNotificationScheduled, res.Status)
+		suite.Equal("Asia/Jakarta", res.Timezone)
+		suite.True(sendAt.Equal(res.NextRunAt.Time))
+	})
+
+	suite.Run("successfully schedule a recurring template in its timezone", func() {
+		suite.templateRepository.EXPECT().FindByKey(ctx, "weekly.digest").Return(&entity.NotificationTemplate{ID: uuid.New()}, nil)
+		suite.scheduledRepository.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		res, err := suite.scheduler.ScheduleNotification(ctx, newScheduledNotification("weekly.digest"), time.Time{}, "0 9 * * 1", "Asia/Jayapura")
+
+		suite.Nil(err)
+		suite.False(res.SendAt.Valid)
+		next := res.NextRunAt.Time.In(time.FixedZone("WIT", 9*60*60))
+		suite.Equal(time.Monday, next.Weekday())
+		suite.Equal(9, next.Hour())
+	})
+
+	suite.Run("fail to schedule neither at a time nor on a recurrence", func() {
+		_, err := suite.scheduler.ScheduleNotification(ctx, newScheduledNotification(""), time.Time{}, "", "")
+		suite.Equal(errors.ErrInvalidNotificationSchedule.Error(), err)
+	})
+
+	suite.Run("fail to schedule both at a time and on a recurrence", func() {
+		_, err := suite.scheduler.ScheduleNotification(ctx, newScheduledNotification(""), time.Now().Add(time.Hour), "@daily", "")
+		suite.Equal(errors.ErrInvalidNotificationSchedule.Error(), err)
+	})
+
+	suite.Run("fail to schedule on a malformed or never running cron expression", func() {
+		for _, expr := range []string{"every day", "0 0 30 2 *"} {
+			_, err := suite.scheduler.ScheduleNotification(ctx, newScheduledNotification(""), time.Time{}, expr, "")
+			suite.Equal(errors.ErrInvalidNotificationSchedule.Error(), err, expr)
+		}
+	})
+
+	suite.Run("fail to schedule at a time which passed", func() {
+		_, err := suite.scheduler.ScheduleNotification(ctx, newScheduledNotification(""), time.Now().Add(-time.Minute), "", "")
+		suite.Equal(errors.ErrNotificationSendAtPassed.Error(), err)
+	})
+
+	suite.Run("fail to schedule in an unknown timezone", func() {
+		_, err := suite.scheduler.ScheduleNotification(ctx, newScheduledNotification(""), time.Time{}, "@daily", "Mars/Olympus")
+		suite.Equal(errors.ErrInvalidNotificationTimezone.Error(), err)
+	})
+
+	suite.Run("fail to schedule a notification without content", func() {
+		scheduled := newScheduledNotification("")
+		scheduled.Message = ""
+
+		_, err := suite.scheduler.ScheduleNotification(ctx, scheduled, time.Now().Add(time.Hour), "", "")
+		suite.Equal(errors.ErrNotificationContentMissing.Error(), err)
+	})
+
+	suite.Run("fail to schedule a template which does not exist", func() {
+		suite.templateRepository.EXPECT().FindByKey(ctx, "missing").Return(nil, nil)
+
+		_, err := suite.scheduler.ScheduleNotification(ctx, newScheduledNotification("missing"), time.Now().Add(time.Hour), "", "")
+		suite.Equal(errors.ErrRecordNotFound.Error(), err)
+	})
+}
+
+func (suite *NotificationSchedulerTestSuite) TestNotificationScheduler_RescheduleNotification() {
+	ctx := context.Background()
+	actorID := uuid.New()
+
+	suite.Run("successfully schedule a cancelled notification again", func() {
+		scheduled := newScheduledNotification("")
+		scheduled.Status = entity.ScheduledNotificationCancelled
+		sendAt := time.Now().Add(time.Hour)
+
+		suite.scheduledRepository.EXPECT().FindByID(ctx, scheduled.ID).Return(scheduled, nil)
+		suite.scheduledRepository.EXPECT().Update(ctx, scheduled).Return(nil)
+
+		res, err := suite.scheduler.RescheduleNotification(ctx, scheduled.ID, sendAt, "", "UTC", actorID)
+
+		suite.Nil(err)
+		suite.Equal(entity.ScheduledNotificationScheduled, res.Status)
+		suite.True(sendAt.Equal(res.NextRunAt.Time))
+		suite.Equal(actorID.String(), res.UpdatedBy.String)
+	})
+
+	suite.Run("fail to reschedule a notification which does not exist", func() {
+		id := uuid.New()
+		suite.scheduledRepository.EXPECT().FindByID(ctx, id).Return(nil, nil)
+
+		_, err := suite.scheduler.RescheduleNotification(ctx, id, time.Time{}, "@daily", "", actorID)
+		suite.Equal(errors.ErrRecordNotFound.Error(), err)
+	})
+}
+
+func (suite *NotificationSchedulerTestSuite) TestNotificationScheduler_CancelScheduledNotification() {
+	ctx := context.Background()
+	actorID := uuid.New()
+
+	suite.Run("successfully cancel a scheduled notification", func() {
+		scheduled := newScheduledNotification("")
+
+		suite.scheduledRepository.EXPECT().FindByID(ctx, scheduled.ID).Return(scheduled, nil).Times(2)
+		suite.scheduledRepository.EXPECT().Cancel(ctx, scheduled.ID, actorID.String()).Return(true, nil)
+
+		_, err := suite.scheduler.CancelScheduledNotification(ctx, scheduled.ID, actorID)
+		suite.Nil(err)
+	})
+
+	suite.Run("fail to cancel a notification which completed", func() {
+		scheduled := newScheduledNotification("")
+
+		suite.scheduledRepository.EXPECT().FindByID(ctx, scheduled.ID).Return(scheduled, nil)
+		suite.scheduledRepository.EXPECT().Cancel(ctx, scheduled.ID, actorID.String()).Return(false, nil)
+
+		_, err := suite.scheduler.CancelScheduledNotification(ctx, scheduled.ID, actorID)
+		suite.Equal(errors.ErrScheduledNotificationNotScheduled.Error(), err)
+	})
+}
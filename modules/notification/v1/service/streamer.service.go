@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/notification/v1/repository"
+)
+
+const (
+	// notificationStreamBuffer is the number of events a client can fall behind before its stream is closed
+	notificationStreamBuffer = 64
+	// notificationSubscribeRetry is the first delay before subscribing again after the subscription failed
+	notificationSubscribeRetry = time.Second
+	// notificationSubscribeRetryMax is the longest delay before subscribing again
+	notificationSubscribeRetryMax = 30 * time.Second
+)
+
+// NotificationStreamer is a service streaming the notification events published by every instance to the signed in users
+type NotificationStreamer struct {
+	cfg              config.Config
+	eventRepo        repository.NotificationEventRepositoryUseCase
+	notificationRepo repository.NotificationRepositoryUseCase
+
+	mu          sync.Mutex
+	subscribers map[*notificationSubscriber]struct{}
+}
+
+// notificationSubscriber is a stream open on this instance
+type notificationSubscriber struct {
+	userID uuid.UUID
+	events chan *entity.NotificationEvent
+}
+
+// NotificationStreamerUseCase is a use case for streaming the notification events
+type NotificationStreamerUseCase interface {
+	// Stream streams the events of the user until the context is done, after the event of lastEventID when given.
+	// The channel is closed once the context is done, or when the client falls too far behind and has to resume.
+	Stream(ctx context.Context, userID uuid.UUID, lastEventID string) (<-chan *entity.NotificationEvent, error)
+	// CountUnread counts the unread notifications of the user
+	CountUnread(ctx context.Context, userID uuid.UUID) (int64, error)
+	// RunSubscriber receives the events published by every instance until the context is done
+	RunSubscriber(ctx context.Context)
+}
+
+// NewNotificationStreamer is a constructor for NotificationStreamer
+func NewNotificationStreamer(
+	cfg config.Config,
+	eventRepo repository.NotificationEventRepositoryUseCase,
+	notificationRepo repository.NotificationRepositoryUseCase,
+) *NotificationStreamer {
+	return &NotificationStreamer{
+		cfg:              cfg,
+		eventRepo:        eventRepo,
+		notificationRepo: notificationRepo,
+		subscribers:      make(map[*notificationSubscriber]struct{}),
+	}
+}
+
+// Stream streams the events of the user until the context is done, after the event of lastEventID when given.
+// The logged events published after lastEventID are replayed first, an unknown id replays nothing.
+func (ns *NotificationStreamer) Stream(ctx context.Context, userID uuid.UUID, lastEventID string) (<-chan *entity.NotificationEvent, error) {
+	// subscribe before reading the log so that no event is lost in between, the duplicates are skipped below
+	sub := ns.subscribe(userID)
+
+	replay := make([]*entity.NotificationEvent, 0)
+	if entity.IsNotificationEventID(lastEventID) {
+		events, err := ns.eventRepo.FindAfter(ctx, userID, lastEventID)
+		if err != nil {
+			ns.unsubscribe(sub)
+			log.Println("[NotificationStreamer-Stream]", err)
+			return nil, errors.ErrInternalServerError.Error()
+		}
+
+		replay = events
+	} else {
+		lastEventID = ""
+	}
+
+	out := make(chan *entity.NotificationEvent, notificationStreamBuffer)
+
+	go func() {
+		defer close(out)
+		defer ns.unsubscribe(sub)
+
+		last := lastEventID
+		send := func(event *entity.NotificationEvent) bool {
+			select {
+			case out <- event:
+				last = event.ID
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for _, event := range replay {
+			if !send(event) {
+				return
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-sub.events:
+				if !ok {
+					return
+				}
+
+				if last != "" && !event.After(last) {
+					continue
+				}
+
+				if !send(event) {
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// CountUnread counts the unread notifications of the user
+func (ns *NotificationStreamer) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	total, err := ns.notificationRepo.CountUnreadNotification(ctx, userID)
+	if err != nil {
+		log.Println("[NotificationStreamer-CountUnread]", err)
+		return 0, errors.ErrInternalServerError.Error()
+	}
+
+	return total, nil
+}
+
+// RunSubscriber receives the events published by every instance until the context is done.
+// The subscription is restored with a growing delay when the connection fails.
+func (ns *NotificationStreamer) RunSubscriber(ctx context.Context) {
+	retry := notificationSubscribeRetry
+
+	for {
+		started := time.Now()
+
+		if err := ns.eventRepo.Subscribe(ctx, ns.dispatch); err != nil {
+			log.Println("[NotificationStreamer-RunSubscriber]", err)
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if time.Since(started) > notificationSubscribeRetryMax {
+			retry = notificationSubscribeRetry
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+
+		retry *= 2
+		if retry > notificationSubscribeRetryMax {
+			retry = notificationSubscribeRetryMax
+		}
+	}
+}
+
+// dispatch passes the event to the streams of its recipients open on this instance.
+// A stream too far behind is closed rather than blocking the others, its client resumes from the event log.
+func (ns *NotificationStreamer) dispatch(event *entity.NotificationEvent) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	for sub := range ns.subscribers {
+		if !event.IsFor(sub.userID) {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			delete(ns.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+func (ns *NotificationStreamer) subscribe(userID uuid.UUID) *notificationSubscriber {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	sub := &notificationSubscriber{
+		userID: userID,
+		events: make(chan *entity.NotificationEvent, notificationStreamBuffer),
+	}
+	ns.subscribers[sub] = struct{}{}
+
+	return sub
+}
+
+func (ns *NotificationStreamer) unsubscribe(sub *notificationSubscriber) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	if _, ok := ns.subscribers[sub]; ok {
+		delete(ns.subscribers, sub)
+		close(sub.events)
+	}
+}
@@ -0,0 +1,180 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/notification/v1/service"
+	mockRepo "gin-starter/test/mock/modules/notification/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotificationStreamerTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	eventRepository        *mockRepo.MockNotificationEventRepositoryUseCase
+	notificationRepository *mockRepo.MockNotificationRepositoryUseCase
+	notificationStreamer   *service.NotificationStreamer
+}
+
+func TestNotificationStreamerTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationStreamerTestSuite))
+}
+
+func (suite *NotificationStreamerTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.eventRepository = mockRepo.NewMockNotificationEventRepositoryUseCase(suite.mockCtrl)
+	suite.notificationRepository = mockRepo.NewMockNotificationRepositoryUseCase(suite.mockCtrl)
+
+	suite.notificationStreamer = service.NewNotificationStreamer(
+		config.Config{},
+		suite.eventRepository,
+		suite.notificationRepository,
+	)
+}
+
+func (suite *NotificationStreamerTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+func (suite *NotificationStreamerTestSuite) TestNotificationStreamer_Stream() {
+	userID := uuid.New()
+	otherID := uuid.New()
+
+	// subscribe runs the subscriber and returns the dispatcher it receives
+	subscribe := func(ctx context.Context) func(event *entity.NotificationEvent) {
+		handlers := make(chan func(event *entity.NotificationEvent), 1)
+		suite.eventRepository.EXPECT().Subscribe(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, handle func(event *entity.NotificationEvent)) error {
+			handlers <- handle
+			<-ctx.Done()
+			return nil
+		})
+
+		go suite.notificationStreamer.RunSubscriber(ctx)
+
+		return <-handlers
+	}
+
+	event := func(id string, userID uuid.UUID) *entity.NotificationEvent {
+		e := entity.NewNotificationEvent(entity.NotificationEventCreated, userID, nil)
+		e.ID = id
+		return e
+	}
+
+	suite.Run("stream the events addressed to the user and the broadcasts", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		dispatch := subscribe(ctx)
+
+		events, err := suite.notificationStreamer.Stream(ctx, userID, "")
+		suite.Require().NoError(err)
+
+		dispatch(event("1-0", otherID))
+		dispatch(event("2-0", userID))
+		dispatch(event("3-0", uuid.Nil))
+
+		suite.Equal("2-0", suite.receive(events).ID)
+		suite.Equal("3-0", suite.receive(events).ID)
+	})
+
+	suite.Run("replay the events after the last event id without repeating them", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		dispatch := subscribe(ctx)
+
+		suite.eventRepository.EXPECT().FindAfter(ctx, userID, "1-0").Return([]*entity.NotificationEvent{event("2-0", userID)}, nil)
+
+		events, err := suite.notificationStreamer.Stream(ctx, userID, "1-0")
+		suite.Require().NoError(err)
+
+		dispatch(event("2-0", userID))
+		dispatch(event("3-0", userID))
+
+		suite.Equal("2-0", suite.receive(events).ID)
+		suite.Equal("3-0", suite.receive(events).ID)
+	})
+
+	suite.Run("close the stream when the client falls behind", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		dispatch := subscribe(ctx)
+
+		events, err := suite.notificationStreamer.Stream(ctx, userID, "")
+		suite.Require().NoError(err)
+
+		for i := 0; i < 200; i++ {
+			dispatch(event("", userID))
+		}
+
+		deadline := time.After(time.Second)
+		for {
+			select {
+			case _, ok := <-events:
+				if !ok {
+					return
+				}
+			case <-deadline:
+				suite.FailNow("the stream was not closed")
+			}
+		}
+	})
+
+	suite.Run("close the stream when the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		events, err := suite.notificationStreamer.Stream(ctx, userID, "")
+		suite.Require().NoError(err)
+
+		cancel()
+
+		select {
+		case _, ok := <-events:
+			suite.False(ok)
+		case <-time.After(time.Second):
+			suite.FailNow("the stream was not closed")
+		}
+	})
+
+	suite.Run("fail when the events cannot be replayed", func() {
+		ctx := context.Background()
+		suite.eventRepository.EXPECT().FindAfter(ctx, userID, "1-0").Return(nil, errors.ErrInternalServerError.Error())
+
+		_, err := suite.notificationStreamer.Stream(ctx, userID, "1-0")
+
+		suite.Equal(errors.ErrInternalServerError.Error(), err)
+	})
+}
+
+func (suite *NotificationStreamerTestSuite) TestNotificationStreamer_CountUnread() {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	suite.Run("count the unread notifications", func() {
+		suite.notificationRepository.EXPECT().CountUnreadNotification(ctx, userID).Return(int64(3), nil)
+
+		total, err := suite.notificationStreamer.CountUnread(ctx, userID)
+
+		suite.NoError(err)
+		suite.Equal(int64(3), total)
+	})
+}
+
+// receive waits for the next streamed event
+func (suite *NotificationStreamerTestSuite) receive(events <-chan *entity.NotificationEvent) *entity.NotificationEvent {
+	select {
+	case event := <-events:
+		suite.Require().NotNil(event)
+		return event
+	case <-time.After(time.Second):
+		suite.FailNow("no event was streamed")
+		return nil
+	}
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	goErrors "errors"
+	"log"
+
+	"github.com/google/uuid"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/query"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/notification/v1/repository"
+	"gin-starter/utils"
+)
+
+// NotificationTemplateManager is a service for the notification templates managed in the CMS
+type NotificationTemplateManager struct {
+	cfg          config.Config
+	templateRepo repository.NotificationTemplateRepositoryUseCase
+}
+
+// NotificationTemplateManagerUseCase is a use case for managing the notification templates
+type NotificationTemplateManagerUseCase interface {
+	// GetTemplates gets the templates
+	GetTemplates(ctx context.Context, q *query.Query) ([]*entity.NotificationTemplate, *query.Page, error)
+	// GetTemplateByID gets a template
+	GetTemplateByID(ctx context.Context, id uuid.UUID) (*entity.NotificationTemplate, error)
+	// CreateTemplate creates a template as a draft
+	CreateTemplate(ctx context.Context, key, notifType, deepLink string, translations []*entity.NotificationTemplateTranslation, actorID uuid.UUID) (*entity.NotificationTemplate, error)
+	// UpdateTemplate updates a template and replaces its translations, keeping its status
+	UpdateTemplate(ctx context.Context, id uuid.UUID, key, notifType, deepLink string, translations []*entity.NotificationTemplateTranslation, actorID uuid.UUID) (*entity.NotificationTemplate, error)
+	// DeleteTemplate deletes a template
+	DeleteTemplate(ctx context.Context, id uuid.UUID, actorID uuid.UUID) error
+	// PublishTemplate lets the services send the template
+	PublishTemplate(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (*entity.NotificationTemplate, error)
+	// UnpublishTemplate turns the template back into a draft the services cannot send
+	UnpublishTemplate(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (*entity.NotificationTemplate, error)
+	// PreviewTemplate renders the template with sample data in the locale, or in every locale when empty
+	PreviewTemplate(ctx context.Context, id uuid.UUID, locale string, data map[string]string) ([]*entity.RenderedNotification, error)
+}
+
+// NewNotificationTemplateManager is a constructor for NotificationTemplateManager
+func NewNotificationTemplateManager(
+	cfg config.Config,
+	templateRepo repository.NotificationTemplateRepositoryUseCase,
+) *NotificationTemplateManager {
+	return &NotificationTemplateManager{
+		cfg:          cfg,
+		templateRepo: templateRepo,
+	}
+}
+
+// GetTemplates gets the templates
+func (tm *NotificationTemplateManager) GetTemplates(ctx context.Context, q *query.Query) ([]*entity.NotificationTemplate, *query.Page, error) {
+	if err := q.Validate(repository.NotificationTemplateListSchema); err != nil {
+		return nil, nil, err
+	}
+
+	templates, page, err := tm.templateRepo.FindAll(ctx, q)
+	if err != nil {
+		log.Println("[NotificationTemplateManager-GetTemplates]", err)
+		return nil, nil, errors.ErrInternalServerError.Error()
+	}
+
+	return templates, page, nil
+}
+
+// GetTemplateByID gets a template
+func (tm *NotificationTemplateManager) GetTemplateByID(ctx context.Context, id uuid.UUID) (*entity.NotificationTemplate, error) {
+	template, err := tm.templateRepo.FindByID(ctx, id)
+	if err != nil {
+		log.Println("[NotificationTemplateManager-GetTemplateByID]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if template == nil {
+		return nil, errors.ErrRecordNotFound.Error()
+	}
+
+	return template, nil
+}
+
+// CreateTemplate creates a template as a draft, it has to be translated to the fallback locale
+func (tm *NotificationTemplateManager) CreateTemplate(
+	ctx context.Context,
+	key, notifType, deepLink string,
+	translations []*entity.NotificationTemplateTranslation,
+	actorID uuid.UUID,
+) (*entity.NotificationTemplate, error) {
+	if err := validateNotificationTemplate(key, translations); err != nil {
+		return nil, err
+	}
+
+	if err := tm.checkKey(ctx, key, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	template := entity.NewNotificationTemplate(uuid.New(), key, notifType, deepLink, translations, actorID.String())
+
+	if err := tm.templateRepo.Create(ctx, template); err != nil {
+		if goErrors.Is(err, repository.ErrNotificationTemplateKeyConflict) {
+			return nil, errors.ErrNotificationTemplateKeyUsed.Error()
+		}
+
+		log.Println("[NotificationTemplateManager-CreateTemplate]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	return template, nil
+}
+
+// UpdateTemplate updates a template and replaces its translations, keeping its status.
+// Changes to a published template apply to the next notifications sent from it.
+func (tm *NotificationTemplateManager) UpdateTemplate(
+	ctx context.Context,
+	id uuid.UUID,
+	key, notifType, deepLink string,
+	translations []*entity.NotificationTemplateTranslation,
+	actorID uuid.UUID,
+) (*entity.NotificationTemplate, error) {
+	if err := validateNotificationTemplate(key, translations); err != nil {
+		return nil, err
+	}
+
+	template, err := tm.GetTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tm.checkKey(ctx, key, id); err != nil {
+		return nil, err
+	}
+
+	template.Key = key
+	template.Type = notifType
+	template.DeepLink = deepLink
+	template.Translations = translations
+	template.UpdatedBy = utils.StringToNullString(actorID.String())
+
+	if err := tm.save(ctx, template, "[NotificationTemplateManager-UpdateTemplate]"); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// DeleteTemplate deletes a template, the notifications already sent from it are kept
+func (tm *NotificationTemplateManager) DeleteTemplate(ctx context.Context, id uuid.UUID, actorID uuid.UUID) error {
+	if _, err := tm.GetTemplateByID(ctx, id); err != nil {
+		return err
+	}
+
+	if err := tm.templateRepo.Delete(ctx, id, actorID.String()); err != nil {
+		log.Println("[NotificationTemplateManager-DeleteTemplate]", err)
+		return errors.ErrInternalServerError.Error()
+	}
+
+	return nil
+}
+
+// PublishTemplate lets the services send the template
+func (tm *NotificationTemplateManager) PublishTemplate(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (*entity.NotificationTemplate, error) {
+	return tm.setStatus(ctx, id, entity.NotificationTemplatePublished, actorID)
+}
+
+// UnpublishTemplate turns the template back into a draft the services cannot send
+func (tm *NotificationTemplateManager) UnpublishTemplate(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (*entity.NotificationTemplate, error) {
+	return tm.setStatus(ctx, id, entity.NotificationTemplateDraft, actorID)
+}
+
+// PreviewTemplate renders the template with sample data in the locale, with the fallback of a send,
+// or in every locale it is translated to when empty. Missing data is reported rather than rejected.
+func (tm *NotificationTemplateManager) PreviewTemplate(ctx context.Context, id uuid.UUID, locale string, data map[string]string) ([]*entity.RenderedNotification, error) {
+	template, err := tm.GetTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if locale != "" {
+		return []*entity.RenderedNotification{template.Render(locale, data)}, nil
+	}
+
+	previews := make([]*entity.RenderedNotification, 0, len(template.Translations))
+	for _, translation := range template.Translations {
+		previews = append(previews, template.Render(translation.Locale, data))
+	}
+
+	return previews, nil
+}
+
+func (tm *NotificationTemplateManager) setStatus(ctx context.Context, id uuid.UUID, status string, actorID uuid.UUID) (*entity.NotificationTemplate, error) {
+	template, err := tm.GetTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if template.Status == status {
+		return template, nil
+	}
+
+	template.Status = status
+	template.UpdatedBy = utils.StringToNullString(actorID.String())
+
+	if err := tm.save(ctx, template, "[NotificationTemplateManager-setStatus]"); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (tm *NotificationTemplateManager) save(ctx context.Context, template *entity.NotificationTemplate, tag string) error {
+	if err := tm.templateRepo.Update(ctx, template); err != nil {
+		if goErrors.Is(err, repository.ErrNotificationTemplateKeyConflict) {
+			return errors.ErrNotificationTemplateKeyUsed.Error()
+		}
+
+		log.Println(tag, err)
+		return errors.ErrInternalServerError.Error()
+	}
+
+	return nil
+}
+
+// checkKey rejects a key another template than id already uses
+func (tm *NotificationTemplateManager) checkKey(ctx context.Context, key string, id uuid.UUID) error {
+	existing, err := tm.templateRepo.FindByKey(ctx, key)
+	if err != nil {
+		log.Println("[NotificationTemplateManager-checkKey]", err)
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if existing != nil && existing.ID != id {
+		return errors.ErrNotificationTemplateKeyUsed.Error()
+	}
+
+	return nil
+}
+
+// validateNotificationTemplate checks the key format and that the template is translated once per supported locale,
+// including the fallback locale
+func validateNotificationTemplate(key string, translations []*entity.NotificationTemplateTranslation) error {
+	if !entity.NotificationTemplateKey.MatchString(key) {
+		return errors.ErrInvalidNotificationTemplateKey.Error()
+	}
+
+	supported := make(map[string]bool)
+	for _, locale := range entity.NotificationTemplateLocales() {
+		supported[locale] = true
+	}
+
+	seen := make(map[string]bool, len(translations))
+	for _, translation := range translations {
+		if !supported[translation.Locale] || seen[translation.Locale] {
+			return errors.ErrInvalidNotificationTemplateLocale.Error()
+		}
+
+		seen[translation.Locale] = true
+	}
+
+	if !seen[entity.NotificationTemplateFallbackLocale()] {
+		return errors.ErrNotificationTemplateFallbackMissing.Error()
+	}
+
+	return nil
+}
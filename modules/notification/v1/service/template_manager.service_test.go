@@ -0,0 +1,206 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/notification/v1/repository"
+	"gin-starter/modules/notification/v1/service"
+	mockRepo "gin-starter/test/mock/modules/notification/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotificationTemplateManagerTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	templateRepository *mockRepo.MockNotificationTemplateRepositoryUseCase
+	templateManager    *service.NotificationTemplateManager
+}
+
+func TestNotificationTemplateManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationTemplateManagerTestSuite))
+}
+
+func (suite *NotificationTemplateManagerTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.templateRepository = mockRepo.NewMockNotificationTemplateRepositoryUseCase(suite.mockCtrl)
+
+	suite.templateManager = service.NewNotificationTemplateManager(config.Config{}, suite.templateRepository)
+}
+
+func (suite *NotificationTemplateManagerTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+// welcomeTranslations are the translations of a welcome template to the supported languages
+func welcomeTranslations() []*entity.NotificationTemplateTranslation {
+	return []*entity.NotificationTemplateTranslation{
+		{Locale: "id", Title: "Halo {{name}}", Body: "Selamat datang"},
+		{Locale: "en", Title: "Hi {{name}}", Body: "Welcome"},
+	}
+}
+
+func (suite *NotificationTemplateManagerTestSuite) TestNotificationTemplateManager_CreateTemplate() {
+	ctx := context.Background()
+	actorID := uuid.New()
+
+	suite.Run("create the template as a draft", func() {
+		suite.templateRepository.EXPECT().FindByKey(ctx, "user.welcome").Return(nil, nil)
+		suite.templateRepository.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		template, err := suite.templateManager.CreateTemplate(ctx, "user.welcome", "announcement", "app://home", welcomeTranslations(), actorID)
+
+		suite.Require().NoError(err)
+		suite.Equal(entity.NotificationTemplateDraft, template.Status)
+		suite.Equal(template.ID, template.Translations[0].TemplateID)
+		suite.Equal([]string{"name"}, template.Variables())
+	})
+
+	suite.Run("fail when the key is malformed", func() {
+		_, err := suite.templateManager.CreateTemplate(ctx, "User Welcome", "announcement", "", welcomeTranslations(), actorID)
+
+		suite.Equal(errors.ErrInvalidNotificationTemplateKey.Error(), err)
+	})
+
+	suite.Run("fail when a locale is not supported", func() {
+		unsupported := append(welcomeTranslations(), &entity.NotificationTemplateTranslation{Locale: "fr", Title: "Salut", Body: "Bienvenue"})
+
+		_, err := suite.templateManager.CreateTemplate(ctx, "user.welcome", "announcement", "", unsupported, actorID)
+
+		suite.Equal(errors.ErrInvalidNotificationTemplateLocale.Error(), err)
+	})
+
+	suite.Run("fail without the translation of the default language", func() {
+		_, err := suite.templateManager.CreateTemplate(ctx, "user.welcome", "announcement", "", welcomeTranslations()[1:], actorID)
+
+		suite.Equal(errors.ErrNotificationTemplateFallbackMissing.Error(), err)
+	})
+
+	suite.Run("fail when the key is used", func() {
+		suite.templateRepository.EXPECT().FindByKey(ctx, "user.welcome").Return(&entity.NotificationTemplate{ID: uuid.New()}, nil)
+
+		_, err := suite.templateManager.CreateTemplate(ctx, "user.welcome", "announcement", "", welcomeTranslations(), actorID)
+
+		suite.Equal(errors.ErrNotificationTemplateKeyUsed.Error(), err)
+	})
+
+	suite.Run("fail when another template takes the key meanwhile", func() {
+		suite.templateRepository.EXPECT().FindByKey(ctx, "user.welcome").Return(nil, nil)
+		suite.templateRepository.EXPECT().Create(ctx, gomock.Any()).Return(repository.ErrNotificationTemplateKeyConflict)
+
+		_, err := suite.templateManager.CreateTemplate(ctx, "user.welcome", "announcement", "", welcomeTranslations(), actorID)
+
+		suite.Equal(errors.ErrNotificationTemplateKeyUsed.Error(), err)
+	})
+}
+
+func (suite *NotificationTemplateManagerTestSuite) TestNotificationTemplateManager_UpdateTemplate() {
+	ctx := context.Background()
+	actorID := uuid.New()
+
+	suite.Run("update a published template and keep it published", func() {
+		template := entity.NewNotificationTemplate(uuid.New(), "user.welcome", "announcement", "", welcomeTranslations(), "system")
+		template.Status = entity.NotificationTemplatePublished
+
+		suite.templateRepository.EXPECT().FindByID(ctx, template.ID).Return(template, nil)
+		suite.templateRepository.EXPECT().FindByKey(ctx, "user.welcome").Return(template, nil)
+		suite.templateRepository.EXPECT().Update(ctx, template).Return(nil)
+
+		res, err := suite.templateManager.UpdateTemplate(ctx, template.ID, "user.welcome", "announcement", "app://home", welcomeTranslations()[:1], actorID)
+
+		suite.Require().NoError(err)
+		suite.Equal(entity.NotificationTemplatePublished, res.Status)
+		suite.Equal("app://home", res.DeepLink)
+		suite.Len(res.Translations, 1)
+		suite.Equal(actorID.String(), res.UpdatedBy.String)
+	})
+
+	suite.Run("fail when the template does not exist", func() {
+		id := uuid.New()
+		suite.templateRepository.EXPECT().FindByID(ctx, id).Return(nil, nil)
+
+		_, err := suite.templateManager.UpdateTemplate(ctx, id, "user.welcome", "announcement", "", welcomeTranslations(), actorID)
+
+		suite.Equal(errors.ErrRecordNotFound.Error(), err)
+	})
+}
+
+func (suite *NotificationTemplateManagerTestSuite) TestNotificationTemplateManager_PublishTemplate() {
+	ctx := context.Background()
+	actorID := uuid.New()
+
+	suite.Run("publish a draft", func() {
+		template := entity.NewNotificationTemplate(uuid.New(), "user.welcome", "announcement", "", welcomeTranslations(), "system")
+
+		suite.templateRepository.EXPECT().FindByID(ctx, template.ID).Return(template, nil)
+		suite.templateRepository.EXPECT().Update(ctx, template).Return(nil)
+
+		res, err := suite.templateManager.PublishTemplate(ctx, template.ID, actorID)
+
+		suite.NoError(err)
+		suite.Equal(entity.NotificationTemplatePublished, res.Status)
+	})
+
+	suite.Run("leave a published template as it is", func() {
+		template := entity.NewNotificationTemplate(uuid.New(), "user.welcome", "announcement", "", welcomeTranslations(), "system")
+		template.Status = entity.NotificationTemplatePublished
+
+		suite.templateRepository.EXPECT().FindByID(ctx, template.ID).Return(template, nil)
+
+		_, err := suite.templateManager.PublishTemplate(ctx, template.ID, actorID)
+
+		suite.NoError(err)
+	})
+}
+
+func (suite *NotificationTemplateManagerTestSuite) TestNotificationTemplateManager_PreviewTemplate() {
+	ctx := context.Background()
+	template := entity.NewNotificationTemplate(uuid.New(), "user.welcome", "announcement", "app://users/{{id}}", welcomeTranslations(), "system")
+
+	suite.Run("render every language with the sample data and report the missing variables", func() {
+		suite.templateRepository.EXPECT().FindByID(ctx, template.ID).Return(template, nil)
+
+		res, err := suite.templateManager.PreviewTemplate(ctx, template.ID, "", map[string]string{"name": "Budi"})
+
+		suite.Require().NoError(err)
+		suite.Require().Len(res, 2)
+		suite.Equal("id", res[0].Locale)
+		suite.Equal("Halo Budi", res[0].Title)
+		suite.Equal("Hi Budi", res[1].Title)
+		suite.Equal("app://users/{{id}}", res[1].DeepLink)
+		suite.Equal([]string{"id"}, res[1].Missing)
+	})
+
+	suite.Run("render a single language with the fallback of a send", func() {
+		suite.templateRepository.EXPECT().FindByID(ctx, template.ID).Return(template, nil)
+
+		res, err := suite.templateManager.PreviewTemplate(ctx, template.ID, "fr", map[string]string{"name": "Budi", "id": "1"})
+
+		suite.Require().NoError(err)
+		suite.Require().Len(res, 1)
+		suite.Equal("id", res[0].Locale)
+		suite.Empty(res[0].Missing)
+	})
+}
+
+func (suite *NotificationTemplateManagerTestSuite) TestNotificationTemplateManager_DeleteTemplate() {
+	ctx := context.Background()
+	actorID := uuid.New()
+	id := uuid.New()
+
+	suite.Run("delete the template", func() {
+		suite.templateRepository.EXPECT().FindByID(ctx, id).Return(&entity.NotificationTemplate{ID: id}, nil)
+		suite.templateRepository.EXPECT().Delete(ctx, id, actorID.String()).Return(nil)
+
+		err := suite.templateManager.DeleteTemplate(ctx, id, actorID)
+
+		suite.NoError(err)
+	})
+}
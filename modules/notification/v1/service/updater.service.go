@@ -2,68 +2,83 @@ package service
 
 import (
 	"context"
+	"log"
 
 	"github.com/google/uuid"
-	"github.com/rifqiakrm/onesignal-go-lib"
 
+	"gin-starter/common/errors"
 	"gin-starter/config"
+	"gin-starter/entity"
 	"gin-starter/modules/notification/v1/repository"
 )
 
 type NotificationUpdater struct {
 	cfg              config.Config
 	notificationRepo repository.NotificationRepositoryUseCase
+	eventRepo        repository.NotificationEventRepositoryUseCase
 }
 
 type NotificationUpdaterUseCase interface {
-	RegisterUnregisterPlayerID(ctx context.Context, userID uuid.UUID, playerID uuid.UUID, typeReg string) error
+	// UpdateReadNotification marks every notification of the user as read
 	UpdateReadNotification(ctx context.Context, id uuid.UUID) error
+	// MarkRead marks the notifications of the user as read
+	MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error
+	// Archive moves the notifications of the user out of the inbox
+	Archive(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error
+	// Delete hides the notifications from the user, broadcasts stay visible to everyone else
+	Delete(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error
 }
 
 func NewNotificationUpdater(
 	cfg config.Config,
 	notificationRepo repository.NotificationRepositoryUseCase,
+	eventRepo repository.NotificationEventRepositoryUseCase,
 ) *NotificationUpdater {
 	return &NotificationUpdater{
 		cfg:              cfg,
 		notificationRepo: notificationRepo,
+		eventRepo:        eventRepo,
 	}
 }
 
-func (nu *NotificationUpdater) RegisterUnregisterPlayerID(ctx context.Context, userID uuid.UUID, playerID uuid.UUID, typeReg string) error {
-	var tags map[string]string
-
-	if typeReg == "reg" {
-		tags = map[string]string{
-			"uuid":      userID.String(),
-			"logged_in": "true",
-		}
-	} else {
-		tags = map[string]string{
-			"uuid":      "",
-			"logged_in": "false",
-		}
-	}
+// UpdateReadNotification marks every notification of the user as read and streams the change to the signed in clients
+func (nu *NotificationUpdater) UpdateReadNotification(ctx context.Context, id uuid.UUID) error {
+	return nu.update(ctx, id, entity.NotificationReceiptRead, entity.NotificationEventRead, nil)
+}
 
-	client := onesignal.NewClient(nil)
+// MarkRead marks the notifications of the user as read and streams the change to the signed in clients
+func (nu *NotificationUpdater) MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error {
+	return nu.update(ctx, userID, entity.NotificationReceiptRead, entity.NotificationEventRead, ids)
+}
 
-	player := &onesignal.PlayerRequest{
-		AppID: nu.cfg.OneSignal.AppID,
-		Tags:  tags,
-	}
+// Archive moves the notifications of the user out of the inbox and streams the change to the signed in clients
+func (nu *NotificationUpdater) Archive(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error {
+	return nu.update(ctx, userID, entity.NotificationReceiptArchived, entity.NotificationEventArchived, ids)
+}
 
-	_, _, err := client.Players.Update(playerID.String(), player)
+// Delete hides the notifications from the user and streams the change to the signed in clients
+func (nu *NotificationUpdater) Delete(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error {
+	return nu.update(ctx, userID, entity.NotificationReceiptDeleted, entity.NotificationEventDeleted, ids)
+}
 
+// update sets the receipt state of the notifications of the user, of all of them when ids is empty.
+// It fails with ErrRecordNotFound when none of the ids is a notification of the user.
+func (nu *NotificationUpdater) update(ctx context.Context, userID uuid.UUID, state, eventType string, ids []uuid.UUID) error {
+	total, err := nu.notificationRepo.UpdateReceipts(ctx, userID, state, ids)
 	if err != nil {
-		return err
+		log.Println("[NotificationUpdater-update]", err)
+		return errors.ErrInternalServerError.Error()
 	}
 
-	return nil
-}
+	if len(ids) > 0 && total == 0 {
+		return errors.ErrRecordNotFound.Error()
+	}
 
-func (nu *NotificationUpdater) UpdateReadNotification(ctx context.Context, id uuid.UUID) error {
-	if err := nu.notificationRepo.UpdateReadNotification(ctx, id); err != nil {
-		return err
+	event := entity.NewNotificationEvent(eventType, userID, nil)
+	event.NotificationIDs = ids
+
+	if err := nu.eventRepo.Publish(ctx, event); err != nil {
+		log.Println("[NotificationUpdater-update]", err)
 	}
 
 	return nil
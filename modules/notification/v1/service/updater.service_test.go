@@ -0,0 +1,151 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/notification/v1/service"
+	mockRepo "gin-starter/test/mock/modules/notification/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotificationUpdaterTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	notificationRepository *mockRepo.MockNotificationRepositoryUseCase
+	eventRepository        *mockRepo.MockNotificationEventRepositoryUseCase
+	notificationUpdater    *service.NotificationUpdater
+}
+
+func TestNotificationUpdaterTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationUpdaterTestSuite))
+}
+
+func (suite *NotificationUpdaterTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.notificationRepository = mockRepo.NewMockNotificationRepositoryUseCase(suite.mockCtrl)
+	suite.eventRepository = mockRepo.NewMockNotificationEventRepositoryUseCase(suite.mockCtrl)
+
+	suite.notificationUpdater = service.NewNotificationUpdater(
+		config.Config{},
+		suite.notificationRepository,
+		suite.eventRepository,
+	)
+}
+
+func (suite *NotificationUpdaterTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+func (suite *NotificationUpdaterTestSuite) TestNotificationUpdater_UpdateReadNotification() {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	suite.Run("mark every notification of the user as read", func() {
+		suite.notificationRepository.EXPECT().UpdateReceipts(ctx, userID, entity.NotificationReceiptRead, []uuid.UUID(nil)).Return(int64(0), nil)
+		suite.eventRepository.EXPECT().Publish(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.NotificationEvent) error {
+			suite.Equal(entity.NotificationEventRead, e.Type)
+			suite.Equal(userID, e.UserID)
+			suite.Empty(e.NotificationIDs)
+			return nil
+		})
+
+		err := suite.notificationUpdater.UpdateReadNotification(ctx, userID)
+
+		suite.NoError(err)
+	})
+}
+
+func (suite *NotificationUpdaterTestSuite) TestNotificationUpdater_MarkRead() {
+	ctx := context.Background()
+	userID := uuid.New()
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	suite.Run("mark the notifications as read for the user only", func() {
+		suite.notificationRepository.EXPECT().UpdateReceipts(ctx, userID, entity.NotificationReceiptRead, ids).Return(int64(2), nil)
+		suite.eventRepository.EXPECT().Publish(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.NotificationEvent) error {
+			suite.Equal(ids, e.NotificationIDs)
+			return nil
+		})
+
+		err := suite.notificationUpdater.MarkRead(ctx, userID, ids)
+
+		suite.NoError(err)
+	})
+
+	suite.Run("mark as read when the change cannot be streamed", func() {
+		suite.notificationRepository.EXPECT().UpdateReceipts(ctx, userID, entity.NotificationReceiptRead, ids).Return(int64(2), nil)
+		suite.eventRepository.EXPECT().Publish(ctx, gomock.Any()).Return(errors.ErrInternalServerError.Error())
+
+		err := suite.notificationUpdater.MarkRead(ctx, userID, ids)
+
+		suite.NoError(err)
+	})
+
+	suite.Run("fail when none of the notifications is addressed to the user", func() {
+		suite.notificationRepository.EXPECT().UpdateReceipts(ctx, userID, entity.NotificationReceiptRead, ids).Return(int64(0), nil)
+
+		err := suite.notificationUpdater.MarkRead(ctx, userID, ids)
+
+		suite.Equal(errors.ErrRecordNotFound.Error(), err)
+	})
+
+	suite.Run("fail when the receipts cannot be updated", func() {
+		suite.notificationRepository.EXPECT().UpdateReceipts(ctx, userID, entity.NotificationReceiptRead, ids).Return(int64(0), errors.ErrInternalServerError.Error())
+
+		err := suite.notificationUpdater.MarkRead(ctx, userID, ids)
+
+		suite.Equal(errors.ErrInternalServerError.Error(), err)
+	})
+}
+
+func (suite *NotificationUpdaterTestSuite) TestNotificationUpdater_Archive() {
+	ctx := context.Background()
+	userID := uuid.New()
+	ids := []uuid.UUID{uuid.New()}
+
+	suite.Run("archive the notifications for the user", func() {
+		suite.notificationRepository.EXPECT().UpdateReceipts(ctx, userID, entity.NotificationReceiptArchived, ids).Return(int64(1), nil)
+		suite.eventRepository.EXPECT().Publish(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.NotificationEvent) error {
+			suite.Equal(entity.NotificationEventArchived, e.Type)
+			return nil
+		})
+
+		err := suite.notificationUpdater.Archive(ctx, userID, ids)
+
+		suite.NoError(err)
+	})
+}
+
+func (suite *NotificationUpdaterTestSuite) TestNotificationUpdater_Delete() {
+	ctx := context.Background()
+	userID := uuid.New()
+	ids := []uuid.UUID{uuid.New()}
+
+	suite.Run("delete the notifications for the user", func() {
+		suite.notificationRepository.EXPECT().UpdateReceipts(ctx, userID, entity.NotificationReceiptDeleted, ids).Return(int64(1), nil)
+		suite.eventRepository.EXPECT().Publish(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.NotificationEvent) error {
+			suite.Equal(entity.NotificationEventDeleted, e.Type)
+			return nil
+		})
+
+		err := suite.notificationUpdater.Delete(ctx, userID, ids)
+
+		suite.NoError(err)
+	})
+
+	suite.Run("fail when the notifications were already deleted", func() {
+		suite.notificationRepository.EXPECT().UpdateReceipts(ctx, userID, entity.NotificationReceiptDeleted, ids).Return(int64(0), nil)
+
+		err := suite.notificationUpdater.Delete(ctx, userID, ids)
+
+		suite.Equal(errors.ErrRecordNotFound.Error(), err)
+	})
+}
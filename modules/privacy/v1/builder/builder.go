@@ -0,0 +1,41 @@
+package builder
+
+import (
+	"context"
+
+	"gin-starter/app"
+	"gin-starter/config"
+	"gin-starter/modules/privacy/v1/repository"
+	"gin-starter/modules/privacy/v1/service"
+	"gin-starter/sdk/gcs"
+	"gin-starter/utils"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/gin-gonic/gin"
+	"github.com/gomodule/redigo/redis"
+	"gorm.io/gorm"
+)
+
+// BuildPrivacyHandler builds privacy handler
+// starting from handler down to repository or tool.
+func BuildPrivacyHandler(cfg config.Config, router *gin.Engine, db *gorm.DB, redisPool *redis.Pool, awsSession *session.Session) {
+	// Cache
+	cache := utils.NewClient(redisPool)
+
+	// Cloud Storage
+	cloudStorage := gcs.NewGoogleCloudStorage(cfg)
+	// cloudStorage := aws.NewS3Bucket(cfg, awsSession)
+
+	// Repository
+	pr := repository.NewPrivacyRepository(db, cache)
+
+	// Service
+	de := service.NewDataExporter(cfg, pr, cloudStorage)
+	dr := service.NewDataEraser(cfg, pr, cloudStorage)
+	dw := service.NewDataRequestWorker(cfg, pr, de, dr)
+
+	go dw.Run(context.Background())
+
+	// Handler
+	app.PrivacyHTTPHandler(cfg, router, de, dr)
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"gin-starter/common/errors"
+	"gin-starter/middleware"
+	"gin-starter/modules/privacy/v1/service"
+	"gin-starter/resource"
+	"gin-starter/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DataRequestHandler is a handler for personal data export and erasure requests
+type DataRequestHandler struct {
+	dataExporter service.DataExporterUseCase
+	dataEraser   service.DataEraserUseCase
+}
+
+// NewDataRequestHandler is a constructor for DataRequestHandler
+func NewDataRequestHandler(
+	dataExporter service.DataExporterUseCase,
+	dataEraser service.DataEraserUseCase,
+) *DataRequestHandler {
+	return &DataRequestHandler{
+		dataExporter: dataExporter,
+		dataEraser:   dataEraser,
+	}
+}
+
+// RequestExport is a handler for requesting an export of the personal data of the current user
+func (dr *DataRequestHandler) RequestExport(c *gin.Context) {
+	request, err := dr.dataExporter.RequestExport(c, middleware.UserID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response.SuccessAPIResponseList(http.StatusAccepted, "success", resource.NewDataRequestResponse(request)))
+}
+
+// GetExport is a handler for getting an export request of the current user
+func (dr *DataRequestHandler) GetExport(c *gin.Context) {
+	reqID, ok := bindExportID(c)
+	if !ok {
+		return
+	}
+
+	request, err := dr.dataExporter.GetExport(c, middleware.UserID, reqID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewDataRequestResponse(request)))
+}
+
+// DownloadExport is a handler for downloading the archive of a completed export of the current user
+func (dr *DataRequestHandler) DownloadExport(c *gin.Context) {
+	reqID, ok := bindExportID(c)
+	if !ok {
+		return
+	}
+
+	file, err := dr.dataExporter.DownloadExport(c, middleware.UserID, reqID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=data-export-%s.zip", reqID.String()))
+	c.Status(http.StatusOK)
+
+	if _, err := io.Copy(c.Writer, file); err != nil {
+		log.Println("[DataRequestHandler-DownloadExport]", err)
+	}
+}
+
+// RequestErasure is a handler for scheduling the erasure of the personal data of the current user
+func (dr *DataRequestHandler) RequestErasure(c *gin.Context) {
+	request, err := dr.dataEraser.RequestErasure(c, middleware.UserID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response.SuccessAPIResponseList(http.StatusAccepted, "success", resource.NewDataRequestResponse(request)))
+}
+
+// GetErasure is a handler for getting the scheduled erasure of the current user
+func (dr *DataRequestHandler) GetErasure(c *gin.Context) {
+	request, err := dr.dataEraser.GetErasure(c, middleware.UserID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewDataRequestResponse(request)))
+}
+
+// CancelErasure is a handler for cancelling the scheduled erasure of the current user
+func (dr *DataRequestHandler) CancelErasure(c *gin.Context) {
+	if err := dr.dataEraser.CancelErasure(c, middleware.UserID); err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
+}
+
+// bindExportID binds the export id of the uri, writing the error response when it is invalid
+func bindExportID(c *gin.Context) (uuid.UUID, bool) {
+	var request resource.DataExportRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return uuid.Nil, false
+	}
+
+	reqID, err := uuid.Parse(request.ID)
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return uuid.Nil, false
+	}
+
+	return reqID, true
+}
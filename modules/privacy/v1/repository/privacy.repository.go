@@ -0,0 +1,482 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	commonCache "gin-starter/common/cache"
+	"gin-starter/common/interfaces"
+	"gin-starter/entity"
+	userRepo "gin-starter/modules/user/v1/repository"
+	"gin-starter/utils"
+)
+
+// ErrLastSuperAdmin is returned when the user to erase is the last active super admin
+var ErrLastSuperAdmin = userRepo.ErrLastSuperAdmin
+
+// PrivacyRepository is a repository for data-subject requests
+type PrivacyRepository struct {
+	db    *gorm.DB
+	cache interfaces.Cacheable
+}
+
+// PrivacyRepositoryUseCase is a use case for data-subject requests
+type PrivacyRepositoryUseCase interface {
+	// CreateRequest creates a data request
+	CreateRequest(ctx context.Context, request *entity.DataRequest) error
+	// FindRequestByID finds a data request by id
+	FindRequestByID(ctx context.Context, id uuid.UUID) (*entity.DataRequest, error)
+	// FindOpenRequest finds the pending or processing request of the given type of a user
+	FindOpenRequest(ctx context.Context, userID uuid.UUID, requestType string) (*entity.DataRequest, error)
+	// FindDueRequests finds the pending requests scheduled before now and the processing requests stuck since staleBefore
+	FindDueRequests(ctx context.Context, now, staleBefore time.Time, limit int) ([]*entity.DataRequest, error)
+	// FindExpiredExports finds the completed exports whose download link expired before now
+	FindExpiredExports(ctx context.Context, now time.Time) ([]*entity.DataRequest, error)
+	// FindExportsWithFile finds the exports of a user whose file is still stored
+	FindExportsWithFile(ctx context.Context, userID uuid.UUID) ([]*entity.DataRequest, error)
+	// ClaimRequest marks a due request as processing, returning false when another worker claimed it first
+	ClaimRequest(ctx context.Context, id uuid.UUID, staleBefore time.Time) (bool, error)
+	// CancelRequest cancels a pending data request, returning false when it is no longer pending
+	CancelRequest(ctx context.Context, id uuid.UUID, cancelledBy string) (bool, error)
+	// UpdateRequest updates the status, file and timestamps of a data request
+	UpdateRequest(ctx context.Context, request *entity.DataRequest) error
+	// GetUserByID finds a user by id
+	GetUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
+	// GetNotificationsByUserID finds the notifications addressed to a user with their read state
+	GetNotificationsByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Notification, error)
+	// GetActivitiesByUserID finds the activities of a user
+	GetActivitiesByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Activities, error)
+	// GetEmailsSentTo finds the emails sent to an address
+	GetEmailsSentTo(ctx context.Context, email string) ([]*entity.EmailSent, error)
+	// GetPreferencesByUserID finds the preferences a user set away from their default
+	GetPreferencesByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserPreference, error)
+	// GetDevicesByUserID finds the devices push notifications are delivered to for a user
+	GetDevicesByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserDevice, error)
+	// IsPhotoShared checks whether another user, including deleted ones, references the same photo
+	IsPhotoShared(ctx context.Context, photo string, userID uuid.UUID) (bool, error)
+	// KeepSuperAdmin fails with ErrLastSuperAdmin when the user is the last active super admin
+	KeepSuperAdmin(ctx context.Context, userID uuid.UUID) error
+	// Anonymise replaces the personal data of a user in place, rejected with ErrLastSuperAdmin for the last active super admin
+	Anonymise(ctx context.Context, user *entity.User, anonymised *entity.User) error
+}
+
+// NewPrivacyRepository creates a new PrivacyRepository
+func NewPrivacyRepository(db *gorm.DB, cache interfaces.Cacheable) *PrivacyRepository {
+	return &PrivacyRepository{db, cache}
+}
+
+// CreateRequest creates a data request
+func (pr *PrivacyRepository) CreateRequest(ctx context.Context, request *entity.DataRequest) error {
+	if err := pr.db.
+		WithContext(ctx).
+		Model(&entity.DataRequest{}).
+		Create(request).
+		Error; err != nil {
+		return errors.Wrap(err, "[PrivacyRepository-CreateRequest] error while creating data request")
+	}
+
+	return nil
+}
+
+// FindRequestByID finds a data request by id
+func (pr *PrivacyRepository) FindRequestByID(ctx context.Context, id uuid.UUID) (*entity.DataRequest, error) {
+	request := new(entity.DataRequest)
+
+	if err := pr.db.
+		WithContext(ctx).
+		Where("id = ?", id).
+		First(request).
+		Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "[PrivacyRepository-FindRequestByID] error while getting data request")
+	}
+
+	return request, nil
+}
+
+// FindOpenRequest finds the pending or processing request of the given type of a user
+func (pr *PrivacyRepository) FindOpenRequest(ctx context.Context, userID uuid.UUID, requestType string) (*entity.DataRequest, error) {
+	request := new(entity.DataRequest)
+
+	if err := pr.db.
+		WithContext(ctx).
+		Where("user_id = ? AND type = ?", userID, requestType).
+		Where("status IN ?", []string{entity.DataRequestStatusPending, entity.DataRequestStatusProcessing}).
+		Order("created_at desc").
+		First(request).
+		Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "[PrivacyRepository-FindOpenRequest] error while getting data request")
+	}
+
+	return request, nil
+}
+
+// FindDueRequests finds the pending requests scheduled before now and the processing requests stuck since staleBefore
+func (pr *PrivacyRepository) FindDueRequests(ctx context.Context, now, staleBefore time.Time, limit int) ([]*entity.DataRequest, error) {
+	requests := make([]*entity.DataRequest, 0)
+
+	if err := pr.db.
+		WithContext(ctx).
+		Where("scheduled_at <= ?", now).
+		Where("status = ? OR (status = ? AND updated_at < ?)", entity.DataRequestStatusPending, entity.DataRequestStatusProcessing, staleBefore).
+		Order("scheduled_at asc").
+		Limit(limit).
+		Find(&requests).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "[PrivacyRepository-FindDueRequests] error while getting due data requests")
+	}
+
+	return requests, nil
+}
+
+// FindExpiredExports finds the completed exports whose download link expired before now
+func (pr *PrivacyRepository) FindExpiredExports(ctx context.Context, now time.Time) ([]*entity.DataRequest, error) {
+	requests := make([]*entity.DataRequest, 0)
+
+	if err := pr.db.
+		WithContext(ctx).
+		Where("type = ? AND status = ?", entity.DataRequestTypeExport, entity.DataRequestStatusCompleted).
+		Where("expires_at < ?", now).
+		Find(&requests).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "[PrivacyRepository-FindExpiredExports] error while getting expired exports")
+	}
+
+	return requests, nil
+}
+
+// FindExportsWithFile finds the exports of a user whose file is still stored
+func (pr *PrivacyRepository) FindExportsWithFile(ctx context.Context, userID uuid.UUID) ([]*entity.DataRequest, error) {
+	requests := make([]*entity.DataRequest, 0)
+
+	if err := pr.db.
+		WithContext(ctx).
+		Where("user_id = ? AND type = ?", userID, entity.DataRequestTypeExport).
+		Where("file_path <> ''").
+		Find(&requests).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "[PrivacyRepository-FindExportsWithFile] error while getting exports")
+	}
+
+	return requests, nil
+}
+
+// ClaimRequest marks a due request as processing, returning false when another worker claimed it first
+func (pr *PrivacyRepository) ClaimRequest(ctx context.Context, id uuid.UUID, staleBefore time.Time) (bool, error) {
+	result := pr.db.
+		WithContext(ctx).
+		Model(&entity.DataRequest{}).
+		Where("id = ?", id).
+		Where("status = ? OR (status = ? AND updated_at < ?)", entity.DataRequestStatusPending, entity.DataRequestStatusProcessing, staleBefore).
+		Updates(map[string]interface{}{
+			"status":     entity.DataRequestStatusProcessing,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, errors.Wrap(result.Error, "[PrivacyRepository-ClaimRequest] error while claiming data request")
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// CancelRequest cancels a pending data request, returning false when it is no longer pending.
+// A worker claiming the request at the same time either claims it first or finds it cancelled.
+func (pr *PrivacyRepository) CancelRequest(ctx context.Context, id uuid.UUID, cancelledBy string) (bool, error) {
+	result := pr.db.
+		WithContext(ctx).
+		Model(&entity.DataRequest{}).
+		Where("id = ? AND status = ?", id, entity.DataRequestStatusPending).
+		Updates(map[string]interface{}{
+			"status":     entity.DataRequestStatusCancelled,
+			"updated_by": utils.StringToNullString(cancelledBy),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, errors.Wrap(result.Error, "[PrivacyRepository-CancelRequest] error while cancelling data request")
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// UpdateRequest updates the status, file and timestamps of a data request
+func (pr *PrivacyRepository) UpdateRequest(ctx context.Context, request *entity.DataRequest) error {
+	request.UpdatedAt = time.Now()
+
+	if err := pr.db.
+		WithContext(ctx).
+		Model(&entity.DataRequest{}).
+		Where("id = ?", request.ID).
+		Updates(map[string]interface{}{
+			"status":       request.Status,
+			"file_path":    request.FilePath,
+			"expires_at":   request.ExpiresAt,
+			"completed_at": request.CompletedAt,
+			"updated_by":   request.UpdatedBy,
+			"updated_at":   request.UpdatedAt,
+		}).
+		Error; err != nil {
+		return errors.Wrap(err, "[PrivacyRepository-UpdateRequest] error while updating data request")
+	}
+
+	return nil
+}
+
+// GetUserByID finds a user by id
+func (pr *PrivacyRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	user := new(entity.User)
+
+	if err := pr.db.
+		WithContext(ctx).
+		Where("id = ?", id).
+		First(user).
+		Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "[PrivacyRepository-GetUserByID] error while getting user")
+	}
+
+	return user, nil
+}
+
+// GetNotificationsByUserID finds the notifications addressed to a user with their read state
+func (pr *PrivacyRepository) GetNotificationsByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Notification, error) {
+	notifications := make([]*entity.Notification, 0)
+
+	if err := pr.db.
+		WithContext(ctx).
+		Model(&entity.Notification{}).
+		Select("main.notifications.*, receipts.read_at IS NOT NULL AS is_read, receipts.archived_at IS NOT NULL AS is_archived").
+		Joins("LEFT JOIN main.notification_receipts receipts ON receipts.notification_id = main.notifications.id AND receipts.user_id = main.notifications.user_id").
+		Where("main.notifications.user_id = ?", userID.String()).
+		Order("main.notifications.created_at asc").
+		Find(&notifications).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "[PrivacyRepository-GetNotificationsByUserID] error while getting notifications")
+	}
+
+	return notifications, nil
+}
+
+// GetActivitiesByUserID finds the activities of a user
+func (pr *PrivacyRepository) GetActivitiesByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Activities, error) {
+	activities := make([]*entity.Activities, 0)
+
+	if err := pr.db.
+		WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at asc").
+		Find(&activities).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "[PrivacyRepository-GetActivitiesByUserID] error while getting activities")
+	}
+
+	return activities, nil
+}
+
+// GetEmailsSentTo finds the emails sent to an address
+func (pr *PrivacyRepository) GetEmailsSentTo(ctx context.Context, email string) ([]*entity.EmailSent, error) {
+	emails := make([]*entity.EmailSent, 0)
+
+	if err := pr.db.
+		WithContext(ctx).
+		Where(`LOWER("to") = LOWER(?)`, email).
+		Order("created_at asc").
+		Find(&emails).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "[PrivacyRepository-GetEmailsSentTo] error while getting sent emails")
+	}
+
+	return emails, nil
+}
+
+// GetPreferencesByUserID finds the preferences a user set away from their default
+func (pr *PrivacyRepository) GetPreferencesByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserPreference, error) {
+	preferences := make([]*entity.UserPreference, 0)
+
+	if err := pr.db.
+		WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("key asc").
+		Find(&preferences).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "[PrivacyRepository-GetPreferencesByUserID] error while getting preferences")
+	}
+
+	return preferences, nil
+}
+
+// GetDevicesByUserID finds the devices push notifications are delivered to for a user
+func (pr *PrivacyRepository) GetDevicesByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserDevice, error) {
+	devices := make([]*entity.UserDevice, 0)
+
+	if err := pr.db.
+		WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("last_seen_at desc").
+		Find(&devices).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "[PrivacyRepository-GetDevicesByUserID] error while getting devices")
+	}
+
+	return devices, nil
+}
+
+// IsPhotoShared checks whether another user, including deleted ones, references the same photo
+func (pr *PrivacyRepository) IsPhotoShared(ctx context.Context, photo string, userID uuid.UUID) (bool, error) {
+	var total int64
+
+	if err := pr.db.
+		WithContext(ctx).
+		Unscoped().
+		Model(&entity.User{}).
+		Where("photo = ? AND id <> ?", photo, userID).
+		Count(&total).
+		Error; err != nil {
+		return false, errors.Wrap(err, "[PrivacyRepository-IsPhotoShared] error while counting users")
+	}
+
+	return total > 0, nil
+}
+
+// KeepSuperAdmin fails with ErrLastSuperAdmin when the user is the last active super admin
+func (pr *PrivacyRepository) KeepSuperAdmin(ctx context.Context, userID uuid.UUID) error {
+	if err := pr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return userRepo.KeepSuperAdmin(tx, userID, uuid.Nil)
+	}); err != nil {
+		return errors.Wrap(err, "[PrivacyRepository-KeepSuperAdmin] error while counting super admins")
+	}
+
+	return nil
+}
+
+// Anonymise replaces the personal data of a user in place, rejected with ErrLastSuperAdmin for the last active super admin.
+// Rows referencing the user are kept so audit tables stay consistent, only their personal data is replaced.
+func (pr *PrivacyRepository) Anonymise(ctx context.Context, user *entity.User, anonymised *entity.User) error {
+	now := time.Now()
+
+	if err := pr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the user is deactivated and loses its role
+		if err := userRepo.KeepSuperAdmin(tx, user.ID, uuid.Nil); err != nil {
+			return errors.Wrap(err, "[PrivacyRepository-Anonymise] error while counting super admins")
+		}
+
+		if err := tx.
+			Model(&entity.User{}).
+			Where("id = ?", user.ID).
+			Updates(map[string]interface{}{
+				"name":                  anonymised.Name,
+				"email":                 anonymised.Email,
+				"password":              anonymised.Password,
+				"phone_number":          "",
+				"phone_verified_at":     nil,
+				"otp_channel":           entity.OTPChannelEmail,
+				"photo":                 "",
+				"photo_variants":        "",
+				"dob":                   nil,
+				"otp":                   nil,
+				"forgot_password_token": nil,
+				"status":                entity.UserStatusDeactivated,
+				"version":               gorm.Expr("version + 1"),
+				"updated_by":            anonymised.UpdatedBy,
+				"updated_at":            now,
+			}).Error; err != nil {
+			return errors.Wrap(err, "[PrivacyRepository-Anonymise] error while anonymising user")
+		}
+
+		if err := tx.
+			Model(&entity.Notification{}).
+			Where("user_id = ?", user.ID.String()).
+			Updates(map[string]interface{}{
+				"deleted_by": anonymised.UpdatedBy,
+				"updated_at": now,
+				"deleted_at": now,
+			}).Error; err != nil {
+			return errors.Wrap(err, "[PrivacyRepository-Anonymise] error while deleting notifications")
+		}
+
+		if err := tx.
+			Unscoped().
+			Model(&entity.EmailSent{}).
+			Where(`LOWER("to") = LOWER(?)`, user.Email).
+			Updates(map[string]interface{}{
+				"to":         anonymised.Email,
+				"content":    "",
+				"updated_at": now,
+			}).Error; err != nil {
+			return errors.Wrap(err, "[PrivacyRepository-Anonymise] error while anonymising sent emails")
+		}
+
+		if err := tx.
+			Model(&entity.UserImportRow{}).
+			Where("user_id = ?", user.ID).
+			Updates(map[string]interface{}{
+				"name":         anonymised.Name,
+				"email":        anonymised.Email,
+				"phone_number": "",
+				"dob":          "",
+				"updated_at":   now,
+			}).Error; err != nil {
+			return errors.Wrap(err, "[PrivacyRepository-Anonymise] error while anonymising import rows")
+		}
+
+		if err := tx.
+			Unscoped().
+			Where("user_id = ?", user.ID).
+			Delete(&entity.EmailChange{}).Error; err != nil {
+			return errors.Wrap(err, "[PrivacyRepository-Anonymise] error while deleting email changes")
+		}
+
+		if err := tx.
+			Unscoped().
+			Where("user_id = ?", user.ID).
+			Delete(&entity.PhoneVerification{}).Error; err != nil {
+			return errors.Wrap(err, "[PrivacyRepository-Anonymise] error while deleting phone verifications")
+		}
+
+		if err := tx.
+			Unscoped().
+			Where("user_id = ?", user.ID).
+			Delete(&entity.UserPreference{}).Error; err != nil {
+			return errors.Wrap(err, "[PrivacyRepository-Anonymise] error while deleting preferences")
+		}
+
+		if err := tx.
+			Unscoped().
+			Where("user_id = ?", user.ID).
+			Delete(&entity.UserDevice{}).Error; err != nil {
+			return errors.Wrap(err, "[PrivacyRepository-Anonymise] error while deleting devices")
+		}
+
+		if err := tx.
+			Model(&entity.UserRole{}).
+			Where("user_id = ?", user.ID).
+			Updates(map[string]interface{}{
+				"deleted_by": anonymised.UpdatedBy,
+				"updated_at": now,
+				"deleted_at": now,
+			}).Error; err != nil {
+			return errors.Wrap(err, "[PrivacyRepository-Anonymise] error while revoking user role")
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := pr.cache.Remove(fmt.Sprintf(commonCache.UserPreferencesByUserID, user.ID)); err != nil {
+		return err
+	}
+
+	return pr.cache.Remove(fmt.Sprintf(commonCache.UserRoleByUserID, user.ID.String()))
+}
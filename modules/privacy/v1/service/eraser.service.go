@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	goErrors "errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"gin-starter/common/constant"
+	"gin-starter/common/errors"
+	"gin-starter/common/interfaces"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/privacy/v1/repository"
+	"gin-starter/utils"
+)
+
+const (
+	dataErasureTemplate = "./template/email/data_erasure.html"
+	// ErasedUserName is the name given to an erased user
+	ErasedUserName = "Deleted User"
+)
+
+// DataEraser is a service for personal data erasures
+type DataEraser struct {
+	cfg          config.Config
+	privacyRepo  repository.PrivacyRepositoryUseCase
+	cloudStorage interfaces.CloudStorageUseCase
+}
+
+// DataEraserUseCase is a use case for personal data erasures
+type DataEraserUseCase interface {
+	// RequestErasure schedules the erasure of the personal data of a user after the cooling-off period
+	RequestErasure(ctx context.Context, userID uuid.UUID) (*entity.DataRequest, error)
+	// GetErasure gets the scheduled erasure of a user
+	GetErasure(ctx context.Context, userID uuid.UUID) (*entity.DataRequest, error)
+	// CancelErasure cancels the scheduled erasure of a user
+	CancelErasure(ctx context.Context, userID uuid.UUID) error
+	// Erase anonymises the personal data of the user of a claimed erasure request
+	Erase(ctx context.Context, request *entity.DataRequest) error
+}
+
+// NewDataEraser creates a new DataEraser
+func NewDataEraser(
+	cfg config.Config,
+	privacyRepo repository.PrivacyRepositoryUseCase,
+	cloudStorage interfaces.CloudStorageUseCase,
+) *DataEraser {
+	return &DataEraser{
+		cfg:          cfg,
+		privacyRepo:  privacyRepo,
+		cloudStorage: cloudStorage,
+	}
+}
+
+// RequestErasure schedules the erasure of the personal data of a user after the cooling-off period.
+// An erasure already scheduled is returned instead of creating a new one, the last active super admin cannot be erased.
+func (de *DataEraser) RequestErasure(ctx context.Context, userID uuid.UUID) (*entity.DataRequest, error) {
+	open, err := de.privacyRepo.FindOpenRequest(ctx, userID, entity.DataRequestTypeErasure)
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if open != nil {
+		return open, nil
+	}
+
+	user, err := de.privacyRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if user == nil {
+		return nil, errors.ErrRecordNotFound.Error()
+	}
+
+	if err := de.privacyRepo.KeepSuperAdmin(ctx, userID); err != nil {
+		if goErrors.Is(err, repository.ErrLastSuperAdmin) {
+			return nil, errors.ErrLastSuperAdmin.Error()
+		}
+
+		log.Println("[DataEraser-RequestErasure]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	scheduledAt := time.Now().AddDate(0, 0, de.cfg.Privacy.ErasureCoolingOffDays)
+	request := entity.NewDataRequest(uuid.New(), userID, entity.DataRequestTypeErasure, scheduledAt, userID.String())
+
+	if err := de.privacyRepo.CreateRequest(ctx, request); err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	payload, err := utils.ConstructEmailPayload(dataErasureTemplate, user.Email, "Account Erasure Scheduled", "data-erasure", map[string]interface{}{
+		"Name":        user.Name,
+		"URL":         de.cfg.URL.DataErasureURL,
+		"ScheduledAt": scheduledAt.Format(constant.DefaultTimeFormat),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := utils.SendTopic(ctx, de.cfg, constant.SendEmailTopic, payload); err != nil {
+		log.Println("[DataEraser-RequestErasure]", err)
+	}
+
+	return request, nil
+}
+
+// GetErasure gets the scheduled erasure of a user
+func (de *DataEraser) GetErasure(ctx context.Context, userID uuid.UUID) (*entity.DataRequest, error) {
+	request, err := de.privacyRepo.FindOpenRequest(ctx, userID, entity.DataRequestTypeErasure)
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if request == nil {
+		return nil, errors.ErrRecordNotFound.Error()
+	}
+
+	return request, nil
+}
+
+// CancelErasure cancels the scheduled erasure of a user.
+// An erasure the worker has already started cannot be cancelled.
+func (de *DataEraser) CancelErasure(ctx context.Context, userID uuid.UUID) error {
+	request, err := de.GetErasure(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if request.Status == entity.DataRequestStatusProcessing {
+		return errors.ErrErasureInProgress.Error()
+	}
+
+	cancelled, err := de.privacyRepo.CancelRequest(ctx, request.ID, userID.String())
+	if err != nil {
+		log.Println("[DataEraser-CancelErasure]", err)
+		return errors.ErrInternalServerError.Error()
+	}
+
+	// the worker claimed the request since it was read
+	if !cancelled {
+		return errors.ErrErasureInProgress.Error()
+	}
+
+	request.Status = entity.DataRequestStatusCancelled
+	return nil
+}
+
+// Erase anonymises the personal data of the user of a claimed erasure request.
+// Stored files are removed, while rows referencing the user are kept with their personal data replaced.
+func (de *DataEraser) Erase(ctx context.Context, request *entity.DataRequest) error {
+	user, err := de.privacyRepo.GetUserByID(ctx, request.UserID)
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		request.Status = entity.DataRequestStatusFailed
+		if err := de.privacyRepo.UpdateRequest(ctx, request); err != nil {
+			return err
+		}
+		return fmt.Errorf("user %s not found", request.UserID)
+	}
+
+	// the user may have become the last active super admin since the erasure was requested
+	if err := de.privacyRepo.KeepSuperAdmin(ctx, user.ID); err != nil {
+		return de.failErasure(ctx, request, err)
+	}
+
+	if err := de.removeFiles(ctx, user); err != nil {
+		return err
+	}
+
+	password, err := bcrypt.GenerateFromPassword([]byte(utils.RandStringBytes(constant.ThirtyTwo)), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	anonymised := &entity.User{
+		ID:       user.ID,
+		Name:     ErasedUserName,
+		Email:    fmt.Sprintf("erased-%s@erased.invalid", user.ID.String()),
+		Password: string(password),
+		Auditable: entity.Auditable{
+			UpdatedBy: utils.StringToNullString(user.ID.String()),
+		},
+	}
+
+	if err := de.privacyRepo.Anonymise(ctx, user, anonymised); err != nil {
+		return de.failErasure(ctx, request, err)
+	}
+
+	now := time.Now()
+	request.Status = entity.DataRequestStatusCompleted
+	request.CompletedAt = &now
+
+	return de.privacyRepo.UpdateRequest(ctx, request)
+}
+
+// failErasure fails the request when the user is the last active super admin, whose data is kept.
+// Other errors leave the request to be retried.
+func (de *DataEraser) failErasure(ctx context.Context, request *entity.DataRequest, err error) error {
+	if !goErrors.Is(err, repository.ErrLastSuperAdmin) {
+		return err
+	}
+
+	request.Status = entity.DataRequestStatusFailed
+	if err := de.privacyRepo.UpdateRequest(ctx, request); err != nil {
+		return err
+	}
+
+	return err
+}
+
+// removeFiles deletes the photo and the export archives of a user and cancels the pending exports
+func (de *DataEraser) removeFiles(ctx context.Context, user *entity.User) error {
+	// identical uploads share their variants, those of another user are kept
+	shared := false
+	if user.Photo != "" {
+		var err error
+		if shared, err = de.privacyRepo.IsPhotoShared(ctx, user.Photo, user.ID); err != nil {
+			return err
+		}
+	}
+
+	if !shared {
+		for _, file := range user.PhotoFiles() {
+			if err := de.cloudStorage.Delete(file); err != nil {
+				log.Println("[DataEraser-removeFiles]", err)
+			}
+		}
+	}
+
+	exports, err := de.privacyRepo.FindExportsWithFile(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		if err := de.cloudStorage.Delete(export.FilePath); err != nil {
+			log.Println("[DataEraser-removeFiles]", err)
+		}
+
+		export.Status = entity.DataRequestStatusExpired
+		export.FilePath = ""
+
+		if err := de.privacyRepo.UpdateRequest(ctx, export); err != nil {
+			return err
+		}
+	}
+
+	open, err := de.privacyRepo.FindOpenRequest(ctx, user.ID, entity.DataRequestTypeExport)
+	if err != nil {
+		return err
+	}
+
+	if open != nil {
+		open.Status = entity.DataRequestStatusCancelled
+		return de.privacyRepo.UpdateRequest(ctx, open)
+	}
+
+	return nil
+}
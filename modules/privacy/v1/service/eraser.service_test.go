@@ -0,0 +1,161 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/privacy/v1/repository"
+	"gin-starter/modules/privacy/v1/service"
+	mockInterfaces "gin-starter/test/mock/common/interfaces"
+	mockRepo "gin-starter/test/mock/modules/privacy/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+type DataEraserTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	privacyRepository *mockRepo.MockPrivacyRepositoryUseCase
+	cloudStorage      *mockInterfaces.MockCloudStorageUseCase
+	dataEraser        *service.DataEraser
+	dataExporter      *service.DataExporter
+}
+
+func TestDataEraserTestSuite(t *testing.T) {
+	suite.Run(t, new(DataEraserTestSuite))
+}
+
+func (suite *DataEraserTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.privacyRepository = mockRepo.NewMockPrivacyRepositoryUseCase(suite.mockCtrl)
+	suite.cloudStorage = mockInterfaces.NewMockCloudStorageUseCase(suite.mockCtrl)
+
+	cfg := config.Config{}
+	suite.dataEraser = service.NewDataEraser(cfg, suite.privacyRepository, suite.cloudStorage)
+	suite.dataExporter = service.NewDataExporter(cfg, suite.privacyRepository, suite.cloudStorage)
+}
+
+func (suite *DataEraserTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+func (suite *DataEraserTestSuite) TestDataEraser_CancelErasure() {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	suite.Run("successfully cancel a pending erasure", func() {
+		request := entity.NewDataRequest(uuid.New(), userID, entity.DataRequestTypeErasure, time.Now().AddDate(0, 0, 14), userID.String())
+		suite.privacyRepository.EXPECT().FindOpenRequest(ctx, userID, entity.DataRequestTypeErasure).Return(request, nil)
+		suite.privacyRepository.EXPECT().CancelRequest(ctx, request.ID, userID.String()).Return(true, nil)
+
+		suite.Nil(suite.dataEraser.CancelErasure(ctx, userID))
+		suite.Equal(entity.DataRequestStatusCancelled, request.Status)
+	})
+
+	suite.Run("fail to cancel an erasure the worker claimed after it was read", func() {
+		request := entity.NewDataRequest(uuid.New(), userID, entity.DataRequestTypeErasure, time.Now(), userID.String())
+		suite.privacyRepository.EXPECT().FindOpenRequest(ctx, userID, entity.DataRequestTypeErasure).Return(request, nil)
+		suite.privacyRepository.EXPECT().CancelRequest(ctx, request.ID, userID.String()).Return(false, nil)
+
+		suite.Equal(errors.ErrErasureInProgress.Error(), suite.dataEraser.CancelErasure(ctx, userID))
+		suite.Equal(entity.DataRequestStatusPending, request.Status)
+	})
+
+	suite.Run("fail to cancel an erasure in progress", func() {
+		request := entity.NewDataRequest(uuid.New(), userID, entity.DataRequestTypeErasure, time.Now(), userID.String())
+		request.Status = entity.DataRequestStatusProcessing
+		suite.privacyRepository.EXPECT().FindOpenRequest(ctx, userID, entity.DataRequestTypeErasure).Return(request, nil)
+
+		suite.Equal(errors.ErrErasureInProgress.Error(), suite.dataEraser.CancelErasure(ctx, userID))
+	})
+
+	suite.Run("fail to cancel when no erasure is scheduled", func() {
+		suite.privacyRepository.EXPECT().FindOpenRequest(ctx, userID, entity.DataRequestTypeErasure).Return(nil, nil)
+
+		suite.Equal(errors.ErrRecordNotFound.Error(), suite.dataEraser.CancelErasure(ctx, userID))
+	})
+}
+
+func (suite *DataEraserTestSuite) TestDataEraser_RequestErasure() {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	suite.Run("fail to schedule the erasure of the last active super admin", func() {
+		suite.privacyRepository.EXPECT().FindOpenRequest(ctx, userID, entity.DataRequestTypeErasure).Return(nil, nil)
+		suite.privacyRepository.EXPECT().GetUserByID(ctx, userID).Return(&entity.User{ID: userID, Status: entity.UserStatusActivated}, nil)
+		suite.privacyRepository.EXPECT().KeepSuperAdmin(ctx, userID).Return(pkgErrors.Wrap(repository.ErrLastSuperAdmin, "keep"))
+
+		_, err := suite.dataEraser.RequestErasure(ctx, userID)
+		suite.Equal(errors.ErrLastSuperAdmin.Error(), err)
+	})
+}
+
+func (suite *DataEraserTestSuite) TestDataEraser_Erase() {
+	ctx := context.Background()
+	userID := uuid.New()
+	user := &entity.User{ID: userID, Status: entity.UserStatusActivated}
+
+	suite.Run("fail the erasure of a user who became the last active super admin", func() {
+		request := entity.NewDataRequest(uuid.New(), userID, entity.DataRequestTypeErasure, time.Now(), userID.String())
+		suite.privacyRepository.EXPECT().GetUserByID(ctx, userID).Return(user, nil)
+		suite.privacyRepository.EXPECT().KeepSuperAdmin(ctx, userID).Return(pkgErrors.Wrap(repository.ErrLastSuperAdmin, "keep"))
+		suite.privacyRepository.EXPECT().UpdateRequest(ctx, request).Return(nil)
+
+		suite.NotNil(suite.dataEraser.Erase(ctx, request))
+		suite.Equal(entity.DataRequestStatusFailed, request.Status)
+	})
+
+	suite.Run("fail the erasure when another super admin is deactivated while anonymising", func() {
+		request := entity.NewDataRequest(uuid.New(), userID, entity.DataRequestTypeErasure, time.Now(), userID.String())
+		suite.privacyRepository.EXPECT().GetUserByID(ctx, userID).Return(user, nil)
+		suite.privacyRepository.EXPECT().KeepSuperAdmin(ctx, userID).Return(nil)
+		suite.privacyRepository.EXPECT().FindExportsWithFile(ctx, userID).Return(nil, nil)
+		suite.privacyRepository.EXPECT().FindOpenRequest(ctx, userID, entity.DataRequestTypeExport).Return(nil, nil)
+		suite.privacyRepository.EXPECT().Anonymise(ctx, user, gomock.Any()).Return(pkgErrors.Wrap(repository.ErrLastSuperAdmin, "anonymise"))
+		suite.privacyRepository.EXPECT().UpdateRequest(ctx, request).Return(nil)
+
+		suite.NotNil(suite.dataEraser.Erase(ctx, request))
+		suite.Equal(entity.DataRequestStatusFailed, request.Status)
+	})
+}
+
+func (suite *DataEraserTestSuite) TestDataExporter_DownloadExport() {
+	ctx := context.Background()
+	userID := uuid.New()
+	requestID := uuid.New()
+
+	suite.Run("fail to download an export that is not ready", func() {
+		request := entity.NewDataRequest(requestID, userID, entity.DataRequestTypeExport, time.Now(), userID.String())
+		suite.privacyRepository.EXPECT().FindRequestByID(ctx, requestID).Return(request, nil)
+
+		_, err := suite.dataExporter.DownloadExport(ctx, userID, requestID)
+		suite.Equal(errors.ErrDataExportNotReady.Error(), err)
+	})
+
+	suite.Run("fail to download an expired export", func() {
+		expiresAt := time.Now().Add(-time.Hour)
+		request := entity.NewDataRequest(requestID, userID, entity.DataRequestTypeExport, time.Now(), userID.String())
+		request.Status = entity.DataRequestStatusCompleted
+		request.ExpiresAt = &expiresAt
+		suite.privacyRepository.EXPECT().FindRequestByID(ctx, requestID).Return(request, nil)
+
+		_, err := suite.dataExporter.DownloadExport(ctx, userID, requestID)
+		suite.Equal(errors.ErrDataExportExpired.Error(), err)
+	})
+
+	suite.Run("fail to download the export of another user", func() {
+		request := entity.NewDataRequest(requestID, uuid.New(), entity.DataRequestTypeExport, time.Now(), userID.String())
+		suite.privacyRepository.EXPECT().FindRequestByID(ctx, requestID).Return(request, nil)
+
+		_, err := suite.dataExporter.DownloadExport(ctx, userID, requestID)
+		suite.Equal(errors.ErrRecordNotFound.Error(), err)
+	})
+}
@@ -0,0 +1,481 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gin-starter/common/constant"
+	"gin-starter/common/errors"
+	"gin-starter/common/interfaces"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/privacy/v1/repository"
+	"gin-starter/utils"
+)
+
+const (
+	dataExportTemplate = "./template/email/data_export.html"
+	dataExportFolder   = "privacy/exports"
+	dataExportReadme   = `This archive contains the personal data we hold about you.
+
+profile.json        your account profile
+notifications.json  the notifications addressed to you
+activities.json     the activities recorded on your account
+emails.json         the emails we sent to you
+preferences.json    your language, timezone, notification and consent settings
+devices.json        the devices push notifications are delivered to
+photos/             the photos you uploaded
+`
+)
+
+// DataExporter is a service for personal data exports
+type DataExporter struct {
+	cfg          config.Config
+	privacyRepo  repository.PrivacyRepositoryUseCase
+	cloudStorage interfaces.CloudStorageUseCase
+}
+
+// DataExporterUseCase is a use case for personal data exports
+type DataExporterUseCase interface {
+	// RequestExport requests an export of the personal data of a user
+	RequestExport(ctx context.Context, userID uuid.UUID) (*entity.DataRequest, error)
+	// GetExport gets an export request of a user
+	GetExport(ctx context.Context, userID, id uuid.UUID) (*entity.DataRequest, error)
+	// DownloadExport opens the archive of a completed export of a user
+	DownloadExport(ctx context.Context, userID, id uuid.UUID) (io.ReadCloser, error)
+	// ProcessExport assembles and uploads the archive of a claimed export request
+	ProcessExport(ctx context.Context, request *entity.DataRequest) error
+	// ExpireExports removes the archives whose download link has expired
+	ExpireExports(ctx context.Context) error
+}
+
+// NewDataExporter creates a new DataExporter
+func NewDataExporter(
+	cfg config.Config,
+	privacyRepo repository.PrivacyRepositoryUseCase,
+	cloudStorage interfaces.CloudStorageUseCase,
+) *DataExporter {
+	return &DataExporter{
+		cfg:          cfg,
+		privacyRepo:  privacyRepo,
+		cloudStorage: cloudStorage,
+	}
+}
+
+// RequestExport requests an export of the personal data of a user.
+// An export still waiting or being processed is returned instead of creating a new one.
+func (de *DataExporter) RequestExport(ctx context.Context, userID uuid.UUID) (*entity.DataRequest, error) {
+	open, err := de.privacyRepo.FindOpenRequest(ctx, userID, entity.DataRequestTypeExport)
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if open != nil {
+		return open, nil
+	}
+
+	request := entity.NewDataRequest(uuid.New(), userID, entity.DataRequestTypeExport, time.Now(), userID.String())
+
+	if err := de.privacyRepo.CreateRequest(ctx, request); err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	return request, nil
+}
+
+// GetExport gets an export request of a user
+func (de *DataExporter) GetExport(ctx context.Context, userID, id uuid.UUID) (*entity.DataRequest, error) {
+	request, err := de.privacyRepo.FindRequestByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if request == nil || request.UserID != userID || request.Type != entity.DataRequestTypeExport {
+		return nil, errors.ErrRecordNotFound.Error()
+	}
+
+	return request, nil
+}
+
+// DownloadExport opens the archive of a completed export of a user
+func (de *DataExporter) DownloadExport(ctx context.Context, userID, id uuid.UUID) (io.ReadCloser, error) {
+	request, err := de.GetExport(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Status == entity.DataRequestStatusExpired ||
+		(request.ExpiresAt != nil && request.ExpiresAt.Before(time.Now())) {
+		return nil, errors.ErrDataExportExpired.Error()
+	}
+
+	if request.Status != entity.DataRequestStatusCompleted {
+		return nil, errors.ErrDataExportNotReady.Error()
+	}
+
+	file, err := de.cloudStorage.Download(request.FilePath)
+	if err != nil {
+		log.Println("[DataExporter-DownloadExport]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	return file, nil
+}
+
+// ProcessExport assembles and uploads the archive of a claimed export request, then emails the download link
+func (de *DataExporter) ProcessExport(ctx context.Context, request *entity.DataRequest) error {
+	user, err := de.privacyRepo.GetUserByID(ctx, request.UserID)
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return de.fail(ctx, request, fmt.Errorf("user %s not found", request.UserID))
+	}
+
+	filePath, err := de.buildArchive(ctx, user)
+	if err != nil {
+		return de.fail(ctx, request, err)
+	}
+
+	defer func() {
+		if err := os.Remove(filePath); err != nil {
+			log.Println("[DataExporter-ProcessExport] error while removing archive:", err)
+		}
+	}()
+
+	stored, err := de.cloudStorage.UploadSavedFile(filePath, dataExportFolder)
+	if err != nil {
+		return de.fail(ctx, request, err)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(de.cfg.Privacy.ExportExpiryHours) * time.Hour)
+
+	request.Status = entity.DataRequestStatusCompleted
+	request.FilePath = stored
+	request.ExpiresAt = &expiresAt
+	request.CompletedAt = &now
+
+	if err := de.privacyRepo.UpdateRequest(ctx, request); err != nil {
+		return err
+	}
+
+	payload, err := utils.ConstructEmailPayload(dataExportTemplate, user.Email, "Your Data Export", "data-export", map[string]interface{}{
+		"Name":      user.Name,
+		"URL":       fmt.Sprintf("%s/%s", de.cfg.URL.DataExportURL, request.ID.String()),
+		"ExpiresAt": expiresAt.Format(constant.DefaultTimeFormat),
+	})
+	if err != nil {
+		return err
+	}
+
+	return utils.SendTopic(ctx, de.cfg, constant.SendEmailTopic, payload)
+}
+
+// ExpireExports removes the archives whose download link has expired
+func (de *DataExporter) ExpireExports(ctx context.Context) error {
+	requests, err := de.privacyRepo.FindExpiredExports(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, request := range requests {
+		if request.FilePath != "" {
+			if err := de.cloudStorage.Delete(request.FilePath); err != nil {
+				log.Println("[DataExporter-ExpireExports]", err)
+				continue
+			}
+		}
+
+		request.Status = entity.DataRequestStatusExpired
+		request.FilePath = ""
+
+		if err := de.privacyRepo.UpdateRequest(ctx, request); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fail marks a request as failed and returns the error that aborted it
+func (de *DataExporter) fail(ctx context.Context, request *entity.DataRequest, cause error) error {
+	request.Status = entity.DataRequestStatusFailed
+
+	if err := de.privacyRepo.UpdateRequest(ctx, request); err != nil {
+		return err
+	}
+
+	return cause
+}
+
+// buildArchive writes the personal data of a user into a temporary zip file and returns its path
+func (de *DataExporter) buildArchive(ctx context.Context, user *entity.User) (string, error) {
+	file, err := os.CreateTemp("", "data-export-*.zip")
+	if err != nil {
+		return "", err
+	}
+
+	if err := de.writeArchive(ctx, file, user); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+func (de *DataExporter) writeArchive(ctx context.Context, w io.Writer, user *entity.User) error {
+	notifications, err := de.privacyRepo.GetNotificationsByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	activities, err := de.privacyRepo.GetActivitiesByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	emails, err := de.privacyRepo.GetEmailsSentTo(ctx, user.Email)
+	if err != nil {
+		return err
+	}
+
+	preferences, err := de.privacyRepo.GetPreferencesByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	devices, err := de.privacyRepo.GetDevicesByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(w)
+
+	readme, err := archive.Create("README.txt")
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(readme, dataExportReadme); err != nil {
+		return err
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", newExportProfile(user)},
+		{"notifications.json", newExportNotifications(notifications)},
+		{"activities.json", newExportActivities(activities)},
+		{"emails.json", newExportEmails(emails)},
+		{"preferences.json", entity.NewPreferences(preferences)},
+		{"devices.json", newExportDevices(devices)},
+	}
+
+	for _, f := range files {
+		if err := writeJSON(archive, f.name, f.data); err != nil {
+			return err
+		}
+	}
+
+	photos := user.PhotoFiles()
+
+	variants := make([]string, 0, len(photos))
+	for variant := range photos {
+		variants = append(variants, variant)
+	}
+
+	sort.Strings(variants)
+
+	for _, variant := range variants {
+		if err := de.writePhoto(archive, photos[variant]); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+func (de *DataExporter) writePhoto(archive *zip.Writer, photo string) error {
+	src, err := de.cloudStorage.Download(photo)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := archive.Create(path.Join("photos", path.Base(photo)))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+func writeJSON(archive *zip.Writer, name string, data interface{}) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(data)
+}
+
+// exportProfile is the exported profile, leaving out credentials and tokens
+type exportProfile struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	PhoneNumber string `json:"phone_number"`
+	// PhoneVerifiedAt is nil while the phone number is not verified
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at"`
+	Photo           string     `json:"photo"`
+	DOB             *time.Time `json:"dob"`
+	Status          string     `json:"status"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+func newExportProfile(user *entity.User) *exportProfile {
+	profile := &exportProfile{
+		ID:          user.ID.String(),
+		Name:        user.Name,
+		Email:       user.Email,
+		PhoneNumber: user.PhoneNumber,
+		Photo:       user.Photo,
+		Status:      user.Status,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+	}
+
+	if user.DOB.Valid {
+		profile.DOB = &user.DOB.Time
+	}
+
+	if user.PhoneVerifiedAt.Valid {
+		profile.PhoneVerifiedAt = &user.PhoneVerifiedAt.Time
+	}
+
+	return profile
+}
+
+type exportNotification struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Type        string    `json:"type"`
+	IsRead      bool      `json:"is_read"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func newExportNotifications(notifications []*entity.Notification) []*exportNotification {
+	res := make([]*exportNotification, 0, len(notifications))
+
+	for _, n := range notifications {
+		res = append(res, &exportNotification{
+			ID:          n.ID.String(),
+			Title:       n.Title,
+			Description: n.Description,
+			Type:        n.Type,
+			IsRead:      n.IsRead,
+			CreatedAt:   n.CreatedAt,
+		})
+	}
+
+	return res
+}
+
+type exportActivity struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Type        string    `json:"type"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func newExportActivities(activities []*entity.Activities) []*exportActivity {
+	res := make([]*exportActivity, 0, len(activities))
+
+	for _, a := range activities {
+		res = append(res, &exportActivity{
+			ID:          a.ID.String(),
+			Title:       a.Title,
+			Description: a.Description,
+			Type:        a.ActivitiesType,
+			CreatedAt:   a.CreatedAt,
+		})
+	}
+
+	return res
+}
+
+type exportEmail struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Subject   string    `json:"subject"`
+	Content   string    `json:"content"`
+	Status    string    `json:"status"`
+	Category  string    `json:"category"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type exportDevice struct {
+	Platform   string    `json:"platform"`
+	AppVersion string    `json:"app_version"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func newExportDevices(devices []*entity.UserDevice) []*exportDevice {
+	res := make([]*exportDevice, 0, len(devices))
+
+	for _, d := range devices {
+		res = append(res, &exportDevice{
+			Platform:   d.Platform,
+			AppVersion: d.AppVersion.String,
+			LastSeenAt: d.LastSeenAt,
+			CreatedAt:  d.CreatedAt,
+		})
+	}
+
+	return res
+}
+
+func newExportEmails(emails []*entity.EmailSent) []*exportEmail {
+	res := make([]*exportEmail, 0, len(emails))
+
+	for _, e := range emails {
+		res = append(res, &exportEmail{
+			From:      e.From,
+			To:        e.To,
+			Subject:   e.Subject,
+			Content:   e.Content,
+			Status:    e.Status,
+			Category:  e.Category,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gin-starter/common/constant"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/privacy/v1/repository"
+)
+
+// staleAfter is how long a request may stay processing before another worker picks it up again
+const staleAfter = 30 * time.Minute
+
+// DataRequestWorker processes the due export and erasure requests
+type DataRequestWorker struct {
+	cfg          config.Config
+	privacyRepo  repository.PrivacyRepositoryUseCase
+	dataExporter DataExporterUseCase
+	dataEraser   DataEraserUseCase
+}
+
+// NewDataRequestWorker creates a new DataRequestWorker
+func NewDataRequestWorker(
+	cfg config.Config,
+	privacyRepo repository.PrivacyRepositoryUseCase,
+	dataExporter DataExporterUseCase,
+	dataEraser DataEraserUseCase,
+) *DataRequestWorker {
+	return &DataRequestWorker{
+		cfg:          cfg,
+		privacyRepo:  privacyRepo,
+		dataExporter: dataExporter,
+		dataEraser:   dataEraser,
+	}
+}
+
+// ProcessDue processes the due requests and expires the old export archives
+func (dw *DataRequestWorker) ProcessDue(ctx context.Context) error {
+	now := time.Now()
+	staleBefore := now.Add(-staleAfter)
+
+	requests, err := dw.privacyRepo.FindDueRequests(ctx, now, staleBefore, constant.Hundred)
+	if err != nil {
+		return err
+	}
+
+	for _, request := range requests {
+		claimed, err := dw.privacyRepo.ClaimRequest(ctx, request.ID, staleBefore)
+		if err != nil {
+			return err
+		}
+
+		if !claimed {
+			continue
+		}
+
+		request.Status = entity.DataRequestStatusProcessing
+
+		switch request.Type {
+		case entity.DataRequestTypeExport:
+			err = dw.dataExporter.ProcessExport(ctx, request)
+		case entity.DataRequestTypeErasure:
+			err = dw.dataEraser.Erase(ctx, request)
+		}
+
+		if err != nil {
+			log.Printf("[DataRequestWorker-ProcessDue] %s request %s: %v", request.Type, request.ID, err)
+		}
+	}
+
+	return dw.dataExporter.ExpireExports(ctx)
+}
+
+// Run processes the due requests periodically until the context is done
+func (dw *DataRequestWorker) Run(ctx context.Context) {
+	interval, err := time.ParseDuration(dw.cfg.Privacy.WorkerInterval)
+	if err != nil || interval <= 0 {
+		log.Println("[DataRequestWorker-Run] invalid worker interval, data requests are never processed:", dw.cfg.Privacy.WorkerInterval)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := dw.ProcessDue(ctx); err != nil {
+			log.Println("[DataRequestWorker-Run]", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package builder
+
+import (
+	"gin-starter/app"
+	"gin-starter/config"
+	"gin-starter/modules/scim/v1/service"
+	userRepo "gin-starter/modules/user/v1/repository"
+	"gin-starter/utils"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/gin-gonic/gin"
+	"github.com/gomodule/redigo/redis"
+	"gorm.io/gorm"
+)
+
+// BuildSCIMHandler builds scim handler
+// starting from handler down to repository or tool.
+func BuildSCIMHandler(cfg config.Config, router *gin.Engine, db *gorm.DB, redisPool *redis.Pool, awsSession *session.Session) {
+	// Cache
+	cache := utils.NewClient(redisPool)
+
+	// Repository
+	ur := userRepo.NewUserRepository(db)
+	rr := userRepo.NewRoleRepository(db, cache)
+	urr := userRepo.NewUserRoleRepository(db, cache)
+
+	// Service
+	su := service.NewSCIMUserService(cfg, ur)
+	sg := service.NewSCIMGroupService(cfg, ur, rr, urr)
+
+	// Handler
+	app.SCIMHTTPHandler(cfg, router, su, sg)
+}
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"gin-starter/config"
+	"gin-starter/modules/scim/v1/service"
+	"gin-starter/resource"
+)
+
+// SCIMGroupHandler is a handler for SCIM group provisioning
+type SCIMGroupHandler struct {
+	cfg       config.Config
+	scimGroup service.SCIMGroupUseCase
+}
+
+// NewSCIMGroupHandler is a constructor for SCIMGroupHandler
+func NewSCIMGroupHandler(
+	cfg config.Config,
+	scimGroup service.SCIMGroupUseCase,
+) *SCIMGroupHandler {
+	return &SCIMGroupHandler{
+		cfg:       cfg,
+		scimGroup: scimGroup,
+	}
+}
+
+// GetGroups is a handler for listing groups
+func (sh *SCIMGroupHandler) GetGroups(c *gin.Context) {
+	var request resource.SCIMListRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		abortSCIM(c, service.NewInvalidValueError(err.Error()))
+		return
+	}
+
+	roles, total, err := sh.scimGroup.GetGroups(c, request.Filter, request.StartIndex, request.Count)
+	if err != nil {
+		abortSCIM(c, err)
+		return
+	}
+
+	res := make([]*resource.SCIMGroup, 0, len(roles))
+	for _, role := range roles {
+		members, err := sh.scimGroup.GetGroupMembers(c, role.ID)
+		if err != nil {
+			abortSCIM(c, err)
+			return
+		}
+
+		res = append(res, resource.NewSCIMGroup(role, members, sh.cfg.SCIM.BaseURL))
+	}
+
+	startIndex := request.StartIndex
+	if startIndex < 1 {
+		startIndex = 1
+	}
+
+	writeSCIM(c, http.StatusOK, resource.NewSCIMListResponse(res, total, startIndex, len(res)))
+}
+
+// GetGroup is a handler for getting a group
+func (sh *SCIMGroupHandler) GetGroup(c *gin.Context) {
+	id, err := resourceID(c)
+	if err != nil {
+		abortSCIM(c, err)
+		return
+	}
+
+	role, members, err := sh.scimGroup.GetGroupByID(c, id)
+	if err != nil {
+		abortSCIM(c, err)
+		return
+	}
+
+	version := resource.SCIMVersion(role.Auditable)
+	c.Header("ETag", version)
+
+	if notModified(c, version) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	writeSCIM(c, http.StatusOK, resource.NewSCIMGroup(role, members, sh.cfg.SCIM.BaseURL))
+}
+
+// CreateGroup is a handler for provisioning a group
+func (sh *SCIMGroupHandler) CreateGroup(c *gin.Context) {
+	var request resource.SCIMGroup
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abortSCIM(c, service.NewInvalidValueError(err.Error()))
+		return
+	}
+
+	role, members, err := sh.scimGroup.CreateGroup(c, request.DisplayName, memberIDs(&request))
+	if err != nil {
+		abortSCIM(c, err)
+		return
+	}
+
+	res := resource.NewSCIMGroup(role, members, sh.cfg.SCIM.BaseURL)
+	c.Header("ETag", res.Meta.Version)
+	c.Header("Location", res.Meta.Location)
+	writeSCIM(c, http.StatusCreated, res)
+}
+
+// ReplaceGroup is a handler for replacing a group
+func (sh *SCIMGroupHandler) ReplaceGroup(c *gin.Context) {
+	var request resource.SCIMGroup
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abortSCIM(c, service.NewInvalidValueError(err.Error()))
+		return
+	}
+
+	id, ok := sh.checkVersion(c)
+	if !ok {
+		return
+	}
+
+	role, members, err := sh.scimGroup.ReplaceGroup(c, id, request.DisplayName, memberIDs(&request))
+	if err != nil {
+		abortSCIM(c, err)
+		return
+	}
+
+	res := resource.NewSCIMGroup(role, members, sh.cfg.SCIM.BaseURL)
+	c.Header("ETag", res.Meta.Version)
+	writeSCIM(c, http.StatusOK, res)
+}
+
+// PatchGroup is a handler for patching a group
+func (sh *SCIMGroupHandler) PatchGroup(c *gin.Context) {
+	var request resource.SCIMPatchRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abortSCIM(c, service.NewInvalidValueError(err.Error()))
+		return
+	}
+
+	id, ok := sh.checkVersion(c)
+	if !ok {
+		return
+	}
+
+	role, members, err := sh.scimGroup.PatchGroup(c, id, patchOperations(&request))
+	if err != nil {
+		abortSCIM(c, err)
+		return
+	}
+
+	res := resource.NewSCIMGroup(role, members, sh.cfg.SCIM.BaseURL)
+	c.Header("ETag", res.Meta.Version)
+	writeSCIM(c, http.StatusOK, res)
+}
+
+// DeleteGroup is a handler for deprovisioning a group
+func (sh *SCIMGroupHandler) DeleteGroup(c *gin.Context) {
+	id, ok := sh.checkVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := sh.scimGroup.DeleteGroup(c, id); err != nil {
+		abortSCIM(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// checkVersion loads the group addressed by the request and validates If-Match against it
+func (sh *SCIMGroupHandler) checkVersion(c *gin.Context) (id uuid.UUID, ok bool) {
+	id, err := resourceID(c)
+	if err != nil {
+		abortSCIM(c, err)
+		return id, false
+	}
+
+	role, _, err := sh.scimGroup.GetGroupByID(c, id)
+	if err != nil {
+		abortSCIM(c, err)
+		return id, false
+	}
+
+	if err := checkIfMatch(c, resource.SCIMVersion(role.Auditable)); err != nil {
+		abortSCIM(c, err)
+		return id, false
+	}
+
+	return id, true
+}
+
+// memberIDs returns the user ids referenced by the members of a SCIM group
+func memberIDs(request *resource.SCIMGroup) []string {
+	ids := make([]string, 0, len(request.Members))
+	for _, m := range request.Members {
+		ids = append(ids, m.Value)
+	}
+
+	return ids
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"gin-starter/config"
+	"gin-starter/modules/scim/v1/service"
+	"gin-starter/resource"
+)
+
+// SCIMHandler is a handler for the SCIM discovery endpoints
+type SCIMHandler struct {
+	cfg config.Config
+}
+
+// NewSCIMHandler is a constructor for SCIMHandler
+func NewSCIMHandler(cfg config.Config) *SCIMHandler {
+	return &SCIMHandler{
+		cfg: cfg,
+	}
+}
+
+// GetServiceProviderConfig is a handler for describing the supported SCIM features
+func (sh *SCIMHandler) GetServiceProviderConfig(c *gin.Context) {
+	writeSCIM(c, http.StatusOK, gin.H{
+		"schemas":          []string{resource.SCIMSchemaServiceProviderConfig},
+		"documentationUri": "",
+		"patch":            gin.H{"supported": true},
+		"bulk":             gin.H{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":           gin.H{"supported": true, "maxResults": 200},
+		"changePassword":   gin.H{"supported": true},
+		"sort":             gin.H{"supported": false},
+		"etag":             gin.H{"supported": true},
+		"authenticationSchemes": []gin.H{
+			{
+				"type":        "oauthbearertoken",
+				"name":        "OAuth Bearer Token",
+				"description": "Authentication using the SCIM bearer credential",
+				"primary":     true,
+			},
+		},
+		"meta": gin.H{
+			"resourceType": "ServiceProviderConfig",
+			"location":     sh.cfg.SCIM.BaseURL + "/ServiceProviderConfig",
+		},
+	})
+}
+
+// writeSCIM writes a response with the SCIM media type
+func writeSCIM(c *gin.Context, status int, body interface{}) {
+	c.Header("Content-Type", resource.SCIMContentType)
+	c.JSON(status, body)
+}
+
+// abortSCIM writes a SCIM error response and aborts the request
+func abortSCIM(c *gin.Context, err error) {
+	var scimErr *service.Error
+	if !errors.As(err, &scimErr) {
+		scimErr = &service.Error{Status: http.StatusInternalServerError, Detail: "internal server error"}
+	}
+
+	writeSCIM(c, scimErr.Status, resource.NewSCIMError(scimErr.Status, scimErr.ScimType, scimErr.Detail))
+	c.Abort()
+}
+
+// checkIfMatch returns ErrPreconditionFailed when the If-Match header does not match version
+func checkIfMatch(c *gin.Context, version string) error {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		return nil
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(version, "W/") {
+			return nil
+		}
+	}
+
+	return service.ErrPreconditionFailed
+}
+
+// notModified reports whether the If-None-Match header already matches version
+func notModified(c *gin.Context, version string) bool {
+	for _, tag := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || (tag != "" && strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(version, "W/")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resourceID parses the id uri parameter, an id which is not a uuid cannot exist
+func resourceID(c *gin.Context) (uuid.UUID, error) {
+	var request resource.SCIMResourceIDRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		return uuid.Nil, service.ErrResourceNotFound
+	}
+
+	id, err := uuid.Parse(request.ID)
+	if err != nil {
+		return uuid.Nil, service.ErrResourceNotFound
+	}
+
+	return id, nil
+}
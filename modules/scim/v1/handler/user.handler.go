@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"gin-starter/config"
+	"gin-starter/modules/scim/v1/service"
+	"gin-starter/resource"
+)
+
+// SCIMUserHandler is a handler for SCIM user provisioning
+type SCIMUserHandler struct {
+	cfg      config.Config
+	scimUser service.SCIMUserUseCase
+}
+
+// NewSCIMUserHandler is a constructor for SCIMUserHandler
+func NewSCIMUserHandler(
+	cfg config.Config,
+	scimUser service.SCIMUserUseCase,
+) *SCIMUserHandler {
+	return &SCIMUserHandler{
+		cfg:      cfg,
+		scimUser: scimUser,
+	}
+}
+
+// GetUsers is a handler for listing users
+func (sh *SCIMUserHandler) GetUsers(c *gin.Context) {
+	var request resource.SCIMListRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		abortSCIM(c, service.NewInvalidValueError(err.Error()))
+		return
+	}
+
+	users, total, err := sh.scimUser.GetUsers(c, request.Filter, request.StartIndex, request.Count)
+	if err != nil {
+		abortSCIM(c, err)
+		return
+	}
+
+	res := make([]*resource.SCIMUser, 0, len(users))
+	for _, u := range users {
+		res = append(res, resource.NewSCIMUser(u, sh.cfg.SCIM.BaseURL))
+	}
+
+	startIndex := request.StartIndex
+	if startIndex < 1 {
+		startIndex = 1
+	}
+
+	writeSCIM(c, http.StatusOK, resource.NewSCIMListResponse(res, total, startIndex, len(res)))
+}
+
+// GetUser is a handler for getting a user
+func (sh *SCIMUserHandler) GetUser(c *gin.Context) {
+	id, err := resourceID(c)
+	if err != nil {
+		abortSCIM(c, err)
+		return
+	}
+
+	user, err := sh.scimUser.GetUserByID(c, id)
+	if err != nil {
+		abortSCIM(c, err)
+		return
+	}
+
+	version := resource.SCIMVersion(user.Auditable)
+	c.Header("ETag", version)
+
+	if notModified(c, version) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	writeSCIM(c, http.StatusOK, resource.NewSCIMUser(user, sh.cfg.SCIM.BaseURL))
+}
+
+// CreateUser is a handler for provisioning a user
+func (sh *SCIMUserHandler) CreateUser(c *gin.Context) {
+	var request resource.SCIMUser
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abortSCIM(c, service.NewInvalidValueError(err.Error()))
+		return
+	}
+
+	user, err := sh.scimUser.CreateUser(c, userAttributes(&request))
+	if err != nil {
+		abortSCIM(c, err)
+		return
+	}
+
+	res := resource.NewSCIMUser(user, sh.cfg.SCIM.BaseURL)
+	c.Header("ETag", res.Meta.Version)
+	c.Header("Location", res.Meta.Location)
+	writeSCIM(c, http.StatusCreated, res)
+}
+
+// ReplaceUser is a handler for replacing a user
+func (sh *SCIMUserHandler) ReplaceUser(c *gin.Context) {
+	var request resource.SCIMUser
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abortSCIM(c, service.NewInvalidValueError(err.Error()))
+		return
+	}
+
+	id, ok := sh.checkVersion(c)
+	if !ok {
+		return
+	}
+
+	user, err := sh.scimUser.ReplaceUser(c, id, userAttributes(&request))
+	if err != nil {
+		abortSCIM(c, err)
+		return
+	}
+
+	res := resource.NewSCIMUser(user, sh.cfg.SCIM.BaseURL)
+	c.Header("ETag", res.Meta.Version)
+	writeSCIM(c, http.StatusOK, res)
+}
+
+// PatchUser is a handler for patching a user
+func (sh *SCIMUserHandler) PatchUser(c *gin.Context) {
+	var request resource.SCIMPatchRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abortSCIM(c, service.NewInvalidValueError(err.Error()))
+		return
+	}
+
+	id, ok := sh.checkVersion(c)
+	if !ok {
+		return
+	}
+
+	user, err := sh.scimUser.PatchUser(c, id, patchOperations(&request))
+	if err != nil {
+		abortSCIM(c, err)
+		return
+	}
+
+	res := resource.NewSCIMUser(user, sh.cfg.SCIM.BaseURL)
+	c.Header("ETag", res.Meta.Version)
+	writeSCIM(c, http.StatusOK, res)
+}
+
+// DeleteUser is a handler for deprovisioning a user
+func (sh *SCIMUserHandler) DeleteUser(c *gin.Context) {
+	id, ok := sh.checkVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := sh.scimUser.DeleteUser(c, id); err != nil {
+		abortSCIM(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// checkVersion loads the user addressed by the request and validates If-Match against it
+func (sh *SCIMUserHandler) checkVersion(c *gin.Context) (id uuid.UUID, ok bool) {
+	id, err := resourceID(c)
+	if err != nil {
+		abortSCIM(c, err)
+		return id, false
+	}
+
+	user, err := sh.scimUser.GetUserByID(c, id)
+	if err != nil {
+		abortSCIM(c, err)
+		return id, false
+	}
+
+	if err := checkIfMatch(c, resource.SCIMVersion(user.Auditable)); err != nil {
+		abortSCIM(c, err)
+		return id, false
+	}
+
+	return id, true
+}
+
+// userAttributes converts a SCIM user into the attributes managed by the service
+func userAttributes(request *resource.SCIMUser) *service.UserAttributes {
+	attributes := &service.UserAttributes{
+		UserName:    request.UserName,
+		DisplayName: request.DisplayName,
+		Password:    request.Password,
+		Active:      request.Active == nil || *request.Active,
+	}
+
+	if attributes.DisplayName == "" && request.Name != nil {
+		attributes.DisplayName = request.Name.Formatted
+	}
+
+	for _, p := range request.PhoneNumbers {
+		if attributes.PhoneNumber == "" || p.Primary {
+			attributes.PhoneNumber = p.Value
+		}
+	}
+
+	return attributes
+}
+
+// patchOperations converts the operations of a SCIM patch request
+func patchOperations(request *resource.SCIMPatchRequest) []*service.PatchOperation {
+	operations := make([]*service.PatchOperation, 0, len(request.Operations))
+	for _, op := range request.Operations {
+		operations = append(operations, &service.PatchOperation{
+			Op:    op.Op,
+			Path:  op.Path,
+			Value: op.Value,
+		})
+	}
+
+	return operations
+}
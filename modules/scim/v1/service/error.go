@@ -0,0 +1,85 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const (
+	// ScimTypeInvalidFilter is returned when the filter syntax or attribute is not supported
+	ScimTypeInvalidFilter = "invalidFilter"
+	// ScimTypeUniqueness is returned when a unique attribute is already taken
+	ScimTypeUniqueness = "uniqueness"
+	// ScimTypeInvalidValue is returned when a required value is missing or malformed
+	ScimTypeInvalidValue = "invalidValue"
+	// ScimTypeInvalidPath is returned when a patch path is not supported
+	ScimTypeInvalidPath = "invalidPath"
+	// ScimTypeNoTarget is returned when a patch path matches nothing
+	ScimTypeNoTarget = "noTarget"
+	// ScimTypeMutability is returned when an immutable attribute is modified
+	ScimTypeMutability = "mutability"
+)
+
+// Error is an error carrying the HTTP status and scimType expected by SCIM clients
+type Error struct {
+	Status   int
+	ScimType string
+	Detail   string
+}
+
+// Error returns the error detail
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+var (
+	// ErrResourceNotFound is returned when the requested resource does not exist
+	ErrResourceNotFound = &Error{Status: http.StatusNotFound, Detail: "resource not found"}
+	// ErrUserNameTaken is returned when the userName is already used by another user
+	ErrUserNameTaken = &Error{Status: http.StatusConflict, ScimType: ScimTypeUniqueness, Detail: "userName is already taken"}
+	// ErrDisplayNameTaken is returned when the group displayName is already used by another group
+	ErrDisplayNameTaken = &Error{Status: http.StatusConflict, ScimType: ScimTypeUniqueness, Detail: "displayName is already taken"}
+	// ErrSystemGroupProtected is returned when deleting or renaming the group of a system role
+	ErrSystemGroupProtected = &Error{Status: http.StatusBadRequest, ScimType: ScimTypeMutability, Detail: "system groups cannot be deleted or renamed"}
+	// ErrLastSuperAdmin is returned when deleting, deactivating or moving out of its group the last active super admin
+	ErrLastSuperAdmin = &Error{Status: http.StatusBadRequest, ScimType: ScimTypeMutability, Detail: "the last active super admin cannot be deleted, deactivated or removed from its group"}
+	// ErrPreconditionFailed is returned when If-Match does not match the current version
+	ErrPreconditionFailed = &Error{Status: http.StatusPreconditionFailed, Detail: "resource version mismatch"}
+)
+
+// NewInvalidFilterError creates an invalidFilter error
+func NewInvalidFilterError(detail string) *Error {
+	return &Error{Status: http.StatusBadRequest, ScimType: ScimTypeInvalidFilter, Detail: detail}
+}
+
+// NewInvalidValueError creates an invalidValue error
+func NewInvalidValueError(detail string) *Error {
+	return &Error{Status: http.StatusBadRequest, ScimType: ScimTypeInvalidValue, Detail: detail}
+}
+
+// NewInvalidPathError creates an invalidPath error
+func NewInvalidPathError(detail string) *Error {
+	return &Error{Status: http.StatusBadRequest, ScimType: ScimTypeInvalidPath, Detail: detail}
+}
+
+// PatchOperation is a single operation of a SCIM PATCH request
+type PatchOperation struct {
+	Op    string
+	Path  string
+	Value json.RawMessage
+}
+
+// normalizedPath lower cases a patch path and drops value filters, so
+// `emails[type eq "work"].value` becomes `emails.value`
+func normalizedPath(path string) string {
+	path = strings.ToLower(strings.TrimSpace(path))
+
+	if start := strings.Index(path, "["); start >= 0 {
+		if end := strings.Index(path[start:], "]"); end >= 0 {
+			path = path[:start] + path[start+end+1:]
+		}
+	}
+
+	return path
+}
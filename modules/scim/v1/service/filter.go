@@ -0,0 +1,161 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Filter is a single attribute comparison of a SCIM filter expression
+type Filter struct {
+	// Attribute is the lower cased attribute path, e.g. "username" or "emails.value"
+	Attribute string
+	// Operator is the lower cased comparison operator, e.g. "eq" or "pr"
+	Operator string
+	// Value is the compared value which is a string, bool, float64 or nil
+	Value interface{}
+}
+
+var filterOperators = map[string]bool{
+	"eq": true,
+	"ne": true,
+	"co": true,
+	"sw": true,
+	"ew": true,
+	"gt": true,
+	"ge": true,
+	"lt": true,
+	"le": true,
+	"pr": true,
+}
+
+// ParseFilter parses the subset of RFC 7644 filters made of attribute comparisons joined by "and".
+// An empty filter returns no comparisons.
+func ParseFilter(filter string) ([]*Filter, error) {
+	tokens, err := tokenizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := make([]*Filter, 0)
+
+	for i := 0; i < len(tokens); {
+		if len(filters) > 0 {
+			switch strings.ToLower(tokens[i]) {
+			case "and":
+				i++
+			case "or", "not":
+				return nil, NewInvalidFilterError(fmt.Sprintf("logical operator %q is not supported", tokens[i]))
+			default:
+				return nil, NewInvalidFilterError(fmt.Sprintf("unexpected token %q", tokens[i]))
+			}
+		}
+
+		if i+1 >= len(tokens) {
+			return nil, NewInvalidFilterError("incomplete filter expression")
+		}
+
+		f := &Filter{
+			Attribute: strings.ToLower(tokens[i]),
+			Operator:  strings.ToLower(tokens[i+1]),
+		}
+
+		if !filterOperators[f.Operator] {
+			return nil, NewInvalidFilterError(fmt.Sprintf("operator %q is not supported", tokens[i+1]))
+		}
+
+		i += 2
+
+		if f.Operator != "pr" {
+			if i >= len(tokens) {
+				return nil, NewInvalidFilterError("missing comparison value")
+			}
+
+			value, err := parseFilterValue(tokens[i])
+			if err != nil {
+				return nil, err
+			}
+
+			f.Value = value
+			i++
+		}
+
+		filters = append(filters, f)
+	}
+
+	return filters, nil
+}
+
+// tokenizeFilter splits a filter into attribute paths, operators and values
+func tokenizeFilter(filter string) ([]string, error) {
+	tokens := make([]string, 0)
+	runes := []rune(strings.TrimSpace(filter))
+
+	for i := 0; i < len(runes); {
+		switch {
+		case unicode.IsSpace(runes[i]):
+			i++
+		case runes[i] == '(' || runes[i] == ')' || runes[i] == '[' || runes[i] == ']':
+			return nil, NewInvalidFilterError("grouping and value path filters are not supported")
+		case runes[i] == '"':
+			var sb strings.Builder
+			sb.WriteRune('"')
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					sb.WriteRune(runes[i])
+					sb.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				sb.WriteRune(runes[i])
+				i++
+				if runes[i-1] == '"' {
+					closed = true
+					break
+				}
+			}
+			if !closed {
+				return nil, NewInvalidFilterError("unterminated string value")
+			}
+			tokens = append(tokens, sb.String())
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		}
+	}
+
+	return tokens, nil
+}
+
+// parseFilterValue converts a comparison value token into a go value
+func parseFilterValue(token string) (interface{}, error) {
+	if strings.HasPrefix(token, `"`) {
+		value, err := strconv.Unquote(token)
+		if err != nil {
+			return nil, NewInvalidFilterError(fmt.Sprintf("invalid string value %s", token))
+		}
+		return value, nil
+	}
+
+	switch strings.ToLower(token) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+
+	number, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return nil, NewInvalidFilterError(fmt.Sprintf("invalid comparison value %s", token))
+	}
+
+	return number, nil
+}
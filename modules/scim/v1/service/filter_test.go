@@ -0,0 +1,52 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gin-starter/modules/scim/v1/service"
+)
+
+func TestParseFilter(t *testing.T) {
+	filters, err := service.ParseFilter(`userName eq "John.Doe@example.com" and active eq true and phoneNumbers pr`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*service.Filter{
+		{Attribute: "username", Operator: "eq", Value: "John.Doe@example.com"},
+		{Attribute: "active", Operator: "eq", Value: true},
+		{Attribute: "phonenumbers", Operator: "pr"},
+	}, filters)
+}
+
+func TestParseFilter_EscapedString(t *testing.T) {
+	filters, err := service.ParseFilter(`displayName co "say \"hi\" and"`)
+
+	assert.NoError(t, err)
+	assert.Len(t, filters, 1)
+	assert.Equal(t, `say "hi" and`, filters[0].Value)
+}
+
+func TestParseFilter_Empty(t *testing.T) {
+	filters, err := service.ParseFilter("")
+
+	assert.NoError(t, err)
+	assert.Empty(t, filters)
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	for _, filter := range []string{
+		`userName eq "a" or userName eq "b"`,
+		`userName xx "a"`,
+		`userName eq`,
+		`userName eq "unterminated`,
+		`emails[type eq "work"]`,
+		`userName eq "a" userName eq "b"`,
+	} {
+		_, err := service.ParseFilter(filter)
+
+		var scimErr *service.Error
+		assert.ErrorAs(t, err, &scimErr, filter)
+		assert.Equal(t, service.ScimTypeInvalidFilter, scimErr.ScimType, filter)
+	}
+}
@@ -0,0 +1,509 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"gin-starter/config"
+	"gin-starter/entity"
+	userRepo "gin-starter/modules/user/v1/repository"
+)
+
+// memberPathPattern matches the `members[value eq "<id>"]` path sent to remove a single member
+var memberPathPattern = regexp.MustCompile(`(?i)^members\[\s*value\s+eq\s+"([^"]+)"\s*\]$`)
+
+// SCIMGroupService is a service for SCIM group provisioning.
+// Groups are backed by roles and, since a user holds a single role,
+// adding a member to a group moves the user out of its previous group.
+type SCIMGroupService struct {
+	cfg          config.Config
+	userRepo     userRepo.UserRepositoryUseCase
+	roleRepo     userRepo.RoleRepositoryUseCase
+	userRoleRepo userRepo.UserRoleRepositoryUseCase
+}
+
+// SCIMGroupUseCase is a use case for SCIM group provisioning
+type SCIMGroupUseCase interface {
+	// GetGroups returns groups matching the filter along with the total number of matches
+	GetGroups(ctx context.Context, filter string, startIndex, count int) ([]*entity.Role, int64, error)
+	// GetGroupByID returns a group and its members
+	GetGroupByID(ctx context.Context, id uuid.UUID) (*entity.Role, []*entity.UserRole, error)
+	// GetGroupMembers returns the members of a group
+	GetGroupMembers(ctx context.Context, id uuid.UUID) ([]*entity.UserRole, error)
+	// CreateGroup provisions a new group
+	CreateGroup(ctx context.Context, displayName string, memberIDs []string) (*entity.Role, []*entity.UserRole, error)
+	// ReplaceGroup replaces the name and members of a group
+	ReplaceGroup(ctx context.Context, id uuid.UUID, displayName string, memberIDs []string) (*entity.Role, []*entity.UserRole, error)
+	// PatchGroup applies patch operations to a group
+	PatchGroup(ctx context.Context, id uuid.UUID, operations []*PatchOperation) (*entity.Role, []*entity.UserRole, error)
+	// DeleteGroup deprovisions a group
+	DeleteGroup(ctx context.Context, id uuid.UUID) error
+}
+
+// NewSCIMGroupService is a constructor for SCIMGroupService
+func NewSCIMGroupService(
+	cfg config.Config,
+	userRepo userRepo.UserRepositoryUseCase,
+	roleRepo userRepo.RoleRepositoryUseCase,
+	userRoleRepo userRepo.UserRoleRepositoryUseCase,
+) *SCIMGroupService {
+	return &SCIMGroupService{
+		cfg:          cfg,
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		userRoleRepo: userRoleRepo,
+	}
+}
+
+// GetGroups returns groups matching the filter along with the total number of matches
+func (s *SCIMGroupService) GetGroups(ctx context.Context, filter string, startIndex, count int) ([]*entity.Role, int64, error) {
+	filters, err := ParseFilter(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, f := range filters {
+		if f.Attribute != "id" && f.Attribute != "displayname" {
+			return nil, 0, NewInvalidFilterError(fmt.Sprintf("attribute %q is not filterable", f.Attribute))
+		}
+
+		if _, ok := f.Value.(string); !ok && f.Operator != "pr" {
+			return nil, 0, NewInvalidFilterError(fmt.Sprintf("%s must be compared with a string", f.Attribute))
+		}
+	}
+
+	roles, err := s.roleRepo.FindAll(ctx, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	matched := make([]*entity.Role, 0)
+	for _, role := range roles {
+		if matchRole(role, filters) {
+			matched = append(matched, role)
+		}
+	}
+
+	limit, offset := pagination(startIndex, count)
+	total := int64(len(matched))
+
+	if offset >= len(matched) {
+		return make([]*entity.Role, 0), total, nil
+	}
+
+	matched = matched[offset:]
+	if limit < len(matched) {
+		matched = matched[:limit]
+	}
+
+	return matched, total, nil
+}
+
+// GetGroupByID returns a group and its members
+func (s *SCIMGroupService) GetGroupByID(ctx context.Context, id uuid.UUID) (*entity.Role, []*entity.UserRole, error) {
+	role, err := s.roleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if role == nil {
+		return nil, nil, ErrResourceNotFound
+	}
+
+	members, err := s.GetGroupMembers(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return role, members, nil
+}
+
+// GetGroupMembers returns the members of a group
+func (s *SCIMGroupService) GetGroupMembers(ctx context.Context, id uuid.UUID) ([]*entity.UserRole, error) {
+	userRoles, err := s.userRoleRepo.FindByRoleID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// skip memberships of deprovisioned users
+	members := make([]*entity.UserRole, 0, len(userRoles))
+	for _, ur := range userRoles {
+		if ur.User != nil {
+			members = append(members, ur)
+		}
+	}
+
+	return members, nil
+}
+
+// CreateGroup provisions a new group
+func (s *SCIMGroupService) CreateGroup(ctx context.Context, displayName string, memberIDs []string) (*entity.Role, []*entity.UserRole, error) {
+	displayName = strings.TrimSpace(displayName)
+	if displayName == "" {
+		return nil, nil, NewInvalidValueError("displayName is required")
+	}
+
+	if err := s.checkDisplayName(ctx, uuid.Nil, displayName); err != nil {
+		return nil, nil, err
+	}
+
+	members, err := s.parseMembers(ctx, memberIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	role := entity.NewRole(uuid.New(), displayName, scimActor)
+	if err := s.roleRepo.Create(ctx, role, nil); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.setMembers(ctx, role.ID, members); err != nil {
+		return nil, nil, err
+	}
+
+	return s.GetGroupByID(ctx, role.ID)
+}
+
+// ReplaceGroup replaces the name and members of a group
+func (s *SCIMGroupService) ReplaceGroup(ctx context.Context, id uuid.UUID, displayName string, memberIDs []string) (*entity.Role, []*entity.UserRole, error) {
+	role, _, err := s.GetGroupByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	members, err := s.parseMembers(ctx, memberIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.rename(ctx, role, displayName); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.setMembers(ctx, id, members); err != nil {
+		return nil, nil, err
+	}
+
+	return s.GetGroupByID(ctx, id)
+}
+
+// PatchGroup applies patch operations to a group
+func (s *SCIMGroupService) PatchGroup(ctx context.Context, id uuid.UUID, operations []*PatchOperation) (*entity.Role, []*entity.UserRole, error) {
+	role, current, err := s.GetGroupByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	displayName := role.Name
+	members := make([]string, 0, len(current))
+	for _, m := range current {
+		members = append(members, m.UserID.String())
+	}
+
+	for _, op := range operations {
+		if displayName, members, err = applyGroupOperation(displayName, members, op); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return s.ReplaceGroup(ctx, id, displayName, members)
+}
+
+// DeleteGroup deprovisions a group, the groups of system roles cannot be deleted
+func (s *SCIMGroupService) DeleteGroup(ctx context.Context, id uuid.UUID) error {
+	role, _, err := s.GetGroupByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if role.System {
+		return ErrSystemGroupProtected
+	}
+
+	if err := s.setMembers(ctx, id, nil); err != nil {
+		return err
+	}
+
+	return s.roleRepo.Delete(ctx, id, scimActor)
+}
+
+// rename updates the role name while keeping its permissions, system roles keep their name
+func (s *SCIMGroupService) rename(ctx context.Context, role *entity.Role, displayName string) error {
+	displayName = strings.TrimSpace(displayName)
+	if displayName == "" {
+		return NewInvalidValueError("displayName is required")
+	}
+
+	if displayName == role.Name {
+		return nil
+	}
+
+	if role.System {
+		return ErrSystemGroupProtected
+	}
+
+	if err := s.checkDisplayName(ctx, role.ID, displayName); err != nil {
+		return err
+	}
+
+	permissions := make([]*entity.RolePermission, 0, len(role.RolePermissions))
+	for _, rp := range role.RolePermissions {
+		permission := entity.NewRolePermission(uuid.New(), role.ID, rp.PermissionID, scimActor)
+		permission.Core = rp.Core
+		permissions = append(permissions, permission)
+	}
+
+	return s.roleRepo.Update(ctx, entity.NewRole(role.ID, displayName, scimActor), permissions)
+}
+
+// setMembers makes memberIDs the exact member list of the group.
+// Members are added before the others are removed, so the members of the super admin group can be replaced at once,
+// while removing its last active member is rejected.
+func (s *SCIMGroupService) setMembers(ctx context.Context, roleID uuid.UUID, memberIDs []uuid.UUID) error {
+	current, err := s.userRoleRepo.FindByRoleID(ctx, roleID)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[uuid.UUID]bool, len(memberIDs))
+	for _, id := range memberIDs {
+		wanted[id] = true
+	}
+
+	existing := make(map[uuid.UUID]bool, len(current))
+	for _, ur := range current {
+		existing[ur.UserID] = true
+	}
+
+	for _, id := range memberIDs {
+		if existing[id] {
+			continue
+		}
+
+		if err := s.userRoleRepo.CreateOrUpdate(ctx, entity.NewUserRole(uuid.New(), id, roleID, scimActor)); err != nil {
+			return lastSuperAdmin(err)
+		}
+	}
+
+	for _, ur := range current {
+		if wanted[ur.UserID] {
+			continue
+		}
+
+		if err := s.userRoleRepo.Delete(ctx, ur.UserID); err != nil {
+			return lastSuperAdmin(err)
+		}
+	}
+
+	return nil
+}
+
+// parseMembers validates member ids and makes sure they reference existing users
+func (s *SCIMGroupService) parseMembers(ctx context.Context, memberIDs []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(memberIDs))
+	seen := make(map[uuid.UUID]bool, len(memberIDs))
+
+	for _, value := range memberIDs {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, NewInvalidValueError(fmt.Sprintf("member %q is not a valid user id", value))
+		}
+
+		if seen[id] {
+			continue
+		}
+
+		user, err := s.userRepo.GetUserByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if user == nil {
+			return nil, NewInvalidValueError(fmt.Sprintf("member %q does not exist", value))
+		}
+
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// checkDisplayName makes sure no other group than exceptID owns the displayName
+func (s *SCIMGroupService) checkDisplayName(ctx context.Context, exceptID uuid.UUID, displayName string) error {
+	roles, err := s.roleRepo.FindAll(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, role := range roles {
+		if role.ID != exceptID && strings.EqualFold(role.Name, displayName) {
+			return ErrDisplayNameTaken
+		}
+	}
+
+	return nil
+}
+
+// matchRole reports whether the role satisfies every filter
+func matchRole(role *entity.Role, filters []*Filter) bool {
+	for _, f := range filters {
+		actual := strings.ToLower(role.Name)
+		if f.Attribute == "id" {
+			actual = role.ID.String()
+		}
+
+		expected, _ := f.Value.(string)
+		expected = strings.ToLower(expected)
+
+		var ok bool
+		switch f.Operator {
+		case "eq":
+			ok = actual == expected
+		case "ne":
+			ok = actual != expected
+		case "co":
+			ok = strings.Contains(actual, expected)
+		case "sw":
+			ok = strings.HasPrefix(actual, expected)
+		case "ew":
+			ok = strings.HasSuffix(actual, expected)
+		case "gt":
+			ok = actual > expected
+		case "ge":
+			ok = actual >= expected
+		case "lt":
+			ok = actual < expected
+		case "le":
+			ok = actual <= expected
+		case "pr":
+			ok = actual != ""
+		}
+
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// applyGroupOperation applies a single patch operation to the group name and member list
+func applyGroupOperation(displayName string, members []string, op *PatchOperation) (string, []string, error) {
+	path := strings.TrimSpace(op.Path)
+
+	switch strings.ToLower(op.Op) {
+	case "add", "replace":
+		replace := strings.EqualFold(op.Op, "replace")
+
+		switch strings.ToLower(path) {
+		case "":
+			values := struct {
+				DisplayName *string           `json:"displayName"`
+				Members     []json.RawMessage `json:"members"`
+			}{}
+			if err := json.Unmarshal(op.Value, &values); err != nil {
+				return "", nil, NewInvalidValueError("patch value must be an object when path is omitted")
+			}
+
+			if values.DisplayName != nil {
+				displayName = *values.DisplayName
+			}
+
+			if values.Members != nil {
+				raw, _ := json.Marshal(values.Members)
+				ids, err := memberValues(raw)
+				if err != nil {
+					return "", nil, err
+				}
+				members = mergeMembers(members, ids, replace)
+			}
+		case "displayname":
+			if err := json.Unmarshal(op.Value, &displayName); err != nil {
+				return "", nil, NewInvalidValueError("displayName must be a string")
+			}
+		case "members":
+			ids, err := memberValues(op.Value)
+			if err != nil {
+				return "", nil, err
+			}
+			members = mergeMembers(members, ids, replace)
+		default:
+			return "", nil, NewInvalidPathError(fmt.Sprintf("attribute %q is not supported", op.Path))
+		}
+	case "remove":
+		if match := memberPathPattern.FindStringSubmatch(path); match != nil {
+			return displayName, removeMembers(members, []string{match[1]}), nil
+		}
+
+		if !strings.EqualFold(path, "members") {
+			if path == "" {
+				return "", nil, &Error{Status: 400, ScimType: ScimTypeNoTarget, Detail: "path is required for remove"}
+			}
+			return "", nil, NewInvalidPathError(fmt.Sprintf("attribute %q cannot be removed", op.Path))
+		}
+
+		if len(op.Value) == 0 || string(op.Value) == "null" {
+			return displayName, make([]string, 0), nil
+		}
+
+		ids, err := memberValues(op.Value)
+		if err != nil {
+			return "", nil, err
+		}
+		members = removeMembers(members, ids)
+	default:
+		return "", nil, NewInvalidValueError(fmt.Sprintf("operation %q is not supported", op.Op))
+	}
+
+	return displayName, members, nil
+}
+
+// memberValues decodes the ids of a members value
+func memberValues(value json.RawMessage) ([]string, error) {
+	members := make([]struct {
+		Value string `json:"value"`
+	}, 0)
+
+	if err := json.Unmarshal(value, &members); err != nil {
+		return nil, NewInvalidValueError("members must be a list of objects with a value")
+	}
+
+	ids := make([]string, 0, len(members))
+	for _, m := range members {
+		ids = append(ids, m.Value)
+	}
+
+	return ids, nil
+}
+
+// mergeMembers adds ids to members, or replaces members with ids
+func mergeMembers(members, ids []string, replace bool) []string {
+	if replace {
+		return ids
+	}
+
+	return append(members, ids...)
+}
+
+// removeMembers removes ids from members
+func removeMembers(members, ids []string) []string {
+	removed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		removed[strings.ToLower(id)] = true
+	}
+
+	kept := make([]string, 0, len(members))
+	for _, m := range members {
+		if !removed[strings.ToLower(m)] {
+			kept = append(kept, m)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,525 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"gin-starter/config"
+	"gin-starter/entity"
+	userRepo "gin-starter/modules/user/v1/repository"
+	"gin-starter/utils"
+)
+
+const (
+	// scimActor is recorded as creator of resources provisioned through SCIM
+	scimActor = "scim"
+	// maxResults caps the number of resources returned by a single list request
+	maxResults = 200
+)
+
+// UserAttributes are the user attributes managed through SCIM
+type UserAttributes struct {
+	UserName    string
+	DisplayName string
+	PhoneNumber string
+	Password    string
+	Active      bool
+}
+
+// SCIMUserService is a service for SCIM user provisioning
+type SCIMUserService struct {
+	cfg      config.Config
+	userRepo userRepo.UserRepositoryUseCase
+}
+
+// SCIMUserUseCase is a use case for SCIM user provisioning
+type SCIMUserUseCase interface {
+	// GetUsers returns users matching the filter along with the total number of matches
+	GetUsers(ctx context.Context, filter string, startIndex, count int) ([]*entity.User, int64, error)
+	// GetUserByID returns a user by id
+	GetUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
+	// CreateUser provisions a new user
+	CreateUser(ctx context.Context, attributes *UserAttributes) (*entity.User, error)
+	// ReplaceUser replaces the attributes of a user, the last active super admin cannot be deactivated
+	ReplaceUser(ctx context.Context, id uuid.UUID, attributes *UserAttributes) (*entity.User, error)
+	// PatchUser applies patch operations to a user
+	PatchUser(ctx context.Context, id uuid.UUID, operations []*PatchOperation) (*entity.User, error)
+	// DeleteUser deprovisions a user, the last active super admin cannot be deprovisioned
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+}
+
+// NewSCIMUserService is a constructor for SCIMUserService
+func NewSCIMUserService(
+	cfg config.Config,
+	userRepo userRepo.UserRepositoryUseCase,
+) *SCIMUserService {
+	return &SCIMUserService{
+		cfg:      cfg,
+		userRepo: userRepo,
+	}
+}
+
+// GetUsers returns users matching the filter along with the total number of matches
+func (s *SCIMUserService) GetUsers(ctx context.Context, filter string, startIndex, count int) ([]*entity.User, int64, error) {
+	filters, err := ParseFilter(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	conditions := make([]*userRepo.UserCondition, 0, len(filters))
+	for _, f := range filters {
+		condition, err := userCondition(f)
+		if err != nil {
+			return nil, 0, err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	limit, offset := pagination(startIndex, count)
+
+	// a zero count only asks for totalResults, so fetch a single row and drop it
+	fetch := limit
+	if fetch == 0 {
+		fetch = 1
+	}
+
+	users, total, err := s.userRepo.FindUsers(ctx, conditions, fetch, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if limit == 0 {
+		users = make([]*entity.User, 0)
+	}
+
+	return users, total, nil
+}
+
+// GetUserByID returns a user by id
+func (s *SCIMUserService) GetUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, ErrResourceNotFound
+	}
+
+	return user, nil
+}
+
+// CreateUser provisions a new user
+func (s *SCIMUserService) CreateUser(ctx context.Context, attributes *UserAttributes) (*entity.User, error) {
+	if err := validateUserAttributes(attributes); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkUserName(ctx, uuid.Nil, attributes.UserName); err != nil {
+		return nil, err
+	}
+
+	password := attributes.Password
+	if password == "" {
+		var err error
+		if password, err = randomPassword(); err != nil {
+			return nil, err
+		}
+	}
+
+	name := attributes.DisplayName
+	if name == "" {
+		name = attributes.UserName
+	}
+
+	user := entity.NewUser(
+		uuid.New(),
+		name,
+		attributes.UserName,
+		password,
+		sql.NullTime{},
+		"",
+		attributes.PhoneNumber,
+		scimActor,
+	)
+
+	if !attributes.Active {
+		user.Status = entity.UserStatusDeactivated
+	}
+
+	if err := s.userRepo.CreateUser(ctx, user); err != nil {
+		return nil, userNameConflict(err)
+	}
+
+	return s.GetUserByID(ctx, user.ID)
+}
+
+// ReplaceUser replaces the attributes of a user, the last active super admin cannot be deactivated
+func (s *SCIMUserService) ReplaceUser(ctx context.Context, id uuid.UUID, attributes *UserAttributes) (*entity.User, error) {
+	if err := validateUserAttributes(attributes); err != nil {
+		return nil, err
+	}
+
+	user, err := s.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(user.Email, attributes.UserName) {
+		if err := s.checkUserName(ctx, user.ID, attributes.UserName); err != nil {
+			return nil, err
+		}
+	}
+
+	updated := *user
+	updated.Email = utils.NormalizeEmail(attributes.UserName)
+	updated.PhoneNumber = attributes.PhoneNumber
+	updated.Status = entity.UserStatusDeactivated
+
+	if attributes.DisplayName != "" {
+		updated.Name = attributes.DisplayName
+	}
+
+	if attributes.Active {
+		updated.Status = entity.UserStatusActivated
+	}
+
+	if err := s.userRepo.Update(ctx, &updated); err != nil {
+		return nil, lastSuperAdmin(userNameConflict(err))
+	}
+
+	if attributes.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(attributes.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.userRepo.ChangePassword(ctx, user, string(hash)); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetUserByID(ctx, id)
+}
+
+// PatchUser applies patch operations to a user
+func (s *SCIMUserService) PatchUser(ctx context.Context, id uuid.UUID, operations []*PatchOperation) (*entity.User, error) {
+	user, err := s.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := &UserAttributes{
+		UserName:    user.Email,
+		DisplayName: user.Name,
+		PhoneNumber: user.PhoneNumber,
+		Active:      user.Status == entity.UserStatusActivated,
+	}
+
+	for _, op := range operations {
+		if err := applyUserOperation(attributes, op); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.ReplaceUser(ctx, id, attributes)
+}
+
+// DeleteUser deprovisions a user, the last active super admin cannot be deprovisioned
+func (s *SCIMUserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetUserByID(ctx, id); err != nil {
+		return err
+	}
+
+	return lastSuperAdmin(s.userRepo.DeleteAdmin(ctx, id, scimActor))
+}
+
+// checkUserName makes sure no other user than exceptID owns the userName,
+// including deleted users whose email stays reserved until purged
+func (s *SCIMUserService) checkUserName(ctx context.Context, exceptID uuid.UUID, userName string) error {
+	users, _, err := s.userRepo.FindUsers(ctx, []*userRepo.UserCondition{
+		{Column: "email", Operator: "eq", Value: userName},
+	}, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range users {
+		if u.ID != exceptID {
+			return ErrUserNameTaken
+		}
+	}
+
+	reserved, err := s.userRepo.IsEmailReserved(ctx, userName)
+	if err != nil {
+		return err
+	}
+
+	if reserved {
+		return ErrUserNameTaken
+	}
+
+	return nil
+}
+
+// userNameConflict reports a write rejected by the unique email index as a taken userName
+func userNameConflict(err error) error {
+	if errors.Is(err, userRepo.ErrEmailConflict) {
+		return ErrUserNameTaken
+	}
+
+	return err
+}
+
+// lastSuperAdmin reports a write rejected for removing the last active super admin as a mutability error
+func lastSuperAdmin(err error) error {
+	if errors.Is(err, userRepo.ErrLastSuperAdmin) {
+		return ErrLastSuperAdmin
+	}
+
+	return err
+}
+
+// validateUserAttributes checks the attributes required by the user schema
+func validateUserAttributes(attributes *UserAttributes) error {
+	attributes.UserName = strings.TrimSpace(attributes.UserName)
+
+	if attributes.UserName == "" {
+		return NewInvalidValueError("userName is required")
+	}
+
+	return nil
+}
+
+// applyUserOperation applies a single patch operation to the user attributes
+func applyUserOperation(attributes *UserAttributes, op *PatchOperation) error {
+	switch strings.ToLower(op.Op) {
+	case "add", "replace":
+		if op.Path == "" {
+			values := make(map[string]json.RawMessage)
+			if err := json.Unmarshal(op.Value, &values); err != nil {
+				return NewInvalidValueError("patch value must be an object when path is omitted")
+			}
+
+			for path, value := range values {
+				if err := setUserAttribute(attributes, path, value); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		return setUserAttribute(attributes, op.Path, op.Value)
+	case "remove":
+		switch normalizedPath(op.Path) {
+		case "phonenumbers", "phonenumbers.value":
+			attributes.PhoneNumber = ""
+		case "username", "emails", "emails.value":
+			return &Error{Status: 400, ScimType: ScimTypeMutability, Detail: "userName cannot be removed"}
+		case "":
+			return &Error{Status: 400, ScimType: ScimTypeNoTarget, Detail: "path is required for remove"}
+		default:
+			return NewInvalidPathError(fmt.Sprintf("attribute %q cannot be removed", op.Path))
+		}
+
+		return nil
+	default:
+		return NewInvalidValueError(fmt.Sprintf("operation %q is not supported", op.Op))
+	}
+}
+
+// setUserAttribute sets the attribute addressed by path
+func setUserAttribute(attributes *UserAttributes, path string, value json.RawMessage) error {
+	var err error
+
+	switch normalizedPath(path) {
+	case "active":
+		attributes.Active, err = boolValue(value)
+	case "username":
+		attributes.UserName, err = stringValue(value)
+	case "displayname", "name.formatted":
+		attributes.DisplayName, err = stringValue(value)
+	case "name":
+		name := struct {
+			Formatted string `json:"formatted"`
+		}{}
+		if err = json.Unmarshal(value, &name); err == nil && name.Formatted != "" {
+			attributes.DisplayName = name.Formatted
+		}
+	case "password":
+		attributes.Password, err = stringValue(value)
+	case "phonenumbers.value":
+		attributes.PhoneNumber, err = stringValue(value)
+	case "phonenumbers":
+		attributes.PhoneNumber, err = primaryValue(value)
+	case "emails.value":
+		attributes.UserName, err = stringValue(value)
+	case "emails":
+		attributes.UserName, err = primaryValue(value)
+	default:
+		return NewInvalidPathError(fmt.Sprintf("attribute %q is not supported", path))
+	}
+
+	if err != nil {
+		return NewInvalidValueError(fmt.Sprintf("invalid value for %q", path))
+	}
+
+	return nil
+}
+
+// userCondition maps a SCIM filter onto a user repository condition
+func userCondition(f *Filter) (*userRepo.UserCondition, error) {
+	columns := map[string]string{
+		"id":                 "id",
+		"username":           "email",
+		"emails":             "email",
+		"emails.value":       "email",
+		"displayname":        "name",
+		"name.formatted":     "name",
+		"phonenumbers":       "phone_number",
+		"phonenumbers.value": "phone_number",
+		"active":             "status",
+		"meta.created":       "created_at",
+		"meta.lastmodified":  "updated_at",
+	}
+
+	column, ok := columns[f.Attribute]
+	if !ok {
+		return nil, NewInvalidFilterError(fmt.Sprintf("attribute %q is not filterable", f.Attribute))
+	}
+
+	condition := &userRepo.UserCondition{Column: column, Operator: f.Operator, Value: f.Value}
+
+	switch column {
+	case "status":
+		active, ok := f.Value.(bool)
+		if !ok || (f.Operator != "eq" && f.Operator != "ne") {
+			return nil, NewInvalidFilterError("active only supports eq and ne with a boolean value")
+		}
+
+		condition.Operator = "eq"
+		if active == (f.Operator == "ne") {
+			condition.Value = entity.UserStatusDeactivated
+		} else {
+			condition.Value = entity.UserStatusActivated
+		}
+	case "id":
+		if f.Operator != "eq" && f.Operator != "ne" {
+			return nil, NewInvalidFilterError("id only supports eq and ne")
+		}
+
+		id, ok := f.Value.(string)
+		if _, err := uuid.Parse(id); !ok || err != nil {
+			return nil, NewInvalidFilterError("id must be a valid uuid")
+		}
+	case "created_at", "updated_at":
+		if f.Operator == "pr" {
+			break
+		}
+
+		value, _ := f.Value.(string)
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, NewInvalidFilterError(fmt.Sprintf("%s must be an RFC 3339 date time", f.Attribute))
+		}
+
+		condition.Value = t
+	default:
+		if _, ok := f.Value.(string); !ok && f.Operator != "pr" {
+			return nil, NewInvalidFilterError(fmt.Sprintf("%s must be compared with a string", f.Attribute))
+		}
+	}
+
+	return condition, nil
+}
+
+// pagination converts the 1-based startIndex and count into limit and offset
+func pagination(startIndex, count int) (limit, offset int) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+
+	if count < 0 {
+		count = 0
+	}
+
+	if count > maxResults {
+		count = maxResults
+	}
+
+	return count, startIndex - 1
+}
+
+// stringValue decodes a JSON string
+func stringValue(value json.RawMessage) (string, error) {
+	var s string
+	err := json.Unmarshal(value, &s)
+	return strings.TrimSpace(s), err
+}
+
+// boolValue decodes a JSON boolean, also accepting the "True"/"False" strings sent by some clients
+func boolValue(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+
+	s, err := stringValue(value)
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(s) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
+
+// primaryValue decodes a multi valued attribute and returns the primary, or first, value
+func primaryValue(value json.RawMessage) (string, error) {
+	values := make([]struct {
+		Value   string `json:"value"`
+		Primary bool   `json:"primary"`
+	}, 0)
+
+	if err := json.Unmarshal(value, &values); err != nil {
+		return "", err
+	}
+
+	if len(values) == 0 {
+		return "", nil
+	}
+
+	for _, v := range values {
+		if v.Primary {
+			return strings.TrimSpace(v.Value), nil
+		}
+	}
+
+	return strings.TrimSpace(values[0].Value), nil
+}
+
+// randomPassword generates the password of users provisioned without one
+func randomPassword() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,53 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/scim/v1/service"
+	userRepo "gin-starter/modules/user/v1/repository"
+	mockRepo "gin-starter/test/mock/modules/user/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSCIMUserService_DeleteUser(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepository := mockRepo.NewMockUserRepositoryUseCase(ctrl)
+	s := service.NewSCIMUserService(config.Config{}, userRepository)
+	userID := uuid.New()
+
+	userRepository.EXPECT().GetUserByID(ctx, userID).Return(&entity.User{ID: userID, Status: entity.UserStatusActivated}, nil)
+	userRepository.EXPECT().DeleteAdmin(ctx, userID, gomock.Any()).Return(errors.Wrap(userRepo.ErrLastSuperAdmin, "delete"))
+
+	assert.Equal(t, service.ErrLastSuperAdmin, s.DeleteUser(ctx, userID))
+}
+
+func TestSCIMUserService_ReplaceUser(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepository := mockRepo.NewMockUserRepositoryUseCase(ctrl)
+	s := service.NewSCIMUserService(config.Config{}, userRepository)
+	user := &entity.User{ID: uuid.New(), Email: "budi@example.com", Status: entity.UserStatusActivated}
+
+	userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+	userRepository.EXPECT().
+		Update(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, updated *entity.User) error {
+			assert.Equal(t, entity.UserStatusDeactivated, updated.Status)
+			return errors.Wrap(userRepo.ErrLastSuperAdmin, "update")
+		})
+
+	_, err := s.ReplaceUser(ctx, user.ID, &service.UserAttributes{UserName: user.Email, Active: false})
+	assert.Equal(t, service.ErrLastSuperAdmin, err)
+}
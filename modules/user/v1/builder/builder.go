@@ -1,14 +1,21 @@
 package builder
 
 import (
+	"context"
 	"gin-starter/app"
+	"gin-starter/common/permission"
+	"gin-starter/common/upload"
 	"gin-starter/config"
 	notificationRepo "gin-starter/modules/notification/v1/repository"
 	notification "gin-starter/modules/notification/v1/service"
 	userRepo "gin-starter/modules/user/v1/repository"
 	"gin-starter/modules/user/v1/service"
 	"gin-starter/sdk/gcs"
+	"gin-starter/sdk/imaging"
+	"gin-starter/sdk/push"
+	"gin-starter/sdk/sms"
 	"gin-starter/utils"
+	"log"
 
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/gin-gonic/gin"
@@ -16,9 +23,36 @@ import (
 	"gorm.io/gorm"
 )
 
+// permissions are the permissions of the user module
+var permissions = []permission.Permission{
+	{Name: "user.view", Label: "View users"},
+	{Name: "user.create", Label: "Create users"},
+	{Name: "user.update", Label: "Update users"},
+	{Name: "user.import", Label: "Import users"},
+	{Name: "user.export", Label: "Export users"},
+	{Name: "admin.view", Label: "View admins"},
+	{Name: "admin.update", Label: "Update admins"},
+	{Name: "admin.delete", Label: "Delete admins"},
+	{Name: "admin.invite", Label: "Invite admins"},
+	{Name: "admin.export", Label: "Export admins"},
+	{Name: "role.view", Label: "View roles"},
+	{Name: "role.create", Label: "Create roles"},
+	{Name: "role.update", Label: "Update roles"},
+	{Name: "role.delete", Label: "Delete roles"},
+	{Name: "permission.view", Label: "View permissions"},
+	{Name: "permission.create", Label: "Create permissions"},
+	{Name: "permission.update", Label: "Update permissions"},
+	{Name: "trash.view", Label: "View deleted users and roles"},
+	{Name: "trash.restore", Label: "Restore deleted users and roles"},
+}
+
 // BuildUserHandler builds user handler
 // starting from handler down to repository or tool.
 func BuildUserHandler(cfg config.Config, router *gin.Engine, db *gorm.DB, redisPool *redis.Pool, awsSession *session.Session) {
+	// Permissions
+	permission.Declare("user", permissions...)
+	permission.DeclareSystemRole(permission.SuperAdminRole, permission.Names(permissions...)...)
+
 	// Cache
 	cache := utils.NewClient(redisPool)
 
@@ -28,21 +62,91 @@ func BuildUserHandler(cfg config.Config, router *gin.Engine, db *gorm.DB, redisP
 	urr := userRepo.NewUserRoleRepository(db, cache)
 	pr := userRepo.NewPermissionRepository(db, cache)
 	nr := notificationRepo.NewNotificationRepository(db)
+	udr := notificationRepo.NewUserDeviceRepository(db)
+	ner := notificationRepo.NewNotificationEventRepository(redisPool, cfg.Stream.Length)
+	ntr := notificationRepo.NewNotificationTemplateRepository(db)
+	uir := userRepo.NewUserImportRepository(db)
+	air := userRepo.NewAdminInvitationRepository(db, cache)
+	ecr := userRepo.NewEmailChangeRepository(db)
+	pvr := userRepo.NewPhoneVerificationRepository(db)
+	upr := userRepo.NewUserPreferenceRepository(db, cache)
+
+	// Upload Policy
+	scanner, err := upload.NewScanner(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	guard := upload.NewGuard(upload.Policies(cfg), scanner)
 
 	// Cloud Storage
-	cloudStorage := gcs.NewGoogleCloudStorage(cfg)
-	// cloudStorage := aws.NewS3Bucket(cfg, awsSession)
+	cloudStorage := upload.NewStorage(guard, gcs.NewGoogleCloudStorage(cfg))
+	// cloudStorage := upload.NewStorage(guard, aws.NewS3Bucket(cfg, awsSession))
+	photoStorage := imaging.NewPhotoStorage(cfg, guard, cloudStorage)
+
+	// SMS
+	smsSender, err := sms.NewSender(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rateLimitedSender, err := sms.NewRateLimitedSender(cfg, cache, smsSender)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Push
+	pushProvider, err := push.NewProvider(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// Service
-	nc := notification.NewNotificationCreator(cfg, nr)
+	pm := service.NewUserPreferenceManager(cfg, ur, upr)
+	nc := notification.NewNotificationCreator(cfg, nr, ntr, udr, ner, pm, pushProvider)
 	uc := service.NewUserCreator(cfg, ur, urr, rr, pr, nc, cloudStorage)
 	uf := service.NewUserFinder(cfg, ur, urr, rr, pr)
-	uu := service.NewUserUpdater(cfg, ur, urr, rr, pr)
-	ud := service.NewUserDeleter(cfg, ur, rr)
+	uu := service.NewUserUpdater(cfg, ur, urr, rr, pr, photoStorage)
+	ud := service.NewUserDeleter(cfg, ur, urr, rr)
+	ui := service.NewUserImporter(cfg, ur, urr, rr, uir)
+	ue := service.NewUserExporter(cfg, ur)
+	ut := service.NewUserTrash(cfg, ur, rr)
+	ai := service.NewAdminInviter(cfg, air, ur, rr, urr)
+	ec := service.NewUserEmailChanger(cfg, ur, ecr)
+	pv := service.NewUserPhoneVerifier(cfg, ur, pvr, rateLimitedSender)
+	iw := service.NewUserImportWorker(cfg, uir)
+	pc := service.NewPermissionChecker(urr, rr)
+
+	// Background job
+	go ut.RunPurger(context.Background())
+	go iw.Run(context.Background())
 
 	// Handler
-	app.UserFinderHTTPHandler(cfg, router, uf)
-	app.UserCreatorHTTPHandler(cfg, router, uc, uf, cloudStorage)
-	app.UserUpdaterHTTPHandler(cfg, router, uu, uf, cloudStorage)
-	app.UserDeleterHTTPHandler(cfg, router, ud, cloudStorage)
+	app.UserFinderHTTPHandler(cfg, router, pc, uf)
+	app.UserCreatorHTTPHandler(cfg, router, pc, uc, uf, photoStorage)
+	app.AdminInviterHTTPHandler(cfg, router, pc, ai)
+	app.UserUpdaterHTTPHandler(cfg, router, pc, uu, uf, photoStorage)
+	app.UserEmailChangerHTTPHandler(cfg, router, ec)
+	app.UserPhoneVerifierHTTPHandler(cfg, router, pv)
+	app.UserPreferenceManagerHTTPHandler(cfg, router, pc, pm)
+	app.UserDeleterHTTPHandler(cfg, router, pc, ud, cloudStorage)
+	app.UserImporterHTTPHandler(cfg, router, pc, ui)
+	app.UserExporterHTTPHandler(cfg, router, pc, ue)
+	app.UserTrashHTTPHandler(cfg, router, pc, ut)
+}
+
+// SyncPermissions synchronises the permissions and default roles the module builders declared into the database.
+// It runs once every module is built so that the declarations are complete.
+func SyncPermissions(cfg config.Config, db *gorm.DB, redisPool *redis.Pool) error {
+	// Cache
+	cache := utils.NewClient(redisPool)
+
+	// Repository
+	pr := userRepo.NewPermissionRepository(db, cache)
+	rr := userRepo.NewRoleRepository(db, cache)
+
+	// Service
+	ps := service.NewPermissionSynchronizer(cfg, permission.Default, pr, rr)
+
+	_, err := ps.Sync(context.Background())
+	return err
 }
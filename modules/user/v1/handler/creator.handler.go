@@ -3,6 +3,7 @@ package handler
 import (
 	"gin-starter/common/errors"
 	"gin-starter/common/interfaces"
+	"gin-starter/middleware"
 	"gin-starter/modules/user/v1/service"
 	"gin-starter/resource"
 	"gin-starter/response"
@@ -15,17 +16,17 @@ import (
 // UserCreatorHandler is a handler for user finder
 type UserCreatorHandler struct {
 	userCreator  service.UserCreatorUseCase
-	cloudStorage interfaces.CloudStorageUseCase
+	photoStorage interfaces.PhotoStorageUseCase
 }
 
 // NewUserCreatorHandler is a constructor for UserCreatorHandler
 func NewUserCreatorHandler(
 	userCreator service.UserCreatorUseCase,
-	cloudStorage interfaces.CloudStorageUseCase,
+	photoStorage interfaces.PhotoStorageUseCase,
 ) *UserCreatorHandler {
 	return &UserCreatorHandler{
 		userCreator:  userCreator,
-		cloudStorage: cloudStorage,
+		photoStorage: photoStorage,
 	}
 }
 
@@ -39,7 +40,7 @@ func (uc *UserCreatorHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
-	imagePath, err := uc.cloudStorage.Upload(request.Photo, "users/user/profile")
+	photo, err := uc.photoStorage.Upload(c, request.Photo, "users/user/profile")
 
 	if err != nil {
 		parseError := errors.ParseError(err)
@@ -63,72 +64,13 @@ func (uc *UserCreatorHandler) CreateUser(c *gin.Context) {
 		request.Email,
 		request.Password,
 		request.PhoneNumber,
-		imagePath,
+		photo,
 		dob,
 	)
 
 	if err != nil {
 		parseError := errors.ParseError(err)
-		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
-		c.Abort()
-		return
-	}
-
-	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewUserProfile(user)))
-}
-
-// CreateAdmin is a handler for creating admin
-func (uc *UserCreatorHandler) CreateAdmin(c *gin.Context) {
-	var request resource.CreateAdminRequest
-
-	if err := c.ShouldBind(&request); err != nil {
-		parseError := errors.ParseError(err)
-		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
-		c.Abort()
-		return
-	}
-
-	imagePath, err := uc.cloudStorage.Upload(request.Photo, "users/admin/profile")
-
-	if err != nil {
-		parseError := errors.ParseError(err)
-		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
-		c.Abort()
-		return
-	}
-
-	dob, err := utils.DateStringToTime(request.DOB)
-
-	if err != nil {
-		parseError := errors.ParseError(err)
-		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
-		c.Abort()
-		return
-	}
-
-	roleID, err := uuid.Parse(request.RoleID)
-
-	if err != nil {
-		parseError := errors.ParseError(err)
-		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
-		c.Abort()
-		return
-	}
-
-	user, err := uc.userCreator.CreateAdmin(
-		c,
-		request.Name,
-		request.Email,
-		request.Password,
-		request.PhoneNumber,
-		imagePath,
-		dob,
-		roleID,
-	)
-
-	if err != nil {
-		parseError := errors.ParseError(err)
-		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.JSON(parseError.Code, response.ErrorAPIResponseWithField(parseError.Code, parseError.Message, parseError.Field))
 		c.Abort()
 		return
 	}
@@ -183,7 +125,7 @@ func (uc *UserCreatorHandler) CreateRole(c *gin.Context) {
 		c,
 		request.Name,
 		permissionIDs,
-		"system",
+		middleware.UserID.String(),
 	)
 	if err != nil {
 		parseError := errors.ParseError(err)
@@ -3,6 +3,7 @@ package handler
 import (
 	"gin-starter/common/errors"
 	"gin-starter/common/interfaces"
+	"gin-starter/middleware"
 	"gin-starter/modules/user/v1/service"
 	"gin-starter/resource"
 	"gin-starter/response"
@@ -46,7 +47,7 @@ func (ud *UserDeleterHandler) DeleteAdmin(c *gin.Context) {
 		return
 	}
 
-	if err := ud.userDeleter.DeleteAdmin(c, reqID); err != nil {
+	if err := ud.userDeleter.DeleteAdmin(c, reqID, middleware.UserID.String()); err != nil {
 		parseError := errors.ParseError(err)
 		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
 		c.Abort()
@@ -74,7 +75,24 @@ func (ud *UserDeleterHandler) DeleteRole(c *gin.Context) {
 		return
 	}
 
-	if err := ud.userDeleter.DeleteRole(c, reqID, "system"); err != nil {
+	var query resource.DeleteRoleQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	reassignRoleID := uuid.Nil
+	if query.ReassignRoleID != "" {
+		if reassignRoleID, err = uuid.Parse(query.ReassignRoleID); err != nil {
+			c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+			c.Abort()
+			return
+		}
+	}
+
+	if err := ud.userDeleter.DeleteRole(c, reqID, reassignRoleID, middleware.UserID.String()); err != nil {
 		parseError := errors.ParseError(err)
 		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
 		c.Abort()
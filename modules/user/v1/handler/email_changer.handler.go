@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+
+	"gin-starter/common/errors"
+	"gin-starter/middleware"
+	"gin-starter/modules/user/v1/service"
+	"gin-starter/resource"
+	"gin-starter/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserEmailChangerHandler is a handler for the email change requests of users
+type UserEmailChangerHandler struct {
+	emailChanger service.UserEmailChangerUseCase
+}
+
+// NewUserEmailChangerHandler is a constructor for UserEmailChangerHandler
+func NewUserEmailChangerHandler(
+	emailChanger service.UserEmailChangerUseCase,
+) *UserEmailChangerHandler {
+	return &UserEmailChangerHandler{
+		emailChanger: emailChanger,
+	}
+}
+
+// RequestEmailChange is a handler for requesting to change the email of the signed in user
+func (ec *UserEmailChangerHandler) RequestEmailChange(c *gin.Context) {
+	var request resource.ChangeEmailRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	change, err := ec.emailChanger.RequestEmailChange(c, middleware.UserID, request.Email)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponseWithField(parseError.Code, parseError.Message, parseError.Field))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewEmailChangeResponse(change)))
+}
+
+// ConfirmEmailChange is a handler for confirming an email change from the new address
+func (ec *UserEmailChangerHandler) ConfirmEmailChange(c *gin.Context) {
+	var request resource.EmailChangeTokenRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	if err := ec.emailChanger.ConfirmEmailChange(c, request.Token); err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponseWithField(parseError.Code, parseError.Message, parseError.Field))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
+}
+
+// CancelEmailChange is a handler for cancelling an email change from the current address
+func (ec *UserEmailChangerHandler) CancelEmailChange(c *gin.Context) {
+	var request resource.EmailChangeTokenRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	if err := ec.emailChanger.CancelEmailChange(c, request.Token); err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/query"
+	"gin-starter/modules/user/v1/service"
+	"gin-starter/resource"
+	"gin-starter/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportContentTypes maps the export formats to their content type
+var exportContentTypes = map[string]string{
+	service.ExportFormatCSV:  "text/csv",
+	service.ExportFormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// UserExporterHandler is a handler for user exporter
+type UserExporterHandler struct {
+	userExporter service.UserExporterUseCase
+}
+
+// NewUserExporterHandler is a constructor for UserExporterHandler
+func NewUserExporterHandler(
+	userExporter service.UserExporterUseCase,
+) *UserExporterHandler {
+	return &UserExporterHandler{
+		userExporter: userExporter,
+	}
+}
+
+// ExportUsers is a handler for exporting users
+func (ue *UserExporterHandler) ExportUsers(c *gin.Context) {
+	ue.export(c, false, "users")
+}
+
+// ExportAdminUsers is a handler for exporting admin users
+func (ue *UserExporterHandler) ExportAdminUsers(c *gin.Context) {
+	ue.export(c, true, "admins")
+}
+
+// export streams the users matching the list filter as a file attachment
+func (ue *UserExporterHandler) export(c *gin.Context, admin bool, name string) {
+	var request resource.ExportUsersRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	format := strings.ToLower(request.Format)
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		c.JSON(errors.ErrUnsupportedExportFormat.Code, response.ErrorAPIResponse(errors.ErrUnsupportedExportFormat.Code, errors.ErrUnsupportedExportFormat.Message))
+		c.Abort()
+		return
+	}
+
+	q, err := query.Parse(c.Request.URL.Query())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	var columns []string
+	if request.Columns != "" {
+		columns = strings.Split(request.Columns, ",")
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-%s.%s\"", name, time.Now().Format("20060102150405"), format))
+
+	err = ue.userExporter.ExportUsers(c, c.Writer, admin, format, columns, q)
+	if err == nil {
+		return
+	}
+
+	// once the body has started streaming the status can no longer be changed
+	if c.Writer.Written() {
+		log.Println("[UserExporterHandler-export]", err)
+		c.Abort()
+		return
+	}
+
+	c.Writer.Header().Del("Content-Type")
+	c.Writer.Header().Del("Content-Disposition")
+
+	parseError := errors.ParseError(err)
+	c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+	c.Abort()
+}
@@ -2,10 +2,12 @@ package handler
 
 import (
 	"gin-starter/common/errors"
+	"gin-starter/common/query"
 	"gin-starter/middleware"
 	"gin-starter/modules/user/v1/service"
 	"gin-starter/resource"
 	"gin-starter/response"
+	"gin-starter/utils"
 	"github.com/google/uuid"
 	"net/http"
 
@@ -37,6 +39,7 @@ func (uf *UserFinderHandler) GetUserProfile(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", utils.ETag(res.Version))
 	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewUserProfile(res)))
 }
 
@@ -72,20 +75,21 @@ func (uf *UserFinderHandler) GetAdminProfile(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", utils.ETag(user.Version))
 	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewUserAdmin(user)))
 }
 
 // GetUsers is a handler for getting users
 func (uf *UserFinderHandler) GetUsers(c *gin.Context) {
-	var request resource.GetAdminUsersRequest
-
-	if err := c.ShouldBind(&request); err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+	q, err := query.Parse(c.Request.URL.Query())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
 		c.Abort()
 		return
 	}
 
-	users, total, err := uf.userFinder.GetUsers(c, request.Query, request.Sort, request.Order, request.Limit, request.Offset)
+	users, page, err := uf.userFinder.GetUsers(c, q)
 
 	if err != nil {
 		parseError := errors.ParseError(err)
@@ -102,7 +106,8 @@ func (uf *UserFinderHandler) GetUsers(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", &resource.GetUsersResponse{
 		List:  res,
-		Total: total,
+		Total: page.Total,
+		Meta:  resource.NewListMeta(page),
 	}))
 }
 
@@ -133,20 +138,21 @@ func (uf *UserFinderHandler) GetUserByID(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", utils.ETag(user.Version))
 	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewUserProfile(user)))
 }
 
 // GetAdminUsers is a handler for getting admin users
 func (uf *UserFinderHandler) GetAdminUsers(c *gin.Context) {
-	var request resource.GetAdminUsersRequest
-
-	if err := c.ShouldBind(&request); err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+	q, err := query.Parse(c.Request.URL.Query())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
 		c.Abort()
 		return
 	}
 
-	users, total, err := uf.userFinder.GetAdminUsers(c, request.Query, request.Sort, request.Order, request.Limit, request.Offset)
+	users, page, err := uf.userFinder.GetAdminUsers(c, q)
 
 	if err != nil {
 		parseError := errors.ParseError(err)
@@ -163,7 +169,8 @@ func (uf *UserFinderHandler) GetAdminUsers(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", &resource.GetAdminUsersResponse{
 		List:  res,
-		Total: total,
+		Total: page.Total,
+		Meta:  resource.NewListMeta(page),
 	}))
 }
 
@@ -194,19 +201,21 @@ func (uf *UserFinderHandler) GetAdminUserByID(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", utils.ETag(user.Version))
 	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewUserAdmin(user)))
 }
 
 // GetRoles is a handler for getting roles
 func (uf *UserFinderHandler) GetRoles(c *gin.Context) {
-	var request resource.PaginationQueryParam
-	if err := c.ShouldBindQuery(&request); err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+	q, err := query.Parse(c.Request.URL.Query())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
 		c.Abort()
 		return
 	}
 
-	page, err := uf.userFinder.GetRoles(c, request.Query, request.Sort, request.Order, request.Limit, request.Offset)
+	page, err := uf.userFinder.GetRoles(c, q)
 
 	if err != nil {
 		parseError := errors.ParseError(err)
@@ -221,16 +230,16 @@ func (uf *UserFinderHandler) GetRoles(c *gin.Context) {
 		res = append(res, resource.NewRoleResponse(v))
 	}
 
-	currentPage := request.Offset/request.Limit + 1
-	totalPage := len(res) / request.Limit
-	if len(res)%request.Limit > 0 {
+	currentPage := q.Offset/q.Limit + 1
+	totalPage := len(res) / q.Limit
+	if len(res)%q.Limit > 0 {
 		totalPage++
 	}
 
 	meta := &resource.Meta{
 		Total:       len(res),
-		Limit:       request.Limit,
-		Offset:      request.Offset,
+		Limit:       q.Limit,
+		Offset:      q.Offset,
 		CurrentPage: currentPage,
 		TotalPage:   totalPage,
 	}
@@ -260,8 +269,9 @@ func (uf *UserFinderHandler) GetPermissions(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", &resource.GetPermissionResponse{
-		List:  res,
-		Total: int64(len(res)),
+		List:   res,
+		Groups: resource.NewPermissionGroupsResponse(permissions),
+		Total:  int64(len(res)),
 	}))
 }
 
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gin-starter/common/errors"
+	"gin-starter/middleware"
+	"gin-starter/modules/user/v1/service"
+	"gin-starter/resource"
+	"gin-starter/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UserImporterHandler is a handler for user importer
+type UserImporterHandler struct {
+	userImporter service.UserImporterUseCase
+}
+
+// NewUserImporterHandler is a constructor for UserImporterHandler
+func NewUserImporterHandler(
+	userImporter service.UserImporterUseCase,
+) *UserImporterHandler {
+	return &UserImporterHandler{
+		userImporter: userImporter,
+	}
+}
+
+// ImportUsers is a handler for importing users from a CSV or XLSX file.
+// A dry run returns the validation report of every row without creating anything.
+func (ui *UserImporterHandler) ImportUsers(c *gin.Context) {
+	var request resource.ImportUsersRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	ext := strings.ToLower(filepath.Ext(request.File.Filename))
+	if ext != ".csv" && ext != ".xlsx" {
+		c.JSON(errors.ErrUnsupportedImportFile.Code, response.ErrorAPIResponse(errors.ErrUnsupportedImportFile.Code, errors.ErrUnsupportedImportFile.Message))
+		c.Abort()
+		return
+	}
+
+	tmp, err := os.CreateTemp("", "user-import-*"+ext)
+	if err != nil {
+		log.Println("[UserImporterHandler-ImportUsers]", err)
+		c.JSON(errors.ErrInternalServerError.Code, response.ErrorAPIResponse(errors.ErrInternalServerError.Code, errors.ErrInternalServerError.Message))
+		c.Abort()
+		return
+	}
+	_ = tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := c.SaveUploadedFile(request.File, tmp.Name()); err != nil {
+		log.Println("[UserImporterHandler-ImportUsers]", err)
+		c.JSON(errors.ErrInternalServerError.Code, response.ErrorAPIResponse(errors.ErrInternalServerError.Code, errors.ErrInternalServerError.Message))
+		c.Abort()
+		return
+	}
+
+	if request.DryRun {
+		rows, err := ui.userImporter.PreviewImport(c, tmp.Name(), request.CreateRoles, middleware.UserID)
+		if err != nil {
+			parseError := errors.ParseError(err)
+			c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+			c.Abort()
+			return
+		}
+
+		c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewUserImportPreviewResponse(rows)))
+		return
+	}
+
+	userImport, err := ui.userImporter.Import(
+		c,
+		tmp.Name(),
+		filepath.Base(request.File.Filename),
+		request.CreateRoles,
+		middleware.UserID,
+	)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response.SuccessAPIResponseList(http.StatusAccepted, "success", resource.NewUserImportResponse(userImport)))
+}
+
+// GetUserImport is a handler for getting the status of a user import
+func (ui *UserImporterHandler) GetUserImport(c *gin.Context) {
+	var request resource.GetUserImportRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	reqID, err := uuid.Parse(request.ID)
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	userImport, err := ui.userImporter.GetImportByID(c, reqID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewUserImportResponse(userImport)))
+}
+
+// GetUserImportReport is a handler for downloading the per-row result of a user import as CSV
+func (ui *UserImporterHandler) GetUserImportReport(c *gin.Context) {
+	var request resource.GetUserImportRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	reqID, err := uuid.Parse(request.ID)
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	userImport, err := ui.userImporter.GetImportByID(c, reqID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"user-import-%s.csv\"", userImport.ID))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{"row_number", "name", "email", "phone_number", "dob", "role", "status", "message"})
+
+	// the cells come from the uploaded file, they must not run as formulas when the report is opened
+	for _, row := range userImport.Rows {
+		_ = writer.Write([]string{
+			strconv.Itoa(row.RowNumber),
+			service.EscapeCSVFormula(row.Name),
+			service.EscapeCSVFormula(row.Email),
+			service.EscapeCSVFormula(row.PhoneNumber),
+			service.EscapeCSVFormula(row.DOB),
+			service.EscapeCSVFormula(row.Role),
+			row.Status,
+			service.EscapeCSVFormula(row.Message),
+		})
+	}
+
+	writer.Flush()
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"gin-starter/common/errors"
+	"gin-starter/middleware"
+	"gin-starter/modules/user/v1/service"
+	"gin-starter/resource"
+	"gin-starter/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminInviterHandler is a handler for admin invitations
+type AdminInviterHandler struct {
+	adminInviter service.AdminInviterUseCase
+}
+
+// NewAdminInviterHandler is a constructor for AdminInviterHandler
+func NewAdminInviterHandler(
+	adminInviter service.AdminInviterUseCase,
+) *AdminInviterHandler {
+	return &AdminInviterHandler{
+		adminInviter: adminInviter,
+	}
+}
+
+// InviteAdmin is a handler for inviting an admin
+func (ai *AdminInviterHandler) InviteAdmin(c *gin.Context) {
+	var request resource.InviteAdminRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	roleID, err := uuid.Parse(request.RoleID)
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	invitation, err := ai.adminInviter.Invite(c, request.Name, request.Email, roleID, middleware.UserID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponseWithField(parseError.Code, parseError.Message, parseError.Field))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewAdminInvitationResponse(invitation, time.Now())))
+}
+
+// GetAdminInvitations is a handler for listing the pending invitations
+func (ai *AdminInviterHandler) GetAdminInvitations(c *gin.Context) {
+	var request resource.GetAdminInvitationsRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	invitations, total, err := ai.adminInviter.GetPendingInvitations(c, request.Limit, request.Offset)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	now := time.Now()
+	res := make([]*resource.AdminInvitation, 0)
+	for _, invitation := range invitations {
+		res = append(res, resource.NewAdminInvitationResponse(invitation, now))
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", &resource.GetAdminInvitationsResponse{
+		List:  res,
+		Total: total,
+	}))
+}
+
+// ResendAdminInvitation is a handler for resending an invitation
+func (ai *AdminInviterHandler) ResendAdminInvitation(c *gin.Context) {
+	var request resource.AdminInvitationRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	reqID, err := uuid.Parse(request.ID)
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	invitation, err := ai.adminInviter.Resend(c, reqID, middleware.UserID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewAdminInvitationResponse(invitation, time.Now())))
+}
+
+// RevokeAdminInvitation is a handler for revoking an invitation
+func (ai *AdminInviterHandler) RevokeAdminInvitation(c *gin.Context) {
+	var request resource.AdminInvitationRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	reqID, err := uuid.Parse(request.ID)
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	if err := ai.adminInviter.Revoke(c, reqID, middleware.UserID); err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
+}
+
+// AcceptAdminInvitation is a handler for setting the password of an invited admin
+func (ai *AdminInviterHandler) AcceptAdminInvitation(c *gin.Context) {
+	var request resource.AcceptAdminInvitationRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	if request.Password != request.PasswordConfirmation {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, errors.ErrWrongPasswordConfirmation.Message))
+		c.Abort()
+		return
+	}
+
+	if err := ai.adminInviter.Accept(c, request.Token, request.Password); err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
+}
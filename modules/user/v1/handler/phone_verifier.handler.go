@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"net/http"
+
+	"gin-starter/common/errors"
+	"gin-starter/middleware"
+	"gin-starter/modules/user/v1/service"
+	"gin-starter/resource"
+	"gin-starter/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserPhoneVerifierHandler is a handler for verifying the phone numbers of users
+type UserPhoneVerifierHandler struct {
+	phoneVerifier service.UserPhoneVerifierUseCase
+}
+
+// NewUserPhoneVerifierHandler is a constructor for UserPhoneVerifierHandler
+func NewUserPhoneVerifierHandler(
+	phoneVerifier service.UserPhoneVerifierUseCase,
+) *UserPhoneVerifierHandler {
+	return &UserPhoneVerifierHandler{
+		phoneVerifier: phoneVerifier,
+	}
+}
+
+// SendPhoneVerification is a handler for sending a verification code to the phone number of the signed in user
+func (pv *UserPhoneVerifierHandler) SendPhoneVerification(c *gin.Context) {
+	verification, err := pv.phoneVerifier.SendPhoneVerification(c, middleware.UserID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewPhoneVerificationResponse(verification)))
+}
+
+// VerifyPhone is a handler for verifying the phone number of the signed in user
+func (pv *UserPhoneVerifierHandler) VerifyPhone(c *gin.Context) {
+	var request resource.VerifyPhoneRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	if err := pv.phoneVerifier.VerifyPhone(c, middleware.UserID, request.Code); err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
+}
+
+// SetOTPChannel is a handler for choosing the channel of the login code of the signed in user
+func (pv *UserPhoneVerifierHandler) SetOTPChannel(c *gin.Context) {
+	var request resource.OTPChannelRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	if err := pv.phoneVerifier.SetOTPChannel(c, middleware.UserID, request.Channel); err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"gin-starter/common/errors"
+	"gin-starter/middleware"
+	"gin-starter/modules/user/v1/service"
+	"gin-starter/resource"
+	"gin-starter/response"
+)
+
+// UserPreferenceManagerHandler is a handler for the preferences of users
+type UserPreferenceManagerHandler struct {
+	preferenceManager service.UserPreferenceManagerUseCase
+}
+
+// NewUserPreferenceManagerHandler is a constructor for UserPreferenceManagerHandler
+func NewUserPreferenceManagerHandler(
+	preferenceManager service.UserPreferenceManagerUseCase,
+) *UserPreferenceManagerHandler {
+	return &UserPreferenceManagerHandler{
+		preferenceManager: preferenceManager,
+	}
+}
+
+// GetPreferences is a handler for getting the preferences of the signed in user
+func (pm *UserPreferenceManagerHandler) GetPreferences(c *gin.Context) {
+	preferences, err := pm.preferenceManager.GetPreferences(c, middleware.UserID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", preferences))
+}
+
+// UpdatePreferences is a handler for changing the preferences of the signed in user
+func (pm *UserPreferenceManagerHandler) UpdatePreferences(c *gin.Context) {
+	var request resource.UpdatePreferencesRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	preferences, err := pm.preferenceManager.UpdatePreferences(c, middleware.UserID, request, middleware.UserID.String())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", preferences))
+}
+
+// GetUserPreferences is a handler for getting the preferences of a user in the CMS
+func (pm *UserPreferenceManagerHandler) GetUserPreferences(c *gin.Context) {
+	userID, ok := bindUserID(c)
+	if !ok {
+		return
+	}
+
+	stored, err := pm.preferenceManager.GetStoredPreferences(c, userID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewUserPreferenceSettings(stored)))
+}
+
+// UpdateUserPreferences is a handler for overriding the preferences of a user in the CMS
+func (pm *UserPreferenceManagerHandler) UpdateUserPreferences(c *gin.Context) {
+	userID, ok := bindUserID(c)
+	if !ok {
+		return
+	}
+
+	var request resource.UpdatePreferencesRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	if _, err := pm.preferenceManager.UpdatePreferences(c, userID, request, middleware.UserID.String()); err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	stored, err := pm.preferenceManager.GetStoredPreferences(c, userID)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewUserPreferenceSettings(stored)))
+}
+
+// bindUserID binds the id of the user in the uri, the response is written when it is not a valid id
+func bindUserID(c *gin.Context) (uuid.UUID, bool) {
+	var request resource.GetUserByIDRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(request.ID)
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+
+	"gin-starter/common/errors"
+	"gin-starter/middleware"
+	"gin-starter/modules/user/v1/service"
+	"gin-starter/resource"
+	"gin-starter/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UserTrashHandler is a handler for deleted users and roles
+type UserTrashHandler struct {
+	userTrash service.UserTrashUseCase
+}
+
+// NewUserTrashHandler is a constructor for UserTrashHandler
+func NewUserTrashHandler(
+	userTrash service.UserTrashUseCase,
+) *UserTrashHandler {
+	return &UserTrashHandler{
+		userTrash: userTrash,
+	}
+}
+
+// GetTrash is a handler for listing deleted users or roles
+func (ut *UserTrashHandler) GetTrash(c *gin.Context) {
+	var request resource.GetTrashRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	res := make([]*resource.TrashItem, 0)
+	var total int64
+
+	switch request.Type {
+	case service.TrashTypeUser:
+		users, count, err := ut.userTrash.GetDeletedUsers(c, request.Limit, request.Offset)
+		if err != nil {
+			parseError := errors.ParseError(err)
+			c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+			c.Abort()
+			return
+		}
+
+		for _, u := range users {
+			res = append(res, resource.NewUserTrashItem(u, ut.userTrash.PurgeAt(u.DeletedAt.Time)))
+		}
+		total = count
+	case service.TrashTypeRole:
+		roles, count, err := ut.userTrash.GetDeletedRoles(c, request.Limit, request.Offset)
+		if err != nil {
+			parseError := errors.ParseError(err)
+			c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+			c.Abort()
+			return
+		}
+
+		for _, r := range roles {
+			res = append(res, resource.NewRoleTrashItem(r, ut.userTrash.PurgeAt(r.DeletedAt.Time)))
+		}
+		total = count
+	default:
+		c.JSON(errors.ErrInvalidTrashType.Code, response.ErrorAPIResponse(errors.ErrInvalidTrashType.Code, errors.ErrInvalidTrashType.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", &resource.GetTrashResponse{
+		List:  res,
+		Total: total,
+	}))
+}
+
+// Restore is a handler for restoring a deleted user or role
+func (ut *UserTrashHandler) Restore(c *gin.Context) {
+	var request resource.RestoreTrashRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		c.Abort()
+		return
+	}
+
+	reqID, err := uuid.Parse(request.ID)
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	switch request.Type {
+	case service.TrashTypeUser:
+		err = ut.userTrash.RestoreUser(c, reqID, middleware.UserID.String())
+	case service.TrashTypeRole:
+		err = ut.userTrash.RestoreRole(c, reqID, middleware.UserID.String())
+	default:
+		err = errors.ErrInvalidTrashType.Error()
+	}
+
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
+}
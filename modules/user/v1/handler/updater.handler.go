@@ -3,6 +3,7 @@ package handler
 import (
 	"gin-starter/common/errors"
 	"gin-starter/common/interfaces"
+	"gin-starter/common/patch"
 	"gin-starter/entity"
 	"gin-starter/middleware"
 	"gin-starter/modules/user/v1/service"
@@ -19,19 +20,19 @@ import (
 type UserUpdaterHandler struct {
 	userUpdater  service.UserUpdaterUseCase
 	userFinder   service.UserFinderUseCase
-	cloudStorage interfaces.CloudStorageUseCase
+	photoStorage interfaces.PhotoStorageUseCase
 }
 
 // NewUserUpdaterHandler is a constructor for UserUpdaterHandler
 func NewUserUpdaterHandler(
 	userUpdater service.UserUpdaterUseCase,
 	userFinder service.UserFinderUseCase,
-	cloudStorage interfaces.CloudStorageUseCase,
+	photoStorage interfaces.PhotoStorageUseCase,
 ) *UserUpdaterHandler {
 	return &UserUpdaterHandler{
 		userUpdater:  userUpdater,
 		userFinder:   userFinder,
-		cloudStorage: cloudStorage,
+		photoStorage: photoStorage,
 	}
 }
 
@@ -185,23 +186,35 @@ func (uu *UserUpdaterHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	_, err := uu.userFinder.GetUserByID(c, middleware.UserID)
+	version, err := utils.ParseIfMatch(c.GetHeader("If-Match"))
 
 	if err != nil {
-		parseError := errors.ParseError(err)
-		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
-		c.Abort()
+		uu.abortUpdate(c, err, middleware.UserID, userProfileRepresentation)
 		return
 	}
 
-	imagePath, err := uu.cloudStorage.Upload(request.Photo, "users/user/profile")
+	_, err = uu.userFinder.GetUserByID(c, middleware.UserID)
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorAPIResponse(http.StatusBadRequest, err.Error()))
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
 		c.Abort()
 		return
 	}
 
+	var photo *entity.UserPhoto
+
+	if request.Photo != nil {
+		photo, err = uu.photoStorage.Upload(c, request.Photo, "users/user/profile")
+
+		if err != nil {
+			parseError := errors.ParseError(err)
+			c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+			c.Abort()
+			return
+		}
+	}
+
 	dob, err := utils.DateStringToTime(request.DOB)
 
 	if err != nil {
@@ -213,21 +226,22 @@ func (uu *UserUpdaterHandler) UpdateUser(c *gin.Context) {
 	user := entity.NewUser(
 		middleware.UserID,
 		request.Name,
-		request.Email,
+		"",
 		request.Name,
 		utils.TimeToNullTime(dob),
-		imagePath,
+		"",
 		request.PhoneNumber,
 		"system",
 	)
+	user.SetPhoto(photo)
+	user.Version = version
 
 	if err := uu.userUpdater.Update(c, user); err != nil {
-		parseError := errors.ParseError(err)
-		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
-		c.Abort()
+		uu.abortUpdate(c, err, middleware.UserID, userProfileRepresentation)
 		return
 	}
 
+	c.Header("ETag", utils.ETag(user.Version))
 	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
 }
 
@@ -249,10 +263,15 @@ func (uu *UserUpdaterHandler) ActivateDeactivateUser(c *gin.Context) {
 		return
 	}
 
-	if err := uu.userUpdater.ActivateDeactivateUser(c, reqID); err != nil {
-		parseError := errors.ParseError(err)
-		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
-		c.Abort()
+	version, err := utils.ParseIfMatch(c.GetHeader("If-Match"))
+
+	if err != nil {
+		uu.abortUpdate(c, err, reqID, userProfileRepresentation)
+		return
+	}
+
+	if err := uu.userUpdater.ActivateDeactivateUser(c, reqID, version); err != nil {
+		uu.abortUpdate(c, err, reqID, userProfileRepresentation)
 		return
 	}
 
@@ -277,6 +296,13 @@ func (uu *UserUpdaterHandler) UpdateAdmin(c *gin.Context) {
 		return
 	}
 
+	version, err := utils.ParseIfMatch(c.GetHeader("If-Match"))
+
+	if err != nil {
+		uu.abortUpdate(c, err, userID, userAdminRepresentation)
+		return
+	}
+
 	_, err = uu.userFinder.GetUserByID(c, userID)
 
 	if err != nil {
@@ -286,12 +312,17 @@ func (uu *UserUpdaterHandler) UpdateAdmin(c *gin.Context) {
 		return
 	}
 
-	imagePath, err := uu.cloudStorage.Upload(request.Photo, "users/admin/profile")
+	var photo *entity.UserPhoto
 
-	if err != nil {
-		c.JSON(errors.ErrInternalServerError.Code, response.ErrorAPIResponse(errors.ErrInternalServerError.Code, errors.ErrInternalServerError.Message))
-		c.Abort()
-		return
+	if request.Photo != nil {
+		photo, err = uu.photoStorage.Upload(c, request.Photo, "users/admin/profile")
+
+		if err != nil {
+			parseError := errors.ParseError(err)
+			c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+			c.Abort()
+			return
+		}
 	}
 
 	dob, err := utils.DateStringToTime(request.DOB)
@@ -309,10 +340,11 @@ func (uu *UserUpdaterHandler) UpdateAdmin(c *gin.Context) {
 		request.Email,
 		request.Name,
 		utils.TimeToNullTime(dob),
-		imagePath,
+		"",
 		request.PhoneNumber,
 		"system",
 	)
+	user.SetPhoto(photo)
 
 	roleID, err := uuid.Parse(request.RoleID)
 
@@ -322,13 +354,14 @@ func (uu *UserUpdaterHandler) UpdateAdmin(c *gin.Context) {
 		return
 	}
 
-	if err := uu.userUpdater.UpdateAdmin(c, user, roleID); err != nil {
-		parseError := errors.ParseError(err)
-		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
-		c.Abort()
+	user.Version = version
+
+	if err := uu.userUpdater.UpdateAdmin(c, user, roleID, middleware.UserID); err != nil {
+		uu.abortUpdate(c, err, userID, userAdminRepresentation)
 		return
 	}
 
+	c.Header("ETag", utils.ETag(user.Version))
 	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
 }
 
@@ -362,7 +395,7 @@ func (uu *UserUpdaterHandler) UpdateRole(c *gin.Context) {
 		}
 	}
 
-	if err := uu.userUpdater.UpdateRole(c, roleID, request.Name, permissionIDs); err != nil {
+	if err := uu.userUpdater.UpdateRole(c, roleID, request.Name, permissionIDs, middleware.UserID); err != nil {
 		parseError := errors.ParseError(err)
 		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
 		c.Abort()
@@ -397,3 +430,156 @@ func (uu *UserUpdaterHandler) UpdatePermission(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", nil))
 }
+
+// PatchUser is a handler for partially updating the profile of the user with a JSON merge patch
+func (uu *UserUpdaterHandler) PatchUser(c *gin.Context) {
+	version, err := utils.ParseIfMatch(c.GetHeader("If-Match"))
+
+	if err != nil {
+		uu.abortUpdate(c, err, middleware.UserID, userProfileRepresentation)
+		return
+	}
+
+	p, err := patch.Bind(c, resource.UserProfilePatchSchema)
+
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	user, err := uu.userUpdater.PatchUser(c, middleware.UserID, version, p)
+
+	if err != nil {
+		uu.abortUpdate(c, err, middleware.UserID, userProfileRepresentation)
+		return
+	}
+
+	c.Header("ETag", utils.ETag(user.Version))
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewUserProfile(user)))
+}
+
+// PatchAdmin is a handler for partially updating an admin with a JSON merge patch
+func (uu *UserUpdaterHandler) PatchAdmin(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	version, err := utils.ParseIfMatch(c.GetHeader("If-Match"))
+
+	if err != nil {
+		uu.abortUpdate(c, err, userID, userAdminRepresentation)
+		return
+	}
+
+	p, err := patch.Bind(c, resource.AdminPatchSchema)
+
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	user, err := uu.userUpdater.PatchAdmin(c, userID, version, p, middleware.UserID)
+
+	if err != nil {
+		uu.abortUpdate(c, err, userID, userAdminRepresentation)
+		return
+	}
+
+	c.Header("ETag", utils.ETag(user.Version))
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewUserAdmin(user)))
+}
+
+// PatchRole is a handler for partially updating a role with a JSON merge patch
+func (uu *UserUpdaterHandler) PatchRole(c *gin.Context) {
+	roleID, err := uuid.Parse(c.Param("id"))
+
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	p, err := patch.Bind(c, resource.RolePatchSchema)
+
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	role, err := uu.userUpdater.PatchRole(c, roleID, p, middleware.UserID)
+
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewRoleResponse(role)))
+}
+
+// PatchPermission is a handler for partially updating a permission with a JSON merge patch
+func (uu *UserUpdaterHandler) PatchPermission(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+
+	if err != nil {
+		c.JSON(errors.ErrInvalidArgument.Code, response.ErrorAPIResponse(errors.ErrInvalidArgument.Code, errors.ErrInvalidArgument.Message))
+		c.Abort()
+		return
+	}
+
+	p, err := patch.Bind(c, resource.PermissionPatchSchema)
+
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	permission, err := uu.userUpdater.PatchPermission(c, id, p)
+
+	if err != nil {
+		parseError := errors.ParseError(err)
+		c.JSON(parseError.Code, response.ErrorAPIResponse(parseError.Code, parseError.Message))
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessAPIResponseList(http.StatusOK, "success", resource.NewPermissionResponse(permission)))
+}
+
+// abortUpdate writes the error of a rejected update, a version mismatch also carries the current user and its ETag
+func (uu *UserUpdaterHandler) abortUpdate(c *gin.Context, err error, userID uuid.UUID, represent func(*entity.User) interface{}) {
+	parseError := errors.ParseError(err)
+
+	if utils.IsVersionMismatch(err) {
+		if current, findErr := uu.userFinder.GetUserByID(c, userID); findErr == nil {
+			c.Header("ETag", utils.ETag(current.Version))
+			c.JSON(parseError.Code, response.ErrorAPIResponseWithData(parseError.Code, parseError.Message, represent(current)))
+			c.Abort()
+			return
+		}
+	}
+
+	c.JSON(parseError.Code, response.ErrorAPIResponseWithField(parseError.Code, parseError.Message, parseError.Field))
+	c.Abort()
+}
+
+func userProfileRepresentation(user *entity.User) interface{} {
+	return resource.NewUserProfile(user)
+}
+
+func userAdminRepresentation(user *entity.User) interface{} {
+	return resource.NewUserAdmin(user)
+}
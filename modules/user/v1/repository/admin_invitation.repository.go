@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	commonCache "gin-starter/common/cache"
+	"gin-starter/common/interfaces"
+	"gin-starter/entity"
+)
+
+// AdminInvitationRepository is a repository for admin invitations
+type AdminInvitationRepository struct {
+	db    *gorm.DB
+	cache interfaces.Cacheable
+}
+
+// AdminInvitationRepositoryUseCase is a use case for admin invitations
+type AdminInvitationRepositoryUseCase interface {
+	// Create creates the pending admin along with its role and invitation
+	Create(ctx context.Context, user *entity.User, userRole *entity.UserRole, invitation *entity.AdminInvitation) error
+	// FindByID finds an invitation by id
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.AdminInvitation, error)
+	// FindPending finds the invitations which are neither accepted nor revoked, newest first
+	FindPending(ctx context.Context, limit, offset int) ([]*entity.AdminInvitation, int64, error)
+	// UpdateSent records a resend of the invitation with its rotated nonce and expiry
+	UpdateSent(ctx context.Context, invitation *entity.AdminInvitation) error
+	// Revoke revokes the invitation and permanently deletes its pending admin
+	Revoke(ctx context.Context, invitation *entity.AdminInvitation, revokedBy string) error
+	// Accept accepts the invitation and activates its pending admin with the password hash
+	Accept(ctx context.Context, invitation *entity.AdminInvitation, passwordHash string) error
+}
+
+// NewAdminInvitationRepository is a constructor for AdminInvitationRepository
+func NewAdminInvitationRepository(db *gorm.DB, cache interfaces.Cacheable) *AdminInvitationRepository {
+	return &AdminInvitationRepository{db, cache}
+}
+
+// Create creates the pending admin along with its role and invitation
+func (ar *AdminInvitationRepository) Create(ctx context.Context, user *entity.User, userRole *entity.UserRole, invitation *entity.AdminInvitation) error {
+	if err := ar.db.
+		WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&entity.User{}).Create(user).Error; err != nil {
+				return emailConflict(err)
+			}
+
+			if err := tx.Model(&entity.UserRole{}).Create(userRole).Error; err != nil {
+				return err
+			}
+
+			return tx.Model(&entity.AdminInvitation{}).Omit("Role").Create(invitation).Error
+		}); err != nil {
+		return errors.Wrap(err, "[AdminInvitationRepository-Create] error while creating invitation")
+	}
+
+	return ar.cache.BulkRemove(fmt.Sprintf(commonCache.UserRoleByUserID, "*"))
+}
+
+// FindByID finds an invitation by id
+func (ar *AdminInvitationRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.AdminInvitation, error) {
+	result := new(entity.AdminInvitation)
+
+	if err := ar.db.
+		WithContext(ctx).
+		Preload("Role").
+		Where("id = ?", id).
+		First(result).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "[AdminInvitationRepository-FindByID] error while getting invitation")
+	}
+
+	return result, nil
+}
+
+// FindPending finds the invitations which are neither accepted nor revoked, newest first
+func (ar *AdminInvitationRepository) FindPending(ctx context.Context, limit, offset int) ([]*entity.AdminInvitation, int64, error) {
+	var invitations []*entity.AdminInvitation
+	var total int64
+
+	gormDB := ar.db.
+		WithContext(ctx).
+		Model(&entity.AdminInvitation{}).
+		Where("status = ?", entity.AdminInvitationStatusPending)
+
+	if err := gormDB.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "[AdminInvitationRepository-FindPending] error while counting invitations")
+	}
+
+	if err := gormDB.
+		Preload("Role").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&invitations).
+		Error; err != nil {
+		return nil, 0, errors.Wrap(err, "[AdminInvitationRepository-FindPending] error while getting invitations")
+	}
+
+	return invitations, total, nil
+}
+
+// UpdateSent records a resend of the invitation with its rotated nonce and expiry
+func (ar *AdminInvitationRepository) UpdateSent(ctx context.Context, invitation *entity.AdminInvitation) error {
+	result := ar.db.
+		WithContext(ctx).
+		Model(&entity.AdminInvitation{}).
+		Where("id = ? AND status = ?", invitation.ID, entity.AdminInvitationStatusPending).
+		UpdateColumns(map[string]interface{}{
+			"nonce":        invitation.Nonce,
+			"expires_at":   invitation.ExpiresAt,
+			"sent_count":   invitation.SentCount,
+			"last_sent_at": invitation.LastSentAt,
+			"updated_by":   invitation.UpdatedBy,
+			"updated_at":   invitation.UpdatedAt,
+		})
+
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "[AdminInvitationRepository-UpdateSent] error while updating invitation")
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.Errorf("[AdminInvitationRepository-UpdateSent] invitation %s is no longer pending", invitation.ID)
+	}
+
+	return nil
+}
+
+// Revoke revokes the invitation and permanently deletes its pending admin
+func (ar *AdminInvitationRepository) Revoke(ctx context.Context, invitation *entity.AdminInvitation, revokedBy string) error {
+	now := time.Now()
+
+	if err := ar.db.
+		WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			result := tx.Model(&entity.AdminInvitation{}).
+				Where("id = ? AND status = ?", invitation.ID, entity.AdminInvitationStatusPending).
+				UpdateColumns(map[string]interface{}{
+					"status":     entity.AdminInvitationStatusRevoked,
+					"revoked_at": now,
+					"updated_by": revokedBy,
+					"updated_at": now,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected == 0 {
+				return errors.Errorf("invitation %s is no longer pending", invitation.ID)
+			}
+
+			if !invitation.UserID.Valid {
+				return nil
+			}
+
+			// the pending admin never signed in, nothing references it but its role
+			if err := tx.Unscoped().
+				Where("user_id = ?", invitation.UserID.UUID).
+				Delete(&entity.UserRole{}).
+				Error; err != nil {
+				return err
+			}
+
+			return tx.Unscoped().
+				Where("id = ? AND status = ?", invitation.UserID.UUID, entity.UserStatusPending).
+				Delete(&entity.User{}).
+				Error
+		}); err != nil {
+		return errors.Wrap(err, "[AdminInvitationRepository-Revoke] error while revoking invitation")
+	}
+
+	return ar.cache.BulkRemove(fmt.Sprintf(commonCache.UserRoleByUserID, "*"))
+}
+
+// Accept accepts the invitation and activates its pending admin with the password hash
+func (ar *AdminInvitationRepository) Accept(ctx context.Context, invitation *entity.AdminInvitation, passwordHash string) error {
+	now := time.Now()
+
+	if err := ar.db.
+		WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			// the nonce guards against accepting twice with the same link or with a link superseded by a resend
+			result := tx.Model(&entity.AdminInvitation{}).
+				Where("id = ? AND status = ? AND nonce = ?", invitation.ID, entity.AdminInvitationStatusPending, invitation.Nonce).
+				UpdateColumns(map[string]interface{}{
+					"status":      entity.AdminInvitationStatusAccepted,
+					"accepted_at": now,
+					"updated_at":  now,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected == 0 {
+				return errors.Errorf("invitation %s is no longer pending", invitation.ID)
+			}
+
+			// the pending admin may have been deleted since it was invited
+			result = tx.Model(&entity.User{}).
+				Where("id = ? AND status = ?", invitation.UserID.UUID, entity.UserStatusPending).
+				UpdateColumns(map[string]interface{}{
+					"password":   passwordHash,
+					"status":     entity.UserStatusActivated,
+					"version":    gorm.Expr("version + 1"),
+					"updated_at": now,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected == 0 {
+				return errors.Errorf("pending admin of invitation %s no longer exists", invitation.ID)
+			}
+
+			return nil
+		}); err != nil {
+		return errors.Wrap(err, "[AdminInvitationRepository-Accept] error while accepting invitation")
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gin-starter/entity"
+	"gin-starter/utils"
+)
+
+// EmailChangeRepository is a repository for email change requests
+type EmailChangeRepository struct {
+	db *gorm.DB
+}
+
+// EmailChangeRepositoryUseCase is a use case for email change requests
+type EmailChangeRepositoryUseCase interface {
+	// Create creates an email change, cancelling the pending changes of the user
+	Create(ctx context.Context, change *entity.EmailChange) error
+	// FindByConfirmTokenHash finds an email change by the hash of its confirmation token
+	FindByConfirmTokenHash(ctx context.Context, hash string) (*entity.EmailChange, error)
+	// FindByCancelTokenHash finds an email change by the hash of its cancellation token
+	FindByCancelTokenHash(ctx context.Context, hash string) (*entity.EmailChange, error)
+	// Confirm confirms a pending email change and replaces the email of its user
+	Confirm(ctx context.Context, change *entity.EmailChange) error
+	// Cancel cancels a pending email change
+	Cancel(ctx context.Context, change *entity.EmailChange) error
+}
+
+// NewEmailChangeRepository is a constructor for EmailChangeRepository
+func NewEmailChangeRepository(db *gorm.DB) *EmailChangeRepository {
+	return &EmailChangeRepository{db}
+}
+
+// Create creates an email change, cancelling the pending changes of the user
+func (er *EmailChangeRepository) Create(ctx context.Context, change *entity.EmailChange) error {
+	if err := er.db.
+		WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&entity.EmailChange{}).
+				Where("user_id = ? AND status = ?", change.UserID, entity.EmailChangeStatusPending).
+				UpdateColumns(map[string]interface{}{
+					"status":       entity.EmailChangeStatusCancelled,
+					"cancelled_at": change.CreatedAt,
+					"updated_by":   change.CreatedBy,
+					"updated_at":   change.CreatedAt,
+				}).Error; err != nil {
+				return err
+			}
+
+			return tx.Model(&entity.EmailChange{}).Create(change).Error
+		}); err != nil {
+		return errors.Wrap(err, "[EmailChangeRepository-Create] error while creating email change")
+	}
+
+	return nil
+}
+
+// FindByConfirmTokenHash finds an email change by the hash of its confirmation token
+func (er *EmailChangeRepository) FindByConfirmTokenHash(ctx context.Context, hash string) (*entity.EmailChange, error) {
+	return er.findBy(ctx, "confirm_token_hash", hash)
+}
+
+// FindByCancelTokenHash finds an email change by the hash of its cancellation token
+func (er *EmailChangeRepository) FindByCancelTokenHash(ctx context.Context, hash string) (*entity.EmailChange, error) {
+	return er.findBy(ctx, "cancel_token_hash", hash)
+}
+
+func (er *EmailChangeRepository) findBy(ctx context.Context, column, hash string) (*entity.EmailChange, error) {
+	result := new(entity.EmailChange)
+
+	if err := er.db.
+		WithContext(ctx).
+		Where(column+" = ?", hash).
+		First(result).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "[EmailChangeRepository-FindBy] error while getting email change")
+	}
+
+	return result, nil
+}
+
+// Confirm confirms a pending email change and replaces the email of its user
+func (er *EmailChangeRepository) Confirm(ctx context.Context, change *entity.EmailChange) error {
+	now := time.Now()
+
+	if err := er.db.
+		WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			result := tx.Model(&entity.EmailChange{}).
+				Where("id = ? AND status = ?", change.ID, entity.EmailChangeStatusPending).
+				UpdateColumns(map[string]interface{}{
+					"status":       entity.EmailChangeStatusConfirmed,
+					"confirmed_at": now,
+					"updated_at":   now,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected == 0 {
+				return errors.Errorf("email change %s is no longer pending", change.ID)
+			}
+
+			// a reset link mailed to the old address must not outlive the change
+			return emailConflict(tx.Model(&entity.User{}).
+				Where("id = ?", change.UserID).
+				UpdateColumns(map[string]interface{}{
+					"email":                 utils.NormalizeEmail(change.NewEmail),
+					"forgot_password_token": nil,
+					"version":               gorm.Expr("version + 1"),
+					"updated_by":            change.UserID.String(),
+					"updated_at":            now,
+				}).Error)
+		}); err != nil {
+		return errors.Wrap(err, "[EmailChangeRepository-Confirm] error while confirming email change")
+	}
+
+	return nil
+}
+
+// Cancel cancels a pending email change
+func (er *EmailChangeRepository) Cancel(ctx context.Context, change *entity.EmailChange) error {
+	now := time.Now()
+
+	result := er.db.
+		WithContext(ctx).
+		Model(&entity.EmailChange{}).
+		Where("id = ? AND status = ?", change.ID, entity.EmailChangeStatusPending).
+		UpdateColumns(map[string]interface{}{
+			"status":       entity.EmailChangeStatusCancelled,
+			"cancelled_at": now,
+			"updated_at":   now,
+		})
+
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "[EmailChangeRepository-Cancel] error while cancelling email change")
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.Errorf("[EmailChangeRepository-Cancel] email change %s is no longer pending", change.ID)
+	}
+
+	return nil
+}
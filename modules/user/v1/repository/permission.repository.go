@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	commonCache "gin-starter/common/cache"
@@ -33,8 +34,13 @@ type PermissionRepositoryUseCase interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.Permission, error)
 	// Update updates permission
 	Update(ctx context.Context, permission *entity.Permission) error
+	// Sync synchronises the permissions declared in code and returns the orphaned permissions
+	Sync(ctx context.Context, declared []*entity.Permission) ([]*entity.Permission, error)
 }
 
+// permissionSyncLock is the advisory lock serialising the synchronisation of instances booting together
+const permissionSyncLock = "main.permissions:sync"
+
 // NewPermissionRepository `NewPermissionRepository` is a function that returns a pointer to a `PermissionRepository` struct
 func NewPermissionRepository(db *gorm.DB, cache interfaces.Cacheable) *PermissionRepository {
 	return &PermissionRepository{db, cache}
@@ -150,3 +156,94 @@ func (pr *PermissionRepository) Update(ctx context.Context, permission *entity.P
 	}
 	return nil
 }
+
+// Sync creates the declared permissions missing from the table and updates the label and group of the others,
+// the id of every declared permission is set to its row. The permissions no module declares anymore,
+// including the ones created through the CMS, are flagged as orphaned and returned.
+func (pr *PermissionRepository) Sync(ctx context.Context, declared []*entity.Permission) ([]*entity.Permission, error) {
+	orphaned := make([]*entity.Permission, 0)
+	now := time.Now()
+
+	if err := pr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", permissionSyncLock).Error; err != nil {
+			return errors.Wrap(err, "[PermissionRepository-Sync] error while locking permissions")
+		}
+
+		existing := make([]*entity.Permission, 0)
+		if err := tx.Model(&entity.Permission{}).
+			Order("created_at").
+			Find(&existing).
+			Error; err != nil {
+			return errors.Wrap(err, "[PermissionRepository-Sync] error while getting permissions")
+		}
+
+		byName := make(map[string]*entity.Permission, len(existing))
+		for _, p := range existing {
+			if _, ok := byName[p.Name]; !ok {
+				byName[p.Name] = p
+			}
+		}
+
+		names := make(map[string]bool, len(declared))
+		for _, p := range declared {
+			names[p.Name] = true
+
+			current, ok := byName[p.Name]
+			if !ok {
+				if err := tx.Model(&entity.Permission{}).Create(p).Error; err != nil {
+					return errors.Wrap(err, "[PermissionRepository-Sync] error while creating permission")
+				}
+
+				continue
+			}
+
+			p.ID = current.ID
+
+			if current.Label == p.Label && current.Group == p.Group && !current.OrphanedAt.Valid {
+				continue
+			}
+
+			if err := tx.Model(&entity.Permission{}).
+				Where("id = ?", current.ID).
+				UpdateColumns(map[string]interface{}{
+					"label":       p.Label,
+					"group_name":  p.Group,
+					"orphaned_at": nil,
+					"updated_by":  p.UpdatedBy,
+					"updated_at":  now,
+				}).
+				Error; err != nil {
+				return errors.Wrap(err, "[PermissionRepository-Sync] error while updating permission")
+			}
+		}
+
+		for _, p := range existing {
+			if names[p.Name] {
+				continue
+			}
+
+			if !p.OrphanedAt.Valid {
+				p.OrphanedAt = sql.NullTime{Time: now, Valid: true}
+
+				if err := tx.Model(&entity.Permission{}).
+					Where("id = ?", p.ID).
+					UpdateColumn("orphaned_at", now).
+					Error; err != nil {
+					return errors.Wrap(err, "[PermissionRepository-Sync] error while flagging orphaned permission")
+				}
+			}
+
+			orphaned = append(orphaned, p)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := pr.cache.BulkRemove(fmt.Sprintf(commonCache.PermissionFindByName, "*")); err != nil {
+		return nil, err
+	}
+
+	return orphaned, nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gin-starter/entity"
+)
+
+// PhoneVerificationRepository is a repository for phone verification codes
+type PhoneVerificationRepository struct {
+	db *gorm.DB
+}
+
+// PhoneVerificationRepositoryUseCase is a use case for phone verification codes
+type PhoneVerificationRepositoryUseCase interface {
+	// Create creates a phone verification
+	Create(ctx context.Context, verification *entity.PhoneVerification) error
+	// FindLatest finds the latest phone verification of a user
+	FindLatest(ctx context.Context, userID uuid.UUID) (*entity.PhoneVerification, error)
+	// IncrementAttempts records a wrong code entered for the verification
+	IncrementAttempts(ctx context.Context, verification *entity.PhoneVerification) error
+	// Verify marks the verification as used and the phone number of its user as verified
+	Verify(ctx context.Context, verification *entity.PhoneVerification) error
+}
+
+// NewPhoneVerificationRepository is a constructor for PhoneVerificationRepository
+func NewPhoneVerificationRepository(db *gorm.DB) *PhoneVerificationRepository {
+	return &PhoneVerificationRepository{db}
+}
+
+// Create creates a phone verification
+func (pr *PhoneVerificationRepository) Create(ctx context.Context, verification *entity.PhoneVerification) error {
+	if err := pr.db.
+		WithContext(ctx).
+		Model(&entity.PhoneVerification{}).
+		Create(verification).
+		Error; err != nil {
+		return errors.Wrap(err, "[PhoneVerificationRepository-Create] error while creating phone verification")
+	}
+
+	return nil
+}
+
+// FindLatest finds the latest phone verification of a user, a newer code supersedes the previous ones
+func (pr *PhoneVerificationRepository) FindLatest(ctx context.Context, userID uuid.UUID) (*entity.PhoneVerification, error) {
+	result := new(entity.PhoneVerification)
+
+	if err := pr.db.
+		WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		First(result).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "[PhoneVerificationRepository-FindLatest] error while getting phone verification")
+	}
+
+	return result, nil
+}
+
+// IncrementAttempts records a wrong code entered for the verification
+func (pr *PhoneVerificationRepository) IncrementAttempts(ctx context.Context, verification *entity.PhoneVerification) error {
+	if err := pr.db.
+		WithContext(ctx).
+		Model(&entity.PhoneVerification{}).
+		Where("id = ?", verification.ID).
+		UpdateColumns(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		return errors.Wrap(err, "[PhoneVerificationRepository-IncrementAttempts] error while updating phone verification")
+	}
+
+	return nil
+}
+
+// Verify marks the verification as used and the phone number of its user as verified
+func (pr *PhoneVerificationRepository) Verify(ctx context.Context, verification *entity.PhoneVerification) error {
+	now := time.Now()
+
+	if err := pr.db.
+		WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			// the attempts are checked again so concurrent guesses cannot exceed the limit
+			result := tx.Model(&entity.PhoneVerification{}).
+				Where("id = ? AND verified_at IS NULL AND attempts < ?", verification.ID, entity.PhoneVerificationMaxAttempts).
+				UpdateColumns(map[string]interface{}{
+					"verified_at": now,
+					"updated_at":  now,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected == 0 {
+				return errors.Errorf("phone verification %s is no longer usable", verification.ID)
+			}
+
+			return tx.Model(&entity.User{}).
+				Where("id = ?", verification.UserID).
+				UpdateColumns(map[string]interface{}{
+					"phone_number":      verification.PhoneNumber,
+					"phone_verified_at": now,
+					"version":           gorm.Expr("version + 1"),
+					"updated_by":        verification.UserID.String(),
+					"updated_at":        now,
+				}).Error
+		}); err != nil {
+		return errors.Wrap(err, "[PhoneVerificationRepository-Verify] error while verifying phone number")
+	}
+
+	return nil
+}
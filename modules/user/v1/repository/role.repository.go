@@ -6,6 +6,7 @@ import (
 	"fmt"
 	commonCache "gin-starter/common/cache"
 	"gin-starter/common/interfaces"
+	"gin-starter/common/query"
 	"gin-starter/entity"
 	"time"
 
@@ -15,6 +16,17 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// RoleListSchema declares the fields FindAll can be filtered, sorted and searched on
+var RoleListSchema = &query.Schema{
+	Fields: map[string]string{
+		"name":       "name",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	},
+	Search:      []string{"name"},
+	DefaultSort: "created_at",
+}
+
 // RoleRepository is a repository for role
 type RoleRepository struct {
 	db    *gorm.DB
@@ -26,7 +38,7 @@ type RoleRepositoryUseCase interface {
 	// Create creates a role
 	Create(ctx context.Context, role *entity.Role, permissionIDs []uuid.UUID) error
 	// FindAll finds all roles
-	FindAll(ctx context.Context, query, sort, order string, limit, offset int) ([]*entity.Role, error)
+	FindAll(ctx context.Context, q *query.Query) ([]*entity.Role, error)
 	// FindByID finds a role by id
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.Role, error)
 	// Delete deletes a role
@@ -35,6 +47,16 @@ type RoleRepositoryUseCase interface {
 	FindByName(ctx context.Context, slug string) (*entity.Role, error)
 	// Update update a role
 	Update(ctx context.Context, role *entity.Role, rolePermissions []*entity.RolePermission) error
+	// FindDeleted finds the deleted roles
+	FindDeleted(ctx context.Context, limit, offset int) ([]*entity.Role, int64, error)
+	// FindDeletedByID finds a deleted role by id
+	FindDeletedByID(ctx context.Context, id uuid.UUID) (*entity.Role, error)
+	// Restore restores a deleted role with the permissions it had when it was deleted
+	Restore(ctx context.Context, role *entity.Role, restoredBy string) error
+	// Purge permanently deletes the roles deleted before the given time
+	Purge(ctx context.Context, before time.Time) (int64, error)
+	// SyncDefault creates a default role missing from the table and grants it the permissions it lacks
+	SyncDefault(ctx context.Context, role *entity.Role, permissionIDs []uuid.UUID) error
 }
 
 // NewRoleRepository creates a new role repository
@@ -116,64 +138,50 @@ func (nc *RoleRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.R
 	return role, nil
 }
 
-// FindAll finds all roles
-func (nc *RoleRepository) FindAll(ctx context.Context, query, sort, order string, limit, offset int) ([]*entity.Role, error) {
+// FindAll finds all roles, a nil query finds every role
+func (nc *RoleRepository) FindAll(ctx context.Context, q *query.Query) ([]*entity.Role, error) {
 	role := make([]*entity.Role, 0)
-	var gormDB = nc.db.
+
+	if err := nc.db.
 		WithContext(ctx).
 		Model(&entity.Role{}).
 		Preload("RolePermissions").
-		Preload("RolePermissions.Permission")
-
-	if query != "" {
-		gormDB.Where("name ILIKE ?", "%"+query+"%")
-	}
-
-	if sort != "" {
-		gormDB.Order(fmt.Sprintf("%s %s", sort, order))
-	}
-
-	if limit > 0 {
-		gormDB.Limit(limit)
-	}
-
-	if offset > 0 {
-		gormDB.Offset(offset)
-	}
-
-	if err := gormDB.
+		Preload("RolePermissions.Permission").
+		Scopes(q.Filter(RoleListSchema), q.Order(RoleListSchema), q.Paginate(RoleListSchema)).
 		Find(&role).
 		Error; err != nil {
-		return nil, errors.Wrap(err, "[RoleRepository-GetNewsCategories] error while getting news category")
+		return nil, errors.Wrap(err, "[RoleRepository-FindAll] error while getting roles")
 	}
 
 	return role, nil
 }
 
-// Delete deletes a role
+// Delete deletes a role.
+// The role permissions share the deletion time of the role so that Restore can bring them back together.
 func (nc *RoleRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy string) error {
+	now := time.Now()
 	if err := nc.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// soft delete role
-		if err := nc.db.WithContext(ctx).
+		if err := tx.
 			Model(&entity.Role{}).
 			Where(`id = ?`, id).
 			Updates(
 				map[string]interface{}{
 					"deleted_by": deletedBy,
-					"updated_at": time.Now(),
-					"deleted_at": time.Now(),
+					"updated_at": now,
+					"deleted_at": now,
 				}).Error; err != nil {
 			return errors.Wrap(err, "[RoleRepository-DeactivateRole] error when updating role data")
 		}
 
-		if err := nc.db.WithContext(ctx).
+		if err := tx.
 			Model(&entity.RolePermission{}).
 			Where(`role_id = ?`, id).
 			Updates(
 				map[string]interface{}{
 					"deleted_by": deletedBy,
-					"updated_at": time.Now(),
-					"deleted_at": time.Now(),
+					"updated_at": now,
+					"deleted_at": now,
 				}).Error; err != nil {
 			return errors.Wrap(err, "[RoleRepository-DeactivateRole] error when updating role data")
 		}
@@ -183,15 +191,234 @@ func (nc *RoleRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy st
 		return errors.Wrap(err, "[RoleRepository-Delete] error while deleting role")
 	}
 
-	if err := nc.cache.BulkRemove(fmt.Sprintf(commonCache.RolePermissionFindByRoleIDAndPermissionID, "*", "*")); err != nil {
+	return nc.removeCache()
+}
+
+// FindDeleted finds the deleted roles
+func (nc *RoleRepository) FindDeleted(ctx context.Context, limit, offset int) ([]*entity.Role, int64, error) {
+	roles := make([]*entity.Role, 0)
+	var total int64
+	var gormDB = nc.db.
+		WithContext(ctx).
+		Unscoped().
+		Model(&entity.Role{}).
+		Where("deleted_at IS NOT NULL")
+
+	if err := gormDB.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "[RoleRepository-FindDeleted] error while counting deleted roles")
+	}
+
+	if limit > 0 {
+		gormDB = gormDB.Limit(limit)
+	}
+
+	if offset > 0 {
+		gormDB = gormDB.Offset(offset)
+	}
+
+	if err := gormDB.
+		Order("deleted_at desc").
+		Find(&roles).
+		Error; err != nil {
+		return nil, 0, errors.Wrap(err, "[RoleRepository-FindDeleted] error while getting deleted roles")
+	}
+
+	return roles, total, nil
+}
+
+// FindDeletedByID finds a deleted role by id
+func (nc *RoleRepository) FindDeletedByID(ctx context.Context, id uuid.UUID) (*entity.Role, error) {
+	role := &entity.Role{}
+
+	if err := nc.db.
+		WithContext(ctx).
+		Unscoped().
+		Where("id = ?", id).
+		Where("deleted_at IS NOT NULL").
+		First(role).
+		Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "[RoleRepository-FindDeletedByID] error while getting deleted role")
+	}
+
+	return role, nil
+}
+
+// Restore restores a deleted role with the permissions it had when it was deleted
+func (nc *RoleRepository) Restore(ctx context.Context, role *entity.Role, restoredBy string) error {
+	now := time.Now()
+	if err := nc.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().
+			Model(&entity.RolePermission{}).
+			Where("role_id = ?", role.ID).
+			Where("deleted_at = ?", role.DeletedAt.Time).
+			Updates(
+				map[string]interface{}{
+					"deleted_by": nil,
+					"deleted_at": nil,
+					"updated_by": restoredBy,
+					"updated_at": now,
+				}).Error; err != nil {
+			return errors.Wrap(err, "[RoleRepository-Restore] error when restoring role permissions")
+		}
+
+		if err := tx.Unscoped().
+			Model(&entity.Role{}).
+			Where("id = ?", role.ID).
+			Updates(
+				map[string]interface{}{
+					"deleted_by": nil,
+					"deleted_at": nil,
+					"updated_by": restoredBy,
+					"updated_at": now,
+				}).Error; err != nil {
+			return errors.Wrap(err, "[RoleRepository-Restore] error when restoring role")
+		}
+
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "[RoleRepository-Restore] error while restoring role")
+	}
+
+	return nc.removeCache()
+}
+
+// Purge permanently deletes the roles deleted before the given time, along with their permissions and user roles
+func (nc *RoleRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+
+	if err := nc.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		expired := tx.Unscoped().
+			Model(&entity.Role{}).
+			Select("id").
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before)
+
+		if err := tx.Unscoped().
+			Where("role_id IN (?)", expired).
+			Delete(&entity.RolePermission{}).
+			Error; err != nil {
+			return errors.Wrap(err, "[RoleRepository-Purge] error when deleting role permissions")
+		}
+
+		if err := tx.Unscoped().
+			Where("role_id IN (?)", expired).
+			Delete(&entity.UserRole{}).
+			Error; err != nil {
+			return errors.Wrap(err, "[RoleRepository-Purge] error when deleting user roles")
+		}
+
+		result := tx.Unscoped().
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Delete(&entity.Role{})
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "[RoleRepository-Purge] error when deleting roles")
+		}
+
+		purged = result.RowsAffected
+
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	if purged == 0 {
+		return 0, nil
+	}
+
+	if err := nc.cache.BulkRemove(fmt.Sprintf(commonCache.UserRoleByUserID, "*")); err != nil {
+		return purged, err
+	}
+
+	return purged, nc.removeCache()
+}
+
+// SyncDefault creates the default role when no role has its name and grants it the permissions it lacks.
+// The system flag of the role follows the declaration and the permissions of a system role are its core permissions.
+// Permissions granted through the CMS are kept, the id of the role is set to its row.
+func (nc *RoleRepository) SyncDefault(ctx context.Context, role *entity.Role, permissionIDs []uuid.UUID) error {
+	if err := nc.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		current := &entity.Role{}
+		err := tx.Model(&entity.Role{}).
+			Preload("RolePermissions").
+			Where("name = ?", role.Name).
+			Order("created_at").
+			First(current).
+			Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return errors.Wrap(err, "[RoleRepository-SyncDefault] error while getting role")
+		}
+
+		if err == gorm.ErrRecordNotFound {
+			if err := tx.Model(&entity.Role{}).Create(role).Error; err != nil {
+				return errors.Wrap(err, "[RoleRepository-SyncDefault] error while creating role")
+			}
+		} else {
+			role.ID = current.ID
+
+			if current.System != role.System {
+				if err := tx.Model(&entity.Role{}).
+					Where("id = ?", role.ID).
+					UpdateColumns(map[string]interface{}{
+						"system":     role.System,
+						"updated_at": time.Now(),
+					}).
+					Error; err != nil {
+					return errors.Wrap(err, "[RoleRepository-SyncDefault] error while updating role")
+				}
+			}
+		}
+
+		declared := make(map[uuid.UUID]bool, len(permissionIDs))
+		for _, pid := range permissionIDs {
+			declared[pid] = true
+		}
+
+		granted := make(map[uuid.UUID]bool, len(current.RolePermissions))
+		for _, rp := range current.RolePermissions {
+			granted[rp.PermissionID] = true
+
+			core := role.System && declared[rp.PermissionID]
+			if rp.Core == core {
+				continue
+			}
+
+			if err := tx.Model(&entity.RolePermission{}).
+				Where("id = ?", rp.ID).
+				UpdateColumn("core", core).
+				Error; err != nil {
+				return errors.Wrap(err, "[RoleRepository-SyncDefault] error while updating role permission")
+			}
+		}
+
+		for _, pid := range permissionIDs {
+			if granted[pid] {
+				continue
+			}
+
+			rolePermission := entity.NewRolePermission(uuid.New(), role.ID, pid, role.CreatedBy.String)
+			rolePermission.Core = role.System
+			if err := tx.Model(&entity.RolePermission{}).Create(rolePermission).Error; err != nil {
+				return errors.Wrap(err, "[RoleRepository-SyncDefault] error while creating role permission")
+			}
+		}
+
+		return nil
+	}); err != nil {
 		return err
 	}
 
-	if err := nc.cache.BulkRemove(fmt.Sprintf(commonCache.RoleFindByID, "*")); err != nil {
+	return nc.removeCache()
+}
+
+// removeCache removes the cached roles and role permissions
+func (nc *RoleRepository) removeCache() error {
+	if err := nc.cache.BulkRemove(fmt.Sprintf(commonCache.RolePermissionFindByRoleIDAndPermissionID, "*", "*")); err != nil {
 		return err
 	}
 
-	return nil
+	return nc.cache.BulkRemove(fmt.Sprintf(commonCache.RoleFindByID, "*"))
 }
 
 // FindByName finds a role by name
@@ -253,6 +480,7 @@ func (nc *RoleRepository) Update(ctx context.Context, role *entity.Role, rolePer
 		return nil
 	}); err != nil {
 		role.UpdatedAt = oldTime
+		return err
 	}
 
 	if err := nc.cache.BulkRemove(fmt.Sprintf(commonCache.RolePermissionFindByRoleIDAndPermissionID, "*", "*")); err != nil {
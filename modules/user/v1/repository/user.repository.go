@@ -3,9 +3,12 @@ package repository
 import (
 	"context"
 	"fmt"
-	"gin-starter/common/constant"
+	"gin-starter/common/patch"
+	"gin-starter/common/query"
 	"gin-starter/entity"
+	"gin-starter/utils"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,6 +17,12 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// ErrVersionConflict is returned when an update is based on a version of the user which has been changed since
+var ErrVersionConflict = patch.ErrVersionConflict
+
+// ErrEmailConflict is returned when a write would give a user the email of another user which is not deleted
+var ErrEmailConflict = errors.New("email conflict")
+
 // UserRepository is a repository for user
 type UserRepository struct {
 	db *gorm.DB
@@ -27,7 +36,8 @@ type UserRepositoryUseCase interface {
 	GetUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
 	// GetUserByForgotPasswordToken is a function to get user by forgot password token
 	GetUserByForgotPasswordToken(ctx context.Context, token string) (*entity.User, error)
-	// Update is a function to update user
+	// Update is a function to update user, rejected with ErrVersionConflict when user.Version is not the stored version
+	// and with ErrLastSuperAdmin when it deactivates the last active super admin
 	Update(ctx context.Context, user *entity.User) error
 	// ChangePassword is a function to change password
 	ChangePassword(ctx context.Context, user *entity.User, newPassword string) error
@@ -36,15 +46,93 @@ type UserRepositoryUseCase interface {
 	// CreateUser is a function to create user
 	CreateUser(ctx context.Context, user *entity.User) error
 	// GetUsers is a function to get users
-	GetUsers(ctx context.Context, query, sort, order string, limit, offset int) ([]*entity.User, int64, error)
+	GetUsers(ctx context.Context, q *query.Query) ([]*entity.User, *query.Page, error)
 	// GetAdminUsers is a function to get admin users
-	GetAdminUsers(ctx context.Context, query, sort, order string, limit, offset int) ([]*entity.User, int64, error)
-	// UpdateUser is a function to update user
+	GetAdminUsers(ctx context.Context, q *query.Query) ([]*entity.User, *query.Page, error)
+	// UpdateUser is a function to update user, rejected with ErrVersionConflict when user.Version is not the stored version
 	UpdateUser(ctx context.Context, user *entity.User) error
-	// UpdateUserStatus is a function to update user status
-	UpdateUserStatus(ctx context.Context, id uuid.UUID, status string) error
-	// DeleteAdmin is a function to delete admin user
-	DeleteAdmin(ctx context.Context, id uuid.UUID) error
+	// Patch is a function to update columns of a user, rejected with ErrVersionConflict when version is not the stored version
+	Patch(ctx context.Context, id uuid.UUID, version int64, columns map[string]interface{}) error
+	// UpdateUserStatus is a function to update user status, rejected with ErrVersionConflict when version is not the stored version
+	// and with ErrLastSuperAdmin when it deactivates the last active super admin
+	UpdateUserStatus(ctx context.Context, id uuid.UUID, status string, version int64) error
+	// UpdateOTPChannel is a function to update the channel of the login code of a user
+	UpdateOTPChannel(ctx context.Context, id uuid.UUID, channel string) error
+	// DeleteAdmin is a function to delete admin user, rejected with ErrLastSuperAdmin when it is the last active super admin
+	DeleteAdmin(ctx context.Context, id uuid.UUID, deletedBy string) error
+	// FindUsers is a function to find users matching all the given conditions
+	FindUsers(ctx context.Context, conditions []*UserCondition, limit, offset int) ([]*entity.User, int64, error)
+	// GetUsersByEmails is a function to get users, including deleted ones, owning any of the given emails, ignoring case
+	GetUsersByEmails(ctx context.Context, emails []string) ([]*entity.User, error)
+	// ExportUsers is a function to stream the users or admins matching the filter row by row
+	ExportUsers(ctx context.Context, admin bool, q *query.Query, fn func(*entity.UserExport) error) error
+	// IsEmailReserved is a function to check whether a deleted user still owns the email
+	IsEmailReserved(ctx context.Context, email string) (bool, error)
+	// IsPhotoInUse is a function to check whether a user, including deleted ones, still references the photo
+	IsPhotoInUse(ctx context.Context, photo string) (bool, error)
+	// GetDeletedUsers is a function to get the deleted users
+	GetDeletedUsers(ctx context.Context, limit, offset int) ([]*entity.User, int64, error)
+	// GetDeletedUserByID is a function to get a deleted user by id
+	GetDeletedUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
+	// Restore is a function to restore a deleted user
+	Restore(ctx context.Context, id uuid.UUID, restoredBy string) error
+	// Purge is a function to permanently delete the users deleted before the given time
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// UserCondition is a single condition applied by FindUsers
+type UserCondition struct {
+	// Column is the user column being compared, it must be one of filterableUserColumns
+	Column string
+	// Operator is one of eq, ne, co, sw, ew, gt, ge, lt, le and pr
+	Operator string
+	// Value is the compared value, it is ignored by the pr operator
+	Value interface{}
+}
+
+// filterableUserColumns lists the columns FindUsers accepts
+var filterableUserColumns = map[string]bool{
+	"id":           true,
+	"name":         true,
+	"email":        true,
+	"phone_number": true,
+	"status":       true,
+	"created_at":   true,
+	"updated_at":   true,
+}
+
+// UserListSchema declares the fields GetUsers can be filtered, sorted and searched on
+var UserListSchema = &query.Schema{
+	Fields: map[string]string{
+		"name":         "main.users.name",
+		"email":        "main.users.email",
+		"phone_number": "main.users.phone_number",
+		"status":       "main.users.status",
+		"dob":          "main.users.dob",
+		"created_at":   "main.users.created_at",
+		"updated_at":   "main.users.updated_at",
+	},
+	Search:      []string{"main.users.name", "main.users.email", "main.users.phone_number"},
+	DefaultSort: "-created_at",
+	Keyset:      &query.Keyset{CreatedAt: "main.users.created_at", ID: "main.users.id"},
+	Table:       "main.users",
+}
+
+// AdminListSchema declares the fields GetAdminUsers and ExportUsers can be filtered, sorted and searched on
+var AdminListSchema = &query.Schema{
+	Fields: map[string]string{
+		"name":         "main.users.name",
+		"email":        "main.users.email",
+		"phone_number": "main.users.phone_number",
+		"status":       "main.users.status",
+		"dob":          "main.users.dob",
+		"role_id":      "main.user_roles.role_id",
+		"created_at":   "main.users.created_at",
+		"updated_at":   "main.users.updated_at",
+	},
+	Search:      []string{"main.users.name", "main.users.email", "main.users.phone_number"},
+	DefaultSort: "-created_at",
+	Keyset:      &query.Keyset{CreatedAt: "main.users.created_at", ID: "main.users.id"},
 }
 
 // NewUserRepository creates a new UserRepository
@@ -60,10 +148,8 @@ func (ur *UserRepository) GetUserByEmail(ctx context.Context, email string) (*en
 		WithContext(ctx).
 		Preload("UserRole").
 		Preload("UserRole.Role").
-		Preload("Employee").
-		Preload("Employee.CustomerBranch").
-		Where("email = ?", email).
-		Find(result).
+		Where("LOWER(email) = ?", utils.NormalizeEmail(email)).
+		First(result).
 		Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -82,12 +168,6 @@ func (ur *UserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*entit
 		WithContext(ctx).
 		Preload("UserRole").
 		Preload("UserRole.Role").
-		Preload("Employee").
-		Preload("Employee.CustomerBranch").
-		Preload("Employee.CustomerBranch.Province").
-		Preload("Employee.CustomerBranch.Regency").
-		Preload("Employee.CustomerBranch.District").
-		Preload("Employee.CustomerBranch.Village").
 		Where("id = ?", id).
 		First(result).
 		Error; err != nil {
@@ -108,7 +188,6 @@ func (ur *UserRepository) GetUserByForgotPasswordToken(ctx context.Context, toke
 		WithContext(ctx).
 		Preload("UserRole").
 		Preload("UserRole.Role").
-		Preload("CustomerBranch").
 		Where("forgot_password_token = ?", token).
 		First(result).
 		Error; err != nil {
@@ -136,28 +215,53 @@ func (ur *UserRepository) UpdateOTP(ctx context.Context, user *entity.User, otp
 	return nil
 }
 
-// Update is a function to update user
+// Update is a function to update user, rejected with ErrVersionConflict when user.Version is not the stored version
+// and with ErrLastSuperAdmin when it deactivates the last active super admin
 func (ur *UserRepository) Update(ctx context.Context, user *entity.User) error {
+	if err := updateVersioned(ur.db.WithContext(ctx), user); err != nil {
+		return errors.Wrap(emailConflict(err), "[UserRepository-Update] error when updating user data")
+	}
+
+	return nil
+}
+
+// updateVersioned applies the changed columns of the user under a row lock when its version is still the stored one,
+// then moves the user to the next version. db may be a transaction the update joins.
+func updateVersioned(db *gorm.DB, user *entity.User) error {
 	oldTime := user.UpdatedAt
 	user.UpdatedAt = time.Now()
-	if err := ur.db.
-		WithContext(ctx).
+
+	if err := db.
 		Transaction(func(tx *gorm.DB) error {
+			// the super admins are locked before the user, like every other write removing one does
+			if user.Status != "" && user.Status != entity.UserStatusActivated {
+				if err := KeepSuperAdmin(tx, user.ID, uuid.Nil); err != nil {
+					return err
+				}
+			}
+
 			sourceModel := new(entity.User)
-			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Find(&sourceModel, user.ID).Error; err != nil {
-				log.Println("[GamPTKRepository - Update]", err)
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", user.ID).First(sourceModel).Error; err != nil {
 				return err
 			}
-			if err := tx.Model(&entity.User{}).
-				Where(`id`, user.ID).
-				UpdateColumns(sourceModel.MapUpdateFrom(user)).Error; err != nil {
-				log.Println("[GamPTKRepository - Update]", err)
-				return err
+
+			if sourceModel.Version != user.Version {
+				return ErrVersionConflict
 			}
-			return nil
+
+			updates := sourceModel.MapUpdateFrom(user)
+			(*updates)["version"] = sourceModel.Version + 1
+
+			return tx.Model(&entity.User{}).
+				Where("id = ?", user.ID).
+				UpdateColumns(updates).Error
 		}); err != nil {
 		user.UpdatedAt = oldTime
+		return err
 	}
+
+	user.Version++
+
 	return nil
 }
 
@@ -184,147 +288,480 @@ func (ur *UserRepository) CreateUser(ctx context.Context, user *entity.User) err
 		Model(&entity.User{}).
 		Create(user).
 		Error; err != nil {
-		return errors.Wrap(err, "[UserRepository-CreateUser] error while creating user")
+		return errors.Wrap(emailConflict(err), "[UserRepository-CreateUser] error while creating user")
 	}
 
 	return nil
 }
 
 // GetUsers is a function to get all users
-func (ur *UserRepository) GetUsers(ctx context.Context, query, sort, order string, limit, offset int) ([]*entity.User, int64, error) {
+func (ur *UserRepository) GetUsers(ctx context.Context, q *query.Query) ([]*entity.User, *query.Page, error) {
 	var user []*entity.User
-	var total int64
 	var gormDB = ur.db.
 		WithContext(ctx).
 		Model(&entity.User{}).
 		Joins("left join main.user_roles on users.id=user_roles.user_id").
-		Where("main.user_roles.user_id is null")
+		Where("main.user_roles.user_id is null").
+		Scopes(q.Filter(UserListSchema))
 
-	gormDB.Count(&total)
-
-	gormDB = gormDB.Limit(limit).
-		Offset(offset)
-
-	if query != "" {
-		gormDB = gormDB.
-			Where("name ILIKE ?", "%"+query+"%").
-			Or("email ILIKE ?", "%"+query+"%").
-			Or("phone_number ILIKE ?", "%"+query+"%")
-	}
-
-	if order != constant.Ascending && order != constant.Descending {
-		order = constant.Descending
+	total, estimated, err := q.Count(gormDB, UserListSchema)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "[UserRepository-GetUsers] error when counting users")
 	}
 
-	if sort == "" {
-		sort = "created_at"
+	if err := gormDB.
+		Scopes(q.Order(UserListSchema), q.Paginate(UserListSchema)).
+		Find(&user).
+		Error; err != nil {
+		return nil, nil, errors.Wrap(err, "[UserRepository-GetUsers] error when looking up all user")
 	}
 
-	gormDB = gormDB.Order(fmt.Sprintf("%s %s", sort, order))
-
-	if err := gormDB.Find(&user).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, 0, nil
-		}
-		return nil, 0, errors.Wrap(err, "[UserRepository-GetAdminUsers] error when looking up all user")
-	}
+	page := q.Page(&user, total, estimated, func(i int) (time.Time, string) {
+		return user[i].CreatedAt, user[i].ID.String()
+	})
 
-	return user, total, nil
+	return user, page, nil
 }
 
 // GetAdminUsers is a function to get all admin users
-func (ur *UserRepository) GetAdminUsers(ctx context.Context, query, sort, order string, limit, offset int) ([]*entity.User, int64, error) {
+func (ur *UserRepository) GetAdminUsers(ctx context.Context, q *query.Query) ([]*entity.User, *query.Page, error) {
 	var user []*entity.User
-	var total int64
 	var gormDB = ur.db.
 		WithContext(ctx).
 		Model(&entity.User{}).
+		Joins("inner join main.user_roles on main.users.id=main.user_roles.user_id").
+		Scopes(q.Filter(AdminListSchema))
+
+	total, estimated, err := q.Count(gormDB, AdminListSchema)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "[UserRepository-GetAdminUsers] error when counting users")
+	}
+
+	if err := gormDB.
 		Preload("UserRole").
 		Preload("UserRole.Role.RolePermissions").
 		Preload("UserRole.Role.RolePermissions.Permission").
-		Joins("inner join main.user_roles on main.users.id=main.user_roles.user_id")
-
-	gormDB.Count(&total)
-
-	gormDB = gormDB.Limit(limit).
-		Offset(offset)
-
-	if query != "" {
-		gormDB = gormDB.
-			Where("name ILIKE ?", "%"+query+"%").
-			Or("email ILIKE ?", "%"+query+"%").
-			Or("phone_number ILIKE ?", "%"+query+"%")
+		Scopes(q.Order(AdminListSchema), q.Paginate(AdminListSchema)).
+		Find(&user).
+		Error; err != nil {
+		return nil, nil, errors.Wrap(err, "[UserRepository-GetAdminUsers] error when looking up all user")
 	}
 
-	if order != constant.Ascending && order != constant.Descending {
-		order = constant.Descending
-	}
+	page := q.Page(&user, total, estimated, func(i int) (time.Time, string) {
+		return user[i].CreatedAt, user[i].ID.String()
+	})
 
-	if sort == "" {
-		sort = "created_at"
+	return user, page, nil
+}
+
+// UpdateUser is a function to update user, rejected with ErrVersionConflict when user.Version is not the stored version
+func (ur *UserRepository) UpdateUser(ctx context.Context, user *entity.User) error {
+	if err := updateVersioned(ur.db.WithContext(ctx), user); err != nil {
+		return errors.Wrap(emailConflict(err), "[UserRepository-UpdateUser] error when updating user data")
 	}
 
-	gormDB = gormDB.Order(fmt.Sprintf("%s %s", sort, order))
+	return nil
+}
 
-	if err := gormDB.Find(&user).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, 0, nil
-		}
-		return nil, 0, errors.Wrap(err, "[UserRepository-GetAdminUsers] error when looking up all user")
+// Patch is a function to update columns of a user, rejected with ErrVersionConflict when version is not the stored version
+func (ur *UserRepository) Patch(ctx context.Context, id uuid.UUID, version int64, columns map[string]interface{}) error {
+	if err := patch.Update(ur.db.WithContext(ctx), new(entity.User), id, version, columns); err != nil {
+		return errors.Wrap(emailConflict(err), "[UserRepository-Patch] error when patching user data")
 	}
 
-	return user, total, nil
+	return nil
 }
 
-// UpdateUser is a function to update user
-func (ur *UserRepository) UpdateUser(ctx context.Context, user *entity.User) error {
-	oldTime := user.UpdatedAt
-	user.UpdatedAt = time.Now()
+// UpdateUserStatus is a function to update user status, rejected with ErrVersionConflict when version is not the stored version
+// and with ErrLastSuperAdmin when it deactivates the last active super admin
+func (ur *UserRepository) UpdateUserStatus(ctx context.Context, id uuid.UUID, status string, version int64) error {
 	if err := ur.db.
 		WithContext(ctx).
 		Transaction(func(tx *gorm.DB) error {
-			sourceModel := new(entity.User)
-			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Find(&sourceModel, user.ID).Error; err != nil {
-				log.Println("[UserRepository-UpdateUser]", err)
-				return err
+			if status != entity.UserStatusActivated {
+				if err := KeepSuperAdmin(tx, id, uuid.Nil); err != nil {
+					return err
+				}
 			}
-			if err := tx.Model(&entity.User{}).
-				Where(`id`, user.ID).
-				UpdateColumns(sourceModel.MapUpdateFrom(user)).Error; err != nil {
-				log.Println("[UserRepository-UpdateUser]", err)
-				return err
+
+			result := tx.Model(&entity.User{}).
+				Where(`id = ? AND version = ?`, id, version).
+				Updates(
+					map[string]interface{}{
+						"status":     status,
+						"version":    version + 1,
+						"updated_at": time.Now(),
+					})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected == 0 {
+				return ErrVersionConflict
 			}
+
 			return nil
 		}); err != nil {
-		user.UpdatedAt = oldTime
+		return errors.Wrap(err, "[UserRepository-UpdateUserStatus] error when updating user data")
 	}
+
 	return nil
 }
 
-// UpdateUserStatus is a function to update user status
-func (ur *UserRepository) UpdateUserStatus(ctx context.Context, id uuid.UUID, status string) error {
+// UpdateOTPChannel is a function to update the channel of the login code of a user
+func (ur *UserRepository) UpdateOTPChannel(ctx context.Context, id uuid.UUID, channel string) error {
 	if err := ur.db.WithContext(ctx).
 		Model(&entity.User{}).
 		Where(`id = ?`, id).
 		Updates(
 			map[string]interface{}{
-				"status":     status,
-				"updated_at": time.Now(),
+				"otp_channel": channel,
+				"version":     gorm.Expr("version + 1"),
+				"updated_at":  time.Now(),
 			}).Error; err != nil {
-		return errors.Wrap(err, "[UserRepository-DeactivateUser] error when updating user data")
+		return errors.Wrap(err, "[UserRepository-UpdateOTPChannel] error when updating user data")
 	}
 
 	return nil
 }
 
-// DeleteAdmin is a function to delete admin user
-func (ur *UserRepository) DeleteAdmin(ctx context.Context, id uuid.UUID) error {
-	if err := ur.db.WithContext(ctx).
-		Model(&entity.User{}).
-		Where(`id = ?`, id).
-		Delete(&entity.User{}, "id = ?", id).Error; err != nil {
+// DeleteAdmin is a function to delete admin user, rejected with ErrLastSuperAdmin when it is the last active super admin
+func (ur *UserRepository) DeleteAdmin(ctx context.Context, id uuid.UUID, deletedBy string) error {
+	now := time.Now()
+	if err := ur.db.
+		WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			if err := KeepSuperAdmin(tx, id, uuid.Nil); err != nil {
+				return err
+			}
+
+			return tx.Model(&entity.User{}).
+				Where(`id = ?`, id).
+				Updates(
+					map[string]interface{}{
+						"deleted_by": deletedBy,
+						"updated_at": now,
+						"deleted_at": now,
+					}).Error
+		}); err != nil {
 		return errors.Wrap(err, "[UserRepository-DeleteAdmin] error when updating user data")
 	}
 
 	return nil
 }
+
+// FindUsers is a function to find users matching all the given conditions
+func (ur *UserRepository) FindUsers(ctx context.Context, conditions []*UserCondition, limit, offset int) ([]*entity.User, int64, error) {
+	var users []*entity.User
+	var total int64
+	var gormDB = ur.db.
+		WithContext(ctx).
+		Model(&entity.User{}).
+		Preload("UserRole").
+		Preload("UserRole.Role")
+
+	for _, c := range conditions {
+		if !filterableUserColumns[c.Column] {
+			return nil, 0, errors.Errorf("[UserRepository-FindUsers] column %q is not filterable", c.Column)
+		}
+
+		column := fmt.Sprintf("main.users.%s", c.Column)
+		value, isString := c.Value.(string)
+		if isString && c.Column != "id" && c.Column != "created_at" && c.Column != "updated_at" {
+			column = fmt.Sprintf("LOWER(%s)", column)
+			value = strings.ToLower(value)
+		}
+
+		switch c.Operator {
+		case "eq":
+			gormDB = gormDB.Where(fmt.Sprintf("%s = ?", column), valueOrString(c.Value, value, isString))
+		case "ne":
+			gormDB = gormDB.Where(fmt.Sprintf("%s <> ?", column), valueOrString(c.Value, value, isString))
+		case "co":
+			gormDB = gormDB.Where(fmt.Sprintf("%s LIKE ?", column), "%"+escapeLike(value)+"%")
+		case "sw":
+			gormDB = gormDB.Where(fmt.Sprintf("%s LIKE ?", column), escapeLike(value)+"%")
+		case "ew":
+			gormDB = gormDB.Where(fmt.Sprintf("%s LIKE ?", column), "%"+escapeLike(value))
+		case "gt":
+			gormDB = gormDB.Where(fmt.Sprintf("%s > ?", column), valueOrString(c.Value, value, isString))
+		case "ge":
+			gormDB = gormDB.Where(fmt.Sprintf("%s >= ?", column), valueOrString(c.Value, value, isString))
+		case "lt":
+			gormDB = gormDB.Where(fmt.Sprintf("%s < ?", column), valueOrString(c.Value, value, isString))
+		case "le":
+			gormDB = gormDB.Where(fmt.Sprintf("%s <= ?", column), valueOrString(c.Value, value, isString))
+		case "pr":
+			gormDB = gormDB.Where(fmt.Sprintf("NULLIF(main.users.%s::text, '') IS NOT NULL", c.Column))
+		default:
+			return nil, 0, errors.Errorf("[UserRepository-FindUsers] operator %q is not supported", c.Operator)
+		}
+	}
+
+	if err := gormDB.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "[UserRepository-FindUsers] error when counting users")
+	}
+
+	if limit > 0 {
+		gormDB = gormDB.Limit(limit)
+	}
+
+	if offset > 0 {
+		gormDB = gormDB.Offset(offset)
+	}
+
+	if err := gormDB.
+		Order("main.users.created_at asc").
+		Find(&users).
+		Error; err != nil {
+		return nil, 0, errors.Wrap(err, "[UserRepository-FindUsers] error when looking up users")
+	}
+
+	return users, total, nil
+}
+
+// GetUsersByEmails is a function to get users, including deleted ones, owning any of the given emails, ignoring case
+func (ur *UserRepository) GetUsersByEmails(ctx context.Context, emails []string) ([]*entity.User, error) {
+	users := make([]*entity.User, 0)
+
+	if len(emails) == 0 {
+		return users, nil
+	}
+
+	lowered := make([]string, 0, len(emails))
+	for _, e := range emails {
+		lowered = append(lowered, strings.ToLower(e))
+	}
+
+	if err := ur.db.
+		WithContext(ctx).
+		Unscoped().
+		Model(&entity.User{}).
+		Where("LOWER(email) IN ?", lowered).
+		Find(&users).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "[UserRepository-GetUsersByEmails] error when looking up users")
+	}
+
+	return users, nil
+}
+
+// ExportUsers is a function to stream the users or admins matching the filter row by row.
+// Only the exportable columns are selected so credentials never leave the database.
+func (ur *UserRepository) ExportUsers(ctx context.Context, admin bool, q *query.Query, fn func(*entity.UserExport) error) error {
+	var gormDB = ur.db.
+		WithContext(ctx).
+		Model(&entity.User{}).
+		Select("main.users.name, main.users.email, COALESCE(main.users.phone_number, '') AS phone_number, main.users.status, COALESCE(main.roles.name, '') AS role, main.users.created_at").
+		Joins("left join main.user_roles on main.users.id=main.user_roles.user_id").
+		Joins("left join main.roles on main.user_roles.role_id=main.roles.id")
+
+	if admin {
+		gormDB = gormDB.Where("main.user_roles.user_id is not null")
+	} else {
+		gormDB = gormDB.Where("main.user_roles.user_id is null")
+	}
+
+	schema := UserListSchema
+	if admin {
+		schema = AdminListSchema
+	}
+
+	rows, err := gormDB.Scopes(q.Filter(schema), q.Order(schema)).Rows()
+	if err != nil {
+		return errors.Wrap(err, "[UserRepository-ExportUsers] error when looking up users")
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Println("[UserRepository-ExportUsers] error when closing rows:", err)
+		}
+	}()
+
+	for rows.Next() {
+		user := new(entity.UserExport)
+		if err := ur.db.ScanRows(rows, user); err != nil {
+			return errors.Wrap(err, "[UserRepository-ExportUsers] error when scanning user")
+		}
+
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "[UserRepository-ExportUsers] error when iterating users")
+	}
+
+	return nil
+}
+
+// IsEmailReserved is a function to check whether a deleted user still owns the email.
+// Emails of deleted users stay reserved until the user is purged.
+func (ur *UserRepository) IsEmailReserved(ctx context.Context, email string) (bool, error) {
+	var total int64
+
+	if err := ur.db.
+		WithContext(ctx).
+		Unscoped().
+		Model(&entity.User{}).
+		Where("LOWER(email) = ?", strings.ToLower(strings.TrimSpace(email))).
+		Where("deleted_at IS NOT NULL").
+		Count(&total).
+		Error; err != nil {
+		return false, errors.Wrap(err, "[UserRepository-IsEmailReserved] error when counting deleted users")
+	}
+
+	return total > 0, nil
+}
+
+// IsPhotoInUse is a function to check whether a user, including deleted ones, still references the photo
+func (ur *UserRepository) IsPhotoInUse(ctx context.Context, photo string) (bool, error) {
+	var total int64
+
+	if err := ur.db.
+		WithContext(ctx).
+		Unscoped().
+		Model(&entity.User{}).
+		Where("photo = ?", photo).
+		Count(&total).
+		Error; err != nil {
+		return false, errors.Wrap(err, "[UserRepository-IsPhotoInUse] error when counting users")
+	}
+
+	return total > 0, nil
+}
+
+// GetDeletedUsers is a function to get the deleted users
+func (ur *UserRepository) GetDeletedUsers(ctx context.Context, limit, offset int) ([]*entity.User, int64, error) {
+	users := make([]*entity.User, 0)
+	var total int64
+	var gormDB = ur.db.
+		WithContext(ctx).
+		Unscoped().
+		Model(&entity.User{}).
+		Where("deleted_at IS NOT NULL")
+
+	if err := gormDB.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "[UserRepository-GetDeletedUsers] error when counting deleted users")
+	}
+
+	if limit > 0 {
+		gormDB = gormDB.Limit(limit)
+	}
+
+	if offset > 0 {
+		gormDB = gormDB.Offset(offset)
+	}
+
+	if err := gormDB.
+		Preload("UserRole").
+		Preload("UserRole.Role").
+		Order("deleted_at desc").
+		Find(&users).
+		Error; err != nil {
+		return nil, 0, errors.Wrap(err, "[UserRepository-GetDeletedUsers] error when looking up deleted users")
+	}
+
+	return users, total, nil
+}
+
+// GetDeletedUserByID is a function to get a deleted user by id
+func (ur *UserRepository) GetDeletedUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	result := new(entity.User)
+
+	if err := ur.db.
+		WithContext(ctx).
+		Unscoped().
+		Preload("UserRole").
+		Where("id = ?", id).
+		Where("deleted_at IS NOT NULL").
+		First(result).
+		Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "[UserRepository-GetDeletedUserByID] error when looking up deleted user")
+	}
+
+	return result, nil
+}
+
+// Restore is a function to restore a deleted user
+func (ur *UserRepository) Restore(ctx context.Context, id uuid.UUID, restoredBy string) error {
+	if err := ur.db.
+		WithContext(ctx).
+		Unscoped().
+		Model(&entity.User{}).
+		Where("id = ?", id).
+		Updates(
+			map[string]interface{}{
+				"deleted_by": nil,
+				"deleted_at": nil,
+				"updated_by": restoredBy,
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+		return errors.Wrap(err, "[UserRepository-Restore] error when restoring user")
+	}
+
+	return nil
+}
+
+// Purge is a function to permanently delete the users deleted before the given time, along with their roles
+func (ur *UserRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+
+	if err := ur.db.
+		WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			expired := tx.Unscoped().
+				Model(&entity.User{}).
+				Select("id").
+				Where("deleted_at IS NOT NULL AND deleted_at < ?", before)
+
+			if err := tx.Unscoped().
+				Where("user_id IN (?)", expired).
+				Delete(&entity.UserRole{}).
+				Error; err != nil {
+				return errors.Wrap(err, "[UserRepository-Purge] error when deleting user roles")
+			}
+
+			result := tx.Unscoped().
+				Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+				Delete(&entity.User{})
+			if result.Error != nil {
+				return errors.Wrap(result.Error, "[UserRepository-Purge] error when deleting users")
+			}
+
+			purged = result.RowsAffected
+
+			return nil
+		}); err != nil {
+		return 0, err
+	}
+
+	return purged, nil
+}
+
+// valueOrString returns the lowered string when the original value is a string
+func valueOrString(original interface{}, lowered string, isString bool) interface{} {
+	if isString {
+		return lowered
+	}
+
+	return original
+}
+
+// escapeLike escapes the LIKE wildcards of a user supplied value
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// emailConflict translates a violation of the unique email index to ErrEmailConflict
+func emailConflict(err error) error {
+	if index, ok := utils.UniqueViolation(err); ok && index == entity.UserEmailUniqueIndex {
+		return ErrEmailConflict
+	}
+
+	return err
+}
@@ -0,0 +1,101 @@
+package repository_test
+
+import (
+	"context"
+	goErrors "errors"
+	"regexp"
+	"testing"
+
+	"gin-starter/common/permission"
+	"gin-starter/modules/user/v1/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type UserRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	mock sqlmock.Sqlmock
+	repo *repository.UserRepository
+}
+
+func TestUserRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(UserRepositoryTestSuite))
+}
+
+func (s *UserRepositoryTestSuite) BeforeTest(string, string) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		s.FailNow("error opening a stub db connection: ", err)
+	}
+
+	s.db, err = gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		s.FailNow("error initializing gorm connection: ", err)
+	}
+
+	s.mock = mock
+	s.repo = repository.NewUserRepository(s.db)
+}
+
+func (s *UserRepositoryTestSuite) AfterTest(string, string) {
+	if err := s.mock.ExpectationsWereMet(); err != nil {
+		s.FailNow("there were unfulfilled expectations: ", err)
+	}
+}
+
+func (s *UserRepositoryTestSuite) TestDeleteAdmin() {
+	lockSuperAdmins := regexp.QuoteMeta(`FOR UPDATE OF "user_roles"`)
+	findActive := regexp.QuoteMeta(`SELECT "id" FROM "main"."users"`)
+	deleteUser := regexp.QuoteMeta(`UPDATE "main"."users"`)
+	userID := uuid.New()
+	otherID := uuid.New()
+	roleID := uuid.New()
+
+	holders := func(userIDs ...uuid.UUID) *sqlmock.Rows {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "role_id"})
+		for _, id := range userIDs {
+			rows.AddRow(uuid.New(), id, roleID)
+		}
+
+		return rows
+	}
+
+	s.Run("successfully delete a super admin while another one is active", func() {
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(lockSuperAdmins).WithArgs(permission.SuperAdminRole).WillReturnRows(holders(userID, otherID))
+		s.mock.ExpectQuery(findActive).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(userID).AddRow(otherID))
+		s.mock.ExpectExec(deleteUser).WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+
+		s.Nil(s.repo.DeleteAdmin(context.Background(), userID, uuid.NewString()))
+	})
+
+	s.Run("fail to delete the last active super admin", func() {
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(lockSuperAdmins).WithArgs(permission.SuperAdminRole).WillReturnRows(holders(userID, otherID))
+		s.mock.ExpectQuery(findActive).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(userID))
+		s.mock.ExpectRollback()
+
+		err := s.repo.DeleteAdmin(context.Background(), userID, uuid.NewString())
+		s.True(goErrors.Is(err, repository.ErrLastSuperAdmin))
+	})
+
+	s.Run("successfully delete a user who is no super admin", func() {
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(lockSuperAdmins).WithArgs(permission.SuperAdminRole).WillReturnRows(holders(otherID))
+		s.mock.ExpectExec(deleteUser).WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+
+		s.Nil(s.repo.DeleteAdmin(context.Background(), userID, uuid.NewString()))
+	})
+}
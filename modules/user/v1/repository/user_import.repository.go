@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gin-starter/entity"
+)
+
+// errImportRoleNotFound is returned when the role of a row does not exist and the import may not create roles
+var errImportRoleNotFound = errors.New("import role not found")
+
+// UserImportRepository is a repository for user imports
+type UserImportRepository struct {
+	db *gorm.DB
+}
+
+// UserImportItem is a user to create for a valid import row
+type UserImportItem struct {
+	Row  *entity.UserImportRow
+	User *entity.User
+}
+
+// UserImportRepositoryUseCase is a use case for user imports
+type UserImportRepositoryUseCase interface {
+	// Create creates an import along with its rows
+	Create(ctx context.Context, userImport *entity.UserImport) error
+	// FindByID finds an import and its rows ordered by row number
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.UserImport, error)
+	// FindDue finds the pending imports and the processing imports stuck since staleBefore, without their rows
+	FindDue(ctx context.Context, staleBefore time.Time, limit int) ([]*entity.UserImport, error)
+	// Claim marks a due import as processing, returning false when another worker claimed it first
+	Claim(ctx context.Context, id uuid.UUID, staleBefore time.Time) (bool, error)
+	// UpdateStatus updates the status of an import
+	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
+	// ImportBatch creates the users of a batch of rows and records the outcome of every row
+	ImportBatch(ctx context.Context, userImport *entity.UserImport, items []*UserImportItem) error
+}
+
+// NewUserImportRepository is a constructor for UserImportRepository
+func NewUserImportRepository(db *gorm.DB) *UserImportRepository {
+	return &UserImportRepository{db}
+}
+
+// Create creates an import along with its rows
+func (ur *UserImportRepository) Create(ctx context.Context, userImport *entity.UserImport) error {
+	if err := ur.db.
+		WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			rows := userImport.Rows
+			userImport.Rows = nil
+			defer func() { userImport.Rows = rows }()
+
+			if err := tx.Model(&entity.UserImport{}).Create(userImport).Error; err != nil {
+				return err
+			}
+
+			if len(rows) > 0 {
+				if err := tx.Model(&entity.UserImportRow{}).CreateInBatches(rows, 500).Error; err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}); err != nil {
+		return errors.Wrap(err, "[UserImportRepository-Create] error while creating user import")
+	}
+
+	return nil
+}
+
+// FindByID finds an import and its rows ordered by row number
+func (ur *UserImportRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.UserImport, error) {
+	result := new(entity.UserImport)
+
+	if err := ur.db.
+		WithContext(ctx).
+		Preload("Rows", func(db *gorm.DB) *gorm.DB {
+			return db.Order("row_number asc")
+		}).
+		Where("id = ?", id).
+		First(result).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "[UserImportRepository-FindByID] error while getting user import")
+	}
+
+	return result, nil
+}
+
+// FindDue finds the pending imports and the processing imports stuck since staleBefore, without their rows
+func (ur *UserImportRepository) FindDue(ctx context.Context, staleBefore time.Time, limit int) ([]*entity.UserImport, error) {
+	imports := make([]*entity.UserImport, 0)
+
+	if err := ur.db.
+		WithContext(ctx).
+		Where("status = ? OR (status = ? AND updated_at < ?)", entity.UserImportStatusPending, entity.UserImportStatusProcessing, staleBefore).
+		Order("created_at asc").
+		Limit(limit).
+		Find(&imports).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "[UserImportRepository-FindDue] error while getting due user imports")
+	}
+
+	return imports, nil
+}
+
+// Claim marks a due import as processing, returning false when another worker claimed it first.
+// Every batch moves the updated_at of the import, so only an import whose worker stopped becomes stale.
+func (ur *UserImportRepository) Claim(ctx context.Context, id uuid.UUID, staleBefore time.Time) (bool, error) {
+	result := ur.db.
+		WithContext(ctx).
+		Model(&entity.UserImport{}).
+		Where("id = ?", id).
+		Where("status = ? OR (status = ? AND updated_at < ?)", entity.UserImportStatusPending, entity.UserImportStatusProcessing, staleBefore).
+		Updates(map[string]interface{}{
+			"status":     entity.UserImportStatusProcessing,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, errors.Wrap(result.Error, "[UserImportRepository-Claim] error while claiming user import")
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// UpdateStatus updates the status of an import
+func (ur *UserImportRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
+	values := map[string]interface{}{
+		"status":     status,
+		"updated_at": time.Now(),
+	}
+
+	if status == entity.UserImportStatusCompleted || status == entity.UserImportStatusFailed {
+		values["finished_at"] = time.Now()
+	}
+
+	if err := ur.db.
+		WithContext(ctx).
+		Model(&entity.UserImport{}).
+		Where("id = ?", id).
+		Updates(values).
+		Error; err != nil {
+		return errors.Wrap(err, "[UserImportRepository-UpdateStatus] error while updating user import")
+	}
+
+	return nil
+}
+
+// ImportBatch creates the users of a batch of rows and records the outcome of every row.
+// Every row runs in its own savepoint so a failing row does not roll the rest of the batch back.
+func (ur *UserImportRepository) ImportBatch(ctx context.Context, userImport *entity.UserImport, items []*UserImportItem) error {
+	if err := ur.db.
+		WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			roles := make(map[string]uuid.UUID)
+			created, failed := 0, 0
+
+			for _, item := range items {
+				if err := tx.SavePoint("import_row").Error; err != nil {
+					return err
+				}
+
+				if err := ur.importRow(tx, userImport, item, roles); err != nil {
+					if err := tx.RollbackTo("import_row").Error; err != nil {
+						return err
+					}
+
+					item.Row.Status = entity.UserImportRowStatusFailed
+					item.Row.Message = importRowMessage(item.Row, err)
+					failed++
+				} else {
+					item.Row.Status = entity.UserImportRowStatusCreated
+					item.Row.UserID = &item.User.ID
+					created++
+				}
+
+				if err := tx.Model(&entity.UserImportRow{}).
+					Where("id = ?", item.Row.ID).
+					Updates(map[string]interface{}{
+						"status":     item.Row.Status,
+						"message":    item.Row.Message,
+						"user_id":    item.Row.UserID,
+						"updated_at": time.Now(),
+					}).Error; err != nil {
+					return err
+				}
+			}
+
+			return tx.Model(&entity.UserImport{}).
+				Where("id = ?", userImport.ID).
+				Updates(map[string]interface{}{
+					"created_rows": gorm.Expr("created_rows + ?", created),
+					"failed_rows":  gorm.Expr("failed_rows + ?", failed),
+					"updated_at":   time.Now(),
+				}).Error
+		}); err != nil {
+		return errors.Wrap(err, "[UserImportRepository-ImportBatch] error while importing users")
+	}
+
+	return nil
+}
+
+// importRow creates the user of a row and assigns its role, creating the role when allowed
+func (ur *UserImportRepository) importRow(tx *gorm.DB, userImport *entity.UserImport, item *UserImportItem, roles map[string]uuid.UUID) error {
+	if err := tx.Model(&entity.User{}).Create(item.User).Error; err != nil {
+		return errors.Wrap(emailConflict(err), "error while creating user")
+	}
+
+	if item.Row.Role == "" {
+		return nil
+	}
+
+	key := strings.ToLower(item.Row.Role)
+	roleID, ok := roles[key]
+
+	if !ok {
+		role := new(entity.Role)
+		find := tx.Model(&entity.Role{}).Where("LOWER(name) = ?", key).Limit(1).Find(role)
+		if find.Error != nil {
+			return errors.Wrap(find.Error, "error while getting role")
+		}
+
+		if find.RowsAffected == 0 {
+			if !userImport.CreateRoles {
+				return errImportRoleNotFound
+			}
+
+			role = entity.NewRole(uuid.New(), item.Row.Role, userImport.CreatedBy.String)
+			if err := tx.Model(&entity.Role{}).Create(role).Error; err != nil {
+				return errors.Wrap(err, "error while creating role")
+			}
+		}
+
+		roleID = role.ID
+	}
+
+	userRole := entity.NewUserRole(uuid.New(), item.User.ID, roleID, userImport.CreatedBy.String)
+	if err := tx.Model(&entity.UserRole{}).Create(userRole).Error; err != nil {
+		return errors.Wrap(err, "error while assigning role")
+	}
+
+	roles[key] = roleID
+
+	return nil
+}
+
+// importRowMessage returns the message shown for a row whose user could not be created.
+// The cause of an unexpected failure is logged rather than shown.
+func importRowMessage(row *entity.UserImportRow, err error) string {
+	switch {
+	case errors.Is(err, ErrEmailConflict):
+		return "email is already registered"
+	case errors.Is(err, errImportRoleNotFound):
+		return fmt.Sprintf("role %q does not exist", row.Role)
+	}
+
+	log.Printf("[UserImportRepository-ImportBatch] import %s row %d: %v", row.UserImportID, row.RowNumber, err)
+
+	return "user could not be created"
+}
@@ -0,0 +1,115 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	goErrors "errors"
+	"regexp"
+	"testing"
+
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type UserImportRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	mock sqlmock.Sqlmock
+	repo *repository.UserImportRepository
+}
+
+func TestUserImportRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(UserImportRepositoryTestSuite))
+}
+
+func (s *UserImportRepositoryTestSuite) BeforeTest(string, string) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		s.FailNow("error opening a stub db connection: ", err)
+	}
+
+	s.db, err = gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		s.FailNow("error initializing gorm connection: ", err)
+	}
+
+	s.mock = mock
+	s.repo = repository.NewUserImportRepository(s.db)
+}
+
+func (s *UserImportRepositoryTestSuite) AfterTest(string, string) {
+	if err := s.mock.ExpectationsWereMet(); err != nil {
+		s.FailNow("there were unfulfilled expectations: ", err)
+	}
+}
+
+func (s *UserImportRepositoryTestSuite) TestImportBatch() {
+	createUser := regexp.QuoteMeta(`INSERT INTO "main"."users"`)
+	updateRow := regexp.QuoteMeta(`UPDATE "main"."user_import_rows"`)
+	updateImport := regexp.QuoteMeta(`UPDATE "main"."user_imports"`)
+
+	importRow := func(err error, message string) {
+		row := &entity.UserImportRow{ID: uuid.New(), RowNumber: 2, Name: "Budi", Email: "budi@example.com"}
+		userImport := entity.NewUserImport(uuid.New(), "users.csv", false, []*entity.UserImportRow{row}, uuid.NewString())
+		user := entity.NewUser(uuid.New(), row.Name, row.Email, "secret", sql.NullTime{}, "", "", userImport.CreatedBy.String)
+
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec("SAVEPOINT import_row").WillReturnResult(sqlmock.NewResult(0, 0))
+		s.mock.ExpectExec(createUser).WillReturnError(err)
+		s.mock.ExpectExec("ROLLBACK TO SAVEPOINT import_row").WillReturnResult(sqlmock.NewResult(0, 0))
+		s.mock.
+			ExpectExec(updateRow).
+			WithArgs(message, entity.UserImportRowStatusFailed, sqlmock.AnyArg(), nil, row.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectExec(updateImport).WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+
+		err = s.repo.ImportBatch(context.Background(), userImport, []*repository.UserImportItem{{Row: row, User: user}})
+
+		s.Nil(err)
+		s.Equal(entity.UserImportRowStatusFailed, row.Status)
+		s.Equal(message, row.Message)
+	}
+
+	s.Run("create the user and record the row", func() {
+		row := &entity.UserImportRow{ID: uuid.New(), RowNumber: 2, Name: "Budi", Email: "budi@example.com"}
+		userImport := entity.NewUserImport(uuid.New(), "users.csv", false, []*entity.UserImportRow{row}, uuid.NewString())
+		user := entity.NewUser(uuid.New(), row.Name, row.Email, "secret", sql.NullTime{}, "", "", userImport.CreatedBy.String)
+
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec("SAVEPOINT import_row").WillReturnResult(sqlmock.NewResult(0, 0))
+		s.mock.ExpectExec(createUser).WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.
+			ExpectExec(updateRow).
+			WithArgs("", entity.UserImportRowStatusCreated, sqlmock.AnyArg(), user.ID, row.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectExec(updateImport).WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+
+		err := s.repo.ImportBatch(context.Background(), userImport, []*repository.UserImportItem{{Row: row, User: user}})
+
+		s.Nil(err)
+		s.Equal(entity.UserImportRowStatusCreated, row.Status)
+		s.Equal(&user.ID, row.UserID)
+	})
+
+	s.Run("record a registered email with a fixed message", func() {
+		importRow(&pgconn.PgError{Code: "23505", ConstraintName: entity.UserEmailUniqueIndex}, "email is already registered")
+	})
+
+	s.Run("record an unexpected failure without its cause", func() {
+		importRow(goErrors.New(`pq: value too long for type character varying(50) in "users"`), "user could not be created")
+	})
+}
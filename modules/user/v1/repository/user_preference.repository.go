@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	commonCache "gin-starter/common/cache"
+	"gin-starter/common/interfaces"
+	"gin-starter/entity"
+)
+
+// UserPreferenceRepository is a repository for user preferences
+type UserPreferenceRepository struct {
+	db    *gorm.DB
+	cache interfaces.Cacheable
+}
+
+// UserPreferenceRepositoryUseCase is a use case for user preferences
+type UserPreferenceRepositoryUseCase interface {
+	// FindByUserID finds the stored preferences of a user
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserPreference, error)
+	// Save stores the preferences of a user and deletes the reset ones so they fall back to their default
+	Save(ctx context.Context, userID uuid.UUID, preferences []*entity.UserPreference, resetKeys []string, updatedBy string) error
+}
+
+// NewUserPreferenceRepository is a constructor for UserPreferenceRepository
+func NewUserPreferenceRepository(db *gorm.DB, cache interfaces.Cacheable) *UserPreferenceRepository {
+	return &UserPreferenceRepository{db, cache}
+}
+
+// FindByUserID finds the stored preferences of a user
+func (ur *UserPreferenceRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserPreference, error) {
+	preferences := make([]*entity.UserPreference, 0)
+
+	bytes, _ := ur.cache.Get(fmt.Sprintf(commonCache.UserPreferencesByUserID, userID))
+
+	if bytes != nil {
+		if err := json.Unmarshal(bytes, &preferences); err != nil {
+			return nil, err
+		}
+		return preferences, nil
+	}
+
+	if err := ur.db.
+		WithContext(ctx).
+		Where("user_id = ?", userID).
+		Find(&preferences).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "[UserPreferenceRepository-FindByUserID] error while getting user preferences")
+	}
+
+	if err := ur.cache.Set(fmt.Sprintf(commonCache.UserPreferencesByUserID, userID), preferences, commonCache.OneMonth); err != nil {
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+// Save stores the preferences of a user and deletes the reset ones so they fall back to their default
+func (ur *UserPreferenceRepository) Save(ctx context.Context, userID uuid.UUID, preferences []*entity.UserPreference, resetKeys []string, updatedBy string) error {
+	if err := ur.db.
+		WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			if len(resetKeys) > 0 {
+				if err := tx.Unscoped().
+					Where("user_id = ? AND key IN ?", userID, resetKeys).
+					Delete(&entity.UserPreference{}).
+					Error; err != nil {
+					return err
+				}
+			}
+
+			if len(preferences) == 0 {
+				return nil
+			}
+
+			return tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "user_id"}, {Name: "key"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"value":      gorm.Expr("EXCLUDED.value"),
+					"updated_by": updatedBy,
+					"updated_at": time.Now(),
+				}),
+			}).Create(&preferences).Error
+		}); err != nil {
+		return errors.Wrap(err, "[UserPreferenceRepository-Save] error while saving user preferences")
+	}
+
+	return ur.cache.Remove(fmt.Sprintf(commonCache.UserPreferencesByUserID, userID))
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	commonCache "gin-starter/common/cache"
 	"gin-starter/common/interfaces"
+	"gin-starter/common/patch"
+	"gin-starter/common/permission"
 	"gin-starter/entity"
 	"log"
 	"time"
@@ -16,6 +18,9 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// ErrLastSuperAdmin is returned when a write would take the super admin role away from its last active holder
+var ErrLastSuperAdmin = errors.New("last super admin")
+
 // UserRoleRepository is a repository for user role
 type UserRoleRepository struct {
 	db    *gorm.DB
@@ -24,14 +29,29 @@ type UserRoleRepository struct {
 
 // UserRoleRepositoryUseCase is a use case for user role
 type UserRoleRepositoryUseCase interface {
-	// CreateOrUpdate is a method for creating or updating user role
+	// CreateOrUpdate is a method for creating or updating user role, rejected with ErrLastSuperAdmin when it moves the last
+	// active super admin
 	CreateOrUpdate(ctx context.Context, userRole *entity.UserRole) error
 	// FindByUserID is a method for finding user role by user id
 	FindByUserID(ctx context.Context, id uuid.UUID) (*entity.UserRole, error)
-	// Update is a method for updating user role
+	// Update is a method for updating user role, rejected with ErrLastSuperAdmin when it moves the last active super admin
 	Update(ctx context.Context, userRole *entity.UserRole) error
-	// Delete is a method for deleting user role
+	// UpdateAdmin updates an admin and moves it to the role in one transaction, rejected with ErrVersionConflict when
+	// user.Version is not the stored version and with ErrLastSuperAdmin when it moves the last active super admin
+	UpdateAdmin(ctx context.Context, user *entity.User, roleID uuid.UUID) error
+	// PatchAdmin updates columns of an admin and moves it to the role in one transaction, rejected with ErrVersionConflict
+	// when version is not the stored version and with ErrLastSuperAdmin when it moves the last active super admin
+	PatchAdmin(ctx context.Context, id uuid.UUID, version int64, columns map[string]interface{}, roleID uuid.UUID) error
+	// Delete is a method for deleting user role, rejected with ErrLastSuperAdmin when it removes the last active super admin
 	Delete(ctx context.Context, id uuid.UUID) error
+	// FindByRoleID is a method for finding user roles by role id
+	FindByRoleID(ctx context.Context, roleID uuid.UUID) ([]*entity.UserRole, error)
+	// CountByRoleID is a method for counting the active users assigned to a role
+	CountByRoleID(ctx context.Context, roleID uuid.UUID) (int64, error)
+	// CountActivatedByRoleID is a method for counting the activated users assigned to a role
+	CountActivatedByRoleID(ctx context.Context, roleID uuid.UUID) (int64, error)
+	// Reassign is a method for moving every user of a role to another role
+	Reassign(ctx context.Context, fromRoleID, toRoleID uuid.UUID, updatedBy string) error
 }
 
 // NewUserRoleRepository is a constructor for UserRoleRepository
@@ -39,7 +59,8 @@ func NewUserRoleRepository(db *gorm.DB, cache interfaces.Cacheable) *UserRoleRep
 	return &UserRoleRepository{db, cache}
 }
 
-// CreateOrUpdate is a method for creating or updating user role
+// CreateOrUpdate is a method for creating or updating user role, rejected with ErrLastSuperAdmin when it moves the last
+// active super admin
 func (nc *UserRoleRepository) CreateOrUpdate(ctx context.Context, userRole *entity.UserRole) error {
 	var find *entity.UserRole
 
@@ -54,16 +75,24 @@ func (nc *UserRoleRepository) CreateOrUpdate(ctx context.Context, userRole *enti
 	}
 
 	if findUser.RowsAffected > 0 {
-		if err := nc.db.Model(&entity.UserRole{}).
-			Where("user_id = ?", userRole.UserID).
-			UpdateColumns(map[string]interface{}{
-				"role_id": userRole.RoleID,
-			}).
-			Error; err != nil {
+		if err := nc.db.
+			WithContext(ctx).
+			Transaction(func(tx *gorm.DB) error {
+				if err := KeepSuperAdmin(tx, userRole.UserID, userRole.RoleID); err != nil {
+					return err
+				}
+
+				return tx.Model(&entity.UserRole{}).
+					Where("user_id = ?", userRole.UserID).
+					UpdateColumns(map[string]interface{}{
+						"role_id": userRole.RoleID,
+					}).
+					Error
+			}); err != nil {
 			return err
 		}
 
-		return nil
+		return nc.cache.BulkRemove(fmt.Sprintf(commonCache.UserRoleByUserID, "*"))
 	}
 
 	if err := nc.db.
@@ -117,13 +146,17 @@ func (nc *UserRoleRepository) FindByUserID(ctx context.Context, id uuid.UUID) (*
 	return category, nil
 }
 
-// Update is a method for updating user role
+// Update is a method for updating user role, rejected with ErrLastSuperAdmin when it moves the last active super admin
 func (nc *UserRoleRepository) Update(ctx context.Context, userRole *entity.UserRole) error {
 	oldTime := userRole.UpdatedAt
 	userRole.UpdatedAt = time.Now()
 	if err := nc.db.
 		WithContext(ctx).
 		Transaction(func(tx *gorm.DB) error {
+			if err := KeepSuperAdmin(tx, userRole.UserID, userRole.RoleID); err != nil {
+				return err
+			}
+
 			sourceModel := new(entity.UserRole)
 			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
 				Where("user_id = ?", userRole.UserID).
@@ -140,6 +173,7 @@ func (nc *UserRoleRepository) Update(ctx context.Context, userRole *entity.UserR
 			return nil
 		}); err != nil {
 		userRole.UpdatedAt = oldTime
+		return errors.Wrap(err, "[UserRoleRepository-Update] error while updating user role")
 	}
 
 	if err := nc.cache.BulkRemove(fmt.Sprintf(commonCache.UserRoleByUserID, "*")); err != nil {
@@ -149,21 +183,197 @@ func (nc *UserRoleRepository) Update(ctx context.Context, userRole *entity.UserR
 	return nil
 }
 
-// Delete is a method for deleting user role
+// UpdateAdmin updates an admin and moves it to the role in one transaction, rejected with ErrVersionConflict when
+// user.Version is not the stored version and with ErrLastSuperAdmin when it moves the last active super admin
+func (nc *UserRoleRepository) UpdateAdmin(ctx context.Context, user *entity.User, roleID uuid.UUID) error {
+	if err := nc.db.
+		WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			if err := KeepSuperAdmin(tx, user.ID, roleID); err != nil {
+				return err
+			}
+
+			if err := updateVersioned(tx, user); err != nil {
+				return emailConflict(err)
+			}
+
+			return moveRole(tx, user.ID, roleID)
+		}); err != nil {
+		return errors.Wrap(err, "[UserRoleRepository-UpdateAdmin] error while updating admin")
+	}
+
+	return nc.cache.BulkRemove(fmt.Sprintf(commonCache.UserRoleByUserID, "*"))
+}
+
+// PatchAdmin updates columns of an admin and moves it to the role in one transaction, rejected with ErrVersionConflict
+// when version is not the stored version and with ErrLastSuperAdmin when it moves the last active super admin
+func (nc *UserRoleRepository) PatchAdmin(ctx context.Context, id uuid.UUID, version int64, columns map[string]interface{}, roleID uuid.UUID) error {
+	if err := nc.db.
+		WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			if err := KeepSuperAdmin(tx, id, roleID); err != nil {
+				return err
+			}
+
+			if err := patch.Update(tx, new(entity.User), id, version, columns); err != nil {
+				return emailConflict(err)
+			}
+
+			return moveRole(tx, id, roleID)
+		}); err != nil {
+		return errors.Wrap(err, "[UserRoleRepository-PatchAdmin] error while patching admin")
+	}
+
+	return nc.cache.BulkRemove(fmt.Sprintf(commonCache.UserRoleByUserID, "*"))
+}
+
+// moveRole moves the user to the role within the transaction
+func moveRole(tx *gorm.DB, userID, roleID uuid.UUID) error {
+	return tx.Model(&entity.UserRole{}).
+		Where("user_id = ?", userID).
+		UpdateColumns(map[string]interface{}{
+			"role_id":    roleID,
+			"updated_at": time.Now(),
+		}).
+		Error
+}
+
+// Delete is a method for deleting user role, rejected with ErrLastSuperAdmin when it removes the last active super admin
 func (nc *UserRoleRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	if err := nc.db.WithContext(ctx).
+	if err := nc.db.
+		WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			if err := KeepSuperAdmin(tx, id, uuid.Nil); err != nil {
+				return err
+			}
+
+			return tx.Model(&entity.UserRole{}).
+				Where(`user_id = ?`, id).
+				Updates(
+					map[string]interface{}{
+						"updated_at": time.Now(),
+						"deleted_at": time.Now(),
+					}).Error
+		}); err != nil {
+		return errors.Wrap(err, "[UserRepository-DeactivateUser] error when updating user data")
+	}
+
+	if err := nc.cache.BulkRemove(fmt.Sprintf(commonCache.UserRoleByUserID, "*")); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindByRoleID is a method for finding user roles by role id
+func (nc *UserRoleRepository) FindByRoleID(ctx context.Context, roleID uuid.UUID) ([]*entity.UserRole, error) {
+	userRoles := make([]*entity.UserRole, 0)
+
+	if err := nc.db.
+		WithContext(ctx).
+		Model(&entity.UserRole{}).
+		Preload("User").
+		Where("role_id = ?", roleID).
+		Find(&userRoles).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "[UserRoleRepository-FindByRoleID] error while getting user roles")
+	}
+
+	return userRoles, nil
+}
+
+// CountByRoleID is a method for counting the active users assigned to a role
+func (nc *UserRoleRepository) CountByRoleID(ctx context.Context, roleID uuid.UUID) (int64, error) {
+	var total int64
+
+	if err := nc.db.
+		WithContext(ctx).
 		Model(&entity.UserRole{}).
-		Where(`user_id = ?`, id).
+		Joins("inner join main.users on main.users.id=main.user_roles.user_id and main.users.deleted_at is null").
+		Where("main.user_roles.role_id = ?", roleID).
+		Count(&total).
+		Error; err != nil {
+		return 0, errors.Wrap(err, "[UserRoleRepository-CountByRoleID] error while counting user roles")
+	}
+
+	return total, nil
+}
+
+// CountActivatedByRoleID is a method for counting the activated users assigned to a role, deleted users excluded
+func (nc *UserRoleRepository) CountActivatedByRoleID(ctx context.Context, roleID uuid.UUID) (int64, error) {
+	var total int64
+
+	if err := nc.db.
+		WithContext(ctx).
+		Model(&entity.UserRole{}).
+		Joins("inner join main.users on main.users.id=main.user_roles.user_id and main.users.deleted_at is null").
+		Where("main.user_roles.role_id = ?", roleID).
+		Where("main.users.status = ?", entity.UserStatusActivated).
+		Count(&total).
+		Error; err != nil {
+		return 0, errors.Wrap(err, "[UserRoleRepository-CountActivatedByRoleID] error while counting user roles")
+	}
+
+	return total, nil
+}
+
+// Reassign is a method for moving every user of a role to another role
+func (nc *UserRoleRepository) Reassign(ctx context.Context, fromRoleID, toRoleID uuid.UUID, updatedBy string) error {
+	if err := nc.db.
+		WithContext(ctx).
+		Model(&entity.UserRole{}).
+		Where("role_id = ?", fromRoleID).
 		Updates(
 			map[string]interface{}{
+				"role_id":    toRoleID,
+				"updated_by": updatedBy,
 				"updated_at": time.Now(),
-				"deleted_at": time.Now(),
 			}).Error; err != nil {
-		return errors.Wrap(err, "[UserRepository-DeactivateUser] error when updating user data")
+		return errors.Wrap(err, "[UserRoleRepository-Reassign] error while reassigning user roles")
 	}
 
-	if err := nc.cache.BulkRemove(fmt.Sprintf(commonCache.UserRoleByUserID, "*")); err != nil {
+	return nc.cache.BulkRemove(fmt.Sprintf(commonCache.UserRoleByUserID, "*"))
+}
+
+// KeepSuperAdmin fails with ErrLastSuperAdmin when the write of tx would take the super admin role away from its last
+// active holder, either by moving the user to roleID or, when roleID is uuid.Nil, by deleting or deactivating the user.
+// The user roles of the super admins stay locked until tx ends, so concurrent writes removing super admins run one
+// after the other and each one counts the holders the previous one left.
+func KeepSuperAdmin(tx *gorm.DB, userID, roleID uuid.UUID) error {
+	holders := make([]*entity.UserRole, 0)
+
+	if err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "user_roles"}}).
+		Joins("inner join main.roles on main.roles.id=main.user_roles.role_id and main.roles.deleted_at is null").
+		Where("main.roles.system AND main.roles.name = ?", permission.SuperAdminRole).
+		Find(&holders).
+		Error; err != nil {
+		return err
+	}
+
+	holderIDs := make([]uuid.UUID, 0, len(holders))
+	removed := false
+	for _, h := range holders {
+		holderIDs = append(holderIDs, h.UserID)
+		removed = removed || (h.UserID == userID && h.RoleID != roleID)
+	}
+
+	if !removed {
+		return nil
+	}
+
+	activeIDs := make([]uuid.UUID, 0, len(holderIDs))
+
+	if err := tx.
+		Model(&entity.User{}).
+		Where("id IN ? AND status = ?", holderIDs, entity.UserStatusActivated).
+		Pluck("id", &activeIDs).
+		Error; err != nil {
 		return err
 	}
+
+	if len(activeIDs) == 1 && activeIDs[0] == userID {
+		return ErrLastSuperAdmin
+	}
+
 	return nil
 }
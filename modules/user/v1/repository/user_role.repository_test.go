@@ -0,0 +1,114 @@
+package repository_test
+
+import (
+	"context"
+	goErrors "errors"
+	"regexp"
+	"testing"
+
+	"gin-starter/common/permission"
+	"gin-starter/modules/user/v1/repository"
+	mockInterfaces "gin-starter/test/mock/common/interfaces"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type UserRoleRepositoryTestSuite struct {
+	suite.Suite
+	db       *gorm.DB
+	mock     sqlmock.Sqlmock
+	mockCtrl *gomock.Controller
+	cache    *mockInterfaces.MockCacheable
+	repo     *repository.UserRoleRepository
+}
+
+func TestUserRoleRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(UserRoleRepositoryTestSuite))
+}
+
+func (s *UserRoleRepositoryTestSuite) BeforeTest(string, string) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		s.FailNow("error opening a stub db connection: ", err)
+	}
+
+	s.db, err = gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		s.FailNow("error initializing gorm connection: ", err)
+	}
+
+	s.mock = mock
+	s.mockCtrl = gomock.NewController(s.T())
+	s.cache = mockInterfaces.NewMockCacheable(s.mockCtrl)
+	s.repo = repository.NewUserRoleRepository(s.db, s.cache)
+}
+
+func (s *UserRoleRepositoryTestSuite) AfterTest(string, string) {
+	defer s.mockCtrl.Finish()
+
+	if err := s.mock.ExpectationsWereMet(); err != nil {
+		s.FailNow("there were unfulfilled expectations: ", err)
+	}
+}
+
+func (s *UserRoleRepositoryTestSuite) TestPatchAdmin() {
+	lockSuperAdmins := regexp.QuoteMeta(`FOR UPDATE OF "user_roles"`)
+	findActive := regexp.QuoteMeta(`SELECT "id" FROM "main"."users"`)
+	patchUser := regexp.QuoteMeta(`UPDATE "main"."users"`)
+	findUser := regexp.QuoteMeta(`SELECT * FROM "main"."users"`)
+	moveRole := regexp.QuoteMeta(`UPDATE "main"."user_roles"`)
+	userID := uuid.New()
+	otherID := uuid.New()
+	superAdminID := uuid.New()
+	editorID := uuid.New()
+	columns := map[string]interface{}{"name": "Budi"}
+
+	s.Run("successfully patch an admin and move it to another role", func() {
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(lockSuperAdmins).WithArgs(permission.SuperAdminRole).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "role_id"}).AddRow(uuid.New(), otherID, superAdminID))
+		s.mock.ExpectExec("SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+		s.mock.ExpectExec(patchUser).WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectQuery(findUser).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(userID))
+		s.mock.ExpectExec(moveRole).WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+		s.cache.EXPECT().BulkRemove(gomock.Any()).Return(nil)
+
+		s.Nil(s.repo.PatchAdmin(context.Background(), userID, 1, columns, editorID))
+	})
+
+	s.Run("fail to move the last active super admin, leaving the admin unchanged", func() {
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(lockSuperAdmins).WithArgs(permission.SuperAdminRole).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "role_id"}).AddRow(uuid.New(), userID, superAdminID))
+		s.mock.ExpectQuery(findActive).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(userID))
+		s.mock.ExpectRollback()
+
+		err := s.repo.PatchAdmin(context.Background(), userID, 1, columns, editorID)
+		s.True(goErrors.Is(err, repository.ErrLastSuperAdmin))
+	})
+
+	s.Run("fail to patch an outdated version, leaving the role unchanged", func() {
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(lockSuperAdmins).WithArgs(permission.SuperAdminRole).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "role_id"}))
+		s.mock.ExpectExec("SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+		s.mock.ExpectExec(patchUser).WillReturnResult(sqlmock.NewResult(0, 0))
+		s.mock.ExpectQuery(findUser).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(userID))
+		s.mock.ExpectExec("ROLLBACK TO SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+		s.mock.ExpectRollback()
+
+		err := s.repo.PatchAdmin(context.Background(), userID, 1, columns, editorID)
+		s.True(goErrors.Is(err, repository.ErrVersionConflict))
+	})
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	goErrors "errors"
 	"gin-starter/common/errors"
 	"gin-starter/common/interfaces"
 	"gin-starter/config"
@@ -9,6 +10,7 @@ import (
 	notificationService "gin-starter/modules/notification/v1/service"
 	"gin-starter/modules/user/v1/repository"
 	"gin-starter/utils"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -28,9 +30,7 @@ type UserCreator struct {
 // UserCreatorUseCase is a use case for the User creator
 type UserCreatorUseCase interface {
 	// CreateUser creates a new user
-	CreateUser(ctx context.Context, name, email, password, phoneNumber, photo string, dob time.Time) (*entity.User, error)
-	// CreateAdmin creates a new admin
-	CreateAdmin(ctx context.Context, name, email, password, phoneNumber, photo string, dob time.Time, roleID uuid.UUID) (*entity.User, error)
+	CreateUser(ctx context.Context, name, email, password, phoneNumber string, photo *entity.UserPhoto, dob time.Time) (*entity.User, error)
 	// CreatePermission creates a permission
 	CreatePermission(ctx context.Context, name, label string) (*entity.Permission, error)
 	// CreateRole creates a role
@@ -59,46 +59,34 @@ func NewUserCreator(
 }
 
 // CreateUser creates a new user
-func (uc *UserCreator) CreateUser(ctx context.Context, name, email, password, phoneNumber, photo string, dob time.Time) (*entity.User, error) {
-	user := entity.NewUser(
-		uuid.New(),
-		name,
-		email,
-		password,
-		utils.TimeToNullTime(dob),
-		photo,
-		phoneNumber,
-		"system",
-	)
-
-	if err := uc.userRepo.CreateUser(ctx, user); err != nil {
-		return nil, errors.ErrInternalServerError.Error()
+func (uc *UserCreator) CreateUser(ctx context.Context, name, email, password, phoneNumber string, photo *entity.UserPhoto, dob time.Time) (*entity.User, error) {
+	email = utils.NormalizeEmail(email)
+	if err := checkEmailAvailable(ctx, uc.userRepo, email, uuid.Nil); err != nil {
+		return nil, err
 	}
 
-	return user, nil
-}
+	phoneNumber, err := normalizeOptionalPhoneNumber(phoneNumber)
+	if err != nil {
+		return nil, err
+	}
 
-// CreateAdmin creates a new admin
-func (uc *UserCreator) CreateAdmin(ctx context.Context, name, email, password, phoneNumber, photo string, dob time.Time, roleID uuid.UUID) (*entity.User, error) {
-	userID := uuid.New()
 	user := entity.NewUser(
-		userID,
+		uuid.New(),
 		name,
 		email,
 		password,
 		utils.TimeToNullTime(dob),
-		photo,
+		"",
 		phoneNumber,
 		"system",
 	)
+	user.SetPhoto(photo)
 
 	if err := uc.userRepo.CreateUser(ctx, user); err != nil {
-		return nil, errors.ErrInternalServerError.Error()
-	}
+		if goErrors.Is(err, repository.ErrEmailConflict) {
+			return nil, errors.ErrEmailAlreadyUsed.Error()
+		}
 
-	userRole := entity.NewUserRole(uuid.New(), userID, roleID, "system")
-
-	if err := uc.userRoleRepo.CreateOrUpdate(ctx, userRole); err != nil {
 		return nil, errors.ErrInternalServerError.Error()
 	}
 
@@ -121,8 +109,13 @@ func (uc *UserCreator) CreatePermission(ctx context.Context, name, label string)
 	return permission, nil
 }
 
-// CreateRole creates a role
+// CreateRole creates a role, createdBy can only grant the permissions it holds itself
 func (uc *UserCreator) CreateRole(ctx context.Context, name string, permissionIDs []uuid.UUID, createdBy string) (*entity.Role, error) {
+	actorID, _ := uuid.Parse(createdBy)
+	if err := checkGrantable(ctx, uc.userRoleRepo, uc.roleRepo, actorID, permissionIDs); err != nil {
+		return nil, err
+	}
+
 	role := entity.NewRole(uuid.New(), name, createdBy)
 	if err := uc.roleRepo.Create(ctx, role, permissionIDs); err != nil {
 		return nil, err
@@ -130,3 +123,45 @@ func (uc *UserCreator) CreateRole(ctx context.Context, name string, permissionID
 
 	return role, nil
 }
+
+// checkEmailAvailable rejects an email, ignoring case, which belongs to another user than userID.
+// Deleted users keep their email reserved until they are purged from the trash.
+func checkEmailAvailable(ctx context.Context, userRepo repository.UserRepositoryUseCase, email string, userID uuid.UUID) error {
+	owners, err := userRepo.GetUsersByEmails(ctx, []string{email})
+	if err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	reserved := false
+	for _, owner := range owners {
+		if owner.ID == userID {
+			continue
+		}
+
+		if !owner.DeletedAt.Valid {
+			return errors.ErrEmailAlreadyUsed.Error()
+		}
+
+		reserved = true
+	}
+
+	if reserved {
+		return errors.ErrEmailReserved.Error()
+	}
+
+	return nil
+}
+
+// normalizeOptionalPhoneNumber normalises a phone number to E.164, users may leave it empty
+func normalizeOptionalPhoneNumber(phoneNumber string) (string, error) {
+	if strings.TrimSpace(phoneNumber) == "" {
+		return "", nil
+	}
+
+	normalized, err := utils.NormalizePhoneNumber(phoneNumber)
+	if err != nil {
+		return "", errors.ErrInvalidPhoneNumber.Error()
+	}
+
+	return normalized, nil
+}
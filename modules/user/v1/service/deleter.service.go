@@ -2,52 +2,121 @@ package service
 
 import (
 	"context"
+	goErrors "errors"
 	"gin-starter/common/errors"
 	"gin-starter/config"
 	"gin-starter/modules/user/v1/repository"
+	"log"
 
 	"github.com/google/uuid"
 )
 
 // UserDeleter is a service for user
 type UserDeleter struct {
-	cfg      config.Config
-	userRepo repository.UserRepositoryUseCase
-	roleRepo repository.RoleRepositoryUseCase
+	cfg          config.Config
+	userRepo     repository.UserRepositoryUseCase
+	userRoleRepo repository.UserRoleRepositoryUseCase
+	roleRepo     repository.RoleRepositoryUseCase
 }
 
 // UserDeleterUseCase is a use case for user
 type UserDeleterUseCase interface {
 	// DeleteAdmin deletes admin
-	DeleteAdmin(ctx context.Context, id uuid.UUID) error
-	// DeleteRole deletes role
-	DeleteRole(ctx context.Context, id uuid.UUID, deletedBy string) error
+	DeleteAdmin(ctx context.Context, id uuid.UUID, deletedBy string) error
+	// DeleteRole deletes role, moving its users to reassignRoleID when it is not uuid.Nil
+	DeleteRole(ctx context.Context, id, reassignRoleID uuid.UUID, deletedBy string) error
 }
 
 // NewUserDeleter creates a new UserDeleter
 func NewUserDeleter(
 	cfg config.Config,
 	userRepo repository.UserRepositoryUseCase,
+	userRoleRepo repository.UserRoleRepositoryUseCase,
 	roleRepo repository.RoleRepositoryUseCase,
 ) *UserDeleter {
 	return &UserDeleter{
-		cfg:      cfg,
-		userRepo: userRepo,
-		roleRepo: roleRepo,
+		cfg:          cfg,
+		userRepo:     userRepo,
+		userRoleRepo: userRoleRepo,
+		roleRepo:     roleRepo,
 	}
 }
 
-// DeleteAdmin deletes admin
-func (ud *UserDeleter) DeleteAdmin(ctx context.Context, id uuid.UUID) error {
-	if err := ud.userRepo.DeleteAdmin(ctx, id); err != nil {
+// DeleteAdmin deletes admin, the last active super admin cannot be deleted
+func (ud *UserDeleter) DeleteAdmin(ctx context.Context, id uuid.UUID, deletedBy string) error {
+	user, err := ud.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if user != nil {
+		if err := checkLastSuperAdmin(ctx, ud.userRoleRepo, ud.roleRepo, user, uuid.Nil); err != nil {
+			return err
+		}
+	}
+
+	if err := ud.userRepo.DeleteAdmin(ctx, id, deletedBy); err != nil {
+		if goErrors.Is(err, repository.ErrLastSuperAdmin) {
+			return errors.ErrLastSuperAdmin.Error()
+		}
+
+		log.Println("[UserDeleter-DeleteAdmin]", err)
 		return errors.ErrInternalServerError.Error()
 	}
 
 	return nil
 }
 
-// DeleteRole deletes role
-func (ud *UserDeleter) DeleteRole(ctx context.Context, id uuid.UUID, deletedBy string) error {
+// DeleteRole deletes role.
+// System roles cannot be deleted, and a role still assigned to users can only be deleted when its users
+// are moved to another role granting no permission deletedBy does not hold.
+func (ud *UserDeleter) DeleteRole(ctx context.Context, id, reassignRoleID uuid.UUID, deletedBy string) error {
+	role, err := ud.roleRepo.FindByID(ctx, id)
+	if err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if role == nil {
+		return errors.ErrRecordNotFound.Error()
+	}
+
+	if role.System {
+		return errors.ErrSystemRoleProtected.Error()
+	}
+
+	total, err := ud.userRoleRepo.CountByRoleID(ctx, id)
+	if err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if total > 0 {
+		if reassignRoleID == uuid.Nil {
+			return errors.ErrRoleHasUsers.Error()
+		}
+
+		if reassignRoleID == id {
+			return errors.ErrInvalidArgument.Error()
+		}
+
+		target, err := ud.roleRepo.FindByID(ctx, reassignRoleID)
+		if err != nil {
+			return errors.ErrInternalServerError.Error()
+		}
+
+		if target == nil {
+			return errors.ErrRecordNotFound.Error()
+		}
+
+		actorID, _ := uuid.Parse(deletedBy)
+		if err := checkGrantable(ctx, ud.userRoleRepo, ud.roleRepo, actorID, rolePermissionIDs(target)); err != nil {
+			return err
+		}
+
+		if err := ud.userRoleRepo.Reassign(ctx, id, reassignRoleID, deletedBy); err != nil {
+			return errors.ErrInternalServerError.Error()
+		}
+	}
+
 	if err := ud.roleRepo.Delete(ctx, id, deletedBy); err != nil {
 		return errors.ErrInternalServerError.Error()
 	}
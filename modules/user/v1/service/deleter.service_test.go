@@ -0,0 +1,160 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/permission"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/repository"
+	"gin-starter/modules/user/v1/service"
+	mockRepo "gin-starter/test/mock/modules/user/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserDeleterTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	userRepository     *mockRepo.MockUserRepositoryUseCase
+	userRoleRepository *mockRepo.MockUserRoleRepositoryUseCase
+	roleRepository     *mockRepo.MockRoleRepositoryUseCase
+	userDeleter        *service.UserDeleter
+	userTrash          *service.UserTrash
+}
+
+func TestUserDeleterTestSuite(t *testing.T) {
+	suite.Run(t, new(UserDeleterTestSuite))
+}
+
+func (suite *UserDeleterTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.userRepository = mockRepo.NewMockUserRepositoryUseCase(suite.mockCtrl)
+	suite.userRoleRepository = mockRepo.NewMockUserRoleRepositoryUseCase(suite.mockCtrl)
+	suite.roleRepository = mockRepo.NewMockRoleRepositoryUseCase(suite.mockCtrl)
+
+	cfg := config.Config{}
+	suite.userDeleter = service.NewUserDeleter(cfg, suite.userRepository, suite.userRoleRepository, suite.roleRepository)
+	suite.userTrash = service.NewUserTrash(cfg, suite.userRepository, suite.roleRepository)
+}
+
+func (suite *UserDeleterTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+func (suite *UserDeleterTestSuite) TestUserDeleter_DeleteRole() {
+	ctx := context.Background()
+	roleID := uuid.New()
+	targetID := uuid.New()
+	deletedBy := uuid.NewString()
+
+	suite.Run("successfully delete an unused role", func() {
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(&entity.Role{ID: roleID}, nil)
+		suite.userRoleRepository.EXPECT().CountByRoleID(ctx, roleID).Return(int64(0), nil)
+		suite.roleRepository.EXPECT().Delete(ctx, roleID, deletedBy).Return(nil)
+
+		suite.Nil(suite.userDeleter.DeleteRole(ctx, roleID, uuid.Nil, deletedBy))
+	})
+
+	suite.Run("fail to delete a role still assigned to users", func() {
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(&entity.Role{ID: roleID}, nil)
+		suite.userRoleRepository.EXPECT().CountByRoleID(ctx, roleID).Return(int64(2), nil)
+
+		suite.Equal(errors.ErrRoleHasUsers.Error(), suite.userDeleter.DeleteRole(ctx, roleID, uuid.Nil, deletedBy))
+	})
+
+	suite.Run("successfully delete a role after reassigning its users", func() {
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(&entity.Role{ID: roleID}, nil)
+		suite.userRoleRepository.EXPECT().CountByRoleID(ctx, roleID).Return(int64(2), nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, targetID).Return(&entity.Role{ID: targetID}, nil)
+		suite.userRoleRepository.EXPECT().Reassign(ctx, roleID, targetID, deletedBy).Return(nil)
+		suite.roleRepository.EXPECT().Delete(ctx, roleID, deletedBy).Return(nil)
+
+		suite.Nil(suite.userDeleter.DeleteRole(ctx, roleID, targetID, deletedBy))
+	})
+
+	suite.Run("fail to reassign users to a missing role", func() {
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(&entity.Role{ID: roleID}, nil)
+		suite.userRoleRepository.EXPECT().CountByRoleID(ctx, roleID).Return(int64(2), nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, targetID).Return(nil, nil)
+
+		suite.Equal(errors.ErrRecordNotFound.Error(), suite.userDeleter.DeleteRole(ctx, roleID, targetID, deletedBy))
+	})
+	suite.Run("fail to delete a system role", func() {
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(&entity.Role{ID: roleID, Name: permission.SuperAdminRole, System: true}, nil)
+
+		suite.Equal(errors.ErrSystemRoleProtected.Error(), suite.userDeleter.DeleteRole(ctx, roleID, uuid.Nil, deletedBy))
+	})
+}
+
+func (suite *UserDeleterTestSuite) TestUserDeleter_DeleteAdmin() {
+	ctx := context.Background()
+	userID := uuid.New()
+	roleID := uuid.New()
+	deletedBy := uuid.NewString()
+	superAdmin := &entity.Role{ID: roleID, Name: permission.SuperAdminRole, System: true}
+
+	suite.Run("successfully delete a super admin which is not the last one", func() {
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(&entity.User{ID: userID, Status: entity.UserStatusActivated}, nil)
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, userID).Return(&entity.UserRole{UserID: userID, RoleID: roleID}, nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(superAdmin, nil)
+		suite.userRoleRepository.EXPECT().CountActivatedByRoleID(ctx, roleID).Return(int64(2), nil)
+		suite.userRepository.EXPECT().DeleteAdmin(ctx, userID, deletedBy).Return(nil)
+
+		suite.Nil(suite.userDeleter.DeleteAdmin(ctx, userID, deletedBy))
+	})
+
+	suite.Run("fail to delete the last active super admin", func() {
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(&entity.User{ID: userID, Status: entity.UserStatusActivated}, nil)
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, userID).Return(&entity.UserRole{UserID: userID, RoleID: roleID}, nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(superAdmin, nil)
+		suite.userRoleRepository.EXPECT().CountActivatedByRoleID(ctx, roleID).Return(int64(1), nil)
+
+		suite.Equal(errors.ErrLastSuperAdmin.Error(), suite.userDeleter.DeleteAdmin(ctx, userID, deletedBy))
+	})
+
+	suite.Run("fail to delete a super admin when the other one was removed meanwhile", func() {
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(&entity.User{ID: userID, Status: entity.UserStatusActivated}, nil)
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, userID).Return(&entity.UserRole{UserID: userID, RoleID: roleID}, nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(superAdmin, nil)
+		suite.userRoleRepository.EXPECT().CountActivatedByRoleID(ctx, roleID).Return(int64(2), nil)
+		suite.userRepository.EXPECT().DeleteAdmin(ctx, userID, deletedBy).Return(pkgErrors.Wrap(repository.ErrLastSuperAdmin, "delete"))
+
+		suite.Equal(errors.ErrLastSuperAdmin.Error(), suite.userDeleter.DeleteAdmin(ctx, userID, deletedBy))
+	})
+}
+
+func (suite *UserDeleterTestSuite) TestUserTrash_RestoreUser() {
+	ctx := context.Background()
+	userID := uuid.New()
+	roleID := uuid.New()
+	restoredBy := uuid.NewString()
+	user := &entity.User{ID: userID, UserRole: &entity.UserRole{UserID: userID, RoleID: roleID}}
+
+	suite.Run("successfully restore a deleted admin", func() {
+		suite.userRepository.EXPECT().GetDeletedUserByID(ctx, userID).Return(user, nil)
+		suite.roleRepository.EXPECT().FindDeletedByID(ctx, roleID).Return(nil, nil)
+		suite.userRepository.EXPECT().Restore(ctx, userID, restoredBy).Return(nil)
+
+		suite.Nil(suite.userTrash.RestoreUser(ctx, userID, restoredBy))
+	})
+
+	suite.Run("fail to restore an admin whose role is deleted", func() {
+		suite.userRepository.EXPECT().GetDeletedUserByID(ctx, userID).Return(user, nil)
+		suite.roleRepository.EXPECT().FindDeletedByID(ctx, roleID).Return(&entity.Role{ID: roleID}, nil)
+
+		suite.Equal(errors.ErrRoleInTrash.Error(), suite.userTrash.RestoreUser(ctx, userID, restoredBy))
+	})
+
+	suite.Run("fail to restore a user missing from the trash", func() {
+		suite.userRepository.EXPECT().GetDeletedUserByID(ctx, userID).Return(nil, nil)
+
+		suite.Equal(errors.ErrRecordNotFound.Error(), suite.userTrash.RestoreUser(ctx, userID, restoredBy))
+	})
+}
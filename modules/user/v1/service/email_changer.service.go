@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	goErrors "errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gin-starter/common/constant"
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/repository"
+	"gin-starter/utils"
+)
+
+const (
+	emailChangeConfirmTemplate = "./template/email/email_change_confirm.html"
+	emailChangeNoticeTemplate  = "./template/email/email_change_notice.html"
+	// emailChangeTokenSize is the number of random bytes of an email change token
+	emailChangeTokenSize = 32
+)
+
+// UserEmailChanger is a service for the email change requests of users
+type UserEmailChanger struct {
+	cfg             config.Config
+	userRepo        repository.UserRepositoryUseCase
+	emailChangeRepo repository.EmailChangeRepositoryUseCase
+}
+
+// UserEmailChangerUseCase is a use case for the email change requests of users
+type UserEmailChangerUseCase interface {
+	// RequestEmailChange mails a confirmation link to the new address and a cancellation link to the current one
+	RequestEmailChange(ctx context.Context, userID uuid.UUID, newEmail string) (*entity.EmailChange, error)
+	// ConfirmEmailChange replaces the email of the user with the confirmed address
+	ConfirmEmailChange(ctx context.Context, token string) error
+	// CancelEmailChange cancels a pending email change from the link mailed to the current address
+	CancelEmailChange(ctx context.Context, token string) error
+}
+
+// NewUserEmailChanger is a constructor for UserEmailChanger
+func NewUserEmailChanger(
+	cfg config.Config,
+	userRepo repository.UserRepositoryUseCase,
+	emailChangeRepo repository.EmailChangeRepositoryUseCase,
+) *UserEmailChanger {
+	return &UserEmailChanger{
+		cfg:             cfg,
+		userRepo:        userRepo,
+		emailChangeRepo: emailChangeRepo,
+	}
+}
+
+// RequestEmailChange mails a confirmation link to the new address and a cancellation link to the current one.
+// The uniqueness of the new address is only checked on confirmation so the request does not reveal registered emails.
+func (ec *UserEmailChanger) RequestEmailChange(ctx context.Context, userID uuid.UUID, newEmail string) (*entity.EmailChange, error) {
+	user, err := ec.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if user == nil {
+		return nil, errors.ErrRecordNotFound.Error()
+	}
+
+	newEmail = utils.NormalizeEmail(newEmail)
+	if strings.EqualFold(newEmail, user.Email) {
+		return nil, errors.ErrEmailUnchanged.Error()
+	}
+
+	confirmToken, err := newEmailChangeToken()
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	cancelToken, err := newEmailChangeToken()
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	change := entity.NewEmailChange(
+		uuid.New(),
+		user.ID,
+		user.Email,
+		newEmail,
+		hashEmailChangeToken(confirmToken),
+		hashEmailChangeToken(cancelToken),
+		time.Now().Add(time.Duration(ec.cfg.EmailChange.ExpiryHours)*time.Hour),
+		user.ID.String(),
+	)
+
+	if err := ec.emailChangeRepo.Create(ctx, change); err != nil {
+		log.Println("[UserEmailChanger-RequestEmailChange]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	expiresAt := change.ExpiresAt.Format(constant.DefaultTimeFormat)
+
+	ec.send(ctx, emailChangeConfirmTemplate, change.NewEmail, "Confirm Your New Email", "email-change-confirm", map[string]interface{}{
+		"Name":      user.Name,
+		"URL":       fmt.Sprintf("%s?token=%s", ec.cfg.URL.EmailChangeConfirmURL, url.QueryEscape(confirmToken)),
+		"ExpiresAt": expiresAt,
+	})
+
+	ec.send(ctx, emailChangeNoticeTemplate, change.OldEmail, "Email Change Requested", "email-change-notice", map[string]interface{}{
+		"Name":      user.Name,
+		"NewEmail":  change.NewEmail,
+		"URL":       fmt.Sprintf("%s?token=%s", ec.cfg.URL.EmailChangeCancelURL, url.QueryEscape(cancelToken)),
+		"ExpiresAt": expiresAt,
+	})
+
+	return change, nil
+}
+
+// ConfirmEmailChange replaces the email of the user with the confirmed address
+func (ec *UserEmailChanger) ConfirmEmailChange(ctx context.Context, token string) error {
+	change, err := ec.emailChangeRepo.FindByConfirmTokenHash(ctx, hashEmailChangeToken(token))
+	if err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if err := checkEmailChangePending(change); err != nil {
+		return err
+	}
+
+	if !time.Now().Before(change.ExpiresAt) {
+		return errors.ErrEmailChangeExpired.Error()
+	}
+
+	// another user may have taken the address since the change was requested
+	if err := checkEmailAvailable(ctx, ec.userRepo, change.NewEmail, change.UserID); err != nil {
+		return err
+	}
+
+	if err := ec.emailChangeRepo.Confirm(ctx, change); err != nil {
+		log.Println("[UserEmailChanger-ConfirmEmailChange]", err)
+		if goErrors.Is(err, repository.ErrEmailConflict) {
+			return errors.ErrEmailAlreadyUsed.Error()
+		}
+
+		return errors.ErrInternalServerError.Error()
+	}
+
+	return nil
+}
+
+// CancelEmailChange cancels a pending email change from the link mailed to the current address
+func (ec *UserEmailChanger) CancelEmailChange(ctx context.Context, token string) error {
+	change, err := ec.emailChangeRepo.FindByCancelTokenHash(ctx, hashEmailChangeToken(token))
+	if err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if err := checkEmailChangePending(change); err != nil {
+		return err
+	}
+
+	if err := ec.emailChangeRepo.Cancel(ctx, change); err != nil {
+		log.Println("[UserEmailChanger-CancelEmailChange]", err)
+		return errors.ErrInternalServerError.Error()
+	}
+
+	return nil
+}
+
+// send mails a link of the change, a failure is only logged since the user can request the change again
+func (ec *UserEmailChanger) send(ctx context.Context, templatePath, to, subject, category string, data map[string]interface{}) {
+	payload, err := utils.ConstructEmailPayload(templatePath, to, subject, category, data)
+	if err != nil {
+		log.Println("[UserEmailChanger-send]", err)
+		return
+	}
+
+	if err := utils.SendTopic(ctx, ec.cfg, constant.SendEmailTopic, payload); err != nil {
+		log.Println("[UserEmailChanger-send]", err)
+	}
+}
+
+func checkEmailChangePending(change *entity.EmailChange) error {
+	if change == nil {
+		return errors.ErrInvalidEmailChange.Error()
+	}
+
+	if change.Status != entity.EmailChangeStatusPending {
+		return errors.ErrEmailChangeNotPending.Error()
+	}
+
+	return nil
+}
+
+func newEmailChangeToken() (string, error) {
+	b := make([]byte, emailChangeTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashEmailChangeToken hashes a token the way it is stored, a leaked table does not expose usable links
+func hashEmailChangeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
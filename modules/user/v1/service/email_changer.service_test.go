@@ -0,0 +1,146 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/service"
+	mockRepo "gin-starter/test/mock/modules/user/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserEmailChangerTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	userRepository        *mockRepo.MockUserRepositoryUseCase
+	emailChangeRepository *mockRepo.MockEmailChangeRepositoryUseCase
+	emailChanger          *service.UserEmailChanger
+}
+
+func TestUserEmailChangerTestSuite(t *testing.T) {
+	suite.Run(t, new(UserEmailChangerTestSuite))
+}
+
+func (suite *UserEmailChangerTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.userRepository = mockRepo.NewMockUserRepositoryUseCase(suite.mockCtrl)
+	suite.emailChangeRepository = mockRepo.NewMockEmailChangeRepositoryUseCase(suite.mockCtrl)
+
+	cfg := config.Config{}
+	cfg.EmailChange.ExpiryHours = 24
+
+	suite.emailChanger = service.NewUserEmailChanger(cfg, suite.userRepository, suite.emailChangeRepository)
+}
+
+func (suite *UserEmailChangerTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (suite *UserEmailChangerTestSuite) TestUserEmailChanger_RequestEmailChange() {
+	ctx := context.Background()
+	user := &entity.User{ID: uuid.New(), Email: "old@example.com"}
+
+	suite.Run("successfully store the pending address without changing the email", func() {
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+		suite.emailChangeRepository.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		change, err := suite.emailChanger.RequestEmailChange(ctx, user.ID, " New@Example.com ")
+
+		suite.Nil(err)
+		suite.Equal("new@example.com", change.NewEmail)
+		suite.Equal("old@example.com", change.OldEmail)
+		suite.NotEqual(change.ConfirmTokenHash, change.CancelTokenHash)
+		suite.Equal("old@example.com", user.Email)
+	})
+
+	suite.Run("fail to request the current email in another case", func() {
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+
+		_, err := suite.emailChanger.RequestEmailChange(ctx, user.ID, "OLD@example.com")
+		suite.Equal(errors.ErrEmailUnchanged.Error(), err)
+	})
+
+	suite.Run("successfully request an email another user owns without revealing it", func() {
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+		suite.emailChangeRepository.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		change, err := suite.emailChanger.RequestEmailChange(ctx, user.ID, "TAKEN@example.com")
+
+		suite.Nil(err)
+		suite.Equal("taken@example.com", change.NewEmail)
+	})
+}
+
+func (suite *UserEmailChangerTestSuite) TestUserEmailChanger_ConfirmEmailChange() {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	newChange := func() *entity.EmailChange {
+		return entity.NewEmailChange(uuid.New(), userID, "old@example.com", "new@example.com", hashToken("confirm"), hashToken("cancel"), time.Now().Add(time.Hour), userID.String())
+	}
+
+	suite.Run("successfully swap the email after confirmation", func() {
+		change := newChange()
+		suite.emailChangeRepository.EXPECT().FindByConfirmTokenHash(ctx, hashToken("confirm")).Return(change, nil)
+		suite.userRepository.EXPECT().GetUsersByEmails(ctx, []string{"new@example.com"}).Return([]*entity.User{}, nil)
+		suite.emailChangeRepository.EXPECT().Confirm(ctx, change).Return(nil)
+
+		suite.Nil(suite.emailChanger.ConfirmEmailChange(ctx, "confirm"))
+	})
+
+	suite.Run("fail to confirm an address another user took in the meantime", func() {
+		change := newChange()
+		suite.emailChangeRepository.EXPECT().FindByConfirmTokenHash(ctx, hashToken("confirm")).Return(change, nil)
+		suite.userRepository.EXPECT().GetUsersByEmails(ctx, []string{"new@example.com"}).Return([]*entity.User{{ID: uuid.New(), Email: "New@Example.com"}}, nil)
+
+		suite.Equal(errors.ErrEmailAlreadyUsed.Error(), suite.emailChanger.ConfirmEmailChange(ctx, "confirm"))
+	})
+
+	suite.Run("fail to confirm an expired change", func() {
+		change := newChange()
+		change.ExpiresAt = time.Now().Add(-time.Minute)
+		suite.emailChangeRepository.EXPECT().FindByConfirmTokenHash(ctx, hashToken("confirm")).Return(change, nil)
+
+		suite.Equal(errors.ErrEmailChangeExpired.Error(), suite.emailChanger.ConfirmEmailChange(ctx, "confirm"))
+	})
+
+	suite.Run("fail to confirm a cancelled change", func() {
+		change := newChange()
+		change.Status = entity.EmailChangeStatusCancelled
+		suite.emailChangeRepository.EXPECT().FindByConfirmTokenHash(ctx, hashToken("confirm")).Return(change, nil)
+
+		suite.Equal(errors.ErrEmailChangeNotPending.Error(), suite.emailChanger.ConfirmEmailChange(ctx, "confirm"))
+	})
+
+	suite.Run("fail to confirm an unknown token", func() {
+		suite.emailChangeRepository.EXPECT().FindByConfirmTokenHash(ctx, hashToken("unknown")).Return(nil, nil)
+
+		suite.Equal(errors.ErrInvalidEmailChange.Error(), suite.emailChanger.ConfirmEmailChange(ctx, "unknown"))
+	})
+}
+
+func (suite *UserEmailChangerTestSuite) TestUserEmailChanger_CancelEmailChange() {
+	ctx := context.Background()
+	userID := uuid.New()
+	change := entity.NewEmailChange(uuid.New(), userID, "old@example.com", "new@example.com", hashToken("confirm"), hashToken("cancel"), time.Now().Add(time.Hour), userID.String())
+
+	suite.emailChangeRepository.EXPECT().FindByCancelTokenHash(ctx, hashToken("cancel")).Return(change, nil)
+	suite.emailChangeRepository.EXPECT().Cancel(ctx, change).Return(nil)
+
+	suite.Nil(suite.emailChanger.CancelEmailChange(ctx, "cancel"))
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"gin-starter/common/constant"
+	"gin-starter/common/errors"
+	"gin-starter/common/query"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/repository"
+)
+
+const (
+	// ExportFormatCSV is the csv export format
+	ExportFormatCSV = "csv"
+	// ExportFormatXLSX is the xlsx export format
+	ExportFormatXLSX = "xlsx"
+
+	exportSheetName = "Sheet1"
+)
+
+// exportColumn is a column of an export file
+type exportColumn struct {
+	header string
+	value  func(user *entity.UserExport) string
+}
+
+// exportColumns lists the columns which can be exported.
+// Credentials such as password, otp and forgot password token are never exportable.
+var exportColumns = map[string]*exportColumn{
+	"name":       {header: "Name", value: func(u *entity.UserExport) string { return u.Name }},
+	"email":      {header: "Email", value: func(u *entity.UserExport) string { return u.Email }},
+	"phone":      {header: "Phone", value: func(u *entity.UserExport) string { return u.PhoneNumber }},
+	"status":     {header: "Status", value: func(u *entity.UserExport) string { return u.Status }},
+	"role":       {header: "Role", value: func(u *entity.UserExport) string { return u.Role }},
+	"created_at": {header: "Created At", value: func(u *entity.UserExport) string { return u.CreatedAt.Format(constant.DefaultTimeFormat) }},
+}
+
+// defaultExportColumns is the column set used when none is selected
+var defaultExportColumns = []string{"name", "email", "phone", "status", "role", "created_at"}
+
+// UserExporter is a service for exporting users
+type UserExporter struct {
+	cfg      config.Config
+	userRepo repository.UserRepositoryUseCase
+}
+
+// UserExporterUseCase is a use case for exporting users
+type UserExporterUseCase interface {
+	// ExportUsers writes the users or admins matching the filter to w as csv or xlsx
+	ExportUsers(ctx context.Context, w io.Writer, admin bool, format string, columns []string, q *query.Query) error
+}
+
+// NewUserExporter is a constructor for the User exporter
+func NewUserExporter(
+	cfg config.Config,
+	userRepo repository.UserRepositoryUseCase,
+) *UserExporter {
+	return &UserExporter{
+		cfg:      cfg,
+		userRepo: userRepo,
+	}
+}
+
+// ExportUsers writes the users or admins matching the filter to w as csv or xlsx.
+// Nothing is written to w when the format or a column is invalid.
+func (ue *UserExporter) ExportUsers(ctx context.Context, w io.Writer, admin bool, format string, columns []string, q *query.Query) error {
+	selected, err := selectExportColumns(columns)
+	if err != nil {
+		return err
+	}
+
+	schema := repository.UserListSchema
+	if admin {
+		schema = repository.AdminListSchema
+	}
+
+	if err := q.Validate(schema); err != nil {
+		return err
+	}
+
+	switch format {
+	case ExportFormatCSV:
+		err = ue.exportCSV(ctx, w, admin, selected, q)
+	case ExportFormatXLSX:
+		err = ue.exportXLSX(ctx, w, admin, selected, q)
+	default:
+		return errors.ErrUnsupportedExportFormat.Error()
+	}
+
+	if err != nil {
+		log.Println("[UserExporter-ExportUsers]", err)
+		return errors.ErrInternalServerError.Error()
+	}
+
+	return nil
+}
+
+// exportCSV streams the users as csv, flushing every hundred rows
+func (ue *UserExporter) exportCSV(ctx context.Context, w io.Writer, admin bool, columns []*exportColumn, q *query.Query) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(exportHeaders(columns)); err != nil {
+		return err
+	}
+
+	count := 0
+	err := ue.userRepo.ExportUsers(ctx, admin, q, func(user *entity.UserExport) error {
+		record := make([]string, 0, len(columns))
+		for _, column := range columns {
+			record = append(record, EscapeCSVFormula(column.value(user)))
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+
+		count++
+		if count%constant.Hundred == 0 {
+			writer.Flush()
+			return writer.Error()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// exportXLSX streams the users into a xlsx worksheet.
+// The stream writer spills rows to a temporary file so large exports stay out of memory.
+func (ue *UserExporter) exportXLSX(ctx context.Context, w io.Writer, admin bool, columns []*exportColumn, q *query.Query) error {
+	f := excelize.NewFile()
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.Println("[UserExporter-exportXLSX] error when closing file:", err)
+		}
+	}()
+
+	sw, err := f.NewStreamWriter(exportSheetName)
+	if err != nil {
+		return err
+	}
+
+	headers := exportHeaders(columns)
+	values := make([]interface{}, len(headers))
+	for i, header := range headers {
+		values[i] = header
+	}
+
+	if err := sw.SetRow("A1", values); err != nil {
+		return err
+	}
+
+	row := 1
+	err = ue.userRepo.ExportUsers(ctx, admin, q, func(user *entity.UserExport) error {
+		row++
+
+		cell, err := excelize.CoordinatesToCellName(1, row)
+		if err != nil {
+			return err
+		}
+
+		values := make([]interface{}, 0, len(columns))
+		for _, column := range columns {
+			values = append(values, column.value(user))
+		}
+
+		return sw.SetRow(cell, values)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := sw.Flush(); err != nil {
+		return err
+	}
+
+	return f.Write(w)
+}
+
+// selectExportColumns resolves the selected column names, keeping their order
+func selectExportColumns(names []string) ([]*exportColumn, error) {
+	if len(names) == 0 {
+		names = defaultExportColumns
+	}
+
+	columns := make([]*exportColumn, 0, len(names))
+	seen := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+
+		column, ok := exportColumns[name]
+		if !ok {
+			return nil, errors.ErrInvalidExportColumn.Error()
+		}
+
+		seen[name] = true
+		columns = append(columns, column)
+	}
+
+	if len(columns) == 0 {
+		return nil, errors.ErrInvalidExportColumn.Error()
+	}
+
+	return columns, nil
+}
+
+// exportHeaders returns the header row of the columns
+func exportHeaders(columns []*exportColumn) []string {
+	headers := make([]string, 0, len(columns))
+	for _, column := range columns {
+		headers = append(headers, column.header)
+	}
+
+	return headers
+}
+
+// EscapeCSVFormula prevents spreadsheet applications from evaluating a value as a formula.
+// Phone numbers such as +62812... are left untouched.
+func EscapeCSVFormula(value string) string {
+	if value == "" {
+		return value
+	}
+
+	switch value[0] {
+	case '=', '@', '\t', '\r':
+		return "'" + value
+	case '+', '-':
+		if len(value) > 1 && (value[1] < '0' || value[1] > '9') {
+			return "'" + value
+		}
+	}
+
+	return value
+}
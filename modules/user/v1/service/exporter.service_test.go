@@ -0,0 +1,121 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/query"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/service"
+	mockRepo "gin-starter/test/mock/modules/user/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+	"github.com/xuri/excelize/v2"
+)
+
+type UserExporterTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	userRepository *mockRepo.MockUserRepositoryUseCase
+	userExporter   *service.UserExporter
+}
+
+func TestUserExporterTestSuite(t *testing.T) {
+	suite.Run(t, new(UserExporterTestSuite))
+}
+
+func (suite *UserExporterTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.userRepository = mockRepo.NewMockUserRepositoryUseCase(suite.mockCtrl)
+	suite.userExporter = service.NewUserExporter(config.Config{}, suite.userRepository)
+}
+
+func (suite *UserExporterTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+func (suite *UserExporterTestSuite) streamUsers(q *query.Query, users ...*entity.UserExport) {
+	suite.userRepository.EXPECT().
+		ExportUsers(gomock.Any(), true, q, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ bool, _ *query.Query, fn func(*entity.UserExport) error) error {
+			for _, u := range users {
+				if err := fn(u); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+}
+
+func (suite *UserExporterTestSuite) TestUserExporter_ExportUsers() {
+	createdAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	john := &entity.UserExport{Name: "John", Email: "john@example.com", PhoneNumber: "+628123456789", Status: "ACTIVATED", Role: "Admin", CreatedAt: createdAt}
+	formula := &entity.UserExport{Name: "=HYPERLINK(\"x\")", Email: "evil@example.com", CreatedAt: createdAt}
+	q := &query.Query{Search: "john", Sorts: []query.Sort{{Field: "name"}}}
+
+	suite.Run("successfully export selected columns as csv", func() {
+		suite.streamUsers(q, john, formula)
+
+		var buf bytes.Buffer
+		err := suite.userExporter.ExportUsers(context.Background(), &buf, true, service.ExportFormatCSV, []string{"name", "phone", "created_at"}, q)
+
+		suite.Nil(err)
+		suite.Equal("Name,Phone,Created At\nJohn,+628123456789,2026-10-19 09:00:00\n\"'=HYPERLINK(\"\"x\"\")\",,2026-10-19 09:00:00\n", buf.String())
+	})
+
+	suite.Run("successfully export default columns as xlsx", func() {
+		suite.streamUsers(q, john)
+
+		var buf bytes.Buffer
+		err := suite.userExporter.ExportUsers(context.Background(), &buf, true, service.ExportFormatXLSX, nil, q)
+		suite.Nil(err)
+
+		f, err := excelize.OpenReader(&buf)
+		suite.Nil(err)
+
+		rows, err := f.GetRows("Sheet1", excelize.Options{RawCellValue: true})
+		suite.Nil(err)
+		suite.Equal([][]string{
+			{"Name", "Email", "Phone", "Status", "Role", "Created At"},
+			{"John", "john@example.com", "+628123456789", "ACTIVATED", "Admin", "2026-10-19 09:00:00"},
+		}, rows)
+	})
+
+	suite.Run("fail to export a credential column", func() {
+		var buf bytes.Buffer
+		err := suite.userExporter.ExportUsers(context.Background(), &buf, false, service.ExportFormatCSV, []string{"name", "password"}, nil)
+
+		suite.Equal(errors.ErrInvalidExportColumn.Error(), err)
+		suite.Zero(buf.Len())
+	})
+
+	suite.Run("fail to export an unsupported format", func() {
+		var buf bytes.Buffer
+		err := suite.userExporter.ExportUsers(context.Background(), &buf, false, "pdf", nil, nil)
+
+		suite.Equal(errors.ErrUnsupportedExportFormat.Error(), err)
+		suite.Zero(buf.Len())
+	})
+
+	suite.Run("fail to export sorted by a field that is not sortable", func() {
+		var buf bytes.Buffer
+		err := suite.userExporter.ExportUsers(context.Background(), &buf, true, service.ExportFormatCSV, nil, &query.Query{Sorts: []query.Sort{{Field: "password"}}})
+
+		suite.Equal(errors.ErrInvalidSort.Error(), err)
+		suite.Zero(buf.Len())
+	})
+}
+
+func (suite *UserExporterTestSuite) TestEscapeCSVFormula() {
+	suite.Equal(`'=HYPERLINK("http://example.com")`, service.EscapeCSVFormula(`=HYPERLINK("http://example.com")`))
+	suite.Equal("'@SUM(A1)", service.EscapeCSVFormula("@SUM(A1)"))
+	suite.Equal("'-cmd", service.EscapeCSVFormula("-cmd"))
+	suite.Equal("+6281234567890", service.EscapeCSVFormula("+6281234567890"))
+	suite.Equal("Budi", service.EscapeCSVFormula("Budi"))
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"gin-starter/common/errors"
+	"gin-starter/common/query"
 	"gin-starter/config"
 	"gin-starter/entity"
 	"gin-starter/modules/user/v1/repository"
@@ -22,11 +23,11 @@ type UserFinder struct {
 // UserFinderUseCase is a usecase for user
 type UserFinderUseCase interface {
 	// GetUsers gets all users
-	GetUsers(ctx context.Context, query, order, sort string, limit, offset int) ([]*entity.User, int64, error)
+	GetUsers(ctx context.Context, q *query.Query) ([]*entity.User, *query.Page, error)
 	// GetUserByID gets a user by ID
 	GetUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
 	// GetAdminUsers gets all admin users
-	GetAdminUsers(ctx context.Context, query, order, sort string, limit, offset int) ([]*entity.User, int64, error)
+	GetAdminUsers(ctx context.Context, q *query.Query) ([]*entity.User, *query.Page, error)
 	// GetAdminUserByID gets a admin user by ID
 	GetAdminUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
 	// GetUserByEmail gets user by email
@@ -34,7 +35,7 @@ type UserFinderUseCase interface {
 	// GetUserByForgotPasswordToken gets user by forgot password token
 	GetUserByForgotPasswordToken(ctx context.Context, token string) (*entity.User, error)
 	// GetRoles gets all roles
-	GetRoles(ctx context.Context, query, sort, order string, limit, offset int) ([]*entity.Role, error)
+	GetRoles(ctx context.Context, q *query.Query) ([]*entity.Role, error)
 	// GetPermissions gets all permissions
 	GetPermissions(ctx context.Context) ([]*entity.Permission, error)
 	// GetUserPermissions gets all user permissions
@@ -59,25 +60,33 @@ func NewUserFinder(
 }
 
 // GetUsers gets all users
-func (uf *UserFinder) GetUsers(ctx context.Context, query, sort, order string, limit, offset int) ([]*entity.User, int64, error) {
-	users, total, err := uf.userRepo.GetUsers(ctx, query, sort, order, limit, offset)
+func (uf *UserFinder) GetUsers(ctx context.Context, q *query.Query) ([]*entity.User, *query.Page, error) {
+	if err := q.Validate(repository.UserListSchema); err != nil {
+		return nil, nil, err
+	}
+
+	users, page, err := uf.userRepo.GetUsers(ctx, q)
 
 	if err != nil {
-		return nil, 0, errors.ErrInternalServerError.Error()
+		return nil, nil, errors.ErrInternalServerError.Error()
 	}
 
-	return users, total, nil
+	return users, page, nil
 }
 
 // GetAdminUsers gets all admin users
-func (uf *UserFinder) GetAdminUsers(ctx context.Context, query, sort, order string, limit, offset int) ([]*entity.User, int64, error) {
-	users, total, err := uf.userRepo.GetAdminUsers(ctx, query, sort, order, limit, offset)
+func (uf *UserFinder) GetAdminUsers(ctx context.Context, q *query.Query) ([]*entity.User, *query.Page, error) {
+	if err := q.Validate(repository.AdminListSchema); err != nil {
+		return nil, nil, err
+	}
+
+	users, page, err := uf.userRepo.GetAdminUsers(ctx, q)
 
 	if err != nil {
-		return nil, 0, errors.ErrInternalServerError.Error()
+		return nil, nil, errors.ErrInternalServerError.Error()
 	}
 
-	return users, total, nil
+	return users, page, nil
 }
 
 // GetAdminUserByID gets a admin user by ID
@@ -133,8 +142,12 @@ func (uf *UserFinder) GetUserByForgotPasswordToken(ctx context.Context, token st
 }
 
 // GetRoles gets all roles
-func (uf *UserFinder) GetRoles(ctx context.Context, query, sort, order string, limit, offset int) ([]*entity.Role, error) {
-	roles, err := uf.roleRepo.FindAll(ctx, query, sort, order, limit, offset)
+func (uf *UserFinder) GetRoles(ctx context.Context, q *query.Query) ([]*entity.Role, error) {
+	if err := q.Validate(repository.RoleListSchema); err != nil {
+		return nil, err
+	}
+
+	roles, err := uf.roleRepo.FindAll(ctx, q)
 
 	if err != nil {
 		return nil, errors.ErrInternalServerError.Error()
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gin-starter/common/constant"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/repository"
+)
+
+// importStaleAfter is how long an import may go without a batch before another worker picks it up again
+const importStaleAfter = 10 * time.Minute
+
+// UserImportWorker creates the users of the pending imports.
+// Every instance runs a worker, each import is claimed by a single one of them.
+type UserImportWorker struct {
+	cfg            config.Config
+	userImportRepo repository.UserImportRepositoryUseCase
+}
+
+// NewUserImportWorker creates a new UserImportWorker
+func NewUserImportWorker(
+	cfg config.Config,
+	userImportRepo repository.UserImportRepositoryUseCase,
+) *UserImportWorker {
+	return &UserImportWorker{
+		cfg:            cfg,
+		userImportRepo: userImportRepo,
+	}
+}
+
+// ProcessDue claims and processes the pending imports, and the imports a stopped worker left processing.
+// The rows created before the worker stopped are not created again.
+func (iw *UserImportWorker) ProcessDue(ctx context.Context) error {
+	staleBefore := time.Now().Add(-importStaleAfter)
+
+	imports, err := iw.userImportRepo.FindDue(ctx, staleBefore, constant.Ten)
+	if err != nil {
+		return err
+	}
+
+	for _, userImport := range imports {
+		claimed, err := iw.userImportRepo.Claim(ctx, userImport.ID, staleBefore)
+		if err != nil {
+			return err
+		}
+
+		if !claimed {
+			continue
+		}
+
+		status := iw.process(ctx, userImport.ID)
+
+		if err := iw.userImportRepo.UpdateStatus(ctx, userImport.ID, status); err != nil {
+			log.Println("[UserImportWorker-ProcessDue]", err)
+		}
+	}
+
+	return nil
+}
+
+// process creates the users of the valid rows in batches and returns the status the import finished with.
+// A panic fails the import rather than stopping the worker.
+func (iw *UserImportWorker) process(ctx context.Context, id uuid.UUID) (status string) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[UserImportWorker-process] import %s panicked: %v\n%s", id, r, debug.Stack())
+			status = entity.UserImportStatusFailed
+		}
+	}()
+
+	userImport, err := iw.userImportRepo.FindByID(ctx, id)
+	if err != nil || userImport == nil {
+		log.Printf("[UserImportWorker-process] import %s: %v", id, err)
+		return entity.UserImportStatusFailed
+	}
+
+	items := make([]*repository.UserImportItem, 0, importBatchSize)
+
+	for i, row := range userImport.Rows {
+		if row.Status == entity.UserImportRowStatusValid {
+			items = append(items, &repository.UserImportItem{Row: row, User: newImportedUser(row, userImport.CreatedBy.String)})
+		}
+
+		if len(items) == importBatchSize || (i == len(userImport.Rows)-1 && len(items) > 0) {
+			if err := iw.userImportRepo.ImportBatch(ctx, userImport, items); err != nil {
+				log.Printf("[UserImportWorker-process] import %s: %v", id, err)
+				return entity.UserImportStatusFailed
+			}
+			items = items[:0]
+		}
+	}
+
+	return entity.UserImportStatusCompleted
+}
+
+// Run processes the pending imports periodically until the context is done
+func (iw *UserImportWorker) Run(ctx context.Context) {
+	interval, err := time.ParseDuration(iw.cfg.UserImport.WorkerInterval)
+	if err != nil || interval <= 0 {
+		log.Println("[UserImportWorker-Run] invalid worker interval, imports are never processed:", iw.cfg.UserImport.WorkerInterval)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := iw.ProcessDue(ctx); err != nil {
+			log.Println("[UserImportWorker-Run]", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/repository"
+	"gin-starter/modules/user/v1/service"
+	mockRepo "gin-starter/test/mock/modules/user/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserImportWorkerTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	userImportRepository *mockRepo.MockUserImportRepositoryUseCase
+	worker               *service.UserImportWorker
+}
+
+func TestUserImportWorkerTestSuite(t *testing.T) {
+	suite.Run(t, new(UserImportWorkerTestSuite))
+}
+
+func (suite *UserImportWorkerTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.userImportRepository = mockRepo.NewMockUserImportRepositoryUseCase(suite.mockCtrl)
+
+	suite.worker = service.NewUserImportWorker(config.Config{}, suite.userImportRepository)
+}
+
+func (suite *UserImportWorkerTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+func newUserImport(statuses ...string) *entity.UserImport {
+	rows := make([]*entity.UserImportRow, 0, len(statuses))
+	for i, status := range statuses {
+		rows = append(rows, &entity.UserImportRow{ID: uuid.New(), RowNumber: i + 2, Name: "Budi", Email: uuid.NewString() + "@example.com", Status: status})
+	}
+
+	return entity.NewUserImport(uuid.New(), "users.csv", false, rows, uuid.NewString())
+}
+
+func (suite *UserImportWorkerTestSuite) TestUserImportWorker_ProcessDue() {
+	ctx := context.Background()
+
+	suite.Run("successfully create the users of the valid rows the previous worker left", func() {
+		userImport := newUserImport(entity.UserImportRowStatusCreated, entity.UserImportRowStatusValid, entity.UserImportRowStatusInvalid)
+
+		suite.userImportRepository.EXPECT().FindDue(ctx, gomock.Any(), gomock.Any()).Return([]*entity.UserImport{userImport}, nil)
+		suite.userImportRepository.EXPECT().Claim(ctx, userImport.ID, gomock.Any()).Return(true, nil)
+		suite.userImportRepository.EXPECT().FindByID(ctx, userImport.ID).Return(userImport, nil)
+		suite.userImportRepository.EXPECT().
+			ImportBatch(ctx, userImport, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *entity.UserImport, items []*repository.UserImportItem) error {
+				suite.Len(items, 1)
+				suite.Equal(userImport.Rows[1], items[0].Row)
+				suite.Equal(userImport.Rows[1].Email, items[0].User.Email)
+				return nil
+			})
+		suite.userImportRepository.EXPECT().UpdateStatus(ctx, userImport.ID, entity.UserImportStatusCompleted).Return(nil)
+
+		suite.Nil(suite.worker.ProcessDue(ctx))
+	})
+
+	suite.Run("fail the import when a batch cannot be stored", func() {
+		userImport := newUserImport(entity.UserImportRowStatusValid)
+
+		suite.userImportRepository.EXPECT().FindDue(ctx, gomock.Any(), gomock.Any()).Return([]*entity.UserImport{userImport}, nil)
+		suite.userImportRepository.EXPECT().Claim(ctx, userImport.ID, gomock.Any()).Return(true, nil)
+		suite.userImportRepository.EXPECT().FindByID(ctx, userImport.ID).Return(userImport, nil)
+		suite.userImportRepository.EXPECT().ImportBatch(ctx, userImport, gomock.Any()).Return(errors.ErrInternalServerError.Error())
+		suite.userImportRepository.EXPECT().UpdateStatus(ctx, userImport.ID, entity.UserImportStatusFailed).Return(nil)
+
+		suite.Nil(suite.worker.ProcessDue(ctx))
+	})
+
+	suite.Run("fail the import and keep the worker running when processing panics", func() {
+		userImport := newUserImport(entity.UserImportRowStatusValid)
+		next := newUserImport()
+
+		suite.userImportRepository.EXPECT().FindDue(ctx, gomock.Any(), gomock.Any()).Return([]*entity.UserImport{userImport, next}, nil)
+		suite.userImportRepository.EXPECT().Claim(ctx, userImport.ID, gomock.Any()).Return(true, nil)
+		suite.userImportRepository.EXPECT().FindByID(ctx, userImport.ID).Return(userImport, nil)
+		suite.userImportRepository.EXPECT().
+			ImportBatch(ctx, userImport, gomock.Any()).
+			DoAndReturn(func(context.Context, *entity.UserImport, []*repository.UserImportItem) error {
+				panic("unexpected row")
+			})
+		suite.userImportRepository.EXPECT().UpdateStatus(ctx, userImport.ID, entity.UserImportStatusFailed).Return(nil)
+		suite.userImportRepository.EXPECT().Claim(ctx, next.ID, gomock.Any()).Return(true, nil)
+		suite.userImportRepository.EXPECT().FindByID(ctx, next.ID).Return(next, nil)
+		suite.userImportRepository.EXPECT().UpdateStatus(ctx, next.ID, entity.UserImportStatusCompleted).Return(nil)
+
+		suite.Nil(suite.worker.ProcessDue(ctx))
+	})
+
+	suite.Run("skip an import another worker claimed", func() {
+		userImport := newUserImport(entity.UserImportRowStatusValid)
+
+		suite.userImportRepository.EXPECT().FindDue(ctx, gomock.Any(), gomock.Any()).Return([]*entity.UserImport{userImport}, nil)
+		suite.userImportRepository.EXPECT().Claim(ctx, userImport.ID, gomock.Any()).Return(false, nil)
+
+		suite.Nil(suite.worker.ProcessDue(ctx))
+	})
+}
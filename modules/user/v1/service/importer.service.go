@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/mail"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gin-starter/common/constant"
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/repository"
+	"gin-starter/utils"
+)
+
+const (
+	// maxImportRows caps the number of data rows of a single import file
+	maxImportRows = 5000
+	// importBatchSize is the number of users created per transaction
+	importBatchSize = constant.Hundred
+)
+
+// UserImporter is a service for importing users from CSV or XLSX files
+type UserImporter struct {
+	cfg            config.Config
+	userRepo       repository.UserRepositoryUseCase
+	userRoleRepo   repository.UserRoleRepositoryUseCase
+	roleRepo       repository.RoleRepositoryUseCase
+	userImportRepo repository.UserImportRepositoryUseCase
+}
+
+// UserImporterUseCase is a use case for importing users
+type UserImporterUseCase interface {
+	// PreviewImport validates every row of the file without creating anything, as imported by actorID
+	PreviewImport(ctx context.Context, filePath string, createRoles bool, actorID uuid.UUID) ([]*entity.UserImportRow, error)
+	// Import validates the file and creates the users of the valid rows in the background
+	Import(ctx context.Context, filePath, fileName string, createRoles bool, createdBy uuid.UUID) (*entity.UserImport, error)
+	// GetImportByID returns an import and its per-row results
+	GetImportByID(ctx context.Context, id uuid.UUID) (*entity.UserImport, error)
+}
+
+// NewUserImporter is a constructor for the User importer
+func NewUserImporter(
+	cfg config.Config,
+	userRepo repository.UserRepositoryUseCase,
+	userRoleRepo repository.UserRoleRepositoryUseCase,
+	roleRepo repository.RoleRepositoryUseCase,
+	userImportRepo repository.UserImportRepositoryUseCase,
+) *UserImporter {
+	return &UserImporter{
+		cfg:            cfg,
+		userRepo:       userRepo,
+		userRoleRepo:   userRoleRepo,
+		roleRepo:       roleRepo,
+		userImportRepo: userImportRepo,
+	}
+}
+
+// PreviewImport validates every row of the file without creating anything, as imported by actorID.
+// Rows assigning a role which grants permissions the actor does not hold are rejected.
+func (ui *UserImporter) PreviewImport(ctx context.Context, filePath string, createRoles bool, actorID uuid.UUID) ([]*entity.UserImportRow, error) {
+	records, err := readImportFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ui.validateRows(ctx, records, createRoles, actorID)
+}
+
+// Import validates the file and creates the users of the valid rows in the background
+func (ui *UserImporter) Import(ctx context.Context, filePath, fileName string, createRoles bool, createdBy uuid.UUID) (*entity.UserImport, error) {
+	rows, err := ui.PreviewImport(ctx, filePath, createRoles, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	userImport := entity.NewUserImport(uuid.New(), fileName, createRoles, rows, createdBy.String())
+
+	// the import worker of one of the instances claims the pending import
+	if err := ui.userImportRepo.Create(ctx, userImport); err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	return userImport, nil
+}
+
+// GetImportByID returns an import and its per-row results
+func (ui *UserImporter) GetImportByID(ctx context.Context, id uuid.UUID) (*entity.UserImport, error) {
+	userImport, err := ui.userImportRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if userImport == nil {
+		return nil, errors.ErrRecordNotFound.Error()
+	}
+
+	return userImport, nil
+}
+
+// validateRows validates the header and every data row of the file imported by actorID
+func (ui *UserImporter) validateRows(ctx context.Context, records [][]string, createRoles bool, actorID uuid.UUID) ([]*entity.UserImportRow, error) {
+	if len(records) < 2 {
+		return nil, errors.ErrInvalidImportFile.Error()
+	}
+
+	columns := make(map[string]int)
+	for i, header := range records[0] {
+		name := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(header)), " ", "_")
+		if name == "phone" {
+			name = "phone_number"
+		}
+		columns[name] = i
+	}
+
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.ErrInvalidImportFile.Error()
+	}
+
+	if _, ok := columns["email"]; !ok {
+		return nil, errors.ErrInvalidImportFile.Error()
+	}
+
+	if len(records)-1 > maxImportRows {
+		return nil, errors.ErrTooManyImportRows.Error()
+	}
+
+	roles, err := ui.roleRepo.FindAll(ctx, nil)
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	existingRoles := make(map[string]*entity.Role, len(roles))
+	for _, r := range roles {
+		existingRoles[strings.ToLower(r.Name)] = r
+	}
+
+	// whether the actor may grant a role is checked once per role
+	grantable := make(map[string]bool)
+
+	cell := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	rows := make([]*entity.UserImportRow, 0, len(records)-1)
+	seen := make(map[string]int)
+	messages := make(map[*entity.UserImportRow][]string)
+
+	for i, record := range records[1:] {
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		row := &entity.UserImportRow{
+			ID:          uuid.New(),
+			RowNumber:   i + 2,
+			Name:        cell(record, "name"),
+			Email:       strings.ToLower(cell(record, "email")),
+			PhoneNumber: cell(record, "phone_number"),
+			DOB:         cell(record, "dob"),
+			Role:        cell(record, "role"),
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+
+		var problems []string
+
+		if row.Name == "" {
+			problems = append(problems, "name is required")
+		} else if len(row.Name) > 128 {
+			problems = append(problems, "name must not exceed 128 characters")
+		}
+
+		if address, err := mail.ParseAddress(row.Email); err != nil || address.Address != row.Email || len(row.Email) > 128 {
+			problems = append(problems, "email is not a valid email address")
+		} else if first, ok := seen[row.Email]; ok {
+			problems = append(problems, fmt.Sprintf("email duplicates row %d", first))
+		} else {
+			seen[row.Email] = row.RowNumber
+		}
+
+		if phoneNumber, err := normalizeOptionalPhoneNumber(row.PhoneNumber); err != nil {
+			problems = append(problems, "phone_number is not a valid phone number")
+		} else {
+			row.PhoneNumber = phoneNumber
+		}
+
+		if row.DOB != "" {
+			if _, err := time.Parse(constant.DefaultTimeFormatShort, row.DOB); err != nil {
+				problems = append(problems, "dob must use the YYYY-MM-DD format")
+			}
+		}
+
+		if row.Role != "" {
+			key := strings.ToLower(row.Role)
+			role, ok := existingRoles[key]
+
+			if !ok && len(row.Role) > 128 {
+				problems = append(problems, "role must not exceed 128 characters")
+			} else if !ok && !createRoles {
+				problems = append(problems, fmt.Sprintf("role %q does not exist", row.Role))
+			}
+
+			if ok {
+				allowed, checked := grantable[key]
+				if !checked {
+					if allowed, err = ui.canGrant(ctx, actorID, role); err != nil {
+						return nil, err
+					}
+					grantable[key] = allowed
+				}
+
+				if !allowed {
+					problems = append(problems, fmt.Sprintf("role %q grants permissions you do not hold", row.Role))
+				}
+			}
+		}
+
+		messages[row] = problems
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.ErrInvalidImportFile.Error()
+	}
+
+	emails := make([]string, 0, len(seen))
+	for email := range seen {
+		emails = append(emails, email)
+	}
+
+	registered, err := ui.userRepo.GetUsersByEmails(ctx, emails)
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	taken := make(map[string]bool, len(registered))
+	for _, u := range registered {
+		taken[strings.ToLower(u.Email)] = true
+	}
+
+	for _, row := range rows {
+		if taken[row.Email] && seen[row.Email] == row.RowNumber {
+			messages[row] = append(messages[row], "email is already registered")
+		}
+
+		row.Status = entity.UserImportRowStatusValid
+		if len(messages[row]) > 0 {
+			row.Status = entity.UserImportRowStatusInvalid
+			row.Message = strings.Join(messages[row], "; ")
+		}
+	}
+
+	return rows, nil
+}
+
+// canGrant tells whether the actor holds every permission the role grants
+func (ui *UserImporter) canGrant(ctx context.Context, actorID uuid.UUID, role *entity.Role) (bool, error) {
+	err := checkGrantable(ctx, ui.userRoleRepo, ui.roleRepo, actorID, rolePermissionIDs(role))
+	if err == nil {
+		return true, nil
+	}
+
+	if err.Error() == errors.ErrPermissionEscalation.Error().Error() {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// newImportedUser creates the user of a valid row.
+// Imported users get an unguessable password and set their own through forgot password.
+func newImportedUser(row *entity.UserImportRow, createdBy string) *entity.User {
+	dob, _ := time.Parse(constant.DefaultTimeFormatShort, row.DOB)
+
+	return entity.NewUser(
+		uuid.New(),
+		row.Name,
+		row.Email,
+		uuid.NewString(),
+		utils.TimeToNullTime(dob),
+		"",
+		row.PhoneNumber,
+		createdBy,
+	)
+}
+
+// readImportFile reads the records of a CSV or XLSX file
+func readImportFile(filePath string) ([][]string, error) {
+	var (
+		records [][]string
+		err     error
+	)
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".csv":
+		records, err = utils.ReadCsvFile(filePath)
+	case ".xlsx":
+		records, err = utils.ReadExcelFile(filePath)
+	default:
+		return nil, errors.ErrUnsupportedImportFile.Error()
+	}
+
+	if err != nil {
+		log.Println("[UserImporter-readImportFile]", err)
+		return nil, errors.ErrInvalidImportFile.Error()
+	}
+
+	return records, nil
+}
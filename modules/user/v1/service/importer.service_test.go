@@ -0,0 +1,242 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/service"
+	mockRepo "gin-starter/test/mock/modules/user/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserImporterTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	userRepository       *mockRepo.MockUserRepositoryUseCase
+	userRoleRepository   *mockRepo.MockUserRoleRepositoryUseCase
+	roleRepository       *mockRepo.MockRoleRepositoryUseCase
+	userImportRepository *mockRepo.MockUserImportRepositoryUseCase
+	importer             *service.UserImporter
+}
+
+func TestUserImporterTestSuite(t *testing.T) {
+	suite.Run(t, new(UserImporterTestSuite))
+}
+
+func (suite *UserImporterTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.userRepository = mockRepo.NewMockUserRepositoryUseCase(suite.mockCtrl)
+	suite.userRoleRepository = mockRepo.NewMockUserRoleRepositoryUseCase(suite.mockCtrl)
+	suite.roleRepository = mockRepo.NewMockRoleRepositoryUseCase(suite.mockCtrl)
+	suite.userImportRepository = mockRepo.NewMockUserImportRepositoryUseCase(suite.mockCtrl)
+
+	suite.importer = service.NewUserImporter(config.Config{}, suite.userRepository, suite.userRoleRepository, suite.roleRepository, suite.userImportRepository)
+}
+
+func (suite *UserImporterTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+// writeCSV writes the lines to a csv file and returns its path
+func (suite *UserImporterTestSuite) writeCSV(lines ...string) string {
+	path := filepath.Join(suite.T().TempDir(), "users.csv")
+	suite.Require().NoError(os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600))
+
+	return path
+}
+
+// expectLookups expects the roles and the registered users the rows are checked against
+func (suite *UserImporterTestSuite) expectLookups(ctx context.Context, registered ...*entity.User) {
+	suite.roleRepository.EXPECT().FindAll(ctx, nil).Return([]*entity.Role{{ID: uuid.New(), Name: "Editor"}}, nil)
+	suite.userRepository.EXPECT().GetUsersByEmails(ctx, gomock.Any()).Return(registered, nil)
+}
+
+func (suite *UserImporterTestSuite) TestUserImporter_PreviewImport() {
+	ctx := context.Background()
+	actorID := uuid.New()
+
+	suite.Run("successfully validate the rows of a file with renamed and reordered columns", func() {
+		suite.expectLookups(ctx)
+
+		rows, err := suite.importer.PreviewImport(ctx, suite.writeCSV(
+			"Email,Name,Phone,DOB,Role",
+			"BUDI@example.com,Budi,0812-3456-7890,1990-01-31,editor",
+		), false, actorID)
+
+		suite.Nil(err)
+		suite.Len(rows, 1)
+		suite.Equal(entity.UserImportRowStatusValid, rows[0].Status)
+		suite.Equal("budi@example.com", rows[0].Email)
+		suite.Equal("+6281234567890", rows[0].PhoneNumber)
+		suite.Equal(2, rows[0].RowNumber)
+	})
+
+	suite.Run("fail to validate a file without the required columns", func() {
+		for _, header := range []string{"name,phone_number", "email,role"} {
+			_, err := suite.importer.PreviewImport(ctx, suite.writeCSV(header, "budi@example.com,Budi"), false, actorID)
+			suite.Equal(errors.ErrInvalidImportFile.Error(), err, header)
+		}
+	})
+
+	suite.Run("fail to validate a file without data rows", func() {
+		_, err := suite.importer.PreviewImport(ctx, suite.writeCSV("name,email"), false, actorID)
+		suite.Equal(errors.ErrInvalidImportFile.Error(), err)
+	})
+
+	suite.Run("fail to validate a file of an unsupported type", func() {
+		_, err := suite.importer.PreviewImport(ctx, filepath.Join(suite.T().TempDir(), "users.txt"), false, actorID)
+		suite.Equal(errors.ErrUnsupportedImportFile.Error(), err)
+	})
+
+	suite.Run("reject the duplicates within the file and the registered emails", func() {
+		suite.expectLookups(ctx, &entity.User{ID: uuid.New(), Email: "Sari@Example.com"})
+
+		rows, err := suite.importer.PreviewImport(ctx, suite.writeCSV(
+			"name,email",
+			"Budi,budi@example.com",
+			"Budi Again,Budi@Example.com",
+			"Sari,sari@example.com",
+		), false, actorID)
+
+		suite.Nil(err)
+		suite.Len(rows, 3)
+		suite.Equal(entity.UserImportRowStatusValid, rows[0].Status)
+		suite.Equal(entity.UserImportRowStatusInvalid, rows[1].Status)
+		suite.Equal("email duplicates row 2", rows[1].Message)
+		suite.Equal(entity.UserImportRowStatusInvalid, rows[2].Status)
+		suite.Equal("email is already registered", rows[2].Message)
+	})
+
+	suite.Run("reject the malformed dates of birth and phone numbers", func() {
+		suite.expectLookups(ctx)
+
+		rows, err := suite.importer.PreviewImport(ctx, suite.writeCSV(
+			"name,email,phone_number,dob",
+			"Budi,budi@example.com,not a number,1990-01-31",
+			"Sari,sari@example.com,,31/01/1990",
+			"Andi,andi@example.com,+6281234567890,",
+		), false, actorID)
+
+		suite.Nil(err)
+		suite.Equal("phone_number is not a valid phone number", rows[0].Message)
+		suite.Equal("dob must use the YYYY-MM-DD format", rows[1].Message)
+		suite.Equal(entity.UserImportRowStatusValid, rows[2].Status)
+	})
+
+	suite.Run("reject an unknown role unless roles are created", func() {
+		lines := []string{"name,email,role", "Budi,budi@example.com,Auditor"}
+
+		suite.expectLookups(ctx)
+		rows, err := suite.importer.PreviewImport(ctx, suite.writeCSV(lines...), false, actorID)
+
+		suite.Nil(err)
+		suite.Equal(entity.UserImportRowStatusInvalid, rows[0].Status)
+		suite.Equal(`role "Auditor" does not exist`, rows[0].Message)
+
+		suite.expectLookups(ctx)
+		rows, err = suite.importer.PreviewImport(ctx, suite.writeCSV(lines...), true, actorID)
+
+		suite.Nil(err)
+		suite.Equal(entity.UserImportRowStatusValid, rows[0].Status)
+	})
+
+	suite.Run("reject the roles granting permissions the importer does not hold", func() {
+		actorRoleID := uuid.New()
+		auditor := &entity.Role{ID: uuid.New(), Name: "Auditor", RolePermissions: []*entity.RolePermission{{PermissionID: uuid.New()}}}
+
+		suite.roleRepository.EXPECT().FindAll(ctx, nil).Return([]*entity.Role{auditor}, nil)
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, actorID).Return(&entity.UserRole{UserID: actorID, RoleID: actorRoleID}, nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, actorRoleID).Return(&entity.Role{ID: actorRoleID, Name: "Editor"}, nil)
+		suite.userRepository.EXPECT().GetUsersByEmails(ctx, gomock.Any()).Return(nil, nil)
+
+		rows, err := suite.importer.PreviewImport(ctx, suite.writeCSV(
+			"name,email,role",
+			"Budi,budi@example.com,Auditor",
+			"Sari,sari@example.com,auditor",
+			"Andi,andi@example.com,",
+		), true, actorID)
+
+		suite.Nil(err)
+		suite.Equal(entity.UserImportRowStatusInvalid, rows[0].Status)
+		suite.Equal(`role "Auditor" grants permissions you do not hold`, rows[0].Message)
+		suite.Equal(`role "auditor" grants permissions you do not hold`, rows[1].Message)
+		suite.Equal(entity.UserImportRowStatusValid, rows[2].Status)
+	})
+
+	suite.Run("fail to validate when the permissions of the importer cannot be read", func() {
+		auditor := &entity.Role{ID: uuid.New(), Name: "Auditor", RolePermissions: []*entity.RolePermission{{PermissionID: uuid.New()}}}
+
+		suite.roleRepository.EXPECT().FindAll(ctx, nil).Return([]*entity.Role{auditor}, nil)
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, actorID).Return(nil, errors.ErrInternalServerError.Error())
+
+		_, err := suite.importer.PreviewImport(ctx, suite.writeCSV("name,email,role", "Budi,budi@example.com,Auditor"), false, actorID)
+		suite.Equal(errors.ErrInternalServerError.Error(), err)
+	})
+}
+
+func (suite *UserImporterTestSuite) TestUserImporter_Import() {
+	ctx := context.Background()
+	createdBy := uuid.New()
+
+	suite.Run("successfully store a pending import for the worker", func() {
+		suite.expectLookups(ctx)
+		suite.userImportRepository.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		userImport, err := suite.importer.Import(ctx, suite.writeCSV(
+			"name,email",
+			"Budi,budi@example.com",
+			"Sari,not an email",
+		), "users.csv", false, createdBy)
+
+		suite.Nil(err)
+		suite.Equal(entity.UserImportStatusPending, userImport.Status)
+		suite.Equal(2, userImport.TotalRows)
+		suite.Equal(1, userImport.FailedRows)
+		suite.Equal(createdBy.String(), userImport.CreatedBy.String)
+	})
+
+	suite.Run("successfully store the over-long cells of the rejected rows for the report", func() {
+		long := strings.Repeat("a", 300)
+
+		suite.expectLookups(ctx)
+		suite.userImportRepository.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, userImport *entity.UserImport) error {
+				suite.Equal(long, userImport.Rows[0].Name)
+				suite.Equal(long+"@example.com", userImport.Rows[0].Email)
+				suite.Equal(long, userImport.Rows[0].PhoneNumber)
+				suite.Equal(long, userImport.Rows[0].Role)
+				return nil
+			})
+
+		userImport, err := suite.importer.Import(ctx, suite.writeCSV(
+			"name,email,phone_number,dob,role",
+			strings.Join([]string{long, long + "@example.com", long, long, long}, ","),
+		), "users.csv", true, createdBy)
+
+		suite.Nil(err)
+		suite.Equal(1, userImport.FailedRows)
+		suite.Equal(entity.UserImportRowStatusInvalid, userImport.Rows[0].Status)
+		suite.Equal("name must not exceed 128 characters; email is not a valid email address; "+
+			"phone_number is not a valid phone number; dob must use the YYYY-MM-DD format; role must not exceed 128 characters",
+			userImport.Rows[0].Message)
+	})
+
+	suite.Run("fail to import when the import cannot be stored", func() {
+		suite.expectLookups(ctx)
+		suite.userImportRepository.EXPECT().Create(ctx, gomock.Any()).Return(errors.ErrInternalServerError.Error())
+
+		_, err := suite.importer.Import(ctx, suite.writeCSV("name,email", "Budi,budi@example.com"), "users.csv", false, createdBy)
+		suite.Equal(errors.ErrInternalServerError.Error(), err)
+	})
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	goErrors "errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"gin-starter/common/constant"
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/repository"
+	"gin-starter/utils"
+)
+
+const (
+	adminInvitationTemplate = "./template/email/admin_invitation.html"
+	// nonceSize is the number of random bytes of an invitation nonce
+	nonceSize = 16
+)
+
+// AdminInviter is a service for admin invitations
+type AdminInviter struct {
+	cfg            config.Config
+	invitationRepo repository.AdminInvitationRepositoryUseCase
+	userRepo       repository.UserRepositoryUseCase
+	roleRepo       repository.RoleRepositoryUseCase
+	userRoleRepo   repository.UserRoleRepositoryUseCase
+}
+
+// AdminInviterUseCase is a use case for admin invitations
+type AdminInviterUseCase interface {
+	// Invite creates a pending admin and emails it a link to set its password
+	Invite(ctx context.Context, name, email string, roleID, invitedBy uuid.UUID) (*entity.AdminInvitation, error)
+	// Resend emails a new link for a pending invitation, the links sent before stop working
+	Resend(ctx context.Context, id, resentBy uuid.UUID) (*entity.AdminInvitation, error)
+	// Revoke withdraws a pending invitation and deletes its pending admin
+	Revoke(ctx context.Context, id, revokedBy uuid.UUID) error
+	// GetPendingInvitations gets the invitations which are neither accepted nor revoked
+	GetPendingInvitations(ctx context.Context, limit, offset int) ([]*entity.AdminInvitation, int64, error)
+	// Accept sets the password of the invited admin and activates it
+	Accept(ctx context.Context, token, password string) error
+}
+
+// NewAdminInviter is a constructor for AdminInviter
+func NewAdminInviter(
+	cfg config.Config,
+	invitationRepo repository.AdminInvitationRepositoryUseCase,
+	userRepo repository.UserRepositoryUseCase,
+	roleRepo repository.RoleRepositoryUseCase,
+	userRoleRepo repository.UserRoleRepositoryUseCase,
+) *AdminInviter {
+	return &AdminInviter{
+		cfg:            cfg,
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		userRoleRepo:   userRoleRepo,
+	}
+}
+
+// Invite creates a pending admin and emails it a link to set its password.
+// The role of the admin cannot grant permissions invitedBy does not hold.
+func (ai *AdminInviter) Invite(ctx context.Context, name, email string, roleID, invitedBy uuid.UUID) (*entity.AdminInvitation, error) {
+	role, err := ai.roleRepo.FindByID(ctx, roleID)
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if role == nil {
+		return nil, errors.ErrRecordNotFound.Error()
+	}
+
+	if err := checkGrantable(ctx, ai.userRoleRepo, ai.roleRepo, invitedBy, rolePermissionIDs(role)); err != nil {
+		return nil, err
+	}
+
+	email = utils.NormalizeEmail(email)
+
+	existing, err := ai.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if existing != nil && existing.ID != uuid.Nil {
+		return nil, errors.ErrEmailAlreadyUsed.Error()
+	}
+
+	reserved, err := ai.userRepo.IsEmailReserved(ctx, email)
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if reserved {
+		return nil, errors.ErrEmailReserved.Error()
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	// the pending admin cannot sign in until the invitee replaces this random password
+	user := entity.NewUser(
+		uuid.New(),
+		name,
+		email,
+		uuid.NewString(),
+		sql.NullTime{},
+		"",
+		"",
+		invitedBy.String(),
+	)
+	user.Status = entity.UserStatusPending
+
+	userRole := entity.NewUserRole(uuid.New(), user.ID, roleID, invitedBy.String())
+	invitation := entity.NewAdminInvitation(
+		uuid.New(),
+		user.ID,
+		name,
+		email,
+		roleID,
+		nonce,
+		time.Now().Add(ai.expiry()),
+		invitedBy.String(),
+	)
+
+	if err := ai.invitationRepo.Create(ctx, user, userRole, invitation); err != nil {
+		if goErrors.Is(err, repository.ErrEmailConflict) {
+			return nil, errors.ErrEmailAlreadyUsed.Error()
+		}
+
+		log.Println("[AdminInviter-Invite]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	invitation.Role = role
+	ai.send(ctx, invitation, invitedBy)
+
+	return invitation, nil
+}
+
+// Resend emails a new link for a pending invitation, the links sent before stop working
+func (ai *AdminInviter) Resend(ctx context.Context, id, resentBy uuid.UUID) (*entity.AdminInvitation, error) {
+	invitation, err := ai.findPending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	now := time.Now()
+	invitation.Nonce = nonce
+	invitation.ExpiresAt = now.Add(ai.expiry())
+	invitation.SentCount++
+	invitation.LastSentAt = now
+	invitation.UpdatedBy = utils.StringToNullString(resentBy.String())
+	invitation.UpdatedAt = now
+
+	if err := ai.invitationRepo.UpdateSent(ctx, invitation); err != nil {
+		log.Println("[AdminInviter-Resend]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	ai.send(ctx, invitation, resentBy)
+
+	return invitation, nil
+}
+
+// Revoke withdraws a pending invitation and deletes its pending admin
+func (ai *AdminInviter) Revoke(ctx context.Context, id, revokedBy uuid.UUID) error {
+	invitation, err := ai.findPending(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := ai.invitationRepo.Revoke(ctx, invitation, revokedBy.String()); err != nil {
+		log.Println("[AdminInviter-Revoke]", err)
+		return errors.ErrInternalServerError.Error()
+	}
+
+	return nil
+}
+
+// GetPendingInvitations gets the invitations which are neither accepted nor revoked
+func (ai *AdminInviter) GetPendingInvitations(ctx context.Context, limit, offset int) ([]*entity.AdminInvitation, int64, error) {
+	invitations, total, err := ai.invitationRepo.FindPending(ctx, limit, offset)
+	if err != nil {
+		log.Println("[AdminInviter-GetPendingInvitations]", err)
+		return nil, 0, errors.ErrInternalServerError.Error()
+	}
+
+	return invitations, total, nil
+}
+
+// Accept sets the password of the invited admin and activates it
+func (ai *AdminInviter) Accept(ctx context.Context, token, password string) error {
+	id, nonce, expiresAt, err := ai.verifyToken(token)
+	if err != nil {
+		return errors.ErrInvalidInvitation.Error()
+	}
+
+	invitation, err := ai.invitationRepo.FindByID(ctx, id)
+	if err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if invitation == nil || subtle.ConstantTimeCompare([]byte(invitation.Nonce), []byte(nonce)) != 1 {
+		return errors.ErrInvalidInvitation.Error()
+	}
+
+	if invitation.Status != entity.AdminInvitationStatusPending {
+		return errors.ErrInvitationNotPending.Error()
+	}
+
+	now := time.Now()
+	if !now.Before(expiresAt) || invitation.CurrentStatus(now) == entity.AdminInvitationStatusExpired {
+		return errors.ErrInvitationExpired.Error()
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if err := ai.invitationRepo.Accept(ctx, invitation, string(passwordHash)); err != nil {
+		log.Println("[AdminInviter-Accept]", err)
+		return errors.ErrInternalServerError.Error()
+	}
+
+	return nil
+}
+
+// Token signs the invite link of the invitation as <id>.<expiry>.<nonce>.<signature>
+func (ai *AdminInviter) Token(invitation *entity.AdminInvitation) string {
+	payload := fmt.Sprintf("%s.%d.%s", invitation.ID, invitation.ExpiresAt.Unix(), invitation.Nonce)
+
+	return payload + "." + ai.sign(payload)
+}
+
+// verifyToken checks the signature of an invite token and returns its invitation id, nonce and expiry
+func (ai *AdminInviter) verifyToken(token string) (uuid.UUID, string, time.Time, error) {
+	i := strings.LastIndex(token, ".")
+	if i < 0 {
+		return uuid.Nil, "", time.Time{}, fmt.Errorf("missing signature")
+	}
+
+	payload, signature := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(signature), []byte(ai.sign(payload))) {
+		return uuid.Nil, "", time.Time{}, fmt.Errorf("invalid signature")
+	}
+
+	parts := strings.Split(payload, ".")
+	if len(parts) != 3 {
+		return uuid.Nil, "", time.Time{}, fmt.Errorf("malformed payload")
+	}
+
+	id, err := uuid.Parse(parts[0])
+	if err != nil {
+		return uuid.Nil, "", time.Time{}, err
+	}
+
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return uuid.Nil, "", time.Time{}, err
+	}
+
+	return id, parts[2], time.Unix(expiresAt, 0), nil
+}
+
+func (ai *AdminInviter) sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(ai.cfg.Invitation.Secret))
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// send emails the invite link, a failure is only logged since the operator can resend the invitation
+func (ai *AdminInviter) send(ctx context.Context, invitation *entity.AdminInvitation, sentBy uuid.UUID) {
+	invitedBy := "An administrator"
+	if inviter, err := ai.userRepo.GetUserByID(ctx, sentBy); err == nil && inviter != nil && inviter.Name != "" {
+		invitedBy = inviter.Name
+	}
+
+	role := ""
+	if invitation.Role != nil {
+		role = invitation.Role.Name
+	}
+
+	payload, err := utils.ConstructEmailPayload(adminInvitationTemplate, invitation.Email, "Admin Invitation", "admin-invitation", map[string]interface{}{
+		"Name":      invitation.Name,
+		"InvitedBy": invitedBy,
+		"Role":      role,
+		"URL":       fmt.Sprintf("%s?token=%s", ai.cfg.URL.AdminInvitationURL, url.QueryEscape(ai.Token(invitation))),
+		"ExpiresAt": invitation.ExpiresAt.Format(constant.DefaultTimeFormat),
+	})
+	if err != nil {
+		log.Println("[AdminInviter-send]", err)
+		return
+	}
+
+	if err := utils.SendTopic(ctx, ai.cfg, constant.SendEmailTopic, payload); err != nil {
+		log.Println("[AdminInviter-send]", err)
+	}
+}
+
+// findPending finds an invitation which can still be resent or revoked
+func (ai *AdminInviter) findPending(ctx context.Context, id uuid.UUID) (*entity.AdminInvitation, error) {
+	invitation, err := ai.invitationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if invitation == nil {
+		return nil, errors.ErrRecordNotFound.Error()
+	}
+
+	if invitation.Status != entity.AdminInvitationStatusPending {
+		return nil, errors.ErrInvitationNotPending.Error()
+	}
+
+	return invitation, nil
+}
+
+func (ai *AdminInviter) expiry() time.Duration {
+	return time.Duration(ai.cfg.Invitation.ExpiryHours) * time.Hour
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, nonceSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,168 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/service"
+	mockRepo "gin-starter/test/mock/modules/user/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type AdminInviterTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	invitationRepository *mockRepo.MockAdminInvitationRepositoryUseCase
+	userRepository       *mockRepo.MockUserRepositoryUseCase
+	roleRepository       *mockRepo.MockRoleRepositoryUseCase
+	userRoleRepository   *mockRepo.MockUserRoleRepositoryUseCase
+	adminInviter         *service.AdminInviter
+}
+
+func TestAdminInviterTestSuite(t *testing.T) {
+	suite.Run(t, new(AdminInviterTestSuite))
+}
+
+func (suite *AdminInviterTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.invitationRepository = mockRepo.NewMockAdminInvitationRepositoryUseCase(suite.mockCtrl)
+	suite.userRepository = mockRepo.NewMockUserRepositoryUseCase(suite.mockCtrl)
+	suite.roleRepository = mockRepo.NewMockRoleRepositoryUseCase(suite.mockCtrl)
+	suite.userRoleRepository = mockRepo.NewMockUserRoleRepositoryUseCase(suite.mockCtrl)
+
+	cfg := config.Config{}
+	cfg.Invitation.Secret = "secret"
+	cfg.Invitation.ExpiryHours = 72
+
+	suite.adminInviter = service.NewAdminInviter(cfg, suite.invitationRepository, suite.userRepository, suite.roleRepository, suite.userRoleRepository)
+}
+
+func (suite *AdminInviterTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+func (suite *AdminInviterTestSuite) newInvitation() *entity.AdminInvitation {
+	return entity.NewAdminInvitation(uuid.New(), uuid.New(), "Admin", "admin@example.com", uuid.New(), "nonce", time.Now().Add(time.Hour), "system")
+}
+
+func (suite *AdminInviterTestSuite) TestAdminInviter_Invite() {
+	ctx := context.Background()
+	roleID := uuid.New()
+	invitedBy := uuid.New()
+
+	suite.Run("successfully create a pending admin", func() {
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(&entity.Role{ID: roleID, Name: "Editor"}, nil)
+		suite.userRepository.EXPECT().GetUserByEmail(ctx, "admin@example.com").Return(&entity.User{}, nil)
+		suite.userRepository.EXPECT().IsEmailReserved(ctx, "admin@example.com").Return(false, nil)
+		suite.invitationRepository.EXPECT().Create(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, user *entity.User, userRole *entity.UserRole, invitation *entity.AdminInvitation) error {
+				suite.Equal(entity.UserStatusPending, user.Status)
+				suite.Equal(roleID, userRole.RoleID)
+				suite.Equal(user.ID, invitation.UserID.UUID)
+				return nil
+			})
+		suite.userRepository.EXPECT().GetUserByID(ctx, invitedBy).Return(&entity.User{Name: "Operator"}, nil)
+
+		invitation, err := suite.adminInviter.Invite(ctx, "Admin", "admin@example.com", roleID, invitedBy)
+
+		suite.Nil(err)
+		suite.Equal(entity.AdminInvitationStatusPending, invitation.Status)
+	})
+
+	suite.Run("fail to invite the email of an existing user", func() {
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(&entity.Role{ID: roleID}, nil)
+		suite.userRepository.EXPECT().GetUserByEmail(ctx, "admin@example.com").Return(&entity.User{ID: uuid.New()}, nil)
+
+		_, err := suite.adminInviter.Invite(ctx, "Admin", "admin@example.com", roleID, invitedBy)
+		suite.Equal(errors.ErrEmailAlreadyUsed.Error(), err)
+	})
+	suite.Run("fail to invite with a role granting permissions the inviter does not hold", func() {
+		inviterRoleID := uuid.New()
+		permissionID := uuid.New()
+
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(&entity.Role{
+			ID:              roleID,
+			RolePermissions: []*entity.RolePermission{{PermissionID: permissionID}},
+		}, nil)
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, invitedBy).Return(&entity.UserRole{UserID: invitedBy, RoleID: inviterRoleID}, nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, inviterRoleID).Return(&entity.Role{ID: inviterRoleID, Name: "Editor"}, nil)
+
+		_, err := suite.adminInviter.Invite(ctx, "Admin", "admin@example.com", roleID, invitedBy)
+		suite.Equal(errors.ErrPermissionEscalation.Error(), err)
+	})
+}
+
+func (suite *AdminInviterTestSuite) TestAdminInviter_Accept() {
+	ctx := context.Background()
+
+	suite.Run("successfully accept an invitation", func() {
+		invitation := suite.newInvitation()
+		suite.invitationRepository.EXPECT().FindByID(ctx, invitation.ID).Return(invitation, nil)
+		suite.invitationRepository.EXPECT().Accept(ctx, invitation, gomock.Any()).Return(nil)
+
+		suite.Nil(suite.adminInviter.Accept(ctx, suite.adminInviter.Token(invitation), "password"))
+	})
+
+	suite.Run("fail to accept a tampered link", func() {
+		invitation := suite.newInvitation()
+		token := suite.adminInviter.Token(invitation)
+		invitation.ExpiresAt = invitation.ExpiresAt.Add(time.Hour)
+		forged := suite.adminInviter.Token(invitation)
+
+		suite.Equal(errors.ErrInvalidInvitation.Error(), suite.adminInviter.Accept(ctx, forged[:len(forged)-43]+token[len(token)-43:], "password"))
+	})
+
+	suite.Run("fail to accept a link superseded by a resend", func() {
+		invitation := suite.newInvitation()
+		token := suite.adminInviter.Token(invitation)
+		invitation.Nonce = "rotated"
+		suite.invitationRepository.EXPECT().FindByID(ctx, invitation.ID).Return(invitation, nil)
+
+		suite.Equal(errors.ErrInvalidInvitation.Error(), suite.adminInviter.Accept(ctx, token, "password"))
+	})
+
+	suite.Run("fail to accept an expired invitation", func() {
+		invitation := suite.newInvitation()
+		invitation.ExpiresAt = time.Now().Add(-time.Minute)
+		suite.invitationRepository.EXPECT().FindByID(ctx, invitation.ID).Return(invitation, nil)
+
+		suite.Equal(errors.ErrInvitationExpired.Error(), suite.adminInviter.Accept(ctx, suite.adminInviter.Token(invitation), "password"))
+	})
+
+	suite.Run("fail to accept a revoked invitation", func() {
+		invitation := suite.newInvitation()
+		invitation.Status = entity.AdminInvitationStatusRevoked
+		suite.invitationRepository.EXPECT().FindByID(ctx, invitation.ID).Return(invitation, nil)
+
+		suite.Equal(errors.ErrInvitationNotPending.Error(), suite.adminInviter.Accept(ctx, suite.adminInviter.Token(invitation), "password"))
+	})
+}
+
+func (suite *AdminInviterTestSuite) TestAdminInviter_Revoke() {
+	ctx := context.Background()
+	revokedBy := uuid.New()
+
+	suite.Run("successfully revoke a pending invitation", func() {
+		invitation := suite.newInvitation()
+		suite.invitationRepository.EXPECT().FindByID(ctx, invitation.ID).Return(invitation, nil)
+		suite.invitationRepository.EXPECT().Revoke(ctx, invitation, revokedBy.String()).Return(nil)
+
+		suite.Nil(suite.adminInviter.Revoke(ctx, invitation.ID, revokedBy))
+	})
+
+	suite.Run("fail to revoke an accepted invitation", func() {
+		invitation := suite.newInvitation()
+		invitation.Status = entity.AdminInvitationStatusAccepted
+		suite.invitationRepository.EXPECT().FindByID(ctx, invitation.ID).Return(invitation, nil)
+
+		suite.Equal(errors.ErrInvitationNotPending.Error(), suite.adminInviter.Revoke(ctx, invitation.ID, revokedBy))
+	})
+}
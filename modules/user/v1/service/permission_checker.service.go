@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"gin-starter/modules/user/v1/repository"
+)
+
+// PermissionChecker is a service checking the permissions of the admins on the CMS routes
+type PermissionChecker struct {
+	userRoleRepo repository.UserRoleRepositoryUseCase
+	roleRepo     repository.RoleRepositoryUseCase
+}
+
+// NewPermissionChecker is a constructor for the permission checker
+func NewPermissionChecker(
+	userRoleRepo repository.UserRoleRepositoryUseCase,
+	roleRepo repository.RoleRepositoryUseCase,
+) *PermissionChecker {
+	return &PermissionChecker{
+		userRoleRepo: userRoleRepo,
+		roleRepo:     roleRepo,
+	}
+}
+
+// HasPermission tells whether the role of the user grants the permission.
+// Super admins hold every permission, including the ones created through the CMS.
+func (pc *PermissionChecker) HasPermission(ctx context.Context, userID uuid.UUID, name string) (bool, error) {
+	userRole, err := pc.userRoleRepo.FindByUserID(ctx, userID)
+	if err != nil || userRole == nil {
+		return false, err
+	}
+
+	role, err := pc.roleRepo.FindByID(ctx, userRole.RoleID)
+	if err != nil || role == nil {
+		return false, err
+	}
+
+	if isSuperAdminRole(role) {
+		return true, nil
+	}
+
+	for _, rp := range role.RolePermissions {
+		if rp.Permission != nil && rp.Permission.Name == name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,84 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"gin-starter/common/permission"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/service"
+	mockRepo "gin-starter/test/mock/modules/user/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type PermissionCheckerTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	userRoleRepository *mockRepo.MockUserRoleRepositoryUseCase
+	roleRepository     *mockRepo.MockRoleRepositoryUseCase
+	checker            *service.PermissionChecker
+}
+
+func TestPermissionCheckerTestSuite(t *testing.T) {
+	suite.Run(t, new(PermissionCheckerTestSuite))
+}
+
+func (suite *PermissionCheckerTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.userRoleRepository = mockRepo.NewMockUserRoleRepositoryUseCase(suite.mockCtrl)
+	suite.roleRepository = mockRepo.NewMockRoleRepositoryUseCase(suite.mockCtrl)
+
+	suite.checker = service.NewPermissionChecker(suite.userRoleRepository, suite.roleRepository)
+}
+
+func (suite *PermissionCheckerTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+func (suite *PermissionCheckerTestSuite) TestPermissionChecker_HasPermission() {
+	ctx := context.Background()
+	userID := uuid.New()
+	roleID := uuid.New()
+	editor := &entity.Role{ID: roleID, Name: "Editor", RolePermissions: []*entity.RolePermission{
+		{Permission: &entity.Permission{Name: "user.view"}},
+	}}
+
+	suite.Run("successfully allow a permission the role grants", func() {
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, userID).Return(&entity.UserRole{UserID: userID, RoleID: roleID}, nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(editor, nil)
+
+		allowed, err := suite.checker.HasPermission(ctx, userID, "user.view")
+		suite.Nil(err)
+		suite.True(allowed)
+	})
+
+	suite.Run("deny a permission the role does not grant", func() {
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, userID).Return(&entity.UserRole{UserID: userID, RoleID: roleID}, nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(editor, nil)
+
+		allowed, err := suite.checker.HasPermission(ctx, userID, "admin.delete")
+		suite.Nil(err)
+		suite.False(allowed)
+	})
+
+	suite.Run("successfully allow every permission to a super admin", func() {
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, userID).Return(&entity.UserRole{UserID: userID, RoleID: roleID}, nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(&entity.Role{ID: roleID, Name: permission.SuperAdminRole, System: true}, nil)
+
+		allowed, err := suite.checker.HasPermission(ctx, userID, "admin.delete")
+		suite.Nil(err)
+		suite.True(allowed)
+	})
+
+	suite.Run("deny an admin without a role", func() {
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, userID).Return(nil, nil)
+
+		allowed, err := suite.checker.HasPermission(ctx, userID, "user.view")
+		suite.Nil(err)
+		suite.False(allowed)
+	})
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/permission"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/repository"
+)
+
+// permissionSyncActor is recorded as the author of the permissions and roles created from the registry
+const permissionSyncActor = "system"
+
+// PermissionSynchronizer is a service synchronising the permissions declared in code into the database
+type PermissionSynchronizer struct {
+	cfg            config.Config
+	registry       *permission.Registry
+	permissionRepo repository.PermissionRepositoryUseCase
+	roleRepo       repository.RoleRepositoryUseCase
+}
+
+// PermissionSynchronizerUseCase is a use case for synchronising the declared permissions
+type PermissionSynchronizerUseCase interface {
+	// Sync upserts the declared permissions and default roles and flags the orphaned permissions
+	Sync(ctx context.Context) ([]*entity.Permission, error)
+}
+
+// NewPermissionSynchronizer creates a new PermissionSynchronizer
+func NewPermissionSynchronizer(
+	cfg config.Config,
+	registry *permission.Registry,
+	permissionRepo repository.PermissionRepositoryUseCase,
+	roleRepo repository.RoleRepositoryUseCase,
+) *PermissionSynchronizer {
+	return &PermissionSynchronizer{
+		cfg:            cfg,
+		registry:       registry,
+		permissionRepo: permissionRepo,
+		roleRepo:       roleRepo,
+	}
+}
+
+// Sync upserts the declared permissions by name, creates the missing default roles and grants them
+// the permissions they lack. The permissions in the database no module declares are flagged, logged and returned.
+func (ps *PermissionSynchronizer) Sync(ctx context.Context) ([]*entity.Permission, error) {
+	if err := ps.registry.Validate(); err != nil {
+		return nil, err
+	}
+
+	declared := make([]*entity.Permission, 0)
+	for _, p := range ps.registry.Permissions() {
+		declaredPermission := entity.NewPermission(uuid.New(), p.Name, p.Label, permissionSyncActor)
+		declaredPermission.Group = p.Group
+		declared = append(declared, declaredPermission)
+	}
+
+	orphaned, err := ps.permissionRepo.Sync(ctx, declared)
+	if err != nil {
+		log.Println("[PermissionSynchronizer-Sync]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	ids := make(map[string]uuid.UUID, len(declared))
+	for _, p := range declared {
+		ids[p.Name] = p.ID
+	}
+
+	for _, r := range ps.registry.Roles() {
+		permissionIDs := make([]uuid.UUID, 0, len(r.Permissions))
+		for _, name := range r.Permissions {
+			permissionIDs = append(permissionIDs, ids[name])
+		}
+
+		role := entity.NewRole(uuid.New(), r.Name, permissionSyncActor)
+		role.System = r.System
+		if err := ps.roleRepo.SyncDefault(ctx, role, permissionIDs); err != nil {
+			log.Println("[PermissionSynchronizer-Sync]", err)
+			return nil, errors.ErrInternalServerError.Error()
+		}
+	}
+
+	for _, p := range orphaned {
+		log.Printf("[PermissionSynchronizer-Sync] permission %s is no longer declared by any module\n", p.Name)
+	}
+
+	return orphaned, nil
+}
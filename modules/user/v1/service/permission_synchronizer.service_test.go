@@ -0,0 +1,103 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/permission"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/service"
+	mockRepo "gin-starter/test/mock/modules/user/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type PermissionSynchronizerTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	permissionRepository   *mockRepo.MockPermissionRepositoryUseCase
+	roleRepository         *mockRepo.MockRoleRepositoryUseCase
+	registry               *permission.Registry
+	permissionSynchronizer *service.PermissionSynchronizer
+}
+
+func TestPermissionSynchronizerTestSuite(t *testing.T) {
+	suite.Run(t, new(PermissionSynchronizerTestSuite))
+}
+
+func (suite *PermissionSynchronizerTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.permissionRepository = mockRepo.NewMockPermissionRepositoryUseCase(suite.mockCtrl)
+	suite.roleRepository = mockRepo.NewMockRoleRepositoryUseCase(suite.mockCtrl)
+
+	suite.registry = permission.NewRegistry()
+	suite.registry.Declare("user", permission.Permission{Name: "user.view", Label: "View users"})
+	suite.registry.Declare("notification", permission.Permission{Name: "notification.create", Label: "Send notifications"})
+	suite.registry.DeclareSystemRole(permission.SuperAdminRole, "user.view", "notification.create")
+
+	suite.permissionSynchronizer = service.NewPermissionSynchronizer(config.Config{}, suite.registry, suite.permissionRepository, suite.roleRepository)
+}
+
+func (suite *PermissionSynchronizerTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+func (suite *PermissionSynchronizerTestSuite) TestPermissionSynchronizer_Sync() {
+	ctx := context.Background()
+	userViewID := uuid.New()
+	notificationCreateID := uuid.New()
+
+	suite.Run("upsert the declared permissions and grant them to the default roles", func() {
+		orphaned := []*entity.Permission{{ID: uuid.New(), Name: "report.view", OrphanedAt: sql.NullTime{Time: time.Now(), Valid: true}}}
+
+		suite.permissionRepository.EXPECT().Sync(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, declared []*entity.Permission) ([]*entity.Permission, error) {
+			suite.Require().Len(declared, 2)
+			suite.Equal("notification.create", declared[0].Name)
+			suite.Equal("notification", declared[0].Group)
+			suite.Equal("user.view", declared[1].Name)
+			suite.Equal("View users", declared[1].Label)
+
+			declared[0].ID = notificationCreateID
+			declared[1].ID = userViewID
+
+			return orphaned, nil
+		})
+		suite.roleRepository.EXPECT().SyncDefault(ctx, gomock.Any(), []uuid.UUID{notificationCreateID, userViewID}).DoAndReturn(func(_ context.Context, role *entity.Role, _ []uuid.UUID) error {
+			suite.Equal(permission.SuperAdminRole, role.Name)
+			suite.True(role.System)
+			return nil
+		})
+
+		res, err := suite.permissionSynchronizer.Sync(ctx)
+
+		suite.NoError(err)
+		suite.Equal(orphaned, res)
+	})
+
+	suite.Run("fail when a default role grants an undeclared permission", func() {
+		suite.registry.DeclareRole("Editor", "article.publish")
+
+		_, err := suite.permissionSynchronizer.Sync(ctx)
+
+		suite.Error(err)
+	})
+
+	suite.Run("fail when the permissions cannot be synchronised", func() {
+		registry := permission.NewRegistry()
+		registry.Declare("user", permission.Permission{Name: "user.view", Label: "View users"})
+		synchronizer := service.NewPermissionSynchronizer(config.Config{}, registry, suite.permissionRepository, suite.roleRepository)
+
+		suite.permissionRepository.EXPECT().Sync(ctx, gomock.Any()).Return(nil, errors.ErrInternalServerError.Error())
+
+		_, err := synchronizer.Sync(ctx)
+
+		suite.Equal(errors.ErrInternalServerError.Error(), err)
+	})
+}
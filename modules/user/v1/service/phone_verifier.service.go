@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/interfaces"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/repository"
+	"gin-starter/utils"
+)
+
+const (
+	// phoneVerificationCodeLength is the number of digits of a phone verification code
+	phoneVerificationCodeLength = 6
+	defaultPhoneCodeExpiry      = 10 * time.Minute
+)
+
+// UserPhoneVerifier is a service for verifying the phone numbers of users
+type UserPhoneVerifier struct {
+	cfg                   config.Config
+	userRepo              repository.UserRepositoryUseCase
+	phoneVerificationRepo repository.PhoneVerificationRepositoryUseCase
+	smsSender             interfaces.SMSSender
+}
+
+// UserPhoneVerifierUseCase is a use case for verifying the phone numbers of users
+type UserPhoneVerifierUseCase interface {
+	// SendPhoneVerification sends a verification code by SMS to the phone number of the user
+	SendPhoneVerification(ctx context.Context, userID uuid.UUID) (*entity.PhoneVerification, error)
+	// VerifyPhone verifies the phone number of the user with the code sent to it
+	VerifyPhone(ctx context.Context, userID uuid.UUID, code string) error
+	// SetOTPChannel sets the channel the login code of the user is sent through
+	SetOTPChannel(ctx context.Context, userID uuid.UUID, channel string) error
+}
+
+// NewUserPhoneVerifier is a constructor for UserPhoneVerifier
+func NewUserPhoneVerifier(
+	cfg config.Config,
+	userRepo repository.UserRepositoryUseCase,
+	phoneVerificationRepo repository.PhoneVerificationRepositoryUseCase,
+	smsSender interfaces.SMSSender,
+) *UserPhoneVerifier {
+	return &UserPhoneVerifier{
+		cfg:                   cfg,
+		userRepo:              userRepo,
+		phoneVerificationRepo: phoneVerificationRepo,
+		smsSender:             smsSender,
+	}
+}
+
+// SendPhoneVerification sends a verification code by SMS to the phone number of the user
+func (pv *UserPhoneVerifier) SendPhoneVerification(ctx context.Context, userID uuid.UUID) (*entity.PhoneVerification, error) {
+	user, err := pv.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.PhoneVerifiedAt.Valid {
+		return nil, errors.ErrPhoneAlreadyVerified.Error()
+	}
+
+	phoneNumber, err := utils.NormalizePhoneNumber(user.PhoneNumber)
+	if err != nil {
+		return nil, errors.ErrInvalidPhoneNumber.Error()
+	}
+
+	code := utils.GenerateOTP(phoneVerificationCodeLength)
+	verification := entity.NewPhoneVerification(
+		uuid.New(),
+		user.ID,
+		phoneNumber,
+		hashPhoneVerificationCode(code),
+		time.Now().Add(pv.codeExpiry()),
+		user.ID.String(),
+	)
+
+	// the code is stored once sent, a resend which is rate limited or fails keeps the previous code usable
+	message := fmt.Sprintf("%s is your %s verification code. It expires in %s.", code, pv.cfg.AppName, pv.codeExpiry())
+	if err := pv.smsSender.Send(ctx, phoneNumber, message); err != nil {
+		if isSMSRateLimited(err) {
+			return nil, err
+		}
+
+		log.Println("[UserPhoneVerifier-SendPhoneVerification]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if err := pv.phoneVerificationRepo.Create(ctx, verification); err != nil {
+		log.Println("[UserPhoneVerifier-SendPhoneVerification]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	return verification, nil
+}
+
+// VerifyPhone verifies the phone number of the user with the latest code sent to it
+func (pv *UserPhoneVerifier) VerifyPhone(ctx context.Context, userID uuid.UUID, code string) error {
+	user, err := pv.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.PhoneVerifiedAt.Valid {
+		return errors.ErrPhoneAlreadyVerified.Error()
+	}
+
+	verification, err := pv.phoneVerificationRepo.FindLatest(ctx, user.ID)
+	if err != nil {
+		log.Println("[UserPhoneVerifier-VerifyPhone]", err)
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if verification == nil || !verification.Usable(time.Now()) {
+		return errors.ErrInvalidVerificationCode.Error()
+	}
+
+	// the code only verifies the number it was sent to, the user may have changed it since
+	if phoneNumber, err := utils.NormalizePhoneNumber(user.PhoneNumber); err != nil || phoneNumber != verification.PhoneNumber {
+		return errors.ErrInvalidVerificationCode.Error()
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashPhoneVerificationCode(code)), []byte(verification.CodeHash)) != 1 {
+		if err := pv.phoneVerificationRepo.IncrementAttempts(ctx, verification); err != nil {
+			log.Println("[UserPhoneVerifier-VerifyPhone]", err)
+		}
+
+		return errors.ErrInvalidVerificationCode.Error()
+	}
+
+	if err := pv.phoneVerificationRepo.Verify(ctx, verification); err != nil {
+		log.Println("[UserPhoneVerifier-VerifyPhone]", err)
+		return errors.ErrInvalidVerificationCode.Error()
+	}
+
+	return nil
+}
+
+// SetOTPChannel sets the channel the login code of the user is sent through, sms needs a verified phone number
+func (pv *UserPhoneVerifier) SetOTPChannel(ctx context.Context, userID uuid.UUID, channel string) error {
+	if channel != entity.OTPChannelEmail && channel != entity.OTPChannelSMS {
+		return errors.ErrInvalidOTPChannel.Error()
+	}
+
+	user, err := pv.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if channel == entity.OTPChannelSMS && !user.PhoneVerifiedAt.Valid {
+		return errors.ErrPhoneNotVerified.Error()
+	}
+
+	if err := pv.userRepo.UpdateOTPChannel(ctx, user.ID, channel); err != nil {
+		log.Println("[UserPhoneVerifier-SetOTPChannel]", err)
+		return errors.ErrInternalServerError.Error()
+	}
+
+	return nil
+}
+
+func (pv *UserPhoneVerifier) getUser(ctx context.Context, userID uuid.UUID) (*entity.User, error) {
+	user, err := pv.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if user == nil {
+		return nil, errors.ErrRecordNotFound.Error()
+	}
+
+	return user, nil
+}
+
+func (pv *UserPhoneVerifier) codeExpiry() time.Duration {
+	expiry, err := time.ParseDuration(pv.cfg.SMS.CodeExpiry)
+	if err != nil || expiry <= 0 {
+		return defaultPhoneCodeExpiry
+	}
+
+	return expiry
+}
+
+// hashPhoneVerificationCode hashes a code the way it is stored
+func hashPhoneVerificationCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// isSMSRateLimited tells whether the sender refused the message because of the rate limit of the phone number
+func isSMSRateLimited(err error) bool {
+	return err.Error() == errors.ErrSMSRateLimited.Error().Error()
+}
@@ -0,0 +1,174 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	goErrors "errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/service"
+	mockInterfaces "gin-starter/test/mock/common/interfaces"
+	mockRepo "gin-starter/test/mock/modules/user/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserPhoneVerifierTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	userRepository              *mockRepo.MockUserRepositoryUseCase
+	phoneVerificationRepository *mockRepo.MockPhoneVerificationRepositoryUseCase
+	smsSender                   *mockInterfaces.MockSMSSender
+	phoneVerifier               *service.UserPhoneVerifier
+}
+
+func TestUserPhoneVerifierTestSuite(t *testing.T) {
+	suite.Run(t, new(UserPhoneVerifierTestSuite))
+}
+
+func (suite *UserPhoneVerifierTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.userRepository = mockRepo.NewMockUserRepositoryUseCase(suite.mockCtrl)
+	suite.phoneVerificationRepository = mockRepo.NewMockPhoneVerificationRepositoryUseCase(suite.mockCtrl)
+	suite.smsSender = mockInterfaces.NewMockSMSSender(suite.mockCtrl)
+
+	cfg := config.Config{}
+	cfg.SMS.CodeExpiry = "10m"
+
+	suite.phoneVerifier = service.NewUserPhoneVerifier(cfg, suite.userRepository, suite.phoneVerificationRepository, suite.smsSender)
+}
+
+func (suite *UserPhoneVerifierTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func (suite *UserPhoneVerifierTestSuite) TestUserPhoneVerifier_SendPhoneVerification() {
+	ctx := context.Background()
+
+	suite.Run("successfully send a code to the normalised number", func() {
+		user := &entity.User{ID: uuid.New(), PhoneNumber: "0812-3456-7890"}
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+		gomock.InOrder(
+			suite.smsSender.EXPECT().Send(ctx, "+6281234567890", gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, message string) error {
+					suite.Len(strings.Fields(message)[0], 6)
+					return nil
+				}),
+			suite.phoneVerificationRepository.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+		)
+
+		verification, err := suite.phoneVerifier.SendPhoneVerification(ctx, user.ID)
+
+		suite.Nil(err)
+		suite.Equal("+6281234567890", verification.PhoneNumber)
+	})
+
+	suite.Run("fail to send a code when the number is rate limited", func() {
+		user := &entity.User{ID: uuid.New(), PhoneNumber: "+6281234567890"}
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+		suite.smsSender.EXPECT().Send(ctx, user.PhoneNumber, gomock.Any()).Return(errors.ErrSMSRateLimited.Error())
+
+		_, err := suite.phoneVerifier.SendPhoneVerification(ctx, user.ID)
+		suite.Equal(errors.ErrSMSRateLimited.Error(), err)
+	})
+
+	suite.Run("fail to send a code without replacing the previous one when the provider fails", func() {
+		user := &entity.User{ID: uuid.New(), PhoneNumber: "+6281234567890"}
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+		suite.smsSender.EXPECT().Send(ctx, user.PhoneNumber, gomock.Any()).Return(goErrors.New("provider unavailable"))
+
+		_, err := suite.phoneVerifier.SendPhoneVerification(ctx, user.ID)
+		suite.Equal(errors.ErrInternalServerError.Error(), err)
+	})
+
+	suite.Run("fail to send a code to an invalid number", func() {
+		user := &entity.User{ID: uuid.New(), PhoneNumber: "12345"}
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+
+		_, err := suite.phoneVerifier.SendPhoneVerification(ctx, user.ID)
+		suite.Equal(errors.ErrInvalidPhoneNumber.Error(), err)
+	})
+}
+
+func (suite *UserPhoneVerifierTestSuite) TestUserPhoneVerifier_VerifyPhone() {
+	ctx := context.Background()
+	user := &entity.User{ID: uuid.New(), PhoneNumber: "+6281234567890"}
+
+	newVerification := func() *entity.PhoneVerification {
+		return entity.NewPhoneVerification(uuid.New(), user.ID, user.PhoneNumber, hashCode("123456"), time.Now().Add(time.Minute), user.ID.String())
+	}
+
+	suite.Run("successfully verify the phone number", func() {
+		verification := newVerification()
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+		suite.phoneVerificationRepository.EXPECT().FindLatest(ctx, user.ID).Return(verification, nil)
+		suite.phoneVerificationRepository.EXPECT().Verify(ctx, verification).Return(nil)
+
+		suite.Nil(suite.phoneVerifier.VerifyPhone(ctx, user.ID, "123456"))
+	})
+
+	suite.Run("fail to verify a wrong code and count the attempt", func() {
+		verification := newVerification()
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+		suite.phoneVerificationRepository.EXPECT().FindLatest(ctx, user.ID).Return(verification, nil)
+		suite.phoneVerificationRepository.EXPECT().IncrementAttempts(ctx, verification).Return(nil)
+
+		suite.Equal(errors.ErrInvalidVerificationCode.Error(), suite.phoneVerifier.VerifyPhone(ctx, user.ID, "654321"))
+	})
+
+	suite.Run("fail to verify with exhausted attempts", func() {
+		verification := newVerification()
+		verification.Attempts = entity.PhoneVerificationMaxAttempts
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+		suite.phoneVerificationRepository.EXPECT().FindLatest(ctx, user.ID).Return(verification, nil)
+
+		suite.Equal(errors.ErrInvalidVerificationCode.Error(), suite.phoneVerifier.VerifyPhone(ctx, user.ID, "123456"))
+	})
+
+	suite.Run("fail to verify a code sent to a previous number", func() {
+		verification := newVerification()
+		verification.PhoneNumber = "+6289999999999"
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+		suite.phoneVerificationRepository.EXPECT().FindLatest(ctx, user.ID).Return(verification, nil)
+
+		suite.Equal(errors.ErrInvalidVerificationCode.Error(), suite.phoneVerifier.VerifyPhone(ctx, user.ID, "123456"))
+	})
+}
+
+func (suite *UserPhoneVerifierTestSuite) TestUserPhoneVerifier_SetOTPChannel() {
+	ctx := context.Background()
+
+	suite.Run("successfully choose sms with a verified phone number", func() {
+		user := &entity.User{ID: uuid.New(), PhoneNumber: "+6281234567890", PhoneVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true}}
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+		suite.userRepository.EXPECT().UpdateOTPChannel(ctx, user.ID, entity.OTPChannelSMS).Return(nil)
+
+		suite.Nil(suite.phoneVerifier.SetOTPChannel(ctx, user.ID, entity.OTPChannelSMS))
+	})
+
+	suite.Run("fail to choose sms without a verified phone number", func() {
+		user := &entity.User{ID: uuid.New(), PhoneNumber: "+6281234567890"}
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+
+		suite.Equal(errors.ErrPhoneNotVerified.Error(), suite.phoneVerifier.SetOTPChannel(ctx, user.ID, entity.OTPChannelSMS))
+	})
+
+	suite.Run("fail to choose an unknown channel", func() {
+		suite.Equal(errors.ErrInvalidOTPChannel.Error(), suite.phoneVerifier.SetOTPChannel(ctx, uuid.New(), "whatsapp"))
+	})
+}
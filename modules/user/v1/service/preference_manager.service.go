@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/repository"
+)
+
+// UserPreferenceManager is a service for the preferences of users
+type UserPreferenceManager struct {
+	cfg                config.Config
+	userRepo           repository.UserRepositoryUseCase
+	userPreferenceRepo repository.UserPreferenceRepositoryUseCase
+}
+
+// UserPreferenceManagerUseCase is a use case for the preferences of users
+type UserPreferenceManagerUseCase interface {
+	// GetPreferences returns the preferences of the user, keys the user never set hold their default
+	GetPreferences(ctx context.Context, userID uuid.UUID) (entity.Preferences, error)
+	// GetStoredPreferences returns the preferences the user set away from their default
+	GetStoredPreferences(ctx context.Context, userID uuid.UUID) ([]*entity.UserPreference, error)
+	// UpdatePreferences validates and stores the changed preferences, a nil value resets the key to its default
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, changes map[string]interface{}, updatedBy string) (entity.Preferences, error)
+}
+
+// NewUserPreferenceManager is a constructor for UserPreferenceManager
+func NewUserPreferenceManager(
+	cfg config.Config,
+	userRepo repository.UserRepositoryUseCase,
+	userPreferenceRepo repository.UserPreferenceRepositoryUseCase,
+) *UserPreferenceManager {
+	return &UserPreferenceManager{
+		cfg:                cfg,
+		userRepo:           userRepo,
+		userPreferenceRepo: userPreferenceRepo,
+	}
+}
+
+// GetPreferences returns the preferences of the user, keys the user never set hold their default
+func (pm *UserPreferenceManager) GetPreferences(ctx context.Context, userID uuid.UUID) (entity.Preferences, error) {
+	stored, err := pm.GetStoredPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.NewPreferences(stored), nil
+}
+
+// GetStoredPreferences returns the preferences the user set away from their default
+func (pm *UserPreferenceManager) GetStoredPreferences(ctx context.Context, userID uuid.UUID) ([]*entity.UserPreference, error) {
+	stored, err := pm.userPreferenceRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		log.Println("[UserPreferenceManager-GetStoredPreferences]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	return stored, nil
+}
+
+// UpdatePreferences validates and stores the changed preferences, a nil value resets the key to its default.
+// Nothing is stored unless every change is valid.
+func (pm *UserPreferenceManager) UpdatePreferences(ctx context.Context, userID uuid.UUID, changes map[string]interface{}, updatedBy string) (entity.Preferences, error) {
+	user, err := pm.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if user == nil {
+		return nil, errors.ErrRecordNotFound.Error()
+	}
+
+	// sorted so the upsert locks the rows of the user in a stable order
+	keys := make([]string, 0, len(changes))
+	for key := range changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	preferences := make([]*entity.UserPreference, 0, len(keys))
+	resetKeys := make([]string, 0)
+
+	for _, key := range keys {
+		definition := entity.FindPreferenceDefinition(key)
+		if definition == nil {
+			return nil, errors.ErrUnknownPreference.Error()
+		}
+
+		if changes[key] == nil {
+			resetKeys = append(resetKeys, key)
+			continue
+		}
+
+		value, err := definition.Parse(changes[key])
+		if err != nil {
+			log.Println("[UserPreferenceManager-UpdatePreferences]", err)
+			return nil, errors.ErrInvalidPreference.Error()
+		}
+
+		preference, err := entity.NewUserPreference(uuid.New(), user.ID, key, value, updatedBy)
+		if err != nil {
+			return nil, errors.ErrInternalServerError.Error()
+		}
+
+		preferences = append(preferences, preference)
+	}
+
+	if err := pm.userPreferenceRepo.Save(ctx, user.ID, preferences, resetKeys, updatedBy); err != nil {
+		log.Println("[UserPreferenceManager-UpdatePreferences]", err)
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	return pm.GetPreferences(ctx, user.ID)
+}
@@ -0,0 +1,116 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/service"
+	mockRepo "gin-starter/test/mock/modules/user/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserPreferenceManagerTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	userRepository           *mockRepo.MockUserRepositoryUseCase
+	userPreferenceRepository *mockRepo.MockUserPreferenceRepositoryUseCase
+	preferenceManager        *service.UserPreferenceManager
+}
+
+func TestUserPreferenceManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(UserPreferenceManagerTestSuite))
+}
+
+func (suite *UserPreferenceManagerTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.userRepository = mockRepo.NewMockUserRepositoryUseCase(suite.mockCtrl)
+	suite.userPreferenceRepository = mockRepo.NewMockUserPreferenceRepositoryUseCase(suite.mockCtrl)
+
+	suite.preferenceManager = service.NewUserPreferenceManager(config.Config{}, suite.userRepository, suite.userPreferenceRepository)
+}
+
+func (suite *UserPreferenceManagerTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+func (suite *UserPreferenceManagerTestSuite) TestUserPreferenceManager_GetPreferences() {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	suite.Run("successfully merge the stored preferences over the defaults", func() {
+		language, _ := entity.NewUserPreference(uuid.New(), userID, entity.PreferenceLanguage, "en", userID.String())
+		removed, _ := entity.NewUserPreference(uuid.New(), userID, "removed_key", true, userID.String())
+		suite.userPreferenceRepository.EXPECT().FindByUserID(ctx, userID).Return([]*entity.UserPreference{language, removed}, nil)
+
+		preferences, err := suite.preferenceManager.GetPreferences(ctx, userID)
+
+		suite.Nil(err)
+		suite.Equal("en", preferences.String(entity.PreferenceLanguage))
+		suite.Equal("Asia/Jakarta", preferences.String(entity.PreferenceTimezone))
+		suite.True(preferences.Bool(entity.PreferenceEmailNotifications))
+		suite.False(preferences.Bool(entity.PreferenceMarketingConsent))
+		suite.NotContains(preferences, "removed_key")
+	})
+}
+
+func (suite *UserPreferenceManagerTestSuite) TestUserPreferenceManager_UpdatePreferences() {
+	ctx := context.Background()
+	user := &entity.User{ID: uuid.New()}
+
+	suite.Run("successfully store the changes and reset the null ones", func() {
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+		suite.userPreferenceRepository.EXPECT().Save(ctx, user.ID, gomock.Any(), []string{entity.PreferenceLanguage}, "admin").
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, preferences []*entity.UserPreference, _ []string, _ string) error {
+				suite.Len(preferences, 2)
+				suite.Equal(entity.PreferenceMarketingConsent, preferences[0].Key)
+				suite.Equal("true", preferences[0].Value)
+				suite.Equal(entity.PreferenceTimezone, preferences[1].Key)
+				suite.Equal(`"Asia/Makassar"`, preferences[1].Value)
+				return nil
+			})
+		suite.userPreferenceRepository.EXPECT().FindByUserID(ctx, user.ID).Return([]*entity.UserPreference{}, nil)
+
+		_, err := suite.preferenceManager.UpdatePreferences(ctx, user.ID, map[string]interface{}{
+			entity.PreferenceTimezone:         "Asia/Makassar",
+			entity.PreferenceMarketingConsent: true,
+			entity.PreferenceLanguage:         nil,
+		}, "admin")
+
+		suite.Nil(err)
+	})
+
+	suite.Run("fail to update an unknown key", func() {
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+
+		_, err := suite.preferenceManager.UpdatePreferences(ctx, user.ID, map[string]interface{}{"theme": "dark"}, "admin")
+		suite.Equal(errors.ErrUnknownPreference.Error(), err)
+	})
+
+	suite.Run("fail to update a value of the wrong type", func() {
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+
+		_, err := suite.preferenceManager.UpdatePreferences(ctx, user.ID, map[string]interface{}{entity.PreferenceEmailNotifications: "yes"}, "admin")
+		suite.Equal(errors.ErrInvalidPreference.Error(), err)
+	})
+
+	suite.Run("fail to update a language outside the options", func() {
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(user, nil)
+
+		_, err := suite.preferenceManager.UpdatePreferences(ctx, user.ID, map[string]interface{}{entity.PreferenceLanguage: "fr"}, "admin")
+		suite.Equal(errors.ErrInvalidPreference.Error(), err)
+	})
+
+	suite.Run("fail to update the preferences of a missing user", func() {
+		suite.userRepository.EXPECT().GetUserByID(ctx, user.ID).Return(nil, nil)
+
+		_, err := suite.preferenceManager.UpdatePreferences(ctx, user.ID, map[string]interface{}{}, "admin")
+		suite.Equal(errors.ErrRecordNotFound.Error(), err)
+	})
+}
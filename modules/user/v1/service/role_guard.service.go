@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/permission"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/repository"
+)
+
+// isSuperAdminRole tells whether the role is the super admin system role
+func isSuperAdminRole(role *entity.Role) bool {
+	return role != nil && role.System && role.Name == permission.SuperAdminRole
+}
+
+// rolePermissionIDs returns the ids of the permissions the role grants
+func rolePermissionIDs(role *entity.Role) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(role.RolePermissions))
+	for _, rp := range role.RolePermissions {
+		ids = append(ids, rp.PermissionID)
+	}
+
+	return ids
+}
+
+// checkLastSuperAdmin rejects taking the super admin role away from its last active holder,
+// either by moving the user to roleID or, when roleID is uuid.Nil, by deleting or deactivating the user.
+// It rejects the change before anything is written, the repository checks again in the transaction of the write
+// since another super admin may be removed meanwhile.
+func checkLastSuperAdmin(
+	ctx context.Context,
+	userRoleRepo repository.UserRoleRepositoryUseCase,
+	roleRepo repository.RoleRepositoryUseCase,
+	user *entity.User,
+	roleID uuid.UUID,
+) error {
+	if user.Status != entity.UserStatusActivated {
+		return nil
+	}
+
+	userRole, err := userRoleRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if userRole == nil || userRole.RoleID == roleID {
+		return nil
+	}
+
+	role, err := roleRepo.FindByID(ctx, userRole.RoleID)
+	if err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if !isSuperAdminRole(role) {
+		return nil
+	}
+
+	total, err := userRoleRepo.CountActivatedByRoleID(ctx, role.ID)
+	if err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if total <= 1 {
+		return errors.ErrLastSuperAdmin.Error()
+	}
+
+	return nil
+}
+
+// checkGrantable rejects granting permissions the actor does not hold itself.
+// Super admins hold every permission, including the ones created through the CMS.
+func checkGrantable(
+	ctx context.Context,
+	userRoleRepo repository.UserRoleRepositoryUseCase,
+	roleRepo repository.RoleRepositoryUseCase,
+	actorID uuid.UUID,
+	permissionIDs []uuid.UUID,
+) error {
+	if len(permissionIDs) == 0 {
+		return nil
+	}
+
+	userRole, err := userRoleRepo.FindByUserID(ctx, actorID)
+	if err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if userRole == nil {
+		return errors.ErrPermissionEscalation.Error()
+	}
+
+	role, err := roleRepo.FindByID(ctx, userRole.RoleID)
+	if err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if role == nil {
+		return errors.ErrPermissionEscalation.Error()
+	}
+
+	if isSuperAdminRole(role) {
+		return nil
+	}
+
+	held := make(map[uuid.UUID]bool, len(role.RolePermissions))
+	for _, rp := range role.RolePermissions {
+		held[rp.PermissionID] = true
+	}
+
+	for _, pid := range permissionIDs {
+		if !held[pid] {
+			return errors.ErrPermissionEscalation.Error()
+		}
+	}
+
+	return nil
+}
+
+// checkRoleChange rejects moving the user to the role when the user is the last active super admin
+// or when the role grants permissions the actor does not hold. Keeping the current role is always allowed.
+func checkRoleChange(
+	ctx context.Context,
+	userRoleRepo repository.UserRoleRepositoryUseCase,
+	roleRepo repository.RoleRepositoryUseCase,
+	user *entity.User,
+	role *entity.Role,
+	actorID uuid.UUID,
+) error {
+	userRole, err := userRoleRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if userRole != nil && userRole.RoleID == role.ID {
+		return nil
+	}
+
+	if err := checkLastSuperAdmin(ctx, userRoleRepo, roleRepo, user, role.ID); err != nil {
+		return err
+	}
+
+	return checkGrantable(ctx, userRoleRepo, roleRepo, actorID, rolePermissionIDs(role))
+}
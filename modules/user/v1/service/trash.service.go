@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/repository"
+)
+
+const (
+	// TrashTypeUser is the trash type of deleted users
+	TrashTypeUser = "user"
+	// TrashTypeRole is the trash type of deleted roles
+	TrashTypeRole = "role"
+)
+
+// UserTrash is a service for deleted users and roles
+type UserTrash struct {
+	cfg      config.Config
+	userRepo repository.UserRepositoryUseCase
+	roleRepo repository.RoleRepositoryUseCase
+}
+
+// UserTrashUseCase is a use case for deleted users and roles
+type UserTrashUseCase interface {
+	// GetDeletedUsers gets the deleted users
+	GetDeletedUsers(ctx context.Context, limit, offset int) ([]*entity.User, int64, error)
+	// GetDeletedRoles gets the deleted roles
+	GetDeletedRoles(ctx context.Context, limit, offset int) ([]*entity.Role, int64, error)
+	// RestoreUser restores a deleted user
+	RestoreUser(ctx context.Context, id uuid.UUID, restoredBy string) error
+	// RestoreRole restores a deleted role
+	RestoreRole(ctx context.Context, id uuid.UUID, restoredBy string) error
+	// PurgeAt returns when an item deleted at the given time is purged
+	PurgeAt(deletedAt time.Time) time.Time
+	// PurgeExpired permanently deletes the users and roles whose retention period is over
+	PurgeExpired(ctx context.Context) error
+	// RunPurger purges the expired users and roles periodically until the context is done
+	RunPurger(ctx context.Context)
+}
+
+// NewUserTrash creates a new UserTrash
+func NewUserTrash(
+	cfg config.Config,
+	userRepo repository.UserRepositoryUseCase,
+	roleRepo repository.RoleRepositoryUseCase,
+) *UserTrash {
+	return &UserTrash{
+		cfg:      cfg,
+		userRepo: userRepo,
+		roleRepo: roleRepo,
+	}
+}
+
+// GetDeletedUsers gets the deleted users
+func (ut *UserTrash) GetDeletedUsers(ctx context.Context, limit, offset int) ([]*entity.User, int64, error) {
+	users, total, err := ut.userRepo.GetDeletedUsers(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, errors.ErrInternalServerError.Error()
+	}
+
+	return users, total, nil
+}
+
+// GetDeletedRoles gets the deleted roles
+func (ut *UserTrash) GetDeletedRoles(ctx context.Context, limit, offset int) ([]*entity.Role, int64, error) {
+	roles, total, err := ut.roleRepo.FindDeleted(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, errors.ErrInternalServerError.Error()
+	}
+
+	return roles, total, nil
+}
+
+// RestoreUser restores a deleted user.
+// An admin whose role is also deleted can only be restored after the role.
+func (ut *UserTrash) RestoreUser(ctx context.Context, id uuid.UUID, restoredBy string) error {
+	user, err := ut.userRepo.GetDeletedUserByID(ctx, id)
+	if err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if user == nil {
+		return errors.ErrRecordNotFound.Error()
+	}
+
+	if user.UserRole != nil {
+		role, err := ut.roleRepo.FindDeletedByID(ctx, user.UserRole.RoleID)
+		if err != nil {
+			return errors.ErrInternalServerError.Error()
+		}
+
+		if role != nil {
+			return errors.ErrRoleInTrash.Error()
+		}
+	}
+
+	if err := ut.userRepo.Restore(ctx, id, restoredBy); err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	return nil
+}
+
+// RestoreRole restores a deleted role
+func (ut *UserTrash) RestoreRole(ctx context.Context, id uuid.UUID, restoredBy string) error {
+	role, err := ut.roleRepo.FindDeletedByID(ctx, id)
+	if err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	if role == nil {
+		return errors.ErrRecordNotFound.Error()
+	}
+
+	if err := ut.roleRepo.Restore(ctx, role, restoredBy); err != nil {
+		return errors.ErrInternalServerError.Error()
+	}
+
+	return nil
+}
+
+// PurgeAt returns when an item deleted at the given time is purged
+func (ut *UserTrash) PurgeAt(deletedAt time.Time) time.Time {
+	return deletedAt.AddDate(0, 0, ut.cfg.Trash.RetentionDays)
+}
+
+// PurgeExpired permanently deletes the users and roles whose retention period is over
+func (ut *UserTrash) PurgeExpired(ctx context.Context) error {
+	before := time.Now().AddDate(0, 0, -ut.cfg.Trash.RetentionDays)
+
+	users, err := ut.userRepo.Purge(ctx, before)
+	if err != nil {
+		return err
+	}
+
+	roles, err := ut.roleRepo.Purge(ctx, before)
+	if err != nil {
+		return err
+	}
+
+	if users > 0 || roles > 0 {
+		log.Printf("[UserTrash-PurgeExpired] purged %d users and %d roles deleted before %s", users, roles, before.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// RunPurger purges the expired users and roles periodically until the context is done
+func (ut *UserTrash) RunPurger(ctx context.Context) {
+	interval, err := time.ParseDuration(ut.cfg.Trash.PurgeInterval)
+	if err != nil || interval <= 0 {
+		log.Println("[UserTrash-RunPurger] invalid purge interval, trash is never purged:", ut.cfg.Trash.PurgeInterval)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := ut.PurgeExpired(ctx); err != nil {
+			log.Println("[UserTrash-RunPurger]", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -3,9 +3,12 @@ package service
 import (
 	"bytes"
 	"context"
+	goErrors "errors"
 	"fmt"
 	"gin-starter/common/constant"
 	"gin-starter/common/errors"
+	"gin-starter/common/interfaces"
+	"gin-starter/common/patch"
 	"gin-starter/config"
 	"gin-starter/entity"
 	"gin-starter/modules/user/v1/repository"
@@ -23,6 +26,7 @@ type UserUpdater struct {
 	userRoleRepo   repository.UserRoleRepositoryUseCase
 	roleRepo       repository.RoleRepositoryUseCase
 	permissionRepo repository.PermissionRepositoryUseCase
+	photoStorage   interfaces.PhotoStorageUseCase
 }
 
 // UserUpdaterUseCase is a struct that contains the dependencies of UserUpdaterUseCase
@@ -40,13 +44,21 @@ type UserUpdaterUseCase interface {
 	// Update is a function that updates the user
 	Update(ctx context.Context, user *entity.User) error
 	// ActivateDeactivateUser activates or deactivates a user.
-	ActivateDeactivateUser(ctx context.Context, id uuid.UUID) error
-	// UpdateAdmin updates an admin.
-	UpdateAdmin(ctx context.Context, user *entity.User, roleID uuid.UUID) error
-	// UpdateRole updates a role
-	UpdateRole(ctx context.Context, id uuid.UUID, name string, permissionIDs []uuid.UUID) error
+	ActivateDeactivateUser(ctx context.Context, id uuid.UUID, version int64) error
+	// UpdateAdmin updates an admin, updatedBy is the admin moving it to the role
+	UpdateAdmin(ctx context.Context, user *entity.User, roleID, updatedBy uuid.UUID) error
+	// UpdateRole updates a role, updatedBy is the admin granting the permissions
+	UpdateRole(ctx context.Context, id uuid.UUID, name string, permissionIDs []uuid.UUID, updatedBy uuid.UUID) error
 	// UpdatePermission updates a permission
 	UpdatePermission(ctx context.Context, id uuid.UUID, name, label string) error
+	// PatchUser applies a merge patch to the profile of a user, version is the version of the user the client read
+	PatchUser(ctx context.Context, id uuid.UUID, version int64, p *patch.Patch) (*entity.User, error)
+	// PatchAdmin applies a merge patch to an admin, version is the version of the admin the client read
+	PatchAdmin(ctx context.Context, id uuid.UUID, version int64, p *patch.Patch, updatedBy uuid.UUID) (*entity.User, error)
+	// PatchRole applies a merge patch to a role
+	PatchRole(ctx context.Context, id uuid.UUID, p *patch.Patch, updatedBy uuid.UUID) (*entity.Role, error)
+	// PatchPermission applies a merge patch to a permission
+	PatchPermission(ctx context.Context, id uuid.UUID, p *patch.Patch) (*entity.Permission, error)
 }
 
 // NewUserUpdater is a function that creates a new UserUpdater
@@ -56,6 +68,7 @@ func NewUserUpdater(
 	userRoleRepo repository.UserRoleRepositoryUseCase,
 	roleRepo repository.RoleRepositoryUseCase,
 	permissionRepo repository.PermissionRepositoryUseCase,
+	photoStorage interfaces.PhotoStorageUseCase,
 ) *UserUpdater {
 	return &UserUpdater{
 		cfg:            cfg,
@@ -63,6 +76,7 @@ func NewUserUpdater(
 		userRoleRepo:   userRoleRepo,
 		roleRepo:       roleRepo,
 		permissionRepo: permissionRepo,
+		photoStorage:   photoStorage,
 	}
 }
 
@@ -165,6 +179,11 @@ func (uu *UserUpdater) ForgotPasswordRequest(ctx context.Context, email string)
 		return errors.ErrInternalServerError.Error()
 	}
 
+	// unknown emails are not reported so the endpoint cannot be used to find registered users
+	if user == nil {
+		return nil
+	}
+
 	user.ForgotPasswordToken = utils.StringToNullString(utils.RandStringBytes(constant.Thirty))
 
 	if err := uu.userRepo.Update(ctx, user); err != nil {
@@ -231,15 +250,36 @@ func (uu *UserUpdater) ForgotPassword(ctx context.Context, userID uuid.UUID, new
 
 // Update is a function that updates the user
 func (uu *UserUpdater) Update(ctx context.Context, user *entity.User) error {
-	if err := uu.userRepo.Update(ctx, user); err != nil {
+	old, err := uu.userRepo.GetUserByID(ctx, user.ID)
+
+	if err != nil {
 		return errors.ErrInternalServerError.Error()
 	}
 
+	if old == nil {
+		return errors.ErrRecordNotFound.Error()
+	}
+
+	// the email only changes once the new address is confirmed, see UserEmailChanger
+	user.Email = old.Email
+
+	if user.PhoneNumber, err = normalizeOptionalPhoneNumber(user.PhoneNumber); err != nil {
+		return err
+	}
+
+	if err := uu.userRepo.Update(ctx, user); err != nil {
+		log.Println("[UserUpdater-Update]", err)
+		uu.releaseRejectedPhoto(ctx, old, user)
+		return userUpdateError(err)
+	}
+
+	uu.releasePhoto(ctx, old)
+
 	return nil
 }
 
-// ActivateDeactivateUser activates or deactivates a user.
-func (uu *UserUpdater) ActivateDeactivateUser(ctx context.Context, id uuid.UUID) error {
+// ActivateDeactivateUser activates or deactivates a user, version is the version of the user the client read.
+func (uu *UserUpdater) ActivateDeactivateUser(ctx context.Context, id uuid.UUID, version int64) error {
 	user, err := uu.userRepo.GetUserByID(ctx, id)
 
 	if err != nil {
@@ -250,13 +290,23 @@ func (uu *UserUpdater) ActivateDeactivateUser(ctx context.Context, id uuid.UUID)
 		return errors.ErrRecordNotFound.Error()
 	}
 
-	if user.Status == "DEACTIVATED" {
-		if err := uu.userRepo.UpdateUserStatus(ctx, id, "ACTIVATED"); err != nil {
-			return errors.ErrInternalServerError.Error()
+	if user.Version != version {
+		return errors.ErrVersionMismatch.Error()
+	}
+
+	if user.Status == entity.UserStatusDeactivated {
+		if err := uu.userRepo.UpdateUserStatus(ctx, id, entity.UserStatusActivated, version); err != nil {
+			log.Println("[UserUpdater-ActivateDeactivateUser]", err)
+			return userUpdateError(err)
+		}
+	} else if user.Status == entity.UserStatusActivated {
+		if err := checkLastSuperAdmin(ctx, uu.userRoleRepo, uu.roleRepo, user, uuid.Nil); err != nil {
+			return err
 		}
-	} else if user.Status == "ACTIVATED" {
-		if err := uu.userRepo.UpdateUserStatus(ctx, id, "DEACTIVATED"); err != nil {
-			return errors.ErrInternalServerError.Error()
+
+		if err := uu.userRepo.UpdateUserStatus(ctx, id, entity.UserStatusDeactivated, version); err != nil {
+			log.Println("[UserUpdater-ActivateDeactivateUser]", err)
+			return userUpdateError(err)
 		}
 	}
 
@@ -264,28 +314,309 @@ func (uu *UserUpdater) ActivateDeactivateUser(ctx context.Context, id uuid.UUID)
 }
 
 // UpdateAdmin updates an admin.
-func (uu *UserUpdater) UpdateAdmin(ctx context.Context, user *entity.User, roleID uuid.UUID) error {
-	if err := uu.userRepo.UpdateUser(ctx, user); err != nil {
+// The last active super admin keeps its role and the role cannot grant permissions updatedBy does not hold.
+func (uu *UserUpdater) UpdateAdmin(ctx context.Context, user *entity.User, roleID, updatedBy uuid.UUID) error {
+	old, err := uu.userRepo.GetUserByID(ctx, user.ID)
+
+	if err != nil {
 		return errors.ErrInternalServerError.Error()
 	}
 
-	userRole, err := uu.userRoleRepo.FindByUserID(ctx, user.ID)
+	if old == nil {
+		return errors.ErrRecordNotFound.Error()
+	}
+
+	role, err := uu.roleRepo.FindByID(ctx, roleID)
 
 	if err != nil {
 		return errors.ErrInternalServerError.Error()
 	}
 
-	userRole.RoleID = roleID
+	if role == nil {
+		return errors.ErrRecordNotFound.Error()
+	}
+
+	if err := checkRoleChange(ctx, uu.userRoleRepo, uu.roleRepo, old, role, updatedBy); err != nil {
+		uu.releaseRejectedPhoto(ctx, old, user)
+		return err
+	}
 
-	if err := uu.userRoleRepo.Update(ctx, userRole); err != nil {
-		return nil
+	if user.PhoneNumber, err = normalizeOptionalPhoneNumber(user.PhoneNumber); err != nil {
+		return err
 	}
 
+	if user.Email != old.Email {
+		if err := checkEmailAvailable(ctx, uu.userRepo, user.Email, user.ID); err != nil {
+			uu.releaseRejectedPhoto(ctx, old, user)
+			return err
+		}
+	}
+
+	if err := uu.userRoleRepo.UpdateAdmin(ctx, user, roleID); err != nil {
+		log.Println("[UserUpdater-UpdateAdmin]", err)
+		uu.releaseRejectedPhoto(ctx, old, user)
+		return userUpdateError(err)
+	}
+
+	uu.releasePhoto(ctx, old)
+
 	return nil
 }
 
-// UpdateRole updates a role
-func (uu *UserUpdater) UpdateRole(ctx context.Context, id uuid.UUID, name string, permissionIDs []uuid.UUID) error {
+// PatchUser applies a merge patch to the profile of a user, version is the version of the user the client read
+func (uu *UserUpdater) PatchUser(ctx context.Context, id uuid.UUID, version int64, p *patch.Patch) (*entity.User, error) {
+	old, err := uu.userRepo.GetUserByID(ctx, id)
+
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if old == nil {
+		return nil, errors.ErrRecordNotFound.Error()
+	}
+
+	return uu.patchUser(ctx, old, version, p)
+}
+
+// PatchAdmin applies a merge patch to an admin, version is the version of the admin the client read.
+// The last active super admin keeps its role and the role cannot grant permissions updatedBy does not hold.
+func (uu *UserUpdater) PatchAdmin(ctx context.Context, id uuid.UUID, version int64, p *patch.Patch, updatedBy uuid.UUID) (*entity.User, error) {
+	old, err := uu.userRepo.GetUserByID(ctx, id)
+
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if old == nil {
+		return nil, errors.ErrRecordNotFound.Error()
+	}
+
+	if !p.Has("role_id") {
+		return uu.patchUser(ctx, old, version, p)
+	}
+
+	roleID := uuid.MustParse(p.String("role_id"))
+	role, err := uu.roleRepo.FindByID(ctx, roleID)
+
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if role == nil {
+		return nil, errors.ErrRecordNotFound.Error()
+	}
+
+	if err := checkRoleChange(ctx, uu.userRoleRepo, uu.roleRepo, old, role, updatedBy); err != nil {
+		return nil, err
+	}
+
+	columns, err := uu.patchColumns(ctx, old, p)
+	if err != nil {
+		return nil, err
+	}
+
+	// the admin is patched and moved in one transaction, so an outdated version or a refused move changes nothing
+	if err := uu.userRoleRepo.PatchAdmin(ctx, id, version, columns, roleID); err != nil {
+		log.Println("[UserUpdater-PatchAdmin]", err)
+		return nil, userUpdateError(err)
+	}
+
+	if p.Has("photo") {
+		uu.releasePhoto(ctx, old)
+	}
+
+	user, err := uu.userRepo.GetUserByID(ctx, id)
+
+	if err != nil || user == nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	return user, nil
+}
+
+// patchUser applies the columns of a merge patch to a user.
+// A new phone number has to be verified again and a cleared photo releases its variants.
+func (uu *UserUpdater) patchUser(ctx context.Context, old *entity.User, version int64, p *patch.Patch) (*entity.User, error) {
+	if p.Empty() {
+		if old.Version != version {
+			return nil, errors.ErrVersionMismatch.Error()
+		}
+
+		return old, nil
+	}
+
+	columns, err := uu.patchColumns(ctx, old, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uu.userRepo.Patch(ctx, old.ID, version, columns); err != nil {
+		log.Println("[UserUpdater-patchUser]", err)
+		return nil, userUpdateError(err)
+	}
+
+	if p.Has("photo") {
+		uu.releasePhoto(ctx, old)
+	}
+
+	user, err := uu.userRepo.GetUserByID(ctx, old.ID)
+
+	if err != nil || user == nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	return user, nil
+}
+
+// patchColumns returns the column updates of a merge patch to a user.
+// A new phone number has to be verified again and a cleared photo drops its variants.
+func (uu *UserUpdater) patchColumns(ctx context.Context, old *entity.User, p *patch.Patch) (map[string]interface{}, error) {
+	columns := p.Columns()
+
+	if p.Has("email") {
+		email := utils.NormalizeEmail(p.String("email"))
+		if email != old.Email {
+			if err := checkEmailAvailable(ctx, uu.userRepo, email, old.ID); err != nil {
+				return nil, err
+			}
+		}
+
+		columns["email"] = email
+	}
+
+	if p.Has("phone_number") {
+		phoneNumber, err := normalizeOptionalPhoneNumber(p.String("phone_number"))
+		if err != nil {
+			return nil, err
+		}
+
+		columns["phone_number"] = phoneNumber
+
+		if phoneNumber != old.PhoneNumber {
+			columns["phone_verified_at"] = nil
+			columns["otp_channel"] = entity.OTPChannelEmail
+		}
+	}
+
+	if p.Has("photo") {
+		columns["photo_variants"] = ""
+	}
+
+	return columns, nil
+}
+
+// PatchRole applies a merge patch to a role, the permissions are replaced when permission_ids is provided
+func (uu *UserUpdater) PatchRole(ctx context.Context, id uuid.UUID, p *patch.Patch, updatedBy uuid.UUID) (*entity.Role, error) {
+	role, err := uu.roleRepo.FindByID(ctx, id)
+
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if role == nil {
+		return nil, errors.ErrRecordNotFound.Error()
+	}
+
+	name := role.Name
+	if p.Has("name") {
+		name = p.String("name")
+	}
+
+	permissionIDs := make([]uuid.UUID, 0, len(role.RolePermissions))
+	if p.Has("permission_ids") {
+		for _, pid := range p.Strings("permission_ids") {
+			permissionIDs = append(permissionIDs, uuid.MustParse(pid))
+		}
+	} else {
+		for _, rp := range role.RolePermissions {
+			permissionIDs = append(permissionIDs, rp.PermissionID)
+		}
+	}
+
+	if err := uu.UpdateRole(ctx, id, name, permissionIDs, updatedBy); err != nil {
+		return nil, err
+	}
+
+	return uu.roleRepo.FindByID(ctx, id)
+}
+
+// PatchPermission applies a merge patch to a permission
+func (uu *UserUpdater) PatchPermission(ctx context.Context, id uuid.UUID, p *patch.Patch) (*entity.Permission, error) {
+	permission, err := uu.permissionRepo.FindByID(ctx, id)
+
+	if err != nil {
+		return nil, errors.ErrInternalServerError.Error()
+	}
+
+	if permission == nil {
+		return nil, errors.ErrRecordNotFound.Error()
+	}
+
+	name, label := permission.Name, permission.Label
+	if p.Has("name") {
+		name = p.String("name")
+	}
+
+	if p.Has("label") {
+		label = p.String("label")
+	}
+
+	if err := uu.UpdatePermission(ctx, id, name, label); err != nil {
+		return nil, err
+	}
+
+	return uu.permissionRepo.FindByID(ctx, id)
+}
+
+// releaseRejectedPhoto deletes the photo uploaded along with an update which was not applied
+func (uu *UserUpdater) releaseRejectedPhoto(ctx context.Context, old, rejected *entity.User) {
+	if rejected.Photo != old.Photo {
+		uu.releasePhoto(ctx, rejected)
+	}
+}
+
+// userUpdateError maps a failed update of a user to the error returned to the client
+func userUpdateError(err error) error {
+	if goErrors.Is(err, repository.ErrVersionConflict) {
+		return errors.ErrVersionMismatch.Error()
+	}
+
+	if goErrors.Is(err, repository.ErrEmailConflict) {
+		return errors.ErrEmailAlreadyUsed.Error()
+	}
+
+	if goErrors.Is(err, repository.ErrLastSuperAdmin) {
+		return errors.ErrLastSuperAdmin.Error()
+	}
+
+	return errors.ErrInternalServerError.Error()
+}
+
+// releasePhoto deletes the variants of the previous photo of a user once no user references it anymore.
+// Identical uploads share their variants, so a replaced photo may still be the photo of another user.
+func (uu *UserUpdater) releasePhoto(ctx context.Context, previous *entity.User) {
+	if previous == nil || previous.Photo == "" {
+		return
+	}
+
+	inUse, err := uu.userRepo.IsPhotoInUse(ctx, previous.Photo)
+	if err != nil {
+		log.Println("[UserUpdater-releasePhoto]", err)
+		return
+	}
+
+	if inUse {
+		return
+	}
+
+	if err := uu.photoStorage.Delete(previous); err != nil {
+		log.Println("[UserUpdater-releasePhoto]", err)
+	}
+}
+
+// UpdateRole updates a role.
+// System roles keep their name and core permissions, and updatedBy can only add permissions it holds itself.
+func (uu *UserUpdater) UpdateRole(ctx context.Context, id uuid.UUID, name string, permissionIDs []uuid.UUID, updatedBy uuid.UUID) error {
 	role, err := uu.roleRepo.FindByID(ctx, id)
 
 	if err != nil {
@@ -296,16 +627,54 @@ func (uu *UserUpdater) UpdateRole(ctx context.Context, id uuid.UUID, name string
 		return errors.ErrRecordNotFound.Error()
 	}
 
+	if role.System && name != role.Name {
+		return errors.ErrSystemRoleProtected.Error()
+	}
+
+	requested := make(map[uuid.UUID]bool, len(permissionIDs))
+	for _, pid := range permissionIDs {
+		requested[pid] = true
+	}
+
+	granted := make(map[uuid.UUID]bool, len(role.RolePermissions))
+	core := make(map[uuid.UUID]bool)
+	for _, rp := range role.RolePermissions {
+		granted[rp.PermissionID] = true
+
+		if !rp.Core {
+			continue
+		}
+
+		if !requested[rp.PermissionID] {
+			return errors.ErrCorePermissionRemoved.Error()
+		}
+
+		core[rp.PermissionID] = true
+	}
+
+	added := make([]uuid.UUID, 0)
+	for _, pid := range permissionIDs {
+		if !granted[pid] {
+			added = append(added, pid)
+		}
+	}
+
+	if err := checkGrantable(ctx, uu.userRoleRepo, uu.roleRepo, updatedBy, added); err != nil {
+		return err
+	}
+
 	roleRequest := entity.NewRole(role.ID, name, "system")
 
 	newPermissions := make([]*entity.RolePermission, 0)
 	for _, pid := range permissionIDs {
-		newPermissions = append(newPermissions, entity.NewRolePermission(
+		rolePermission := entity.NewRolePermission(
 			uuid.New(),
 			role.ID,
 			pid,
 			role.CreatedBy.String,
-		))
+		)
+		rolePermission.Core = core[pid]
+		newPermissions = append(newPermissions, rolePermission)
 	}
 
 	if err := uu.roleRepo.Update(ctx, roleRequest, newPermissions); err != nil {
@@ -315,7 +684,8 @@ func (uu *UserUpdater) UpdateRole(ctx context.Context, id uuid.UUID, name string
 	return nil
 }
 
-// UpdatePermission updates a permission
+// UpdatePermission updates a permission.
+// Permissions declared by a module keep their name, the routes check them by name and the boot sync would recreate it.
 func (uu *UserUpdater) UpdatePermission(ctx context.Context, id uuid.UUID, name, label string) error {
 	permission, err := uu.permissionRepo.FindByID(ctx, id)
 
@@ -327,6 +697,10 @@ func (uu *UserUpdater) UpdatePermission(ctx context.Context, id uuid.UUID, name,
 		return errors.ErrRecordNotFound.Error()
 	}
 
+	if permission.Group != "" && !permission.OrphanedAt.Valid && name != permission.Name {
+		return errors.ErrDeclaredPermissionProtected.Error()
+	}
+
 	newPermission := entity.NewPermission(id, name, label, permission.CreatedBy.String)
 
 	if err := uu.permissionRepo.Update(ctx, newPermission); err != nil {
@@ -0,0 +1,358 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"gin-starter/common/errors"
+	"gin-starter/common/patch"
+	"gin-starter/common/permission"
+	"gin-starter/config"
+	"gin-starter/entity"
+	"gin-starter/modules/user/v1/repository"
+	"gin-starter/modules/user/v1/service"
+	"gin-starter/resource"
+	mockInterfaces "gin-starter/test/mock/common/interfaces"
+	mockRepo "gin-starter/test/mock/modules/user/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserUpdaterTestSuite struct {
+	suite.Suite
+	mockCtrl *gomock.Controller
+
+	userRepository     *mockRepo.MockUserRepositoryUseCase
+	userRoleRepository *mockRepo.MockUserRoleRepositoryUseCase
+	roleRepository     *mockRepo.MockRoleRepositoryUseCase
+	permissionRepo     *mockRepo.MockPermissionRepositoryUseCase
+	photoStorage       *mockInterfaces.MockPhotoStorageUseCase
+	userUpdater        *service.UserUpdater
+}
+
+func TestUserUpdaterTestSuite(t *testing.T) {
+	suite.Run(t, new(UserUpdaterTestSuite))
+}
+
+func (suite *UserUpdaterTestSuite) BeforeTest(string, string) {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.userRepository = mockRepo.NewMockUserRepositoryUseCase(suite.mockCtrl)
+	suite.userRoleRepository = mockRepo.NewMockUserRoleRepositoryUseCase(suite.mockCtrl)
+	suite.roleRepository = mockRepo.NewMockRoleRepositoryUseCase(suite.mockCtrl)
+	suite.permissionRepo = mockRepo.NewMockPermissionRepositoryUseCase(suite.mockCtrl)
+	suite.photoStorage = mockInterfaces.NewMockPhotoStorageUseCase(suite.mockCtrl)
+
+	cfg := config.Config{}
+	suite.userUpdater = service.NewUserUpdater(
+		cfg,
+		suite.userRepository,
+		suite.userRoleRepository,
+		suite.roleRepository,
+		suite.permissionRepo,
+		suite.photoStorage,
+	)
+}
+
+func (suite *UserUpdaterTestSuite) AfterTest(string, string) {
+	defer suite.mockCtrl.Finish()
+}
+
+func (suite *UserUpdaterTestSuite) TestUserUpdater_Update() {
+	ctx := context.Background()
+	userID := uuid.New()
+	previous := &entity.User{ID: userID, Photo: "/users/user/profile/old", PhotoVariants: "64.jpeg,256.jpeg"}
+	user := &entity.User{ID: userID}
+	user.SetPhoto(&entity.UserPhoto{Path: "/users/user/profile/new", Variants: []string{"64.jpeg", "256.jpeg"}})
+
+	suite.Run("successfully delete the variants of a replaced photo", func() {
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(previous, nil)
+		suite.userRepository.EXPECT().Update(ctx, user).Return(nil)
+		suite.userRepository.EXPECT().IsPhotoInUse(ctx, previous.Photo).Return(false, nil)
+		suite.photoStorage.EXPECT().Delete(previous).Return(nil)
+
+		suite.Nil(suite.userUpdater.Update(ctx, user))
+	})
+
+	suite.Run("keep the current email, it only changes through the change-email flow", func() {
+		current := &entity.User{ID: userID, Email: "current@example.com"}
+		changed := &entity.User{ID: userID, Email: "attacker@example.com"}
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(current, nil)
+		suite.userRepository.EXPECT().Update(ctx, changed).Return(nil)
+
+		suite.Nil(suite.userUpdater.Update(ctx, changed))
+		suite.Equal("current@example.com", changed.Email)
+	})
+
+	suite.Run("keep the variants of a photo another user still references", func() {
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(previous, nil)
+		suite.userRepository.EXPECT().Update(ctx, user).Return(nil)
+		suite.userRepository.EXPECT().IsPhotoInUse(ctx, previous.Photo).Return(true, nil)
+
+		suite.Nil(suite.userUpdater.Update(ctx, user))
+	})
+
+	suite.Run("reject an update based on an outdated version and release its uploaded photo", func() {
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(previous, nil)
+		suite.userRepository.EXPECT().Update(ctx, user).Return(pkgErrors.Wrap(repository.ErrVersionConflict, "update"))
+		suite.userRepository.EXPECT().IsPhotoInUse(ctx, user.Photo).Return(false, nil)
+		suite.photoStorage.EXPECT().Delete(user).Return(nil)
+
+		suite.Equal(errors.ErrVersionMismatch.Error(), suite.userUpdater.Update(ctx, user))
+	})
+}
+
+func (suite *UserUpdaterTestSuite) TestUserUpdater_ActivateDeactivateUser() {
+	ctx := context.Background()
+	userID := uuid.New()
+	roleID := uuid.New()
+
+	suite.Run("successfully deactivate the current version of a user", func() {
+		user := &entity.User{ID: userID, Status: entity.UserStatusActivated, Version: 3}
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(user, nil)
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, userID).Return(&entity.UserRole{UserID: userID, RoleID: roleID}, nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(&entity.Role{ID: roleID, Name: "Editor"}, nil)
+		suite.userRepository.EXPECT().UpdateUserStatus(ctx, userID, entity.UserStatusDeactivated, int64(3)).Return(nil)
+
+		suite.Nil(suite.userUpdater.ActivateDeactivateUser(ctx, userID, 3))
+	})
+
+	suite.Run("fail to deactivate the last active super admin", func() {
+		user := &entity.User{ID: userID, Status: entity.UserStatusActivated, Version: 3}
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(user, nil)
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, userID).Return(&entity.UserRole{UserID: userID, RoleID: roleID}, nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(&entity.Role{ID: roleID, Name: permission.SuperAdminRole, System: true}, nil)
+		suite.userRoleRepository.EXPECT().CountActivatedByRoleID(ctx, roleID).Return(int64(1), nil)
+
+		suite.Equal(errors.ErrLastSuperAdmin.Error(), suite.userUpdater.ActivateDeactivateUser(ctx, userID, 3))
+	})
+
+	suite.Run("fail to deactivate a super admin when the other one was deactivated meanwhile", func() {
+		user := &entity.User{ID: userID, Status: entity.UserStatusActivated, Version: 3}
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(user, nil)
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, userID).Return(&entity.UserRole{UserID: userID, RoleID: roleID}, nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(&entity.Role{ID: roleID, Name: permission.SuperAdminRole, System: true}, nil)
+		suite.userRoleRepository.EXPECT().CountActivatedByRoleID(ctx, roleID).Return(int64(2), nil)
+		suite.userRepository.EXPECT().
+			UpdateUserStatus(ctx, userID, entity.UserStatusDeactivated, int64(3)).
+			Return(pkgErrors.Wrap(repository.ErrLastSuperAdmin, "update"))
+
+		suite.Equal(errors.ErrLastSuperAdmin.Error(), suite.userUpdater.ActivateDeactivateUser(ctx, userID, 3))
+	})
+
+	suite.Run("reject an outdated version without touching the user", func() {
+		user := &entity.User{ID: userID, Status: entity.UserStatusActivated, Version: 4}
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(user, nil)
+
+		suite.Equal(errors.ErrVersionMismatch.Error(), suite.userUpdater.ActivateDeactivateUser(ctx, userID, 3))
+	})
+
+	suite.Run("reject a version changed between the read and the update", func() {
+		user := &entity.User{ID: userID, Status: entity.UserStatusDeactivated, Version: 3}
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(user, nil)
+		suite.userRepository.EXPECT().UpdateUserStatus(ctx, userID, entity.UserStatusActivated, int64(3)).
+			Return(pkgErrors.Wrap(repository.ErrVersionConflict, "update"))
+
+		suite.Equal(errors.ErrVersionMismatch.Error(), suite.userUpdater.ActivateDeactivateUser(ctx, userID, 3))
+	})
+}
+
+func (suite *UserUpdaterTestSuite) TestUserUpdater_PatchUser() {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	suite.Run("successfully reset the verification of a new phone number", func() {
+		old := &entity.User{ID: userID, PhoneNumber: "+6281200000001", Version: 2}
+		p, err := patch.Parse([]byte(`{"phone_number":"081200000002"}`), resource.UserProfilePatchSchema)
+		suite.Require().NoError(err)
+
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(old, nil)
+		suite.userRepository.EXPECT().Patch(ctx, userID, int64(2), map[string]interface{}{
+			"phone_number":      "+6281200000002",
+			"phone_verified_at": nil,
+			"otp_channel":       entity.OTPChannelEmail,
+		}).Return(nil)
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(&entity.User{ID: userID, Version: 3}, nil)
+
+		user, err := suite.userUpdater.PatchUser(ctx, userID, 2, p)
+		suite.Nil(err)
+		suite.Equal(int64(3), user.Version)
+	})
+
+	suite.Run("successfully clear the photo and release its variants", func() {
+		old := &entity.User{ID: userID, Photo: "/users/user/profile/old", PhotoVariants: "64.jpeg", Version: 2}
+		p, err := patch.Parse([]byte(`{"photo":null}`), resource.UserProfilePatchSchema)
+		suite.Require().NoError(err)
+
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(old, nil)
+		suite.userRepository.EXPECT().Patch(ctx, userID, int64(2), map[string]interface{}{
+			"photo":          "",
+			"photo_variants": "",
+		}).Return(nil)
+		suite.userRepository.EXPECT().IsPhotoInUse(ctx, old.Photo).Return(false, nil)
+		suite.photoStorage.EXPECT().Delete(old).Return(nil)
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(&entity.User{ID: userID, Version: 3}, nil)
+
+		_, err = suite.userUpdater.PatchUser(ctx, userID, 2, p)
+		suite.Nil(err)
+	})
+
+	suite.Run("reject a patch of an outdated version", func() {
+		old := &entity.User{ID: userID, Version: 3}
+		p, err := patch.Parse([]byte(`{"name":"John"}`), resource.UserProfilePatchSchema)
+		suite.Require().NoError(err)
+
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(old, nil)
+		suite.userRepository.EXPECT().Patch(ctx, userID, int64(2), map[string]interface{}{"name": "John"}).
+			Return(pkgErrors.Wrap(repository.ErrVersionConflict, "patch"))
+
+		_, err = suite.userUpdater.PatchUser(ctx, userID, 2, p)
+		suite.Equal(errors.ErrVersionMismatch.Error(), err)
+	})
+}
+
+func (suite *UserUpdaterTestSuite) TestUserUpdater_UpdateRole() {
+	ctx := context.Background()
+	roleID := uuid.New()
+	actorID := uuid.New()
+	actorRoleID := uuid.New()
+	corePermissionID := uuid.New()
+	otherPermissionID := uuid.New()
+	superAdmin := &entity.Role{
+		ID:              roleID,
+		Name:            permission.SuperAdminRole,
+		System:          true,
+		RolePermissions: []*entity.RolePermission{{PermissionID: corePermissionID, Core: true}},
+	}
+
+	suite.Run("successfully add a permission to a system role, keeping its core permissions", func() {
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(superAdmin, nil)
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, actorID).Return(&entity.UserRole{UserID: actorID, RoleID: roleID}, nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(superAdmin, nil)
+		suite.roleRepository.EXPECT().Update(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *entity.Role, rolePermissions []*entity.RolePermission) error {
+				suite.Require().Len(rolePermissions, 2)
+				suite.True(rolePermissions[0].Core)
+				suite.False(rolePermissions[1].Core)
+				return nil
+			})
+
+		suite.Nil(suite.userUpdater.UpdateRole(ctx, roleID, permission.SuperAdminRole, []uuid.UUID{corePermissionID, otherPermissionID}, actorID))
+	})
+
+	suite.Run("fail to rename a system role", func() {
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(superAdmin, nil)
+
+		suite.Equal(errors.ErrSystemRoleProtected.Error(), suite.userUpdater.UpdateRole(ctx, roleID, "Owner", []uuid.UUID{corePermissionID}, actorID))
+	})
+
+	suite.Run("fail to remove a core permission from a system role", func() {
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(superAdmin, nil)
+
+		suite.Equal(errors.ErrCorePermissionRemoved.Error(), suite.userUpdater.UpdateRole(ctx, roleID, permission.SuperAdminRole, []uuid.UUID{otherPermissionID}, actorID))
+	})
+
+	suite.Run("fail to grant a permission the admin does not hold", func() {
+		editor := &entity.Role{ID: roleID, Name: "Editor"}
+		suite.roleRepository.EXPECT().FindByID(ctx, roleID).Return(editor, nil)
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, actorID).Return(&entity.UserRole{UserID: actorID, RoleID: actorRoleID}, nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, actorRoleID).Return(&entity.Role{
+			ID:              actorRoleID,
+			Name:            "Editor",
+			RolePermissions: []*entity.RolePermission{{PermissionID: corePermissionID}},
+		}, nil)
+
+		suite.Equal(errors.ErrPermissionEscalation.Error(), suite.userUpdater.UpdateRole(ctx, roleID, "Editor", []uuid.UUID{corePermissionID, otherPermissionID}, actorID))
+	})
+}
+
+func (suite *UserUpdaterTestSuite) TestUserUpdater_UpdatePermission() {
+	ctx := context.Background()
+	id := uuid.New()
+	declared := &entity.Permission{ID: id, Name: "user.view", Label: "View users", Group: "user"}
+
+	suite.Run("successfully relabel a declared permission", func() {
+		suite.permissionRepo.EXPECT().FindByID(ctx, id).Return(declared, nil)
+		suite.permissionRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		suite.Nil(suite.userUpdater.UpdatePermission(ctx, id, "user.view", "Browse users"))
+	})
+
+	suite.Run("fail to rename a declared permission", func() {
+		suite.permissionRepo.EXPECT().FindByID(ctx, id).Return(declared, nil)
+
+		suite.Equal(errors.ErrDeclaredPermissionProtected.Error(), suite.userUpdater.UpdatePermission(ctx, id, "user.browse", "View users"))
+	})
+
+	suite.Run("fail to rename a declared permission through a patch", func() {
+		suite.permissionRepo.EXPECT().FindByID(ctx, id).Return(declared, nil).Times(2)
+
+		p, err := patch.Parse([]byte(`{"name":"user.browse"}`), resource.PermissionPatchSchema)
+		suite.Require().NoError(err)
+
+		_, err = suite.userUpdater.PatchPermission(ctx, id, p)
+		suite.Equal(errors.ErrDeclaredPermissionProtected.Error(), err)
+	})
+
+	suite.Run("successfully rename a permission created in the cms", func() {
+		custom := &entity.Permission{ID: id, Name: "report.view", Label: "View reports"}
+		suite.permissionRepo.EXPECT().FindByID(ctx, id).Return(custom, nil)
+		suite.permissionRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		suite.Nil(suite.userUpdater.UpdatePermission(ctx, id, "report.browse", "View reports"))
+	})
+}
+
+func (suite *UserUpdaterTestSuite) TestUserUpdater_PatchAdmin() {
+	ctx := context.Background()
+	userID := uuid.New()
+	actorID := uuid.New()
+	superAdminID := uuid.New()
+	editorID := uuid.New()
+	admin := &entity.User{ID: userID, Name: "Budi", Status: entity.UserStatusActivated, Version: 1}
+	superAdmin := &entity.Role{ID: superAdminID, Name: permission.SuperAdminRole, System: true}
+	editor := &entity.Role{ID: editorID, Name: "Editor"}
+
+	p, err := patch.Parse([]byte(`{"name":"Budi Santoso","role_id":"`+editorID.String()+`"}`), resource.AdminPatchSchema)
+	suite.Require().NoError(err)
+
+	suite.Run("successfully patch an admin and move it to another role", func() {
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(admin, nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, editorID).Return(editor, nil)
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, userID).Return(&entity.UserRole{UserID: userID, RoleID: superAdminID}, nil).Times(2)
+		suite.roleRepository.EXPECT().FindByID(ctx, superAdminID).Return(superAdmin, nil)
+		suite.userRoleRepository.EXPECT().CountActivatedByRoleID(ctx, superAdminID).Return(int64(2), nil)
+		suite.userRoleRepository.EXPECT().PatchAdmin(ctx, userID, int64(1), map[string]interface{}{"name": "Budi Santoso"}, editorID).Return(nil)
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(&entity.User{ID: userID, Name: "Budi Santoso", Version: 2}, nil)
+
+		user, err := suite.userUpdater.PatchAdmin(ctx, userID, 1, p, actorID)
+		suite.Nil(err)
+		suite.Equal("Budi Santoso", user.Name)
+	})
+
+	suite.Run("fail to move the last active super admin before writing the admin", func() {
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(admin, nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, editorID).Return(editor, nil)
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, userID).Return(&entity.UserRole{UserID: userID, RoleID: superAdminID}, nil).Times(2)
+		suite.roleRepository.EXPECT().FindByID(ctx, superAdminID).Return(superAdmin, nil)
+		suite.userRoleRepository.EXPECT().CountActivatedByRoleID(ctx, superAdminID).Return(int64(1), nil)
+
+		_, err := suite.userUpdater.PatchAdmin(ctx, userID, 1, p, actorID)
+		suite.Equal(errors.ErrLastSuperAdmin.Error(), err)
+	})
+
+	suite.Run("fail when the move is refused within the transaction", func() {
+		suite.userRepository.EXPECT().GetUserByID(ctx, userID).Return(admin, nil)
+		suite.roleRepository.EXPECT().FindByID(ctx, editorID).Return(editor, nil)
+		suite.userRoleRepository.EXPECT().FindByUserID(ctx, userID).Return(&entity.UserRole{UserID: userID, RoleID: superAdminID}, nil).Times(2)
+		suite.roleRepository.EXPECT().FindByID(ctx, superAdminID).Return(superAdmin, nil)
+		suite.userRoleRepository.EXPECT().CountActivatedByRoleID(ctx, superAdminID).Return(int64(2), nil)
+		suite.userRoleRepository.EXPECT().PatchAdmin(ctx, userID, int64(1), gomock.Any(), editorID).
+			Return(pkgErrors.Wrap(repository.ErrLastSuperAdmin, "patch admin"))
+
+		_, err := suite.userUpdater.PatchAdmin(ctx, userID, 1, p, actorID)
+		suite.Equal(errors.ErrLastSuperAdmin.Error(), err)
+	})
+}
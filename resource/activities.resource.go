@@ -1,6 +1,7 @@
 package resource
 
 import (
+	"gin-starter/common/patch"
 	"gin-starter/entity"
 
 	"github.com/google/uuid"
@@ -22,6 +23,16 @@ type UpdateActivitiesRequest struct {
 	ActivitiesType string    `form:"activities_type" json:"activities_type"`
 }
 
+// ActivitiesPatchSchema declares the fields of an activity a client may patch
+var ActivitiesPatchSchema = &patch.Schema{
+	Fields: map[string]*patch.Field{
+		"user_id":         {Column: "user_id", Rules: "required,uuid"},
+		"title":           {Column: "title", Rules: "required"},
+		"description":     {Column: "description", Nullable: true, Cleared: ""},
+		"activities_type": {Column: "activities_type", Rules: "required"},
+	},
+}
+
 type DeleteActivitiesRequest struct {
 	ID uuid.UUID `uri:"id" binding:"required"`
 }
@@ -34,17 +45,10 @@ type Activities struct {
 	ActivitiesType string    `json:"activities_type"`
 }
 
-type GetActivitiesRequest struct {
-	Query  string `form:"query" json:"query"`
-	Sort   string `form:"sort" json:"sort"`
-	Order  string `form:"order" json:"order"`
-	Limit  int    `form:"limit,default=10" json:"limit"`
-	Offset int    `form:"offset,default=0" json:"offset"`
-}
-
 type GetActivitiesResponse struct {
 	List  []*Activities `json:"list"`
-	Total int64         `json:"total"`
+	Total *int64        `json:"total,omitempty"`
+	Meta  *ListMeta     `json:"meta"`
 }
 
 type GetActivitiesWithoutTotalResponse struct {
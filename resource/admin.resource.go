@@ -2,10 +2,9 @@ package resource
 
 import (
 	"mime/multipart"
-	"os"
 
+	"gin-starter/common/patch"
 	"gin-starter/entity"
-	"gin-starter/utils"
 )
 
 type CreateUserRequest struct {
@@ -17,16 +16,6 @@ type CreateUserRequest struct {
 	Photo       *multipart.FileHeader `form:"photo" json:"photo" binding:"required"`
 }
 
-type CreateAdminRequest struct {
-	Name        string                `form:"name" json:"name" binding:"required"`
-	Email       string                `form:"email" json:"email" binding:"required"`
-	Password    string                `form:"password" json:"password" binding:"required"`
-	DOB         string                `form:"dob" json:"dob" binding:"required"`
-	PhoneNumber string                `form:"phone_number" json:"phone_number" binding:"required"`
-	Photo       *multipart.FileHeader `form:"photo" json:"photo" binding:"required"`
-	RoleID      string                `form:"role_id" json:"role_id" binding:"required"`
-}
-
 type UpdateAdminRequest struct {
 	ID          string                `form:"id" json:"id"`
 	Name        string                `form:"name" json:"name"`
@@ -37,6 +26,18 @@ type UpdateAdminRequest struct {
 	RoleID      string                `form:"role_id" json:"role_id" binding:"required"`
 }
 
+// AdminPatchSchema declares the fields of an admin a CMS user may patch, the role is applied by the service
+var AdminPatchSchema = &patch.Schema{
+	Fields: map[string]*patch.Field{
+		"name":         {Column: "name", Rules: "required"},
+		"email":        {Column: "email", Rules: "required,email"},
+		"dob":          {Column: "dob", Nullable: true, Decode: patch.Date},
+		"phone_number": {Column: "phone_number", Nullable: true, Cleared: ""},
+		"photo":        {Column: "photo", ClearOnly: true, Cleared: ""},
+		"role_id":      {Rules: "required,uuid"},
+	},
+}
+
 type UserAdmin struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
@@ -46,19 +47,23 @@ type UserAdmin struct {
 	DOB         string `json:"dob"`
 	Status      string `json:"status"`
 	Photo       string `json:"photo"`
-	Role        *Role  `json:"role"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	// Photos maps the variant sizes of the photo to their url
+	Photos    map[string]string `json:"photos"`
+	Role      *Role             `json:"role"`
+	CreatedAt string            `json:"created_at"`
+	UpdatedAt string            `json:"updated_at"`
 }
 
 type GetUsersResponse struct {
 	List  []*UserProfile `json:"list"`
-	Total int64          `json:"total"`
+	Total *int64         `json:"total,omitempty"`
+	Meta  *ListMeta      `json:"meta"`
 }
 
 type GetAdminUsersResponse struct {
 	List  []*UserAdmin `json:"list"`
-	Total int64        `json:"total"`
+	Total *int64       `json:"total,omitempty"`
+	Meta  *ListMeta    `json:"meta"`
 }
 
 type DeactivateUserRequest struct {
@@ -77,14 +82,6 @@ type GetAdminUserByIDRequest struct {
 	ID string `uri:"id" binding:"required"`
 }
 
-type GetAdminUsersRequest struct {
-	Query  string `form:"query" json:"query"`
-	Sort   string `form:"sort" json:"sort"`
-	Order  string `form:"order" json:"order"`
-	Limit  int    `form:"limit,default=10" json:"limit"`
-	Offset int    `form:"offset,default=0" json:"offset"`
-}
-
 func NewUserAdmin(user *entity.User) *UserAdmin {
 	otpIsNull := false
 	if user.OTP.String != "" {
@@ -96,13 +93,16 @@ func NewUserAdmin(user *entity.User) *UserAdmin {
 		dob = user.DOB.Time.Format(timeFormat)
 	}
 
+	photo, photos := NewPhotoURLs(user)
+
 	return &UserAdmin{
 		ID:          user.ID.String(),
 		Name:        user.Name,
 		Email:       user.Email,
 		PhoneNumber: user.PhoneNumber,
 		DOB:         dob,
-		Photo:       utils.ImageFullPath(os.Getenv("IMAGE_HOST"), user.Photo),
+		Photo:       photo,
+		Photos:      photos,
 		Status:      user.Status,
 		OTPIsNull:   otpIsNull,
 		Role:        NewRoleResponse(user.UserRole.Role),
@@ -0,0 +1,82 @@
+package resource
+
+import (
+	"time"
+
+	"gin-starter/entity"
+
+	"github.com/google/uuid"
+)
+
+// InviteAdminRequest is a request for inviting an admin
+type InviteAdminRequest struct {
+	Name   string `form:"name" json:"name" binding:"required"`
+	Email  string `form:"email" json:"email" binding:"required,email"`
+	RoleID string `form:"role_id" json:"role_id" binding:"required"`
+}
+
+// AdminInvitationRequest is a request for resending or revoking an invitation
+type AdminInvitationRequest struct {
+	ID string `uri:"id" binding:"required"`
+}
+
+// GetAdminInvitationsRequest is a request for listing the pending invitations
+type GetAdminInvitationsRequest struct {
+	Limit  int `form:"limit,default=10" json:"limit"`
+	Offset int `form:"offset,default=0" json:"offset"`
+}
+
+// AcceptAdminInvitationRequest is a request for accepting an invitation
+type AcceptAdminInvitationRequest struct {
+	Token                string `form:"token" json:"token" binding:"required"`
+	Password             string `form:"password" json:"password" binding:"required"`
+	PasswordConfirmation string `form:"password_confirmation" json:"password_confirmation" binding:"required"`
+}
+
+// GetAdminInvitationsResponse is a response for listing the pending invitations
+type GetAdminInvitationsResponse struct {
+	List  []*AdminInvitation `json:"list"`
+	Total int64              `json:"total"`
+}
+
+// AdminInvitation is a response for an admin invitation
+type AdminInvitation struct {
+	ID         uuid.UUID `json:"id"`
+	UserID     string    `json:"user_id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	RoleID     uuid.UUID `json:"role_id"`
+	Role       string    `json:"role"`
+	Status     string    `json:"status"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	SentCount  int       `json:"sent_count"`
+	LastSentAt time.Time `json:"last_sent_at"`
+	InvitedBy  string    `json:"invited_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewAdminInvitationResponse creates a response for an admin invitation, reporting its status at the given time
+func NewAdminInvitationResponse(invitation *entity.AdminInvitation, now time.Time) *AdminInvitation {
+	res := &AdminInvitation{
+		ID:         invitation.ID,
+		Name:       invitation.Name,
+		Email:      invitation.Email,
+		RoleID:     invitation.RoleID,
+		Status:     invitation.CurrentStatus(now),
+		ExpiresAt:  invitation.ExpiresAt,
+		SentCount:  invitation.SentCount,
+		LastSentAt: invitation.LastSentAt,
+		InvitedBy:  invitation.CreatedBy.String,
+		CreatedAt:  invitation.CreatedAt,
+	}
+
+	if invitation.UserID.Valid {
+		res.UserID = invitation.UserID.UUID.String()
+	}
+
+	if invitation.Role != nil {
+		res.Role = invitation.Role.Name
+	}
+
+	return res
+}
@@ -10,6 +10,11 @@ type LoginRequest struct {
 	Password string `json:"password" form:"password" binding:"required"`
 }
 
+// LogoutRequest is a request for logout, every device of the user is detached when no device token is given
+type LogoutRequest struct {
+	DeviceToken string `json:"device_token" form:"device_token"`
+}
+
 type LoginResponse struct {
 	Token     string `json:"token"`
 	OTPIsNull bool   `json:"otp_is_null"`
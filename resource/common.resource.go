@@ -1,5 +1,7 @@
 package resource
 
+import "gin-starter/common/query"
+
 // PaginationQueryParam is a pagination query param
 type PaginationQueryParam struct {
 	Query    string `form:"query" json:"query"`
@@ -21,3 +23,24 @@ type Meta struct {
 	CurrentPage int `json:"current_page"`
 	TotalPage   int `json:"total_page"`
 }
+
+// ListMeta is the pagination meta of a list response.
+// The cursors are only set on cursor paginated lists, and only when there is a page in that direction.
+type ListMeta struct {
+	Limit          int    `json:"limit"`
+	Offset         int    `json:"offset,omitempty"`
+	NextCursor     string `json:"next_cursor,omitempty"`
+	PrevCursor     string `json:"prev_cursor,omitempty"`
+	TotalEstimated bool   `json:"total_estimated,omitempty"`
+}
+
+// NewListMeta creates the pagination meta of a list response
+func NewListMeta(page *query.Page) *ListMeta {
+	return &ListMeta{
+		Limit:          page.Limit,
+		Offset:         page.Offset,
+		NextCursor:     page.NextCursor,
+		PrevCursor:     page.PrevCursor,
+		TotalEstimated: page.Estimated,
+	}
+}
@@ -0,0 +1,38 @@
+package resource
+
+import (
+	"time"
+
+	"gin-starter/entity"
+
+	"github.com/google/uuid"
+)
+
+// DataExportRequest is a request for getting or downloading a data export
+type DataExportRequest struct {
+	ID string `uri:"id" binding:"required"`
+}
+
+// DataRequest is a response for a personal data export or erasure request
+type DataRequest struct {
+	ID          uuid.UUID  `json:"id"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	ScheduledAt time.Time  `json:"scheduled_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// NewDataRequestResponse creates a response for a personal data export or erasure request
+func NewDataRequestResponse(request *entity.DataRequest) *DataRequest {
+	return &DataRequest{
+		ID:          request.ID,
+		Type:        request.Type,
+		Status:      request.Status,
+		ScheduledAt: request.ScheduledAt,
+		ExpiresAt:   request.ExpiresAt,
+		CompletedAt: request.CompletedAt,
+		CreatedAt:   request.CreatedAt,
+	}
+}
@@ -12,29 +12,28 @@ import (
 	"gin-starter/utils"
 )
 
-type NotificationRegistrationRequest struct {
-	PlayerID string `form:"player_id" json:"player_id" binding:"required"`
-	Type     string `form:"type" json:"type" binding:"required"`
-}
-
 type CreateNotificationRequest struct {
 	UserID  string `form:"user_id" json:"user_id"`
 	Title   string `form:"title" json:"title"`
 	Message string `form:"message" json:"message"`
 	Type    string `form:"type" json:"type"`
 	Extra   string `form:"extra" json:"extra"`
+	// Platforms limits the push to the devices of the platforms, every device is reached when empty
+	Platforms []string `form:"platforms" json:"platforms" binding:"dive,oneof=android ios web"`
+	// TemplateKey sends the published template of the key rendered with Data instead of the title and message
+	TemplateKey string            `form:"template_key" json:"template_key"`
+	Data        map[string]string `form:"data" json:"data"`
 }
 
-type GetNotificationsRequest struct {
-	Sort   string `form:"sort" json:"sort"`
-	Order  string `form:"order" json:"order"`
-	Limit  int    `form:"limit,default=10" json:"limit"`
-	Offset int    `form:"offset,default=0" json:"offset"`
+// NotificationIDsRequest is a request changing the state of notifications of the signed in user
+type NotificationIDsRequest struct {
+	NotificationIDs []uuid.UUID `form:"notification_ids" json:"notification_ids" binding:"required,min=1,max=100"`
 }
 
 type GetNotificationsResponse struct {
 	List  []*Notification `json:"list"`
-	Total int64           `json:"total"`
+	Total *int64          `json:"total,omitempty"`
+	Meta  *ListMeta       `json:"meta"`
 }
 
 type CountUnreadNotificationsResponse struct {
@@ -53,7 +52,9 @@ type Notification struct {
 	Description  string     `json:"description"`
 	Type         string     `json:"type"`
 	IsRead       bool       `json:"is_read"`
+	IsArchived   bool       `json:"is_archived"`
 	Extra        string     `json:"extra"`
+	DeepLink     string     `json:"deep_link"`
 	ExtraData    *ExtraData `json:"extra_data"`
 	HumanizeTime string     `json:"humanize_time"`
 	CreatedAt    string     `json:"created_at"`
@@ -67,7 +68,9 @@ func NewNotificationResponse(notification *entity.Notification, extraData *Extra
 		Description:  notification.Description,
 		Type:         notification.Type,
 		IsRead:       notification.IsRead,
+		IsArchived:   notification.IsArchived,
 		Extra:        notification.Extra,
+		DeepLink:     notification.DeepLink.String,
 		ExtraData:    extraData,
 		HumanizeTime: utils.Time(notification.CreatedAt),
 		CreatedAt:    notification.CreatedAt.Format(timeFormat),
@@ -78,3 +81,19 @@ func NewNotificationResponse(notification *entity.Notification, extraData *Extra
 type CountUnreadNotificationResponse struct {
 	Count int64 `json:"count"`
 }
+
+// NotificationStateEvent is streamed when notifications are read, archived or deleted
+type NotificationStateEvent struct {
+	// NotificationIDs are the notifications changed, every notification of the user when empty
+	NotificationIDs []uuid.UUID `json:"notification_ids"`
+}
+
+// NewNotificationStateEvent creates the streamed state event
+func NewNotificationStateEvent(event *entity.NotificationEvent) *NotificationStateEvent {
+	ids := event.NotificationIDs
+	if ids == nil {
+		ids = make([]uuid.UUID, 0)
+	}
+
+	return &NotificationStateEvent{NotificationIDs: ids}
+}
@@ -0,0 +1,127 @@
+package resource
+
+import (
+	"github.com/google/uuid"
+
+	"gin-starter/entity"
+)
+
+// NotificationTemplateTranslationRequest is the title and body of a template in a language
+type NotificationTemplateTranslationRequest struct {
+	Locale string `form:"locale" json:"locale" binding:"required"`
+	Title  string `form:"title" json:"title" binding:"required"`
+	Body   string `form:"body" json:"body" binding:"required"`
+}
+
+// SaveNotificationTemplateRequest is a request creating or updating a notification template
+type SaveNotificationTemplateRequest struct {
+	Key          string                                    `form:"key" json:"key" binding:"required,max=100"`
+	Type         string                                    `form:"type" json:"type" binding:"required,max=50"`
+	DeepLink     string                                    `form:"deep_link" json:"deep_link"`
+	Translations []*NotificationTemplateTranslationRequest `form:"translations" json:"translations" binding:"required,min=1,dive"`
+}
+
+// ToEntities maps the translations of the request
+func (r *SaveNotificationTemplateRequest) ToEntities() []*entity.NotificationTemplateTranslation {
+	translations := make([]*entity.NotificationTemplateTranslation, 0, len(r.Translations))
+	for _, t := range r.Translations {
+		translations = append(translations, &entity.NotificationTemplateTranslation{
+			Locale: t.Locale,
+			Title:  t.Title,
+			Body:   t.Body,
+		})
+	}
+
+	return translations
+}
+
+// PreviewNotificationTemplateRequest is a request rendering a template with sample data
+type PreviewNotificationTemplateRequest struct {
+	// Locale renders a single language with the fallback of a send, every language is rendered when empty
+	Locale string            `form:"locale" json:"locale"`
+	Data   map[string]string `form:"data" json:"data"`
+}
+
+// NotificationTemplateTranslation is the title and body of a template in a language
+type NotificationTemplateTranslation struct {
+	Locale string `json:"locale"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+}
+
+// NotificationTemplate is a notification template
+type NotificationTemplate struct {
+	ID           uuid.UUID                          `json:"id"`
+	Key          string                             `json:"key"`
+	Type         string                             `json:"type"`
+	DeepLink     string                             `json:"deep_link"`
+	Status       string                             `json:"status"`
+	Variables    []string                           `json:"variables"`
+	Translations []*NotificationTemplateTranslation `json:"translations"`
+	CreatedAt    string                             `json:"created_at"`
+	UpdatedAt    string                             `json:"updated_at"`
+}
+
+// NewNotificationTemplateResponse creates the response of a notification template
+func NewNotificationTemplateResponse(template *entity.NotificationTemplate) *NotificationTemplate {
+	translations := make([]*NotificationTemplateTranslation, 0, len(template.Translations))
+	for _, t := range template.Translations {
+		translations = append(translations, &NotificationTemplateTranslation{
+			Locale: t.Locale,
+			Title:  t.Title,
+			Body:   t.Body,
+		})
+	}
+
+	return &NotificationTemplate{
+		ID:           template.ID,
+		Key:          template.Key,
+		Type:         template.Type,
+		DeepLink:     template.DeepLink,
+		Status:       template.Status,
+		Variables:    template.Variables(),
+		Translations: translations,
+		CreatedAt:    template.CreatedAt.Format(timeFormat),
+		UpdatedAt:    template.UpdatedAt.Format(timeFormat),
+	}
+}
+
+// GetNotificationTemplatesResponse is the list of the notification templates
+type GetNotificationTemplatesResponse struct {
+	List  []*NotificationTemplate `json:"list"`
+	Total *int64                  `json:"total,omitempty"`
+	Meta  *ListMeta               `json:"meta"`
+}
+
+// NotificationTemplatePreview is a template rendered in a language with sample data
+type NotificationTemplatePreview struct {
+	Locale   string `json:"locale"`
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+	DeepLink string `json:"deep_link"`
+	// Missing are the variables the sample data lacks, sending the template without them fails
+	Missing []string `json:"missing"`
+}
+
+// PreviewNotificationTemplateResponse is the preview of a template in every rendered language
+type PreviewNotificationTemplateResponse struct {
+	List []*NotificationTemplatePreview `json:"list"`
+}
+
+// NewPreviewNotificationTemplateResponse creates the response of a template preview
+func NewPreviewNotificationTemplateResponse(rendered []*entity.RenderedNotification) *PreviewNotificationTemplateResponse {
+	list := make([]*NotificationTemplatePreview, 0, len(rendered))
+	for _, r := range rendered {
+		list = append(list, &NotificationTemplatePreview{
+			Locale:   r.Locale,
+			Type:     r.Type,
+			Title:    r.Title,
+			Body:     r.Body,
+			DeepLink: r.DeepLink,
+			Missing:  r.Missing,
+		})
+	}
+
+	return &PreviewNotificationTemplateResponse{List: list}
+}
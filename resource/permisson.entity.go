@@ -1,6 +1,9 @@
 package resource
 
 import (
+	"sort"
+
+	"gin-starter/common/patch"
 	"gin-starter/entity"
 
 	"github.com/google/uuid"
@@ -19,25 +22,79 @@ type UpdatePermissionRequest struct {
 	Label string `form:"label" json:"label"`
 }
 
+// PermissionPatchSchema declares the fields of a permission a CMS user may patch
+var PermissionPatchSchema = &patch.Schema{
+	Fields: map[string]*patch.Field{
+		"name":  {Column: "name", Rules: "required"},
+		"label": {Column: "label", Nullable: true, Cleared: ""},
+	},
+}
+
 // Permission is a base response for permission
 type Permission struct {
-	ID    uuid.UUID `json:"id"`
-	Name  string    `json:"name"`
-	Label string    `json:"label"`
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Label    string    `json:"label"`
+	Group    string    `json:"group"`
+	Orphaned bool      `json:"orphaned"`
+	// Core is set on the permissions of a role which cannot be removed from it
+	Core bool `json:"core,omitempty"`
 }
 
 // NewPermissionResponse returns a permission response
 func NewPermissionResponse(permission *entity.Permission) *Permission {
 	return &Permission{
-		ID:    permission.ID,
-		Name:  permission.Name,
-		Label: permission.Label,
+		ID:       permission.ID,
+		Name:     permission.Name,
+		Label:    permission.Label,
+		Group:    permission.Group,
+		Orphaned: permission.OrphanedAt.Valid,
 	}
 }
 
+// PermissionGroup is the response of the permissions a module declares
+type PermissionGroup struct {
+	Group       string        `json:"group"`
+	Permissions []*Permission `json:"permissions"`
+}
+
+// NewPermissionGroupsResponse groups the permissions declared in code by module, sorted by group and name.
+// Orphaned permissions and the ones created through the CMS belong to no module and are left out.
+func NewPermissionGroupsResponse(permissions []*entity.Permission) []*PermissionGroup {
+	declared := make([]*Permission, 0)
+	for _, p := range permissions {
+		if p.Group == "" || p.OrphanedAt.Valid {
+			continue
+		}
+
+		declared = append(declared, NewPermissionResponse(p))
+	}
+
+	sort.Slice(declared, func(i, j int) bool {
+		if declared[i].Group != declared[j].Group {
+			return declared[i].Group < declared[j].Group
+		}
+
+		return declared[i].Name < declared[j].Name
+	})
+
+	groups := make([]*PermissionGroup, 0)
+	for _, p := range declared {
+		if len(groups) == 0 || groups[len(groups)-1].Group != p.Group {
+			groups = append(groups, &PermissionGroup{Group: p.Group, Permissions: make([]*Permission, 0)})
+		}
+
+		last := groups[len(groups)-1]
+		last.Permissions = append(last.Permissions, p)
+	}
+
+	return groups
+}
+
 // GetPermissionResponse returns a list of permission
 type GetPermissionResponse struct {
-	List  []*Permission `json:"list"`
-	Total int64         `json:"total"`
-	Meta  *Meta         `json:"meta"`
+	List   []*Permission      `json:"list"`
+	Groups []*PermissionGroup `json:"groups,omitempty"`
+	Total  int64              `json:"total"`
+	Meta   *Meta              `json:"meta"`
 }
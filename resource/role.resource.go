@@ -1,6 +1,7 @@
 package resource
 
 import (
+	"gin-starter/common/patch"
 	"gin-starter/entity"
 
 	"github.com/google/uuid"
@@ -30,15 +31,29 @@ type UpdateRoleRequest struct {
 	PermissionIDs []string `form:"permission_id"`
 }
 
+// RolePatchSchema declares the fields of a role a CMS user may patch, permission_ids replaces the permissions of the role
+var RolePatchSchema = &patch.Schema{
+	Fields: map[string]*patch.Field{
+		"name":           {Column: "name", Rules: "required"},
+		"permission_ids": {Rules: "dive,uuid", Decode: patch.Strings},
+	},
+}
+
 // DeleteRoleRequest is a request for delete role
 type DeleteRoleRequest struct {
 	ID string `uri:"id" binding:"required"`
 }
 
+// DeleteRoleQuery holds the role the users of a deleted role are moved to
+type DeleteRoleQuery struct {
+	ReassignRoleID string `form:"reassign_role_id" json:"reassign_role_id"`
+}
+
 // Role is a base response for role
 type Role struct {
 	ID         uuid.UUID     `json:"id"`
 	Name       string        `json:"name"`
+	System     bool          `json:"system"`
 	Permission []*Permission `json:"permissions"`
 }
 
@@ -53,11 +68,9 @@ func NewRoleResponse(role *entity.Role) *Role {
 	if len(role.RolePermissions) > 0 {
 		for _, v := range role.RolePermissions {
 			if v.Permission != nil {
-				permissions = append(permissions, &Permission{
-					ID:    v.Permission.ID,
-					Name:  v.Permission.Name,
-					Label: v.Permission.Label,
-				})
+				permission := NewPermissionResponse(v.Permission)
+				permission.Core = v.Core
+				permissions = append(permissions, permission)
 			}
 		}
 	}
@@ -65,6 +78,7 @@ func NewRoleResponse(role *entity.Role) *Role {
 	return &Role{
 		ID:         role.ID,
 		Name:       role.Name,
+		System:     role.System,
 		Permission: permissions,
 	}
 }
@@ -0,0 +1,145 @@
+package resource
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"gin-starter/entity"
+)
+
+// scheduleTimeFormat formats the times of a schedule with their offset, they are shown in the timezone of the schedule
+const scheduleTimeFormat = time.RFC3339
+
+// ScheduleNotificationRequest is a request scheduling a notification once at SendAt or on the recurrence of Cron
+type ScheduleNotificationRequest struct {
+	// UserID is the recipient, everyone when empty
+	UserID  string `form:"user_id" json:"user_id"`
+	Title   string `form:"title" json:"title"`
+	Message string `form:"message" json:"message"`
+	Type    string `form:"type" json:"type" binding:"max=50"`
+	Extra   string `form:"extra" json:"extra"`
+	// Platforms limits the push to the devices of the platforms, every device is reached when empty
+	Platforms []string `form:"platforms" json:"platforms" binding:"dive,oneof=android ios web"`
+	// TemplateKey sends the template of the key rendered with Data instead of the title and message
+	TemplateKey string            `form:"template_key" json:"template_key" binding:"max=100"`
+	Data        map[string]string `form:"data" json:"data"`
+	RescheduleNotificationRequest
+}
+
+// RescheduleNotificationRequest is the schedule of a notification, either SendAt or Cron
+type RescheduleNotificationRequest struct {
+	SendAt time.Time `form:"send_at" json:"send_at" time_format:"2006-01-02T15:04:05Z07:00"`
+	// Cron is a five field cron expression, like "0 9 * * 1-5"
+	Cron string `form:"cron" json:"cron" binding:"max=100"`
+	// Timezone is the zone Cron is evaluated in, the default timezone of the users when empty
+	Timezone string `form:"timezone" json:"timezone"`
+}
+
+// ScheduledNotification is a notification sent at a time or on a recurrence
+type ScheduledNotification struct {
+	ID          uuid.UUID         `json:"id"`
+	UserID      string            `json:"user_id"`
+	TemplateKey string            `json:"template_key"`
+	Data        map[string]string `json:"data"`
+	Title       string            `json:"title"`
+	Message     string            `json:"message"`
+	Type        string            `json:"type"`
+	Extra       string            `json:"extra"`
+	Platforms   []string          `json:"platforms"`
+	SendAt      string            `json:"send_at"`
+	Cron        string            `json:"cron"`
+	Timezone    string            `json:"timezone"`
+	Status      string            `json:"status"`
+	NextRunAt   string            `json:"next_run_at"`
+	LastRunAt   string            `json:"last_run_at"`
+	RunCount    int               `json:"run_count"`
+	CreatedBy   string            `json:"created_by"`
+	CreatedAt   string            `json:"created_at"`
+	UpdatedAt   string            `json:"updated_at"`
+}
+
+// NewScheduledNotificationResponse creates the response of a scheduled notification
+func NewScheduledNotificationResponse(scheduled *entity.ScheduledNotification) *ScheduledNotification {
+	res := &ScheduledNotification{
+		ID:          scheduled.ID,
+		TemplateKey: scheduled.TemplateKey.String,
+		Data:        scheduled.DataMap(),
+		Title:       scheduled.Title,
+		Message:     scheduled.Message,
+		Type:        scheduled.Type,
+		Extra:       scheduled.Extra,
+		Platforms:   scheduled.PlatformList(),
+		Cron:        scheduled.Cron.String,
+		Timezone:    scheduled.Timezone,
+		Status:      scheduled.Status,
+		RunCount:    scheduled.RunCount,
+		CreatedBy:   scheduled.CreatedBy.String,
+		CreatedAt:   scheduled.CreatedAt.Format(timeFormat),
+		UpdatedAt:   scheduled.UpdatedAt.Format(timeFormat),
+	}
+
+	if scheduled.UserID.Valid {
+		res.UserID = scheduled.UserID.UUID.String()
+	}
+
+	loc, err := time.LoadLocation(scheduled.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	if scheduled.SendAt.Valid {
+		res.SendAt = scheduled.SendAt.Time.In(loc).Format(scheduleTimeFormat)
+	}
+
+	if scheduled.NextRunAt.Valid {
+		res.NextRunAt = scheduled.NextRunAt.Time.In(loc).Format(scheduleTimeFormat)
+	}
+
+	if scheduled.LastRunAt.Valid {
+		res.LastRunAt = scheduled.LastRunAt.Time.In(loc).Format(scheduleTimeFormat)
+	}
+
+	return res
+}
+
+// GetScheduledNotificationsResponse is the list of the scheduled notifications
+type GetScheduledNotificationsResponse struct {
+	List  []*ScheduledNotification `json:"list"`
+	Total *int64                   `json:"total,omitempty"`
+	Meta  *ListMeta                `json:"meta"`
+}
+
+// ScheduledNotificationRun is the delivery result of an occurrence of a scheduled notification
+type ScheduledNotificationRun struct {
+	ID           uuid.UUID `json:"id"`
+	ScheduledFor string    `json:"scheduled_for"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error"`
+	StartedAt    string    `json:"started_at"`
+	FinishedAt   string    `json:"finished_at"`
+}
+
+// NewScheduledNotificationRunResponse creates the response of a scheduled notification run
+func NewScheduledNotificationRunResponse(run *entity.ScheduledNotificationRun) *ScheduledNotificationRun {
+	res := &ScheduledNotificationRun{
+		ID:           run.ID,
+		ScheduledFor: run.ScheduledFor.Format(scheduleTimeFormat),
+		Status:       run.Status,
+		Error:        run.Error,
+		StartedAt:    run.StartedAt.Format(timeFormat),
+	}
+
+	if run.FinishedAt.Valid {
+		res.FinishedAt = run.FinishedAt.Time.Format(timeFormat)
+	}
+
+	return res
+}
+
+// GetScheduledNotificationRunsResponse is the list of the runs of a scheduled notification
+type GetScheduledNotificationRunsResponse struct {
+	List  []*ScheduledNotificationRun `json:"list"`
+	Total *int64                      `json:"total,omitempty"`
+	Meta  *ListMeta                   `json:"meta"`
+}
@@ -0,0 +1,219 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gin-starter/entity"
+)
+
+const (
+	// SCIMContentType is the media type of every SCIM response
+	SCIMContentType = "application/scim+json"
+	// SCIMSchemaUser is the core user schema urn
+	SCIMSchemaUser = "urn:ietf:params:scim:schemas:core:2.0:User"
+	// SCIMSchemaGroup is the core group schema urn
+	SCIMSchemaGroup = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	// SCIMSchemaListResponse is the list response message urn
+	SCIMSchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	// SCIMSchemaPatchOp is the patch operation message urn
+	SCIMSchemaPatchOp = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	// SCIMSchemaError is the error message urn
+	SCIMSchemaError = "urn:ietf:params:scim:api:messages:2.0:Error"
+	// SCIMSchemaServiceProviderConfig is the service provider config urn
+	SCIMSchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	scimTimeFormat = time.RFC3339
+)
+
+// SCIMListRequest is a request for listing SCIM resources
+type SCIMListRequest struct {
+	Filter     string `form:"filter"`
+	StartIndex int    `form:"startIndex,default=1"`
+	Count      int    `form:"count,default=100"`
+}
+
+// SCIMResourceIDRequest is a request for a single SCIM resource
+type SCIMResourceIDRequest struct {
+	ID string `uri:"id" binding:"required"`
+}
+
+// SCIMMeta is the meta attribute of a SCIM resource
+type SCIMMeta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created"`
+	LastModified string `json:"lastModified"`
+	Location     string `json:"location"`
+	Version      string `json:"version"`
+}
+
+// SCIMName is the name attribute of a SCIM user
+type SCIMName struct {
+	Formatted string `json:"formatted,omitempty"`
+}
+
+// SCIMMultiValue is a multi valued attribute such as emails or phone numbers
+type SCIMMultiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// SCIMUser is the SCIM representation of entity.User
+type SCIMUser struct {
+	Schemas      []string          `json:"schemas"`
+	ID           string            `json:"id,omitempty"`
+	ExternalID   string            `json:"externalId,omitempty"`
+	UserName     string            `json:"userName"`
+	Name         *SCIMName         `json:"name,omitempty"`
+	DisplayName  string            `json:"displayName,omitempty"`
+	Password     string            `json:"password,omitempty"`
+	Active       *bool             `json:"active,omitempty"`
+	Emails       []*SCIMMultiValue `json:"emails,omitempty"`
+	PhoneNumbers []*SCIMMultiValue `json:"phoneNumbers,omitempty"`
+	Groups       []*SCIMMultiValue `json:"groups,omitempty"`
+	Meta         *SCIMMeta         `json:"meta,omitempty"`
+}
+
+// SCIMGroup is the SCIM representation of entity.Role
+type SCIMGroup struct {
+	Schemas     []string          `json:"schemas"`
+	ID          string            `json:"id,omitempty"`
+	ExternalID  string            `json:"externalId,omitempty"`
+	DisplayName string            `json:"displayName"`
+	Members     []*SCIMMultiValue `json:"members"`
+	Meta        *SCIMMeta         `json:"meta,omitempty"`
+}
+
+// SCIMListResponse is the SCIM list response
+type SCIMListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int64       `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+// SCIMPatchOperation is a single operation of a SCIM patch request
+type SCIMPatchOperation struct {
+	Op    string          `json:"op" binding:"required"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// SCIMPatchRequest is the SCIM patch request
+type SCIMPatchRequest struct {
+	Schemas    []string              `json:"schemas" binding:"required"`
+	Operations []*SCIMPatchOperation `json:"Operations" binding:"required"`
+}
+
+// SCIMError is the SCIM error response
+type SCIMError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+// NewSCIMError creates a SCIM error response
+func NewSCIMError(status int, scimType, detail string) *SCIMError {
+	return &SCIMError{
+		Schemas:  []string{SCIMSchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
+
+// NewSCIMListResponse creates a SCIM list response
+func NewSCIMListResponse(resources interface{}, total int64, startIndex, itemsPerPage int) *SCIMListResponse {
+	return &SCIMListResponse{
+		Schemas:      []string{SCIMSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: itemsPerPage,
+		Resources:    resources,
+	}
+}
+
+// SCIMVersion returns the weak ETag SCIM clients use to detect concurrent changes
+func SCIMVersion(auditable entity.Auditable) string {
+	return fmt.Sprintf(`W/"%x"`, auditable.UpdatedAt.UnixNano())
+}
+
+// NewSCIMUser creates the SCIM representation of a user
+func NewSCIMUser(user *entity.User, baseURL string) *SCIMUser {
+	active := user.Status == entity.UserStatusActivated
+
+	res := &SCIMUser{
+		Schemas:     []string{SCIMSchemaUser},
+		ID:          user.ID.String(),
+		UserName:    user.Email,
+		Name:        &SCIMName{Formatted: user.Name},
+		DisplayName: user.Name,
+		Active:      &active,
+		Emails: []*SCIMMultiValue{
+			{Value: user.Email, Type: "work", Primary: true},
+		},
+		Groups: make([]*SCIMMultiValue, 0),
+		Meta: &SCIMMeta{
+			ResourceType: "User",
+			Created:      user.CreatedAt.Format(scimTimeFormat),
+			LastModified: user.UpdatedAt.Format(scimTimeFormat),
+			Location:     fmt.Sprintf("%s/Users/%s", baseURL, user.ID),
+			Version:      SCIMVersion(user.Auditable),
+		},
+	}
+
+	if user.PhoneNumber != "" {
+		res.PhoneNumbers = []*SCIMMultiValue{
+			{Value: user.PhoneNumber, Type: "work"},
+		}
+	}
+
+	if user.UserRole != nil && user.UserRole.Role != nil {
+		res.Groups = append(res.Groups, &SCIMMultiValue{
+			Value:   user.UserRole.RoleID.String(),
+			Display: user.UserRole.Role.Name,
+			Ref:     fmt.Sprintf("%s/Groups/%s", baseURL, user.UserRole.RoleID),
+		})
+	}
+
+	return res
+}
+
+// NewSCIMGroup creates the SCIM representation of a role and its members
+func NewSCIMGroup(role *entity.Role, members []*entity.UserRole, baseURL string) *SCIMGroup {
+	res := &SCIMGroup{
+		Schemas:     []string{SCIMSchemaGroup},
+		ID:          role.ID.String(),
+		DisplayName: role.Name,
+		Members:     make([]*SCIMMultiValue, 0),
+		Meta: &SCIMMeta{
+			ResourceType: "Group",
+			Created:      role.CreatedAt.Format(scimTimeFormat),
+			LastModified: role.UpdatedAt.Format(scimTimeFormat),
+			Location:     fmt.Sprintf("%s/Groups/%s", baseURL, role.ID),
+			Version:      SCIMVersion(role.Auditable),
+		},
+	}
+
+	for _, m := range members {
+		member := &SCIMMultiValue{
+			Value: m.UserID.String(),
+			Ref:   fmt.Sprintf("%s/Users/%s", baseURL, m.UserID),
+		}
+
+		if m.User != nil {
+			member.Display = m.User.Name
+		}
+
+		res.Members = append(res.Members, member)
+	}
+
+	return res
+}
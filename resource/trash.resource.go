@@ -0,0 +1,71 @@
+package resource
+
+import (
+	"time"
+
+	"gin-starter/entity"
+
+	"github.com/google/uuid"
+)
+
+// GetTrashRequest is a request for listing deleted users or roles
+type GetTrashRequest struct {
+	Type   string `form:"type" json:"type" binding:"required"`
+	Limit  int    `form:"limit,default=10" json:"limit"`
+	Offset int    `form:"offset,default=0" json:"offset"`
+}
+
+// RestoreTrashRequest is a request for restoring a deleted user or role
+type RestoreTrashRequest struct {
+	Type string `uri:"type" binding:"required"`
+	ID   string `uri:"id" binding:"required"`
+}
+
+// GetTrashResponse is a response for listing deleted users or roles
+type GetTrashResponse struct {
+	List  []*TrashItem `json:"list"`
+	Total int64        `json:"total"`
+}
+
+// TrashItem is a response for a deleted user or role
+type TrashItem struct {
+	ID        uuid.UUID `json:"id"`
+	Type      string    `json:"type"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email,omitempty"`
+	Role      string    `json:"role,omitempty"`
+	DeletedBy string    `json:"deleted_by"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// NewUserTrashItem creates a trash item of a deleted user
+func NewUserTrashItem(user *entity.User, purgeAt time.Time) *TrashItem {
+	item := &TrashItem{
+		ID:        user.ID,
+		Type:      "user",
+		Name:      user.Name,
+		Email:     user.Email,
+		DeletedBy: user.DeletedBy.String,
+		DeletedAt: user.DeletedAt.Time,
+		PurgeAt:   purgeAt,
+	}
+
+	if user.UserRole != nil && user.UserRole.Role != nil {
+		item.Role = user.UserRole.Role.Name
+	}
+
+	return item
+}
+
+// NewRoleTrashItem creates a trash item of a deleted role
+func NewRoleTrashItem(role *entity.Role, purgeAt time.Time) *TrashItem {
+	return &TrashItem{
+		ID:        role.ID,
+		Type:      "role",
+		Name:      role.Name,
+		DeletedBy: role.DeletedBy.String,
+		DeletedAt: role.DeletedAt.Time,
+		PurgeAt:   purgeAt,
+	}
+}
@@ -3,7 +3,10 @@ package resource
 import (
 	"mime/multipart"
 	"os"
+	"strconv"
+	"time"
 
+	"gin-starter/common/patch"
 	"gin-starter/entity"
 	"gin-starter/utils"
 )
@@ -15,23 +18,62 @@ const (
 type UpdateUserRequest struct {
 	ID          string                `form:"id" json:"id"`
 	Name        string                `form:"name" json:"name"`
-	Email       string                `form:"email" json:"email"`
 	DOB         string                `form:"dob" json:"dob"`
 	PhoneNumber string                `form:"phone_number" json:"phone_number"`
 	Photo       *multipart.FileHeader `form:"photo" json:"photo"`
 }
 
+// UserProfilePatchSchema declares the fields of the profile a user may patch,
+// the photo can only be cleared as it is uploaded through the PUT endpoint
+var UserProfilePatchSchema = &patch.Schema{
+	Fields: map[string]*patch.Field{
+		"name":         {Column: "name", Rules: "required"},
+		"dob":          {Column: "dob", Nullable: true, Decode: patch.Date},
+		"phone_number": {Column: "phone_number", Nullable: true, Cleared: ""},
+		"photo":        {Column: "photo", ClearOnly: true, Cleared: ""},
+	},
+}
+
 type UserProfile struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
 	Email       string `json:"email"`
 	OTPIsNull   bool   `json:"otp_is_null"`
 	PhoneNumber string `json:"phone_number"`
-	DOB         string `json:"dob"`
-	Status      string `json:"status"`
-	Photo       string `json:"photo"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	// PhoneVerifiedAt is empty while the phone number is not verified
+	PhoneVerifiedAt string `json:"phone_verified_at"`
+	OTPChannel      string `json:"otp_channel"`
+	DOB             string `json:"dob"`
+	Status          string `json:"status"`
+	Photo           string `json:"photo"`
+	// Photos maps the variant sizes of the photo to their url
+	Photos    map[string]string `json:"photos"`
+	CreatedAt string            `json:"created_at"`
+	UpdatedAt string            `json:"updated_at"`
+}
+
+// NewPhotoURLs returns the urls of the photo variants of the user along with the url of the largest one,
+// which is kept as the photo of the responses
+func NewPhotoURLs(user *entity.User) (string, map[string]string) {
+	host := os.Getenv("IMAGE_HOST")
+	urls := make(map[string]string)
+	largest, photo := -1, ""
+
+	for variant, file := range user.PhotoFiles() {
+		urls[variant] = utils.ImageFullPath(host, file)
+
+		// legacy photos have a single original file whose size is unknown
+		size, err := strconv.Atoi(variant)
+		if err != nil {
+			size = 0
+		}
+
+		if size > largest {
+			largest, photo = size, urls[variant]
+		}
+	}
+
+	return photo, urls
 }
 
 func NewUserProfile(user *entity.User) *UserProfile {
@@ -45,17 +87,32 @@ func NewUserProfile(user *entity.User) *UserProfile {
 		dob = user.DOB.Time.Format(timeFormat)
 	}
 
+	phoneVerifiedAt := ""
+	if user.PhoneVerifiedAt.Valid {
+		phoneVerifiedAt = user.PhoneVerifiedAt.Time.Format(timeFormat)
+	}
+
+	otpChannel := entity.OTPChannelEmail
+	if user.SendsOTPBySMS() {
+		otpChannel = entity.OTPChannelSMS
+	}
+
+	photo, photos := NewPhotoURLs(user)
+
 	return &UserProfile{
-		ID:          user.ID.String(),
-		Name:        user.Name,
-		Email:       user.Email,
-		PhoneNumber: user.PhoneNumber,
-		DOB:         dob,
-		Photo:       utils.ImageFullPath(os.Getenv("IMAGE_HOST"), user.Photo),
-		Status:      user.Status,
-		OTPIsNull:   otpIsNull,
-		CreatedAt:   user.CreatedAt.Format(timeFormat),
-		UpdatedAt:   user.UpdatedAt.Format(timeFormat),
+		ID:              user.ID.String(),
+		Name:            user.Name,
+		Email:           user.Email,
+		PhoneNumber:     user.PhoneNumber,
+		PhoneVerifiedAt: phoneVerifiedAt,
+		OTPChannel:      otpChannel,
+		DOB:             dob,
+		Photo:           photo,
+		Photos:          photos,
+		Status:          user.Status,
+		OTPIsNull:       otpIsNull,
+		CreatedAt:       user.CreatedAt.Format(timeFormat),
+		UpdatedAt:       user.UpdatedAt.Format(timeFormat),
 	}
 }
 
@@ -82,3 +139,51 @@ type GetUserByForgotPasswordTokenRequest struct {
 type VerifyOTPRequest struct {
 	Code string `form:"code" json:"code" binding:"required"`
 }
+
+// ChangeEmailRequest is a request for changing the email of the signed in user
+type ChangeEmailRequest struct {
+	Email string `form:"email" json:"email" binding:"required,email"`
+}
+
+// EmailChangeTokenRequest is a request for confirming or cancelling an email change
+type EmailChangeTokenRequest struct {
+	Token string `form:"token" json:"token" binding:"required"`
+}
+
+// VerifyPhoneRequest is a request for verifying the phone number of the signed in user
+type VerifyPhoneRequest struct {
+	Code string `form:"code" json:"code" binding:"required"`
+}
+
+// OTPChannelRequest is a request for choosing the channel of the login code of the signed in user
+type OTPChannelRequest struct {
+	Channel string `form:"channel" json:"channel" binding:"required,oneof=email sms"`
+}
+
+// PhoneVerification is a response for a phone verification code sent by SMS
+type PhoneVerification struct {
+	PhoneNumber string    `json:"phone_number"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// NewPhoneVerificationResponse creates a response for a phone verification code sent by SMS
+func NewPhoneVerificationResponse(verification *entity.PhoneVerification) *PhoneVerification {
+	return &PhoneVerification{
+		PhoneNumber: verification.PhoneNumber,
+		ExpiresAt:   verification.ExpiresAt,
+	}
+}
+
+// EmailChange is a response for a pending email change
+type EmailChange struct {
+	NewEmail  string    `json:"new_email"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewEmailChangeResponse creates a response for a pending email change
+func NewEmailChangeResponse(change *entity.EmailChange) *EmailChange {
+	return &EmailChange{
+		NewEmail:  change.NewEmail,
+		ExpiresAt: change.ExpiresAt,
+	}
+}
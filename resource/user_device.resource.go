@@ -0,0 +1,59 @@
+package resource
+
+import (
+	"github.com/google/uuid"
+
+	"gin-starter/entity"
+)
+
+// RegisterDeviceRequest is a request for registering the device of the signed in user
+type RegisterDeviceRequest struct {
+	// Token is the registration token, or the player id on OneSignal
+	Token      string `form:"token" json:"token" binding:"required"`
+	Platform   string `form:"platform" json:"platform" binding:"required,oneof=android ios web"`
+	AppVersion string `form:"app_version" json:"app_version" binding:"max=64"`
+}
+
+// UnregisterDeviceRequest is a request for unregistering a device of the signed in user
+type UnregisterDeviceRequest struct {
+	Token string `form:"token" json:"token" binding:"required"`
+}
+
+// UserDevice is a device push notifications are delivered to
+type UserDevice struct {
+	ID         uuid.UUID `json:"id"`
+	Provider   string    `json:"provider"`
+	Token      string    `json:"token"`
+	Platform   string    `json:"platform"`
+	AppVersion string    `json:"app_version"`
+	LastSeenAt string    `json:"last_seen_at"`
+	CreatedAt  string    `json:"created_at"`
+}
+
+// NewUserDeviceResponse creates a new UserDevice response
+func NewUserDeviceResponse(device *entity.UserDevice) *UserDevice {
+	return &UserDevice{
+		ID:         device.ID,
+		Provider:   device.Provider,
+		Token:      device.Token,
+		Platform:   device.Platform,
+		AppVersion: device.AppVersion.String,
+		LastSeenAt: device.LastSeenAt.Format(timeFormat),
+		CreatedAt:  device.CreatedAt.Format(timeFormat),
+	}
+}
+
+// GetUserDevicesResponse is a response for the devices of a user
+type GetUserDevicesResponse struct {
+	List []*UserDevice `json:"list"`
+}
+
+// NewGetUserDevicesResponse creates a new GetUserDevicesResponse
+func NewGetUserDevicesResponse(devices []*entity.UserDevice) *GetUserDevicesResponse {
+	list := make([]*UserDevice, 0, len(devices))
+	for _, device := range devices {
+		list = append(list, NewUserDeviceResponse(device))
+	}
+
+	return &GetUserDevicesResponse{List: list}
+}
@@ -0,0 +1,7 @@
+package resource
+
+// ExportUsersRequest is a request for exporting users or admins, the list filters are parsed by the query package
+type ExportUsersRequest struct {
+	Format  string `form:"format,default=xlsx" json:"format"`
+	Columns string `form:"columns" json:"columns"`
+}
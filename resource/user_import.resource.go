@@ -0,0 +1,105 @@
+package resource
+
+import (
+	"mime/multipart"
+	"time"
+
+	"gin-starter/entity"
+
+	"github.com/google/uuid"
+)
+
+// ImportUsersRequest is a request for importing users from a CSV or XLSX file
+type ImportUsersRequest struct {
+	File        *multipart.FileHeader `form:"file" binding:"required"`
+	DryRun      bool                  `form:"dry_run"`
+	CreateRoles bool                  `form:"create_roles"`
+}
+
+// GetUserImportRequest is a request for getting a user import by id
+type GetUserImportRequest struct {
+	ID string `uri:"id" binding:"required"`
+}
+
+// UserImportRow is a response for a row of an import file
+type UserImportRow struct {
+	RowNumber   int        `json:"row_number"`
+	Name        string     `json:"name"`
+	Email       string     `json:"email"`
+	PhoneNumber string     `json:"phone_number"`
+	DOB         string     `json:"dob"`
+	Role        string     `json:"role"`
+	Status      string     `json:"status"`
+	Message     string     `json:"message"`
+	UserID      *uuid.UUID `json:"user_id"`
+}
+
+// UserImportPreview is a response for a dry run import
+type UserImportPreview struct {
+	TotalRows   int              `json:"total_rows"`
+	ValidRows   int              `json:"valid_rows"`
+	InvalidRows int              `json:"invalid_rows"`
+	Rows        []*UserImportRow `json:"rows"`
+}
+
+// UserImport is a response for a user import
+type UserImport struct {
+	ID          uuid.UUID  `json:"id"`
+	FileName    string     `json:"file_name"`
+	Status      string     `json:"status"`
+	CreateRoles bool       `json:"create_roles"`
+	TotalRows   int        `json:"total_rows"`
+	CreatedRows int        `json:"created_rows"`
+	FailedRows  int        `json:"failed_rows"`
+	CreatedAt   time.Time  `json:"created_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+}
+
+// NewUserImportRowResponse creates a new user import row response
+func NewUserImportRowResponse(row *entity.UserImportRow) *UserImportRow {
+	return &UserImportRow{
+		RowNumber:   row.RowNumber,
+		Name:        row.Name,
+		Email:       row.Email,
+		PhoneNumber: row.PhoneNumber,
+		DOB:         row.DOB,
+		Role:        row.Role,
+		Status:      row.Status,
+		Message:     row.Message,
+		UserID:      row.UserID,
+	}
+}
+
+// NewUserImportPreviewResponse creates a new dry run import response
+func NewUserImportPreviewResponse(rows []*entity.UserImportRow) *UserImportPreview {
+	res := &UserImportPreview{
+		TotalRows: len(rows),
+		Rows:      make([]*UserImportRow, 0, len(rows)),
+	}
+
+	for _, row := range rows {
+		if row.Status == entity.UserImportRowStatusInvalid {
+			res.InvalidRows++
+		} else {
+			res.ValidRows++
+		}
+		res.Rows = append(res.Rows, NewUserImportRowResponse(row))
+	}
+
+	return res
+}
+
+// NewUserImportResponse creates a new user import response
+func NewUserImportResponse(userImport *entity.UserImport) *UserImport {
+	return &UserImport{
+		ID:          userImport.ID,
+		FileName:    userImport.FileName,
+		Status:      userImport.Status,
+		CreateRoles: userImport.CreateRoles,
+		TotalRows:   userImport.TotalRows,
+		CreatedRows: userImport.CreatedRows,
+		FailedRows:  userImport.FailedRows,
+		CreatedAt:   userImport.CreatedAt,
+		FinishedAt:  userImport.FinishedAt,
+	}
+}
@@ -0,0 +1,56 @@
+package resource
+
+import (
+	"time"
+
+	"gin-starter/entity"
+)
+
+// UpdatePreferencesRequest is a request for changing preferences, keyed by preference key.
+// Keys left out are unchanged and a null value resets the key to its default.
+type UpdatePreferencesRequest map[string]interface{}
+
+// UserPreferenceSetting is a response for a preference of a user in the CMS
+type UserPreferenceSetting struct {
+	Key     string      `json:"key"`
+	Type    string      `json:"type"`
+	Value   interface{} `json:"value"`
+	Default interface{} `json:"default"`
+	Options []string    `json:"options,omitempty"`
+	// Overridden tells whether the user set the preference away from its default
+	Overridden bool       `json:"overridden"`
+	UpdatedBy  string     `json:"updated_by,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+// NewUserPreferenceSettings creates a response listing every preference of the schema for a user
+func NewUserPreferenceSettings(stored []*entity.UserPreference) []*UserPreferenceSetting {
+	preferences := entity.NewPreferences(stored)
+
+	storedByKey := make(map[string]*entity.UserPreference, len(stored))
+	for _, preference := range stored {
+		storedByKey[preference.Key] = preference
+	}
+
+	settings := make([]*UserPreferenceSetting, 0, len(entity.PreferenceSchema))
+
+	for _, definition := range entity.PreferenceSchema {
+		setting := &UserPreferenceSetting{
+			Key:     definition.Key,
+			Type:    definition.Type,
+			Value:   preferences[definition.Key],
+			Default: definition.Default,
+			Options: definition.Options,
+		}
+
+		if preference, ok := storedByKey[definition.Key]; ok {
+			setting.Overridden = true
+			setting.UpdatedBy = preference.UpdatedBy.String
+			setting.UpdatedAt = &preference.UpdatedAt
+		}
+
+		settings = append(settings, setting)
+	}
+
+	return settings
+}
@@ -53,3 +53,27 @@ func ErrorAPIResponse(code int, message string) APIResponseList {
 		Data:    nil,
 	}
 }
+
+// ErrorAPIResponseWithData is an error response carrying data, such as the current representation of a resource
+// when an update was based on an outdated version
+func ErrorAPIResponseWithData(code int, message string, data interface{}) APIResponseList {
+	return &apiResponseList{
+		Code:    code,
+		Message: message,
+		Data:    data,
+	}
+}
+
+// ErrorAPIResponseWithField is an error response naming the field of the request the error is about,
+// it is a plain error response when field is empty
+func ErrorAPIResponseWithField(code int, message, field string) APIResponseList {
+	if field == "" {
+		return ErrorAPIResponse(code, message)
+	}
+
+	return &apiResponseList{
+		Code:    code,
+		Message: message,
+		Data:    map[string]string{"field": field},
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"gin-starter/config"
 	"gin-starter/utils"
+	"io"
 	"mime/multipart"
 	"strings"
 
@@ -73,6 +74,25 @@ func (s *S3Bucket) Upload(f *multipart.FileHeader, folder string) (string, error
 	return fileStored, nil
 }
 
+// UploadObject uploads the content of r to the given path of the bucket
+func (s *S3Bucket) UploadObject(r io.Reader, path, contentType string) error {
+	uploader := s3manager.NewUploader(s.session)
+
+	_, err := uploader.Upload(&s3manager.UploadInput{
+		Bucket:      aws.String(s.cfg.AWS.BucketName),
+		ACL:         aws.String("public-read"),
+		Key:         aws.String(strings.TrimPrefix(path, "/")),
+		ContentType: aws.String(contentType),
+		Body:        r,
+	})
+
+	if err != nil {
+		return fmt.Errorf("[S3CloudStorage] error while uploading object : %s", err)
+	}
+
+	return nil
+}
+
 // Delete delete file from bucket
 func (s *S3Bucket) Delete(path string) error {
 	svc := s3.New(s.session)
@@ -96,3 +116,18 @@ func (s *S3Bucket) Delete(path string) error {
 
 	return nil
 }
+
+// Download opens a file from bucket
+func (s *S3Bucket) Download(path string) (io.ReadCloser, error) {
+	svc := s3.New(s.session)
+
+	out, err := svc.GetObject(&s3.GetObjectInput{
+		Bucket: &s.cfg.AWS.BucketName,
+		Key:    &path,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Body, nil
+}
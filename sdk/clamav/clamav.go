@@ -0,0 +1,170 @@
+// Package clamav scans content with a ClamAV daemon over its INSTREAM protocol.
+package clamav
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gin-starter/config"
+)
+
+// chunkSize is the size of the chunks streamed to the daemon, clamd rejects chunks over its StreamMaxLength
+const chunkSize = 64 * 1024
+
+// Client scans content with a ClamAV daemon.
+// Every scan opens its own connection so a client is safe for concurrent use.
+type Client struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClient initiate clamav client
+func NewClient(cfg config.Config) (*Client, error) {
+	timeout, err := time.ParseDuration(cfg.ClamAV.Timeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ClamAVClient-New] invalid timeout")
+	}
+
+	u, err := url.Parse(cfg.ClamAV.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ClamAVClient-New] invalid address")
+	}
+
+	c := &Client{network: u.Scheme, timeout: timeout}
+
+	switch u.Scheme {
+	case "tcp":
+		c.address = u.Host
+	case "unix":
+		c.address = u.Path
+	default:
+		return nil, fmt.Errorf("[ClamAVClient-New] unsupported address scheme %q", u.Scheme)
+	}
+
+	return c, nil
+}
+
+// Ping checks that the daemon is reachable
+func (c *Client) Ping(ctx context.Context) error {
+	reply, err := c.command(ctx, "PING", nil)
+	if err != nil {
+		return err
+	}
+
+	if reply != "PONG" {
+		return fmt.Errorf("[ClamAVClient-Ping] unexpected reply %q", reply)
+	}
+
+	return nil
+}
+
+// Scan streams the content read from r to the daemon and returns the name of the detected signature,
+// empty when the content is clean
+func (c *Client) Scan(ctx context.Context, r io.Reader) (string, error) {
+	reply, err := c.command(ctx, "INSTREAM", func(w io.Writer) error {
+		return writeChunks(w, r)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// replies look like "stream: OK", "stream: Eicar-Signature FOUND" or "INSTREAM size limit exceeded. ERROR"
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+
+	switch {
+	case result == "OK":
+		return "", nil
+	case strings.HasSuffix(result, " FOUND"):
+		return strings.TrimSuffix(result, " FOUND"), nil
+	default:
+		return "", fmt.Errorf("[ClamAVClient-Scan] scan failed: %s", reply)
+	}
+}
+
+// command sends a null terminated command, lets body write its payload and reads the null terminated reply
+func (c *Client) command(ctx context.Context, name string, body func(io.Writer) error) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, c.network, c.address)
+	if err != nil {
+		return "", errors.Wrap(err, "[ClamAVClient-Command] error while connecting to clamd")
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return "", errors.Wrap(err, "[ClamAVClient-Command] error while setting deadline")
+		}
+	}
+
+	w := bufio.NewWriterSize(conn, chunkSize+4)
+
+	if _, err := w.WriteString("z" + name + "\x00"); err != nil {
+		return "", errors.Wrap(err, "[ClamAVClient-Command] error while sending command")
+	}
+
+	if body != nil {
+		if err := body(w); err != nil {
+			return "", errors.Wrap(err, "[ClamAVClient-Command] error while streaming content")
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return "", errors.Wrap(err, "[ClamAVClient-Command] error while sending command")
+	}
+
+	reply, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil && (err != io.EOF || len(reply) == 0) {
+		return "", errors.Wrap(err, "[ClamAVClient-Command] error while reading reply")
+	}
+
+	return string(bytes.TrimRight(reply, "\x00\n")), nil
+}
+
+// writeChunks writes r as length prefixed chunks terminated by an empty chunk
+func writeChunks(w io.Writer, r io.Reader) error {
+	buf := make([]byte, chunkSize)
+	size := make([]byte, 4)
+
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+
+			if _, err := w.Write(size); err != nil {
+				return err
+			}
+
+			if _, err := w.Write(buf[:n]); err != nil {
+				return err
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	binary.BigEndian.PutUint32(size, 0)
+	_, err := w.Write(size)
+
+	return err
+}
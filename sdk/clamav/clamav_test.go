@@ -0,0 +1,197 @@
+package clamav_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"gin-starter/config"
+	"gin-starter/sdk/clamav"
+)
+
+// eicar is the standard antivirus test file, split so this file is not flagged itself
+var eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$` + `EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// clamDaemon is a minimal in-process clamd speaking the z-prefixed PING and INSTREAM commands
+type clamDaemon struct {
+	listener net.Listener
+	// maxStream makes INSTREAM fail like clamd does past its StreamMaxLength, zero disables it
+	maxStream int
+	wg        sync.WaitGroup
+}
+
+func newClamDaemon(t *testing.T, network, address string, maxStream int) *clamDaemon {
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &clamDaemon{listener: listener, maxStream: maxStream}
+
+	d.wg.Add(1)
+	go d.serve()
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+		d.wg.Wait()
+	})
+
+	return d
+}
+
+func (d *clamDaemon) serve() {
+	defer d.wg.Done()
+
+	for {
+		conn, err := d.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			defer conn.Close()
+			d.handle(conn)
+		}()
+	}
+}
+
+func (d *clamDaemon) handle(conn net.Conn) {
+	r := bufio.NewReader(conn)
+
+	command, err := r.ReadString(0)
+	if err != nil {
+		return
+	}
+
+	switch strings.TrimSuffix(command, "\x00") {
+	case "zPING":
+		_, _ = conn.Write([]byte("PONG\x00"))
+	case "zINSTREAM":
+		var content bytes.Buffer
+		size := make([]byte, 4)
+
+		for {
+			if _, err := io.ReadFull(r, size); err != nil {
+				return
+			}
+
+			n := binary.BigEndian.Uint32(size)
+			if n == 0 {
+				break
+			}
+
+			if _, err := io.CopyN(&content, r, int64(n)); err != nil {
+				return
+			}
+
+			if d.maxStream > 0 && content.Len() > d.maxStream {
+				_, _ = conn.Write([]byte("INSTREAM size limit exceeded. ERROR\x00"))
+				return
+			}
+		}
+
+		if strings.Contains(content.String(), eicar) {
+			_, _ = conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+			return
+		}
+
+		_, _ = conn.Write([]byte("stream: OK\x00"))
+	default:
+		_, _ = conn.Write([]byte("UNKNOWN COMMAND\x00"))
+	}
+}
+
+type ClamAVTestSuite struct {
+	suite.Suite
+}
+
+func TestClamAVTestSuite(t *testing.T) {
+	suite.Run(t, new(ClamAVTestSuite))
+}
+
+func (suite *ClamAVTestSuite) newClient(address string) *clamav.Client {
+	cfg := config.Config{}
+	cfg.ClamAV.Address = address
+	cfg.ClamAV.Timeout = "5s"
+
+	client, err := clamav.NewClient(cfg)
+	suite.Require().NoError(err)
+
+	return client
+}
+
+func (suite *ClamAVTestSuite) TestScan() {
+	ctx := context.Background()
+	daemon := newClamDaemon(suite.T(), "tcp", "127.0.0.1:0", 0)
+	client := suite.newClient("tcp://" + daemon.listener.Addr().String())
+
+	suite.Run("successfully ping the daemon", func() {
+		suite.Nil(client.Ping(ctx))
+	})
+
+	suite.Run("successfully scan clean content spanning several chunks", func() {
+		signature, err := client.Scan(ctx, bytes.NewReader(bytes.Repeat([]byte("a"), 200*1024)))
+
+		suite.Nil(err)
+		suite.Empty(signature)
+	})
+
+	suite.Run("successfully detect infected content", func() {
+		signature, err := client.Scan(ctx, strings.NewReader(eicar))
+
+		suite.Nil(err)
+		suite.Equal("Eicar-Test-Signature", signature)
+	})
+
+	suite.Run("fail when the daemon is unreachable", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		suite.Require().NoError(err)
+		address := listener.Addr().String()
+		suite.Require().NoError(listener.Close())
+
+		_, err = suite.newClient("tcp://"+address).Scan(ctx, strings.NewReader("a"))
+		suite.NotNil(err)
+	})
+}
+
+func (suite *ClamAVTestSuite) TestScan_SizeLimit() {
+	daemon := newClamDaemon(suite.T(), "tcp", "127.0.0.1:0", 10)
+	client := suite.newClient("tcp://" + daemon.listener.Addr().String())
+
+	_, err := client.Scan(context.Background(), strings.NewReader("more than ten bytes"))
+	suite.NotNil(err)
+}
+
+func (suite *ClamAVTestSuite) TestScan_UnixSocket() {
+	socket := suite.T().TempDir() + "/clamd.sock"
+	newClamDaemon(suite.T(), "unix", socket, 0)
+
+	signature, err := suite.newClient("unix://"+socket).Scan(context.Background(), strings.NewReader(eicar))
+
+	suite.Nil(err)
+	suite.Equal("Eicar-Test-Signature", signature)
+}
+
+func (suite *ClamAVTestSuite) TestNewClient() {
+	cfg := config.Config{}
+	cfg.ClamAV.Timeout = "5s"
+
+	cfg.ClamAV.Address = "http://localhost:3310"
+	_, err := clamav.NewClient(cfg)
+	suite.NotNil(err)
+
+	cfg.ClamAV.Address = "tcp://localhost:3310"
+	cfg.ClamAV.Timeout = "soon"
+	_, err = clamav.NewClient(cfg)
+	suite.NotNil(err)
+}
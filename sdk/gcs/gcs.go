@@ -148,6 +148,46 @@ func (g *GoogleCloudStorage) UploadSavedFile(filepath, folder string) (string, e
 	return u.String(), nil
 }
 
+// UploadObject uploads the content of r to the given path of the bucket
+func (g *GoogleCloudStorage) UploadObject(r io.Reader, path, contentType string) error {
+	bucket := g.cfg.Google.StorageBucketName
+
+	ctx := context.Background()
+
+	storageClient, err := storage.NewClient(ctx, option.WithCredentialsFile(os.Getenv("GOOGLE_SA")))
+
+	if err != nil {
+		return errors.Wrap(err, "[CloudStorageService-UploadObject] error get config json")
+	}
+
+	defer func() {
+		if err := storageClient.Close(); err != nil {
+			fmt.Println("error while closing storage client :", err)
+		}
+	}()
+
+	object := storageClient.Bucket(bucket).Object(strings.TrimPrefix(path, "/"))
+
+	sw := object.NewWriter(ctx)
+	sw.ContentType = contentType
+
+	if _, err := io.Copy(sw, r); err != nil {
+		_ = sw.Close()
+		return errors.Wrap(err, "[CloudStorageService-UploadObject] error copy file")
+	}
+
+	if err := sw.Close(); err != nil {
+		return errors.Wrap(err, "[CloudStorageService-UploadObject] error close file")
+	}
+
+	// Make public
+	if err := object.ACL().Set(ctx, storage.AllUsers, storage.RoleReader); err != nil {
+		return errors.Wrap(err, "[CloudStorageService-UploadObject] error while making object public")
+	}
+
+	return nil
+}
+
 // Delete delete file from bucket
 func (g *GoogleCloudStorage) Delete(path string) error {
 	bucket := g.cfg.Google.StorageBucketName
@@ -162,9 +202,46 @@ func (g *GoogleCloudStorage) Delete(path string) error {
 		return errors.Wrap(err, "[CloudStorageService-Delete] error get config json")
 	}
 
-	if err := storageClient.Bucket(bucket).Object(url.QueryEscape(path)).Delete(context.Background()); err != nil {
-		return errors.Wrap(err, fmt.Sprintf("[CloudStorageService-Delete] unable to delete bucket %q, file %q", bucket, url.QueryEscape(path)))
+	if err := storageClient.Bucket(bucket).Object(strings.TrimPrefix(path, "/")).Delete(context.Background()); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("[CloudStorageService-Delete] unable to delete bucket %q, file %q", bucket, path))
 	}
 
 	return nil
 }
+
+// objectReader closes the storage client along with the object reader
+type objectReader struct {
+	*storage.Reader
+	client *storage.Client
+}
+
+// Close closes the object reader and the storage client
+func (r *objectReader) Close() error {
+	err := r.Reader.Close()
+	if cerr := r.client.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+// Download opens a file from bucket
+func (g *GoogleCloudStorage) Download(path string) (io.ReadCloser, error) {
+	bucket := g.cfg.Google.StorageBucketName
+
+	ctx := context.Background()
+
+	storageClient, err := storage.NewClient(ctx, option.WithCredentialsFile(os.Getenv("GOOGLE_SA")))
+
+	if err != nil {
+		return nil, errors.Wrap(err, "[CloudStorageService-Download] error get config json")
+	}
+
+	reader, err := storageClient.Bucket(bucket).Object(strings.TrimPrefix(path, "/")).NewReader(ctx)
+	if err != nil {
+		_ = storageClient.Close()
+		return nil, errors.Wrap(err, fmt.Sprintf("[CloudStorageService-Download] unable to read bucket %q, file %q", bucket, path))
+	}
+
+	return &objectReader{Reader: reader, client: storageClient}, nil
+}
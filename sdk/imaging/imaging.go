@@ -0,0 +1,185 @@
+// Package imaging turns uploaded photos into resized variants: it decodes the upload,
+// applies the EXIF orientation and re-encodes every variant, which also strips the metadata.
+package imaging
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	// registers the gif decoder, an animated upload keeps its first frame
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/draw"
+	// registers the webp decoder
+	_ "golang.org/x/image/webp"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+)
+
+const (
+	// FormatJPEG encodes variants as jpeg, flattening transparency onto white
+	FormatJPEG = "jpeg"
+	// FormatPNG encodes variants as png
+	FormatPNG = "png"
+	// FormatWebP encodes variants as lossless webp
+	FormatWebP = "webp"
+)
+
+// Variant is an encoded variant of a photo
+type Variant struct {
+	// Size is the longest edge the variant was resized to fit
+	Size        int
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Image is a processed photo
+type Image struct {
+	// Hash is the sha256 of the uploaded content, identical uploads share it
+	Hash     string
+	Variants []*Variant
+}
+
+// Processor processes uploaded photos into the variants of the configuration
+type Processor struct {
+	cfg config.Config
+}
+
+// NewProcessor is a constructor for Processor
+func NewProcessor(cfg config.Config) *Processor {
+	return &Processor{cfg: cfg}
+}
+
+// Process decodes the photo read from r and encodes its variants
+func (p *Processor) Process(r io.Reader) (*Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	conf, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.ErrInvalidImage.Error()
+	}
+
+	// checked before decoding so a small file cannot claim a huge canvas
+	if p.cfg.Image.MaxPixels > 0 && conf.Width*conf.Height > p.cfg.Image.MaxPixels {
+		return nil, errors.ErrImageTooLarge.Error()
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.ErrInvalidImage.Error()
+	}
+
+	if format == "jpeg" {
+		img = orient(img, exifOrientation(data))
+	}
+
+	sum := sha256.Sum256(data)
+	result := &Image{Hash: hex.EncodeToString(sum[:])}
+
+	for _, size := range p.cfg.Image.VariantSizes {
+		if size <= 0 {
+			continue
+		}
+
+		variant, err := p.encode(resize(img, size), size)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Variants = append(result.Variants, variant)
+	}
+
+	return result, nil
+}
+
+// encode encodes a resized variant in the configured format
+func (p *Processor) encode(img image.Image, size int) (*Variant, error) {
+	var buf bytes.Buffer
+
+	format := p.cfg.Image.VariantFormat
+	if format == "" {
+		format = FormatJPEG
+	}
+
+	switch format {
+	case FormatJPEG:
+		quality := p.cfg.Image.JPEGQuality
+		if quality <= 0 {
+			quality = jpeg.DefaultQuality
+		}
+
+		if err := jpeg.Encode(&buf, flatten(img), &jpeg.Options{Quality: quality}); err != nil {
+			return nil, err
+		}
+	case FormatPNG:
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+	case FormatWebP:
+		if err := encodeWebP(&buf, img); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("[Imaging-Encode] unsupported variant format %q", format)
+	}
+
+	return &Variant{
+		Size:        size,
+		Name:        fmt.Sprintf("%d.%s", size, format),
+		ContentType: "image/" + format,
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+// resize scales img to fit a size by size square, images already fitting are never upscaled
+func resize(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	if w <= size && h <= size {
+		return img
+	}
+
+	if w >= h {
+		h = max(1, h*size/w)
+		w = size
+	} else {
+		w = max(1, w*size/h)
+		h = size
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+
+	return dst
+}
+
+// flatten draws img onto white, jpeg has no alpha channel
+func flatten(img image.Image) image.Image {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Over)
+
+	return dst
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,160 @@
+package imaging_test
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/sdk/imaging"
+
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/image/webp"
+)
+
+type ProcessorTestSuite struct {
+	suite.Suite
+	processor *imaging.Processor
+}
+
+func TestProcessorTestSuite(t *testing.T) {
+	suite.Run(t, new(ProcessorTestSuite))
+}
+
+func (suite *ProcessorTestSuite) SetupTest() {
+	cfg := config.Config{}
+	cfg.Image.VariantSizes = []int{16, 64}
+	cfg.Image.VariantFormat = imaging.FormatJPEG
+	cfg.Image.MaxPixels = 10000
+
+	suite.processor = imaging.NewProcessor(cfg)
+}
+
+// newJPEG encodes a w by h jpeg, with an EXIF segment holding the orientation when it is set
+func (suite *ProcessorTestSuite) newJPEG(w, h, orientation int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: 200, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	suite.Require().NoError(jpeg.Encode(&buf, img, nil))
+
+	data := buf.Bytes()
+	if orientation == 0 {
+		return data
+	}
+
+	// big endian TIFF header with a single IFD entry: tag 0x0112, type SHORT, count 1
+	tiff := []byte{
+		'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08,
+		0x00, 0x01,
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, byte(orientation), 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	}
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	length := len(segment) + 2
+
+	exif := append([]byte{0xFF, 0xE1, byte(length >> 8), byte(length)}, segment...)
+
+	return append(append(append([]byte{}, data[:2]...), exif...), data[2:]...)
+}
+
+func (suite *ProcessorTestSuite) TestProcess() {
+	suite.Run("successfully resize, orient and strip the metadata", func() {
+		img, err := suite.processor.Process(bytes.NewReader(suite.newJPEG(40, 20, 6)))
+
+		suite.Nil(err)
+		suite.Len(img.Hash, 64)
+		suite.Len(img.Variants, 2)
+
+		small := img.Variants[0]
+		suite.Equal("16.jpeg", small.Name)
+		suite.Equal("image/jpeg", small.ContentType)
+		suite.NotContains(string(small.Data), "Exif")
+
+		conf, err := jpeg.DecodeConfig(bytes.NewReader(small.Data))
+		suite.Nil(err)
+		suite.Equal(8, conf.Width)
+		suite.Equal(16, conf.Height)
+
+		// never upscaled past the upload, rotated by the orientation
+		conf, err = jpeg.DecodeConfig(bytes.NewReader(img.Variants[1].Data))
+		suite.Nil(err)
+		suite.Equal(20, conf.Width)
+		suite.Equal(40, conf.Height)
+	})
+
+	suite.Run("successfully encode the variants as lossless webp", func() {
+		src := image.NewNRGBA(image.Rect(0, 0, 90, 60))
+		for y := 0; y < 60; y++ {
+			for x := 0; x < 90; x++ {
+				src.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 3), G: uint8(y * 4), B: uint8(x ^ y), A: uint8(255 - x)})
+			}
+		}
+
+		var upload bytes.Buffer
+		suite.Require().NoError(png.Encode(&upload, src))
+
+		cfg := config.Config{}
+		cfg.Image.VariantSizes = []int{16, 64}
+
+		cfg.Image.VariantFormat = imaging.FormatPNG
+		expected, err := imaging.NewProcessor(cfg).Process(bytes.NewReader(upload.Bytes()))
+		suite.Require().NoError(err)
+
+		cfg.Image.VariantFormat = imaging.FormatWebP
+		img, err := imaging.NewProcessor(cfg).Process(bytes.NewReader(upload.Bytes()))
+
+		suite.Nil(err)
+		suite.Len(img.Variants, 2)
+
+		for i, variant := range img.Variants {
+			suite.Equal(fmt.Sprintf("%d.webp", variant.Size), variant.Name)
+			suite.Equal("image/webp", variant.ContentType)
+
+			decoded, err := webp.Decode(bytes.NewReader(variant.Data))
+			suite.Require().NoError(err)
+
+			want, err := png.Decode(bytes.NewReader(expected.Variants[i].Data))
+			suite.Require().NoError(err)
+
+			// lossless, the pixels are the ones of the png variant
+			suite.Equal(want.Bounds(), decoded.Bounds())
+			for y := want.Bounds().Min.Y; y < want.Bounds().Max.Y; y++ {
+				for x := want.Bounds().Min.X; x < want.Bounds().Max.X; x++ {
+					suite.Require().Equal(color.NRGBAModel.Convert(want.At(x, y)), color.NRGBAModel.Convert(decoded.At(x, y)))
+				}
+			}
+		}
+	})
+
+	suite.Run("identical uploads share the hash", func() {
+		data := suite.newJPEG(10, 10, 0)
+
+		first, err := suite.processor.Process(bytes.NewReader(data))
+		suite.Nil(err)
+
+		second, err := suite.processor.Process(bytes.NewReader(data))
+		suite.Nil(err)
+
+		suite.Equal(first.Hash, second.Hash)
+	})
+
+	suite.Run("fail to process a file that is not an image", func() {
+		_, err := suite.processor.Process(bytes.NewReader([]byte("not an image")))
+		suite.Equal(errors.ErrInvalidImage.Error(), err)
+	})
+
+	suite.Run("fail to process an image over the pixel limit", func() {
+		_, err := suite.processor.Process(bytes.NewReader(suite.newJPEG(200, 100, 0)))
+		suite.Equal(errors.ErrImageTooLarge.Error(), err)
+	})
+}
@@ -0,0 +1,68 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"path"
+
+	"github.com/pkg/errors"
+
+	"gin-starter/common/interfaces"
+	"gin-starter/common/upload"
+	"gin-starter/config"
+	"gin-starter/entity"
+)
+
+// PhotoStorage stores the variants of processed photos on the cloud storage
+type PhotoStorage struct {
+	processor    *Processor
+	guard        *upload.Guard
+	cloudStorage interfaces.CloudStorageUseCase
+}
+
+// NewPhotoStorage is a constructor for PhotoStorage
+func NewPhotoStorage(cfg config.Config, guard *upload.Guard, cloudStorage interfaces.CloudStorageUseCase) *PhotoStorage {
+	return &PhotoStorage{
+		processor:    NewProcessor(cfg),
+		guard:        guard,
+		cloudStorage: cloudStorage,
+	}
+}
+
+// Upload checks the uploaded photo against the policy of the folder, processes it
+// and stores its variants as <folder>/<hash>/<size>.<format>
+func (ps *PhotoStorage) Upload(ctx context.Context, f *multipart.FileHeader, folder string) (*entity.UserPhoto, error) {
+	file, err := ps.guard.Check(ctx, f, folder)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := ps.processor.Process(bytes.NewReader(file.Data))
+	if err != nil {
+		return nil, err
+	}
+
+	photo := &entity.UserPhoto{Path: path.Join("/", folder, img.Hash)}
+
+	for _, variant := range img.Variants {
+		if err := ps.cloudStorage.UploadObject(bytes.NewReader(variant.Data), path.Join(photo.Path, variant.Name), variant.ContentType); err != nil {
+			return nil, errors.Wrap(err, "[PhotoStorage-Upload] error upload variant")
+		}
+
+		photo.Variants = append(photo.Variants, variant.Name)
+	}
+
+	return photo, nil
+}
+
+// Delete deletes the stored variants of the photo of the user
+func (ps *PhotoStorage) Delete(user *entity.User) error {
+	for _, file := range user.PhotoFiles() {
+		if err := ps.cloudStorage.Delete(file); err != nil {
+			return errors.Wrap(err, "[PhotoStorage-Delete] error delete variant")
+		}
+	}
+
+	return nil
+}
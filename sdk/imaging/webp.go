@@ -0,0 +1,591 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+	"sort"
+
+	"golang.org/x/image/draw"
+)
+
+// The variants are encoded as lossless webp (VP8L), which is the webp format a pure go encoder can produce.
+// The encoder applies the subtract green and predictor transforms and codes every pixel as a literal,
+// it leaves out the color cache and the backward references.
+
+const (
+	// webpMaxSize is the largest width or height VP8L stores
+	webpMaxSize = 1 << 14
+	// webpPredictorBits is the log-2 size of the tiles sharing a predictor
+	webpPredictorBits = 5
+
+	webpTransformPredictor     = 0
+	webpTransformSubtractGreen = 2
+
+	// webpMaxCodeLength is the longest prefix code, webpMaxCodeLengthCodeLength the longest one of the code length code
+	webpMaxCodeLength           = 15
+	webpMaxCodeLengthCodeLength = 7
+)
+
+// webpAlphabetSizes are the sizes of the green, red, blue, alpha and distance codes without color cache
+var webpAlphabetSizes = [5]int{256 + 24, 256, 256, 256, 40}
+
+// webpCodeLengthCodeOrder is the order the lengths of the code length code are written in
+var webpCodeLengthCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// encodeWebP writes img to w as a lossless webp
+func encodeWebP(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+
+	if width < 1 || height < 1 || width > webpMaxSize || height > webpMaxSize {
+		return fmt.Errorf("[Imaging-EncodeWebP] webp cannot store a %dx%d image", width, height)
+	}
+
+	src := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+
+	// pixels in the r, g, b, a order of image.NRGBA, one per element
+	pix := make([][4]uint8, width*height)
+	alpha := false
+	for i := range pix {
+		copy(pix[i][:], src.Pix[4*i:4*i+4])
+		alpha = alpha || pix[i][3] != 0xff
+	}
+
+	bw := &bitWriter{}
+	bw.write(0x2f, 8)
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	bw.write(boolBit(alpha), 1)
+	bw.write(0, 3)
+
+	// the decoder inverts the transforms in the reverse order they are written in
+	bw.write(1, 1)
+	bw.write(webpTransformSubtractGreen, 2)
+	subtractGreen(pix)
+
+	bw.write(1, 1)
+	bw.write(webpTransformPredictor, 2)
+	bw.write(webpPredictorBits-2, 3)
+	modes := predict(pix, width, height)
+	writeEntropyImage(bw, modes, false)
+
+	bw.write(0, 1)
+	writeEntropyImage(bw, pix, true)
+
+	data := bw.bytes()
+
+	size := len(data)
+	padding := size & 1
+
+	header := make([]byte, 20)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(4+8+size+padding))
+	copy(header[8:], "WEBPVP8L")
+	binary.LittleEndian.PutUint32(header[16:], uint32(size))
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+
+	if padding == 1 {
+		if _, err := w.Write([]byte{0}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// subtractGreen subtracts the green of every pixel from its red and blue
+func subtractGreen(pix [][4]uint8) {
+	for i := range pix {
+		pix[i][0] -= pix[i][1]
+		pix[i][2] -= pix[i][1]
+	}
+}
+
+// predict replaces every pixel by its difference from the prediction of the mode of its tile, picking for every
+// tile the mode leaving the smallest differences. It returns the image of the modes, one pixel per tile.
+func predict(pix [][4]uint8, width, height int) [][4]uint8 {
+	tilesPerRow := (width + 1<<webpPredictorBits - 1) >> webpPredictorBits
+	tilesPerColumn := (height + 1<<webpPredictorBits - 1) >> webpPredictorBits
+
+	modes := make([][4]uint8, tilesPerRow*tilesPerColumn)
+	for ty := 0; ty < tilesPerColumn; ty++ {
+		for tx := 0; tx < tilesPerRow; tx++ {
+			best, bestCost := 0, -1
+			for mode := 0; mode < 14; mode++ {
+				cost := 0
+				forEachTilePixel(width, height, tx, ty, func(x, y int) {
+					residual := difference(pix[y*width+x], predictor(pix, width, x, y, mode))
+					for _, c := range residual {
+						cost += absInt(int(int8(c)))
+					}
+				})
+
+				if bestCost < 0 || cost < bestCost {
+					best, bestCost = mode, cost
+				}
+			}
+
+			// the mode is read from the green of the tile
+			modes[ty*tilesPerRow+tx] = [4]uint8{0, uint8(best), 0, 0}
+		}
+	}
+
+	residuals := make([][4]uint8, len(pix))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			mode := int(modes[(y>>webpPredictorBits)*tilesPerRow+(x>>webpPredictorBits)][1])
+			residuals[y*width+x] = difference(pix[y*width+x], predictor(pix, width, x, y, mode))
+		}
+	}
+
+	copy(pix, residuals)
+
+	return modes
+}
+
+// forEachTilePixel calls fn with the coordinates of every pixel of the tile
+func forEachTilePixel(width, height, tx, ty int, fn func(x, y int)) {
+	for y := ty << webpPredictorBits; y < (ty+1)<<webpPredictorBits && y < height; y++ {
+		for x := tx << webpPredictorBits; x < (tx+1)<<webpPredictorBits && x < width; x++ {
+			fn(x, y)
+		}
+	}
+}
+
+// predictor predicts the pixel at x, y from its decoded neighbours. The first pixel is predicted as opaque black,
+// the rest of the first row from the left and the first column from the top, whatever the mode.
+func predictor(pix [][4]uint8, width, x, y, mode int) [4]uint8 {
+	i := y*width + x
+
+	switch {
+	case x == 0 && y == 0:
+		return [4]uint8{0, 0, 0, 0xff}
+	case y == 0:
+		return pix[i-1]
+	case x == 0:
+		return pix[i-width]
+	}
+
+	// the top right of the last column is the first pixel of the row, as the pixels are stored
+	l, t, tl, tr := pix[i-1], pix[i-width], pix[i-width-1], pix[i-width+1]
+
+	switch mode {
+	case 0:
+		return [4]uint8{0, 0, 0, 0xff}
+	case 1:
+		return l
+	case 2:
+		return t
+	case 3:
+		return tr
+	case 4:
+		return tl
+	case 5:
+		return average(average(l, tr), t)
+	case 6:
+		return average(l, tl)
+	case 7:
+		return average(l, t)
+	case 8:
+		return average(tl, t)
+	case 9:
+		return average(t, tr)
+	case 10:
+		return average(average(l, tl), average(t, tr))
+	case 11:
+		var pl, pt int
+		for c := 0; c < 4; c++ {
+			pl += absInt(int(tl[c]) - int(t[c]))
+			pt += absInt(int(tl[c]) - int(l[c]))
+		}
+
+		if pl < pt {
+			return l
+		}
+
+		return t
+	case 12:
+		var p [4]uint8
+		for c := 0; c < 4; c++ {
+			p[c] = clamp(int(l[c]) + int(t[c]) - int(tl[c]))
+		}
+
+		return p
+	default:
+		a := average(l, t)
+
+		var p [4]uint8
+		for c := 0; c < 4; c++ {
+			p[c] = clamp(int(a[c]) + (int(a[c])-int(tl[c]))/2)
+		}
+
+		return p
+	}
+}
+
+func difference(a, b [4]uint8) [4]uint8 {
+	return [4]uint8{a[0] - b[0], a[1] - b[1], a[2] - b[2], a[3] - b[3]}
+}
+
+func average(a, b [4]uint8) [4]uint8 {
+	var p [4]uint8
+	for c := 0; c < 4; c++ {
+		p[c] = uint8((int(a[c]) + int(b[c])) / 2)
+	}
+
+	return p
+}
+
+func clamp(v int) uint8 {
+	if v < 0 {
+		return 0
+	}
+
+	if v > 255 {
+		return 255
+	}
+
+	return uint8(v)
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
+
+func boolBit(b bool) uint32 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// writeEntropyImage writes the pixels as literals coded by a single group of prefix codes, without color cache
+func writeEntropyImage(bw *bitWriter, pix [][4]uint8, main bool) {
+	// green, red, blue and alpha are coded in that order
+	channels := [4]int{1, 0, 2, 3}
+
+	var histograms [5][]int
+	for i, size := range webpAlphabetSizes {
+		histograms[i] = make([]int, size)
+	}
+
+	for _, p := range pix {
+		for i, c := range channels {
+			histograms[i][p[c]]++
+		}
+	}
+
+	// no color cache
+	bw.write(0, 1)
+
+	if main {
+		// a single group of prefix codes
+		bw.write(0, 1)
+	}
+
+	var codes [5]*prefixCode
+	for i, histogram := range histograms {
+		codes[i] = writePrefixCode(bw, histogram)
+	}
+
+	for _, p := range pix {
+		for i, c := range channels {
+			codes[i].write(bw, int(p[c]))
+		}
+	}
+}
+
+// prefixCode is a canonical prefix code, the bits of every code are reversed as the stream is read from the lowest bit
+type prefixCode struct {
+	codes   []uint32
+	lengths []int
+}
+
+func (pc *prefixCode) write(bw *bitWriter, symbol int) {
+	bw.write(pc.codes[symbol], pc.lengths[symbol])
+}
+
+// writePrefixCode writes the prefix code of the histogram and returns it
+func writePrefixCode(bw *bitWriter, histogram []int) *prefixCode {
+	symbols := make([]int, 0, 2)
+	for symbol, count := range histogram {
+		if count > 0 {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	pc := &prefixCode{codes: make([]uint32, len(histogram)), lengths: make([]int, len(histogram))}
+
+	// up to two symbols below 256 are written as a simple code, an unused code holds the symbol 0
+	if len(symbols) <= 2 && (len(symbols) == 0 || symbols[len(symbols)-1] < 256) {
+		if len(symbols) == 0 {
+			symbols = append(symbols, 0)
+		}
+
+		bw.write(1, 1)
+		bw.write(uint32(len(symbols)-1), 1)
+
+		if symbols[0] < 2 {
+			bw.write(0, 1)
+			bw.write(uint32(symbols[0]), 1)
+		} else {
+			bw.write(1, 1)
+			bw.write(uint32(symbols[0]), 8)
+		}
+
+		if len(symbols) == 2 {
+			bw.write(uint32(symbols[1]), 8)
+			pc.codes[symbols[1]], pc.lengths[symbols[0]], pc.lengths[symbols[1]] = 1, 1, 1
+		}
+
+		return pc
+	}
+
+	lengths := codeLengths(histogram, webpMaxCodeLength)
+
+	// the lengths are written as literal lengths and runs of zeros
+	tokens := make([][2]int, 0, len(lengths))
+	for i := 0; i < len(lengths); {
+		if lengths[i] != 0 {
+			tokens = append(tokens, [2]int{lengths[i], 0})
+			i++
+			continue
+		}
+
+		run := 1
+		for i+run < len(lengths) && lengths[i+run] == 0 && run < 138 {
+			run++
+		}
+
+		switch {
+		case run < 3:
+			for j := 0; j < run; j++ {
+				tokens = append(tokens, [2]int{0, 0})
+			}
+		case run < 11:
+			tokens = append(tokens, [2]int{17, run - 3})
+		default:
+			tokens = append(tokens, [2]int{18, run - 11})
+		}
+
+		i += run
+	}
+
+	lengthHistogram := make([]int, len(webpCodeLengthCodeOrder))
+	for _, token := range tokens {
+		lengthHistogram[token[0]]++
+	}
+
+	lengthLengths := codeLengths(lengthHistogram, webpMaxCodeLengthCodeLength)
+	lengthCode := newPrefixCode(lengthLengths)
+
+	count := 4
+	for i, symbol := range webpCodeLengthCodeOrder {
+		if lengthLengths[symbol] != 0 && i+1 > count {
+			count = i + 1
+		}
+	}
+
+	bw.write(0, 1)
+	bw.write(uint32(count-4), 4)
+	for _, symbol := range webpCodeLengthCodeOrder[:count] {
+		bw.write(uint32(lengthLengths[symbol]), 3)
+	}
+
+	// the lengths of every symbol follow, rather than a count of the tokens
+	bw.write(0, 1)
+	for _, token := range tokens {
+		lengthCode.write(bw, token[0])
+
+		switch token[0] {
+		case 17:
+			bw.write(uint32(token[1]), 3)
+		case 18:
+			bw.write(uint32(token[1]), 7)
+		}
+	}
+
+	return newPrefixCode(lengths)
+}
+
+// newPrefixCode creates the canonical prefix code of the lengths.
+// A code of a single symbol is read without any bit, whatever its length.
+func newPrefixCode(lengths []int) *prefixCode {
+	pc := &prefixCode{codes: make([]uint32, len(lengths)), lengths: make([]int, len(lengths))}
+
+	var histogram [webpMaxCodeLength + 1]int
+	used := 0
+	for _, length := range lengths {
+		histogram[length]++
+		if length > 0 {
+			used++
+		}
+	}
+
+	if used == 1 {
+		return pc
+	}
+
+	var next [webpMaxCodeLength + 1]uint32
+	code := uint32(0)
+	histogram[0] = 0
+	for length := 1; length <= webpMaxCodeLength; length++ {
+		code = (code + uint32(histogram[length-1])) << 1
+		next[length] = code
+	}
+
+	for symbol, length := range lengths {
+		if length == 0 {
+			continue
+		}
+
+		pc.codes[symbol] = reverse(next[length], length)
+		pc.lengths[symbol] = length
+		next[length]++
+	}
+
+	return pc
+}
+
+// reverse reverses the lowest n bits of code
+func reverse(code uint32, n int) uint32 {
+	r := uint32(0)
+	for i := 0; i < n; i++ {
+		r = r<<1 | code&1
+		code >>= 1
+	}
+
+	return r
+}
+
+// codeLengths returns the lengths of a huffman code of the histogram no longer than maxLength.
+// The counts are evened out until the code fits, a single used symbol gets a length of 1.
+func codeLengths(histogram []int, maxLength int) []int {
+	for minCount := 1; ; minCount *= 2 {
+		lengths := huffmanLengths(histogram, minCount)
+
+		fits := true
+		for _, length := range lengths {
+			if length > maxLength {
+				fits = false
+				break
+			}
+		}
+
+		if fits {
+			return lengths
+		}
+	}
+}
+
+// huffmanLengths returns the lengths of the huffman code of the used symbols, counting every symbol at least minCount
+func huffmanLengths(histogram []int, minCount int) []int {
+	type node struct {
+		count       int
+		symbol      int
+		left, right int
+	}
+
+	nodes := make([]node, 0, 2*len(histogram))
+	for symbol, count := range histogram {
+		if count == 0 {
+			continue
+		}
+
+		if count < minCount {
+			count = minCount
+		}
+
+		nodes = append(nodes, node{count: count, symbol: symbol, left: -1, right: -1})
+	}
+
+	lengths := make([]int, len(histogram))
+	if len(nodes) == 0 {
+		return lengths
+	}
+
+	if len(nodes) == 1 {
+		lengths[nodes[0].symbol] = 1
+		return lengths
+	}
+
+	// the leaves are sorted, the merged nodes are created in increasing count, so the smallest node heads either queue
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].count < nodes[j].count
+	})
+
+	leaves := len(nodes)
+	leaf, merged := 0, leaves
+	smallest := func() int {
+		if leaf < leaves && (merged >= len(nodes) || nodes[leaf].count <= nodes[merged].count) {
+			leaf++
+			return leaf - 1
+		}
+
+		merged++
+		return merged - 1
+	}
+
+	for i := 0; i < leaves-1; i++ {
+		a := smallest()
+		b := smallest()
+		nodes = append(nodes, node{count: nodes[a].count + nodes[b].count, symbol: -1, left: a, right: b})
+	}
+
+	var walk func(i, depth int)
+	walk = func(i, depth int) {
+		if nodes[i].symbol >= 0 {
+			lengths[nodes[i].symbol] = depth
+			return
+		}
+
+		walk(nodes[i].left, depth+1)
+		walk(nodes[i].right, depth+1)
+	}
+	walk(len(nodes)-1, 0)
+
+	return lengths
+}
+
+// bitWriter writes the bits of VP8L, from the lowest bit of every byte
+type bitWriter struct {
+	buf   []byte
+	bits  uint64
+	nBits int
+}
+
+func (bw *bitWriter) write(bits uint32, n int) {
+	bw.bits |= uint64(bits) << bw.nBits
+	bw.nBits += n
+
+	for bw.nBits >= 8 {
+		bw.buf = append(bw.buf, byte(bw.bits))
+		bw.bits >>= 8
+		bw.nBits -= 8
+	}
+}
+
+// bytes returns the written bits, the last byte padded with zeros
+func (bw *bitWriter) bytes() []byte {
+	if bw.nBits > 0 {
+		bw.buf = append(bw.buf, byte(bw.bits))
+		bw.bits, bw.nBits = 0, 0
+	}
+
+	return bw.buf
+}
@@ -0,0 +1,282 @@
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/pkg/errors"
+
+	commonErrors "gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/entity"
+)
+
+// groupRole maps a directory group to a role name
+type groupRole struct {
+	group *ldap.DN
+	role  string
+}
+
+// Authenticator authenticates CMS users against an LDAP or Active Directory server.
+// It binds with the service account, looks the user up by email or UPN,
+// binds as the user to verify the password and maps group membership to roles.
+type Authenticator struct {
+	cfg      config.LDAP
+	pool     *pool
+	mappings []*groupRole
+	timeout  time.Duration
+}
+
+// NewAuthenticator initiate ldap authenticator
+func NewAuthenticator(cfg config.Config) (*Authenticator, error) {
+	timeout, err := time.ParseDuration(cfg.LDAP.Timeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "[LDAPAuthenticator-New] invalid timeout")
+	}
+
+	mappings, err := parseGroupRoleMapping(cfg.LDAP.GroupRoleMapping)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := newTLSConfig(cfg.LDAP)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Authenticator{
+		cfg:      cfg.LDAP,
+		mappings: mappings,
+		timeout:  timeout,
+	}
+
+	a.pool = newPool(cfg.LDAP.PoolSize, func() (*ldap.Conn, error) {
+		return a.dial(tlsConfig)
+	})
+
+	return a, nil
+}
+
+// Authenticate verifies the credentials against the directory
+func (a *Authenticator) Authenticate(ctx context.Context, email, password string) (*entity.AuthIdentity, error) {
+	// an empty password would turn the user bind into an unauthenticated bind which always succeeds
+	if strings.TrimSpace(email) == "" || password == "" {
+		return nil, commonErrors.ErrWrongLoginCredentials.Error()
+	}
+
+	conn, err := a.serviceConn()
+	if err != nil {
+		return nil, err
+	}
+
+	healthy := true
+	defer func() {
+		if healthy {
+			a.pool.put(conn)
+			return
+		}
+		conn.Close()
+	}()
+
+	entry, err := a.findUser(conn, email)
+	if err != nil {
+		healthy = !ldap.IsErrorWithCode(err, ldap.ErrorNetwork)
+		return nil, err
+	}
+
+	if entry == nil {
+		return nil, commonErrors.ErrWrongLoginCredentials.Error()
+	}
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, commonErrors.ErrWrongLoginCredentials.Error()
+		}
+		healthy = false
+		return nil, errors.Wrap(err, "[LDAPAuthenticator-Authenticate] error while binding as user")
+	}
+
+	identity := &entity.AuthIdentity{
+		Email: strings.ToLower(entry.GetAttributeValue(a.cfg.EmailAttribute)),
+		Name:  entry.GetAttributeValue(a.cfg.NameAttribute),
+		Roles: a.roles(entry.GetAttributeValues(a.cfg.GroupAttribute)),
+	}
+
+	if identity.Email == "" {
+		identity.Email = strings.ToLower(strings.TrimSpace(email))
+	}
+
+	if identity.Name == "" {
+		identity.Name = identity.Email
+	}
+
+	return identity, nil
+}
+
+// Close closes the pooled connections
+func (a *Authenticator) Close() {
+	a.pool.close()
+}
+
+// serviceConn returns a connection bound as the service account.
+// A pooled connection dropped by the server is replaced by a fresh one.
+func (a *Authenticator) serviceConn() (*ldap.Conn, error) {
+	for attempt := 0; ; attempt++ {
+		conn, err := a.pool.get()
+		if err != nil {
+			return nil, errors.Wrap(err, "[LDAPAuthenticator-Authenticate] error while connecting")
+		}
+
+		err = conn.Bind(a.cfg.BindDN, a.cfg.BindPassword)
+		if err == nil {
+			return conn, nil
+		}
+
+		conn.Close()
+
+		if !ldap.IsErrorWithCode(err, ldap.ErrorNetwork) || attempt > 0 {
+			return nil, errors.Wrap(err, "[LDAPAuthenticator-Authenticate] error while binding service account")
+		}
+	}
+}
+
+// findUser looks the user up by email, returning nil when no single entry matches
+func (a *Authenticator) findUser(conn *ldap.Conn, email string) (*ldap.Entry, error) {
+	filter := strings.ReplaceAll(a.cfg.UserFilter, "{email}", ldap.EscapeFilter(strings.TrimSpace(email)))
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		a.cfg.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		2,
+		int(a.timeout.Seconds()),
+		false,
+		filter,
+		[]string{a.cfg.EmailAttribute, a.cfg.NameAttribute, a.cfg.GroupAttribute},
+		nil,
+	))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+			log.Println("[LDAPAuthenticator-Authenticate] more than one entry matches", email)
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "[LDAPAuthenticator-Authenticate] error while searching user")
+	}
+
+	if len(result.Entries) != 1 {
+		if len(result.Entries) > 1 {
+			log.Println("[LDAPAuthenticator-Authenticate] more than one entry matches", email)
+		}
+		return nil, nil
+	}
+
+	return result.Entries[0], nil
+}
+
+// roles returns the roles mapped from the given groups in mapping order
+func (a *Authenticator) roles(groups []string) []string {
+	dns := make([]*ldap.DN, 0, len(groups))
+	for _, g := range groups {
+		dn, err := ldap.ParseDN(g)
+		if err != nil {
+			continue
+		}
+		dns = append(dns, dn)
+	}
+
+	roles := make([]string, 0)
+	for _, m := range a.mappings {
+		for _, dn := range dns {
+			if m.group.EqualFold(dn) {
+				roles = append(roles, m.role)
+				break
+			}
+		}
+	}
+
+	return roles
+}
+
+// dial opens a new connection, upgrading it with StartTLS when configured
+func (a *Authenticator) dial(tlsConfig *tls.Config) (*ldap.Conn, error) {
+	conn, err := ldap.DialURL(
+		a.cfg.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: a.timeout}),
+		ldap.DialWithTLSConfig(tlsConfig),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.cfg.StartTLS && strings.HasPrefix(strings.ToLower(a.cfg.URL), "ldap://") {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	conn.SetTimeout(a.timeout)
+
+	return conn, nil
+}
+
+// newTLSConfig builds the tls configuration used for ldaps and StartTLS
+func newTLSConfig(cfg config.LDAP) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, // #nosec
+	}
+
+	if host, _, err := net.SplitHostPort(strings.TrimPrefix(strings.TrimPrefix(cfg.URL, "ldaps://"), "ldap://")); err == nil {
+		tlsConfig.ServerName = host
+	}
+
+	if cfg.CACertFile != "" {
+		pem, err := os.ReadFile(cfg.CACertFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "[LDAPAuthenticator-New] error while reading ca certificate")
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("[LDAPAuthenticator-New] no certificate found in ca certificate file")
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// parseGroupRoleMapping parses `group DN=>role name` pairs separated by semicolons
+func parseGroupRoleMapping(mapping string) ([]*groupRole, error) {
+	mappings := make([]*groupRole, 0)
+
+	for _, pair := range strings.Split(mapping, ";") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=>", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("[LDAPAuthenticator-New] invalid group role mapping %q", pair)
+		}
+
+		dn, err := ldap.ParseDN(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "[LDAPAuthenticator-New] invalid group dn %q", parts[0])
+		}
+
+		mappings = append(mappings, &groupRole{group: dn, role: strings.TrimSpace(parts[1])})
+	}
+
+	return mappings, nil
+}
@@ -0,0 +1,403 @@
+package ldap_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/suite"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/sdk/ldap"
+)
+
+const (
+	serviceDN       = "cn=service,ou=accounts,dc=example,dc=com"
+	servicePassword = "service-secret"
+	baseDN          = "dc=example,dc=com"
+)
+
+// directoryEntry is a user known by the fake directory server
+type directoryEntry struct {
+	dn       string
+	password string
+	attrs    map[string][]string
+}
+
+// directoryServer is a minimal in-process LDAP server supporting bind, search and unbind
+type directoryServer struct {
+	listener net.Listener
+	entries  []*directoryEntry
+	accepted int32
+	wg       sync.WaitGroup
+}
+
+func newDirectoryServer(t *testing.T, tlsConfig *tls.Config, entries []*directoryEntry) *directoryServer {
+	var (
+		listener net.Listener
+		err      error
+	)
+
+	if tlsConfig != nil {
+		listener, err = tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	} else {
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &directoryServer{listener: listener, entries: entries}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+		s.wg.Wait()
+	})
+
+	return s
+}
+
+func (s *directoryServer) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		atomic.AddInt32(&s.accepted, 1)
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *directoryServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+
+		messageID := packet.Children[0].Value.(int64)
+		request := packet.Children[1]
+
+		switch request.Tag {
+		case goldap.ApplicationBindRequest:
+			name := request.Children[1].Value.(string)
+			password := request.Children[2].Data.String()
+
+			code := uint16(goldap.LDAPResultInvalidCredentials)
+			if name == serviceDN && password == servicePassword {
+				code = goldap.LDAPResultSuccess
+			}
+			for _, e := range s.entries {
+				if name == e.dn && password == e.password {
+					code = goldap.LDAPResultSuccess
+				}
+			}
+
+			s.write(conn, messageID, result(goldap.ApplicationBindResponse, code))
+		case goldap.ApplicationSearchRequest:
+			filter, err := goldap.DecompileFilter(request.Children[6])
+			if err != nil {
+				s.write(conn, messageID, result(goldap.ApplicationSearchResultDone, goldap.LDAPResultOperationsError))
+				continue
+			}
+
+			for _, e := range s.entries {
+				if matches(filter, e) {
+					s.write(conn, messageID, searchEntry(e))
+				}
+			}
+
+			s.write(conn, messageID, result(goldap.ApplicationSearchResultDone, goldap.LDAPResultSuccess))
+		default:
+			return
+		}
+	}
+}
+
+func (s *directoryServer) write(conn net.Conn, messageID int64, op *ber.Packet) {
+	envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+	envelope.AppendChild(op)
+	_, _ = conn.Write(envelope.Bytes())
+}
+
+func (s *directoryServer) url(scheme string) string {
+	return scheme + "://" + s.listener.Addr().String()
+}
+
+// matches reports whether the entry satisfies the email lookup filter, ignoring case like directory servers do
+func matches(filter string, e *directoryEntry) bool {
+	filter = strings.ToLower(filter)
+
+	for _, attr := range []string{"mail", "userPrincipalName"} {
+		for _, v := range e.attrs[attr] {
+			if strings.Contains(filter, strings.ToLower("("+attr+"="+goldap.EscapeFilter(v)+")")) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func result(tag ber.Tag, code uint16) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "resultCode"))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "diagnosticMessage"))
+	return p
+}
+
+func searchEntry(e *directoryEntry) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, goldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "objectName"))
+
+	attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attributes")
+	for name, values := range e.attrs {
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "vals")
+		for _, v := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "value"))
+		}
+		attr.AppendChild(set)
+		attributes.AppendChild(attr)
+	}
+	p.AppendChild(attributes)
+
+	return p
+}
+
+type LDAPAuthenticatorTestSuite struct {
+	suite.Suite
+	entries []*directoryEntry
+}
+
+func TestLDAPAuthenticatorTestSuite(t *testing.T) {
+	suite.Run(t, new(LDAPAuthenticatorTestSuite))
+}
+
+func (suite *LDAPAuthenticatorTestSuite) SetupTest() {
+	suite.entries = []*directoryEntry{
+		{
+			dn:       "cn=jane,ou=people,dc=example,dc=com",
+			password: "jane-secret",
+			attrs: map[string][]string{
+				"mail":              {"Jane@Example.com"},
+				"userPrincipalName": {"jane@corp.example.com"},
+				"displayName":       {"Jane Doe"},
+				"memberOf": {
+					"CN=Editors,OU=Groups,DC=example,DC=com",
+					"cn=admins,ou=groups,dc=example,dc=com",
+				},
+			},
+		},
+		{
+			dn:       "cn=john,ou=people,dc=example,dc=com",
+			password: "john-secret",
+			attrs: map[string][]string{
+				"mail":     {"john@example.com"},
+				"memberOf": {"cn=staff,ou=groups,dc=example,dc=com"},
+			},
+		},
+	}
+}
+
+func (suite *LDAPAuthenticatorTestSuite) config(url string) config.Config {
+	return config.Config{
+		LDAP: config.LDAP{
+			URL:              url,
+			BindDN:           serviceDN,
+			BindPassword:     servicePassword,
+			BaseDN:           baseDN,
+			UserFilter:       "(|(mail={email})(userPrincipalName={email}))",
+			EmailAttribute:   "mail",
+			NameAttribute:    "displayName",
+			GroupAttribute:   "memberOf",
+			GroupRoleMapping: "cn=admins,ou=groups,dc=example,dc=com=>Super Admin; cn=editors,ou=groups,dc=example,dc=com=>Editor",
+			PoolSize:         2,
+			Timeout:          "5s",
+		},
+	}
+}
+
+func (suite *LDAPAuthenticatorTestSuite) authenticator(cfg config.Config) *ldap.Authenticator {
+	authenticator, err := ldap.NewAuthenticator(cfg)
+	suite.Require().NoError(err)
+	suite.T().Cleanup(authenticator.Close)
+
+	return authenticator
+}
+
+func (suite *LDAPAuthenticatorTestSuite) TestAuthenticate() {
+	server := newDirectoryServer(suite.T(), nil, suite.entries)
+	authenticator := suite.authenticator(suite.config(server.url("ldap")))
+
+	suite.Run("maps groups to roles in mapping order", func() {
+		identity, err := authenticator.Authenticate(context.Background(), "jane@example.com", "jane-secret")
+
+		suite.Require().NoError(err)
+		suite.Equal("jane@example.com", identity.Email)
+		suite.Equal("Jane Doe", identity.Name)
+		suite.Nil(identity.User)
+		suite.Equal([]string{"Super Admin", "Editor"}, identity.Roles)
+	})
+
+	suite.Run("finds users by upn", func() {
+		identity, err := authenticator.Authenticate(context.Background(), "jane@corp.example.com", "jane-secret")
+
+		suite.NoError(err)
+		suite.Equal("jane@example.com", identity.Email)
+	})
+
+	suite.Run("returns no roles for unmapped groups", func() {
+		identity, err := authenticator.Authenticate(context.Background(), "john@example.com", "john-secret")
+
+		suite.NoError(err)
+		suite.Equal("john@example.com", identity.Name)
+		suite.Empty(identity.Roles)
+	})
+
+	suite.Run("rejects a wrong password", func() {
+		identity, err := authenticator.Authenticate(context.Background(), "jane@example.com", "wrong")
+
+		suite.Nil(identity)
+		suite.Equal(errors.ErrWrongLoginCredentials.Error(), err)
+	})
+
+	suite.Run("rejects an empty password", func() {
+		identity, err := authenticator.Authenticate(context.Background(), "jane@example.com", "")
+
+		suite.Nil(identity)
+		suite.Equal(errors.ErrWrongLoginCredentials.Error(), err)
+	})
+
+	suite.Run("rejects an unknown user and escapes the filter", func() {
+		identity, err := authenticator.Authenticate(context.Background(), "*", "jane-secret")
+
+		suite.Nil(identity)
+		suite.Equal(errors.ErrWrongLoginCredentials.Error(), err)
+	})
+
+	suite.Run("reuses pooled connections", func() {
+		suite.EqualValues(1, atomic.LoadInt32(&server.accepted))
+	})
+}
+
+func (suite *LDAPAuthenticatorTestSuite) TestAuthenticate_ServiceBindFailure() {
+	server := newDirectoryServer(suite.T(), nil, suite.entries)
+	cfg := suite.config(server.url("ldap"))
+	cfg.LDAP.BindPassword = "wrong"
+
+	identity, err := suite.authenticator(cfg).Authenticate(context.Background(), "jane@example.com", "jane-secret")
+
+	suite.Nil(identity)
+	suite.Error(err)
+	suite.NotEqual(errors.ErrWrongLoginCredentials.Error(), err)
+}
+
+func (suite *LDAPAuthenticatorTestSuite) TestAuthenticate_TLS() {
+	certPEM, tlsCert := generateCertificate(suite.T())
+	server := newDirectoryServer(suite.T(), &tls.Config{Certificates: []tls.Certificate{tlsCert}, MinVersion: tls.VersionTLS12}, suite.entries)
+
+	suite.Run("verifies the server against the configured ca", func() {
+		caFile := filepath.Join(suite.T().TempDir(), "ca.pem")
+		suite.Require().NoError(os.WriteFile(caFile, certPEM, 0o600))
+
+		cfg := suite.config(server.url("ldaps"))
+		cfg.LDAP.CACertFile = caFile
+
+		identity, err := suite.authenticator(cfg).Authenticate(context.Background(), "jane@example.com", "jane-secret")
+
+		suite.NoError(err)
+		suite.Equal("jane@example.com", identity.Email)
+	})
+
+	suite.Run("refuses an untrusted server", func() {
+		identity, err := suite.authenticator(suite.config(server.url("ldaps"))).
+			Authenticate(context.Background(), "jane@example.com", "jane-secret")
+
+		suite.Nil(identity)
+		suite.Error(err)
+	})
+}
+
+func (suite *LDAPAuthenticatorTestSuite) TestNewAuthenticator_InvalidMapping() {
+	cfg := suite.config("ldap://127.0.0.1:389")
+	cfg.LDAP.GroupRoleMapping = "cn=admins,dc=example,dc=com"
+
+	_, err := ldap.NewAuthenticator(cfg)
+
+	suite.Error(err)
+}
+
+// generateCertificate creates a self signed certificate for 127.0.0.1
+func generateCertificate(t *testing.T) ([]byte, tls.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return certPEM, cert
+}
@@ -0,0 +1,63 @@
+package ldap
+
+import (
+	"github.com/go-ldap/ldap/v3"
+)
+
+// pool keeps up to size idle connections for reuse
+type pool struct {
+	conns chan *ldap.Conn
+	dial  func() (*ldap.Conn, error)
+}
+
+// newPool creates a connection pool
+func newPool(size int, dial func() (*ldap.Conn, error)) *pool {
+	if size < 1 {
+		size = 1
+	}
+
+	return &pool{
+		conns: make(chan *ldap.Conn, size),
+		dial:  dial,
+	}
+}
+
+// get returns an idle connection or dials a new one
+func (p *pool) get() (*ldap.Conn, error) {
+	for {
+		select {
+		case conn := <-p.conns:
+			if conn.IsClosing() {
+				continue
+			}
+			return conn, nil
+		default:
+			return p.dial()
+		}
+	}
+}
+
+// put returns a connection to the pool, closing it when the pool is full
+func (p *pool) put(conn *ldap.Conn) {
+	if conn.IsClosing() {
+		return
+	}
+
+	select {
+	case p.conns <- conn:
+	default:
+		conn.Close()
+	}
+}
+
+// close closes every idle connection
+func (p *pool) close() {
+	for {
+		select {
+		case conn := <-p.conns:
+			conn.Close()
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package push
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"gin-starter/common/interfaces"
+)
+
+// Message is a message recorded by the fake provider
+type Message struct {
+	Tokens []string
+	interfaces.PushMessage
+}
+
+// FakeProvider logs the messages instead of delivering them and keeps them for inspection
+type FakeProvider struct {
+	mu       sync.Mutex
+	messages []Message
+	invalid  map[string]bool
+}
+
+// NewFakeProvider initiate fake push provider
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{invalid: make(map[string]bool)}
+}
+
+// Name returns the name of the provider
+func (p *FakeProvider) Name() string {
+	return ProviderFake
+}
+
+// Send logs the message and reports the invalidated tokens
+func (p *FakeProvider) Send(_ context.Context, tokens []string, message *interfaces.PushMessage) ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	log.Printf("[PushFakeProvider-Send] to %d devices: %s - %s", len(tokens), message.Title, message.Body)
+	p.messages = append(p.messages, Message{Tokens: append([]string(nil), tokens...), PushMessage: *message})
+
+	invalid := make([]string, 0)
+	for _, token := range tokens {
+		if p.invalid[token] {
+			invalid = append(invalid, token)
+		}
+	}
+
+	return invalid, nil
+}
+
+// Invalidate makes the provider report the tokens as no longer valid
+func (p *FakeProvider) Invalidate(tokens ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, token := range tokens {
+		p.invalid[token] = true
+	}
+}
+
+// Messages returns the messages sent so far
+func (p *FakeProvider) Messages() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Message(nil), p.messages...)
+}
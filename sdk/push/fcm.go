@@ -0,0 +1,197 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"gin-starter/common/interfaces"
+	"gin-starter/config"
+)
+
+// fcmScope is the OAuth scope of the Firebase Cloud Messaging API
+const fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+// FCMProvider delivers the messages through the HTTP v1 API of Firebase Cloud Messaging.
+// The API takes a single token per message, the devices are reached one request after another.
+type FCMProvider struct {
+	projectID string
+	url       string
+	client    *http.Client
+}
+
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type fcmErrorResponse struct {
+	Error struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Details []struct {
+			ErrorCode       string `json:"errorCode"`
+			FieldViolations []struct {
+				Field string `json:"field"`
+			} `json:"fieldViolations"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// unregistered tells whether the error reports a token which is no longer valid, or which is malformed.
+// The status alone is not enough, a wrong project replies NOT_FOUND to every message whatever its token.
+func (r *fcmErrorResponse) unregistered() bool {
+	invalidArgument := false
+	tokenViolation := false
+
+	for _, detail := range r.Error.Details {
+		switch detail.ErrorCode {
+		case "UNREGISTERED":
+			return true
+		case "INVALID_ARGUMENT":
+			invalidArgument = true
+		}
+
+		for _, violation := range detail.FieldViolations {
+			if violation.Field == "message.token" {
+				tokenViolation = true
+			}
+		}
+	}
+
+	return invalidArgument && tokenViolation
+}
+
+// NewFCMProvider initiate fcm push provider, authenticated with the service account of the configuration
+// or the application default credentials
+func NewFCMProvider(cfg config.Config) (*FCMProvider, error) {
+	if cfg.Push.FCMProjectID == "" {
+		return nil, fmt.Errorf("[PushFCMProvider-New] PUSH_FCM_PROJECT_ID is required")
+	}
+
+	timeout, err := time.ParseDuration(cfg.Push.Timeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "[PushFCMProvider-New] invalid timeout")
+	}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Timeout: timeout})
+
+	var credentials *google.Credentials
+	if cfg.Push.FCMCredentialsFile != "" {
+		data, err := os.ReadFile(cfg.Push.FCMCredentialsFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "[PushFCMProvider-New] error while reading credentials")
+		}
+
+		credentials, err = google.CredentialsFromJSON(ctx, data, fcmScope)
+		if err != nil {
+			return nil, errors.Wrap(err, "[PushFCMProvider-New] invalid credentials")
+		}
+	} else {
+		credentials, err = google.FindDefaultCredentials(ctx, fcmScope)
+		if err != nil {
+			return nil, errors.Wrap(err, "[PushFCMProvider-New] error while finding default credentials")
+		}
+	}
+
+	client := oauth2.NewClient(ctx, credentials.TokenSource)
+	client.Timeout = timeout
+
+	return &FCMProvider{
+		projectID: cfg.Push.FCMProjectID,
+		url:       strings.TrimSuffix(cfg.Push.FCMURL, "/"),
+		client:    client,
+	}, nil
+}
+
+// Name returns the name of the provider
+func (p *FCMProvider) Name() string {
+	return ProviderFCM
+}
+
+// Send sends the message to every token. The unregistered tokens are reported as invalid,
+// it fails when no token is reached and the last error is not about an unregistered token.
+func (p *FCMProvider) Send(ctx context.Context, tokens []string, message *interfaces.PushMessage) ([]string, error) {
+	invalid := make([]string, 0)
+	sent := 0
+
+	var lastErr error
+	for _, token := range tokens {
+		unregistered, err := p.send(ctx, token, message)
+		switch {
+		case unregistered:
+			invalid = append(invalid, token)
+		case err != nil:
+			lastErr = err
+		default:
+			sent++
+		}
+	}
+
+	if sent == 0 && lastErr != nil {
+		return invalid, errors.Wrap(lastErr, "[PushFCMProvider-Send] error while sending message")
+	}
+
+	return invalid, nil
+}
+
+// send sends the message to the token and tells whether the token is unregistered
+func (p *FCMProvider) send(ctx context.Context, token string, message *interfaces.PushMessage) (bool, error) {
+	data, err := json.Marshal(&fcmRequest{
+		Message: fcmMessage{
+			Token:        token,
+			Notification: fcmNotification{Title: message.Title, Body: message.Body},
+			Data:         message.Data,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	url := fmt.Sprintf("%s/v1/projects/%s/messages:send", p.url, p.projectID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices {
+		return false, nil
+	}
+
+	reply, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+
+	fcmErr := &fcmErrorResponse{}
+	if json.Unmarshal(reply, fcmErr) == nil && fcmErr.unregistered() {
+		return true, nil
+	}
+
+	return false, fmt.Errorf("provider replied %d: %s", res.StatusCode, bytes.TrimSpace(reply))
+}
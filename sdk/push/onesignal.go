@@ -0,0 +1,95 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rifqiakrm/onesignal-go-lib"
+
+	"gin-starter/common/interfaces"
+	"gin-starter/config"
+)
+
+// OneSignalProvider delivers the messages through OneSignal, the tokens are player ids.
+// The OneSignal client does not take a context, the requests are bounded by the configured timeout instead.
+type OneSignalProvider struct {
+	appID  string
+	client *onesignal.Client
+}
+
+// NewOneSignalProvider initiate onesignal push provider
+func NewOneSignalProvider(cfg config.Config) (*OneSignalProvider, error) {
+	if cfg.OneSignal.AppID == "" {
+		return nil, fmt.Errorf("[PushOneSignalProvider-New] ONESIGNAL_APP_ID is required")
+	}
+
+	timeout, err := time.ParseDuration(cfg.Push.Timeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "[PushOneSignalProvider-New] invalid timeout")
+	}
+
+	client := onesignal.NewClient(&http.Client{Timeout: timeout})
+	client.AppKey = cfg.OneSignal.AppKey
+
+	return &OneSignalProvider{
+		appID:  cfg.OneSignal.AppID,
+		client: client,
+	}, nil
+}
+
+// Name returns the name of the provider
+func (p *OneSignalProvider) Name() string {
+	return ProviderOneSignal
+}
+
+// Send delivers the message to the players, OneSignal lists the unknown players in the errors of its reply
+func (p *OneSignalProvider) Send(_ context.Context, tokens []string, message *interfaces.PushMessage) ([]string, error) {
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	req := &onesignal.NotificationRequest{
+		AppID: p.appID,
+		Contents: map[string]string{
+			"en": message.Body,
+		},
+		Headings: map[string]string{
+			"en": message.Title,
+		},
+		IncludePlayerIDs: tokens,
+	}
+
+	if len(message.Data) > 0 {
+		req.Data = message.Data
+	}
+
+	res, _, err := p.client.Notifications.Create(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "[PushOneSignalProvider-Send] error while creating notification")
+	}
+
+	return oneSignalInvalidPlayers(res.Errors), nil
+}
+
+// oneSignalInvalidPlayers reads the players reported in {"invalid_player_ids": [...]},
+// the other errors OneSignal replies with are a list of messages
+func oneSignalInvalidPlayers(replyErrors interface{}) []string {
+	invalid := make([]string, 0)
+
+	reported, ok := replyErrors.(map[string]interface{})
+	if !ok {
+		return invalid
+	}
+
+	ids, _ := reported["invalid_player_ids"].([]interface{})
+	for _, id := range ids {
+		if s, ok := id.(string); ok {
+			invalid = append(invalid, s)
+		}
+	}
+
+	return invalid
+}
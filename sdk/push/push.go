@@ -0,0 +1,43 @@
+// Package push delivers push notifications to the devices of a user through OneSignal,
+// Firebase Cloud Messaging or a local fake.
+package push
+
+import (
+	"fmt"
+
+	"gin-starter/common/interfaces"
+	"gin-starter/config"
+)
+
+const (
+	// ProviderOneSignal delivers the messages through OneSignal
+	ProviderOneSignal = "onesignal"
+	// ProviderFCM delivers the messages through the HTTP v1 API of Firebase Cloud Messaging
+	ProviderFCM = "fcm"
+	// ProviderFake logs the messages instead of delivering them, for local development
+	ProviderFake = "fake"
+)
+
+// NewProvider returns the push provider of the configuration
+func NewProvider(cfg config.Config) (interfaces.PushProvider, error) {
+	switch cfg.Push.Provider {
+	case "", ProviderFake:
+		return NewFakeProvider(), nil
+	case ProviderOneSignal:
+		provider, err := NewOneSignalProvider(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return provider, nil
+	case ProviderFCM:
+		provider, err := NewFCMProvider(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return provider, nil
+	default:
+		return nil, fmt.Errorf("[Push-NewProvider] unknown provider %q", cfg.Push.Provider)
+	}
+}
@@ -0,0 +1,180 @@
+package push_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"gin-starter/common/interfaces"
+	"gin-starter/config"
+	"gin-starter/sdk/push"
+)
+
+type PushTestSuite struct {
+	suite.Suite
+}
+
+func TestPushTestSuite(t *testing.T) {
+	suite.Run(t, new(PushTestSuite))
+}
+
+func (suite *PushTestSuite) newConfig() config.Config {
+	cfg := config.Config{}
+	cfg.Push.Timeout = "5s"
+
+	return cfg
+}
+
+// writeServiceAccount writes a service account key file whose tokens are issued by tokenURL
+func (suite *PushTestSuite) writeServiceAccount(tokenURL string) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+
+	data, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "starter",
+		"private_key_id": "key",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		"client_email":   "push@starter.iam.gserviceaccount.com",
+		"token_uri":      tokenURL,
+	})
+	suite.Require().NoError(err)
+
+	path := filepath.Join(suite.T().TempDir(), "service-account.json")
+	suite.Require().NoError(os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+func (suite *PushTestSuite) TestFCMProvider() {
+	ctx := context.Background()
+	sent := make([]map[string]interface{}, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/token" {
+			_, _ = w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+
+		if r.URL.Path == "/v1/projects/missing/messages:send" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Requested entity was not found.","status":"NOT_FOUND"}}`))
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer token" || r.URL.Path != "/v1/projects/starter/messages:send" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body := make(map[string]interface{})
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		message := body["message"].(map[string]interface{})
+
+		switch message["token"] {
+		case "unregistered":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`))
+		case "malformed":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"code":400,"status":"INVALID_ARGUMENT","details":[{"errorCode":"INVALID_ARGUMENT"},{"fieldViolations":[{"field":"message.token"}]}]}}`))
+		case "broken":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":{"code":503,"status":"UNAVAILABLE"}}`))
+		default:
+			sent = append(sent, message)
+			_, _ = w.Write([]byte(`{"name":"projects/starter/messages/1"}`))
+		}
+	}))
+	defer server.Close()
+
+	cfg := suite.newConfig()
+	cfg.Push.FCMProjectID = "starter"
+	cfg.Push.FCMCredentialsFile = suite.writeServiceAccount(server.URL + "/token")
+	cfg.Push.FCMURL = server.URL
+
+	provider, err := push.NewFCMProvider(cfg)
+	suite.Require().NoError(err)
+
+	suite.Run("successfully send the message and report the unregistered tokens", func() {
+		message := &interfaces.PushMessage{Title: "Title", Body: "Body", Data: map[string]string{"type": "info"}}
+
+		invalid, err := provider.Send(ctx, []string{"device", "unregistered", "malformed", "broken"}, message)
+
+		suite.Nil(err)
+		suite.Equal([]string{"unregistered", "malformed"}, invalid)
+		suite.Require().Len(sent, 1)
+		suite.Equal("device", sent[0]["token"])
+		suite.Equal(map[string]interface{}{"title": "Title", "body": "Body"}, sent[0]["notification"])
+		suite.Equal(map[string]interface{}{"type": "info"}, sent[0]["data"])
+	})
+
+	suite.Run("fail when no device is reached", func() {
+		_, err := provider.Send(ctx, []string{"broken"}, &interfaces.PushMessage{Title: "Title"})
+
+		suite.NotNil(err)
+	})
+
+	suite.Run("fail without reporting the tokens when the project is not found", func() {
+		cfg.Push.FCMProjectID = "missing"
+		missing, err := push.NewFCMProvider(cfg)
+		suite.Require().NoError(err)
+
+		invalid, err := missing.Send(ctx, []string{"device", "other"}, &interfaces.PushMessage{Title: "Title"})
+
+		suite.NotNil(err)
+		suite.Empty(invalid)
+	})
+}
+
+func (suite *PushTestSuite) TestFakeProvider() {
+	ctx := context.Background()
+	provider := push.NewFakeProvider()
+	provider.Invalidate("two")
+
+	invalid, err := provider.Send(ctx, []string{"one", "two"}, &interfaces.PushMessage{Title: "Title", Body: "Body"})
+
+	suite.Nil(err)
+	suite.Equal([]string{"two"}, invalid)
+	suite.Require().Len(provider.Messages(), 1)
+	suite.Equal([]string{"one", "two"}, provider.Messages()[0].Tokens)
+	suite.Equal("Title", provider.Messages()[0].Title)
+}
+
+func (suite *PushTestSuite) TestNewProvider() {
+	cfg := suite.newConfig()
+
+	cfg.Push.Provider = push.ProviderFake
+	provider, err := push.NewProvider(cfg)
+	suite.Nil(err)
+	suite.Equal(push.ProviderFake, provider.Name())
+
+	cfg.Push.Provider = push.ProviderOneSignal
+	_, err = push.NewProvider(cfg)
+	suite.NotNil(err)
+
+	cfg.OneSignal.AppID = "app"
+	provider, err = push.NewProvider(cfg)
+	suite.Nil(err)
+	suite.Equal(push.ProviderOneSignal, provider.Name())
+
+	cfg.Push.Provider = push.ProviderFCM
+	_, err = push.NewProvider(cfg)
+	suite.NotNil(err)
+
+	cfg.Push.Provider = "carrier-pigeon"
+	_, err = push.NewProvider(cfg)
+	suite.NotNil(err)
+}
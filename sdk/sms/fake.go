@@ -0,0 +1,43 @@
+package sms
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// Message is a message recorded by the fake sender
+type Message struct {
+	PhoneNumber string
+	Text        string
+}
+
+// FakeSender logs the messages instead of sending them and keeps them for inspection
+type FakeSender struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewFakeSender initiate fake sms sender
+func NewFakeSender() *FakeSender {
+	return &FakeSender{}
+}
+
+// Send logs the message
+func (s *FakeSender) Send(_ context.Context, phoneNumber, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	log.Printf("[SMSFakeSender-Send] to %s: %s", phoneNumber, message)
+	s.messages = append(s.messages, Message{PhoneNumber: phoneNumber, Text: message})
+
+	return nil
+}
+
+// Messages returns the messages sent so far
+func (s *FakeSender) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Message(nil), s.messages...)
+}
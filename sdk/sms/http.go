@@ -0,0 +1,80 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gin-starter/config"
+)
+
+// HTTPSender sends messages through the JSON API of an SMS gateway.
+// It posts {"from", "to", "text"} with the API key as bearer token and treats any 2xx status as accepted.
+type HTTPSender struct {
+	url      string
+	apiKey   string
+	senderID string
+	client   *http.Client
+}
+
+type httpMessage struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to"`
+	Text string `json:"text"`
+}
+
+// NewHTTPSender initiate http sms sender
+func NewHTTPSender(cfg config.Config) (*HTTPSender, error) {
+	if cfg.SMS.HTTPURL == "" {
+		return nil, fmt.Errorf("[SMSHTTPSender-New] SMS_HTTP_URL is required")
+	}
+
+	timeout, err := time.ParseDuration(cfg.SMS.Timeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "[SMSHTTPSender-New] invalid timeout")
+	}
+
+	return &HTTPSender{
+		url:      cfg.SMS.HTTPURL,
+		apiKey:   cfg.SMS.APIKey,
+		senderID: cfg.SMS.SenderID,
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Send sends the message to the phone number
+func (s *HTTPSender) Send(ctx context.Context, phoneNumber, message string) error {
+	body, err := json.Marshal(&httpMessage{From: s.senderID, To: phoneNumber, Text: message})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "[SMSHTTPSender-Send] error while building request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "[SMSHTTPSender-Send] error while calling provider")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		reply, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("[SMSHTTPSender-Send] provider replied %d: %s", res.StatusCode, bytes.TrimSpace(reply))
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/pkg/errors"
+
+	commonCache "gin-starter/common/cache"
+	commonErrors "gin-starter/common/errors"
+	"gin-starter/common/interfaces"
+	"gin-starter/config"
+)
+
+// RateLimitedSender limits the messages sent to each phone number in front of another sender.
+// A number receives at most limit messages per window and waits interval between two messages.
+// The limits are kept in counters every instance changes atomically, concurrent requests cannot exceed them.
+type RateLimitedSender struct {
+	counter  interfaces.Counter
+	next     interfaces.SMSSender
+	limit    int
+	window   time.Duration
+	interval time.Duration
+}
+
+// NewRateLimitedSender is a constructor for RateLimitedSender
+func NewRateLimitedSender(cfg config.Config, counter interfaces.Counter, next interfaces.SMSSender) (*RateLimitedSender, error) {
+	window, err := time.ParseDuration(cfg.SMS.RateWindow)
+	if err != nil {
+		return nil, errors.Wrap(err, "[SMSRateLimitedSender-New] invalid rate window")
+	}
+
+	interval, err := time.ParseDuration(cfg.SMS.ResendInterval)
+	if err != nil {
+		return nil, errors.Wrap(err, "[SMSRateLimitedSender-New] invalid resend interval")
+	}
+
+	return &RateLimitedSender{
+		counter:  counter,
+		next:     next,
+		limit:    cfg.SMS.RateLimit,
+		window:   window,
+		interval: interval,
+	}, nil
+}
+
+// Send sends the message unless the phone number exceeded its rate.
+// Attempts are counted before sending so failing messages cannot be retried without limit.
+func (s *RateLimitedSender) Send(ctx context.Context, phoneNumber, message string) error {
+	if s.interval > 0 {
+		free, err := s.counter.SetNX(fmt.Sprintf(commonCache.SMSResendByPhoneNumber, phoneNumber), s.interval)
+		if err != nil {
+			// an unavailable cache must not lock users out of their codes
+			log.Println("[SMSRateLimitedSender-Send]", err)
+			return s.next.Send(ctx, phoneNumber, message)
+		}
+
+		if !free {
+			return commonErrors.ErrSMSRateLimited.Error()
+		}
+	}
+
+	if s.limit > 0 && s.window > 0 {
+		count, err := s.counter.Increment(fmt.Sprintf(commonCache.SMSRateByPhoneNumber, phoneNumber), s.window)
+		if err != nil {
+			log.Println("[SMSRateLimitedSender-Send]", err)
+			return s.next.Send(ctx, phoneNumber, message)
+		}
+
+		if count > int64(s.limit) {
+			return commonErrors.ErrSMSRateLimited.Error()
+		}
+	}
+
+	return s.next.Send(ctx, phoneNumber, message)
+}
@@ -0,0 +1,33 @@
+// Package sms sends text messages through an HTTP provider or a local fake and limits the rate of messages per phone number.
+package sms
+
+import (
+	"fmt"
+
+	"gin-starter/common/interfaces"
+	"gin-starter/config"
+)
+
+const (
+	// ProviderHTTP sends the messages through the HTTP API of an SMS gateway
+	ProviderHTTP = "http"
+	// ProviderFake logs the messages instead of sending them, for local development
+	ProviderFake = "fake"
+)
+
+// NewSender returns the SMS sender of the configuration
+func NewSender(cfg config.Config) (interfaces.SMSSender, error) {
+	switch cfg.SMS.Provider {
+	case "", ProviderFake:
+		return NewFakeSender(), nil
+	case ProviderHTTP:
+		sender, err := NewHTTPSender(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return sender, nil
+	default:
+		return nil, fmt.Errorf("[SMS-NewSender] unknown provider %q", cfg.SMS.Provider)
+	}
+}
@@ -0,0 +1,174 @@
+package sms_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"gin-starter/common/errors"
+	"gin-starter/config"
+	"gin-starter/sdk/sms"
+)
+
+// memoryCounter is a minimal in-memory counter honouring the ttl of its keys
+type memoryCounter struct {
+	mu      sync.Mutex
+	counts  map[string]int64
+	expires map[string]time.Time
+}
+
+func newMemoryCounter() *memoryCounter {
+	return &memoryCounter{counts: map[string]int64{}, expires: map[string]time.Time{}}
+}
+
+func (c *memoryCounter) live(key string) bool {
+	at, ok := c.expires[key]
+	return ok && time.Now().Before(at)
+}
+
+func (c *memoryCounter) Increment(key string, ttl time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.live(key) {
+		c.counts[key] = 0
+		c.expires[key] = time.Now().Add(ttl)
+	}
+
+	c.counts[key]++
+	return c.counts[key], nil
+}
+
+func (c *memoryCounter) SetNX(key string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.live(key) {
+		return false, nil
+	}
+
+	c.expires[key] = time.Now().Add(ttl)
+	return true, nil
+}
+
+type SMSTestSuite struct {
+	suite.Suite
+}
+
+func TestSMSTestSuite(t *testing.T) {
+	suite.Run(t, new(SMSTestSuite))
+}
+
+func (suite *SMSTestSuite) newConfig() config.Config {
+	cfg := config.Config{}
+	cfg.SMS.Timeout = "5s"
+	cfg.SMS.RateLimit = 2
+	cfg.SMS.RateWindow = "1h"
+	cfg.SMS.ResendInterval = "0s"
+
+	return cfg
+}
+
+func (suite *SMSTestSuite) TestHTTPSender_Send() {
+	var received map[string]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	cfg := suite.newConfig()
+	cfg.SMS.HTTPURL = server.URL
+	cfg.SMS.APIKey = "key"
+	cfg.SMS.SenderID = "STARTER"
+
+	sender, err := sms.NewHTTPSender(cfg)
+	suite.Require().NoError(err)
+
+	suite.Run("successfully post the message", func() {
+		suite.Nil(sender.Send(context.Background(), "+6281234567890", "code 123456"))
+		suite.Equal(map[string]string{"from": "STARTER", "to": "+6281234567890", "text": "code 123456"}, received)
+	})
+
+	suite.Run("fail when the provider rejects the message", func() {
+		cfg.SMS.APIKey = "wrong"
+		rejected, err := sms.NewHTTPSender(cfg)
+		suite.Require().NoError(err)
+
+		suite.NotNil(rejected.Send(context.Background(), "+6281234567890", "code 123456"))
+	})
+}
+
+func (suite *SMSTestSuite) TestRateLimitedSender_Send() {
+	ctx := context.Background()
+	fake := sms.NewFakeSender()
+
+	sender, err := sms.NewRateLimitedSender(suite.newConfig(), newMemoryCounter(), fake)
+	suite.Require().NoError(err)
+
+	suite.Nil(sender.Send(ctx, "+6281234567890", "one"))
+	suite.Nil(sender.Send(ctx, "+6281234567890", "two"))
+	suite.Equal(errors.ErrSMSRateLimited.Error(), sender.Send(ctx, "+6281234567890", "three"))
+	suite.Nil(sender.Send(ctx, "+6289876543210", "other number"))
+	suite.Len(fake.Messages(), 3)
+}
+
+func (suite *SMSTestSuite) TestRateLimitedSender_Concurrent() {
+	ctx := context.Background()
+	fake := sms.NewFakeSender()
+
+	sender, err := sms.NewRateLimitedSender(suite.newConfig(), newMemoryCounter(), fake)
+	suite.Require().NoError(err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = sender.Send(ctx, "+6281234567890", "code")
+		}()
+	}
+	wg.Wait()
+
+	suite.Len(fake.Messages(), 2)
+}
+
+func (suite *SMSTestSuite) TestRateLimitedSender_ResendInterval() {
+	ctx := context.Background()
+	cfg := suite.newConfig()
+	cfg.SMS.ResendInterval = "1h"
+
+	sender, err := sms.NewRateLimitedSender(cfg, newMemoryCounter(), sms.NewFakeSender())
+	suite.Require().NoError(err)
+
+	suite.Nil(sender.Send(ctx, "+6281234567890", "one"))
+	suite.Equal(errors.ErrSMSRateLimited.Error(), sender.Send(ctx, "+6281234567890", "two"))
+}
+
+func (suite *SMSTestSuite) TestNewSender() {
+	cfg := suite.newConfig()
+
+	cfg.SMS.Provider = sms.ProviderFake
+	_, err := sms.NewSender(cfg)
+	suite.Nil(err)
+
+	cfg.SMS.Provider = sms.ProviderHTTP
+	_, err = sms.NewSender(cfg)
+	suite.NotNil(err)
+
+	cfg.SMS.Provider = "carrier-pigeon"
+	_, err = sms.NewSender(cfg)
+	suite.NotNil(err)
+}
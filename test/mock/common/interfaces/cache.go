@@ -0,0 +1,189 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./common/interfaces/cache.go
+
+// Package mock_interfaces is a generated GoMock package.
+package mock_interfaces
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCacheable is a mock of Cacheable interface.
+type MockCacheable struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheableMockRecorder
+}
+
+// MockCacheableMockRecorder is the mock recorder for MockCacheable.
+type MockCacheableMockRecorder struct {
+	mock *MockCacheable
+}
+
+// NewMockCacheable creates a new mock instance.
+func NewMockCacheable(ctrl *gomock.Controller) *MockCacheable {
+	mock := &MockCacheable{ctrl: ctrl}
+	mock.recorder = &MockCacheableMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCacheable) EXPECT() *MockCacheableMockRecorder {
+	return m.recorder
+}
+
+// BulkRemove mocks base method.
+func (m *MockCacheable) BulkRemove(pattern string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkRemove", pattern)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BulkRemove indicates an expected call of BulkRemove.
+func (mr *MockCacheableMockRecorder) BulkRemove(pattern interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkRemove", reflect.TypeOf((*MockCacheable)(nil).BulkRemove), pattern)
+}
+
+// Exists mocks base method.
+func (m *MockCacheable) Exists(key string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockCacheableMockRecorder) Exists(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockCacheable)(nil).Exists), key)
+}
+
+// Get mocks base method.
+func (m *MockCacheable) Get(key string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", key)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCacheableMockRecorder) Get(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCacheable)(nil).Get), key)
+}
+
+// Remove mocks base method.
+func (m *MockCacheable) Remove(key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockCacheableMockRecorder) Remove(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockCacheable)(nil).Remove), key)
+}
+
+// Scan mocks base method.
+func (m *MockCacheable) Scan(pattern string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scan", pattern)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockCacheableMockRecorder) Scan(pattern interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockCacheable)(nil).Scan), pattern)
+}
+
+// Set mocks base method.
+func (m *MockCacheable) Set(key string, value interface{}, ttl int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockCacheableMockRecorder) Set(key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockCacheable)(nil).Set), key, value, ttl)
+}
+
+// SetWithExpireAt mocks base method.
+func (m *MockCacheable) SetWithExpireAt(key string, value interface{}, ttl time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWithExpireAt", key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWithExpireAt indicates an expected call of SetWithExpireAt.
+func (mr *MockCacheableMockRecorder) SetWithExpireAt(key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWithExpireAt", reflect.TypeOf((*MockCacheable)(nil).SetWithExpireAt), key, value, ttl)
+}
+
+// MockCounter is a mock of Counter interface.
+type MockCounter struct {
+	ctrl     *gomock.Controller
+	recorder *MockCounterMockRecorder
+}
+
+// MockCounterMockRecorder is the mock recorder for MockCounter.
+type MockCounterMockRecorder struct {
+	mock *MockCounter
+}
+
+// NewMockCounter creates a new mock instance.
+func NewMockCounter(ctrl *gomock.Controller) *MockCounter {
+	mock := &MockCounter{ctrl: ctrl}
+	mock.recorder = &MockCounterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCounter) EXPECT() *MockCounterMockRecorder {
+	return m.recorder
+}
+
+// Increment mocks base method.
+func (m *MockCounter) Increment(key string, ttl time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increment", key, ttl)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Increment indicates an expected call of Increment.
+func (mr *MockCounterMockRecorder) Increment(key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increment", reflect.TypeOf((*MockCounter)(nil).Increment), key, ttl)
+}
+
+// SetNX mocks base method.
+func (m *MockCounter) SetNX(key string, ttl time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNX", key, ttl)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetNX indicates an expected call of SetNX.
+func (mr *MockCounterMockRecorder) SetNX(key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNX", reflect.TypeOf((*MockCounter)(nil).SetNX), key, ttl)
+}
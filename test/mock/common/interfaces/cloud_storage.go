@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./common/interfaces/cloud_storage.go
+
+// Package mock_interfaces is a generated GoMock package.
+package mock_interfaces
+
+import (
+	io "io"
+	multipart "mime/multipart"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCloudStorageUseCase is a mock of CloudStorageUseCase interface.
+type MockCloudStorageUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockCloudStorageUseCaseMockRecorder
+}
+
+// MockCloudStorageUseCaseMockRecorder is the mock recorder for MockCloudStorageUseCase.
+type MockCloudStorageUseCaseMockRecorder struct {
+	mock *MockCloudStorageUseCase
+}
+
+// NewMockCloudStorageUseCase creates a new mock instance.
+func NewMockCloudStorageUseCase(ctrl *gomock.Controller) *MockCloudStorageUseCase {
+	mock := &MockCloudStorageUseCase{ctrl: ctrl}
+	mock.recorder = &MockCloudStorageUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCloudStorageUseCase) EXPECT() *MockCloudStorageUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockCloudStorageUseCase) Delete(path string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", path)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCloudStorageUseCaseMockRecorder) Delete(path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCloudStorageUseCase)(nil).Delete), path)
+}
+
+// Download mocks base method.
+func (m *MockCloudStorageUseCase) Download(path string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Download", path)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Download indicates an expected call of Download.
+func (mr *MockCloudStorageUseCaseMockRecorder) Download(path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Download", reflect.TypeOf((*MockCloudStorageUseCase)(nil).Download), path)
+}
+
+// Upload mocks base method.
+func (m *MockCloudStorageUseCase) Upload(f *multipart.FileHeader, folder string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", f, folder)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upload indicates an expected call of Upload.
+func (mr *MockCloudStorageUseCaseMockRecorder) Upload(f, folder interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockCloudStorageUseCase)(nil).Upload), f, folder)
+}
+
+// UploadObject mocks base method.
+func (m *MockCloudStorageUseCase) UploadObject(r io.Reader, path, contentType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadObject", r, path, contentType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UploadObject indicates an expected call of UploadObject.
+func (mr *MockCloudStorageUseCaseMockRecorder) UploadObject(r, path, contentType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadObject", reflect.TypeOf((*MockCloudStorageUseCase)(nil).UploadObject), r, path, contentType)
+}
+
+// UploadSavedFile mocks base method.
+func (m *MockCloudStorageUseCase) UploadSavedFile(filepath, folder string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadSavedFile", filepath, folder)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadSavedFile indicates an expected call of UploadSavedFile.
+func (mr *MockCloudStorageUseCaseMockRecorder) UploadSavedFile(filepath, folder interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadSavedFile", reflect.TypeOf((*MockCloudStorageUseCase)(nil).UploadSavedFile), filepath, folder)
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./common/interfaces/content_scanner.go
+
+// Package mock_interfaces is a generated GoMock package.
+package mock_interfaces
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockContentScanner is a mock of ContentScanner interface.
+type MockContentScanner struct {
+	ctrl     *gomock.Controller
+	recorder *MockContentScannerMockRecorder
+}
+
+// MockContentScannerMockRecorder is the mock recorder for MockContentScanner.
+type MockContentScannerMockRecorder struct {
+	mock *MockContentScanner
+}
+
+// NewMockContentScanner creates a new mock instance.
+func NewMockContentScanner(ctrl *gomock.Controller) *MockContentScanner {
+	mock := &MockContentScanner{ctrl: ctrl}
+	mock.recorder = &MockContentScannerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContentScanner) EXPECT() *MockContentScannerMockRecorder {
+	return m.recorder
+}
+
+// Scan mocks base method.
+func (m *MockContentScanner) Scan(ctx context.Context, r io.Reader) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scan", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockContentScannerMockRecorder) Scan(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockContentScanner)(nil).Scan), ctx, r)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./common/interfaces/device_detacher.go
+
+// Package mock_interfaces is a generated GoMock package.
+package mock_interfaces
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockDeviceDetacher is a mock of DeviceDetacher interface.
+type MockDeviceDetacher struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceDetacherMockRecorder
+}
+
+// MockDeviceDetacherMockRecorder is the mock recorder for MockDeviceDetacher.
+type MockDeviceDetacherMockRecorder struct {
+	mock *MockDeviceDetacher
+}
+
+// NewMockDeviceDetacher creates a new mock instance.
+func NewMockDeviceDetacher(ctrl *gomock.Controller) *MockDeviceDetacher {
+	mock := &MockDeviceDetacher{ctrl: ctrl}
+	mock.recorder = &MockDeviceDetacherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceDetacher) EXPECT() *MockDeviceDetacherMockRecorder {
+	return m.recorder
+}
+
+// DetachDevices mocks base method.
+func (m *MockDeviceDetacher) DetachDevices(ctx context.Context, userID uuid.UUID, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachDevices", ctx, userID, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachDevices indicates an expected call of DetachDevices.
+func (mr *MockDeviceDetacherMockRecorder) DetachDevices(ctx, userID, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachDevices", reflect.TypeOf((*MockDeviceDetacher)(nil).DetachDevices), ctx, userID, token)
+}
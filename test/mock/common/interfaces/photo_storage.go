@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./common/interfaces/photo_storage.go
+
+// Package mock_interfaces is a generated GoMock package.
+package mock_interfaces
+
+import (
+	context "context"
+	entity "gin-starter/entity"
+	multipart "mime/multipart"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPhotoStorageUseCase is a mock of PhotoStorageUseCase interface.
+type MockPhotoStorageUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockPhotoStorageUseCaseMockRecorder
+}
+
+// MockPhotoStorageUseCaseMockRecorder is the mock recorder for MockPhotoStorageUseCase.
+type MockPhotoStorageUseCaseMockRecorder struct {
+	mock *MockPhotoStorageUseCase
+}
+
+// NewMockPhotoStorageUseCase creates a new mock instance.
+func NewMockPhotoStorageUseCase(ctrl *gomock.Controller) *MockPhotoStorageUseCase {
+	mock := &MockPhotoStorageUseCase{ctrl: ctrl}
+	mock.recorder = &MockPhotoStorageUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPhotoStorageUseCase) EXPECT() *MockPhotoStorageUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockPhotoStorageUseCase) Delete(user *entity.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPhotoStorageUseCaseMockRecorder) Delete(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPhotoStorageUseCase)(nil).Delete), user)
+}
+
+// Upload mocks base method.
+func (m *MockPhotoStorageUseCase) Upload(ctx context.Context, f *multipart.FileHeader, folder string) (*entity.UserPhoto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", ctx, f, folder)
+	ret0, _ := ret[0].(*entity.UserPhoto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upload indicates an expected call of Upload.
+func (mr *MockPhotoStorageUseCaseMockRecorder) Upload(ctx, f, folder interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockPhotoStorageUseCase)(nil).Upload), ctx, f, folder)
+}
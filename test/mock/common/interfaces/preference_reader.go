@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./common/interfaces/preference_reader.go
+
+// Package mock_interfaces is a generated GoMock package.
+package mock_interfaces
+
+import (
+	context "context"
+	entity "gin-starter/entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockPreferenceReader is a mock of PreferenceReader interface.
+type MockPreferenceReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockPreferenceReaderMockRecorder
+}
+
+// MockPreferenceReaderMockRecorder is the mock recorder for MockPreferenceReader.
+type MockPreferenceReaderMockRecorder struct {
+	mock *MockPreferenceReader
+}
+
+// NewMockPreferenceReader creates a new mock instance.
+func NewMockPreferenceReader(ctrl *gomock.Controller) *MockPreferenceReader {
+	mock := &MockPreferenceReader{ctrl: ctrl}
+	mock.recorder = &MockPreferenceReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPreferenceReader) EXPECT() *MockPreferenceReaderMockRecorder {
+	return m.recorder
+}
+
+// GetPreferences mocks base method.
+func (m *MockPreferenceReader) GetPreferences(ctx context.Context, userID uuid.UUID) (entity.Preferences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreferences", ctx, userID)
+	ret0, _ := ret[0].(entity.Preferences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreferences indicates an expected call of GetPreferences.
+func (mr *MockPreferenceReaderMockRecorder) GetPreferences(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreferences", reflect.TypeOf((*MockPreferenceReader)(nil).GetPreferences), ctx, userID)
+}
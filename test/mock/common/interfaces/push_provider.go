@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./common/interfaces/push_provider.go
+
+// Package mock_interfaces is a generated GoMock package.
+package mock_interfaces
+
+import (
+	context "context"
+	interfaces "gin-starter/common/interfaces"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPushProvider is a mock of PushProvider interface.
+type MockPushProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockPushProviderMockRecorder
+}
+
+// MockPushProviderMockRecorder is the mock recorder for MockPushProvider.
+type MockPushProviderMockRecorder struct {
+	mock *MockPushProvider
+}
+
+// NewMockPushProvider creates a new mock instance.
+func NewMockPushProvider(ctrl *gomock.Controller) *MockPushProvider {
+	mock := &MockPushProvider{ctrl: ctrl}
+	mock.recorder = &MockPushProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPushProvider) EXPECT() *MockPushProviderMockRecorder {
+	return m.recorder
+}
+
+// Name mocks base method.
+func (m *MockPushProvider) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockPushProviderMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockPushProvider)(nil).Name))
+}
+
+// Send mocks base method.
+func (m *MockPushProvider) Send(ctx context.Context, tokens []string, message *interfaces.PushMessage) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, tokens, message)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockPushProviderMockRecorder) Send(ctx, tokens, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockPushProvider)(nil).Send), ctx, tokens, message)
+}
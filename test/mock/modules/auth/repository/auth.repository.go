@@ -1,16 +1,16 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ./modules/auth/repository/auth.repository.go
+// Source: ./modules/auth/v1/repository/auth.repository.go
 
 // Package mock_repository is a generated GoMock package.
 package mock_repository
 
 import (
 	context "context"
+	entity "gin-starter/entity"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
-
-	entity "gin-starter/entity"
+	uuid "github.com/google/uuid"
 )
 
 // MockAuthRepositoryUseCase is a mock of AuthRepositoryUseCase interface.
@@ -36,6 +36,34 @@ func (m *MockAuthRepositoryUseCase) EXPECT() *MockAuthRepositoryUseCaseMockRecor
 	return m.recorder
 }
 
+// AssignRole mocks base method.
+func (m *MockAuthRepositoryUseCase) AssignRole(ctx context.Context, userID, roleID uuid.UUID, updatedBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignRole", ctx, userID, roleID, updatedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignRole indicates an expected call of AssignRole.
+func (mr *MockAuthRepositoryUseCaseMockRecorder) AssignRole(ctx, userID, roleID, updatedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRole", reflect.TypeOf((*MockAuthRepositoryUseCase)(nil).AssignRole), ctx, userID, roleID, updatedBy)
+}
+
+// CreateAdmin mocks base method.
+func (m *MockAuthRepositoryUseCase) CreateAdmin(ctx context.Context, user *entity.User, roleID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAdmin", ctx, user, roleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAdmin indicates an expected call of CreateAdmin.
+func (mr *MockAuthRepositoryUseCaseMockRecorder) CreateAdmin(ctx, user, roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAdmin", reflect.TypeOf((*MockAuthRepositoryUseCase)(nil).CreateAdmin), ctx, user, roleID)
+}
+
 // GetAdminByEmail mocks base method.
 func (m *MockAuthRepositoryUseCase) GetAdminByEmail(ctx context.Context, email string) (*entity.User, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +79,21 @@ func (mr *MockAuthRepositoryUseCaseMockRecorder) GetAdminByEmail(ctx, email inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminByEmail", reflect.TypeOf((*MockAuthRepositoryUseCase)(nil).GetAdminByEmail), ctx, email)
 }
 
+// GetRoleByName mocks base method.
+func (m *MockAuthRepositoryUseCase) GetRoleByName(ctx context.Context, name string) (*entity.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoleByName", ctx, name)
+	ret0, _ := ret[0].(*entity.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoleByName indicates an expected call of GetRoleByName.
+func (mr *MockAuthRepositoryUseCaseMockRecorder) GetRoleByName(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleByName", reflect.TypeOf((*MockAuthRepositoryUseCase)(nil).GetRoleByName), ctx, name)
+}
+
 // GetUserByEmail mocks base method.
 func (m *MockAuthRepositoryUseCase) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
 	m.ctrl.T.Helper()
@@ -66,6 +109,21 @@ func (mr *MockAuthRepositoryUseCaseMockRecorder) GetUserByEmail(ctx, email inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockAuthRepositoryUseCase)(nil).GetUserByEmail), ctx, email)
 }
 
+// IsEmailReserved mocks base method.
+func (m *MockAuthRepositoryUseCase) IsEmailReserved(ctx context.Context, email string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEmailReserved", ctx, email)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsEmailReserved indicates an expected call of IsEmailReserved.
+func (mr *MockAuthRepositoryUseCaseMockRecorder) IsEmailReserved(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEmailReserved", reflect.TypeOf((*MockAuthRepositoryUseCase)(nil).IsEmailReserved), ctx, email)
+}
+
 // UpdateOTP mocks base method.
 func (m *MockAuthRepositoryUseCase) UpdateOTP(ctx context.Context, user *entity.User, otp string) error {
 	m.ctrl.T.Helper()
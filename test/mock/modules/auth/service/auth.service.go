@@ -1,16 +1,16 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ./modules/auth/service/auth.service.go
+// Source: ./modules/auth/v1/service/auth.service.go
 
 // Package mock_service is a generated GoMock package.
 package mock_service
 
 import (
 	context "context"
+	entity "gin-starter/entity"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
-
-	entity "gin-starter/entity"
+	uuid "github.com/google/uuid"
 )
 
 // MockAuthUseCase is a mock of AuthUseCase interface.
@@ -95,3 +95,17 @@ func (mr *MockAuthUseCaseMockRecorder) GenerateAccessTokenCMS(ctx, user interfac
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateAccessTokenCMS", reflect.TypeOf((*MockAuthUseCase)(nil).GenerateAccessTokenCMS), ctx, user)
 }
+
+// Logout mocks base method.
+func (m *MockAuthUseCase) Logout(ctx context.Context, userID uuid.UUID, deviceToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, userID, deviceToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthUseCaseMockRecorder) Logout(ctx, userID, deviceToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthUseCase)(nil).Logout), ctx, userID, deviceToken)
+}
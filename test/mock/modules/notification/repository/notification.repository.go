@@ -1,17 +1,17 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ./modules/notification/repository/notification.repository.go
+// Source: ./modules/notification/v1/repository/notification.repository.go
 
 // Package mock_repository is a generated GoMock package.
 package mock_repository
 
 import (
 	context "context"
+	query "gin-starter/common/query"
+	entity "gin-starter/entity"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
-
-	entity "gin-starter/entity"
 )
 
 // MockNotificationRepositoryUseCase is a mock of NotificationRepositoryUseCase interface.
@@ -67,31 +67,32 @@ func (mr *MockNotificationRepositoryUseCaseMockRecorder) Create(ctx, notificatio
 }
 
 // GetNotification mocks base method.
-func (m *MockNotificationRepositoryUseCase) GetNotification(ctx context.Context, id uuid.UUID, sort, order string, limit, offset int) ([]*entity.Notification, int64, error) {
+func (m *MockNotificationRepositoryUseCase) GetNotification(ctx context.Context, id uuid.UUID, q *query.Query) ([]*entity.Notification, *query.Page, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNotification", ctx, id, sort, order, limit, offset)
+	ret := m.ctrl.Call(m, "GetNotification", ctx, id, q)
 	ret0, _ := ret[0].([]*entity.Notification)
-	ret1, _ := ret[1].(int64)
+	ret1, _ := ret[1].(*query.Page)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
 // GetNotification indicates an expected call of GetNotification.
-func (mr *MockNotificationRepositoryUseCaseMockRecorder) GetNotification(ctx, id, sort, order, limit, offset interface{}) *gomock.Call {
+func (mr *MockNotificationRepositoryUseCaseMockRecorder) GetNotification(ctx, id, q interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotification", reflect.TypeOf((*MockNotificationRepositoryUseCase)(nil).GetNotification), ctx, id, sort, order, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotification", reflect.TypeOf((*MockNotificationRepositoryUseCase)(nil).GetNotification), ctx, id, q)
 }
 
-// UpdateReadNotification mocks base method.
-func (m *MockNotificationRepositoryUseCase) UpdateReadNotification(ctx context.Context, id uuid.UUID) error {
+// UpdateReceipts mocks base method.
+func (m *MockNotificationRepositoryUseCase) UpdateReceipts(ctx context.Context, userID uuid.UUID, state string, ids []uuid.UUID) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateReadNotification", ctx, id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UpdateReceipts", ctx, userID, state, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateReadNotification indicates an expected call of UpdateReadNotification.
-func (mr *MockNotificationRepositoryUseCaseMockRecorder) UpdateReadNotification(ctx, id interface{}) *gomock.Call {
+// UpdateReceipts indicates an expected call of UpdateReceipts.
+func (mr *MockNotificationRepositoryUseCaseMockRecorder) UpdateReceipts(ctx, userID, state, ids interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReadNotification", reflect.TypeOf((*MockNotificationRepositoryUseCase)(nil).UpdateReadNotification), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReceipts", reflect.TypeOf((*MockNotificationRepositoryUseCase)(nil).UpdateReceipts), ctx, userID, state, ids)
 }
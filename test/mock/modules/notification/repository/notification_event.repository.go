@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./modules/notification/v1/repository/notification_event.repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	entity "gin-starter/entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockNotificationEventRepositoryUseCase is a mock of NotificationEventRepositoryUseCase interface.
+type MockNotificationEventRepositoryUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationEventRepositoryUseCaseMockRecorder
+}
+
+// MockNotificationEventRepositoryUseCaseMockRecorder is the mock recorder for MockNotificationEventRepositoryUseCase.
+type MockNotificationEventRepositoryUseCaseMockRecorder struct {
+	mock *MockNotificationEventRepositoryUseCase
+}
+
+// NewMockNotificationEventRepositoryUseCase creates a new mock instance.
+func NewMockNotificationEventRepositoryUseCase(ctrl *gomock.Controller) *MockNotificationEventRepositoryUseCase {
+	mock := &MockNotificationEventRepositoryUseCase{ctrl: ctrl}
+	mock.recorder = &MockNotificationEventRepositoryUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationEventRepositoryUseCase) EXPECT() *MockNotificationEventRepositoryUseCaseMockRecorder {
+	return m.recorder
+}
+
+// FindAfter mocks base method.
+func (m *MockNotificationEventRepositoryUseCase) FindAfter(ctx context.Context, userID uuid.UUID, id string) ([]*entity.NotificationEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAfter", ctx, userID, id)
+	ret0, _ := ret[0].([]*entity.NotificationEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAfter indicates an expected call of FindAfter.
+func (mr *MockNotificationEventRepositoryUseCaseMockRecorder) FindAfter(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAfter", reflect.TypeOf((*MockNotificationEventRepositoryUseCase)(nil).FindAfter), ctx, userID, id)
+}
+
+// Publish mocks base method.
+func (m *MockNotificationEventRepositoryUseCase) Publish(ctx context.Context, event *entity.NotificationEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockNotificationEventRepositoryUseCaseMockRecorder) Publish(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockNotificationEventRepositoryUseCase)(nil).Publish), ctx, event)
+}
+
+// Subscribe mocks base method.
+func (m *MockNotificationEventRepositoryUseCase) Subscribe(ctx context.Context, handle func(*entity.NotificationEvent)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockNotificationEventRepositoryUseCaseMockRecorder) Subscribe(ctx, handle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockNotificationEventRepositoryUseCase)(nil).Subscribe), ctx, handle)
+}
@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./modules/notification/v1/repository/notification_template.repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	query "gin-starter/common/query"
+	entity "gin-starter/entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockNotificationTemplateRepositoryUseCase is a mock of NotificationTemplateRepositoryUseCase interface.
+type MockNotificationTemplateRepositoryUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationTemplateRepositoryUseCaseMockRecorder
+}
+
+// MockNotificationTemplateRepositoryUseCaseMockRecorder is the mock recorder for MockNotificationTemplateRepositoryUseCase.
+type MockNotificationTemplateRepositoryUseCaseMockRecorder struct {
+	mock *MockNotificationTemplateRepositoryUseCase
+}
+
+// NewMockNotificationTemplateRepositoryUseCase creates a new mock instance.
+func NewMockNotificationTemplateRepositoryUseCase(ctrl *gomock.Controller) *MockNotificationTemplateRepositoryUseCase {
+	mock := &MockNotificationTemplateRepositoryUseCase{ctrl: ctrl}
+	mock.recorder = &MockNotificationTemplateRepositoryUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationTemplateRepositoryUseCase) EXPECT() *MockNotificationTemplateRepositoryUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockNotificationTemplateRepositoryUseCase) Create(ctx context.Context, template *entity.NotificationTemplate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, template)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockNotificationTemplateRepositoryUseCaseMockRecorder) Create(ctx, template interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNotificationTemplateRepositoryUseCase)(nil).Create), ctx, template)
+}
+
+// Delete mocks base method.
+func (m *MockNotificationTemplateRepositoryUseCase) Delete(ctx context.Context, id uuid.UUID, deletedBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, deletedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockNotificationTemplateRepositoryUseCaseMockRecorder) Delete(ctx, id, deletedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNotificationTemplateRepositoryUseCase)(nil).Delete), ctx, id, deletedBy)
+}
+
+// FindAll mocks base method.
+func (m *MockNotificationTemplateRepositoryUseCase) FindAll(ctx context.Context, q *query.Query) ([]*entity.NotificationTemplate, *query.Page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, q)
+	ret0, _ := ret[0].([]*entity.NotificationTemplate)
+	ret1, _ := ret[1].(*query.Page)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockNotificationTemplateRepositoryUseCaseMockRecorder) FindAll(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockNotificationTemplateRepositoryUseCase)(nil).FindAll), ctx, q)
+}
+
+// FindByID mocks base method.
+func (m *MockNotificationTemplateRepositoryUseCase) FindByID(ctx context.Context, id uuid.UUID) (*entity.NotificationTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*entity.NotificationTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockNotificationTemplateRepositoryUseCaseMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockNotificationTemplateRepositoryUseCase)(nil).FindByID), ctx, id)
+}
+
+// FindByKey mocks base method.
+func (m *MockNotificationTemplateRepositoryUseCase) FindByKey(ctx context.Context, key string) (*entity.NotificationTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByKey", ctx, key)
+	ret0, _ := ret[0].(*entity.NotificationTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByKey indicates an expected call of FindByKey.
+func (mr *MockNotificationTemplateRepositoryUseCaseMockRecorder) FindByKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByKey", reflect.TypeOf((*MockNotificationTemplateRepositoryUseCase)(nil).FindByKey), ctx, key)
+}
+
+// Update mocks base method.
+func (m *MockNotificationTemplateRepositoryUseCase) Update(ctx context.Context, template *entity.NotificationTemplate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, template)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockNotificationTemplateRepositoryUseCaseMockRecorder) Update(ctx, template interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNotificationTemplateRepositoryUseCase)(nil).Update), ctx, template)
+}
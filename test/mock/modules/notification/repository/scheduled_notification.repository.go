@@ -0,0 +1,173 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./modules/notification/v1/repository/scheduled_notification.repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	query "gin-starter/common/query"
+	entity "gin-starter/entity"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockScheduledNotificationRepositoryUseCase is a mock of ScheduledNotificationRepositoryUseCase interface.
+type MockScheduledNotificationRepositoryUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduledNotificationRepositoryUseCaseMockRecorder
+}
+
+// MockScheduledNotificationRepositoryUseCaseMockRecorder is the mock recorder for MockScheduledNotificationRepositoryUseCase.
+type MockScheduledNotificationRepositoryUseCaseMockRecorder struct {
+	mock *MockScheduledNotificationRepositoryUseCase
+}
+
+// NewMockScheduledNotificationRepositoryUseCase creates a new mock instance.
+func NewMockScheduledNotificationRepositoryUseCase(ctrl *gomock.Controller) *MockScheduledNotificationRepositoryUseCase {
+	mock := &MockScheduledNotificationRepositoryUseCase{ctrl: ctrl}
+	mock.recorder = &MockScheduledNotificationRepositoryUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduledNotificationRepositoryUseCase) EXPECT() *MockScheduledNotificationRepositoryUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockScheduledNotificationRepositoryUseCase) Cancel(ctx context.Context, id uuid.UUID, cancelledBy string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id, cancelledBy)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockScheduledNotificationRepositoryUseCaseMockRecorder) Cancel(ctx, id, cancelledBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockScheduledNotificationRepositoryUseCase)(nil).Cancel), ctx, id, cancelledBy)
+}
+
+// ClaimRun mocks base method.
+func (m *MockScheduledNotificationRepositoryUseCase) ClaimRun(ctx context.Context, scheduled *entity.ScheduledNotification, dueAt time.Time, run *entity.ScheduledNotificationRun) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimRun", ctx, scheduled, dueAt, run)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimRun indicates an expected call of ClaimRun.
+func (mr *MockScheduledNotificationRepositoryUseCaseMockRecorder) ClaimRun(ctx, scheduled, dueAt, run interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimRun", reflect.TypeOf((*MockScheduledNotificationRepositoryUseCase)(nil).ClaimRun), ctx, scheduled, dueAt, run)
+}
+
+// Create mocks base method.
+func (m *MockScheduledNotificationRepositoryUseCase) Create(ctx context.Context, scheduled *entity.ScheduledNotification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, scheduled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockScheduledNotificationRepositoryUseCaseMockRecorder) Create(ctx, scheduled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScheduledNotificationRepositoryUseCase)(nil).Create), ctx, scheduled)
+}
+
+// FindAll mocks base method.
+func (m *MockScheduledNotificationRepositoryUseCase) FindAll(ctx context.Context, q *query.Query) ([]*entity.ScheduledNotification, *query.Page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, q)
+	ret0, _ := ret[0].([]*entity.ScheduledNotification)
+	ret1, _ := ret[1].(*query.Page)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockScheduledNotificationRepositoryUseCaseMockRecorder) FindAll(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockScheduledNotificationRepositoryUseCase)(nil).FindAll), ctx, q)
+}
+
+// FindByID mocks base method.
+func (m *MockScheduledNotificationRepositoryUseCase) FindByID(ctx context.Context, id uuid.UUID) (*entity.ScheduledNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*entity.ScheduledNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockScheduledNotificationRepositoryUseCaseMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockScheduledNotificationRepositoryUseCase)(nil).FindByID), ctx, id)
+}
+
+// FindDue mocks base method.
+func (m *MockScheduledNotificationRepositoryUseCase) FindDue(ctx context.Context, now time.Time, limit int) ([]*entity.ScheduledNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDue", ctx, now, limit)
+	ret0, _ := ret[0].([]*entity.ScheduledNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDue indicates an expected call of FindDue.
+func (mr *MockScheduledNotificationRepositoryUseCaseMockRecorder) FindDue(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDue", reflect.TypeOf((*MockScheduledNotificationRepositoryUseCase)(nil).FindDue), ctx, now, limit)
+}
+
+// FindRuns mocks base method.
+func (m *MockScheduledNotificationRepositoryUseCase) FindRuns(ctx context.Context, id uuid.UUID, q *query.Query) ([]*entity.ScheduledNotificationRun, *query.Page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRuns", ctx, id, q)
+	ret0, _ := ret[0].([]*entity.ScheduledNotificationRun)
+	ret1, _ := ret[1].(*query.Page)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindRuns indicates an expected call of FindRuns.
+func (mr *MockScheduledNotificationRepositoryUseCaseMockRecorder) FindRuns(ctx, id, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRuns", reflect.TypeOf((*MockScheduledNotificationRepositoryUseCase)(nil).FindRuns), ctx, id, q)
+}
+
+// FinishRun mocks base method.
+func (m *MockScheduledNotificationRepositoryUseCase) FinishRun(ctx context.Context, run *entity.ScheduledNotificationRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishRun", ctx, run)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishRun indicates an expected call of FinishRun.
+func (mr *MockScheduledNotificationRepositoryUseCaseMockRecorder) FinishRun(ctx, run interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishRun", reflect.TypeOf((*MockScheduledNotificationRepositoryUseCase)(nil).FinishRun), ctx, run)
+}
+
+// Update mocks base method.
+func (m *MockScheduledNotificationRepositoryUseCase) Update(ctx context.Context, scheduled *entity.ScheduledNotification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, scheduled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockScheduledNotificationRepositoryUseCaseMockRecorder) Update(ctx, scheduled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockScheduledNotificationRepositoryUseCase)(nil).Update), ctx, scheduled)
+}
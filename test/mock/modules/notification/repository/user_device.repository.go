@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./modules/notification/v1/repository/user_device.repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	entity "gin-starter/entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockUserDeviceRepositoryUseCase is a mock of UserDeviceRepositoryUseCase interface.
+type MockUserDeviceRepositoryUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserDeviceRepositoryUseCaseMockRecorder
+}
+
+// MockUserDeviceRepositoryUseCaseMockRecorder is the mock recorder for MockUserDeviceRepositoryUseCase.
+type MockUserDeviceRepositoryUseCaseMockRecorder struct {
+	mock *MockUserDeviceRepositoryUseCase
+}
+
+// NewMockUserDeviceRepositoryUseCase creates a new mock instance.
+func NewMockUserDeviceRepositoryUseCase(ctrl *gomock.Controller) *MockUserDeviceRepositoryUseCase {
+	mock := &MockUserDeviceRepositoryUseCase{ctrl: ctrl}
+	mock.recorder = &MockUserDeviceRepositoryUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserDeviceRepositoryUseCase) EXPECT() *MockUserDeviceRepositoryUseCaseMockRecorder {
+	return m.recorder
+}
+
+// DeleteByToken mocks base method.
+func (m *MockUserDeviceRepositoryUseCase) DeleteByToken(ctx context.Context, userID uuid.UUID, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByToken", ctx, userID, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByToken indicates an expected call of DeleteByToken.
+func (mr *MockUserDeviceRepositoryUseCaseMockRecorder) DeleteByToken(ctx, userID, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByToken", reflect.TypeOf((*MockUserDeviceRepositoryUseCase)(nil).DeleteByToken), ctx, userID, token)
+}
+
+// DeleteByTokens mocks base method.
+func (m *MockUserDeviceRepositoryUseCase) DeleteByTokens(ctx context.Context, provider string, tokens []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByTokens", ctx, provider, tokens)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByTokens indicates an expected call of DeleteByTokens.
+func (mr *MockUserDeviceRepositoryUseCaseMockRecorder) DeleteByTokens(ctx, provider, tokens interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByTokens", reflect.TypeOf((*MockUserDeviceRepositoryUseCase)(nil).DeleteByTokens), ctx, provider, tokens)
+}
+
+// DeleteByUserID mocks base method.
+func (m *MockUserDeviceRepositoryUseCase) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUserID", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUserID indicates an expected call of DeleteByUserID.
+func (mr *MockUserDeviceRepositoryUseCaseMockRecorder) DeleteByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUserID", reflect.TypeOf((*MockUserDeviceRepositoryUseCase)(nil).DeleteByUserID), ctx, userID)
+}
+
+// FindAfter mocks base method.
+func (m *MockUserDeviceRepositoryUseCase) FindAfter(ctx context.Context, provider string, platforms []string, afterID uuid.UUID, limit int) ([]*entity.UserDevice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAfter", ctx, provider, platforms, afterID, limit)
+	ret0, _ := ret[0].([]*entity.UserDevice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAfter indicates an expected call of FindAfter.
+func (mr *MockUserDeviceRepositoryUseCaseMockRecorder) FindAfter(ctx, provider, platforms, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAfter", reflect.TypeOf((*MockUserDeviceRepositoryUseCase)(nil).FindAfter), ctx, provider, platforms, afterID, limit)
+}
+
+// FindByUserID mocks base method.
+func (m *MockUserDeviceRepositoryUseCase) FindByUserID(ctx context.Context, userID uuid.UUID, provider string, platforms []string) ([]*entity.UserDevice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", ctx, userID, provider, platforms)
+	ret0, _ := ret[0].([]*entity.UserDevice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockUserDeviceRepositoryUseCaseMockRecorder) FindByUserID(ctx, userID, provider, platforms interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockUserDeviceRepositoryUseCase)(nil).FindByUserID), ctx, userID, provider, platforms)
+}
+
+// Save mocks base method.
+func (m *MockUserDeviceRepositoryUseCase) Save(ctx context.Context, device *entity.UserDevice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, device)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockUserDeviceRepositoryUseCaseMockRecorder) Save(ctx, device interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockUserDeviceRepositoryUseCase)(nil).Save), ctx, device)
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ./modules/notification/service/creator.service.go
+// Source: ./modules/notification/v1/service/creator.service.go
 
 // Package mock_service is a generated GoMock package.
 package mock_service
@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
 )
 
 // MockNotificationCreatorUseCase is a mock of NotificationCreatorUseCase interface.
@@ -35,15 +36,39 @@ func (m *MockNotificationCreatorUseCase) EXPECT() *MockNotificationCreatorUseCas
 }
 
 // InsertNotification mocks base method.
-func (m *MockNotificationCreatorUseCase) InsertNotification(ctx context.Context, userID, title, message, notifType, extra string, isRead bool) error {
+func (m *MockNotificationCreatorUseCase) InsertNotification(ctx context.Context, userID, title, message, notifType, extra string, isRead bool, platforms ...string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertNotification", ctx, userID, title, message, notifType, extra, isRead)
+	varargs := []interface{}{ctx, userID, title, message, notifType, extra, isRead}
+	for _, a := range platforms {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InsertNotification", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // InsertNotification indicates an expected call of InsertNotification.
-func (mr *MockNotificationCreatorUseCaseMockRecorder) InsertNotification(ctx, userID, title, message, notifType, extra, isRead interface{}) *gomock.Call {
+func (mr *MockNotificationCreatorUseCaseMockRecorder) InsertNotification(ctx, userID, title, message, notifType, extra, isRead interface{}, platforms ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertNotification", reflect.TypeOf((*MockNotificationCreatorUseCase)(nil).InsertNotification), ctx, userID, title, message, notifType, extra, isRead)
+	varargs := append([]interface{}{ctx, userID, title, message, notifType, extra, isRead}, platforms...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertNotification", reflect.TypeOf((*MockNotificationCreatorUseCase)(nil).InsertNotification), varargs...)
+}
+
+// SendTemplate mocks base method.
+func (m *MockNotificationCreatorUseCase) SendTemplate(ctx context.Context, userID uuid.UUID, key string, data map[string]string, platforms ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, userID, key, data}
+	for _, a := range platforms {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SendTemplate", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendTemplate indicates an expected call of SendTemplate.
+func (mr *MockNotificationCreatorUseCaseMockRecorder) SendTemplate(ctx, userID, key, data interface{}, platforms ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, userID, key, data}, platforms...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTemplate", reflect.TypeOf((*MockNotificationCreatorUseCase)(nil).SendTemplate), varargs...)
 }
@@ -0,0 +1,304 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./modules/privacy/v1/repository/privacy.repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	entity "gin-starter/entity"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockPrivacyRepositoryUseCase is a mock of PrivacyRepositoryUseCase interface.
+type MockPrivacyRepositoryUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockPrivacyRepositoryUseCaseMockRecorder
+}
+
+// MockPrivacyRepositoryUseCaseMockRecorder is the mock recorder for MockPrivacyRepositoryUseCase.
+type MockPrivacyRepositoryUseCaseMockRecorder struct {
+	mock *MockPrivacyRepositoryUseCase
+}
+
+// NewMockPrivacyRepositoryUseCase creates a new mock instance.
+func NewMockPrivacyRepositoryUseCase(ctrl *gomock.Controller) *MockPrivacyRepositoryUseCase {
+	mock := &MockPrivacyRepositoryUseCase{ctrl: ctrl}
+	mock.recorder = &MockPrivacyRepositoryUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPrivacyRepositoryUseCase) EXPECT() *MockPrivacyRepositoryUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Anonymise mocks base method.
+func (m *MockPrivacyRepositoryUseCase) Anonymise(ctx context.Context, user, anonymised *entity.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Anonymise", ctx, user, anonymised)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Anonymise indicates an expected call of Anonymise.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) Anonymise(ctx, user, anonymised interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Anonymise", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).Anonymise), ctx, user, anonymised)
+}
+
+// CancelRequest mocks base method.
+func (m *MockPrivacyRepositoryUseCase) CancelRequest(ctx context.Context, id uuid.UUID, cancelledBy string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelRequest", ctx, id, cancelledBy)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelRequest indicates an expected call of CancelRequest.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) CancelRequest(ctx, id, cancelledBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelRequest", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).CancelRequest), ctx, id, cancelledBy)
+}
+
+// ClaimRequest mocks base method.
+func (m *MockPrivacyRepositoryUseCase) ClaimRequest(ctx context.Context, id uuid.UUID, staleBefore time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimRequest", ctx, id, staleBefore)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimRequest indicates an expected call of ClaimRequest.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) ClaimRequest(ctx, id, staleBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimRequest", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).ClaimRequest), ctx, id, staleBefore)
+}
+
+// CreateRequest mocks base method.
+func (m *MockPrivacyRepositoryUseCase) CreateRequest(ctx context.Context, request *entity.DataRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRequest", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRequest indicates an expected call of CreateRequest.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) CreateRequest(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRequest", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).CreateRequest), ctx, request)
+}
+
+// FindDueRequests mocks base method.
+func (m *MockPrivacyRepositoryUseCase) FindDueRequests(ctx context.Context, now, staleBefore time.Time, limit int) ([]*entity.DataRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDueRequests", ctx, now, staleBefore, limit)
+	ret0, _ := ret[0].([]*entity.DataRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDueRequests indicates an expected call of FindDueRequests.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) FindDueRequests(ctx, now, staleBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDueRequests", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).FindDueRequests), ctx, now, staleBefore, limit)
+}
+
+// FindExpiredExports mocks base method.
+func (m *MockPrivacyRepositoryUseCase) FindExpiredExports(ctx context.Context, now time.Time) ([]*entity.DataRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExpiredExports", ctx, now)
+	ret0, _ := ret[0].([]*entity.DataRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExpiredExports indicates an expected call of FindExpiredExports.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) FindExpiredExports(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpiredExports", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).FindExpiredExports), ctx, now)
+}
+
+// FindExportsWithFile mocks base method.
+func (m *MockPrivacyRepositoryUseCase) FindExportsWithFile(ctx context.Context, userID uuid.UUID) ([]*entity.DataRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExportsWithFile", ctx, userID)
+	ret0, _ := ret[0].([]*entity.DataRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExportsWithFile indicates an expected call of FindExportsWithFile.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) FindExportsWithFile(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExportsWithFile", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).FindExportsWithFile), ctx, userID)
+}
+
+// FindOpenRequest mocks base method.
+func (m *MockPrivacyRepositoryUseCase) FindOpenRequest(ctx context.Context, userID uuid.UUID, requestType string) (*entity.DataRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOpenRequest", ctx, userID, requestType)
+	ret0, _ := ret[0].(*entity.DataRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOpenRequest indicates an expected call of FindOpenRequest.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) FindOpenRequest(ctx, userID, requestType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOpenRequest", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).FindOpenRequest), ctx, userID, requestType)
+}
+
+// FindRequestByID mocks base method.
+func (m *MockPrivacyRepositoryUseCase) FindRequestByID(ctx context.Context, id uuid.UUID) (*entity.DataRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRequestByID", ctx, id)
+	ret0, _ := ret[0].(*entity.DataRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRequestByID indicates an expected call of FindRequestByID.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) FindRequestByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRequestByID", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).FindRequestByID), ctx, id)
+}
+
+// GetActivitiesByUserID mocks base method.
+func (m *MockPrivacyRepositoryUseCase) GetActivitiesByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Activities, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActivitiesByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*entity.Activities)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActivitiesByUserID indicates an expected call of GetActivitiesByUserID.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) GetActivitiesByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActivitiesByUserID", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).GetActivitiesByUserID), ctx, userID)
+}
+
+// GetDevicesByUserID mocks base method.
+func (m *MockPrivacyRepositoryUseCase) GetDevicesByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserDevice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDevicesByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*entity.UserDevice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDevicesByUserID indicates an expected call of GetDevicesByUserID.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) GetDevicesByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDevicesByUserID", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).GetDevicesByUserID), ctx, userID)
+}
+
+// GetEmailsSentTo mocks base method.
+func (m *MockPrivacyRepositoryUseCase) GetEmailsSentTo(ctx context.Context, email string) ([]*entity.EmailSent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmailsSentTo", ctx, email)
+	ret0, _ := ret[0].([]*entity.EmailSent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmailsSentTo indicates an expected call of GetEmailsSentTo.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) GetEmailsSentTo(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmailsSentTo", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).GetEmailsSentTo), ctx, email)
+}
+
+// GetNotificationsByUserID mocks base method.
+func (m *MockPrivacyRepositoryUseCase) GetNotificationsByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*entity.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationsByUserID indicates an expected call of GetNotificationsByUserID.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) GetNotificationsByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationsByUserID", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).GetNotificationsByUserID), ctx, userID)
+}
+
+// GetPreferencesByUserID mocks base method.
+func (m *MockPrivacyRepositoryUseCase) GetPreferencesByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreferencesByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*entity.UserPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreferencesByUserID indicates an expected call of GetPreferencesByUserID.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) GetPreferencesByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreferencesByUserID", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).GetPreferencesByUserID), ctx, userID)
+}
+
+// GetUserByID mocks base method.
+func (m *MockPrivacyRepositoryUseCase) GetUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, id)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) GetUserByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).GetUserByID), ctx, id)
+}
+
+// IsPhotoShared mocks base method.
+func (m *MockPrivacyRepositoryUseCase) IsPhotoShared(ctx context.Context, photo string, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsPhotoShared", ctx, photo, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsPhotoShared indicates an expected call of IsPhotoShared.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) IsPhotoShared(ctx, photo, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPhotoShared", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).IsPhotoShared), ctx, photo, userID)
+}
+
+// KeepSuperAdmin mocks base method.
+func (m *MockPrivacyRepositoryUseCase) KeepSuperAdmin(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeepSuperAdmin", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// KeepSuperAdmin indicates an expected call of KeepSuperAdmin.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) KeepSuperAdmin(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeepSuperAdmin", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).KeepSuperAdmin), ctx, userID)
+}
+
+// UpdateRequest mocks base method.
+func (m *MockPrivacyRepositoryUseCase) UpdateRequest(ctx context.Context, request *entity.DataRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRequest", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRequest indicates an expected call of UpdateRequest.
+func (mr *MockPrivacyRepositoryUseCaseMockRecorder) UpdateRequest(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRequest", reflect.TypeOf((*MockPrivacyRepositoryUseCase)(nil).UpdateRequest), ctx, request)
+}
@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./modules/user/v1/repository/admin_invitation.repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	entity "gin-starter/entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockAdminInvitationRepositoryUseCase is a mock of AdminInvitationRepositoryUseCase interface.
+type MockAdminInvitationRepositoryUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminInvitationRepositoryUseCaseMockRecorder
+}
+
+// MockAdminInvitationRepositoryUseCaseMockRecorder is the mock recorder for MockAdminInvitationRepositoryUseCase.
+type MockAdminInvitationRepositoryUseCaseMockRecorder struct {
+	mock *MockAdminInvitationRepositoryUseCase
+}
+
+// NewMockAdminInvitationRepositoryUseCase creates a new mock instance.
+func NewMockAdminInvitationRepositoryUseCase(ctrl *gomock.Controller) *MockAdminInvitationRepositoryUseCase {
+	mock := &MockAdminInvitationRepositoryUseCase{ctrl: ctrl}
+	mock.recorder = &MockAdminInvitationRepositoryUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminInvitationRepositoryUseCase) EXPECT() *MockAdminInvitationRepositoryUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Accept mocks base method.
+func (m *MockAdminInvitationRepositoryUseCase) Accept(ctx context.Context, invitation *entity.AdminInvitation, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accept", ctx, invitation, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Accept indicates an expected call of Accept.
+func (mr *MockAdminInvitationRepositoryUseCaseMockRecorder) Accept(ctx, invitation, passwordHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accept", reflect.TypeOf((*MockAdminInvitationRepositoryUseCase)(nil).Accept), ctx, invitation, passwordHash)
+}
+
+// Create mocks base method.
+func (m *MockAdminInvitationRepositoryUseCase) Create(ctx context.Context, user *entity.User, userRole *entity.UserRole, invitation *entity.AdminInvitation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, user, userRole, invitation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAdminInvitationRepositoryUseCaseMockRecorder) Create(ctx, user, userRole, invitation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAdminInvitationRepositoryUseCase)(nil).Create), ctx, user, userRole, invitation)
+}
+
+// FindByID mocks base method.
+func (m *MockAdminInvitationRepositoryUseCase) FindByID(ctx context.Context, id uuid.UUID) (*entity.AdminInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*entity.AdminInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockAdminInvitationRepositoryUseCaseMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockAdminInvitationRepositoryUseCase)(nil).FindByID), ctx, id)
+}
+
+// FindPending mocks base method.
+func (m *MockAdminInvitationRepositoryUseCase) FindPending(ctx context.Context, limit, offset int) ([]*entity.AdminInvitation, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPending", ctx, limit, offset)
+	ret0, _ := ret[0].([]*entity.AdminInvitation)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindPending indicates an expected call of FindPending.
+func (mr *MockAdminInvitationRepositoryUseCaseMockRecorder) FindPending(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPending", reflect.TypeOf((*MockAdminInvitationRepositoryUseCase)(nil).FindPending), ctx, limit, offset)
+}
+
+// Revoke mocks base method.
+func (m *MockAdminInvitationRepositoryUseCase) Revoke(ctx context.Context, invitation *entity.AdminInvitation, revokedBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, invitation, revokedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockAdminInvitationRepositoryUseCaseMockRecorder) Revoke(ctx, invitation, revokedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockAdminInvitationRepositoryUseCase)(nil).Revoke), ctx, invitation, revokedBy)
+}
+
+// UpdateSent mocks base method.
+func (m *MockAdminInvitationRepositoryUseCase) UpdateSent(ctx context.Context, invitation *entity.AdminInvitation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSent", ctx, invitation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSent indicates an expected call of UpdateSent.
+func (mr *MockAdminInvitationRepositoryUseCaseMockRecorder) UpdateSent(ctx, invitation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSent", reflect.TypeOf((*MockAdminInvitationRepositoryUseCase)(nil).UpdateSent), ctx, invitation)
+}
@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./modules/user/v1/repository/email_change.repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	entity "gin-starter/entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockEmailChangeRepositoryUseCase is a mock of EmailChangeRepositoryUseCase interface.
+type MockEmailChangeRepositoryUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailChangeRepositoryUseCaseMockRecorder
+}
+
+// MockEmailChangeRepositoryUseCaseMockRecorder is the mock recorder for MockEmailChangeRepositoryUseCase.
+type MockEmailChangeRepositoryUseCaseMockRecorder struct {
+	mock *MockEmailChangeRepositoryUseCase
+}
+
+// NewMockEmailChangeRepositoryUseCase creates a new mock instance.
+func NewMockEmailChangeRepositoryUseCase(ctrl *gomock.Controller) *MockEmailChangeRepositoryUseCase {
+	mock := &MockEmailChangeRepositoryUseCase{ctrl: ctrl}
+	mock.recorder = &MockEmailChangeRepositoryUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailChangeRepositoryUseCase) EXPECT() *MockEmailChangeRepositoryUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockEmailChangeRepositoryUseCase) Cancel(ctx context.Context, change *entity.EmailChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockEmailChangeRepositoryUseCaseMockRecorder) Cancel(ctx, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockEmailChangeRepositoryUseCase)(nil).Cancel), ctx, change)
+}
+
+// Confirm mocks base method.
+func (m *MockEmailChangeRepositoryUseCase) Confirm(ctx context.Context, change *entity.EmailChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", ctx, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockEmailChangeRepositoryUseCaseMockRecorder) Confirm(ctx, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockEmailChangeRepositoryUseCase)(nil).Confirm), ctx, change)
+}
+
+// Create mocks base method.
+func (m *MockEmailChangeRepositoryUseCase) Create(ctx context.Context, change *entity.EmailChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockEmailChangeRepositoryUseCaseMockRecorder) Create(ctx, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEmailChangeRepositoryUseCase)(nil).Create), ctx, change)
+}
+
+// FindByCancelTokenHash mocks base method.
+func (m *MockEmailChangeRepositoryUseCase) FindByCancelTokenHash(ctx context.Context, hash string) (*entity.EmailChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCancelTokenHash", ctx, hash)
+	ret0, _ := ret[0].(*entity.EmailChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCancelTokenHash indicates an expected call of FindByCancelTokenHash.
+func (mr *MockEmailChangeRepositoryUseCaseMockRecorder) FindByCancelTokenHash(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCancelTokenHash", reflect.TypeOf((*MockEmailChangeRepositoryUseCase)(nil).FindByCancelTokenHash), ctx, hash)
+}
+
+// FindByConfirmTokenHash mocks base method.
+func (m *MockEmailChangeRepositoryUseCase) FindByConfirmTokenHash(ctx context.Context, hash string) (*entity.EmailChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByConfirmTokenHash", ctx, hash)
+	ret0, _ := ret[0].(*entity.EmailChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByConfirmTokenHash indicates an expected call of FindByConfirmTokenHash.
+func (mr *MockEmailChangeRepositoryUseCaseMockRecorder) FindByConfirmTokenHash(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByConfirmTokenHash", reflect.TypeOf((*MockEmailChangeRepositoryUseCase)(nil).FindByConfirmTokenHash), ctx, hash)
+}
@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./modules/user/v1/repository/permission.repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	entity "gin-starter/entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockPermissionRepositoryUseCase is a mock of PermissionRepositoryUseCase interface.
+type MockPermissionRepositoryUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockPermissionRepositoryUseCaseMockRecorder
+}
+
+// MockPermissionRepositoryUseCaseMockRecorder is the mock recorder for MockPermissionRepositoryUseCase.
+type MockPermissionRepositoryUseCaseMockRecorder struct {
+	mock *MockPermissionRepositoryUseCase
+}
+
+// NewMockPermissionRepositoryUseCase creates a new mock instance.
+func NewMockPermissionRepositoryUseCase(ctrl *gomock.Controller) *MockPermissionRepositoryUseCase {
+	mock := &MockPermissionRepositoryUseCase{ctrl: ctrl}
+	mock.recorder = &MockPermissionRepositoryUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPermissionRepositoryUseCase) EXPECT() *MockPermissionRepositoryUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPermissionRepositoryUseCase) Create(ctx context.Context, permission *entity.Permission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, permission)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPermissionRepositoryUseCaseMockRecorder) Create(ctx, permission interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPermissionRepositoryUseCase)(nil).Create), ctx, permission)
+}
+
+// FindAll mocks base method.
+func (m *MockPermissionRepositoryUseCase) FindAll(ctx context.Context) ([]*entity.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx)
+	ret0, _ := ret[0].([]*entity.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockPermissionRepositoryUseCaseMockRecorder) FindAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockPermissionRepositoryUseCase)(nil).FindAll), ctx)
+}
+
+// FindByID mocks base method.
+func (m *MockPermissionRepositoryUseCase) FindByID(ctx context.Context, id uuid.UUID) (*entity.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockPermissionRepositoryUseCaseMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockPermissionRepositoryUseCase)(nil).FindByID), ctx, id)
+}
+
+// FindByName mocks base method.
+func (m *MockPermissionRepositoryUseCase) FindByName(ctx context.Context, name string) (*entity.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByName", ctx, name)
+	ret0, _ := ret[0].(*entity.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByName indicates an expected call of FindByName.
+func (mr *MockPermissionRepositoryUseCaseMockRecorder) FindByName(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByName", reflect.TypeOf((*MockPermissionRepositoryUseCase)(nil).FindByName), ctx, name)
+}
+
+// Sync mocks base method.
+func (m *MockPermissionRepositoryUseCase) Sync(ctx context.Context, declared []*entity.Permission) ([]*entity.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync", ctx, declared)
+	ret0, _ := ret[0].([]*entity.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockPermissionRepositoryUseCaseMockRecorder) Sync(ctx, declared interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockPermissionRepositoryUseCase)(nil).Sync), ctx, declared)
+}
+
+// Update mocks base method.
+func (m *MockPermissionRepositoryUseCase) Update(ctx context.Context, permission *entity.Permission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, permission)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPermissionRepositoryUseCaseMockRecorder) Update(ctx, permission interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPermissionRepositoryUseCase)(nil).Update), ctx, permission)
+}
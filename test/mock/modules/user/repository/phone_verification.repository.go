@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./modules/user/v1/repository/phone_verification.repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	entity "gin-starter/entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockPhoneVerificationRepositoryUseCase is a mock of PhoneVerificationRepositoryUseCase interface.
+type MockPhoneVerificationRepositoryUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockPhoneVerificationRepositoryUseCaseMockRecorder
+}
+
+// MockPhoneVerificationRepositoryUseCaseMockRecorder is the mock recorder for MockPhoneVerificationRepositoryUseCase.
+type MockPhoneVerificationRepositoryUseCaseMockRecorder struct {
+	mock *MockPhoneVerificationRepositoryUseCase
+}
+
+// NewMockPhoneVerificationRepositoryUseCase creates a new mock instance.
+func NewMockPhoneVerificationRepositoryUseCase(ctrl *gomock.Controller) *MockPhoneVerificationRepositoryUseCase {
+	mock := &MockPhoneVerificationRepositoryUseCase{ctrl: ctrl}
+	mock.recorder = &MockPhoneVerificationRepositoryUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPhoneVerificationRepositoryUseCase) EXPECT() *MockPhoneVerificationRepositoryUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPhoneVerificationRepositoryUseCase) Create(ctx context.Context, verification *entity.PhoneVerification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, verification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPhoneVerificationRepositoryUseCaseMockRecorder) Create(ctx, verification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPhoneVerificationRepositoryUseCase)(nil).Create), ctx, verification)
+}
+
+// FindLatest mocks base method.
+func (m *MockPhoneVerificationRepositoryUseCase) FindLatest(ctx context.Context, userID uuid.UUID) (*entity.PhoneVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLatest", ctx, userID)
+	ret0, _ := ret[0].(*entity.PhoneVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLatest indicates an expected call of FindLatest.
+func (mr *MockPhoneVerificationRepositoryUseCaseMockRecorder) FindLatest(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLatest", reflect.TypeOf((*MockPhoneVerificationRepositoryUseCase)(nil).FindLatest), ctx, userID)
+}
+
+// IncrementAttempts mocks base method.
+func (m *MockPhoneVerificationRepositoryUseCase) IncrementAttempts(ctx context.Context, verification *entity.PhoneVerification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementAttempts", ctx, verification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementAttempts indicates an expected call of IncrementAttempts.
+func (mr *MockPhoneVerificationRepositoryUseCaseMockRecorder) IncrementAttempts(ctx, verification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementAttempts", reflect.TypeOf((*MockPhoneVerificationRepositoryUseCase)(nil).IncrementAttempts), ctx, verification)
+}
+
+// Verify mocks base method.
+func (m *MockPhoneVerificationRepositoryUseCase) Verify(ctx context.Context, verification *entity.PhoneVerification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, verification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockPhoneVerificationRepositoryUseCaseMockRecorder) Verify(ctx, verification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockPhoneVerificationRepositoryUseCase)(nil).Verify), ctx, verification)
+}
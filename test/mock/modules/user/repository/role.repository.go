@@ -0,0 +1,200 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./modules/user/v1/repository/role.repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	query "gin-starter/common/query"
+	entity "gin-starter/entity"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockRoleRepositoryUseCase is a mock of RoleRepositoryUseCase interface.
+type MockRoleRepositoryUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleRepositoryUseCaseMockRecorder
+}
+
+// MockRoleRepositoryUseCaseMockRecorder is the mock recorder for MockRoleRepositoryUseCase.
+type MockRoleRepositoryUseCaseMockRecorder struct {
+	mock *MockRoleRepositoryUseCase
+}
+
+// NewMockRoleRepositoryUseCase creates a new mock instance.
+func NewMockRoleRepositoryUseCase(ctrl *gomock.Controller) *MockRoleRepositoryUseCase {
+	mock := &MockRoleRepositoryUseCase{ctrl: ctrl}
+	mock.recorder = &MockRoleRepositoryUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleRepositoryUseCase) EXPECT() *MockRoleRepositoryUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRoleRepositoryUseCase) Create(ctx context.Context, role *entity.Role, permissionIDs []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, role, permissionIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRoleRepositoryUseCaseMockRecorder) Create(ctx, role, permissionIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRoleRepositoryUseCase)(nil).Create), ctx, role, permissionIDs)
+}
+
+// Delete mocks base method.
+func (m *MockRoleRepositoryUseCase) Delete(ctx context.Context, id uuid.UUID, deletedBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, deletedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRoleRepositoryUseCaseMockRecorder) Delete(ctx, id, deletedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRoleRepositoryUseCase)(nil).Delete), ctx, id, deletedBy)
+}
+
+// FindAll mocks base method.
+func (m *MockRoleRepositoryUseCase) FindAll(ctx context.Context, q *query.Query) ([]*entity.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, q)
+	ret0, _ := ret[0].([]*entity.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockRoleRepositoryUseCaseMockRecorder) FindAll(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockRoleRepositoryUseCase)(nil).FindAll), ctx, q)
+}
+
+// FindByID mocks base method.
+func (m *MockRoleRepositoryUseCase) FindByID(ctx context.Context, id uuid.UUID) (*entity.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockRoleRepositoryUseCaseMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockRoleRepositoryUseCase)(nil).FindByID), ctx, id)
+}
+
+// FindByName mocks base method.
+func (m *MockRoleRepositoryUseCase) FindByName(ctx context.Context, slug string) (*entity.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByName", ctx, slug)
+	ret0, _ := ret[0].(*entity.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByName indicates an expected call of FindByName.
+func (mr *MockRoleRepositoryUseCaseMockRecorder) FindByName(ctx, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByName", reflect.TypeOf((*MockRoleRepositoryUseCase)(nil).FindByName), ctx, slug)
+}
+
+// FindDeleted mocks base method.
+func (m *MockRoleRepositoryUseCase) FindDeleted(ctx context.Context, limit, offset int) ([]*entity.Role, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeleted", ctx, limit, offset)
+	ret0, _ := ret[0].([]*entity.Role)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindDeleted indicates an expected call of FindDeleted.
+func (mr *MockRoleRepositoryUseCaseMockRecorder) FindDeleted(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeleted", reflect.TypeOf((*MockRoleRepositoryUseCase)(nil).FindDeleted), ctx, limit, offset)
+}
+
+// FindDeletedByID mocks base method.
+func (m *MockRoleRepositoryUseCase) FindDeletedByID(ctx context.Context, id uuid.UUID) (*entity.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeletedByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeletedByID indicates an expected call of FindDeletedByID.
+func (mr *MockRoleRepositoryUseCaseMockRecorder) FindDeletedByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeletedByID", reflect.TypeOf((*MockRoleRepositoryUseCase)(nil).FindDeletedByID), ctx, id)
+}
+
+// Purge mocks base method.
+func (m *MockRoleRepositoryUseCase) Purge(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockRoleRepositoryUseCaseMockRecorder) Purge(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockRoleRepositoryUseCase)(nil).Purge), ctx, before)
+}
+
+// Restore mocks base method.
+func (m *MockRoleRepositoryUseCase) Restore(ctx context.Context, role *entity.Role, restoredBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, role, restoredBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockRoleRepositoryUseCaseMockRecorder) Restore(ctx, role, restoredBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRoleRepositoryUseCase)(nil).Restore), ctx, role, restoredBy)
+}
+
+// SyncDefault mocks base method.
+func (m *MockRoleRepositoryUseCase) SyncDefault(ctx context.Context, role *entity.Role, permissionIDs []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncDefault", ctx, role, permissionIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncDefault indicates an expected call of SyncDefault.
+func (mr *MockRoleRepositoryUseCaseMockRecorder) SyncDefault(ctx, role, permissionIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncDefault", reflect.TypeOf((*MockRoleRepositoryUseCase)(nil).SyncDefault), ctx, role, permissionIDs)
+}
+
+// Update mocks base method.
+func (m *MockRoleRepositoryUseCase) Update(ctx context.Context, role *entity.Role, rolePermissions []*entity.RolePermission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, role, rolePermissions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRoleRepositoryUseCaseMockRecorder) Update(ctx, role, rolePermissions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRoleRepositoryUseCase)(nil).Update), ctx, role, rolePermissions)
+}
@@ -1,17 +1,19 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ./modules/user/repository/user.repository.go
+// Source: ./modules/user/v1/repository/user.repository.go
 
 // Package mock_repository is a generated GoMock package.
 package mock_repository
 
 import (
 	context "context"
+	query "gin-starter/common/query"
+	entity "gin-starter/entity"
+	repository "gin-starter/modules/user/v1/repository"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
-
-	entity "gin-starter/entity"
 )
 
 // MockUserRepositoryUseCase is a mock of UserRepositoryUseCase interface.
@@ -51,6 +53,111 @@ func (mr *MockUserRepositoryUseCaseMockRecorder) ChangePassword(ctx, user, newPa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).ChangePassword), ctx, user, newPassword)
 }
 
+// CreateUser mocks base method.
+func (m *MockUserRepositoryUseCase) CreateUser(ctx context.Context, user *entity.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockUserRepositoryUseCaseMockRecorder) CreateUser(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).CreateUser), ctx, user)
+}
+
+// DeleteAdmin mocks base method.
+func (m *MockUserRepositoryUseCase) DeleteAdmin(ctx context.Context, id uuid.UUID, deletedBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAdmin", ctx, id, deletedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAdmin indicates an expected call of DeleteAdmin.
+func (mr *MockUserRepositoryUseCaseMockRecorder) DeleteAdmin(ctx, id, deletedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAdmin", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).DeleteAdmin), ctx, id, deletedBy)
+}
+
+// ExportUsers mocks base method.
+func (m *MockUserRepositoryUseCase) ExportUsers(ctx context.Context, admin bool, q *query.Query, fn func(*entity.UserExport) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportUsers", ctx, admin, q, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportUsers indicates an expected call of ExportUsers.
+func (mr *MockUserRepositoryUseCaseMockRecorder) ExportUsers(ctx, admin, q, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportUsers", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).ExportUsers), ctx, admin, q, fn)
+}
+
+// FindUsers mocks base method.
+func (m *MockUserRepositoryUseCase) FindUsers(ctx context.Context, conditions []*repository.UserCondition, limit, offset int) ([]*entity.User, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUsers", ctx, conditions, limit, offset)
+	ret0, _ := ret[0].([]*entity.User)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindUsers indicates an expected call of FindUsers.
+func (mr *MockUserRepositoryUseCaseMockRecorder) FindUsers(ctx, conditions, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUsers", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).FindUsers), ctx, conditions, limit, offset)
+}
+
+// GetAdminUsers mocks base method.
+func (m *MockUserRepositoryUseCase) GetAdminUsers(ctx context.Context, q *query.Query) ([]*entity.User, *query.Page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdminUsers", ctx, q)
+	ret0, _ := ret[0].([]*entity.User)
+	ret1, _ := ret[1].(*query.Page)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAdminUsers indicates an expected call of GetAdminUsers.
+func (mr *MockUserRepositoryUseCaseMockRecorder) GetAdminUsers(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminUsers", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).GetAdminUsers), ctx, q)
+}
+
+// GetDeletedUserByID mocks base method.
+func (m *MockUserRepositoryUseCase) GetDeletedUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedUserByID", ctx, id)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedUserByID indicates an expected call of GetDeletedUserByID.
+func (mr *MockUserRepositoryUseCaseMockRecorder) GetDeletedUserByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedUserByID", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).GetDeletedUserByID), ctx, id)
+}
+
+// GetDeletedUsers mocks base method.
+func (m *MockUserRepositoryUseCase) GetDeletedUsers(ctx context.Context, limit, offset int) ([]*entity.User, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedUsers", ctx, limit, offset)
+	ret0, _ := ret[0].([]*entity.User)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetDeletedUsers indicates an expected call of GetDeletedUsers.
+func (mr *MockUserRepositoryUseCaseMockRecorder) GetDeletedUsers(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedUsers", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).GetDeletedUsers), ctx, limit, offset)
+}
+
 // GetUserByEmail mocks base method.
 func (m *MockUserRepositoryUseCase) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +203,110 @@ func (mr *MockUserRepositoryUseCaseMockRecorder) GetUserByID(ctx, id interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).GetUserByID), ctx, id)
 }
 
+// GetUsers mocks base method.
+func (m *MockUserRepositoryUseCase) GetUsers(ctx context.Context, q *query.Query) ([]*entity.User, *query.Page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers", ctx, q)
+	ret0, _ := ret[0].([]*entity.User)
+	ret1, _ := ret[1].(*query.Page)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUsers indicates an expected call of GetUsers.
+func (mr *MockUserRepositoryUseCaseMockRecorder) GetUsers(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).GetUsers), ctx, q)
+}
+
+// GetUsersByEmails mocks base method.
+func (m *MockUserRepositoryUseCase) GetUsersByEmails(ctx context.Context, emails []string) ([]*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersByEmails", ctx, emails)
+	ret0, _ := ret[0].([]*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersByEmails indicates an expected call of GetUsersByEmails.
+func (mr *MockUserRepositoryUseCaseMockRecorder) GetUsersByEmails(ctx, emails interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByEmails", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).GetUsersByEmails), ctx, emails)
+}
+
+// IsEmailReserved mocks base method.
+func (m *MockUserRepositoryUseCase) IsEmailReserved(ctx context.Context, email string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEmailReserved", ctx, email)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsEmailReserved indicates an expected call of IsEmailReserved.
+func (mr *MockUserRepositoryUseCaseMockRecorder) IsEmailReserved(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEmailReserved", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).IsEmailReserved), ctx, email)
+}
+
+// IsPhotoInUse mocks base method.
+func (m *MockUserRepositoryUseCase) IsPhotoInUse(ctx context.Context, photo string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsPhotoInUse", ctx, photo)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsPhotoInUse indicates an expected call of IsPhotoInUse.
+func (mr *MockUserRepositoryUseCaseMockRecorder) IsPhotoInUse(ctx, photo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPhotoInUse", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).IsPhotoInUse), ctx, photo)
+}
+
+// Patch mocks base method.
+func (m *MockUserRepositoryUseCase) Patch(ctx context.Context, id uuid.UUID, version int64, columns map[string]interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, id, version, columns)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockUserRepositoryUseCaseMockRecorder) Patch(ctx, id, version, columns interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).Patch), ctx, id, version, columns)
+}
+
+// Purge mocks base method.
+func (m *MockUserRepositoryUseCase) Purge(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockUserRepositoryUseCaseMockRecorder) Purge(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).Purge), ctx, before)
+}
+
+// Restore mocks base method.
+func (m *MockUserRepositoryUseCase) Restore(ctx context.Context, id uuid.UUID, restoredBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id, restoredBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockUserRepositoryUseCaseMockRecorder) Restore(ctx, id, restoredBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).Restore), ctx, id, restoredBy)
+}
+
 // Update mocks base method.
 func (m *MockUserRepositoryUseCase) Update(ctx context.Context, user *entity.User) error {
 	m.ctrl.T.Helper()
@@ -123,3 +334,45 @@ func (mr *MockUserRepositoryUseCaseMockRecorder) UpdateOTP(ctx, user, otp interf
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOTP", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).UpdateOTP), ctx, user, otp)
 }
+
+// UpdateOTPChannel mocks base method.
+func (m *MockUserRepositoryUseCase) UpdateOTPChannel(ctx context.Context, id uuid.UUID, channel string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOTPChannel", ctx, id, channel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOTPChannel indicates an expected call of UpdateOTPChannel.
+func (mr *MockUserRepositoryUseCaseMockRecorder) UpdateOTPChannel(ctx, id, channel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOTPChannel", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).UpdateOTPChannel), ctx, id, channel)
+}
+
+// UpdateUser mocks base method.
+func (m *MockUserRepositoryUseCase) UpdateUser(ctx context.Context, user *entity.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockUserRepositoryUseCaseMockRecorder) UpdateUser(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).UpdateUser), ctx, user)
+}
+
+// UpdateUserStatus mocks base method.
+func (m *MockUserRepositoryUseCase) UpdateUserStatus(ctx context.Context, id uuid.UUID, status string, version int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserStatus", ctx, id, status, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserStatus indicates an expected call of UpdateUserStatus.
+func (mr *MockUserRepositoryUseCaseMockRecorder) UpdateUserStatus(ctx, id, status, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserStatus", reflect.TypeOf((*MockUserRepositoryUseCase)(nil).UpdateUserStatus), ctx, id, status, version)
+}
@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./modules/user/v1/repository/user_import.repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	entity "gin-starter/entity"
+	repository "gin-starter/modules/user/v1/repository"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockUserImportRepositoryUseCase is a mock of UserImportRepositoryUseCase interface.
+type MockUserImportRepositoryUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserImportRepositoryUseCaseMockRecorder
+}
+
+// MockUserImportRepositoryUseCaseMockRecorder is the mock recorder for MockUserImportRepositoryUseCase.
+type MockUserImportRepositoryUseCaseMockRecorder struct {
+	mock *MockUserImportRepositoryUseCase
+}
+
+// NewMockUserImportRepositoryUseCase creates a new mock instance.
+func NewMockUserImportRepositoryUseCase(ctrl *gomock.Controller) *MockUserImportRepositoryUseCase {
+	mock := &MockUserImportRepositoryUseCase{ctrl: ctrl}
+	mock.recorder = &MockUserImportRepositoryUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserImportRepositoryUseCase) EXPECT() *MockUserImportRepositoryUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockUserImportRepositoryUseCase) Claim(ctx context.Context, id uuid.UUID, staleBefore time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, id, staleBefore)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockUserImportRepositoryUseCaseMockRecorder) Claim(ctx, id, staleBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockUserImportRepositoryUseCase)(nil).Claim), ctx, id, staleBefore)
+}
+
+// Create mocks base method.
+func (m *MockUserImportRepositoryUseCase) Create(ctx context.Context, userImport *entity.UserImport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userImport)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUserImportRepositoryUseCaseMockRecorder) Create(ctx, userImport interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserImportRepositoryUseCase)(nil).Create), ctx, userImport)
+}
+
+// FindByID mocks base method.
+func (m *MockUserImportRepositoryUseCase) FindByID(ctx context.Context, id uuid.UUID) (*entity.UserImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*entity.UserImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockUserImportRepositoryUseCaseMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockUserImportRepositoryUseCase)(nil).FindByID), ctx, id)
+}
+
+// FindDue mocks base method.
+func (m *MockUserImportRepositoryUseCase) FindDue(ctx context.Context, staleBefore time.Time, limit int) ([]*entity.UserImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDue", ctx, staleBefore, limit)
+	ret0, _ := ret[0].([]*entity.UserImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDue indicates an expected call of FindDue.
+func (mr *MockUserImportRepositoryUseCaseMockRecorder) FindDue(ctx, staleBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDue", reflect.TypeOf((*MockUserImportRepositoryUseCase)(nil).FindDue), ctx, staleBefore, limit)
+}
+
+// ImportBatch mocks base method.
+func (m *MockUserImportRepositoryUseCase) ImportBatch(ctx context.Context, userImport *entity.UserImport, items []*repository.UserImportItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportBatch", ctx, userImport, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportBatch indicates an expected call of ImportBatch.
+func (mr *MockUserImportRepositoryUseCaseMockRecorder) ImportBatch(ctx, userImport, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportBatch", reflect.TypeOf((*MockUserImportRepositoryUseCase)(nil).ImportBatch), ctx, userImport, items)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockUserImportRepositoryUseCase) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockUserImportRepositoryUseCaseMockRecorder) UpdateStatus(ctx, id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockUserImportRepositoryUseCase)(nil).UpdateStatus), ctx, id, status)
+}
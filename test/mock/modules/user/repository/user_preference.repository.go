@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./modules/user/v1/repository/user_preference.repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	entity "gin-starter/entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockUserPreferenceRepositoryUseCase is a mock of UserPreferenceRepositoryUseCase interface.
+type MockUserPreferenceRepositoryUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserPreferenceRepositoryUseCaseMockRecorder
+}
+
+// MockUserPreferenceRepositoryUseCaseMockRecorder is the mock recorder for MockUserPreferenceRepositoryUseCase.
+type MockUserPreferenceRepositoryUseCaseMockRecorder struct {
+	mock *MockUserPreferenceRepositoryUseCase
+}
+
+// NewMockUserPreferenceRepositoryUseCase creates a new mock instance.
+func NewMockUserPreferenceRepositoryUseCase(ctrl *gomock.Controller) *MockUserPreferenceRepositoryUseCase {
+	mock := &MockUserPreferenceRepositoryUseCase{ctrl: ctrl}
+	mock.recorder = &MockUserPreferenceRepositoryUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserPreferenceRepositoryUseCase) EXPECT() *MockUserPreferenceRepositoryUseCaseMockRecorder {
+	return m.recorder
+}
+
+// FindByUserID mocks base method.
+func (m *MockUserPreferenceRepositoryUseCase) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*entity.UserPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockUserPreferenceRepositoryUseCaseMockRecorder) FindByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockUserPreferenceRepositoryUseCase)(nil).FindByUserID), ctx, userID)
+}
+
+// Save mocks base method.
+func (m *MockUserPreferenceRepositoryUseCase) Save(ctx context.Context, userID uuid.UUID, preferences []*entity.UserPreference, resetKeys []string, updatedBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, userID, preferences, resetKeys, updatedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockUserPreferenceRepositoryUseCaseMockRecorder) Save(ctx, userID, preferences, resetKeys, updatedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockUserPreferenceRepositoryUseCase)(nil).Save), ctx, userID, preferences, resetKeys, updatedBy)
+}
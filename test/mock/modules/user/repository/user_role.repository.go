@@ -0,0 +1,181 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./modules/user/v1/repository/user_role.repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	entity "gin-starter/entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockUserRoleRepositoryUseCase is a mock of UserRoleRepositoryUseCase interface.
+type MockUserRoleRepositoryUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRoleRepositoryUseCaseMockRecorder
+}
+
+// MockUserRoleRepositoryUseCaseMockRecorder is the mock recorder for MockUserRoleRepositoryUseCase.
+type MockUserRoleRepositoryUseCaseMockRecorder struct {
+	mock *MockUserRoleRepositoryUseCase
+}
+
+// NewMockUserRoleRepositoryUseCase creates a new mock instance.
+func NewMockUserRoleRepositoryUseCase(ctrl *gomock.Controller) *MockUserRoleRepositoryUseCase {
+	mock := &MockUserRoleRepositoryUseCase{ctrl: ctrl}
+	mock.recorder = &MockUserRoleRepositoryUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRoleRepositoryUseCase) EXPECT() *MockUserRoleRepositoryUseCaseMockRecorder {
+	return m.recorder
+}
+
+// CountActivatedByRoleID mocks base method.
+func (m *MockUserRoleRepositoryUseCase) CountActivatedByRoleID(ctx context.Context, roleID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActivatedByRoleID", ctx, roleID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActivatedByRoleID indicates an expected call of CountActivatedByRoleID.
+func (mr *MockUserRoleRepositoryUseCaseMockRecorder) CountActivatedByRoleID(ctx, roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActivatedByRoleID", reflect.TypeOf((*MockUserRoleRepositoryUseCase)(nil).CountActivatedByRoleID), ctx, roleID)
+}
+
+// CountByRoleID mocks base method.
+func (m *MockUserRoleRepositoryUseCase) CountByRoleID(ctx context.Context, roleID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByRoleID", ctx, roleID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByRoleID indicates an expected call of CountByRoleID.
+func (mr *MockUserRoleRepositoryUseCaseMockRecorder) CountByRoleID(ctx, roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByRoleID", reflect.TypeOf((*MockUserRoleRepositoryUseCase)(nil).CountByRoleID), ctx, roleID)
+}
+
+// CreateOrUpdate mocks base method.
+func (m *MockUserRoleRepositoryUseCase) CreateOrUpdate(ctx context.Context, userRole *entity.UserRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdate", ctx, userRole)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrUpdate indicates an expected call of CreateOrUpdate.
+func (mr *MockUserRoleRepositoryUseCaseMockRecorder) CreateOrUpdate(ctx, userRole interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdate", reflect.TypeOf((*MockUserRoleRepositoryUseCase)(nil).CreateOrUpdate), ctx, userRole)
+}
+
+// Delete mocks base method.
+func (m *MockUserRoleRepositoryUseCase) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUserRoleRepositoryUseCaseMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserRoleRepositoryUseCase)(nil).Delete), ctx, id)
+}
+
+// FindByRoleID mocks base method.
+func (m *MockUserRoleRepositoryUseCase) FindByRoleID(ctx context.Context, roleID uuid.UUID) ([]*entity.UserRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByRoleID", ctx, roleID)
+	ret0, _ := ret[0].([]*entity.UserRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByRoleID indicates an expected call of FindByRoleID.
+func (mr *MockUserRoleRepositoryUseCaseMockRecorder) FindByRoleID(ctx, roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByRoleID", reflect.TypeOf((*MockUserRoleRepositoryUseCase)(nil).FindByRoleID), ctx, roleID)
+}
+
+// FindByUserID mocks base method.
+func (m *MockUserRoleRepositoryUseCase) FindByUserID(ctx context.Context, id uuid.UUID) (*entity.UserRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", ctx, id)
+	ret0, _ := ret[0].(*entity.UserRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockUserRoleRepositoryUseCaseMockRecorder) FindByUserID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockUserRoleRepositoryUseCase)(nil).FindByUserID), ctx, id)
+}
+
+// PatchAdmin mocks base method.
+func (m *MockUserRoleRepositoryUseCase) PatchAdmin(ctx context.Context, id uuid.UUID, version int64, columns map[string]interface{}, roleID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchAdmin", ctx, id, version, columns, roleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PatchAdmin indicates an expected call of PatchAdmin.
+func (mr *MockUserRoleRepositoryUseCaseMockRecorder) PatchAdmin(ctx, id, version, columns, roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchAdmin", reflect.TypeOf((*MockUserRoleRepositoryUseCase)(nil).PatchAdmin), ctx, id, version, columns, roleID)
+}
+
+// Reassign mocks base method.
+func (m *MockUserRoleRepositoryUseCase) Reassign(ctx context.Context, fromRoleID, toRoleID uuid.UUID, updatedBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reassign", ctx, fromRoleID, toRoleID, updatedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reassign indicates an expected call of Reassign.
+func (mr *MockUserRoleRepositoryUseCaseMockRecorder) Reassign(ctx, fromRoleID, toRoleID, updatedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reassign", reflect.TypeOf((*MockUserRoleRepositoryUseCase)(nil).Reassign), ctx, fromRoleID, toRoleID, updatedBy)
+}
+
+// Update mocks base method.
+func (m *MockUserRoleRepositoryUseCase) Update(ctx context.Context, userRole *entity.UserRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userRole)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockUserRoleRepositoryUseCaseMockRecorder) Update(ctx, userRole interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRoleRepositoryUseCase)(nil).Update), ctx, userRole)
+}
+
+// UpdateAdmin mocks base method.
+func (m *MockUserRoleRepositoryUseCase) UpdateAdmin(ctx context.Context, user *entity.User, roleID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAdmin", ctx, user, roleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAdmin indicates an expected call of UpdateAdmin.
+func (mr *MockUserRoleRepositoryUseCaseMockRecorder) UpdateAdmin(ctx, user, roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAdmin", reflect.TypeOf((*MockUserRoleRepositoryUseCase)(nil).UpdateAdmin), ctx, user, roleID)
+}
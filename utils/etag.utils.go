@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+
+	"gin-starter/common/errors"
+)
+
+// ETag returns the entity tag of a version of a resource
+func ETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ParseIfMatch returns the version named by an If-Match header.
+// A missing header fails with ErrPreconditionRequired and a header naming no version, or several, with ErrVersionMismatch.
+func ParseIfMatch(header string) (int64, error) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, errors.ErrPreconditionRequired.Error()
+	}
+
+	// clients and proxies may weaken the tag, the version is compared either way
+	tag := strings.TrimPrefix(header, "W/")
+	if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		return 0, errors.ErrVersionMismatch.Error()
+	}
+
+	version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	if err != nil || version < 1 {
+		return 0, errors.ErrVersionMismatch.Error()
+	}
+
+	return version, nil
+}
+
+// IsVersionMismatch tells whether the error rejected an update based on an outdated version
+func IsVersionMismatch(err error) bool {
+	return err != nil && err.Error() == errors.ErrVersionMismatch.Error().Error()
+}
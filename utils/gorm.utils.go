@@ -1,11 +1,13 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"gin-starter/config"
 	"strconv"
 	"time"
 
+	"github.com/jackc/pgconn"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	gormLogger "gorm.io/gorm/logger"
@@ -51,3 +53,16 @@ func NewPostgresGormDB(cfg *config.Postgres) (*gorm.DB, error) {
 
 	return gormDB, err
 }
+
+// pgUniqueViolation is the SQLSTATE of Postgres unique violations
+const pgUniqueViolation = "23505"
+
+// UniqueViolation returns the unique constraint or index a Postgres error violated
+func UniqueViolation(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return pgErr.ConstraintName, true
+	}
+
+	return "", false
+}
@@ -6,6 +6,7 @@ import (
 	"gin-starter/common/errors"
 	"html/template"
 	"log"
+	"strings"
 )
 
 // EmailPayload is the payload for sending email
@@ -41,3 +42,8 @@ func ConstructEmailPayload(templatePath, receiver, subject, category string, dat
 
 	return emailPayload, nil
 }
+
+// NormalizeEmail returns the form emails are stored and compared in, emails are unique ignoring case
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const indonesiaCountryCode = "62"
+
+var (
+	phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+	e164Pattern     = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+)
+
+// NormalizePhoneNumber normalises a phone number to E.164.
+// Indonesian numbers may be written in the national 08xx form or without the plus sign, numbers of other countries need their country code.
+func NormalizePhoneNumber(phoneNumber string) (string, error) {
+	normalized := phoneSeparators.Replace(strings.TrimSpace(phoneNumber))
+
+	switch {
+	case strings.HasPrefix(normalized, "+"):
+	case strings.HasPrefix(normalized, "00"):
+		normalized = "+" + normalized[2:]
+	case strings.HasPrefix(normalized, "0"):
+		normalized = "+" + indonesiaCountryCode + normalized[1:]
+	case strings.HasPrefix(normalized, indonesiaCountryCode):
+		normalized = "+" + normalized
+	case strings.HasPrefix(normalized, "8"):
+		normalized = "+" + indonesiaCountryCode + normalized
+	}
+
+	if !e164Pattern.MatchString(normalized) {
+		return "", fmt.Errorf("invalid phone number %q", phoneNumber)
+	}
+
+	// the national trunk prefix is dropped in the international form, +62 08xx is a common typo
+	if strings.HasPrefix(normalized, "+"+indonesiaCountryCode+"0") {
+		return NormalizePhoneNumber(normalized[len(indonesiaCountryCode)+1:])
+	}
+
+	return normalized, nil
+}
@@ -7,14 +7,15 @@ import (
 	"log"
 	"os"
 
+	"github.com/pkg/errors"
 	"github.com/xuri/excelize/v2"
 )
 
 // ReadCsvFile reads csv file content
-func ReadCsvFile(filePath string) [][]string {
+func ReadCsvFile(filePath string) ([][]string, error) {
 	f, err := os.Open(filePath) // #nosec
 	if err != nil {
-		log.Fatal("Unable to read input file "+filePath, err)
+		return nil, errors.Wrap(err, "unable to read input file "+filePath)
 	}
 	defer func() {
 		if err := f.Close(); err != nil {
@@ -23,19 +24,22 @@ func ReadCsvFile(filePath string) [][]string {
 	}()
 
 	csvReader := csv.NewReader(f)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
 	records, err := csvReader.ReadAll()
 	if err != nil {
-		log.Println("Unable to parse file as CSV for "+filePath, err)
+		return nil, errors.Wrap(err, "unable to parse file as CSV for "+filePath)
 	}
 
-	return records
+	return records, nil
 }
 
-// ReadExcelFile reads excel file content
-func ReadExcelFile(filePath string) [][]string {
+// ReadExcelFile reads the content of the first sheet of an excel file
+func ReadExcelFile(filePath string) ([][]string, error) {
 	f, err := excelize.OpenFile(filePath)
 	if err != nil {
-		log.Fatal("Unable to read input file "+filePath, err)
+		return nil, errors.Wrap(err, "unable to read input file "+filePath)
 	}
 
 	defer func() {
@@ -44,10 +48,15 @@ func ReadExcelFile(filePath string) [][]string {
 		}
 	}()
 
-	rows, err := f.GetRows("Sheet1")
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New("no sheet found in " + filePath)
+	}
+
+	rows, err := f.GetRows(sheets[0])
 	if err != nil {
-		log.Println("Unable to parse file as XLSX for "+filePath, err)
+		return nil, errors.Wrap(err, "unable to parse file as XLSX for "+filePath)
 	}
 
-	return rows
+	return rows, nil
 }
@@ -162,6 +162,47 @@ func (r *Client) SetWithExpireAt(key string, value interface{}, ttl time.Time) e
 	return err
 }
 
+// incrementScript increments a counter and sets the expiry of a new counter in a single step,
+// so that a counter never outlives its window
+var incrementScript = redis.NewScript(1, `
+local n = redis.call("INCR", KEYS[1])
+if n == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// Increment increments the counter of the key and returns its value, a new counter expires after ttl
+func (r *Client) Increment(key string, ttl time.Duration) (int64, error) {
+	conn := r.cachePool.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	n, err := redis.Int64(incrementScript.Do(conn, key, ttl.Milliseconds()))
+	if err != nil {
+		return 0, fmt.Errorf("error incrementing key %s: %v", key, err)
+	}
+	return n, nil
+}
+
+// SetNX sets the key for ttl unless it exists, returning false when it exists
+func (r *Client) SetNX(key string, ttl time.Duration) (bool, error) {
+	conn := r.cachePool.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	_, err := redis.String(conn.Do("SET", key, 1, "NX", "PX", ttl.Milliseconds()))
+	if err == redis.ErrNil {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error setting key %s: %v", key, err)
+	}
+	return true, nil
+}
+
 // Exists check if key is exist in redis
 func (r *Client) Exists(key string) (bool, error) {
 	conn := r.cachePool.Get()